@@ -0,0 +1,415 @@
+package stages
+
+import (
+	"net"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/mitchellh/mapstructure"
+	"github.com/oschwald/geoip2-golang"
+	"github.com/pkg/errors"
+	"go.uber.org/atomic"
+)
+
+// Config Errors
+const (
+	ErrEmptyGeoIPStageConfig     = "geoip stage config cannot be empty"
+	ErrEmptyGeoIPStageDB         = "db is required in geoip stage"
+	ErrEmptyGeoIPStageSource     = "source is required in geoip stage"
+	ErrGeoIPStageInvalidDBType   = "invalid db_type %q in geoip stage, must be one of: city, asn"
+	ErrGeoIPStageInvalidField    = "invalid field %q in geoip stage for the %s database, must be one of: %v"
+	ErrGeoIPStageInvalidInterval = "geoip stage invalid reload_interval, %v cannot be converted to a duration: %v"
+	ErrGeoIPStageCouldNotOpenDB  = "could not open the database of the geoip stage"
+)
+
+const (
+	defaultGeoIPReloadInterval  = time.Minute
+	defaultGeoIPExtractedPrefix = "geoip_"
+	// geoIPNamesLanguage is the language of the names of the places.
+	geoIPNamesLanguage = "en"
+)
+
+// Database types
+const (
+	GeoIPDBTypeCity = "city"
+	GeoIPDBTypeASN  = "asn"
+)
+
+// Fields
+const (
+	GeoIPFieldCityName        = "city_name"
+	GeoIPFieldCountryName     = "country_name"
+	GeoIPFieldCountryCode     = "country_code"
+	GeoIPFieldContinentName   = "continent_name"
+	GeoIPFieldContinentCode   = "continent_code"
+	GeoIPFieldSubdivisionName = "subdivision_name"
+	GeoIPFieldSubdivisionCode = "subdivision_code"
+	GeoIPFieldPostalCode      = "postal_code"
+	GeoIPFieldTimezone        = "timezone"
+	GeoIPFieldLatitude        = "latitude"
+	GeoIPFieldLongitude       = "longitude"
+	GeoIPFieldASN             = "asn"
+	GeoIPFieldASNOrg          = "asn_org"
+)
+
+// geoIPFields are the fields available for each type of database.
+var geoIPFields = map[string][]string{
+	GeoIPDBTypeCity: {
+		GeoIPFieldCityName,
+		GeoIPFieldCountryName,
+		GeoIPFieldCountryCode,
+		GeoIPFieldContinentName,
+		GeoIPFieldContinentCode,
+		GeoIPFieldSubdivisionName,
+		GeoIPFieldSubdivisionCode,
+		GeoIPFieldPostalCode,
+		GeoIPFieldTimezone,
+		GeoIPFieldLatitude,
+		GeoIPFieldLongitude,
+	},
+	GeoIPDBTypeASN: {
+		GeoIPFieldASN,
+		GeoIPFieldASNOrg,
+	},
+}
+
+// GeoIPConfig contains the configuration for a geoIPStage
+type GeoIPConfig struct {
+	DB             string            `mapstructure:"db"`
+	DBType         string            `mapstructure:"db_type"`
+	Source         *string           `mapstructure:"source"`
+	Fields         map[string]string `mapstructure:"fields"`
+	ReloadInterval *string           `mapstructure:"reload_interval"`
+	reloadInterval time.Duration
+}
+
+// validateGeoIPConfig validates the GeoIPConfig for the geoIPStage
+func validateGeoIPConfig(cfg *GeoIPConfig) error {
+	if cfg == nil {
+		return errors.New(ErrEmptyGeoIPStageConfig)
+	}
+	if cfg.DB == "" {
+		return errors.New(ErrEmptyGeoIPStageDB)
+	}
+	if cfg.Source == nil || *cfg.Source == "" {
+		return errors.New(ErrEmptyGeoIPStageSource)
+	}
+
+	if cfg.DBType == "" {
+		cfg.DBType = GeoIPDBTypeCity
+	}
+	fields, ok := geoIPFields[cfg.DBType]
+	if !ok {
+		return errors.Errorf(ErrGeoIPStageInvalidDBType, cfg.DBType)
+	}
+	if len(cfg.Fields) == 0 {
+		cfg.Fields = make(map[string]string, len(fields))
+		for _, f := range fields {
+			cfg.Fields[f] = ""
+		}
+	}
+	for field, name := range cfg.Fields {
+		valid := false
+		for _, f := range fields {
+			valid = valid || f == field
+		}
+		if !valid {
+			return errors.Errorf(ErrGeoIPStageInvalidField, field, cfg.DBType, fields)
+		}
+		if name == "" {
+			cfg.Fields[field] = defaultGeoIPExtractedPrefix + field
+		}
+	}
+
+	cfg.reloadInterval = defaultGeoIPReloadInterval
+	if cfg.ReloadInterval != nil {
+		d, err := time.ParseDuration(*cfg.ReloadInterval)
+		if err != nil {
+			return errors.Errorf(ErrGeoIPStageInvalidInterval, *cfg.ReloadInterval, err)
+		}
+		cfg.reloadInterval = d
+	}
+	return nil
+}
+
+// newGeoIPStage creates a geoIPStage from config
+func newGeoIPStage(logger log.Logger, config interface{}) (Stage, error) {
+	cfg := &GeoIPConfig{}
+	err := mapstructure.Decode(config, cfg)
+	if err != nil {
+		return nil, err
+	}
+	err = validateGeoIPConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &geoIPStage{
+		logger: log.With(logger, "component", "stage", "type", "geoip"),
+		cfg:    cfg,
+	}
+	s.db, err = geoIPDatabases.acquire(s.logger, cfg.DB, cfg.reloadInterval)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// geoIPStage sets extracted data with the geolocation of an IP address, looked up in a MaxMind database.
+type geoIPStage struct {
+	logger log.Logger
+	cfg    *GeoIPConfig
+
+	// mtx protects the database, which is released when the stage stops and acquired again if the stage is run again.
+	mtx sync.Mutex
+	db  *geoIPDatabase
+}
+
+// reader returns the current reader of the database, acquiring the database if the stage stopped before.
+func (g *geoIPStage) reader() *geoip2.Reader {
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+	if g.db == nil {
+		db, err := geoIPDatabases.acquire(g.logger, g.cfg.DB, g.cfg.reloadInterval)
+		if err != nil {
+			level.Warn(g.logger).Log("msg", "failed to load the database", "db", g.cfg.DB, "err", err)
+			return nil
+		}
+		g.db = db
+	}
+	return g.db.reader.Load()
+}
+
+// Run implements Stage
+func (g *geoIPStage) Run(in chan Entry) chan Entry {
+	out := make(chan Entry)
+	go func() {
+		defer close(out)
+		for e := range in {
+			g.process(e.Extracted)
+			out <- e
+		}
+
+		g.mtx.Lock()
+		defer g.mtx.Unlock()
+		if g.db != nil {
+			geoIPDatabases.release(g.db)
+			g.db = nil
+		}
+	}()
+	return out
+}
+
+func (g *geoIPStage) process(extracted map[string]interface{}) {
+	v, ok := extracted[*g.cfg.Source]
+	if !ok {
+		if Debug {
+			level.Debug(g.logger).Log("msg", "source does not exist in the set of extracted values", "source", *g.cfg.Source)
+		}
+		return
+	}
+	s, err := getString(v)
+	if err != nil {
+		if Debug {
+			level.Debug(g.logger).Log("msg", "failed to convert source value to string", "source", *g.cfg.Source, "err", err, "type", reflect.TypeOf(v))
+		}
+		return
+	}
+	ip := net.ParseIP(s)
+	if ip == nil {
+		if Debug {
+			level.Debug(g.logger).Log("msg", "source value is not an IP address", "source", *g.cfg.Source, "value", s)
+		}
+		return
+	}
+
+	reader := g.reader()
+	if reader == nil {
+		return
+	}
+
+	switch g.cfg.DBType {
+	case GeoIPDBTypeCity:
+		record, err := reader.City(ip)
+		if err != nil {
+			if Debug {
+				level.Debug(g.logger).Log("msg", "failed to look up the IP address", "ip", s, "err", err)
+			}
+			return
+		}
+		g.setField(extracted, GeoIPFieldCityName, record.City.Names[geoIPNamesLanguage])
+		g.setField(extracted, GeoIPFieldCountryName, record.Country.Names[geoIPNamesLanguage])
+		g.setField(extracted, GeoIPFieldCountryCode, record.Country.IsoCode)
+		g.setField(extracted, GeoIPFieldContinentName, record.Continent.Names[geoIPNamesLanguage])
+		g.setField(extracted, GeoIPFieldContinentCode, record.Continent.Code)
+		if len(record.Subdivisions) > 0 {
+			g.setField(extracted, GeoIPFieldSubdivisionName, record.Subdivisions[0].Names[geoIPNamesLanguage])
+			g.setField(extracted, GeoIPFieldSubdivisionCode, record.Subdivisions[0].IsoCode)
+		}
+		g.setField(extracted, GeoIPFieldPostalCode, record.Postal.Code)
+		g.setField(extracted, GeoIPFieldTimezone, record.Location.TimeZone)
+		// Records without a location have no accuracy radius.
+		if record.Location.AccuracyRadius > 0 || record.Location.Latitude != 0 || record.Location.Longitude != 0 {
+			g.setField(extracted, GeoIPFieldLatitude, record.Location.Latitude)
+			g.setField(extracted, GeoIPFieldLongitude, record.Location.Longitude)
+		}
+	case GeoIPDBTypeASN:
+		record, err := reader.ASN(ip)
+		if err != nil {
+			if Debug {
+				level.Debug(g.logger).Log("msg", "failed to look up the IP address", "ip", s, "err", err)
+			}
+			return
+		}
+		if record.AutonomousSystemNumber > 0 {
+			g.setField(extracted, GeoIPFieldASN, record.AutonomousSystemNumber)
+		}
+		g.setField(extracted, GeoIPFieldASNOrg, record.AutonomousSystemOrganization)
+	}
+}
+
+// setField sets the extracted value of a field if it's configured and the value is known.
+func (g *geoIPStage) setField(extracted map[string]interface{}, field string, value interface{}) {
+	name, ok := g.cfg.Fields[field]
+	if !ok || value == "" {
+		return
+	}
+	extracted[name] = value
+}
+
+// Name implements Stage
+func (g *geoIPStage) Name() string {
+	return StageTypeGeoIP
+}
+
+// geoIPDatabases are the databases of the geoip stages, shared by the stages using the same file.
+var geoIPDatabases = &geoIPDatabaseRegistry{dbs: map[string]*geoIPDatabase{}}
+
+type geoIPDatabaseRegistry struct {
+	mtx sync.Mutex
+	dbs map[string]*geoIPDatabase
+}
+
+// acquire returns the database of a file, loading it if no stage uses it yet.
+// The database is reloaded in the background at the shortest reload interval of the stages using it.
+func (r *geoIPDatabaseRegistry) acquire(logger log.Logger, path string, reloadInterval time.Duration) (*geoIPDatabase, error) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	db, ok := r.dbs[path]
+	if !ok {
+		db = &geoIPDatabase{
+			logger: logger,
+			path:   path,
+			done:   make(chan struct{}),
+		}
+		if err := db.load(); err != nil {
+			return nil, err
+		}
+		r.dbs[path] = db
+	}
+	db.refs++
+	db.setReloadInterval(reloadInterval)
+	return db, nil
+}
+
+// release stops reloading the database once no stage uses it.
+func (r *geoIPDatabaseRegistry) release(db *geoIPDatabase) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	db.refs--
+	if db.refs > 0 {
+		return
+	}
+	delete(r.dbs, db.path)
+	close(db.done)
+}
+
+// geoIPDatabase is a MaxMind database, reloaded whenever its file changes.
+// The file is read in memory rather than memory mapped, so that the database isn't corrupted if the file is overwritten,
+// and so that the previous reader remains usable by the lookups in progress when it's replaced.
+type geoIPDatabase struct {
+	logger log.Logger
+	path   string
+	reader atomic.Pointer[geoip2.Reader]
+
+	// refs, reloadInterval and ticker are protected by the mutex of the registry.
+	refs           int
+	reloadInterval time.Duration
+	ticker         *time.Ticker
+	done           chan struct{}
+
+	// modTime and size are only accessed by the first load and then by the reload loop.
+	modTime time.Time
+	size    int64
+}
+
+// load reads the database file and replaces the current reader.
+func (d *geoIPDatabase) load() error {
+	info, err := os.Stat(d.path)
+	if err != nil {
+		return errors.Wrap(err, ErrGeoIPStageCouldNotOpenDB)
+	}
+	b, err := os.ReadFile(d.path)
+	if err != nil {
+		return errors.Wrap(err, ErrGeoIPStageCouldNotOpenDB)
+	}
+	reader, err := geoip2.FromBytes(b)
+	if err != nil {
+		return errors.Wrap(err, ErrGeoIPStageCouldNotOpenDB)
+	}
+
+	d.reader.Store(reader)
+	d.modTime = info.ModTime()
+	d.size = info.Size()
+	return nil
+}
+
+// setReloadInterval starts reloading the database, or reloads it more often if the interval is shorter than the current one.
+// A non-positive interval doesn't reload the database.
+func (d *geoIPDatabase) setReloadInterval(interval time.Duration) {
+	if interval <= 0 || (d.ticker != nil && interval >= d.reloadInterval) {
+		return
+	}
+	d.reloadInterval = interval
+	if d.ticker != nil {
+		d.ticker.Reset(interval)
+		return
+	}
+	d.ticker = time.NewTicker(interval)
+	go d.reloadLoop(d.ticker, d.done)
+}
+
+func (d *geoIPDatabase) reloadLoop(ticker *time.Ticker, done chan struct{}) {
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			d.reloadIfChanged()
+		}
+	}
+}
+
+// reloadIfChanged reloads the database if its file changed since it was loaded.
+func (d *geoIPDatabase) reloadIfChanged() {
+	info, err := os.Stat(d.path)
+	if err != nil {
+		level.Warn(d.logger).Log("msg", "failed to check the database file, keeping the current database", "db", d.path, "err", err)
+		return
+	}
+	if info.ModTime().Equal(d.modTime) && info.Size() == d.size {
+		return
+	}
+	if err := d.load(); err != nil {
+		level.Warn(d.logger).Log("msg", "failed to reload the database, keeping the current database", "db", d.path, "err", err)
+		return
+	}
+	level.Info(d.logger).Log("msg", "reloaded the database", "db", d.path)
+}
@@ -0,0 +1,365 @@
+package stages
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	util_log "github.com/grafana/loki/pkg/util/log"
+)
+
+var testGeoIPYaml = `
+pipeline_stages:
+- regex:
+    expression: "^(?P<ip>\\S+) "
+- geoip:
+    db: %s
+    source: ip
+    fields:
+      country_code: country
+      city_name:
+      latitude:
+      longitude:
+`
+
+func TestGeoIPPipeline(t *testing.T) {
+	db := filepath.Join(t.TempDir(), "city.mmdb")
+	writeTestGeoIPDB(t, db, "GeoLite2-City", map[string]map[string]interface{}{
+		"81.2.69.142": testGeoIPCityRecord("London", "GB", "United Kingdom", 51.5142, -0.0931),
+	})
+
+	pl, err := NewPipeline(util_log.Logger, loadConfig(fmt.Sprintf(testGeoIPYaml, db)), nil, prometheus.DefaultRegisterer)
+	require.NoError(t, err)
+
+	out := processEntries(pl,
+		newEntry(nil, nil, "81.2.69.142 GET /", time.Now()),
+		newEntry(nil, nil, "10.0.0.1 GET /", time.Now()),
+		newEntry(nil, nil, "unknown GET /", time.Now()),
+	)
+	require.Len(t, out, 3)
+	assert.Equal(t, map[string]interface{}{
+		"ip":              "81.2.69.142",
+		"country":         "GB",
+		"geoip_city_name": "London",
+		"geoip_latitude":  51.5142,
+		"geoip_longitude": -0.0931,
+	}, out[0].Extracted)
+	assert.Equal(t, map[string]interface{}{"ip": "10.0.0.1"}, out[1].Extracted)
+	assert.Equal(t, map[string]interface{}{"ip": "unknown"}, out[2].Extracted)
+}
+
+func TestGeoIPStageASN(t *testing.T) {
+	db := filepath.Join(t.TempDir(), "asn.mmdb")
+	writeTestGeoIPDB(t, db, "GeoLite2-ASN", map[string]map[string]interface{}{
+		"1.128.0.0": {
+			"autonomous_system_number":       uint32(1221),
+			"autonomous_system_organization": "Telstra Pty Ltd",
+		},
+	})
+
+	s, err := newGeoIPStage(util_log.Logger, map[string]interface{}{"db": db, "db_type": "asn", "source": "ip"})
+	require.NoError(t, err)
+
+	out := processEntries(s, newEntry(map[string]interface{}{"ip": "1.128.0.0"}, nil, "", time.Now()))
+	require.Len(t, out, 1)
+	assert.Equal(t, map[string]interface{}{
+		"ip":            "1.128.0.0",
+		"geoip_asn":     uint(1221),
+		"geoip_asn_org": "Telstra Pty Ltd",
+	}, out[0].Extracted)
+	// The database is released when the stage stops, and acquired again when it's run again.
+	require.Nil(t, s.(*geoIPStage).db)
+	out = processEntries(s, newEntry(map[string]interface{}{"ip": "1.128.0.0"}, nil, "", time.Now()))
+	require.Len(t, out, 1)
+	assert.Equal(t, uint(1221), out[0].Extracted["geoip_asn"])
+}
+
+func TestGeoIPStageReload(t *testing.T) {
+	db := filepath.Join(t.TempDir(), "city.mmdb")
+	writeTestGeoIPDB(t, db, "GeoLite2-City", map[string]map[string]interface{}{
+		"81.2.69.142": testGeoIPCityRecord("London", "GB", "United Kingdom", 51.5142, -0.0931),
+	})
+
+	s, err := newGeoIPStage(util_log.Logger, map[string]interface{}{
+		"db":              db,
+		"source":          "ip",
+		"fields":          map[string]interface{}{"city_name": "city"},
+		"reload_interval": "10ms",
+	})
+	require.NoError(t, err)
+
+	// The stage keeps running between the lookups, as the database is released when it stops.
+	in := make(chan Entry)
+	out := s.Run(in)
+	defer close(in)
+	lookup := func() interface{} {
+		in <- newEntry(map[string]interface{}{"ip": "81.2.69.142"}, nil, "", time.Now())
+		return (<-out).Extracted["city"]
+	}
+	require.Equal(t, "London", lookup())
+
+	// The database is replaced atomically, so that a partially written file isn't loaded.
+	tmp := filepath.Join(filepath.Dir(db), "city.mmdb.tmp")
+	writeTestGeoIPDB(t, tmp, "GeoLite2-City", map[string]map[string]interface{}{
+		"81.2.69.142": testGeoIPCityRecord("Paris", "FR", "France", 48.8566, 2.3522),
+	})
+	modTime := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(tmp, modTime, modTime))
+	require.NoError(t, os.Rename(tmp, db))
+	require.Eventually(t, func() bool { return lookup() == "Paris" }, 5*time.Second, 10*time.Millisecond)
+
+	// An invalid database is ignored.
+	require.NoError(t, os.WriteFile(tmp, []byte("invalid"), 0o644))
+	require.NoError(t, os.Rename(tmp, db))
+	time.Sleep(50 * time.Millisecond)
+	require.Equal(t, "Paris", lookup())
+}
+
+func TestGeoIPStageSharedDB(t *testing.T) {
+	db := filepath.Join(t.TempDir(), "asn.mmdb")
+	writeTestGeoIPDB(t, db, "GeoLite2-ASN", map[string]map[string]interface{}{
+		"1.128.0.0": {"autonomous_system_number": uint32(1221)},
+	})
+
+	newStage := func(reloadInterval string) *geoIPStage {
+		s, err := newGeoIPStage(util_log.Logger, map[string]interface{}{"db": db, "db_type": "asn", "source": "ip", "reload_interval": reloadInterval})
+		require.NoError(t, err)
+		return s.(*geoIPStage)
+	}
+	s1, s2 := newStage("1h"), newStage("1m")
+	require.Same(t, s1.db, s2.db)
+	// The database is reloaded at the shortest interval of the stages.
+	require.Equal(t, time.Minute, s1.db.reloadInterval)
+
+	// The database is released once all the stages using it stop.
+	for _, s := range []*geoIPStage{s1, s2} {
+		in := make(chan Entry)
+		out := s.Run(in)
+		close(in)
+		for range out {
+		}
+	}
+	geoIPDatabases.mtx.Lock()
+	defer geoIPDatabases.mtx.Unlock()
+	require.NotContains(t, geoIPDatabases.dbs, db)
+}
+
+func TestValidateGeoIPConfig(t *testing.T) {
+	source := "ip"
+	invalidInterval := "1x"
+	for name, tc := range map[string]struct {
+		config *GeoIPConfig
+		fields map[string]string
+		err    error
+	}{
+		"default fields": {
+			config: &GeoIPConfig{DB: "db", DBType: "asn", Source: &source},
+			fields: map[string]string{"asn": "geoip_asn", "asn_org": "geoip_asn_org"},
+		},
+		"custom fields": {
+			config: &GeoIPConfig{DB: "db", Source: &source, Fields: map[string]string{"country_code": "", "city_name": "city"}},
+			fields: map[string]string{"country_code": "geoip_country_code", "city_name": "city"},
+		},
+		"empty": {
+			err: errors.New(ErrEmptyGeoIPStageConfig),
+		},
+		"no db": {
+			config: &GeoIPConfig{Source: &source},
+			err:    errors.New(ErrEmptyGeoIPStageDB),
+		},
+		"no source": {
+			config: &GeoIPConfig{DB: "db"},
+			err:    errors.New(ErrEmptyGeoIPStageSource),
+		},
+		"invalid db type": {
+			config: &GeoIPConfig{DB: "db", DBType: "isp", Source: &source},
+			err:    errors.Errorf(ErrGeoIPStageInvalidDBType, "isp"),
+		},
+		"invalid field": {
+			config: &GeoIPConfig{DB: "db", DBType: "asn", Source: &source, Fields: map[string]string{"city_name": ""}},
+			err:    errors.Errorf(ErrGeoIPStageInvalidField, "city_name", "asn", geoIPFields["asn"]),
+		},
+		"invalid reload interval": {
+			config: &GeoIPConfig{DB: "db", Source: &source, ReloadInterval: &invalidInterval},
+			err:    errors.Errorf(ErrGeoIPStageInvalidInterval, invalidInterval, `time: unknown unit "x" in duration "1x"`),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := validateGeoIPConfig(tc.config)
+			if tc.err != nil {
+				require.EqualError(t, err, tc.err.Error())
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.fields, tc.config.Fields)
+		})
+	}
+}
+
+func TestGeoIPStageMissingDB(t *testing.T) {
+	source := "ip"
+	_, err := newGeoIPStage(util_log.Logger, &GeoIPConfig{DB: filepath.Join(t.TempDir(), "missing.mmdb"), Source: &source})
+	require.ErrorContains(t, err, ErrGeoIPStageCouldNotOpenDB)
+}
+
+func testGeoIPCityRecord(city, countryCode, country string, lat, long float64) map[string]interface{} {
+	return map[string]interface{}{
+		"city":    map[string]interface{}{"names": map[string]interface{}{"en": city}},
+		"country": map[string]interface{}{"iso_code": countryCode, "names": map[string]interface{}{"en": country}},
+		"location": map[string]interface{}{
+			"accuracy_radius": uint16(10),
+			"latitude":        lat,
+			"longitude":       long,
+		},
+	}
+}
+
+// writeTestGeoIPDB writes an IPv4 MaxMind database with the records of single IP addresses.
+func writeTestGeoIPDB(t *testing.T, path, dbType string, records map[string]map[string]interface{}) {
+	t.Helper()
+
+	// The search tree has a node per bit of the addresses.
+	type node [2]uint32
+	const noData = ^uint32(0)
+	nodes := []node{{noData, noData}}
+	var data bytes.Buffer
+	ips := make([]string, 0, len(records))
+	for ip := range records {
+		ips = append(ips, ip)
+	}
+	sort.Strings(ips)
+	dataOffsets := map[string]uint32{}
+	for _, ip := range ips {
+		dataOffsets[ip] = uint32(data.Len())
+		encodeTestMMDBValue(&data, records[ip])
+	}
+
+	type leaf struct{ node, bit int }
+	var leaves []leaf
+	for _, ip := range ips {
+		addr := net.ParseIP(ip).To4()
+		require.NotNil(t, addr)
+		n := 0
+		for i := 0; i < 32; i++ {
+			bit := int(addr[i/8]>>(7-i%8)) & 1
+			if i == 31 {
+				leaves = append(leaves, leaf{n, bit})
+				nodes[n][bit] = dataOffsets[ip]
+				break
+			}
+			if nodes[n][bit] == noData {
+				nodes = append(nodes, node{noData, noData})
+				nodes[n][bit] = uint32(len(nodes) - 1)
+			}
+			n = int(nodes[n][bit])
+		}
+	}
+
+	// Records point to a node, to the data section after the node count plus 16, or to no data with the node count.
+	nodeCount := uint32(len(nodes))
+	isLeaf := map[leaf]bool{}
+	for _, l := range leaves {
+		isLeaf[l] = true
+	}
+	var buf bytes.Buffer
+	for i, n := range nodes {
+		for bit, record := range n {
+			switch {
+			case record == noData:
+				record = nodeCount
+			case isLeaf[leaf{i, bit}]:
+				record += nodeCount + 16
+			}
+			buf.Write([]byte{byte(record >> 16), byte(record >> 8), byte(record)})
+		}
+	}
+	buf.Write(make([]byte, 16))
+	buf.Write(data.Bytes())
+	buf.WriteString("\xab\xcd\xefMaxMind.com")
+	encodeTestMMDBValue(&buf, map[string]interface{}{
+		"binary_format_major_version": uint16(2),
+		"binary_format_minor_version": uint16(0),
+		"build_epoch":                 uint64(time.Now().Unix()),
+		"database_type":               dbType,
+		"description":                 map[string]interface{}{"en": "test database"},
+		"ip_version":                  uint16(4),
+		"languages":                   []interface{}{"en"},
+		"node_count":                  nodeCount,
+		"record_size":                 uint16(24),
+	})
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0o644))
+}
+
+// encodeTestMMDBValue encodes a value in the data section format of MaxMind databases.
+func encodeTestMMDBValue(buf *bytes.Buffer, v interface{}) {
+	writeControl := func(typ byte, size int) {
+		var ext []byte
+		if size >= 29 {
+			// Only sizes up to 284 are needed.
+			ext = []byte{byte(size - 29)}
+			size = 29
+		}
+		if typ > 7 {
+			buf.WriteByte(byte(size))
+			buf.WriteByte(typ - 7)
+		} else {
+			buf.WriteByte(typ<<5 | byte(size))
+		}
+		buf.Write(ext)
+	}
+	writeUint := func(typ byte, u uint64) {
+		var b [8]byte
+		binary.BigEndian.PutUint64(b[:], u)
+		i := 0
+		for i < 8 && b[i] == 0 {
+			i++
+		}
+		writeControl(typ, 8-i)
+		buf.Write(b[i:])
+	}
+
+	switch v := v.(type) {
+	case string:
+		writeControl(2, len(v))
+		buf.WriteString(v)
+	case float64:
+		writeControl(3, 8)
+		_ = binary.Write(buf, binary.BigEndian, math.Float64bits(v))
+	case uint16:
+		writeUint(5, uint64(v))
+	case uint32:
+		writeUint(6, uint64(v))
+	case uint64:
+		writeUint(9, v)
+	case map[string]interface{}:
+		writeControl(7, len(v))
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			encodeTestMMDBValue(buf, k)
+			encodeTestMMDBValue(buf, v[k])
+		}
+	case []interface{}:
+		writeControl(11, len(v))
+		for _, e := range v {
+			encodeTestMMDBValue(buf, e)
+		}
+	default:
+		panic(errors.Errorf("unsupported type %T", v))
+	}
+}
@@ -0,0 +1,357 @@
+package stages
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+)
+
+// Config Errors
+const (
+	ErrEmptyRedactStageSource      = "empty source in redact stage"
+	ErrRedactStageUnknownDetector  = "unknown detector %q in redact stage"
+	ErrRedactStageInvalidMode      = "invalid mode %q in redact stage, must be one of: mask, hash"
+	ErrRedactStagePatternName      = "patterns of the redact stage must have a name"
+	ErrRedactStageDuplicateName    = "duplicate detector or pattern name %q in redact stage"
+	ErrRedactStageNothingToRedact  = "redact stage must have at least one detector or pattern"
+	ErrRedactStageInvalidPattern   = "could not compile the expression of the pattern %q of the redact stage"
+	ErrRedactStagePatternNoMatches = "the expression of the pattern %q of the redact stage must not match empty strings"
+)
+
+// Redaction modes
+const (
+	RedactModeMask = "mask"
+	RedactModeHash = "hash"
+)
+
+// Built-in detectors
+const (
+	RedactDetectorEmail              = "email"
+	RedactDetectorIPv4               = "ipv4"
+	RedactDetectorIPv6               = "ipv6"
+	RedactDetectorCreditCard         = "credit_card"
+	RedactDetectorJWT                = "jwt"
+	RedactDetectorBearerToken        = "bearer_token"
+	RedactDetectorAWSAccessKeyID     = "aws_access_key_id"
+	RedactDetectorAWSSecretAccessKey = "aws_secret_access_key"
+)
+
+const defaultRedactMask = "[REDACTED]"
+
+// redactDetector finds sensitive values in a string.
+// When the expression has capture groups, only the first one is redacted.
+type redactDetector struct {
+	name       string
+	expression *regexp.Regexp
+	// validate optionally discards the matches s[start:end] which are not actually sensitive.
+	validate func(s string, start, end int) bool
+}
+
+// builtinRedactDetectors are the built-in detectors, in the order they are applied.
+// Tokens are redacted first, so that the values they contain aren't detected as something else.
+var builtinRedactDetectors = []redactDetector{
+	{
+		name:       RedactDetectorJWT,
+		expression: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`),
+	},
+	{
+		name:       RedactDetectorBearerToken,
+		expression: regexp.MustCompile(`(?i)\bbearer\s+([A-Za-z0-9\-._~+/]+=*)`),
+	},
+	{
+		name:       RedactDetectorAWSAccessKeyID,
+		expression: regexp.MustCompile(`\b(?:AKIA|ASIA|ABIA|ACCA)[0-9A-Z]{16}\b`),
+	},
+	{
+		name:       RedactDetectorAWSSecretAccessKey,
+		expression: regexp.MustCompile(`(?i)aws_?secret_?access_?key["']?\s*[:=]\s*["']?([A-Za-z0-9/+=]{40})\b`),
+	},
+	{
+		name:       RedactDetectorEmail,
+		expression: regexp.MustCompile(`\b[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}\b`),
+	},
+	{
+		name:       RedactDetectorCreditCard,
+		expression: regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`),
+		validate:   isCreditCardNumber,
+	},
+	{
+		name:       RedactDetectorIPv6,
+		expression: regexp.MustCompile(`(?:[0-9A-Fa-f]{0,4}:){2,7}(?:[0-9A-Fa-f]{0,4}|(?:\d{1,3}\.){3}\d{1,3})`),
+		validate:   isIPv6,
+	},
+	{
+		name:       RedactDetectorIPv4,
+		expression: regexp.MustCompile(`\b(?:(?:25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)\.){3}(?:25[0-5]|2[0-4]\d|1\d\d|[1-9]?\d)\b`),
+	},
+}
+
+// isCreditCardNumber returns whether the digits of s[start:end] are a valid card number:
+// issued by a payment network and passing the Luhn check.
+func isCreditCardNumber(s string, start, end int) bool {
+	// Visa, Mastercard, American Express, Discover, JCB... numbers start with 3 to 6,
+	// which rules out most timestamps.
+	if s[start] < '3' || s[start] > '6' {
+		return false
+	}
+	var sum, digits int
+	for i := end - 1; i >= start; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if digits%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		digits++
+	}
+	return digits >= 13 && digits <= 19 && sum%10 == 0
+}
+
+// isIPv6 returns whether s[start:end] is an IPv6 address which is not part of a bigger word,
+// e.g. "std::fmt" or a MAC address.
+func isIPv6(s string, start, end int) bool {
+	if start > 0 && isAddressChar(s[start-1]) || end < len(s) && isAddressChar(s[end]) {
+		return false
+	}
+	return net.ParseIP(s[start:end]) != nil
+}
+
+func isAddressChar(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || c == ':' || c == '.'
+}
+
+// RedactPatternConfig configures a custom detector of the redact stage.
+type RedactPatternConfig struct {
+	Name       string `mapstructure:"name"`
+	Expression string `mapstructure:"expression"`
+}
+
+// RedactConfig configures the redact stage.
+type RedactConfig struct {
+	Source    *string               `mapstructure:"source"`
+	Detectors []string              `mapstructure:"detectors"`
+	Patterns  []RedactPatternConfig `mapstructure:"patterns"`
+	Mode      string                `mapstructure:"mode"`
+	Mask      *string               `mapstructure:"mask"`
+	Salt      string                `mapstructure:"salt"`
+}
+
+// validateRedactConfig validates the config and returns the detectors to apply.
+func validateRedactConfig(cfg *RedactConfig) ([]redactDetector, error) {
+	if cfg.Source != nil && *cfg.Source == "" {
+		return nil, errors.New(ErrEmptyRedactStageSource)
+	}
+
+	switch cfg.Mode {
+	case "":
+		cfg.Mode = RedactModeMask
+	case RedactModeMask, RedactModeHash:
+	default:
+		return nil, errors.Errorf(ErrRedactStageInvalidMode, cfg.Mode)
+	}
+	if cfg.Mask == nil {
+		mask := defaultRedactMask
+		cfg.Mask = &mask
+	}
+
+	var detectors []redactDetector
+	names := map[string]struct{}{}
+	if cfg.Detectors == nil {
+		detectors = append(detectors, builtinRedactDetectors...)
+	} else {
+		enabled := map[string]struct{}{}
+		for _, name := range cfg.Detectors {
+			if _, ok := enabled[name]; ok {
+				return nil, errors.Errorf(ErrRedactStageDuplicateName, name)
+			}
+			enabled[name] = struct{}{}
+		}
+		for _, d := range builtinRedactDetectors {
+			if _, ok := enabled[d.name]; ok {
+				detectors = append(detectors, d)
+				delete(enabled, d.name)
+			}
+		}
+		for _, name := range cfg.Detectors {
+			if _, ok := enabled[name]; ok {
+				return nil, errors.Errorf(ErrRedactStageUnknownDetector, name)
+			}
+		}
+	}
+	for _, d := range detectors {
+		names[d.name] = struct{}{}
+	}
+
+	for _, p := range cfg.Patterns {
+		if p.Name == "" {
+			return nil, errors.New(ErrRedactStagePatternName)
+		}
+		if _, ok := names[p.Name]; ok {
+			return nil, errors.Errorf(ErrRedactStageDuplicateName, p.Name)
+		}
+		names[p.Name] = struct{}{}
+		if p.Expression == "" {
+			return nil, errors.New(ErrExpressionRequired)
+		}
+		expr, err := regexp.Compile(p.Expression)
+		if err != nil {
+			return nil, errors.Wrapf(err, ErrRedactStageInvalidPattern, p.Name)
+		}
+		if expr.MatchString("") {
+			return nil, errors.Errorf(ErrRedactStagePatternNoMatches, p.Name)
+		}
+		detectors = append(detectors, redactDetector{name: p.Name, expression: expr})
+	}
+
+	if len(detectors) == 0 {
+		return nil, errors.New(ErrRedactStageNothingToRedact)
+	}
+	return detectors, nil
+}
+
+// redactStage replaces the sensitive values of the log line or of an extracted value.
+type redactStage struct {
+	cfg           *RedactConfig
+	detectors     []redactDetector
+	redactedCount *prometheus.CounterVec
+	logger        log.Logger
+}
+
+// newRedactStage creates a redactStage
+func newRedactStage(logger log.Logger, config interface{}, registerer prometheus.Registerer) (Stage, error) {
+	cfg := &RedactConfig{}
+	if err := mapstructure.Decode(config, cfg); err != nil {
+		return nil, err
+	}
+	detectors, err := validateRedactConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return toStage(&redactStage{
+		cfg:           cfg,
+		detectors:     detectors,
+		redactedCount: getRedactedCountMetric(registerer),
+		logger:        log.With(logger, "component", "stage", "type", "redact"),
+	}), nil
+}
+
+func getRedactedCountMetric(registerer prometheus.Registerer) *prometheus.CounterVec {
+	redactedCount := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "logentry",
+		Name:      "redacted_values_total",
+		Help:      "A count of all the values redacted by the redact stage, by detector",
+	}, []string{"detector"})
+	err := registerer.Register(redactedCount)
+	if err != nil {
+		if existing, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			redactedCount = existing.ExistingCollector.(*prometheus.CounterVec)
+		} else {
+			// Same behavior as MustRegister if the error is not for AlreadyRegistered
+			panic(err)
+		}
+	}
+	return redactedCount
+}
+
+// Process implements Stage
+func (r *redactStage) Process(_ model.LabelSet, extracted map[string]interface{}, _ *time.Time, entry *string) {
+	if r.cfg.Source == nil {
+		if entry == nil {
+			if Debug {
+				level.Debug(r.logger).Log("msg", "cannot redact a nil entry")
+			}
+			return
+		}
+		*entry = r.redact(*entry)
+		return
+	}
+
+	value, ok := extracted[*r.cfg.Source]
+	if !ok {
+		if Debug {
+			level.Debug(r.logger).Log("msg", "source does not exist in the set of extracted values", "source", *r.cfg.Source)
+		}
+		return
+	}
+	s, err := getString(value)
+	if err != nil {
+		if Debug {
+			level.Debug(r.logger).Log("msg", "failed to convert source value to string", "source", *r.cfg.Source, "err", err, "type", reflect.TypeOf(value))
+		}
+		return
+	}
+	extracted[*r.cfg.Source] = r.redact(s)
+}
+
+// redact applies all the detectors to s one after the other.
+func (r *redactStage) redact(s string) string {
+	for _, d := range r.detectors {
+		matches := d.expression.FindAllStringSubmatchIndex(s, -1)
+		if len(matches) == 0 {
+			continue
+		}
+
+		var (
+			sb       strings.Builder
+			last     int
+			redacted int
+		)
+		for _, m := range matches {
+			start, end := m[0], m[1]
+			// Only redact the first capture group if there is one.
+			if len(m) > 2 && m[2] >= 0 {
+				start, end = m[2], m[3]
+			}
+			if start == end || d.validate != nil && !d.validate(s, start, end) {
+				continue
+			}
+			sb.WriteString(s[last:start])
+			sb.WriteString(r.replacement(s[start:end]))
+			last = end
+			redacted++
+		}
+		if redacted == 0 {
+			continue
+		}
+		sb.WriteString(s[last:])
+		s = sb.String()
+
+		r.redactedCount.WithLabelValues(d.name).Add(float64(redacted))
+		if Debug {
+			level.Debug(r.logger).Log("msg", fmt.Sprintf("redacted %d values", redacted), "detector", d.name)
+		}
+	}
+	return s
+}
+
+func (r *redactStage) replacement(value string) string {
+	if r.cfg.Mode == RedactModeHash {
+		sum := sha256.Sum256([]byte(r.cfg.Salt + value))
+		return hex.EncodeToString(sum[:])
+	}
+	return *r.cfg.Mask
+}
+
+// Name implements Stage
+func (r *redactStage) Name() string {
+	return StageTypeRedact
+}
@@ -0,0 +1,139 @@
+package stages
+
+import (
+	"math/rand"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+)
+
+// Config Errors
+const (
+	ErrSamplingStageInvalidRate  = "sampling stage rate must be greater than 0 and less than or equal to 1, got %v"
+	ErrSamplingStageEmptySource  = "empty source in sampling stage"
+	ErrSamplingStageInvalidLabel = "invalid sample rate label name %q in sampling stage"
+)
+
+var (
+	defaultSamplingDropReason      = "sampling_stage"
+	defaultSamplingSampleRateLabel = "sample_rate"
+)
+
+// SamplingConfig contains the configuration for a samplingStage
+type SamplingConfig struct {
+	Rate            float64 `mapstructure:"rate"`
+	Source          *string `mapstructure:"source"`
+	SampleRateLabel *string `mapstructure:"sample_rate_label"`
+	DropReason      *string `mapstructure:"drop_counter_reason"`
+}
+
+// validateSamplingConfig validates the SamplingConfig for the samplingStage
+func validateSamplingConfig(cfg *SamplingConfig) error {
+	if cfg.Rate <= 0 || cfg.Rate > 1 {
+		return errors.Errorf(ErrSamplingStageInvalidRate, cfg.Rate)
+	}
+	if cfg.Source != nil && *cfg.Source == "" {
+		return errors.New(ErrSamplingStageEmptySource)
+	}
+	if cfg.SampleRateLabel == nil {
+		cfg.SampleRateLabel = &defaultSamplingSampleRateLabel
+	}
+	if *cfg.SampleRateLabel != "" && !model.LabelName(*cfg.SampleRateLabel).IsValid() {
+		return errors.Errorf(ErrSamplingStageInvalidLabel, *cfg.SampleRateLabel)
+	}
+	if cfg.DropReason == nil || *cfg.DropReason == "" {
+		cfg.DropReason = &defaultSamplingDropReason
+	}
+	return nil
+}
+
+// newSamplingStage creates a samplingStage from config
+func newSamplingStage(logger log.Logger, config interface{}, registerer prometheus.Registerer) (Stage, error) {
+	cfg := &SamplingConfig{}
+	err := mapstructure.WeakDecode(config, cfg)
+	if err != nil {
+		return nil, err
+	}
+	err = validateSamplingConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &samplingStage{
+		logger:     log.With(logger, "component", "stage", "type", "sampling"),
+		cfg:        cfg,
+		sampleRate: model.LabelValue(strconv.FormatFloat(cfg.Rate, 'f', -1, 64)),
+		dropCount:  getDropCountMetric(registerer),
+		random:     rand.New(rand.NewSource(time.Now().UnixNano())),
+	}, nil
+}
+
+// samplingStage keeps a fraction of the log lines and drops the others.
+type samplingStage struct {
+	logger     log.Logger
+	cfg        *SamplingConfig
+	sampleRate model.LabelValue
+	dropCount  *prometheus.CounterVec
+	// random is only used by the goroutine of Run.
+	random *rand.Rand
+}
+
+func (m *samplingStage) Run(in chan Entry) chan Entry {
+	out := make(chan Entry)
+	go func() {
+		defer close(out)
+		for e := range in {
+			if !m.shouldKeep(e) {
+				m.dropCount.WithLabelValues(*m.cfg.DropReason).Inc()
+				continue
+			}
+			if *m.cfg.SampleRateLabel != "" {
+				e.Labels[model.LabelName(*m.cfg.SampleRateLabel)] = m.sampleRate
+			}
+			out <- e
+		}
+	}()
+	return out
+}
+
+// shouldKeep samples the entry. When a source is configured, the decision only depends on its value,
+// so that all the lines sharing the same value are kept or dropped together.
+func (m *samplingStage) shouldKeep(e Entry) bool {
+	if m.cfg.Rate == 1 {
+		return true
+	}
+
+	if m.cfg.Source != nil {
+		if v, ok := e.Extracted[*m.cfg.Source]; ok {
+			s, err := getString(v)
+			if err == nil {
+				return sampleHash(s) < m.cfg.Rate
+			}
+			if Debug {
+				level.Debug(m.logger).Log("msg", "failed to convert source value to string, the line is sampled randomly", "source", *m.cfg.Source, "err", err, "type", reflect.TypeOf(v))
+			}
+		} else if Debug {
+			level.Debug(m.logger).Log("msg", "source does not exist in the set of extracted values, the line is sampled randomly", "source", *m.cfg.Source)
+		}
+	}
+	return m.random.Float64() < m.cfg.Rate
+}
+
+// sampleHash maps a value to a number uniformly distributed in [0, 1).
+func sampleHash(s string) float64 {
+	// Keep the 53 bits which a float64 represents exactly.
+	return float64(xxhash.Sum64String(s)>>11) / (1 << 53)
+}
+
+// Name implements Stage
+func (m *samplingStage) Name() string {
+	return StageTypeSampling
+}
@@ -0,0 +1,139 @@
+package stages
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	util_log "github.com/grafana/loki/pkg/util/log"
+)
+
+var testSamplingYaml = `
+pipeline_stages:
+- json:
+    expressions:
+      trace_id:
+- sampling:
+    rate: 0.25
+    source: trace_id
+`
+
+func TestSamplingPipeline(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	pl, err := NewPipeline(util_log.Logger, loadConfig(testSamplingYaml), nil, registry)
+	require.NoError(t, err)
+
+	const traces = 1000
+	var entries []Entry
+	for i := 0; i < traces; i++ {
+		for j := 0; j < 3; j++ {
+			entries = append(entries, newEntry(nil, nil, fmt.Sprintf(`{"trace_id":"%d","msg":"%d"}`, i, j), time.Now()))
+		}
+	}
+	out := processEntries(pl, entries...)
+
+	kept := map[string]int{}
+	for _, e := range out {
+		assert.Equal(t, model.LabelValue("0.25"), e.Labels["sample_rate"])
+		kept[e.Extracted["trace_id"].(string)]++
+	}
+	// Lines of the same trace are kept or dropped together.
+	for id, n := range kept {
+		assert.Equal(t, 3, n, "trace %s", id)
+	}
+	assert.InDelta(t, traces/4, len(kept), traces/20)
+
+	dropped := float64(len(entries) - len(out))
+	assert.Equal(t, dropped, testutil.ToFloat64(pl.stages[1].(*samplingStage).dropCount.WithLabelValues(defaultSamplingDropReason)))
+}
+
+func TestSamplingStage(t *testing.T) {
+	const lines = 10000
+	for _, tc := range []struct {
+		name   string
+		config map[string]interface{}
+		labels model.LabelSet
+	}{
+		{
+			name:   "random",
+			config: map[string]interface{}{"rate": 0.1},
+			labels: model.LabelSet{"app": "foo", "sample_rate": "0.1"},
+		},
+		{
+			name:   "source not extracted",
+			config: map[string]interface{}{"rate": 0.1, "source": "trace_id"},
+			labels: model.LabelSet{"app": "foo", "sample_rate": "0.1"},
+		},
+		{
+			name:   "custom label",
+			config: map[string]interface{}{"rate": "0.1", "sample_rate_label": "sampling"},
+			labels: model.LabelSet{"app": "foo", "sampling": "0.1"},
+		},
+		{
+			name:   "no label",
+			config: map[string]interface{}{"rate": 0.1, "sample_rate_label": ""},
+			labels: model.LabelSet{"app": "foo"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := newSamplingStage(util_log.Logger, tc.config, prometheus.NewRegistry())
+			require.NoError(t, err)
+
+			var entries []Entry
+			for i := 0; i < lines; i++ {
+				entries = append(entries, newEntry(nil, model.LabelSet{"app": "foo"}, "line", time.Now()))
+			}
+			out := processEntries(s, entries...)
+			assert.InDelta(t, lines/10, len(out), lines/50)
+			for _, e := range out {
+				assert.Equal(t, tc.labels, e.Labels)
+			}
+		})
+	}
+}
+
+func TestValidateSamplingConfig(t *testing.T) {
+	empty := ""
+	invalid := "sample-rate"
+	for name, tc := range map[string]struct {
+		config *SamplingConfig
+		err    error
+	}{
+		"valid": {
+			&SamplingConfig{Rate: 1},
+			nil,
+		},
+		"no rate": {
+			&SamplingConfig{},
+			errors.Errorf(ErrSamplingStageInvalidRate, 0.0),
+		},
+		"rate too high": {
+			&SamplingConfig{Rate: 1.5},
+			errors.Errorf(ErrSamplingStageInvalidRate, 1.5),
+		},
+		"empty source": {
+			&SamplingConfig{Rate: 0.5, Source: &empty},
+			errors.New(ErrSamplingStageEmptySource),
+		},
+		"invalid label": {
+			&SamplingConfig{Rate: 0.5, SampleRateLabel: &invalid},
+			errors.Errorf(ErrSamplingStageInvalidLabel, invalid),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := validateSamplingConfig(tc.config)
+			if tc.err == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tc.err.Error())
+		})
+	}
+}
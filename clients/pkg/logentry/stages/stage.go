@@ -37,6 +37,9 @@ const (
 	StageTypeLabelAllow   = "labelallow"
 	StageTypeStaticLabels = "static_labels"
 	StageTypeDecolorize   = "decolorize"
+	StageTypeRedact       = "redact"
+	StageTypeSampling     = "sampling"
+	StageTypeGeoIP        = "geoip"
 )
 
 // Processor takes an existing set of labels, timestamp and log entry and returns either a possibly mutated
@@ -215,6 +218,21 @@ func New(logger log.Logger, jobName *string, stageType string,
 		if err != nil {
 			return nil, err
 		}
+	case StageTypeRedact:
+		s, err = newRedactStage(logger, cfg, registerer)
+		if err != nil {
+			return nil, err
+		}
+	case StageTypeSampling:
+		s, err = newSamplingStage(logger, cfg, registerer)
+		if err != nil {
+			return nil, err
+		}
+	case StageTypeGeoIP:
+		s, err = newGeoIPStage(logger, cfg)
+		if err != nil {
+			return nil, err
+		}
 	default:
 		return nil, errors.Errorf("Unknown stage type: %s", stageType)
 	}
@@ -54,6 +54,10 @@ type Metrics struct {
 	countersWithHost   []*prometheus.CounterVec
 	countersWithTenant []*prometheus.CounterVec
 	streamLag          *prometheus.GaugeVec
+	walSize            *prometheus.GaugeVec
+	walDroppedBytes    *prometheus.CounterVec
+	walReplayedBatches *prometheus.CounterVec
+	walReplayProgress  *prometheus.GaugeVec
 }
 
 func NewMetrics(reg prometheus.Registerer, streamLagLabels []string) *Metrics {
@@ -95,6 +99,27 @@ func NewMetrics(reg prometheus.Registerer, streamLagLabels []string) *Metrics {
 		Help:      "Number of times batches has had to be retried.",
 	}, []string{HostLabel, TenantLabel})
 
+	m.walSize = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "promtail",
+		Name:      "wal_size_bytes",
+		Help:      "Size of the write ahead log holding batches not sent yet.",
+	}, []string{HostLabel})
+	m.walDroppedBytes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "promtail",
+		Name:      "wal_dropped_bytes_total",
+		Help:      "Number of bytes of write ahead log segments dropped because the write ahead log exceeded its max size.",
+	}, []string{HostLabel})
+	m.walReplayedBatches = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "promtail",
+		Name:      "wal_replayed_batches_total",
+		Help:      "Number of batches replayed from the write ahead log.",
+	}, []string{HostLabel})
+	m.walReplayProgress = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "promtail",
+		Name:      "wal_replay_progress_ratio",
+		Help:      "Ratio of the write ahead log replayed when the client started, between 0 and 1.",
+	}, []string{HostLabel})
+
 	m.countersWithHost = []*prometheus.CounterVec{
 		m.encodedBytes, m.sentBytes, m.sentEntries,
 	}
@@ -120,6 +145,10 @@ func NewMetrics(reg prometheus.Registerer, streamLagLabels []string) *Metrics {
 		m.requestDuration = mustRegisterOrGet(reg, m.requestDuration).(*prometheus.HistogramVec)
 		m.batchRetries = mustRegisterOrGet(reg, m.batchRetries).(*prometheus.CounterVec)
 		m.streamLag = mustRegisterOrGet(reg, m.streamLag).(*prometheus.GaugeVec)
+		m.walSize = mustRegisterOrGet(reg, m.walSize).(*prometheus.GaugeVec)
+		m.walDroppedBytes = mustRegisterOrGet(reg, m.walDroppedBytes).(*prometheus.CounterVec)
+		m.walReplayedBatches = mustRegisterOrGet(reg, m.walReplayedBatches).(*prometheus.CounterVec)
+		m.walReplayProgress = mustRegisterOrGet(reg, m.walReplayProgress).(*prometheus.GaugeVec)
 	}
 
 	return &m
@@ -153,6 +182,13 @@ type client struct {
 	client          *http.Client
 	entries         chan api.Entry
 
+	// wal persists the batches until they're sent, it's nil when disabled.
+	wal *clientWAL
+	// walStop stops the goroutine retrying the batches of the WAL, which
+	// closes walDone once it's stopped.
+	walStop chan struct{}
+	walDone chan struct{}
+
 	once sync.Once
 	wg   sync.WaitGroup
 
@@ -212,6 +248,15 @@ func newClient(metrics *Metrics, cfg Config, streamLagLabels []string, maxStream
 
 	c.client.Timeout = cfg.Timeout
 
+	if cfg.WAL.Enabled {
+		c.wal, err = newClientWAL(cfg.WAL, c.name, c.metrics, cfg.URL.Host, c.logger)
+		if err != nil {
+			return nil, fmt.Errorf("opening client WAL: %w", err)
+		}
+		c.walStop = make(chan struct{})
+		c.walDone = make(chan struct{})
+	}
+
 	// Initialize counters to 0 so the metrics are exported before the first
 	// occurrence of incrementing to avoid missing metrics.
 	for _, counter := range c.metrics.countersWithHost {
@@ -220,6 +265,9 @@ func newClient(metrics *Metrics, cfg Config, streamLagLabels []string, maxStream
 
 	c.wg.Add(1)
 	go c.run()
+	if c.wal != nil {
+		go c.runWALRetries()
+	}
 	return c, nil
 }
 
@@ -261,6 +309,14 @@ func (c *client) run() {
 			c.sendBatch(tenantID, batch)
 		}
 
+		if c.wal != nil {
+			// The batches not replayed or retried yet are kept in the WAL for the next run.
+			close(c.walStop)
+			<-c.walDone
+			if err := c.wal.close(); err != nil {
+				level.Error(c.logger).Log("msg", "error closing WAL", "error", err)
+			}
+		}
 		c.wg.Done()
 	}()
 
@@ -333,11 +389,80 @@ func (c *client) sendBatch(tenantID string, batch *batch) {
 		level.Error(c.logger).Log("msg", "error encoding batch", "error", err)
 		return
 	}
+	c.metrics.encodedBytes.WithLabelValues(c.cfg.URL.Host).Add(float64(len(buf)))
+
+	segment := -1
+	if c.wal != nil {
+		segment, err = c.wal.log(tenantID, buf)
+		if err != nil {
+			level.Error(c.logger).Log("msg", "error writing batch to WAL", "tenant", tenantID, "error", err)
+		}
+	}
+	c.sendEncodedBatch(tenantID, batch, buf, entriesCount, segment)
+}
+
+// runWALRetries replays the batches which couldn't be sent before the previous
+// shutdown, and then sends again the batches which couldn't be sent because of
+// a retryable error, with a backoff between attempts. It runs alongside run, so
+// that the replay and the retries don't block new entries.
+func (c *client) runWALRetries() {
+	defer close(c.walDone)
+
+	c.wal.replayBatches(c.walStop, c.sendWALBatch)
+
+	backoff := backoff.New(c.ctx, backoff.Config{
+		MinBackoff: c.cfg.BackoffConfig.MinBackoff,
+		MaxBackoff: c.cfg.BackoffConfig.MaxBackoff,
+	})
+	for {
+		select {
+		case <-c.walStop:
+			return
+		case <-c.wal.retryc:
+		}
+
+		for batches := c.wal.takeRetries(); len(batches) > 0; batches = c.wal.takeRetries() {
+			select {
+			case <-c.walStop:
+				return
+			case <-time.After(backoff.NextDelay()):
+			}
+			for _, b := range batches {
+				select {
+				case <-c.walStop:
+					return
+				default:
+				}
+				c.sendWALBatch(b)
+			}
+		}
+		backoff.Reset()
+	}
+}
+
+func (c *client) sendWALBatch(b walBatch) {
+	c.sendEncodedBatch(b.tenantID, b.batch, b.buf, b.entriesCount, b.segment)
+}
+
+// sendEncodedBatch sends the encoded batch with retries. When the batch is
+// persisted in the given WAL segment, it's kept there if it can't be sent
+// because of a retryable error, to be sent again in the background.
+func (c *client) sendEncodedBatch(tenantID string, batch *batch, buf []byte, entriesCount int, segment int) {
+	keep := false
+	if segment >= 0 {
+		defer func() {
+			if !keep {
+				c.wal.done(segment)
+			}
+		}()
+	}
 	bufBytes := float64(len(buf))
-	c.metrics.encodedBytes.WithLabelValues(c.cfg.URL.Host).Add(bufBytes)
 
 	backoff := backoff.New(c.ctx, c.cfg.BackoffConfig)
-	var status int
+	var (
+		status int
+		err    error
+	)
 	for {
 		start := time.Now()
 		// send uses `timeout` internally, so `context.Background` is good enough.
@@ -399,6 +524,12 @@ func (c *client) sendBatch(tenantID string, batch *batch) {
 	}
 
 	if err != nil {
+		if segment >= 0 && (status <= 0 || status == 429 || status/100 == 5) {
+			level.Error(c.logger).Log("msg", "final error sending batch, keeping it in WAL to retry it", "status", status, "tenant", tenantID, "error", err)
+			keep = true
+			c.wal.retryLater(walBatch{tenantID: tenantID, batch: batch, buf: buf, entriesCount: entriesCount, segment: segment})
+			return
+		}
 		level.Error(c.logger).Log("msg", "final error sending batch", "status", status, "tenant", tenantID, "error", err)
 		c.metrics.droppedBytes.WithLabelValues(c.cfg.URL.Host, tenantID).Add(bufBytes)
 		c.metrics.droppedEntries.WithLabelValues(c.cfg.URL.Host, tenantID).Add(float64(entriesCount))
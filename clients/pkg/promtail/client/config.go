@@ -39,6 +39,9 @@ type Config struct {
 	// single tenant mode)
 	TenantID string `yaml:"tenant_id"`
 
+	// The write ahead log persisting batches until they're sent to Loki.
+	WAL WALConfig `yaml:"wal"`
+
 	// deprecated use StreamLagLabels from config.Config instead
 	StreamLagLabels flagext.StringSliceCSV `yaml:"stream_lag_labels"`
 }
@@ -82,6 +85,9 @@ func (c *Config) UnmarshalYAML(unmarshal func(interface{}) error) error {
 			BatchSize: BatchSize,
 			BatchWait: BatchWait,
 			Timeout:   Timeout,
+			WAL: WALConfig{
+				MaxSize: DefaultWALMaxSize,
+			},
 		}
 	}
 
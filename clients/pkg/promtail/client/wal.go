@@ -0,0 +1,381 @@
+package client
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/prometheus/prometheus/tsdb/fileutil"
+	"github.com/prometheus/prometheus/tsdb/wlog"
+
+	"github.com/grafana/loki/pkg/logproto"
+	lokiflag "github.com/grafana/loki/pkg/util/flagext"
+	"github.com/grafana/loki/pkg/util/wal"
+)
+
+const (
+	// walSegmentSize is the size of the WAL segments, which is also the
+	// granularity at which the WAL is truncated and its size bounded.
+	walSegmentSize = 8 * 1024 * 1024
+
+	DefaultWALMaxSize = 1 << 30
+
+	// walPageSize and walRecordHeaderSize are the size of the pages of the WAL
+	// segments, and the size of the header of each record fragment in a page.
+	walPageSize         = 32 * 1024
+	walRecordHeaderSize = 7
+)
+
+// WALConfig describes the optional write ahead log of a client, which
+// persists batches until they're successfully sent to Loki.
+type WALConfig struct {
+	Enabled bool              `yaml:"enabled"`
+	Dir     string            `yaml:"dir"`
+	MaxSize lokiflag.ByteSize `yaml:"max_size"`
+}
+
+// clientWAL persists the encoded batches of a client before they're sent, so
+// that batches which couldn't be sent are retried in the background, and
+// replayed when the client restarts.
+//
+// Batches are appended to segments, and a segment is removed once all the
+// batches it holds have been sent or dropped. The WAL is used both from the
+// goroutine running the client and from the one retrying the batches.
+type clientWAL struct {
+	wl      *wlog.WL
+	logger  log.Logger
+	metrics *Metrics
+	host    string
+	maxSize int64
+
+	mtx sync.Mutex
+	// pending is the number of batches not sent yet for each segment.
+	pending map[int]int
+	// replaySegment is the first segment which isn't entirely replayed yet,
+	// which must not be truncated, or -1 once the replay is done.
+	replaySegment int
+	// retries are the batches which couldn't be sent, to be sent again.
+	retries []walBatch
+	// retryc is notified when a batch is added to the retries.
+	retryc chan struct{}
+
+	// replay reads the segments which existed when the WAL was opened.
+	replay       *wlog.Reader
+	replayCloser io.Closer
+	replaySize   int64
+}
+
+// walBatch is an encoded batch persisted in a segment of the WAL.
+type walBatch struct {
+	tenantID     string
+	batch        *batch
+	buf          []byte
+	entriesCount int
+	segment      int
+}
+
+func newClientWAL(cfg WALConfig, name string, metrics *Metrics, host string, logger log.Logger) (*clientWAL, error) {
+	if cfg.Dir == "" {
+		return nil, errors.New("client WAL needs a directory")
+	}
+	dir := filepath.Join(cfg.Dir, name)
+
+	w := &clientWAL{
+		logger:  log.With(logger, "wal", dir),
+		metrics: metrics,
+		host:    host,
+		maxSize: int64(cfg.MaxSize.Val()),
+		pending: map[int]int{},
+		retryc:  make(chan struct{}, 1),
+
+		replaySegment: -1,
+	}
+
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, err
+	}
+
+	// The reader is opened before the WAL, so that it only reads the segments
+	// written by a previous run and not the ones about to be written.
+	first, last, err := wlog.Segments(dir)
+	if err != nil {
+		return nil, err
+	}
+	if last >= 0 {
+		w.replaySize, err = fileutil.DirSize(dir)
+		if err != nil {
+			return nil, err
+		}
+		w.replay, w.replayCloser, err = wal.NewWalReader(dir, first)
+		if err != nil {
+			return nil, err
+		}
+		w.replaySegment = first
+	}
+
+	// The metrics of the underlying WAL aren't registered, as multiple clients
+	// would register the same ones.
+	w.wl, err = wlog.NewSize(w.logger, nil, dir, walSegmentSize, false)
+	if err != nil {
+		if w.replayCloser != nil {
+			_ = w.replayCloser.Close()
+		}
+		return nil, err
+	}
+	w.updateSize()
+	return w, nil
+}
+
+// log persists the encoded batch of the tenant, and returns the segment it was
+// written to.
+func (w *clientWAL) log(tenantID string, buf []byte) (int, error) {
+	rec := make([]byte, 0, binary.MaxVarintLen64+len(tenantID)+len(buf))
+	rec = binary.AppendUvarint(rec, uint64(len(tenantID)))
+	rec = append(rec, tenantID...)
+	rec = append(rec, buf...)
+
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	if err := w.rotateIfFull(len(rec)); err != nil {
+		return -1, err
+	}
+	if err := w.wl.Log(rec); err != nil {
+		return -1, err
+	}
+	segment, _, err := w.wl.LastSegmentAndOffset()
+	if err != nil {
+		return -1, err
+	}
+	w.pending[segment]++
+	w.enforceMaxSize()
+	return segment, nil
+}
+
+// rotateIfFull starts a new segment if the record doesn't fit in the current
+// one. The WAL would otherwise start it itself, and sync the full segment
+// asynchronously: it's synced before the record is written instead.
+func (w *clientWAL) rotateIfFull(size int) error {
+	_, offset, err := w.wl.LastSegmentAndOffset()
+	if err != nil || offset == 0 {
+		return err
+	}
+	remaining := walSegmentSize - offset
+	pages := (remaining + walPageSize - 1) / walPageSize
+	if size <= remaining-pages*walRecordHeaderSize {
+		return nil
+	}
+	_, err = w.wl.NextSegmentSync()
+	return err
+}
+
+// done marks a batch of the segment as sent or dropped.
+func (w *clientWAL) done(segment int) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	if w.pending[segment]--; w.pending[segment] <= 0 {
+		delete(w.pending, segment)
+	}
+	w.truncate()
+}
+
+// retryLater queues a batch which couldn't be sent, to be sent again.
+func (w *clientWAL) retryLater(b walBatch) {
+	w.mtx.Lock()
+	w.retries = append(w.retries, b)
+	w.mtx.Unlock()
+
+	select {
+	case w.retryc <- struct{}{}:
+	default:
+	}
+}
+
+// takeRetries returns the batches to send again. The batches of the segments
+// dropped because the WAL exceeded its max size are discarded.
+func (w *clientWAL) takeRetries() []walBatch {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	retries := w.retries[:0]
+	for _, b := range w.retries {
+		if _, ok := w.pending[b.segment]; ok {
+			retries = append(retries, b)
+		}
+	}
+	w.retries = nil
+	return retries
+}
+
+// truncate removes the segments whose batches are all sent or dropped, and
+// which are replayed. It must be called with the lock held.
+func (w *clientWAL) truncate() {
+	last, _, err := w.wl.LastSegmentAndOffset()
+	if err != nil {
+		level.Error(w.logger).Log("msg", "error getting the last WAL segment", "error", err)
+		return
+	}
+	if w.replaySegment >= 0 && w.replaySegment < last {
+		last = w.replaySegment
+	}
+	for segment := range w.pending {
+		if segment < last {
+			last = segment
+		}
+	}
+	if err := w.wl.Truncate(last); err != nil {
+		level.Error(w.logger).Log("msg", "error truncating the WAL", "error", err)
+	}
+	w.updateSize()
+}
+
+// enforceMaxSize drops the oldest segments until the WAL fits in its max size.
+// The segment being written is never dropped. It must be called with the lock held.
+func (w *clientWAL) enforceMaxSize() {
+	if w.maxSize <= 0 {
+		return
+	}
+	size := w.updateSize()
+	if size <= w.maxSize {
+		return
+	}
+	first, last, err := wlog.Segments(w.wl.Dir())
+	if err != nil {
+		level.Error(w.logger).Log("msg", "error listing WAL segments", "error", err)
+		return
+	}
+	for ; size > w.maxSize && first < last; first++ {
+		if err := w.wl.Truncate(first + 1); err != nil {
+			level.Error(w.logger).Log("msg", "error truncating the WAL", "error", err)
+			return
+		}
+		level.Warn(w.logger).Log("msg", "dropped WAL segment because the WAL exceeds its max size", "segment", first, "batches", w.pending[first], "max_size", w.maxSize)
+		delete(w.pending, first)
+
+		prev := size
+		size = w.updateSize()
+		w.metrics.walDroppedBytes.WithLabelValues(w.host).Add(float64(prev - size))
+	}
+}
+
+func (w *clientWAL) updateSize() int64 {
+	size, err := w.wl.Size()
+	if err != nil {
+		level.Error(w.logger).Log("msg", "error computing the WAL size", "error", err)
+		return 0
+	}
+	w.metrics.walSize.WithLabelValues(w.host).Set(float64(size))
+	return size
+}
+
+// replayBatches calls send with each batch written by a previous run, until
+// stop is closed. The segments are removed once they're replayed and their
+// batches are sent, the ones not replayed yet are kept for the next run.
+func (w *clientWAL) replayBatches(stop <-chan struct{}, send func(b walBatch)) {
+	if w.replay == nil {
+		return
+	}
+	defer func() {
+		_ = w.replayCloser.Close()
+		w.replay, w.replayCloser = nil, nil
+	}()
+
+	progress := w.metrics.walReplayProgress.WithLabelValues(w.host)
+	progress.Set(0)
+
+	var read int64
+	for w.replay.Next() {
+		select {
+		case <-stop:
+			return
+		default:
+		}
+
+		rec := w.replay.Record()
+		read += int64(len(rec))
+		if w.replaySize > 0 {
+			progress.Set(float64(read) / float64(w.replaySize))
+		}
+
+		tenantID, batch, buf, entriesCount, err := decodeWALRecord(rec)
+		if err != nil {
+			level.Error(w.logger).Log("msg", "error decoding WAL record, skipping it", "segment", w.replay.Segment(), "error", err)
+			continue
+		}
+		segment := w.replay.Segment()
+		w.mtx.Lock()
+		w.replaySegment = segment
+		w.pending[segment]++
+		w.mtx.Unlock()
+
+		send(walBatch{tenantID: tenantID, batch: batch, buf: buf, entriesCount: entriesCount, segment: segment})
+		w.metrics.walReplayedBatches.WithLabelValues(w.host).Inc()
+	}
+	if err := w.replay.Err(); err != nil {
+		level.Error(w.logger).Log("msg", "error replaying the WAL", "error", err)
+	}
+	progress.Set(1)
+
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	w.replaySegment = -1
+	w.truncate()
+}
+
+// close closes the WAL. The batches already sent are removed from the segment
+// being written by starting a new one, so that they aren't replayed again.
+func (w *clientWAL) close() error {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	if w.replayCloser != nil {
+		_ = w.replayCloser.Close()
+	}
+	if _, err := w.wl.NextSegmentSync(); err != nil {
+		level.Error(w.logger).Log("msg", "error starting a new WAL segment", "error", err)
+	} else {
+		w.truncate()
+	}
+	return w.wl.Close()
+}
+
+// decodeWALRecord decodes a record written by clientWAL.log. The batch is
+// rebuilt from the encoded push request.
+func decodeWALRecord(rec []byte) (string, *batch, []byte, int, error) {
+	n, size := binary.Uvarint(rec)
+	if size <= 0 || uint64(len(rec)-size) < n {
+		return "", nil, nil, 0, fmt.Errorf("invalid WAL record of %d bytes", len(rec))
+	}
+	tenantID := string(rec[size : size+int(n)])
+	buf := append([]byte(nil), rec[size+int(n):]...)
+
+	decoded, err := snappy.Decode(nil, buf)
+	if err != nil {
+		return "", nil, nil, 0, err
+	}
+	var req logproto.PushRequest
+	if err := proto.Unmarshal(decoded, &req); err != nil {
+		return "", nil, nil, 0, err
+	}
+
+	b := &batch{streams: make(map[string]*logproto.Stream, len(req.Streams))}
+	entriesCount := 0
+	for i := range req.Streams {
+		s := req.Streams[i]
+		b.streams[s.Labels] = &s
+		entriesCount += len(s.Entries)
+		for _, e := range s.Entries {
+			b.bytes += len(e.Line)
+		}
+	}
+	return tenantID, b, buf, entriesCount, nil
+}
@@ -0,0 +1,220 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/backoff"
+	"github.com/grafana/dskit/flagext"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/tsdb/wlog"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+
+	"github.com/grafana/loki/clients/pkg/promtail/api"
+
+	"github.com/grafana/loki/pkg/logproto"
+)
+
+func TestClient_WALReplay(t *testing.T) {
+	dir := t.TempDir()
+
+	newWALClient := func(status int, reqs chan receivedReq) (*client, *prometheus.Registry) {
+		server := httptest.NewServer(createServerHandler(reqs, status))
+		t.Cleanup(server.Close)
+
+		serverURL := flagext.URLValue{}
+		require.NoError(t, serverURL.Set(server.URL))
+
+		reg := prometheus.NewRegistry()
+		c, err := newClient(NewMetrics(reg, nil), Config{
+			Name:          "wal-test",
+			URL:           serverURL,
+			BatchWait:     time.Minute,
+			BatchSize:     1024,
+			BackoffConfig: backoff.Config{MinBackoff: 1 * time.Millisecond, MaxBackoff: 2 * time.Millisecond, MaxRetries: 2},
+			Timeout:       1 * time.Second,
+			WAL:           WALConfig{Enabled: true, Dir: dir, MaxSize: DefaultWALMaxSize},
+		}, nil, 0, log.NewNopLogger())
+		require.NoError(t, err)
+		return c, reg
+	}
+
+	// A 4xx error isn't retryable, so the batch is dropped from the WAL.
+	rejectedReqs := make(chan receivedReq, 10)
+	c, _ := newWALClient(400, rejectedReqs)
+	c.Chan() <- logEntries[2]
+	c.Stop()
+	require.Len(t, rejectedReqs, 1)
+
+	// Loki is failing, so the batch is kept in the WAL.
+	failedReqs := make(chan receivedReq, 10)
+	c, _ = newWALClient(500, failedReqs)
+	c.Chan() <- logEntries[0]
+	c.Chan() <- logEntries[1]
+	c.Stop()
+	require.NotEmpty(t, failedReqs)
+
+	// The batches are replayed in the background on restart, and the WAL is truncated once sent.
+	reqs := make(chan receivedReq, 10)
+	c, reg := newWALClient(200, reqs)
+	require.Eventually(t, func() bool {
+		return len(reqs) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	c.Stop()
+	close(reqs)
+
+	var received []logproto.Entry
+	for req := range reqs {
+		for _, s := range req.pushReq.Streams {
+			received = append(received, s.Entries...)
+		}
+	}
+	require.Equal(t, []logproto.Entry{logEntries[0].Entry, logEntries[1].Entry}, received)
+
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+		# HELP promtail_wal_replay_progress_ratio Ratio of the write ahead log replayed when the client started, between 0 and 1.
+		# TYPE promtail_wal_replay_progress_ratio gauge
+		promtail_wal_replay_progress_ratio{host="`+c.cfg.URL.Host+`"} 1
+		# HELP promtail_wal_replayed_batches_total Number of batches replayed from the write ahead log.
+		# TYPE promtail_wal_replayed_batches_total counter
+		promtail_wal_replayed_batches_total{host="`+c.cfg.URL.Host+`"} 1
+	`), "promtail_wal_replay_progress_ratio", "promtail_wal_replayed_batches_total"))
+
+	first, last, err := wlog.Segments(dir + "/wal-test")
+	require.NoError(t, err)
+	require.Equal(t, first, last)
+}
+
+func TestClient_WALRetry(t *testing.T) {
+	dir := t.TempDir()
+
+	// Loki fails the first requests, which are all the retries of the first attempt to send the batch.
+	var requests atomic.Int32
+	reqs := make(chan receivedReq, 10)
+	succeed := createServerHandler(reqs, 200)
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if requests.Inc() <= 3 {
+			rw.WriteHeader(500)
+			return
+		}
+		succeed(rw, req)
+	}))
+	defer server.Close()
+
+	serverURL := flagext.URLValue{}
+	require.NoError(t, serverURL.Set(server.URL))
+	c, err := newClient(NewMetrics(prometheus.NewRegistry(), nil), Config{
+		Name:          "wal-test",
+		URL:           serverURL,
+		BatchWait:     10 * time.Millisecond,
+		BatchSize:     1024,
+		BackoffConfig: backoff.Config{MinBackoff: 1 * time.Millisecond, MaxBackoff: 2 * time.Millisecond, MaxRetries: 3},
+		Timeout:       1 * time.Second,
+		WAL:           WALConfig{Enabled: true, Dir: dir, MaxSize: DefaultWALMaxSize},
+	}, nil, 0, log.NewNopLogger())
+	require.NoError(t, err)
+	defer c.Stop()
+
+	// The batch is sent again in the background, without waiting for a restart.
+	c.Chan() <- logEntries[0]
+	var req receivedReq
+	select {
+	case req = <-reqs:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the batch wasn't retried")
+	}
+	require.Equal(t, []logproto.Entry{logEntries[0].Entry}, req.pushReq.Streams[0].Entries)
+
+	// The following batches are sent as usual, and the WAL is emptied.
+	c.Chan() <- logEntries[1]
+	select {
+	case req = <-reqs:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the new batch wasn't sent")
+	}
+	require.Equal(t, []logproto.Entry{logEntries[1].Entry}, req.pushReq.Streams[0].Entries)
+
+	require.Eventually(t, func() bool {
+		c.wal.mtx.Lock()
+		defer c.wal.mtx.Unlock()
+		return len(c.wal.pending) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestClientWAL_MaxSize(t *testing.T) {
+	metrics := NewMetrics(prometheus.NewRegistry(), nil)
+	w, err := newClientWAL(WALConfig{Dir: t.TempDir(), MaxSize: 2 * walSegmentSize}, "test", metrics, "host", log.NewNopLogger())
+	require.NoError(t, err)
+	defer w.close()
+
+	b := newBatch(0, api.Entry{Labels: nil, Entry: logproto.Entry{Timestamp: time.Unix(1, 0), Line: strings.Repeat("a", 1024)}})
+	buf, _, err := b.encode()
+	require.NoError(t, err)
+
+	// Fill a bit more than 4 segments without sending anything.
+	big := make([]byte, walSegmentSize/4)
+	for i := 0; i < 17; i++ {
+		_, err := w.log("tenant", big)
+		require.NoError(t, err)
+	}
+	size, err := w.wl.Size()
+	require.NoError(t, err)
+	require.LessOrEqual(t, size, int64(2*walSegmentSize))
+	require.Greater(t, testutil.ToFloat64(metrics.walDroppedBytes.WithLabelValues("host")), float64(0))
+
+	// Once the pending batches are done, the previous segments are removed.
+	segment, err := w.log("tenant", buf)
+	require.NoError(t, err)
+	for s, n := range w.pending {
+		for ; n > 0; n-- {
+			w.done(s)
+		}
+	}
+	first, _, err := wlog.Segments(w.wl.Dir())
+	require.NoError(t, err)
+	require.Equal(t, segment, first)
+
+	entries, err := os.ReadDir(w.wl.Dir())
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+}
+
+func TestDecodeWALRecord(t *testing.T) {
+	b := newBatch(0,
+		api.Entry{Labels: model.LabelSet{"app": "foo"}, Entry: logproto.Entry{Timestamp: time.Unix(1, 0).UTC(), Line: "line1"}},
+	)
+	buf, entriesCount, err := b.encode()
+	require.NoError(t, err)
+
+	metrics := NewMetrics(nil, nil)
+	w, err := newClientWAL(WALConfig{Dir: t.TempDir()}, "test", metrics, "host", log.NewNopLogger())
+	require.NoError(t, err)
+	defer w.close()
+	_, err = w.log("tenant-1", buf)
+	require.NoError(t, err)
+
+	segments, err := wlog.NewSegmentsReader(w.wl.Dir())
+	require.NoError(t, err)
+	defer segments.Close()
+	reader := wlog.NewReader(segments)
+	require.True(t, reader.Next())
+
+	tenantID, decoded, decodedBuf, decodedCount, err := decodeWALRecord(reader.Record())
+	require.NoError(t, err)
+	require.Equal(t, "tenant-1", tenantID)
+	require.Equal(t, buf, decodedBuf)
+	require.Equal(t, entriesCount, decodedCount)
+	require.Equal(t, b.streams, decoded.streams)
+	require.Equal(t, b.bytes, decoded.bytes)
+
+	_, _, _, _, err = decodeWALRecord([]byte{0x10, 'a'})
+	require.Error(t, err)
+}
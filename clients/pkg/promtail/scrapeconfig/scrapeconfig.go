@@ -29,22 +29,26 @@ import (
 
 	"github.com/grafana/loki/clients/pkg/logentry/stages"
 	"github.com/grafana/loki/clients/pkg/promtail/discovery/consulagent"
+
+	lokiflag "github.com/grafana/loki/pkg/util/flagext"
 )
 
 // Config describes a job to scrape.
 type Config struct {
-	JobName           string                     `mapstructure:"job_name,omitempty" yaml:"job_name,omitempty"`
-	PipelineStages    stages.PipelineStages      `mapstructure:"pipeline_stages,omitempty" yaml:"pipeline_stages,omitempty"`
-	JournalConfig     *JournalTargetConfig       `mapstructure:"journal,omitempty" yaml:"journal,omitempty"`
-	SyslogConfig      *SyslogTargetConfig        `mapstructure:"syslog,omitempty" yaml:"syslog,omitempty"`
-	GcplogConfig      *GcplogTargetConfig        `mapstructure:"gcplog,omitempty" yaml:"gcplog,omitempty"`
-	PushConfig        *PushTargetConfig          `mapstructure:"loki_push_api,omitempty" yaml:"loki_push_api,omitempty"`
-	WindowsConfig     *WindowsEventsTargetConfig `mapstructure:"windows_events,omitempty" yaml:"windows_events,omitempty"`
-	KafkaConfig       *KafkaTargetConfig         `mapstructure:"kafka,omitempty" yaml:"kafka,omitempty"`
-	GelfConfig        *GelfTargetConfig          `mapstructure:"gelf,omitempty" yaml:"gelf,omitempty"`
-	CloudflareConfig  *CloudflareConfig          `mapstructure:"cloudflare,omitempty" yaml:"cloudflare,omitempty"`
-	HerokuDrainConfig *HerokuDrainTargetConfig   `mapstructure:"heroku_drain,omitempty" yaml:"heroku_drain,omitempty"`
-	RelabelConfigs    []*relabel.Config          `mapstructure:"relabel_configs,omitempty" yaml:"relabel_configs,omitempty"`
+	JobName             string                     `mapstructure:"job_name,omitempty" yaml:"job_name,omitempty"`
+	PipelineStages      stages.PipelineStages      `mapstructure:"pipeline_stages,omitempty" yaml:"pipeline_stages,omitempty"`
+	JournalConfig       *JournalTargetConfig       `mapstructure:"journal,omitempty" yaml:"journal,omitempty"`
+	SyslogConfig        *SyslogTargetConfig        `mapstructure:"syslog,omitempty" yaml:"syslog,omitempty"`
+	GcplogConfig        *GcplogTargetConfig        `mapstructure:"gcplog,omitempty" yaml:"gcplog,omitempty"`
+	PushConfig          *PushTargetConfig          `mapstructure:"loki_push_api,omitempty" yaml:"loki_push_api,omitempty"`
+	WindowsConfig       *WindowsEventsTargetConfig `mapstructure:"windows_events,omitempty" yaml:"windows_events,omitempty"`
+	KafkaConfig         *KafkaTargetConfig         `mapstructure:"kafka,omitempty" yaml:"kafka,omitempty"`
+	GelfConfig          *GelfTargetConfig          `mapstructure:"gelf,omitempty" yaml:"gelf,omitempty"`
+	CloudflareConfig    *CloudflareConfig          `mapstructure:"cloudflare,omitempty" yaml:"cloudflare,omitempty"`
+	HerokuDrainConfig   *HerokuDrainTargetConfig   `mapstructure:"heroku_drain,omitempty" yaml:"heroku_drain,omitempty"`
+	FluentForwardConfig *FluentForwardTargetConfig `mapstructure:"fluent_forward,omitempty" yaml:"fluent_forward,omitempty"`
+	OTLPConfig          *OTLPTargetConfig          `mapstructure:"otlp,omitempty" yaml:"otlp,omitempty"`
+	RelabelConfigs      []*relabel.Config          `mapstructure:"relabel_configs,omitempty" yaml:"relabel_configs,omitempty"`
 	// List of Docker service discovery configurations.
 	DockerSDConfigs        []*moby.DockerSDConfig `mapstructure:"docker_sd_configs,omitempty" yaml:"docker_sd_configs,omitempty"`
 	ServiceDiscoveryConfig ServiceDiscoveryConfig `mapstructure:",squash" yaml:",inline"`
@@ -390,6 +394,45 @@ type HerokuDrainTargetConfig struct {
 	UseIncomingTimestamp bool `yaml:"use_incoming_timestamp"`
 }
 
+// FluentForwardTargetConfig describes a scrape config that listens for log records sent with the Fluent Forward protocol,
+// e.g. by the `forward` output of fluentd and fluent-bit.
+type FluentForwardTargetConfig struct {
+	// ListenAddress is the address to listen on TCP for Fluent Forward messages. (Default to `:24224`)
+	ListenAddress string `yaml:"listen_address"`
+
+	// IdleTimeout is the idle timeout for tcp connections.
+	IdleTimeout time.Duration `yaml:"idle_timeout"`
+
+	// SharedKey is the key shared with the clients to authenticate them. Authentication is disabled if empty.
+	SharedKey flagext.Secret `yaml:"shared_key"`
+
+	// SelfHostname is the hostname sent to the clients during authentication. (Default to the hostname of the host)
+	SelfHostname string `yaml:"self_hostname"`
+
+	// ChunkSizeLimit is the maximum size of a message as received, and of its entries after decompression. (Default to 8MB)
+	ChunkSizeLimit lokiflag.ByteSize `yaml:"chunk_size_limit"`
+
+	// Labels optionally holds labels to associate with each record received.
+	Labels model.LabelSet `yaml:"labels"`
+
+	// UseIncomingTimestamp sets the timestamp to the incoming record time. If false,
+	// promtail will assign the current timestamp to the log entry when it was processed.
+	UseIncomingTimestamp bool `yaml:"use_incoming_timestamp"`
+}
+
+// OTLPTargetConfig describes a scrape config that receives logs exported with the OpenTelemetry protocol over gRPC and HTTP.
+type OTLPTargetConfig struct {
+	// Server is the weaveworks server config for listening connections
+	Server server.Config `yaml:"server"`
+
+	// Labels optionally holds labels to associate with each log record received.
+	Labels model.LabelSet `yaml:"labels"`
+
+	// UseIncomingTimestamp sets the timestamp to the incoming log record timestamp. If false,
+	// promtail will assign the current timestamp to the log entry when it was processed.
+	UseIncomingTimestamp bool `yaml:"use_incoming_timestamp"`
+}
+
 // PushTargetConfig describes a scrape config that listens for Loki push messages.
 type PushTargetConfig struct {
 	// Server is the weaveworks server config for listening connections
@@ -0,0 +1,52 @@
+package fluentforward
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// Metrics holds a set of fluent forward metrics.
+type Metrics struct {
+	reg prometheus.Registerer
+
+	fluentForwardEntries       prometheus.Counter
+	fluentForwardErrors        prometheus.Counter
+	fluentForwardDroppedChunks prometheus.Counter
+	fluentForwardAuthFailures  prometheus.Counter
+}
+
+// NewMetrics creates a new set of fluent forward metrics. If reg is non-nil, the
+// metrics will be registered.
+func NewMetrics(reg prometheus.Registerer) *Metrics {
+	var m Metrics
+	m.reg = reg
+
+	m.fluentForwardEntries = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "promtail",
+		Name:      "fluentforward_target_entries_total",
+		Help:      "Total number of successful entries sent to the fluent forward target",
+	})
+	m.fluentForwardErrors = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "promtail",
+		Name:      "fluentforward_target_parsing_errors_total",
+		Help:      "Total number of parsing errors while receiving fluent forward messages",
+	})
+	m.fluentForwardDroppedChunks = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "promtail",
+		Name:      "fluentforward_target_dropped_chunks_total",
+		Help:      "Total number of invalid fluent forward chunks acknowledged and dropped",
+	})
+	m.fluentForwardAuthFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "promtail",
+		Name:      "fluentforward_target_authentication_failures_total",
+		Help:      "Total number of fluent forward clients which failed to authenticate",
+	})
+
+	if reg != nil {
+		reg.MustRegister(
+			m.fluentForwardEntries,
+			m.fluentForwardErrors,
+			m.fluentForwardDroppedChunks,
+			m.fluentForwardAuthFailures,
+		)
+	}
+
+	return &m
+}
@@ -0,0 +1,267 @@
+package fluentforward
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha512"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"reflect"
+	"time"
+
+	"github.com/ugorji/go/codec"
+)
+
+// The Fluent Forward protocol is described at https://github.com/fluent/fluentd/wiki/Forward-Protocol-Specification-v1.
+
+const (
+	// eventTimeExtType is the msgpack extension type of EventTime, a timestamp with a nanosecond precision.
+	eventTimeExtType = 0
+
+	compressedGzip = "gzip"
+
+	// maxPingSize is the maximum size of the PING message of the authentication, which only holds a few short strings.
+	maxPingSize = 4 << 10
+)
+
+// msgpackHandle decodes str to string, bin to []byte and maps to map[string]interface{}, and encodes []byte as bin.
+var msgpackHandle = func() *codec.MsgpackHandle {
+	h := &codec.MsgpackHandle{WriteExt: true}
+	h.MapType = reflect.TypeOf(map[string]interface{}(nil))
+	return h
+}()
+
+// messageDecoder decodes the messages of a connection. The size of each message is limited, so that a client
+// can't make the target buffer an arbitrarily large msgpack value before it is even decoded.
+type messageDecoder struct {
+	r   *io.LimitedReader
+	dec *codec.Decoder
+}
+
+func newMessageDecoder(r io.Reader) *messageDecoder {
+	lr := &io.LimitedReader{R: r}
+	return &messageDecoder{r: lr, dec: codec.NewDecoder(lr, msgpackHandle)}
+}
+
+// decode decodes the next message, which must be at most maxSize bytes long.
+// The rest of a message larger than maxSize isn't read, so the connection can't be used anymore.
+func (d *messageDecoder) decode(msg *[]interface{}, maxSize int) error {
+	d.r.N = int64(maxSize)
+	err := d.dec.Decode(msg)
+	if err != nil && d.r.N == 0 {
+		return fmt.Errorf("message larger than the limit of %d bytes", maxSize)
+	}
+	return err
+}
+
+// event is a record received with its tag and its time.
+type event struct {
+	tag    string
+	time   time.Time
+	record map[string]interface{}
+}
+
+// decodeMessage returns the events of a message, sent in the Message, Forward, PackedForward or CompressedPackedForward mode,
+// and the chunk ID to acknowledge, if the client requested an acknowledgement. The chunk ID is also returned with
+// the error of a message which has valid options but invalid entries, so that the message can be acknowledged and dropped.
+// The size of the packed entries is limited to maxChunkSize, after decompression.
+func decodeMessage(msg []interface{}, maxChunkSize int) ([]event, string, error) {
+	if len(msg) < 2 {
+		return nil, "", fmt.Errorf("invalid message with %d elements", len(msg))
+	}
+	tag, ok := toString(msg[0])
+	if !ok {
+		return nil, "", fmt.Errorf("invalid tag of type %T", msg[0])
+	}
+
+	var options interface{}
+	switch msg[1].(type) {
+	case []interface{}, string, []byte:
+		// Forward mode: [tag, [[time, record], ...], options]
+		// PackedForward and CompressedPackedForward modes: [tag, msgpack stream of [time, record], options]
+		if len(msg) > 3 {
+			return nil, "", fmt.Errorf("invalid forward mode message with %d elements", len(msg))
+		}
+		if len(msg) == 3 {
+			options = msg[2]
+		}
+	default:
+		// Message mode: [tag, time, record, options]
+		if len(msg) < 3 || len(msg) > 4 {
+			return nil, "", fmt.Errorf("invalid message mode message with %d elements", len(msg))
+		}
+		if len(msg) == 4 {
+			options = msg[3]
+		}
+	}
+	chunk, _ := option(options, "chunk")
+
+	var events []event
+	switch entries := msg[1].(type) {
+	case []interface{}:
+		events = make([]event, 0, len(entries))
+		for _, entry := range entries {
+			e, ok := entry.([]interface{})
+			if !ok {
+				return nil, chunk, fmt.Errorf("invalid entry of type %T", entry)
+			}
+			ev, err := decodeEntry(tag, e)
+			if err != nil {
+				return nil, chunk, err
+			}
+			events = append(events, ev)
+		}
+	case string, []byte:
+		var err error
+		events, err = decodePackedEntries(tag, entries, options, maxChunkSize)
+		if err != nil {
+			return nil, chunk, err
+		}
+	default:
+		ev, err := decodeEntry(tag, msg[1:3])
+		if err != nil {
+			return nil, chunk, err
+		}
+		events = []event{ev}
+	}
+	return events, chunk, nil
+}
+
+// decodePackedEntries decodes the msgpack stream of the entries of PackedForward and CompressedPackedForward messages.
+func decodePackedEntries(tag string, entries interface{}, options interface{}, maxChunkSize int) ([]event, error) {
+	var r io.Reader
+	switch entries := entries.(type) {
+	case string:
+		r = bytes.NewBufferString(entries)
+	case []byte:
+		r = bytes.NewBuffer(entries)
+	}
+
+	if compressed, ok := option(options, "compressed"); ok && compressed != "text" {
+		if compressed != compressedGzip {
+			return nil, fmt.Errorf("unsupported compression %q", compressed)
+		}
+		gr, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read gzip entries: %w", err)
+		}
+		defer gr.Close()
+		r = gr
+	}
+
+	// The limit is exceeded if the byte after the limit can be read.
+	lr := &io.LimitedReader{R: r, N: int64(maxChunkSize) + 1}
+	var events []event
+	dec := codec.NewDecoder(lr, msgpackHandle)
+	for {
+		var entry []interface{}
+		err := dec.Decode(&entry)
+		if lr.N == 0 {
+			return nil, fmt.Errorf("packed entries larger than the chunk size limit of %d bytes", maxChunkSize)
+		}
+		if err == io.EOF {
+			return events, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode packed entries: %w", err)
+		}
+		ev, err := decodeEntry(tag, entry)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, ev)
+	}
+}
+
+// decodeEntry decodes a [time, record] entry.
+func decodeEntry(tag string, entry []interface{}) (event, error) {
+	if len(entry) != 2 {
+		return event{}, fmt.Errorf("invalid entry with %d elements", len(entry))
+	}
+	ts, err := decodeTime(entry[0])
+	if err != nil {
+		return event{}, err
+	}
+	record, ok := entry[1].(map[string]interface{})
+	if !ok {
+		return event{}, fmt.Errorf("invalid record of type %T", entry[1])
+	}
+	return event{tag: tag, time: ts, record: record}, nil
+}
+
+// decodeTime decodes a time sent either as an integer number of seconds or as an EventTime.
+func decodeTime(v interface{}) (time.Time, error) {
+	switch t := v.(type) {
+	case uint64:
+		return time.Unix(int64(t), 0), nil
+	case int64:
+		return time.Unix(t, 0), nil
+	case float64:
+		return time.Unix(0, int64(t*float64(time.Second))), nil
+	case codec.RawExt:
+		// EventTime is the seconds and the nanoseconds since the epoch, as big-endian 32-bit unsigned integers.
+		if t.Tag != eventTimeExtType || len(t.Data) != 8 {
+			return time.Time{}, fmt.Errorf("invalid time extension of type %d", t.Tag)
+		}
+		return time.Unix(int64(binary.BigEndian.Uint32(t.Data[:4])), int64(binary.BigEndian.Uint32(t.Data[4:]))), nil
+	default:
+		return time.Time{}, fmt.Errorf("invalid time of type %T", v)
+	}
+}
+
+// option returns the string value of an option of a message.
+func option(options interface{}, name string) (string, bool) {
+	m, ok := options.(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+	v, ok := m[name]
+	if !ok {
+		return "", false
+	}
+	return toString(v)
+}
+
+// toString returns the value of msgpack strings, which may be sent as str or bin.
+func toString(v interface{}) (string, bool) {
+	switch s := v.(type) {
+	case string:
+		return s, true
+	case []byte:
+		return string(s), true
+	default:
+		return "", false
+	}
+}
+
+// normalizeRecord converts the bin values of a record to strings, so that it can be marshalled to JSON.
+func normalizeRecord(v interface{}) interface{} {
+	switch v := v.(type) {
+	case []byte:
+		return string(v)
+	case map[string]interface{}:
+		for k, e := range v {
+			v[k] = normalizeRecord(e)
+		}
+		return v
+	case []interface{}:
+		for i, e := range v {
+			v[i] = normalizeRecord(e)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// sharedKeyDigest is the digest used by the clients and the server to prove they know the shared key.
+func sharedKeyDigest(salt, hostname string, nonce []byte, sharedKey string) string {
+	h := sha512.New()
+	h.Write([]byte(salt))
+	h.Write([]byte(hostname))
+	h.Write(nonce)
+	h.Write([]byte(sharedKey))
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,376 @@
+package fluentforward
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/backoff"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/relabel"
+	"github.com/ugorji/go/codec"
+
+	"github.com/grafana/loki/clients/pkg/promtail/api"
+	"github.com/grafana/loki/clients/pkg/promtail/scrapeconfig"
+	"github.com/grafana/loki/clients/pkg/promtail/targets/target"
+
+	"github.com/grafana/loki/pkg/logproto"
+	lokiflag "github.com/grafana/loki/pkg/util/flagext"
+)
+
+var (
+	defaultListenAddress = ":24224"
+	defaultIdleTimeout   = 120 * time.Second
+	// defaultChunkSizeLimit is the default size of the buffer chunks of fluentd.
+	defaultChunkSizeLimit = 8 << 20
+
+	invalidLabelCharRegex = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+)
+
+// Target listens to log records sent with the Fluent Forward protocol on tcp.
+type Target struct {
+	metrics       *Metrics
+	logger        log.Logger
+	handler       api.EntryHandler
+	config        *scrapeconfig.FluentForwardTargetConfig
+	relabelConfig []*relabel.Config
+	listener      net.Listener
+	wg            sync.WaitGroup
+
+	ctx       context.Context
+	ctxCancel context.CancelFunc
+}
+
+// NewTarget configures a new Fluent Forward Target.
+func NewTarget(
+	metrics *Metrics,
+	logger log.Logger,
+	handler api.EntryHandler,
+	relabel []*relabel.Config,
+	config *scrapeconfig.FluentForwardTargetConfig,
+) (*Target, error) {
+
+	if config.ListenAddress == "" {
+		config.ListenAddress = defaultListenAddress
+	}
+	if config.IdleTimeout == 0 {
+		config.IdleTimeout = defaultIdleTimeout
+	}
+	if config.ChunkSizeLimit == 0 {
+		config.ChunkSizeLimit = lokiflag.ByteSize(defaultChunkSizeLimit)
+	}
+	if config.SelfHostname == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the hostname of the fluent forward target: %w", err)
+		}
+		config.SelfHostname = hostname
+	}
+
+	l, err := net.Listen("tcp", config.ListenAddress)
+	if err != nil {
+		return nil, fmt.Errorf("error setting up fluent forward target: %w", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	t := &Target{
+		metrics:       metrics,
+		logger:        logger,
+		handler:       handler,
+		config:        config,
+		relabelConfig: relabel,
+		listener:      l,
+
+		ctx:       ctx,
+		ctxCancel: cancel,
+	}
+
+	t.run()
+	return t, nil
+}
+
+func (t *Target) run() {
+	level.Info(t.logger).Log("msg", "listening for fluent forward messages", "listen_address", t.listener.Addr().String(), "authentication", t.config.SharedKey.String() != "")
+
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+
+		backoff := backoff.New(t.ctx, backoff.Config{
+			MinBackoff: 5 * time.Millisecond,
+			MaxBackoff: 1 * time.Second,
+		})
+		for {
+			c, err := t.listener.Accept()
+			if err != nil {
+				if t.ctx.Err() != nil {
+					level.Info(t.logger).Log("msg", "fluent forward listener shutdown", "listen_address", t.config.ListenAddress)
+					return
+				}
+				var ne net.Error
+				if errors.As(err, &ne) {
+					level.Warn(t.logger).Log("msg", "failed to accept fluent forward connection", "err", err, "num_retries", backoff.NumRetries())
+					backoff.Wait()
+					continue
+				}
+				level.Error(t.logger).Log("msg", "failed to accept fluent forward connection. quiting", "err", err)
+				return
+			}
+			backoff.Reset()
+
+			t.wg.Add(1)
+			go t.handleConnection(c)
+		}
+	}()
+}
+
+func (t *Target) handleConnection(cn net.Conn) {
+	defer t.wg.Done()
+
+	c := &idleTimeoutConn{cn, t.config.IdleTimeout}
+
+	ctx, cancel := context.WithCancel(t.ctx)
+	defer cancel()
+	go func() {
+		<-ctx.Done()
+		_ = c.Close()
+	}()
+
+	logger := log.With(t.logger, "remote_addr", c.RemoteAddr().String())
+	dec := newMessageDecoder(bufio.NewReader(c))
+	enc := codec.NewEncoder(c, msgpackHandle)
+
+	if t.config.SharedKey.String() != "" {
+		if err := t.authenticate(dec, enc); err != nil {
+			level.Warn(logger).Log("msg", "fluent forward client authentication failed", "err", err)
+			t.metrics.fluentForwardAuthFailures.Inc()
+			return
+		}
+	}
+
+	connLabels := t.connectionLabels(c)
+	for {
+		var msg []interface{}
+		if err := dec.decode(&msg, t.config.ChunkSizeLimit.Val()); err != nil {
+			t.handleConnectionError(logger, err)
+			return
+		}
+
+		events, chunk, err := decodeMessage(msg, t.config.ChunkSizeLimit.Val())
+		if err != nil {
+			level.Warn(logger).Log("msg", "error decoding fluent forward message", "chunk", chunk, "err", err)
+			t.metrics.fluentForwardErrors.Inc()
+			// Acknowledging the invalid chunk drops it, otherwise the client would send it again after its ack timeout.
+			if chunk == "" {
+				continue
+			}
+			t.metrics.fluentForwardDroppedChunks.Inc()
+		}
+		for _, e := range events {
+			t.handleEvent(connLabels, e)
+		}
+
+		if chunk != "" {
+			if err := enc.Encode(map[string]interface{}{"ack": chunk}); err != nil {
+				t.handleConnectionError(logger, err)
+				return
+			}
+		}
+	}
+}
+
+// handleConnectionError logs the error that ended a connection, ignoring closed and timed out connections.
+func (t *Target) handleConnectionError(logger log.Logger, err error) {
+	var ne net.Error
+	switch {
+	case errors.Is(err, io.EOF), t.ctx.Err() != nil:
+		return
+	case errors.As(err, &ne) && ne.Timeout():
+		level.Debug(logger).Log("msg", "connection timed out", "err", ne)
+	default:
+		level.Warn(logger).Log("msg", "error reading fluent forward stream", "err", err)
+		t.metrics.fluentForwardErrors.Inc()
+	}
+}
+
+// authenticate runs the shared key handshake: the target sends a HELO with a nonce, the client replies with a PING
+// with its digest of the shared key and the nonce, and the target sends a PONG with its own digest on success.
+// User authentication isn't supported, so the HELO has no salt for the user passwords.
+func (t *Target) authenticate(dec *messageDecoder, enc *codec.Encoder) error {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to generate the nonce: %w", err)
+	}
+	helo := []interface{}{"HELO", map[string]interface{}{"nonce": nonce, "auth": []byte{}, "keepalive": true}}
+	if err := enc.Encode(helo); err != nil {
+		return fmt.Errorf("failed to send HELO: %w", err)
+	}
+
+	// PING: ["PING", client hostname, shared key salt, shared key digest, username, password]
+	var msg []interface{}
+	if err := dec.decode(&msg, maxPingSize); err != nil {
+		return fmt.Errorf("failed to read PING: %w", err)
+	}
+	ping := make([]string, len(msg))
+	for i, v := range msg {
+		s, ok := toString(v)
+		if !ok {
+			return fmt.Errorf("invalid PING element of type %T", v)
+		}
+		ping[i] = s
+	}
+	if len(ping) != 6 || ping[0] != "PING" {
+		return errors.New("invalid PING")
+	}
+
+	hostname, salt, digest := ping[1], ping[2], ping[3]
+	sharedKey := t.config.SharedKey.String()
+	if subtle.ConstantTimeCompare([]byte(digest), []byte(sharedKeyDigest(salt, hostname, nonce, sharedKey))) != 1 {
+		_ = enc.Encode([]interface{}{"PONG", false, "shared_key mismatch", "", ""})
+		return fmt.Errorf("shared key mismatch for client %q", hostname)
+	}
+	pong := []interface{}{"PONG", true, "", t.config.SelfHostname, sharedKeyDigest(salt, t.config.SelfHostname, nonce, sharedKey)}
+	if err := enc.Encode(pong); err != nil {
+		return fmt.Errorf("failed to send PONG: %w", err)
+	}
+	return nil
+}
+
+func (t *Target) connectionLabels(c net.Conn) labels.Labels {
+	lb := labels.NewBuilder(nil)
+	for k, v := range t.config.Labels {
+		lb.Set(string(k), string(v))
+	}
+	if addr, ok := c.RemoteAddr().(*net.TCPAddr); ok {
+		lb.Set("__fluentforward_connection_ip_address", addr.IP.String())
+	}
+	return lb.Labels(nil)
+}
+
+func (t *Target) handleEvent(connLabels labels.Labels, e event) {
+	lb := labels.NewBuilder(connLabels)
+	lb.Set("__fluentforward_tag", e.tag)
+	record := normalizeRecord(e.record)
+	for k, v := range record.(map[string]interface{}) {
+		// Only the fields with a scalar value are available for relabeling.
+		var value string
+		switch v := v.(type) {
+		case string:
+			value = v
+		case bool:
+			value = strconv.FormatBool(v)
+		case int64:
+			value = strconv.FormatInt(v, 10)
+		case uint64:
+			value = strconv.FormatUint(v, 10)
+		case float64:
+			value = strconv.FormatFloat(v, 'f', -1, 64)
+		default:
+			continue
+		}
+		lb.Set("__fluentforward_record_"+invalidLabelCharRegex.ReplaceAllString(k, "_"), value)
+	}
+
+	processed := relabel.Process(lb.Labels(nil), t.relabelConfig...)
+
+	filtered := make(model.LabelSet)
+	for _, lbl := range processed {
+		if strings.HasPrefix(lbl.Name, "__") {
+			continue
+		}
+		filtered[model.LabelName(lbl.Name)] = model.LabelValue(lbl.Value)
+	}
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		level.Error(t.logger).Log("msg", "error while marshalling fluent forward record", "tag", e.tag, "err", err)
+		t.metrics.fluentForwardErrors.Inc()
+		return
+	}
+
+	timestamp := time.Now()
+	if t.config.UseIncomingTimestamp {
+		timestamp = e.time
+	}
+
+	t.handler.Chan() <- api.Entry{
+		Labels: filtered,
+		Entry: logproto.Entry{
+			Timestamp: timestamp,
+			Line:      string(line),
+		},
+	}
+	t.metrics.fluentForwardEntries.Inc()
+}
+
+// Type returns FluentForwardTargetType.
+func (t *Target) Type() target.TargetType {
+	return target.FluentForwardTargetType
+}
+
+// Ready indicates whether or not the fluent forward target is ready to be read from.
+func (t *Target) Ready() bool {
+	return t.ctx.Err() == nil
+}
+
+// DiscoveredLabels returns the set of labels discovered by the fluent forward target, which
+// is always nil. Implements Target.
+func (t *Target) DiscoveredLabels() model.LabelSet {
+	return nil
+}
+
+// Labels returns the set of labels that statically apply to all log entries
+// produced by the fluent forward target.
+func (t *Target) Labels() model.LabelSet {
+	return t.config.Labels
+}
+
+// Details returns target-specific details.
+func (t *Target) Details() interface{} {
+	return map[string]string{}
+}
+
+// Stop shuts down the fluent forward target.
+func (t *Target) Stop() {
+	level.Info(t.logger).Log("msg", "Shutting down fluent forward listener", "listen_address", t.config.ListenAddress)
+	t.ctxCancel()
+	if err := t.listener.Close(); err != nil {
+		level.Error(t.logger).Log("msg", "error while closing fluent forward listener", "err", err)
+	}
+	t.wg.Wait()
+	t.handler.Stop()
+}
+
+type idleTimeoutConn struct {
+	net.Conn
+	idleTimeout time.Duration
+}
+
+func (c *idleTimeoutConn) Write(p []byte) (int, error) {
+	c.setDeadline()
+	return c.Conn.Write(p)
+}
+
+func (c *idleTimeoutConn) Read(b []byte) (int, error) {
+	c.setDeadline()
+	return c.Conn.Read(b)
+}
+
+func (c *idleTimeoutConn) setDeadline() {
+	_ = c.Conn.SetDeadline(time.Now().Add(c.idleTimeout))
+}
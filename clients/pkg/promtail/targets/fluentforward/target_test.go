@@ -0,0 +1,243 @@
+package fluentforward
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/flagext"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/relabel"
+	"github.com/stretchr/testify/require"
+	"github.com/ugorji/go/codec"
+
+	"github.com/grafana/loki/clients/pkg/promtail/client/fake"
+	"github.com/grafana/loki/clients/pkg/promtail/scrapeconfig"
+)
+
+func Test_FluentForward(t *testing.T) {
+	client := fake.New(func() {})
+	metrics := NewMetrics(nil)
+	tm, err := NewTargetManager(metrics, log.NewNopLogger(), client, []scrapeconfig.Config{
+		{
+			JobName: "fluentforward",
+			FluentForwardConfig: &scrapeconfig.FluentForwardTargetConfig{
+				ListenAddress:        "127.0.0.1:0",
+				SharedKey:            flagext.SecretWithValue("secret"),
+				SelfHostname:         "promtail",
+				ChunkSizeLimit:       1024,
+				UseIncomingTimestamp: true,
+				Labels:               model.LabelSet{"cfg": "true"},
+			},
+			RelabelConfigs: []*relabel.Config{
+				{
+					SourceLabels: model.LabelNames{"__fluentforward_tag"},
+					TargetLabel:  "tag",
+					Replacement:  "$1",
+					Action:       relabel.Replace,
+					Regex:        relabel.MustNewRegexp("(.*)"),
+				},
+				{
+					SourceLabels: model.LabelNames{"__fluentforward_record_kubernetes_namespace"},
+					TargetLabel:  "namespace",
+					Replacement:  "$1",
+					Action:       relabel.Replace,
+					Regex:        relabel.MustNewRegexp("(.*)"),
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	defer tm.Stop()
+
+	target := tm.targets["fluentforward"]
+	require.NotNil(t, target)
+	c := newTestClient(t, target.listener.Addr().String())
+	defer c.Close()
+	require.Equal(t, "promtail", c.authenticate(t, "secret"))
+
+	record := func(i int) map[string]interface{} {
+		return map[string]interface{}{"log": "line " + string(rune('0'+i)), "kubernetes.namespace": []byte("default")}
+	}
+	baseTs := time.Unix(10, 250)
+
+	// Message mode.
+	c.send(t, []interface{}{"app.0", testEventTime(baseTs), record(0)})
+	// Forward mode, with an integer time.
+	c.send(t, []interface{}{"app.1", []interface{}{[]interface{}{uint64(baseTs.Add(time.Second).Unix()), record(1)}}})
+	// Invalid messages are skipped.
+	c.send(t, []interface{}{"app.invalid", uint64(0)})
+	// PackedForward mode.
+	c.send(t, []interface{}{"app.2", c.pack(t, baseTs.Add(2*time.Second), record(2)), map[string]interface{}{"size": 1}})
+	// CompressedPackedForward mode, with an acknowledgement.
+	var compressed bytes.Buffer
+	gw := gzip.NewWriter(&compressed)
+	_, err = gw.Write(c.pack(t, baseTs.Add(3*time.Second), record(3)))
+	require.NoError(t, err)
+	require.NoError(t, gw.Close())
+	c.send(t, []interface{}{"app.3", compressed.Bytes(), map[string]interface{}{"compressed": "gzip", "chunk": "chunk-3"}})
+	var ack map[string]interface{}
+	require.NoError(t, c.dec.Decode(&ack))
+	require.Equal(t, map[string]interface{}{"ack": "chunk-3"}, ack)
+	// Chunks larger than the limit once decompressed are acknowledged and dropped.
+	compressed.Reset()
+	gw = gzip.NewWriter(&compressed)
+	_, err = gw.Write(c.pack(t, baseTs, map[string]interface{}{"log": strings.Repeat("a", 2048)}))
+	require.NoError(t, err)
+	require.NoError(t, gw.Close())
+	c.send(t, []interface{}{"app.large", compressed.Bytes(), map[string]interface{}{"compressed": "gzip", "chunk": "chunk-large"}})
+	require.NoError(t, c.dec.Decode(&ack))
+	require.Equal(t, map[string]interface{}{"ack": "chunk-large"}, ack)
+
+	require.Eventually(t, func() bool {
+		return len(client.Received()) == 4
+	}, 5*time.Second, 20*time.Millisecond)
+
+	for i, actual := range client.Received() {
+		require.Equal(t, model.LabelSet{
+			"cfg":       "true",
+			"tag":       model.LabelValue("app." + string(rune('0'+i))),
+			"namespace": "default",
+		}, actual.Labels)
+		expectedTs := baseTs.Add(time.Duration(i) * time.Second)
+		if i == 1 {
+			// Integer times have a second precision.
+			expectedTs = expectedTs.Truncate(time.Second)
+		}
+		require.Equal(t, expectedTs, actual.Timestamp)
+		require.JSONEq(t, `{"log":"line `+string(rune('0'+i))+`","kubernetes.namespace":"default"}`, actual.Line)
+	}
+	require.Equal(t, 4.0, testutil.ToFloat64(metrics.fluentForwardEntries))
+	require.Equal(t, 2.0, testutil.ToFloat64(metrics.fluentForwardErrors))
+	require.Equal(t, 1.0, testutil.ToFloat64(metrics.fluentForwardDroppedChunks))
+
+	// Messages larger than the limit close the connection before they are decoded.
+	c.send(t, []interface{}{"app.large", []interface{}{[]interface{}{uint64(baseTs.Unix()), map[string]interface{}{"log": strings.Repeat("a", 2048)}}}})
+	require.Error(t, c.dec.Decode(&ack))
+	require.Equal(t, 4.0, testutil.ToFloat64(metrics.fluentForwardEntries))
+	require.Equal(t, 3.0, testutil.ToFloat64(metrics.fluentForwardErrors))
+}
+
+func Test_FluentForwardAuthenticationFailure(t *testing.T) {
+	client := fake.New(func() {})
+	metrics := NewMetrics(nil)
+	tm, err := NewTargetManager(metrics, log.NewNopLogger(), client, []scrapeconfig.Config{
+		{
+			JobName: "fluentforward",
+			FluentForwardConfig: &scrapeconfig.FluentForwardTargetConfig{
+				ListenAddress: "127.0.0.1:0",
+				SharedKey:     flagext.SecretWithValue("secret"),
+			},
+		},
+	})
+	require.NoError(t, err)
+	defer tm.Stop()
+
+	c := newTestClient(t, tm.targets["fluentforward"].listener.Addr().String())
+	defer c.Close()
+	c.readHelo(t)
+	c.send(t, []interface{}{"PING", "client", "salt", "invalid", "", ""})
+	var pong []interface{}
+	require.NoError(t, c.dec.Decode(&pong))
+	require.Equal(t, []interface{}{"PONG", false, "shared_key mismatch", "", ""}, pong)
+
+	// The connection is closed.
+	require.Error(t, c.dec.Decode(&pong))
+	require.Equal(t, 1.0, testutil.ToFloat64(metrics.fluentForwardAuthFailures))
+
+	// The size of the PING is limited.
+	c = newTestClient(t, tm.targets["fluentforward"].listener.Addr().String())
+	defer c.Close()
+	c.readHelo(t)
+	c.send(t, []interface{}{"PING", strings.Repeat("a", maxPingSize), "salt", "invalid", "", ""})
+	require.Error(t, c.dec.Decode(&pong))
+	require.Equal(t, 2.0, testutil.ToFloat64(metrics.fluentForwardAuthFailures))
+}
+
+// testEventTime is an EventTime, encoded as a msgpack extension.
+type testEventTime time.Time
+
+type testEventTimeExt struct{}
+
+func (testEventTimeExt) WriteExt(v interface{}) []byte {
+	var t time.Time
+	switch v := v.(type) {
+	case testEventTime:
+		t = time.Time(v)
+	case *testEventTime:
+		t = time.Time(*v)
+	}
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint32(b, uint32(t.Unix()))
+	binary.BigEndian.PutUint32(b[4:], uint32(t.Nanosecond()))
+	return b
+}
+
+func (testEventTimeExt) ReadExt(interface{}, []byte) {
+	panic("not implemented")
+}
+
+type testClient struct {
+	net.Conn
+	handle *codec.MsgpackHandle
+	dec    *codec.Decoder
+	enc    *codec.Encoder
+	nonce  []byte
+}
+
+func newTestClient(t *testing.T, addr string) *testClient {
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	require.NoError(t, conn.SetDeadline(time.Now().Add(5*time.Second)))
+
+	h := &codec.MsgpackHandle{WriteExt: true}
+	h.MapType = reflect.TypeOf(map[string]interface{}(nil))
+	require.NoError(t, h.SetBytesExt(reflect.TypeOf(testEventTime{}), eventTimeExtType, testEventTimeExt{}))
+	return &testClient{
+		Conn:   conn,
+		handle: h,
+		dec:    codec.NewDecoder(conn, h),
+		enc:    codec.NewEncoder(conn, h),
+	}
+}
+
+func (c *testClient) readHelo(t *testing.T) {
+	var helo []interface{}
+	require.NoError(t, c.dec.Decode(&helo))
+	require.Len(t, helo, 2)
+	require.Equal(t, "HELO", helo[0])
+	c.nonce = helo[1].(map[string]interface{})["nonce"].([]byte)
+	require.Len(t, c.nonce, 16)
+}
+
+// authenticate authenticates the client and returns the hostname of the server.
+func (c *testClient) authenticate(t *testing.T, sharedKey string) string {
+	c.readHelo(t)
+	c.send(t, []interface{}{"PING", "client", "salt", sharedKeyDigest("salt", "client", c.nonce, sharedKey), "", ""})
+
+	var pong []interface{}
+	require.NoError(t, c.dec.Decode(&pong))
+	require.Len(t, pong, 5)
+	require.Equal(t, []interface{}{"PONG", true, ""}, pong[:3])
+	hostname := pong[3].(string)
+	require.Equal(t, sharedKeyDigest("salt", hostname, c.nonce, sharedKey), pong[4])
+	return hostname
+}
+
+func (c *testClient) send(t *testing.T, msg []interface{}) {
+	require.NoError(t, c.enc.Encode(msg))
+}
+
+// pack encodes an entry of PackedForward messages.
+func (c *testClient) pack(t *testing.T, ts time.Time, record map[string]interface{}) []byte {
+	var b []byte
+	require.NoError(t, codec.NewEncoderBytes(&b, c.handle).Encode([]interface{}{testEventTime(ts), record}))
+	return b
+}
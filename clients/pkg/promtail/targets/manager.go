@@ -14,12 +14,14 @@ import (
 	"github.com/grafana/loki/clients/pkg/promtail/targets/cloudflare"
 	"github.com/grafana/loki/clients/pkg/promtail/targets/docker"
 	"github.com/grafana/loki/clients/pkg/promtail/targets/file"
+	"github.com/grafana/loki/clients/pkg/promtail/targets/fluentforward"
 	"github.com/grafana/loki/clients/pkg/promtail/targets/gcplog"
 	"github.com/grafana/loki/clients/pkg/promtail/targets/gelf"
 	"github.com/grafana/loki/clients/pkg/promtail/targets/heroku"
 	"github.com/grafana/loki/clients/pkg/promtail/targets/journal"
 	"github.com/grafana/loki/clients/pkg/promtail/targets/kafka"
 	"github.com/grafana/loki/clients/pkg/promtail/targets/lokipush"
+	"github.com/grafana/loki/clients/pkg/promtail/targets/otlp"
 	"github.com/grafana/loki/clients/pkg/promtail/targets/stdin"
 	"github.com/grafana/loki/clients/pkg/promtail/targets/syslog"
 	"github.com/grafana/loki/clients/pkg/promtail/targets/target"
@@ -39,17 +41,21 @@ const (
 	DockerConfigs        = "dockerConfigs"
 	DockerSDConfigs      = "dockerSDConfigs"
 	HerokuDrainConfigs   = "herokuDrainConfigs"
+	FluentForwardConfigs = "fluentForwardConfigs"
+	OTLPConfigs          = "otlpConfigs"
 )
 
 var (
-	fileMetrics        *file.Metrics
-	syslogMetrics      *syslog.Metrics
-	gcplogMetrics      *gcplog.Metrics
-	gelfMetrics        *gelf.Metrics
-	cloudflareMetrics  *cloudflare.Metrics
-	dockerMetrics      *docker.Metrics
-	journalMetrics     *journal.Metrics
-	herokuDrainMetrics *heroku.Metrics
+	fileMetrics          *file.Metrics
+	syslogMetrics        *syslog.Metrics
+	gcplogMetrics        *gcplog.Metrics
+	gelfMetrics          *gelf.Metrics
+	cloudflareMetrics    *cloudflare.Metrics
+	dockerMetrics        *docker.Metrics
+	journalMetrics       *journal.Metrics
+	herokuDrainMetrics   *heroku.Metrics
+	fluentForwardMetrics *fluentforward.Metrics
+	otlpMetrics          *otlp.Metrics
 )
 
 type targetManager interface {
@@ -111,6 +117,10 @@ func NewTargetManagers(
 			targetScrapeConfigs[DockerSDConfigs] = append(targetScrapeConfigs[DockerSDConfigs], cfg)
 		case cfg.HerokuDrainConfig != nil:
 			targetScrapeConfigs[HerokuDrainConfigs] = append(targetScrapeConfigs[HerokuDrainConfigs], cfg)
+		case cfg.FluentForwardConfig != nil:
+			targetScrapeConfigs[FluentForwardConfigs] = append(targetScrapeConfigs[FluentForwardConfigs], cfg)
+		case cfg.OTLPConfig != nil:
+			targetScrapeConfigs[OTLPConfigs] = append(targetScrapeConfigs[OTLPConfigs], cfg)
 		default:
 			return nil, fmt.Errorf("no valid target scrape config defined for %q", cfg.JobName)
 		}
@@ -154,6 +164,12 @@ func NewTargetManagers(
 	if len(targetScrapeConfigs[HerokuDrainConfigs]) > 0 && herokuDrainMetrics == nil {
 		herokuDrainMetrics = heroku.NewMetrics(reg)
 	}
+	if len(targetScrapeConfigs[FluentForwardConfigs]) > 0 && fluentForwardMetrics == nil {
+		fluentForwardMetrics = fluentforward.NewMetrics(reg)
+	}
+	if len(targetScrapeConfigs[OTLPConfigs]) > 0 && otlpMetrics == nil {
+		otlpMetrics = otlp.NewMetrics(reg)
+	}
 
 	for target, scrapeConfigs := range targetScrapeConfigs {
 		switch target {
@@ -229,6 +245,18 @@ func NewTargetManagers(
 				return nil, errors.Wrap(err, "failed to make Heroku drain target manager")
 			}
 			targetManagers = append(targetManagers, herokuDrainTargetManager)
+		case FluentForwardConfigs:
+			fluentForwardTargetManager, err := fluentforward.NewTargetManager(fluentForwardMetrics, logger, client, scrapeConfigs)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to make fluent forward target manager")
+			}
+			targetManagers = append(targetManagers, fluentForwardTargetManager)
+		case OTLPConfigs:
+			otlpTargetManager, err := otlp.NewTargetManager(otlpMetrics, reg, logger, client, scrapeConfigs)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to make otlp target manager")
+			}
+			targetManagers = append(targetManagers, otlpTargetManager)
 		case WindowsEventsConfigs:
 			windowsTargetManager, err := windows.NewTargetManager(reg, logger, client, scrapeConfigs)
 			if err != nil {
@@ -0,0 +1,27 @@
+package otlp
+
+import "github.com/prometheus/client_golang/prometheus"
+
+type Metrics struct {
+	otlpEntries *prometheus.CounterVec
+	otlpErrors  *prometheus.CounterVec
+}
+
+func NewMetrics(reg prometheus.Registerer) *Metrics {
+	var m Metrics
+
+	m.otlpEntries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "promtail",
+		Name:      "otlp_target_entries_total",
+		Help:      "Number of successful entries received by the OTLP target",
+	}, []string{})
+
+	m.otlpErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "promtail",
+		Name:      "otlp_target_parsing_errors_total",
+		Help:      "Number of parsing errors while receiving OTLP requests",
+	}, []string{})
+
+	reg.MustRegister(m.otlpEntries, m.otlpErrors)
+	return &m
+}
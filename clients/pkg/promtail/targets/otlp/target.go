@@ -0,0 +1,310 @@
+package otlp
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/relabel"
+	"github.com/weaveworks/common/logging"
+	"github.com/weaveworks/common/server"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
+
+	// Register the gzip compressor, used by default by many OTLP/gRPC exporters.
+	_ "google.golang.org/grpc/encoding/gzip"
+
+	"github.com/grafana/loki/clients/pkg/promtail/api"
+	"github.com/grafana/loki/clients/pkg/promtail/scrapeconfig"
+	"github.com/grafana/loki/clients/pkg/promtail/targets/serverutils"
+	"github.com/grafana/loki/clients/pkg/promtail/targets/target"
+
+	"github.com/grafana/loki/pkg/loghttp/push"
+	"github.com/grafana/loki/pkg/logproto"
+	util_log "github.com/grafana/loki/pkg/util/log"
+)
+
+const (
+	contentTypeProtobuf = "application/x-protobuf"
+	contentTypeJSON     = "application/json"
+)
+
+// Target receives logs exported with the OpenTelemetry protocol, over gRPC and HTTP.
+type Target struct {
+	logger         log.Logger
+	handler        api.EntryHandler
+	config         *scrapeconfig.OTLPTargetConfig
+	jobName        string
+	server         *server.Server
+	metrics        *Metrics
+	relabelConfigs []*relabel.Config
+}
+
+// NewTarget creates a new OTLP target, exposing the OTLP logs service on the gRPC server and the `/v1/logs`
+// endpoint on the HTTP server.
+func NewTarget(metrics *Metrics, logger log.Logger, handler api.EntryHandler, jobName string, config *scrapeconfig.OTLPTargetConfig, relabel []*relabel.Config) (*Target, error) {
+	wrappedLogger := log.With(logger, "component", "otlp")
+
+	t := &Target{
+		metrics:        metrics,
+		logger:         wrappedLogger,
+		handler:        handler,
+		jobName:        jobName,
+		config:         config,
+		relabelConfigs: relabel,
+	}
+
+	mergedServerConfigs, err := serverutils.MergeWithDefaults(config.Server)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse configs and override defaults when configuring otlp target: %w", err)
+	}
+	// Set the config to the new combined config.
+	config.Server = mergedServerConfigs
+
+	err = t.run()
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (t *Target) run() error {
+	level.Info(t.logger).Log("msg", "starting otlp target", "job", t.jobName)
+
+	// To prevent metric collisions because all metrics are going to be registered in the global Prometheus registry.
+	tentativeServerMetricNamespace := "promtail_otlp_target_" + t.jobName
+	if !model.IsValidMetricName(model.LabelValue(tentativeServerMetricNamespace)) {
+		return fmt.Errorf("invalid prometheus-compatible job name: %s", t.jobName)
+	}
+	t.config.Server.MetricsNamespace = tentativeServerMetricNamespace
+
+	// We don't want the /debug and /metrics endpoints running, since this is not the main promtail HTTP server.
+	t.config.Server.RegisterInstrumentation = false
+
+	// Wrapping util logger with component-specific key vals, and the expected GoKit logging interface
+	t.config.Server.Log = logging.GoKit(log.With(util_log.Logger, "component", "otlp"))
+
+	srv, err := server.New(t.config.Server)
+	if err != nil {
+		return err
+	}
+
+	t.server = srv
+	plogotlp.RegisterGRPCServer(t.server.GRPC, t)
+	t.server.HTTP.Path("/v1/logs").Methods("POST").Handler(http.HandlerFunc(t.handleHTTP))
+
+	go func() {
+		err := srv.Run()
+		if err != nil {
+			level.Error(t.logger).Log("msg", "otlp target shutdown with error", "err", err)
+		}
+	}()
+
+	return nil
+}
+
+// Export implements plogotlp.GRPCServer.
+func (t *Target) Export(_ context.Context, req plogotlp.ExportRequest) (plogotlp.ExportResponse, error) {
+	t.handleLogs(req.Logs())
+	return plogotlp.NewExportResponse(), nil
+}
+
+// handleHTTP handles OTLP/HTTP requests, encoded in JSON or otherwise in protobuf.
+func (t *Target) handleHTTP(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	contentType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		t.handleHTTPError(w, err)
+		return
+	}
+
+	// Requests are limited to the size of the messages accepted by the gRPC server, before and after decompression.
+	maxSize := t.config.Server.GPRCServerMaxRecvMsgSize
+	body := io.Reader(http.MaxBytesReader(w, r.Body, int64(maxSize)))
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		gr, err := gzip.NewReader(body)
+		if err != nil {
+			t.handleHTTPError(w, err)
+			return
+		}
+		defer gr.Close()
+		body = gr
+	}
+	logs, err := push.DecodeOTLPLogs(body, contentType, maxSize)
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) || errors.Is(err, push.ErrOTLPRequestTooLarge) {
+		t.metrics.otlpErrors.WithLabelValues().Inc()
+		level.Warn(t.logger).Log("msg", "otlp request body too large", "max_size", maxSize)
+		http.Error(w, fmt.Sprintf("request body larger than max (%d bytes)", maxSize), http.StatusRequestEntityTooLarge)
+		return
+	}
+	if err != nil {
+		t.handleHTTPError(w, err)
+		return
+	}
+
+	t.handleLogs(logs)
+
+	var data []byte
+	resp := plogotlp.NewExportResponse()
+	if contentType == contentTypeJSON {
+		data, err = resp.MarshalJSON()
+	} else {
+		contentType = contentTypeProtobuf
+		data, err = resp.MarshalProto()
+	}
+	if err != nil {
+		level.Error(t.logger).Log("msg", "failed to marshal otlp response", "err", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(data)
+}
+
+func (t *Target) handleHTTPError(w http.ResponseWriter, err error) {
+	t.metrics.otlpErrors.WithLabelValues().Inc()
+	level.Warn(t.logger).Log("msg", "failed to read incoming otlp request", "err", err.Error())
+	http.Error(w, err.Error(), http.StatusBadRequest)
+}
+
+func (t *Target) handleLogs(logs plog.Logs) {
+	entries := t.handler.Chan()
+	rls := logs.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		lb := labels.NewBuilder(nil)
+		setAttributeLabels(lb, "__otlp_resource_attribute_", rl.Resource().Attributes())
+		resourceLabels := lb.Labels(nil)
+
+		sls := rl.ScopeLogs()
+		for j := 0; j < sls.Len(); j++ {
+			sl := sls.At(j)
+			lb := labels.NewBuilder(resourceLabels)
+			if name := sl.Scope().Name(); name != "" {
+				lb.Set("__otlp_scope_name", name)
+			}
+			if version := sl.Scope().Version(); version != "" {
+				lb.Set("__otlp_scope_version", version)
+			}
+			scopeLabels := lb.Labels(nil)
+
+			records := sl.LogRecords()
+			for k := 0; k < records.Len(); k++ {
+				record := records.At(k)
+				filtered, ok := t.recordLabels(scopeLabels, record)
+				if !ok {
+					continue
+				}
+
+				ts := time.Now()
+				if t.config.UseIncomingTimestamp {
+					ts = push.OTLPTimestamp(record, ts)
+				}
+
+				entries <- api.Entry{
+					Labels: filtered,
+					Entry: logproto.Entry{
+						Timestamp: ts,
+						Line:      record.Body().AsString(),
+					},
+				}
+				t.metrics.otlpEntries.WithLabelValues().Inc()
+			}
+		}
+	}
+}
+
+// recordLabels returns the labels of a log record after relabeling, and false if the record is dropped.
+func (t *Target) recordLabels(scopeLabels labels.Labels, record plog.LogRecord) (model.LabelSet, bool) {
+	lb := labels.NewBuilder(scopeLabels)
+	// Start with the set of labels fixed in the configuration
+	for k, v := range t.config.Labels {
+		lb.Set(string(k), string(v))
+	}
+	setAttributeLabels(lb, "__otlp_log_attribute_", record.Attributes())
+	if text := record.SeverityText(); text != "" {
+		lb.Set("__otlp_severity_text", text)
+	}
+	if number := record.SeverityNumber(); number != plog.SeverityNumberUnspecified {
+		lb.Set("__otlp_severity_number", strconv.Itoa(int(number)))
+	}
+	if traceID := record.TraceID(); !traceID.IsEmpty() {
+		lb.Set("__otlp_trace_id", traceID.HexString())
+	}
+	if spanID := record.SpanID(); !spanID.IsEmpty() {
+		lb.Set("__otlp_span_id", spanID.HexString())
+	}
+
+	processed := relabel.Process(lb.Labels(nil), t.relabelConfigs...)
+	if len(processed) == 0 {
+		return nil, false
+	}
+
+	filtered := make(model.LabelSet)
+	for _, lbl := range processed {
+		if strings.HasPrefix(lbl.Name, "__") {
+			continue
+		}
+		filtered[model.LabelName(lbl.Name)] = model.LabelValue(lbl.Value)
+	}
+	return filtered, true
+}
+
+// setAttributeLabels sets a label for each attribute, with the attribute name sanitized the same way as in Loki and prefixed.
+func setAttributeLabels(lb *labels.Builder, prefix string, attributes pcommon.Map) {
+	attributes.Range(func(k string, v pcommon.Value) bool {
+		lb.Set(prefix+push.OTLPAttributeName(k), v.AsString())
+		return true
+	})
+}
+
+// Type returns OTLPTargetType.
+func (t *Target) Type() target.TargetType {
+	return target.OTLPTargetType
+}
+
+// DiscoveredLabels returns the set of labels discovered by the OTLP target, which
+// is always nil. Implements Target.
+func (t *Target) DiscoveredLabels() model.LabelSet {
+	return nil
+}
+
+// Labels returns the set of labels that statically apply to all log entries
+// produced by the OTLP target.
+func (t *Target) Labels() model.LabelSet {
+	return t.config.Labels
+}
+
+// Ready indicates whether or not the OTLP target is ready to be read from.
+func (t *Target) Ready() bool {
+	return true
+}
+
+// Details returns target-specific details.
+func (t *Target) Details() interface{} {
+	return map[string]string{}
+}
+
+// Stop shuts down the OTLP target.
+func (t *Target) Stop() error {
+	level.Info(t.logger).Log("msg", "stopping otlp target", "job", t.jobName)
+	t.server.Shutdown()
+	t.handler.Stop()
+	return nil
+}
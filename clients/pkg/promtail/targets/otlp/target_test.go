@@ -0,0 +1,233 @@
+package otlp
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"flag"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/relabel"
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/server"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/grafana/loki/clients/pkg/promtail/client/fake"
+	"github.com/grafana/loki/clients/pkg/promtail/scrapeconfig"
+)
+
+const localhost = "127.0.0.1"
+
+var testTimestamp = time.Unix(1670000000, 123456789)
+
+func TestOTLPTarget(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		export func(t *testing.T, target *Target, logs plog.Logs)
+	}{
+		{
+			name: "grpc",
+			export: func(t *testing.T, target *Target, logs plog.Logs) {
+				conn, err := grpc.Dial(target.server.GRPCListenAddr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+				require.NoError(t, err)
+				defer conn.Close()
+				_, err = plogotlp.NewGRPCClient(conn).Export(context.Background(), plogotlp.NewExportRequestFromLogs(logs))
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "http protobuf",
+			export: func(t *testing.T, target *Target, logs plog.Logs) {
+				body, err := plogotlp.NewExportRequestFromLogs(logs).MarshalProto()
+				require.NoError(t, err)
+				resp := postLogs(t, target, contentTypeProtobuf, false, body)
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+				require.Equal(t, contentTypeProtobuf, resp.Header.Get("Content-Type"))
+			},
+		},
+		{
+			name: "http gzipped json",
+			export: func(t *testing.T, target *Target, logs plog.Logs) {
+				body, err := plogotlp.NewExportRequestFromLogs(logs).MarshalJSON()
+				require.NoError(t, err)
+				resp := postLogs(t, target, contentTypeJSON, true, body)
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+				require.Equal(t, contentTypeJSON, resp.Header.Get("Content-Type"))
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			eh := fake.New(func() {})
+			defer eh.Stop()
+
+			target, err := NewTarget(NewMetrics(prometheus.NewRegistry()), log.NewNopLogger(), eh, "job", &scrapeconfig.OTLPTargetConfig{
+				Server:               testServerConfig(),
+				Labels:               model.LabelSet{"job": "otlp"},
+				UseIncomingTimestamp: true,
+			}, []*relabel.Config{
+				{
+					SourceLabels: model.LabelNames{"__otlp_resource_attribute_service_name"},
+					TargetLabel:  "service",
+					Replacement:  "$1",
+					Action:       relabel.Replace,
+					Regex:        relabel.MustNewRegexp("(.*)"),
+				},
+				{
+					SourceLabels: model.LabelNames{"__otlp_severity_text"},
+					TargetLabel:  "level",
+					Replacement:  "$1",
+					Action:       relabel.Replace,
+					Regex:        relabel.MustNewRegexp("(.*)"),
+				},
+				{
+					SourceLabels: model.LabelNames{"__otlp_log_attribute_http_method"},
+					TargetLabel:  "method",
+					Replacement:  "$1",
+					Action:       relabel.Replace,
+					Regex:        relabel.MustNewRegexp("(.*)"),
+				},
+				{
+					SourceLabels: model.LabelNames{"__otlp_log_attribute_drop"},
+					Action:       relabel.Drop,
+					Regex:        relabel.MustNewRegexp("true"),
+				},
+			})
+			require.NoError(t, err)
+			defer func() {
+				require.NoError(t, target.Stop())
+			}()
+
+			tc.export(t, target, testLogs())
+
+			require.Eventually(t, func() bool {
+				return len(eh.Received()) == 2
+			}, 5*time.Second, 10*time.Millisecond)
+			received := eh.Received()
+			require.Equal(t, model.LabelSet{"job": "otlp", "service": "api", "level": "INFO", "method": "GET"}, received[0].Labels)
+			require.Equal(t, "GET /", received[0].Line)
+			require.Equal(t, testTimestamp.UnixNano(), received[0].Timestamp.UnixNano())
+			require.Equal(t, model.LabelSet{"job": "otlp", "service": "api"}, received[1].Labels)
+			require.JSONEq(t, `{"msg":"started"}`, received[1].Line)
+			require.Equal(t, testTimestamp.Add(time.Second).UnixNano(), received[1].Timestamp.UnixNano())
+		})
+	}
+}
+
+func TestOTLPTarget_InvalidRequests(t *testing.T) {
+	eh := fake.New(func() {})
+	defer eh.Stop()
+
+	target, err := NewTarget(NewMetrics(prometheus.NewRegistry()), log.NewNopLogger(), eh, "job", &scrapeconfig.OTLPTargetConfig{
+		Server: testServerConfig(),
+	}, nil)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, target.Stop())
+	}()
+
+	// Bodies which are not JSON are decoded as protobuf.
+	require.Equal(t, http.StatusBadRequest, postLogs(t, target, "text/plain", false, []byte("logs")).StatusCode)
+	require.Equal(t, http.StatusBadRequest, postLogs(t, target, "", false, []byte("logs")).StatusCode)
+	require.Equal(t, http.StatusBadRequest, postLogs(t, target, contentTypeJSON, false, []byte("{")).StatusCode)
+	require.Equal(t, http.StatusBadRequest, postLogs(t, target, contentTypeProtobuf, true, []byte("invalid")).StatusCode)
+	require.Empty(t, eh.Received())
+}
+
+func TestOTLPTarget_RequestTooLarge(t *testing.T) {
+	eh := fake.New(func() {})
+	defer eh.Stop()
+
+	cfg := testServerConfig()
+	cfg.GPRCServerMaxRecvMsgSize = 1024
+	target, err := NewTarget(NewMetrics(prometheus.NewRegistry()), log.NewNopLogger(), eh, "job", &scrapeconfig.OTLPTargetConfig{
+		Server: cfg,
+	}, nil)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, target.Stop())
+	}()
+
+	body := bytes.Repeat([]byte("a"), 2048)
+	require.Equal(t, http.StatusRequestEntityTooLarge, postLogs(t, target, contentTypeProtobuf, false, body).StatusCode)
+	// The compressed body fits the limit but not the decompressed one.
+	require.Equal(t, http.StatusRequestEntityTooLarge, postLogs(t, target, contentTypeProtobuf, true, body).StatusCode)
+	require.Empty(t, eh.Received())
+}
+
+func TestOTLPTarget_ErrorOnNotPrometheusCompatibleJobName(t *testing.T) {
+	eh := fake.New(func() {})
+	defer eh.Stop()
+
+	_, err := NewTarget(NewMetrics(prometheus.NewRegistry()), log.NewNopLogger(), eh, "test-job", &scrapeconfig.OTLPTargetConfig{
+		Server: testServerConfig(),
+	}, nil)
+	require.ErrorContains(t, err, "invalid prometheus-compatible job name")
+}
+
+// testLogs returns logs of a service with a request log record, a log record with a map body and a dropped log record.
+func testLogs() plog.Logs {
+	logs := plog.NewLogs()
+	rl := logs.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("service.name", "api")
+	sl := rl.ScopeLogs().AppendEmpty()
+	sl.Scope().SetName("http")
+
+	record := sl.LogRecords().AppendEmpty()
+	record.SetTimestamp(pcommon.NewTimestampFromTime(testTimestamp))
+	record.SetSeverityText("INFO")
+	record.SetSeverityNumber(plog.SeverityNumberInfo)
+	record.SetTraceID(pcommon.TraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}))
+	record.Attributes().PutStr("http.method", "GET")
+	record.Body().SetStr("GET /")
+
+	record = sl.LogRecords().AppendEmpty()
+	record.SetObservedTimestamp(pcommon.NewTimestampFromTime(testTimestamp.Add(time.Second)))
+	record.Body().SetEmptyMap().PutStr("msg", "started")
+
+	record = sl.LogRecords().AppendEmpty()
+	record.Attributes().PutStr("drop", "true")
+	record.Body().SetStr("dropped")
+	return logs
+}
+
+func postLogs(t *testing.T, target *Target, contentType string, gzipped bool, body []byte) *http.Response {
+	if gzipped {
+		var buf bytes.Buffer
+		gw := gzip.NewWriter(&buf)
+		_, err := gw.Write(body)
+		require.NoError(t, err)
+		require.NoError(t, gw.Close())
+		body = buf.Bytes()
+	}
+	req, err := http.NewRequest(http.MethodPost, "http://"+target.server.HTTPListenAddr().String()+"/v1/logs", bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", contentType)
+	if gzipped {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	return resp
+}
+
+func testServerConfig() server.Config {
+	var cfg server.Config
+	cfg.RegisterFlags(flag.NewFlagSet("empty", flag.ContinueOnError))
+	cfg.HTTPListenAddress = localhost
+	cfg.HTTPListenPort = 0
+	cfg.GRPCListenAddress = localhost
+	cfg.GRPCListenPort = 0
+	// The server metrics are registered for each target.
+	cfg.Registerer = prometheus.NewRegistry()
+	return cfg
+}
@@ -44,6 +44,12 @@ const (
 
 	// HerokuDrainTargetType is a Heroku Logs target
 	HerokuDrainTargetType = TargetType("HerokuDrain")
+
+	// FluentForwardTargetType is a Fluent Forward target
+	FluentForwardTargetType = TargetType("FluentForward")
+
+	// OTLPTargetType is an OpenTelemetry protocol target
+	OTLPTargetType = TargetType("OTLP")
 )
 
 // Target is a promtail scrape target
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/backoff"
+	"github.com/prometheus/client_golang/prometheus"
+	config_util "github.com/prometheus/common/config"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/storage/remote"
+
+	"github.com/grafana/loki/pkg/logql"
+	"github.com/grafana/loki/pkg/loki"
+	"github.com/grafana/loki/pkg/ruler/backfill"
+	"github.com/grafana/loki/pkg/storage"
+	"github.com/grafana/loki/pkg/util/cfg"
+	util_log "github.com/grafana/loki/pkg/util/log"
+	"github.com/grafana/loki/pkg/validation"
+)
+
+func main() {
+	configFile := flag.String("config.file", "", "Loki configuration file, used to read the logs from the storage.")
+	start := flag.String("start", "", "Start of the time range to backfill, RFC3339 2006-01-02T15:04:05Z07:00")
+	end := flag.String("end", "", "End of the time range to backfill, RFC3339 2006-01-02T15:04:05Z07:00")
+	tenant := flag.String("tenant", "fake", "Tenant whose logs the rules are evaluated against, default is `fake` for single tenant Loki")
+	interval := flag.Duration("evaluation-interval", 0, "Evaluation interval of the rules, defaults to the interval of each rule group")
+	blockDuration := flag.Duration("block-duration", 2*time.Hour, "Duration of the time ranges evaluated and written at once")
+	progressFile := flag.String("progress.file", "", "File recording the progress of the backfill, so that it can be resumed")
+	outputDir := flag.String("output.dir", "data/", "Directory the TSDB blocks are written to")
+	remoteWriteURL := flag.String("remote-write.url", "", "Prometheus remote-write URL the samples are sent to, instead of writing TSDB blocks")
+	remoteWriteTimeout := flag.Duration("remote-write.timeout", 30*time.Second, "Timeout of the remote-write requests")
+	remoteWriteBatch := flag.Int("remote-write.max-samples-per-send", 2000, "Maximum number of samples per remote-write request")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] <rule file>...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	logger := util_log.Logger
+	exit := func(msg string, err error) {
+		level.Error(logger).Log("msg", msg, "err", err)
+		os.Exit(1)
+	}
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	backfillCfg := backfill.Config{
+		Tenant:             *tenant,
+		EvaluationInterval: *interval,
+		BlockDuration:      *blockDuration,
+		ProgressFile:       *progressFile,
+	}
+	var err error
+	if backfillCfg.Start, err = time.Parse(time.RFC3339, *start); err != nil {
+		exit("invalid start", err)
+	}
+	if backfillCfg.End, err = time.Parse(time.RFC3339, *end); err != nil {
+		exit("invalid end", err)
+	}
+
+	var config loki.ConfigWrapper
+	if err := cfg.DynamicUnmarshal(&config, []string{"-config.file=" + *configFile}, flag.NewFlagSet("config-file-loader", flag.ContinueOnError)); err != nil {
+		exit("failed parsing config", err)
+	}
+	// The blocks evaluated are longer than the queries usually allowed.
+	config.LimitsConfig.MaxQueryLength = 0
+	if err := config.Validate(); err != nil {
+		exit("invalid config", err)
+	}
+
+	limits, err := validation.NewOverrides(config.LimitsConfig, nil)
+	if err != nil {
+		exit("failed to create limit overrides", err)
+	}
+	store, err := storage.NewStore(config.StorageConfig, config.ChunkStoreConfig, config.SchemaConfig, limits, storage.NewClientMetrics(), prometheus.DefaultRegisterer, logger)
+	if err != nil {
+		exit("failed to create store", err)
+	}
+	defer store.Stop()
+
+	var writer backfill.Writer
+	if *remoteWriteURL != "" {
+		u, err := url.Parse(*remoteWriteURL)
+		if err != nil {
+			exit("invalid remote-write URL", err)
+		}
+		client, err := remote.NewWriteClient("ruler-backfill", &remote.ClientConfig{
+			URL:              &config_util.URL{URL: u},
+			Timeout:          model.Duration(*remoteWriteTimeout),
+			HTTPClientConfig: config_util.DefaultHTTPClientConfig,
+		})
+		if err != nil {
+			exit("failed to create remote-write client", err)
+		}
+		writer = backfill.NewRemoteWriteWriter(client, *remoteWriteBatch, backoff.Config{
+			MinBackoff: 100 * time.Millisecond,
+			MaxBackoff: 10 * time.Second,
+			MaxRetries: 10,
+		})
+	} else {
+		if err := os.MkdirAll(*outputDir, 0o750); err != nil {
+			exit("failed to create output directory", err)
+		}
+		writer = backfill.NewTSDBWriter(*outputDir, logger)
+	}
+
+	engine := logql.NewEngine(config.Querier.Engine, store, limits, logger)
+	b, err := backfill.New(backfillCfg, engine, writer, logger)
+	if err != nil {
+		exit("invalid backfill", err)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	if err := b.Run(ctx, flag.Args()); err != nil {
+		exit("backfill failed", err)
+	}
+	level.Info(logger).Log("msg", "backfill finished")
+}
@@ -40,6 +40,11 @@ func (e *encbuf) putVarint64(x int64) {
 	e.b = append(e.b, e.c[:n]...)
 }
 
+func (e *encbuf) putUvarintStr(s string) {
+	e.putUvarint(len(s))
+	e.b = append(e.b, s...)
+}
+
 // putHash appends a hash over the buffers current contents to the buffer.
 func (e *encbuf) putHash(h hash.Hash) {
 	h.Reset()
@@ -131,4 +136,8 @@ func (d *decbuf) bytes(n int) []byte {
 	return x
 }
 
+func (d *decbuf) uvarintStr() string {
+	return string(d.bytes(d.uvarint()))
+}
+
 func (d *decbuf) err() error { return d.e }
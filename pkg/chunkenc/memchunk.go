@@ -8,6 +8,7 @@ import (
 	"hash"
 	"hash/crc32"
 	"io"
+	"math"
 	"reflect"
 	"time"
 	"unsafe"
@@ -31,6 +32,8 @@ const (
 	chunkFormatV1
 	chunkFormatV2
 	chunkFormatV3
+	// chunkFormatV4 adds the structured metadata of the entries to the blocks.
+	chunkFormatV4
 
 	DefaultChunkFormat = chunkFormatV3 // the currently used chunk format
 
@@ -43,7 +46,7 @@ const (
 	defaultBlockSize = 256 * 1024
 )
 
-var HeadBlockFmts = []HeadBlockFmt{OrderedHeadBlockFmt, UnorderedHeadBlockFmt}
+var HeadBlockFmts = []HeadBlockFmt{OrderedHeadBlockFmt, UnorderedHeadBlockFmt, UnorderedWithStructuredMetadataHeadBlockFmt}
 
 type HeadBlockFmt byte
 
@@ -55,6 +58,8 @@ func (f HeadBlockFmt) String() string {
 		return "ordered"
 	case f == UnorderedHeadBlockFmt:
 		return "unordered"
+	case f == UnorderedWithStructuredMetadataHeadBlockFmt:
+		return "unordered with structured metadata"
 	default:
 		return fmt.Sprintf("unknown: %v", byte(f))
 	}
@@ -65,10 +70,18 @@ func (f HeadBlockFmt) NewBlock() HeadBlock {
 	case f < UnorderedHeadBlockFmt:
 		return &headBlock{}
 	default:
-		return newUnorderedHeadBlock()
+		return newUnorderedHeadBlock(f)
 	}
 }
 
+// ChunkFormat returns the format of the chunks cutting blocks from head blocks of this format.
+func (f HeadBlockFmt) ChunkFormat() byte {
+	if f >= UnorderedWithStructuredMetadataHeadBlockFmt {
+		return chunkFormatV4
+	}
+	return DefaultChunkFormat
+}
+
 const (
 	_ HeadBlockFmt = iota
 	// placeholders to start splitting chunk formats vs head block
@@ -77,6 +90,9 @@ const (
 	_
 	OrderedHeadBlockFmt
 	UnorderedHeadBlockFmt
+	// UnorderedWithStructuredMetadataHeadBlockFmt is an unordered head block
+	// also storing the structured metadata of the entries.
+	UnorderedWithStructuredMetadataHeadBlockFmt
 )
 
 var magicNumber = uint32(0x12EE56A)
@@ -159,7 +175,8 @@ func (hb *headBlock) Reset() {
 
 func (hb *headBlock) Bounds() (int64, int64) { return hb.mint, hb.maxt }
 
-func (hb *headBlock) Append(ts int64, line string) error {
+// Append adds an entry to the head block, ordered head blocks don't store the structured metadata of the entries.
+func (hb *headBlock) Append(ts int64, line string, _ labels.Labels) error {
 	if !hb.IsEmpty() && hb.maxt > ts {
 		return ErrOutOfOrder
 	}
@@ -315,10 +332,10 @@ func (hb *headBlock) Convert(version HeadBlockFmt) (HeadBlock, error) {
 	if version < UnorderedHeadBlockFmt {
 		return hb, nil
 	}
-	out := newUnorderedHeadBlock()
+	out := newUnorderedHeadBlock(version)
 
 	for _, e := range hb.entries {
-		if err := out.Append(e.t, e.s); err != nil {
+		if err := out.Append(e.t, e.s, nil); err != nil {
 			return nil, err
 		}
 	}
@@ -330,6 +347,31 @@ type entry struct {
 	s string
 }
 
+// writeStructuredMetadata appends the structured metadata of an entry to a block: the number of labels,
+// followed by the length and the content of the name and of the value of each label.
+func writeStructuredMetadata(w *bytes.Buffer, encBuf []byte, structuredMetadata labels.Labels) {
+	n := binary.PutUvarint(encBuf, uint64(len(structuredMetadata)))
+	w.Write(encBuf[:n])
+	for _, l := range structuredMetadata {
+		n = binary.PutUvarint(encBuf, uint64(len(l.Name)))
+		w.Write(encBuf[:n])
+		w.WriteString(l.Name)
+
+		n = binary.PutUvarint(encBuf, uint64(len(l.Value)))
+		w.Write(encBuf[:n])
+		w.WriteString(l.Value)
+	}
+}
+
+// structuredMetadataSize returns the maximum encoded size of the structured metadata of an entry.
+func structuredMetadataSize(structuredMetadata labels.Labels) int {
+	size := binary.MaxVarintLen32
+	for _, l := range structuredMetadata {
+		size += 2*binary.MaxVarintLen32 + len(l.Name) + len(l.Value)
+	}
+	return size
+}
+
 // NewMemChunk returns a new in-mem chunk.
 func NewMemChunk(enc Encoding, head HeadBlockFmt, blockSize, targetSize int) *MemChunk {
 	return &MemChunk{
@@ -337,7 +379,7 @@ func NewMemChunk(enc Encoding, head HeadBlockFmt, blockSize, targetSize int) *Me
 		targetSize: targetSize, // Desired chunk size in compressed bytes
 		blocks:     []block{},
 
-		format: DefaultChunkFormat,
+		format: head.ChunkFormat(),
 		head:   head.NewBlock(),
 
 		encoding: enc,
@@ -366,7 +408,7 @@ func NewByteChunk(b []byte, blockSize, targetSize int) (*MemChunk, error) {
 	switch version {
 	case chunkFormatV1:
 		bc.encoding = EncGZIP
-	case chunkFormatV2, chunkFormatV3:
+	case chunkFormatV2, chunkFormatV3, chunkFormatV4:
 		// format v2+ has a byte for block encoding.
 		enc := Encoding(db.byte())
 		if db.err() != nil {
@@ -376,6 +418,11 @@ func NewByteChunk(b []byte, blockSize, targetSize int) (*MemChunk, error) {
 	default:
 		return nil, errors.Errorf("invalid version %d", version)
 	}
+	if version >= chunkFormatV4 {
+		// Rebuilding the chunk, e.g. when deleting logs, must keep the structured metadata of the entries.
+		bc.headFmt = UnorderedWithStructuredMetadataHeadBlockFmt
+		bc.head = bc.headFmt.NewBlock()
+	}
 
 	metasOffset := binary.BigEndian.Uint64(b[len(b)-8:])
 	mb := b[metasOffset : len(b)-(8+4)] // storing the metasOffset + checksum of meta
@@ -401,7 +448,7 @@ func NewByteChunk(b []byte, blockSize, targetSize int) (*MemChunk, error) {
 
 		// Read offset and length.
 		blk.offset = db.uvarint()
-		if version == chunkFormatV3 {
+		if version >= chunkFormatV3 {
 			blk.uncompressedSize = db.uvarint()
 		}
 		l := db.uvarint()
@@ -460,7 +507,7 @@ func (c *MemChunk) BytesSize() int {
 		size += binary.MaxVarintLen64 // mint
 		size += binary.MaxVarintLen64 // maxt
 		size += binary.MaxVarintLen32 // offset
-		if c.format == chunkFormatV3 {
+		if c.format >= chunkFormatV3 {
 			size += binary.MaxVarintLen32 // uncompressed size
 		}
 		size += binary.MaxVarintLen32 // len(b)
@@ -534,7 +581,7 @@ func (c *MemChunk) WriteTo(w io.Writer) (int64, error) {
 		eb.putVarint64(b.mint)
 		eb.putVarint64(b.maxt)
 		eb.putUvarint(b.offset)
-		if c.format == chunkFormatV3 {
+		if c.format >= chunkFormatV3 {
 			eb.putUvarint(b.uncompressedSize)
 		}
 		eb.putUvarint(len(b.b))
@@ -589,13 +636,24 @@ func MemchunkFromCheckpoint(chk, head []byte, desired HeadBlockFmt, blockSize in
 	if err != nil {
 		return nil, err
 	}
-	h, err := HeadFromCheckpoint(head, desired)
+	// The head block of v4+ chunks is loaded with its structured metadata
+	// to check whether the chunk can be converted to the desired format.
+	headFmt := desired
+	if mc.format >= chunkFormatV4 {
+		headFmt = UnorderedWithStructuredMetadataHeadBlockFmt
+	} else if headFmt >= UnorderedWithStructuredMetadataHeadBlockFmt {
+		headFmt = UnorderedHeadBlockFmt
+	}
+	h, err := HeadFromCheckpoint(head, headFmt)
 	if err != nil {
 		return nil, err
 	}
 
 	mc.head = h
-	mc.headFmt = desired
+	mc.headFmt = headFmt
+	if err := mc.ConvertHead(desired); err != nil {
+		return nil, err
+	}
 	return mc, nil
 }
 
@@ -674,7 +732,7 @@ func (c *MemChunk) Append(entry *logproto.Entry) error {
 		return ErrOutOfOrder
 	}
 
-	if err := c.head.Append(entryTimestamp, entry.Line); err != nil {
+	if err := c.head.Append(entryTimestamp, entry.Line, logproto.FromLabelAdaptersToLabels(entry.StructuredMetadata)); err != nil {
 		return err
 	}
 
@@ -722,6 +780,10 @@ func (c *MemChunk) reorder() error {
 }
 
 func (c *MemChunk) ConvertHead(desired HeadBlockFmt) error {
+	desired, err := c.compatibleHeadFmt(desired)
+	if err != nil {
+		return err
+	}
 	if c.head != nil && c.head.Format() != desired {
 		newH, err := c.head.Convert(desired)
 		if err != nil {
@@ -734,6 +796,98 @@ func (c *MemChunk) ConvertHead(desired HeadBlockFmt) error {
 	return nil
 }
 
+// compatibleHeadFmt returns the head block format closest to the desired one which can be used with
+// the format of the chunk: the head blocks of v4+ chunks must write the structured metadata of
+// the entries to the blocks they cut, while the head blocks of older chunks must not.
+// v4+ chunks without any structured metadata are converted back to the default chunk format
+// when the desired head block format doesn't store it.
+func (c *MemChunk) compatibleHeadFmt(desired HeadBlockFmt) (HeadBlockFmt, error) {
+	if c.format < chunkFormatV4 {
+		if desired >= UnorderedWithStructuredMetadataHeadBlockFmt {
+			return UnorderedHeadBlockFmt, nil
+		}
+		return desired, nil
+	}
+	if desired >= UnorderedWithStructuredMetadataHeadBlockFmt {
+		return desired, nil
+	}
+
+	hasStructuredMetadata, err := c.hasStructuredMetadata()
+	if err != nil {
+		return 0, err
+	}
+	if hasStructuredMetadata {
+		// The structured metadata of the entries already accepted can't be dropped.
+		return UnorderedWithStructuredMetadataHeadBlockFmt, nil
+	}
+	if err := c.reencodeBlocks(DefaultChunkFormat, desired); err != nil {
+		return 0, err
+	}
+	return desired, nil
+}
+
+// hasStructuredMetadata returns whether any entry of the chunk has structured metadata.
+func (c *MemChunk) hasStructuredMetadata() (bool, error) {
+	if c.format < chunkFormatV4 {
+		return false, nil
+	}
+	it, err := c.Iterator(context.Background(), time.Unix(0, 0), time.Unix(0, math.MaxInt64), logproto.FORWARD, log.NewNoopPipeline().ForStream(labels.Labels{}))
+	if err != nil {
+		return false, err
+	}
+	defer it.Close()
+
+	for it.Next() {
+		if len(it.Entry().StructuredMetadata) > 0 {
+			return true, nil
+		}
+	}
+	return false, it.Error()
+}
+
+// reencodeBlocks re-encodes the cut blocks of the chunk in the given chunk format,
+// by serialising their entries with head blocks of the given format.
+func (c *MemChunk) reencodeBlocks(format byte, headFmt HeadBlockFmt) error {
+	blocks := make([]block, 0, len(c.blocks))
+	cutBlockSize := 0
+	for _, b := range c.blocks {
+		hb := headFmt.NewBlock()
+		it := encBlock{c.encoding, c.format, b}.Iterator(context.Background(), log.NewNoopPipeline().ForStream(labels.Labels{}))
+		var err error
+		for err == nil && it.Next() {
+			e := it.Entry()
+			err = hb.Append(e.Timestamp.UnixNano(), e.Line, nil)
+		}
+		if err == nil {
+			err = it.Error()
+		}
+		if closeErr := it.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return err
+		}
+
+		data, err := hb.Serialise(getWriterPool(c.encoding))
+		if err != nil {
+			return err
+		}
+		blocks = append(blocks, block{
+			b:                data,
+			numEntries:       b.numEntries,
+			mint:             b.mint,
+			maxt:             b.maxt,
+			uncompressedSize: hb.UncompressedSize(),
+		})
+		cutBlockSize += len(data)
+	}
+
+	c.blocks = blocks
+	c.cutBlockSize = cutBlockSize
+	c.format = format
+	return nil
+}
+
 // cut a new block and add it to finished blocks.
 func (c *MemChunk) cut() error {
 	if c.head.IsEmpty() {
@@ -797,7 +951,7 @@ func (c *MemChunk) Iterator(ctx context.Context, mintT, maxtT time.Time, directi
 		}
 		lastMax = b.maxt
 
-		blockItrs = append(blockItrs, encBlock{c.encoding, b}.Iterator(ctx, pipeline))
+		blockItrs = append(blockItrs, encBlock{c.encoding, c.format, b}.Iterator(ctx, pipeline))
 	}
 
 	if !c.head.IsEmpty() {
@@ -871,7 +1025,7 @@ func (c *MemChunk) SampleIterator(ctx context.Context, from, through time.Time,
 			ordered = false
 		}
 		lastMax = b.maxt
-		its = append(its, encBlock{c.encoding, b}.SampleIterator(ctx, extractor))
+		its = append(its, encBlock{c.encoding, c.format, b}.SampleIterator(ctx, extractor))
 	}
 
 	if !c.head.IsEmpty() {
@@ -903,7 +1057,7 @@ func (c *MemChunk) Blocks(mintT, maxtT time.Time) []Block {
 
 	for _, b := range c.blocks {
 		if maxt >= b.mint && b.maxt >= mint {
-			blocks = append(blocks, encBlock{c.encoding, b})
+			blocks = append(blocks, encBlock{c.encoding, c.format, b})
 		}
 	}
 	return blocks
@@ -955,7 +1109,8 @@ func (c *MemChunk) Rebound(start, end time.Time, filter filter.Func) (Chunk, err
 // then allows us to bind a decoding context to a block when requested, but otherwise helps reduce the
 // chances of chunk<>block encoding drift in the codebase as the latter is parameterized by the former.
 type encBlock struct {
-	enc Encoding
+	enc    Encoding
+	format byte
 	block
 }
 
@@ -963,14 +1118,14 @@ func (b encBlock) Iterator(ctx context.Context, pipeline log.StreamPipeline) ite
 	if len(b.b) == 0 {
 		return iter.NoopIterator
 	}
-	return newEntryIterator(ctx, getReaderPool(b.enc), b.b, pipeline)
+	return newEntryIterator(ctx, getReaderPool(b.enc), b.b, b.format, pipeline)
 }
 
 func (b encBlock) SampleIterator(ctx context.Context, extractor log.StreamSampleExtractor) iter.SampleIterator {
 	if len(b.b) == 0 {
 		return iter.NoopIterator
 	}
-	return newSampleIterator(ctx, getReaderPool(b.enc), b.b, extractor)
+	return newSampleIterator(ctx, getReaderPool(b.enc), b.b, b.format, extractor)
 }
 
 func (b block) Offset() int {
@@ -1109,8 +1264,13 @@ func unsafeGetBytes(s string) []byte {
 	return buf
 }
 
+func unsafeGetString(buf []byte) string {
+	return *((*string)(unsafe.Pointer(&buf)))
+}
+
 type bufferedIterator struct {
 	origBytes []byte
+	format    byte
 	stats     *stats.Context
 
 	reader io.Reader
@@ -1125,15 +1285,18 @@ type bufferedIterator struct {
 	currLine []byte // the current line, this is the same as the buffer but sliced the the line size.
 	currTs   int64
 
+	currStructuredMetadata labels.Labels // the structured metadata of the current entry, for chunks of the format v4+.
+
 	closed bool
 }
 
-func newBufferedIterator(ctx context.Context, pool ReaderPool, b []byte) *bufferedIterator {
+func newBufferedIterator(ctx context.Context, pool ReaderPool, b []byte, format byte) *bufferedIterator {
 	stats := stats.FromContext(ctx)
 	stats.AddCompressedBytes(int64(len(b)))
 	return &bufferedIterator{
 		stats:     stats,
 		origBytes: b,
+		format:    format,
 		reader:    nil, // will be initialized later
 		pool:      pool,
 	}
@@ -1163,11 +1326,98 @@ func (si *bufferedIterator) Next() bool {
 	si.stats.AddDecompressedBytes(int64(len(line)) + 2*binary.MaxVarintLen64)
 	si.stats.AddDecompressedLines(1)
 
+	si.currStructuredMetadata = nil
+	if si.format >= chunkFormatV4 {
+		if si.currStructuredMetadata, ok = si.readStructuredMetadata(); !ok {
+			si.Close()
+			return false
+		}
+	}
+
 	si.currTs = ts
 	si.currLine = line
 	return true
 }
 
+// readStructuredMetadata reads the structured metadata following the line of an entry.
+// The labels are allocated for each entry since they are passed along with the entry.
+func (si *bufferedIterator) readStructuredMetadata() (labels.Labels, bool) {
+	n, ok := si.readUvarint()
+	if !ok || n == 0 {
+		return nil, ok
+	}
+
+	structuredMetadata := make(labels.Labels, n)
+	for i := range structuredMetadata {
+		name, ok := si.readString()
+		if !ok {
+			return nil, false
+		}
+		value, ok := si.readString()
+		if !ok {
+			return nil, false
+		}
+		structuredMetadata[i] = labels.Label{Name: name, Value: value}
+	}
+	return structuredMetadata, true
+}
+
+// readUvarint reads a varint from the bytes left in the read buffer, reading more from the reader when needed.
+func (si *bufferedIterator) readUvarint() (int, bool) {
+	for {
+		v, w := binary.Uvarint(si.readBuf[:si.readBufValid])
+		if w > 0 {
+			si.readBufValid = copy(si.readBuf[:], si.readBuf[w:si.readBufValid])
+			return int(v), true
+		}
+		if w < 0 {
+			si.err = fmt.Errorf("invalid data in chunk")
+			return 0, false
+		}
+
+		n, err := si.reader.Read(si.readBuf[si.readBufValid:])
+		si.readBufValid += n
+		if err != nil && n == 0 {
+			if err == io.EOF {
+				err = fmt.Errorf("invalid data in chunk")
+			}
+			si.err = err
+			return 0, false
+		}
+	}
+}
+
+// readString reads a string prefixed by its length.
+func (si *bufferedIterator) readString() (string, bool) {
+	size, ok := si.readUvarint()
+	if !ok {
+		return "", false
+	}
+	if size >= maxLineLength {
+		si.err = fmt.Errorf("structured metadata too long %d, maximum %d", size, maxLineLength)
+		return "", false
+	}
+
+	b := make([]byte, size)
+	// Take however many bytes are left in the read buffer.
+	n := copy(b, si.readBuf[:si.readBufValid])
+	si.readBufValid = copy(si.readBuf[:], si.readBuf[n:si.readBufValid])
+
+	for n < size {
+		r, err := si.reader.Read(b[n:])
+		n += r
+		if err != nil {
+			if err == io.EOF && r != 0 {
+				continue
+			}
+			si.err = err
+			return "", false
+		}
+	}
+	si.stats.AddDecompressedBytes(int64(size))
+	return unsafeGetString(b), true
+}
+
 // moveNext moves the buffer to the next entry
 func (si *bufferedIterator) moveNext() (int64, []byte, bool) {
 	var ts int64
@@ -1257,9 +1507,9 @@ func (si *bufferedIterator) close() {
 	si.origBytes = nil
 }
 
-func newEntryIterator(ctx context.Context, pool ReaderPool, b []byte, pipeline log.StreamPipeline) iter.EntryIterator {
+func newEntryIterator(ctx context.Context, pool ReaderPool, b []byte, format byte, pipeline log.StreamPipeline) iter.EntryIterator {
 	return &entryBufferedIterator{
-		bufferedIterator: newBufferedIterator(ctx, pool, b),
+		bufferedIterator: newBufferedIterator(ctx, pool, b, format),
 		pipeline:         pipeline,
 	}
 }
@@ -1282,21 +1532,22 @@ func (e *entryBufferedIterator) StreamHash() uint64 { return e.pipeline.BaseLabe
 
 func (e *entryBufferedIterator) Next() bool {
 	for e.bufferedIterator.Next() {
-		newLine, lbs, matches := e.pipeline.Process(e.currTs, e.currLine)
+		newLine, lbs, matches := e.pipeline.Process(e.currTs, e.currLine, e.currStructuredMetadata...)
 		if !matches {
 			continue
 		}
 		e.cur.Timestamp = time.Unix(0, e.currTs)
 		e.cur.Line = string(newLine)
+		e.cur.StructuredMetadata = logproto.FromLabelsToLabelAdapters(e.currStructuredMetadata)
 		e.currLabels = lbs
 		return true
 	}
 	return false
 }
 
-func newSampleIterator(ctx context.Context, pool ReaderPool, b []byte, extractor log.StreamSampleExtractor) iter.SampleIterator {
+func newSampleIterator(ctx context.Context, pool ReaderPool, b []byte, format byte, extractor log.StreamSampleExtractor) iter.SampleIterator {
 	it := &sampleBufferedIterator{
-		bufferedIterator: newBufferedIterator(ctx, pool, b),
+		bufferedIterator: newBufferedIterator(ctx, pool, b, format),
 		extractor:        extractor,
 	}
 	return it
@@ -1313,7 +1564,7 @@ type sampleBufferedIterator struct {
 
 func (e *sampleBufferedIterator) Next() bool {
 	for e.bufferedIterator.Next() {
-		val, labels, ok := e.extractor.Process(e.currTs, e.currLine)
+		val, labels, ok := e.extractor.Process(e.currTs, e.currLine, e.currStructuredMetadata...)
 		if !ok {
 			continue
 		}
@@ -250,7 +250,11 @@ func TestReadFormatV1(t *testing.T) {
 func TestRoundtripV2(t *testing.T) {
 	for _, f := range HeadBlockFmts {
 		for _, enc := range testEncoding {
-			for _, version := range []byte{chunkFormatV2, chunkFormatV3} {
+			for _, version := range []byte{chunkFormatV2, chunkFormatV3, chunkFormatV4} {
+				// Only the head blocks storing structured metadata can cut blocks of v4 chunks.
+				if (version >= chunkFormatV4) != (f.ChunkFormat() >= chunkFormatV4) {
+					continue
+				}
 				t.Run(enc.String(), func(t *testing.T) {
 					t.Parallel()
 
@@ -708,10 +712,10 @@ func BenchmarkWrite(b *testing.B) {
 type nomatchPipeline struct{}
 
 func (nomatchPipeline) BaseLabels() log.LabelsResult { return log.EmptyLabelsResult }
-func (nomatchPipeline) Process(_ int64, line []byte) ([]byte, log.LabelsResult, bool) {
+func (nomatchPipeline) Process(_ int64, line []byte, _ ...labels.Label) ([]byte, log.LabelsResult, bool) {
 	return line, nil, false
 }
-func (nomatchPipeline) ProcessString(_ int64, line string) (string, log.LabelsResult, bool) {
+func (nomatchPipeline) ProcessString(_ int64, line string, _ ...labels.Label) (string, log.LabelsResult, bool) {
 	return line, nil, false
 }
 
@@ -824,7 +828,7 @@ func BenchmarkHeadBlockIterator(b *testing.B) {
 			h := headBlock{}
 
 			for i := 0; i < j; i++ {
-				if err := h.Append(int64(i), "this is the append string"); err != nil {
+				if err := h.Append(int64(i), "this is the append string", nil); err != nil {
 					b.Fatal(err)
 				}
 			}
@@ -848,7 +852,7 @@ func BenchmarkHeadBlockSampleIterator(b *testing.B) {
 			h := headBlock{}
 
 			for i := 0; i < j; i++ {
-				if err := h.Append(int64(i), "this is the append string"); err != nil {
+				if err := h.Append(int64(i), "this is the append string", nil); err != nil {
 					b.Fatal(err)
 				}
 			}
@@ -1340,3 +1344,144 @@ func buildFilterableTestMemChunk(t *testing.T, from, through time.Time, matching
 
 	return chk
 }
+
+func TestMemChunk_StructuredMetadata(t *testing.T) {
+	entries := []logproto.Entry{
+		{Timestamp: time.Unix(0, 1), Line: "lineA", StructuredMetadata: logproto.FromLabelsToLabelAdapters(labels.FromStrings("traceID", "123", "user", "a"))},
+		{Timestamp: time.Unix(0, 2), Line: "lineB"},
+		{Timestamp: time.Unix(0, 3), Line: "lineC", StructuredMetadata: logproto.FromLabelsToLabelAdapters(labels.FromStrings("traceID", "456"))},
+	}
+	newChunk := func() *MemChunk {
+		c := NewMemChunk(EncSnappy, UnorderedWithStructuredMetadataHeadBlockFmt, testBlockSize, testTargetSize)
+		require.Equal(t, chunkFormatV4, c.format)
+		for i := range entries {
+			require.NoError(t, c.Append(&entries[i]))
+		}
+		return c
+	}
+
+	expr, err := syntax.ParseLogSelector(`{app="foo"} | traceID != ""`, true)
+	require.NoError(t, err)
+	p, err := expr.Pipeline()
+	require.NoError(t, err)
+	sampleExpr, err := syntax.ParseSampleExpr(`sum by (traceID) (count_over_time({app="foo"}[1m]))`)
+	require.NoError(t, err)
+	ex, err := sampleExpr.Extractor()
+	require.NoError(t, err)
+
+	assertChunk := func(t *testing.T, c *MemChunk) {
+		t.Helper()
+
+		it, err := c.Iterator(context.Background(), time.Unix(0, 0), time.Unix(0, 10), logproto.FORWARD, log.NewNoopPipeline().ForStream(labels.FromStrings("app", "foo")))
+		require.NoError(t, err)
+		expectedLabels := []string{`{app="foo", traceID="123", user="a"}`, `{app="foo"}`, `{app="foo", traceID="456"}`}
+		var i int
+		for ; it.Next(); i++ {
+			require.Equal(t, entries[i], it.Entry())
+			require.Equal(t, expectedLabels[i], it.Labels())
+		}
+		require.NoError(t, it.Close())
+		require.Equal(t, len(entries), i)
+
+		// The structured metadata is available to the pipelines as labels.
+		it, err = c.Iterator(context.Background(), time.Unix(0, 0), time.Unix(0, 10), logproto.FORWARD, p.ForStream(labels.FromStrings("app", "foo")))
+		require.NoError(t, err)
+		streams := map[string][]string{}
+		for it.Next() {
+			streams[it.Labels()] = append(streams[it.Labels()], it.Entry().Line)
+		}
+		require.NoError(t, it.Close())
+		require.Equal(t, map[string][]string{
+			`{app="foo", traceID="123", user="a"}`: {"lineA"},
+			`{app="foo", traceID="456"}`:           {"lineC"},
+		}, streams)
+
+		sit := c.SampleIterator(context.Background(), time.Unix(0, 0), time.Unix(0, 10), ex.ForStream(labels.FromStrings("app", "foo")))
+		series := map[string]int{}
+		for sit.Next() {
+			series[sit.Labels()]++
+		}
+		require.NoError(t, sit.Close())
+		require.Equal(t, map[string]int{`{}`: 1, `{traceID="123"}`: 1, `{traceID="456"}`: 1}, series)
+	}
+
+	t.Run("head block", func(t *testing.T) {
+		assertChunk(t, newChunk())
+	})
+
+	t.Run("cut blocks", func(t *testing.T) {
+		c := newChunk()
+		require.NoError(t, c.Close())
+		b, err := c.Bytes()
+		require.NoError(t, err)
+		loaded, err := NewByteChunk(b, testBlockSize, testTargetSize)
+		require.NoError(t, err)
+		assertChunk(t, loaded)
+
+		rebound, err := loaded.Rebound(time.Unix(0, 1), time.Unix(0, 3), nil)
+		require.NoError(t, err)
+		assertChunk(t, rebound.(*MemChunk))
+	})
+
+	t.Run("checkpoint", func(t *testing.T) {
+		c := newChunk()
+		var chk, head bytes.Buffer
+		require.NoError(t, c.SerializeForCheckpointTo(&chk, &head))
+		loaded, err := MemchunkFromCheckpoint(chk.Bytes(), head.Bytes(), UnorderedHeadBlockFmt, testBlockSize, testTargetSize)
+		require.NoError(t, err)
+		require.Equal(t, UnorderedWithStructuredMetadataHeadBlockFmt, loaded.head.Format())
+		assertChunk(t, loaded)
+	})
+
+	t.Run("older chunk formats", func(t *testing.T) {
+		c := NewMemChunk(EncSnappy, UnorderedHeadBlockFmt, testBlockSize, testTargetSize)
+		require.NoError(t, c.ConvertHead(UnorderedWithStructuredMetadataHeadBlockFmt))
+		require.Equal(t, UnorderedHeadBlockFmt, c.head.Format())
+		require.NoError(t, c.Append(&entries[0]))
+
+		it, err := c.Iterator(context.Background(), time.Unix(0, 0), time.Unix(0, 10), logproto.FORWARD, noopStreamPipeline)
+		require.NoError(t, err)
+		require.True(t, it.Next())
+		require.Equal(t, logproto.Entry{Timestamp: entries[0].Timestamp, Line: entries[0].Line}, it.Entry())
+		require.NoError(t, it.Close())
+	})
+
+	t.Run("without structured metadata", func(t *testing.T) {
+		for _, desired := range []HeadBlockFmt{OrderedHeadBlockFmt, UnorderedHeadBlockFmt} {
+			t.Run(desired.String(), func(t *testing.T) {
+				c := NewMemChunk(EncSnappy, UnorderedWithStructuredMetadataHeadBlockFmt, testBlockSize, testTargetSize)
+				for i := 0; i < 2; i++ {
+					require.NoError(t, c.Append(&logproto.Entry{Timestamp: time.Unix(0, int64(2*i+1)), Line: "cut"}))
+					require.NoError(t, c.cut())
+				}
+				require.NoError(t, c.Append(&logproto.Entry{Timestamp: time.Unix(0, 5), Line: "head"}))
+
+				var chk, head bytes.Buffer
+				require.NoError(t, c.SerializeForCheckpointTo(&chk, &head))
+				require.NoError(t, c.ConvertHead(desired))
+				loaded, err := MemchunkFromCheckpoint(chk.Bytes(), head.Bytes(), desired, testBlockSize, testTargetSize)
+				require.NoError(t, err)
+
+				// v4 chunks without structured metadata are converted back to the default chunk format.
+				for _, c := range []*MemChunk{c, loaded} {
+					require.Equal(t, DefaultChunkFormat, c.format)
+					require.Equal(t, desired, c.head.Format())
+					require.NoError(t, c.Close())
+					b, err := c.Bytes()
+					require.NoError(t, err)
+					reloaded, err := NewByteChunk(b, testBlockSize, testTargetSize)
+					require.NoError(t, err)
+
+					it, err := reloaded.Iterator(context.Background(), time.Unix(0, 0), time.Unix(0, 10), logproto.FORWARD, noopStreamPipeline)
+					require.NoError(t, err)
+					var lines []string
+					for it.Next() {
+						lines = append(lines, it.Entry().Line)
+					}
+					require.NoError(t, it.Close())
+					require.Equal(t, []string{"cut", "cut", "head"}, lines)
+				}
+			})
+		}
+	})
+}
@@ -34,7 +34,7 @@ type HeadBlock interface {
 	Entries() int
 	UncompressedSize() int
 	Convert(HeadBlockFmt) (HeadBlock, error)
-	Append(int64, string) error
+	Append(int64, string, labels.Labels) error
 	Iterator(
 		ctx context.Context,
 		direction logproto.Direction,
@@ -57,18 +57,25 @@ type unorderedHeadBlock struct {
 	// Scans: (O(k+log(n))) where k=num_scanned_entries & n=total_entries
 	rt rangetree.RangeTree
 
+	format     HeadBlockFmt
 	lines      int   // number of entries
 	size       int   // size of uncompressed bytes.
 	mint, maxt int64 // upper and lower bounds
 }
 
-func newUnorderedHeadBlock() *unorderedHeadBlock {
+func newUnorderedHeadBlock(format HeadBlockFmt) *unorderedHeadBlock {
 	return &unorderedHeadBlock{
-		rt: rangetree.New(1),
+		format: format,
+		rt:     rangetree.New(1),
 	}
 }
 
-func (hb *unorderedHeadBlock) Format() HeadBlockFmt { return UnorderedHeadBlockFmt }
+func (hb *unorderedHeadBlock) Format() HeadBlockFmt { return hb.format }
+
+// hasStructuredMetadata tells if the head block stores the structured metadata of the entries.
+func (hb *unorderedHeadBlock) hasStructuredMetadata() bool {
+	return hb.format >= UnorderedWithStructuredMetadataHeadBlockFmt
+}
 
 func (hb *unorderedHeadBlock) IsEmpty() bool {
 	return hb.size == 0
@@ -87,21 +94,32 @@ func (hb *unorderedHeadBlock) UncompressedSize() int {
 }
 
 func (hb *unorderedHeadBlock) Reset() {
-	x := newUnorderedHeadBlock()
+	x := newUnorderedHeadBlock(hb.format)
 	*hb = *x
 }
 
+type nsEntry struct {
+	line               string
+	structuredMetadata labels.Labels
+}
+
 // collection of entries belonging to the same nanosecond
 type nsEntries struct {
 	ts      int64
-	entries []string
+	entries []nsEntry
 }
 
 func (e *nsEntries) ValueAtDimension(_ uint64) int64 {
 	return e.ts
 }
 
-func (hb *unorderedHeadBlock) Append(ts int64, line string) error {
+// Append adds an entry to the head block.
+// The structured metadata is dropped if the format of the head block doesn't support it.
+func (hb *unorderedHeadBlock) Append(ts int64, line string, structuredMetadata labels.Labels) error {
+	if !hb.hasStructuredMetadata() {
+		structuredMetadata = nil
+	}
+
 	// This is an allocation hack. The rangetree lib does not
 	// support the ability to pass a "mutate" function during an insert
 	// and instead will displace any existing entry at the specified timestamp.
@@ -120,14 +138,14 @@ func (hb *unorderedHeadBlock) Append(ts int64, line string) error {
 		// entries at the same time with the same content, iterate through any existing
 		// entries and ignore the line if we already have an entry with the same content
 		for _, et := range displaced[0].(*nsEntries).entries {
-			if et == line {
+			if et.line == line && labels.Equal(et.structuredMetadata, structuredMetadata) {
 				e.entries = displaced[0].(*nsEntries).entries
 				return nil
 			}
 		}
-		e.entries = append(displaced[0].(*nsEntries).entries, line)
+		e.entries = append(displaced[0].(*nsEntries).entries, nsEntry{line, structuredMetadata})
 	} else {
-		e.entries = []string{line}
+		e.entries = []nsEntry{{line, structuredMetadata}}
 	}
 
 	// Update hb metdata
@@ -162,7 +180,7 @@ func (hb *unorderedHeadBlock) forEntries(
 	direction logproto.Direction,
 	mint,
 	maxt int64,
-	entryFn func(int64, string) error, // returning an error exits early
+	entryFn func(int64, string, labels.Labels) error, // returning an error exits early
 ) (err error) {
 	if hb.IsEmpty() || (maxt < hb.mint || hb.maxt < mint) {
 		return
@@ -191,9 +209,9 @@ func (hb *unorderedHeadBlock) forEntries(
 		}
 
 		for ; i < len(es.entries) && i >= 0; next() {
-			line := es.entries[i]
-			chunkStats.AddHeadChunkBytes(int64(len(line)))
-			err = entryFn(es.ts, line)
+			e := es.entries[i]
+			chunkStats.AddHeadChunkBytes(int64(len(e.line)))
+			err = entryFn(es.ts, e.line, e.structuredMetadata)
 
 		}
 	}
@@ -235,8 +253,8 @@ func (hb *unorderedHeadBlock) Iterator(
 		direction,
 		mint,
 		maxt,
-		func(ts int64, line string) error {
-			newLine, parsedLbs, matches := pipeline.ProcessString(ts, line)
+		func(ts int64, line string, structuredMetadata labels.Labels) error {
+			newLine, parsedLbs, matches := pipeline.ProcessString(ts, line, structuredMetadata...)
 			if !matches {
 				return nil
 			}
@@ -253,8 +271,9 @@ func (hb *unorderedHeadBlock) Iterator(
 			}
 
 			stream.Entries = append(stream.Entries, logproto.Entry{
-				Timestamp: time.Unix(0, ts),
-				Line:      newLine,
+				Timestamp:          time.Unix(0, ts),
+				Line:               newLine,
+				StructuredMetadata: logproto.FromLabelsToLabelAdapters(structuredMetadata),
 			})
 			return nil
 		},
@@ -284,8 +303,8 @@ func (hb *unorderedHeadBlock) SampleIterator(
 		logproto.FORWARD,
 		mint,
 		maxt,
-		func(ts int64, line string) error {
-			value, parsedLabels, ok := extractor.ProcessString(ts, line)
+		func(ts int64, line string, structuredMetadata labels.Labels) error {
+			value, parsedLabels, ok := extractor.ProcessString(ts, line, structuredMetadata...)
 			if !ok {
 				return nil
 			}
@@ -346,7 +365,7 @@ func (hb *unorderedHeadBlock) Serialise(pool WriterPool) ([]byte, error) {
 		logproto.FORWARD,
 		0,
 		math.MaxInt64,
-		func(ts int64, line string) error {
+		func(ts int64, line string, structuredMetadata labels.Labels) error {
 			n := binary.PutVarint(encBuf, ts)
 			inBuf.Write(encBuf[:n])
 
@@ -354,6 +373,10 @@ func (hb *unorderedHeadBlock) Serialise(pool WriterPool) ([]byte, error) {
 			inBuf.Write(encBuf[:n])
 
 			inBuf.WriteString(line)
+
+			if hb.hasStructuredMetadata() {
+				writeStructuredMetadata(inBuf, encBuf, structuredMetadata)
+			}
 			return nil
 		},
 	)
@@ -369,7 +392,7 @@ func (hb *unorderedHeadBlock) Serialise(pool WriterPool) ([]byte, error) {
 }
 
 func (hb *unorderedHeadBlock) Convert(version HeadBlockFmt) (HeadBlock, error) {
-	if version > OrderedHeadBlockFmt {
+	if version == hb.format {
 		return hb, nil
 	}
 	out := version.NewBlock()
@@ -379,8 +402,8 @@ func (hb *unorderedHeadBlock) Convert(version HeadBlockFmt) (HeadBlock, error) {
 		logproto.FORWARD,
 		0,
 		math.MaxInt64,
-		func(ts int64, line string) error {
-			return out.Append(ts, line)
+		func(ts int64, line string, structuredMetadata labels.Labels) error {
+			return out.Append(ts, line, structuredMetadata)
 		},
 	)
 	return out, err
@@ -393,6 +416,12 @@ func (hb *unorderedHeadBlock) CheckpointSize() int {
 	size += binary.MaxVarintLen64 * 2                                  // mint,maxt
 	size += (binary.MaxVarintLen64 + binary.MaxVarintLen32) * hb.lines // ts + len of log line.
 	size += hb.size                                                    // uncompressed bytes of lines
+	if hb.hasStructuredMetadata() {
+		_ = hb.forEntries(context.Background(), logproto.FORWARD, 0, math.MaxInt64, func(_ int64, _ string, structuredMetadata labels.Labels) error {
+			size += structuredMetadataSize(structuredMetadata)
+			return nil
+		})
+	}
 	return size
 }
 
@@ -432,7 +461,7 @@ func (hb *unorderedHeadBlock) CheckpointTo(w io.Writer) error {
 		logproto.FORWARD,
 		0,
 		math.MaxInt64,
-		func(ts int64, line string) error {
+		func(ts int64, line string, structuredMetadata labels.Labels) error {
 			eb.putVarint64(ts)
 			eb.putUvarint(len(line))
 			_, err = w.Write(eb.get())
@@ -445,6 +474,19 @@ func (hb *unorderedHeadBlock) CheckpointTo(w io.Writer) error {
 			if err != nil {
 				return errors.Wrap(err, "write headblock entry line")
 			}
+
+			if hb.hasStructuredMetadata() {
+				eb.putUvarint(len(structuredMetadata))
+				for _, l := range structuredMetadata {
+					eb.putUvarintStr(l.Name)
+					eb.putUvarintStr(l.Value)
+				}
+				_, err = w.Write(eb.get())
+				if err != nil {
+					return errors.Wrap(err, "write headBlock entry structured metadata")
+				}
+				eb.reset()
+			}
 			return nil
 		},
 	)
@@ -454,7 +496,7 @@ func (hb *unorderedHeadBlock) CheckpointTo(w io.Writer) error {
 
 func (hb *unorderedHeadBlock) LoadBytes(b []byte) error {
 	// ensure it's empty
-	*hb = *newUnorderedHeadBlock()
+	*hb = *newUnorderedHeadBlock(hb.format)
 
 	if len(b) < 1 {
 		return nil
@@ -467,8 +509,11 @@ func (hb *unorderedHeadBlock) LoadBytes(b []byte) error {
 		return errors.Wrap(db.err(), "verifying headblock header")
 	}
 
-	if version != UnorderedHeadBlockFmt.Byte() {
-		return errors.Errorf("incompatible headBlock version (%v), only V4 is currently supported", version)
+	switch HeadBlockFmt(version) {
+	case UnorderedHeadBlockFmt, UnorderedWithStructuredMetadataHeadBlockFmt:
+		hb.format = HeadBlockFmt(version)
+	default:
+		return errors.Errorf("incompatible headBlock version (%v), only V4 and V5 are currently supported", version)
 	}
 
 	n := db.uvarint()
@@ -481,7 +526,18 @@ func (hb *unorderedHeadBlock) LoadBytes(b []byte) error {
 		ts := db.varint64()
 		lineLn := db.uvarint()
 		line := string(db.bytes(lineLn))
-		if err := hb.Append(ts, line); err != nil {
+
+		var structuredMetadata labels.Labels
+		if hb.hasStructuredMetadata() {
+			if n := db.uvarint(); n > 0 {
+				structuredMetadata = make(labels.Labels, n)
+				for j := range structuredMetadata {
+					structuredMetadata[j].Name = db.uvarintStr()
+					structuredMetadata[j].Value = db.uvarintStr()
+				}
+			}
+		}
+		if err := hb.Append(ts, line, structuredMetadata); err != nil {
 			return err
 		}
 	}
@@ -508,7 +564,7 @@ func HeadFromCheckpoint(b []byte, desired HeadBlockFmt) (HeadBlock, error) {
 		return nil, errors.Wrap(db.err(), "verifying headblock header")
 	}
 	format := HeadBlockFmt(version)
-	if format > UnorderedHeadBlockFmt {
+	if format > UnorderedWithStructuredMetadataHeadBlockFmt {
 		return nil, fmt.Errorf("unexpected head block version: %v", format)
 	}
 
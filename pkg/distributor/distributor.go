@@ -61,6 +61,10 @@ type Config struct {
 	factory ring_client.PoolFactory `yaml:"-"`
 
 	RateStore RateStoreConfig `yaml:"rate_store"`
+
+	// MaxRecvMsgSize limits the uncompressed size of the OTLP push requests, like the size of the push requests
+	// received over gRPC. It is set from the server configuration.
+	MaxRecvMsgSize int `yaml:"-"`
 }
 
 // RegisterFlags registers distributor-related flags.
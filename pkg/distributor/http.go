@@ -1,6 +1,7 @@
 package distributor
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
@@ -18,6 +19,15 @@ import (
 
 // PushHandler reads a snappy-compressed proto from the HTTP body.
 func (d *Distributor) PushHandler(w http.ResponseWriter, r *http.Request) {
+	d.pushHandler(w, r, push.ParseRequest)
+}
+
+// OTLPPushHandler reads OTLP logs, in protobuf or JSON, from the HTTP body.
+func (d *Distributor) OTLPPushHandler(w http.ResponseWriter, r *http.Request) {
+	d.pushHandler(w, r, push.NewOTLPRequestParser(d.validator.Limits, d.cfg.MaxRecvMsgSize))
+}
+
+func (d *Distributor) pushHandler(w http.ResponseWriter, r *http.Request, pushRequestParser push.RequestParser) {
 	logger := util_log.WithContext(r.Context(), util_log.Logger)
 	tenantID, err := tenant.TenantID(r.Context())
 	if err != nil {
@@ -25,16 +35,20 @@ func (d *Distributor) PushHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	req, err := push.ParseRequest(logger, tenantID, r, d.tenantsRetention)
+	req, err := pushRequestParser(logger, tenantID, r, d.tenantsRetention)
 	if err != nil {
+		code := http.StatusBadRequest
+		if errors.Is(err, push.ErrOTLPRequestTooLarge) {
+			code = http.StatusRequestEntityTooLarge
+		}
 		if d.tenantConfigs.LogPushRequest(tenantID) {
 			level.Debug(logger).Log(
 				"msg", "push request failed",
-				"code", http.StatusBadRequest,
+				"code", code,
 				"err", err,
 			)
 		}
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		http.Error(w, err.Error(), code)
 		return
 	}
 
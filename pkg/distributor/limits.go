@@ -22,6 +22,9 @@ type Limits interface {
 	RejectOldSamplesMaxAge(userID string) time.Duration
 
 	IncrementDuplicateTimestamps(userID string) bool
+	AllowStructuredMetadata(userID string) bool
+
+	OTLPResourceAttributesAsLabels(userID string) []string
 
 	ShardStreams(userID string) *shardstreams.Config
 	AllByUserID() map[string]*validation.Limits
@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/weaveworks/common/httpgrpc"
 
@@ -42,6 +43,8 @@ type validationContext struct {
 
 	incrementDuplicateTimestamps bool
 
+	allowStructuredMetadata bool
+
 	userID string
 }
 
@@ -57,6 +60,7 @@ func (v Validator) getValidationContextForTime(now time.Time, userID string) val
 		maxLabelNameLength:           v.MaxLabelNameLength(userID),
 		maxLabelValueLength:          v.MaxLabelValueLength(userID),
 		incrementDuplicateTimestamps: v.IncrementDuplicateTimestamps(userID),
+		allowStructuredMetadata:      v.AllowStructuredMetadata(userID),
 	}
 }
 
@@ -91,6 +95,21 @@ func (v Validator) ValidateEntry(ctx validationContext, labels string, entry log
 		return httpgrpc.Errorf(http.StatusBadRequest, validation.LineTooLongErrorMsg, maxSize, labels, len(entry.Line))
 	}
 
+	if len(entry.StructuredMetadata) > 0 {
+		if !ctx.allowStructuredMetadata {
+			validation.DiscardedSamples.WithLabelValues(validation.DisallowedStructuredMetadata, ctx.userID).Inc()
+			validation.DiscardedBytes.WithLabelValues(validation.DisallowedStructuredMetadata, ctx.userID).Add(float64(len(entry.Line)))
+			return httpgrpc.Errorf(http.StatusBadRequest, validation.DisallowedStructuredMetadataErrorMsg, labels)
+		}
+		for _, l := range entry.StructuredMetadata {
+			if !model.LabelName(l.Name).IsValid() {
+				validation.DiscardedSamples.WithLabelValues(validation.InvalidStructuredMetadata, ctx.userID).Inc()
+				validation.DiscardedBytes.WithLabelValues(validation.InvalidStructuredMetadata, ctx.userID).Add(float64(len(entry.Line)))
+				return httpgrpc.Errorf(http.StatusBadRequest, validation.InvalidStructuredMetadataErrorMsg, labels, l.Name)
+			}
+		}
+	}
+
 	return nil
 }
 
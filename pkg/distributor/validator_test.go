@@ -85,6 +85,35 @@ func TestValidator_ValidateEntry(t *testing.T) {
 			logproto.Entry{Timestamp: testTime, Line: "12345678901"},
 			httpgrpc.Errorf(http.StatusBadRequest, validation.LineTooLongErrorMsg, 10, testStreamLabels, 11),
 		},
+		{
+			"disallowed structured metadata",
+			"test",
+			nil,
+			logproto.Entry{Timestamp: testTime, Line: "test", StructuredMetadata: []logproto.LabelAdapter{{Name: "traceID", Value: "123"}}},
+			httpgrpc.Errorf(http.StatusBadRequest, validation.DisallowedStructuredMetadataErrorMsg, testStreamLabels),
+		},
+		{
+			"allowed structured metadata",
+			"test",
+			fakeLimits{
+				&validation.Limits{
+					AllowStructuredMetadata: true,
+				},
+			},
+			logproto.Entry{Timestamp: testTime, Line: "test", StructuredMetadata: []logproto.LabelAdapter{{Name: "traceID", Value: "123"}}},
+			nil,
+		},
+		{
+			"invalid structured metadata name",
+			"test",
+			fakeLimits{
+				&validation.Limits{
+					AllowStructuredMetadata: true,
+				},
+			},
+			logproto.Entry{Timestamp: testTime, Line: "test", StructuredMetadata: []logproto.LabelAdapter{{Name: "trace.id", Value: "123"}}},
+			httpgrpc.Errorf(http.StatusBadRequest, validation.InvalidStructuredMetadataErrorMsg, testStreamLabels, "trace.id"),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
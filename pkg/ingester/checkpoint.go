@@ -89,7 +89,9 @@ func toWireChunks(descs []chunkDesc, wireChunks []chunkWithBuffer) ([]chunkWithB
 	return wireChunks, nil
 }
 
-func fromWireChunks(conf *Config, wireChunks []Chunk) ([]chunkDesc, error) {
+// fromWireChunks returns the chunks of a checkpoint, with their head blocks in the format of unordered head blocks,
+// storing the structured metadata of the entries only when structuredMetadata is true or when a chunk already has some.
+func fromWireChunks(conf *Config, wireChunks []Chunk, structuredMetadata bool) ([]chunkDesc, error) {
 	descs := make([]chunkDesc, 0, len(wireChunks))
 	for _, c := range wireChunks {
 		desc := chunkDesc{
@@ -102,7 +104,7 @@ func fromWireChunks(conf *Config, wireChunks []Chunk) ([]chunkDesc, error) {
 		// Always use Unordered headblocks during replay
 		// to ensure Loki can effectively replay an unordered-friendly
 		// WAL into a new configuration that disables unordered writes.
-		hbType := chunkenc.UnorderedHeadBlockFmt
+		hbType := headBlockType(true, structuredMetadata)
 		mc, err := chunkenc.MemchunkFromCheckpoint(c.Data, c.Head, hbType, conf.BlockSize, conf.TargetChunkSize)
 		if err != nil {
 			return nil, err
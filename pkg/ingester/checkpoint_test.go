@@ -670,3 +670,97 @@ func TestIngesterWALReplaysUnorderedToOrdered(t *testing.T) {
 		})
 	}
 }
+
+func TestIngesterWALReplaysWithoutStructuredMetadata(t *testing.T) {
+	for _, tc := range []struct {
+		name                 string
+		allowedBeforeRestart bool
+		waitForCheckpoint    bool
+	}{
+		{name: "not allowed"},
+		{name: "not allowed with checkpoint", waitForCheckpoint: true},
+		{name: "disallowed after restart", allowedBeforeRestart: true},
+		{name: "disallowed after restart with checkpoint", allowedBeforeRestart: true, waitForCheckpoint: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			walDir := t.TempDir()
+
+			ingesterConfig := defaultIngesterTestConfigWithWAL(t, walDir)
+
+			newLimits := func(allowStructuredMetadata bool) *validation.Overrides {
+				dft := defaultLimitsTestConfig()
+				dft.UnorderedWrites = true
+				dft.AllowStructuredMetadata = allowStructuredMetadata
+				limits, err := validation.NewOverrides(dft, nil)
+				require.NoError(t, err)
+				return limits
+			}
+			newStore := func() *mockStore {
+				return &mockStore{
+					chunks: map[string][]chunk.Chunk{},
+				}
+			}
+
+			i, err := New(ingesterConfig, client.Config{}, newStore(), newLimits(tc.allowedBeforeRestart), runtime.DefaultTenantConfigs(), nil)
+			require.NoError(t, err)
+			require.Nil(t, services.StartAndAwaitRunning(context.Background(), i))
+			defer services.StopAndAwaitTerminated(context.Background(), i) //nolint:errcheck
+
+			req := logproto.PushRequest{
+				Streams: []logproto.Stream{
+					{
+						Labels: `{foo="bar",bar="baz1"}`,
+					},
+					{
+						Labels: `{foo="bar",bar="baz2"}`,
+					},
+				},
+			}
+
+			start := time.Now()
+			steps := 10
+			end := start.Add(time.Second * time.Duration(steps))
+			for i := 0; i < steps; i++ {
+				for j := range req.Streams {
+					req.Streams[j].Entries = append(req.Streams[j].Entries, logproto.Entry{
+						Timestamp: start.Add(time.Duration(i) * time.Second),
+						Line:      fmt.Sprintf("line %d", i),
+					})
+				}
+			}
+
+			ctx := user.InjectOrgID(context.Background(), "test")
+			_, err = i.Push(ctx, &req)
+			require.NoError(t, err)
+
+			if tc.waitForCheckpoint {
+				expectCheckpoint(t, walDir, true, ingesterConfig.WAL.CheckpointDuration*10)
+			}
+
+			require.Nil(t, services.StopAndAwaitTerminated(context.Background(), i))
+
+			// restart the ingester with structured metadata not allowed
+			i, err = New(ingesterConfig, client.Config{}, newStore(), newLimits(false), runtime.DefaultTenantConfigs(), nil)
+			require.NoError(t, err)
+			defer services.StopAndAwaitTerminated(context.Background(), i) //nolint:errcheck
+			require.Nil(t, services.StartAndAwaitRunning(context.Background(), i))
+
+			ensureIngesterData(ctx, t, start, end, i)
+
+			// The recovered chunks use the default chunk format, which can be read by older queriers.
+			inst, ok := i.getInstanceByID("test")
+			require.True(t, ok)
+			require.NoError(t, inst.forAllStreams(ctx, func(s *stream) error {
+				require.False(t, s.structuredMetadata)
+				for _, c := range s.chunks {
+					require.NoError(t, c.chunk.Close())
+					b, err := c.chunk.Bytes()
+					require.NoError(t, err)
+					// The chunk format is the byte following the magic number.
+					require.Equal(t, byte(chunkenc.DefaultChunkFormat), b[4])
+				}
+				return nil
+			}))
+		})
+	}
+}
@@ -129,6 +129,47 @@ func Test_Encoding_Entries(t *testing.T) {
 			},
 			version: WALRecordEntriesV2,
 		},
+		{
+			desc: "v3",
+			rec: &WALRecord{
+				entryIndexMap: make(map[uint64]int),
+				UserID:        "123",
+				RefEntries: []RefEntries{
+					{
+						Ref:     456,
+						Counter: 1,
+						Entries: []logproto.Entry{
+							{
+								Timestamp: time.Unix(1000, 0),
+								Line:      "first",
+							},
+							{
+								Timestamp: time.Unix(2000, 0),
+								Line:      "second",
+								StructuredMetadata: []logproto.LabelAdapter{
+									{Name: "traceID", Value: "123"},
+									{Name: "user", Value: "a"},
+								},
+							},
+						},
+					},
+					{
+						Ref:     789,
+						Counter: 2,
+						Entries: []logproto.Entry{
+							{
+								Timestamp: time.Unix(3000, 0),
+								Line:      "third",
+								StructuredMetadata: []logproto.LabelAdapter{
+									{Name: "traceID", Value: "456"},
+								},
+							},
+						},
+					},
+				},
+			},
+			version: WALRecordEntriesV3,
+		},
 	} {
 		decoded := recordPool.GetRecord()
 		buf := tc.rec.encodeEntries(tc.version, nil)
@@ -281,7 +322,7 @@ func Test_EncodingChunks(t *testing.T) {
 						}
 					}
 
-					backAgain, err := fromWireChunks(&conf, chunks)
+					backAgain, err := fromWireChunks(&conf, chunks, f == chunkenc.UnorderedWithStructuredMetadataHeadBlockFmt)
 					require.Nil(t, err)
 
 					for i, to := range backAgain {
@@ -17,7 +17,10 @@ import (
 	"github.com/grafana/dskit/tenant"
 
 	"github.com/grafana/loki/pkg/chunkenc"
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql/log"
 	"github.com/grafana/loki/pkg/storage/chunk"
+	"github.com/grafana/loki/pkg/storage/chunk/bloom"
 	"github.com/grafana/loki/pkg/util"
 	loki_util "github.com/grafana/loki/pkg/util"
 	util_log "github.com/grafana/loki/pkg/util/log"
@@ -300,6 +303,12 @@ func (i *Ingester) flushChunks(ctx context.Context, fp model.Fingerprint, labelP
 			lastTime,
 		)
 
+		if i.cfg.ChunkBloomFilters {
+			if ch.Bloom, err = buildChunkBloom(ctx, c.chunk); err != nil {
+				return fmt.Errorf("chunk bloom filter: %w", err)
+			}
+		}
+
 		// encodeChunk mutates the chunk so we must pass by reference
 		if err := i.encodeChunk(ctx, &ch, c); err != nil {
 			return err
@@ -357,6 +366,25 @@ func (i *Ingester) encodeChunk(ctx context.Context, ch *chunk.Chunk, desc *chunk
 	return nil
 }
 
+// buildChunkBloom builds the bloom filter of the lines of the given chunk, which is written alongside it.
+func buildChunkBloom(ctx context.Context, c *chunkenc.MemChunk) (*bloom.Filter, error) {
+	from, through := c.Bounds()
+	it, err := c.Iterator(ctx, from, through.Add(time.Nanosecond), logproto.FORWARD, log.NewNoopPipeline().ForStream(nil))
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+
+	b := bloom.NewBuilder()
+	for it.Next() {
+		b.Add(it.Entry().Line)
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+	return b.Build(), nil
+}
+
 // flushChunk flushes the given chunk to the store.
 //
 // If the flush is successful, metrics for this flush are to be reported.
@@ -117,6 +117,36 @@ func Test_Flush(t *testing.T) {
 	require.NoError(t, ing.flushChunks(ctx, 0, lbs, buildChunkDecs(t), &sync.RWMutex{}))
 }
 
+func Test_FlushChunkBloomFilters(t *testing.T) {
+	for _, enabled := range []bool{false, true} {
+		t.Run(fmt.Sprintf("enabled=%v", enabled), func(t *testing.T) {
+			cfg := defaultIngesterTestConfig(t)
+			cfg.ChunkBloomFilters = enabled
+			var (
+				store, ing = newTestStore(t, cfg, nil)
+				lbs        = makeRandomLabels()
+				ctx        = user.InjectOrgID(context.Background(), "foo")
+				flushed    int
+			)
+			store.onPut = func(ctx context.Context, chunks []chunk.Chunk) error {
+				for _, c := range chunks {
+					flushed++
+					if !enabled {
+						require.Nil(t, c.Bloom)
+						continue
+					}
+					require.NotNil(t, c.Bloom)
+					require.True(t, c.Bloom.MayContain("entry for line 42"))
+					require.False(t, c.Bloom.MayContain("traceID"))
+				}
+				return nil
+			}
+			require.NoError(t, ing.flushChunks(ctx, 0, lbs, buildChunkDecs(t), &sync.RWMutex{}))
+			require.Equal(t, 10, flushed)
+		})
+	}
+}
+
 func buildChunkDecs(t testing.TB) []*chunkDesc {
 	res := make([]*chunkDesc, 10)
 	for i := range res {
@@ -350,6 +380,10 @@ func (s *testStore) Stats(ctx context.Context, userID string, from, through mode
 	return &stats.Stats{}, nil
 }
 
+func (s *testStore) Volume(ctx context.Context, userID string, from, through model.Time, targetLabels []string, matchers ...*labels.Matcher) (*stats.Volumes, error) {
+	return &stats.Volumes{}, nil
+}
+
 func pushTestSamples(t *testing.T, ing logproto.PusherServer) map[string][]logproto.Stream {
 	userIDs := []string{"1", "2", "3"}
 
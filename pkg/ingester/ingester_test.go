@@ -323,6 +323,10 @@ func (s *mockStore) Stats(ctx context.Context, userID string, from, through mode
 	return &stats.Stats{}, nil
 }
 
+func (s *mockStore) Volume(ctx context.Context, userID string, from, through model.Time, targetLabels []string, matchers ...*labels.Matcher) (*stats.Volumes, error) {
+	return &stats.Volumes{}, nil
+}
+
 func (s *mockStore) Stop() {}
 
 type mockQuerierServer struct {
@@ -29,6 +29,7 @@ import (
 	"github.com/grafana/loki/pkg/runtime"
 	"github.com/grafana/loki/pkg/storage/chunk"
 	"github.com/grafana/loki/pkg/storage/config"
+	index_stats "github.com/grafana/loki/pkg/storage/stores/index/stats"
 	"github.com/grafana/loki/pkg/usagestats"
 	"github.com/grafana/loki/pkg/util"
 	"github.com/grafana/loki/pkg/util/deletion"
@@ -266,6 +267,7 @@ func (i *instance) createStream(pushReqStream logproto.Stream, record *WALRecord
 
 	sortedLabels := i.index.Add(logproto.FromLabelsToLabelAdapters(labels), fp)
 	s := newStream(i.cfg, i.limiter, i.instanceID, fp, sortedLabels, i.limiter.UnorderedWrites(i.instanceID), i.streamRateCalculator, i.metrics)
+	s.structuredMetadata = i.limiter.AllowStructuredMetadata(i.instanceID)
 
 	// record will be nil when replaying the wal (we don't want to rewrite wal entries as we replay them).
 	if record != nil {
@@ -297,6 +299,7 @@ func (i *instance) createStream(pushReqStream logproto.Stream, record *WALRecord
 func (i *instance) createStreamByFP(ls labels.Labels, fp model.Fingerprint) *stream {
 	sortedLabels := i.index.Add(logproto.FromLabelsToLabelAdapters(ls), fp)
 	s := newStream(i.cfg, i.limiter, i.instanceID, fp, sortedLabels, i.limiter.UnorderedWrites(i.instanceID), i.streamRateCalculator, i.metrics)
+	s.structuredMetadata = i.limiter.AllowStructuredMetadata(i.instanceID)
 
 	i.streamsCreatedTotal.Inc()
 	memoryStreams.WithLabelValues(i.instanceID).Inc()
@@ -588,6 +591,41 @@ func (i *instance) GetStats(ctx context.Context, req *logproto.IndexStatsRequest
 	return res, nil
 }
 
+func (i *instance) GetVolume(ctx context.Context, req *logproto.VolumeRequest) (*logproto.VolumeResponse, error) {
+	matchers, err := syntax.ParseMatchers(req.Matchers)
+	if err != nil {
+		return nil, err
+	}
+
+	acc := index_stats.NewVolumeAccumulator(req.TargetLabels, nil)
+	from, through := req.From.Time(), req.Through.Time()
+
+	if err = i.forMatchingStreams(ctx, from, matchers, nil, func(s *stream) error {
+		// Consider streams which overlap our time range
+		if shouldConsiderStream(s, from, through) {
+			acc.AddStream(s.labels, s.fp)
+
+			s.chunkMtx.RLock()
+			for _, chk := range s.chunks {
+				// Consider chunks which overlap our time range and haven't been flushed.
+				// Flushed chunks will already be counted by the TSDB manager+shipper.
+				chkFrom, chkThrough := chk.chunk.Bounds()
+
+				if chk.flushed.IsZero() && from.Before(chkThrough) && through.After(chkFrom) {
+					acc.AddChunkData(s.fp, uint64(chk.chunk.UncompressedSize()), uint64(chk.chunk.Size()))
+				}
+			}
+			s.chunkMtx.RUnlock()
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	res := acc.Volumes()
+	return &res, nil
+}
+
 func (i *instance) numStreams() int {
 	return i.streams.Len()
 }
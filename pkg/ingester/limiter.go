@@ -67,6 +67,13 @@ func (l *Limiter) UnorderedWrites(userID string) bool {
 	return l.limits.UnorderedWrites(userID)
 }
 
+// AllowStructuredMetadata returns whether the streams of the tenant store the structured metadata of the entries.
+// Unlike unordered writes, this isn't allowed while replaying the WAL unless the tenant allows it: chunks storing
+// structured metadata can't be read by older queriers, which would prevent rolling back after a restart.
+func (l *Limiter) AllowStructuredMetadata(userID string) bool {
+	return l.limits.AllowStructuredMetadata(userID)
+}
+
 // AssertMaxStreamsPerUser ensures limit has not been reached compared to the current
 // number of streams in input and returns an error if so.
 func (l *Limiter) AssertMaxStreamsPerUser(userID string, streams int) error {
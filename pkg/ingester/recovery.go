@@ -207,9 +207,10 @@ func (r *ingesterRecoverer) Close() {
 			isAllowed := r.ing.limiter.UnorderedWrites(s.tenant)
 			old := s.unorderedWrites
 			s.unorderedWrites = isAllowed
+			s.structuredMetadata = r.ing.limiter.AllowStructuredMetadata(s.tenant)
 
 			if !isAllowed && old {
-				err := s.chunks[len(s.chunks)-1].chunk.ConvertHead(headBlockType(isAllowed))
+				err := s.chunks[len(s.chunks)-1].chunk.ConvertHead(headBlockType(isAllowed, s.structuredMetadata))
 				if err != nil {
 					level.Warn(util_log.Logger).Log(
 						"msg", "error converting headblock",
@@ -70,6 +70,7 @@ type stream struct {
 	entryCt int64
 
 	unorderedWrites      bool
+	structuredMetadata   bool
 	streamRateCalculator *StreamRateCalculator
 }
 
@@ -128,7 +129,7 @@ func (s *stream) consumeChunk(_ context.Context, chunk *logproto.Chunk) error {
 func (s *stream) setChunks(chunks []Chunk) (bytesAdded, entriesAdded int, err error) {
 	s.chunkMtx.Lock()
 	defer s.chunkMtx.Unlock()
-	chks, err := fromWireChunks(s.cfg, chunks)
+	chks, err := fromWireChunks(s.cfg, chunks, s.structuredMetadata)
 	if err != nil {
 		return 0, 0, err
 	}
@@ -141,7 +142,7 @@ func (s *stream) setChunks(chunks []Chunk) (bytesAdded, entriesAdded int, err er
 }
 
 func (s *stream) NewChunk() *chunkenc.MemChunk {
-	return chunkenc.NewMemChunk(s.cfg.parsedEncoding, headBlockType(s.unorderedWrites), s.cfg.BlockSize, s.cfg.TargetChunkSize)
+	return chunkenc.NewMemChunk(s.cfg.parsedEncoding, headBlockType(s.unorderedWrites, s.structuredMetadata), s.cfg.BlockSize, s.cfg.TargetChunkSize)
 }
 
 func (s *stream) Push(
@@ -577,8 +578,13 @@ func (s *stream) resetCounter() {
 	s.entryCt = 0
 }
 
-func headBlockType(unorderedWrites bool) chunkenc.HeadBlockFmt {
+// headBlockType returns the format of the head blocks of the stream.
+// Structured metadata is only stored by unordered head blocks.
+func headBlockType(unorderedWrites, structuredMetadata bool) chunkenc.HeadBlockFmt {
 	if unorderedWrites {
+		if structuredMetadata {
+			return chunkenc.UnorderedWithStructuredMetadataHeadBlockFmt
+		}
 		return chunkenc.UnorderedHeadBlockFmt
 	}
 	return chunkenc.OrderedHeadBlockFmt
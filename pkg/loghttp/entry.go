@@ -8,16 +8,20 @@ import (
 	"github.com/buger/jsonparser"
 	jsoniter "github.com/json-iterator/go"
 	"github.com/modern-go/reflect2"
+	"github.com/prometheus/prometheus/model/labels"
 )
 
 func init() {
 	jsoniter.RegisterExtension(&jsonExtension{})
 }
 
-// Entry represents a log entry.  It includes a log message and the time it occurred at.
+// Entry represents a log entry.  It includes a log message, the time it occurred at
+// and optionally its structured metadata.
+// Its layout must match logproto.Entry as it is converted to it without copy.
 type Entry struct {
-	Timestamp time.Time
-	Line      string
+	Timestamp          time.Time
+	Line               string
+	StructuredMetadata labels.Labels
 }
 
 func (e *Entry) UnmarshalJSON(data []byte) error {
@@ -26,8 +30,11 @@ func (e *Entry) UnmarshalJSON(data []byte) error {
 		parseError error
 	)
 	_, err := jsonparser.ArrayEach(data, func(value []byte, t jsonparser.ValueType, _ int, _ error) {
-		// assert that both items in array are of type string
-		if t != jsonparser.String {
+		if parseError != nil {
+			return
+		}
+		// assert that the timestamp and the line are of type string, and the structured metadata an object
+		if i < 2 && t != jsonparser.String || i == 2 && t != jsonparser.Object {
 			parseError = jsonparser.MalformedStringError
 			return
 		}
@@ -46,6 +53,22 @@ func (e *Entry) UnmarshalJSON(data []byte) error {
 				return
 			}
 			e.Line = v
+		case 2: // structured metadata
+			var structuredMetadata labels.Labels
+			parseError = jsonparser.ObjectEach(value, func(key []byte, value []byte, t jsonparser.ValueType, _ int) error {
+				if t != jsonparser.String {
+					return jsonparser.MalformedStringError
+				}
+				v, err := jsonparser.ParseString(value)
+				if err != nil {
+					return err
+				}
+				structuredMetadata = append(structuredMetadata, labels.Label{Name: string(key), Value: v})
+				return nil
+			})
+			e.StructuredMetadata = structuredMetadata
+		default:
+			parseError = jsonparser.MalformedArrayError
 		}
 		i++
 	})
@@ -67,6 +90,7 @@ func (sliceEntryDecoder) Decode(ptr unsafe.Pointer, iter *jsoniter.Iterator) {
 		i := 0
 		var ts time.Time
 		var line string
+		var structuredMetadata labels.Labels
 		ok := iter.ReadArrayCB(func(iter *jsoniter.Iterator) bool {
 			var ok bool
 			switch i {
@@ -81,15 +105,27 @@ func (sliceEntryDecoder) Decode(ptr unsafe.Pointer, iter *jsoniter.Iterator) {
 					return false
 				}
 				return true
+			case 2:
+				iter.ReadMapCB(func(iter *jsoniter.Iterator, name string) bool {
+					value := iter.ReadString()
+					if iter.Error != nil {
+						return false
+					}
+					structuredMetadata = append(structuredMetadata, labels.Label{Name: name, Value: value})
+					return true
+				})
+				i++
+				return iter.Error == nil
 			default:
-				iter.ReportError("error reading entry", "array must contains 2 values")
+				iter.ReportError("error reading entry", "array must contains 2 or 3 values")
 				return false
 			}
 		})
 		if ok {
 			*((*[]Entry)(ptr)) = append(*((*[]Entry)(ptr)), Entry{
-				Timestamp: ts,
-				Line:      line,
+				Timestamp:          ts,
+				Line:               line,
+				StructuredMetadata: structuredMetadata,
 			})
 			return true
 		}
@@ -126,6 +162,18 @@ func (EntryEncoder) Encode(ptr unsafe.Pointer, stream *jsoniter.Stream) {
 	stream.WriteRaw(`"`)
 	stream.WriteMore()
 	stream.WriteStringWithHTMLEscaped(e.Line)
+	if len(e.StructuredMetadata) > 0 {
+		stream.WriteMore()
+		stream.WriteObjectStart()
+		for i, l := range e.StructuredMetadata {
+			if i > 0 {
+				stream.WriteMore()
+			}
+			stream.WriteObjectField(l.Name)
+			stream.WriteStringWithHTMLEscaped(l.Value)
+		}
+		stream.WriteObjectEnd()
+	}
 	stream.WriteArrayEnd()
 }
 
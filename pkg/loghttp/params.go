@@ -35,7 +35,7 @@ func query(r *http.Request) string {
 }
 
 func ts(r *http.Request) (time.Time, error) {
-	return parseTimestamp(r.Form.Get("time"), time.Now())
+	return ParseTimestamp(r.Form.Get("time"), time.Now())
 }
 
 func direction(r *http.Request) (logproto.Direction, error) {
@@ -48,11 +48,11 @@ func shards(r *http.Request) []string {
 
 func bounds(r *http.Request) (time.Time, time.Time, error) {
 	now := time.Now()
-	start, err := parseTimestamp(r.Form.Get("start"), now.Add(-defaultSince))
+	start, err := ParseTimestamp(r.Form.Get("start"), now.Add(-defaultSince))
 	if err != nil {
 		return time.Time{}, time.Time{}, err
 	}
-	end, err := parseTimestamp(r.Form.Get("end"), now)
+	end, err := ParseTimestamp(r.Form.Get("end"), now)
 	if err != nil {
 		return time.Time{}, time.Time{}, err
 	}
@@ -98,9 +98,10 @@ func parseInt(value string, def int) (int, error) {
 	return strconv.Atoi(value)
 }
 
-// parseUnixNano parses a ns unix timestamp from a string
-// if the value is empty it returns a default value passed as second parameter
-func parseTimestamp(value string, def time.Time) (time.Time, error) {
+// ParseTimestamp parses a timestamp from a string: a ns or s unix timestamp, a s unix timestamp with decimals,
+// or a RFC3339Nano timestamp.
+// If the value is empty it returns a default value passed as second parameter
+func ParseTimestamp(value string, def time.Time) (time.Time, error) {
 	if value == "" {
 		return def, nil
 	}
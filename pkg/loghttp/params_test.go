@@ -212,13 +212,13 @@ func Test_parseTimestamp(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := parseTimestamp(tt.value, tt.def)
+			got, err := ParseTimestamp(tt.value, tt.def)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("parseTimestamp() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("ParseTimestamp() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("parseTimestamp() = %v, want %v", got, tt.want)
+				t.Errorf("ParseTimestamp() = %v, want %v", got, tt.want)
 			}
 		})
 	}
@@ -0,0 +1,233 @@
+package push
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-logfmt/logfmt"
+	"github.com/prometheus/prometheus/model/labels"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
+
+	"github.com/grafana/loki/pkg/logproto"
+)
+
+const (
+	// otlpDefaultServiceName is the label set to streams which have none of the allowed resource attributes,
+	// as a stream requires at least one label.
+	otlpDefaultServiceName = "unknown_service"
+	otlpServiceNameLabel   = "service_name"
+)
+
+// ErrOTLPRequestTooLarge is returned when the uncompressed body of an OTLP export request is larger than the limit.
+var ErrOTLPRequestTooLarge = errors.New("received message larger than max")
+
+// OTLPLimits are the per tenant limits used to convert OTLP logs into streams.
+type OTLPLimits interface {
+	// OTLPResourceAttributesAsLabels returns the resource attributes which are converted to stream labels.
+	OTLPResourceAttributesAsLabels(userID string) []string
+}
+
+// NewOTLPRequestParser returns a RequestParser for OTLP logs export requests, sent as protobuf or JSON.
+//
+// Log records are grouped in streams labeled by the resource attributes allowed by the limits of the tenant,
+// with dots replaced by underscores. The line of an entry is the body of the log record followed by the remaining
+// attributes of the resource, the scope and the log record, as well as the severity and the trace context,
+// in logfmt. The uncompressed body of the requests is limited to maxRequestSize bytes.
+func NewOTLPRequestParser(limits OTLPLimits, maxRequestSize int) RequestParser {
+	return func(logger log.Logger, userID string, r *http.Request, tenantsRetention TenantsRetention) (*logproto.PushRequest, error) {
+		labelAttributes := limits.OTLPResourceAttributesAsLabels(userID)
+		return parseRequest(logger, userID, r, tenantsRetention, func(_ *http.Request, body io.Reader, contentType string) (*logproto.PushRequest, error) {
+			logs, err := DecodeOTLPLogs(body, contentType, maxRequestSize)
+			if err != nil {
+				return nil, err
+			}
+			return otlpToPushRequest(logs, labelAttributes, time.Now()), nil
+		})
+	}
+}
+
+// DecodeOTLPLogs decodes the logs of an export request, failing with ErrOTLPRequestTooLarge when the uncompressed body
+// is larger than maxSize. Bodies are decoded as JSON when the content type is JSON, and as protobuf otherwise.
+func DecodeOTLPLogs(body io.Reader, contentType string, maxSize int) (plog.Logs, error) {
+	// Read at most maxSize+1 bytes, so that a body over the limit is detected without reading it whole.
+	b, err := io.ReadAll(io.LimitReader(body, int64(maxSize)+1))
+	if err != nil {
+		return plog.Logs{}, err
+	}
+	if len(b) > maxSize {
+		return plog.Logs{}, fmt.Errorf("%w (%d vs %d)", ErrOTLPRequestTooLarge, len(b), maxSize)
+	}
+
+	req := plogotlp.NewExportRequest()
+	switch contentType {
+	case applicationJSON:
+		if err := req.UnmarshalJSON(b); err != nil {
+			return plog.Logs{}, fmt.Errorf("decoding OTLP JSON logs: %w", err)
+		}
+	default:
+		// OTLP/HTTP requests are sent as protobuf when they are not JSON.
+		if err := req.UnmarshalProto(b); err != nil {
+			return plog.Logs{}, fmt.Errorf("decoding OTLP protobuf logs: %w", err)
+		}
+	}
+	return req.Logs(), nil
+}
+
+func otlpToPushRequest(logs plog.Logs, labelAttributes []string, now time.Time) *logproto.PushRequest {
+	allowed := make(map[string]struct{}, len(labelAttributes))
+	for _, a := range labelAttributes {
+		allowed[a] = struct{}{}
+	}
+
+	var (
+		req     logproto.PushRequest
+		streams = map[string]int{}
+		lb      = labels.NewBuilder(nil)
+	)
+	rls := logs.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		lb.Reset(nil)
+		resourceAttributes := pcommon.NewMap()
+		rl.Resource().Attributes().Range(func(k string, v pcommon.Value) bool {
+			if _, ok := allowed[k]; ok {
+				if s := v.AsString(); s != "" {
+					lb.Set(OTLPAttributeName(k), s)
+					return true
+				}
+			}
+			v.CopyTo(resourceAttributes.PutEmpty(k))
+			return true
+		})
+		lbs := lb.Labels(nil)
+		if len(lbs) == 0 {
+			lbs = labels.Labels{{Name: otlpServiceNameLabel, Value: otlpDefaultServiceName}}
+		}
+
+		key := lbs.String()
+		idx, ok := streams[key]
+		if !ok {
+			idx = len(req.Streams)
+			streams[key] = idx
+			req.Streams = append(req.Streams, logproto.Stream{Labels: key})
+		}
+
+		sls := rl.ScopeLogs()
+		for j := 0; j < sls.Len(); j++ {
+			sl := sls.At(j)
+			records := sl.LogRecords()
+			for k := 0; k < records.Len(); k++ {
+				lr := records.At(k)
+				req.Streams[idx].Entries = append(req.Streams[idx].Entries, logproto.Entry{
+					Timestamp: OTLPTimestamp(lr, now),
+					Line:      otlpLogLine(lr, sl.Scope(), resourceAttributes),
+				})
+			}
+		}
+	}
+	return &req
+}
+
+// otlpLogLine returns the body of the log record followed by the attributes which are not labels in logfmt.
+func otlpLogLine(lr plog.LogRecord, scope pcommon.InstrumentationScope, resourceAttributes pcommon.Map) string {
+	var keyvals []interface{}
+	appendAttributes := func(attributes pcommon.Map) {
+		attributes.Range(func(k string, v pcommon.Value) bool {
+			keyvals = append(keyvals, OTLPAttributeName(k), v.AsString())
+			return true
+		})
+	}
+	appendAttributes(resourceAttributes)
+	if scope.Name() != "" {
+		keyvals = append(keyvals, "scope_name", scope.Name())
+	}
+	if scope.Version() != "" {
+		keyvals = append(keyvals, "scope_version", scope.Version())
+	}
+	appendAttributes(scope.Attributes())
+	appendAttributes(lr.Attributes())
+	if severity := otlpSeverity(lr); severity != "" {
+		keyvals = append(keyvals, "severity", severity)
+	}
+	if traceID := lr.TraceID(); !traceID.IsEmpty() {
+		keyvals = append(keyvals, "trace_id", traceID.HexString())
+	}
+	if spanID := lr.SpanID(); !spanID.IsEmpty() {
+		keyvals = append(keyvals, "span_id", spanID.HexString())
+	}
+
+	body := lr.Body().AsString()
+	if len(keyvals) == 0 {
+		return body
+	}
+	var buf bytes.Buffer
+	buf.WriteString(body)
+	if body != "" {
+		buf.WriteByte(' ')
+	}
+	// keys are sanitized, so encoding can't fail.
+	_ = logfmt.NewEncoder(&buf).EncodeKeyvals(keyvals...)
+	return buf.String()
+}
+
+// OTLPAttributeName converts an attribute name into a valid label name, replacing invalid characters with underscores.
+func OTLPAttributeName(name string) string {
+	var sb strings.Builder
+	for i, r := range name {
+		switch {
+		case r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z'):
+			sb.WriteRune(r)
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				sb.WriteByte('_')
+			}
+			sb.WriteRune(r)
+		default:
+			sb.WriteByte('_')
+		}
+	}
+	return sb.String()
+}
+
+// OTLPTimestamp returns the time of the log record, or the time it was observed when unknown.
+func OTLPTimestamp(lr plog.LogRecord, now time.Time) time.Time {
+	switch {
+	case lr.Timestamp() != 0:
+		return lr.Timestamp().AsTime()
+	case lr.ObservedTimestamp() != 0:
+		return lr.ObservedTimestamp().AsTime()
+	default:
+		return now.UTC()
+	}
+}
+
+// otlpSeverity returns the severity text of the log record, or the short name of its severity number.
+func otlpSeverity(lr plog.LogRecord) string {
+	if text := lr.SeverityText(); text != "" {
+		return text
+	}
+	switch number := lr.SeverityNumber(); {
+	case number <= plog.SeverityNumberUnspecified:
+		return ""
+	case number <= plog.SeverityNumberTrace4:
+		return "TRACE"
+	case number <= plog.SeverityNumberDebug4:
+		return "DEBUG"
+	case number <= plog.SeverityNumberInfo4:
+		return "INFO"
+	case number <= plog.SeverityNumberWarn4:
+		return "WARN"
+	case number <= plog.SeverityNumberError4:
+		return "ERROR"
+	default:
+		return "FATAL"
+	}
+}
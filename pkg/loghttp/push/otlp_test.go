@@ -0,0 +1,187 @@
+package push
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
+
+	"github.com/grafana/loki/pkg/logproto"
+	util_log "github.com/grafana/loki/pkg/util/log"
+)
+
+type fakeOTLPLimits []string
+
+func (l fakeOTLPLimits) OTLPResourceAttributesAsLabels(string) []string {
+	return l
+}
+
+const otlpJSONLogs = `{
+  "resourceLogs": [
+    {
+      "resource": {
+        "attributes": [
+          {"key": "service.name", "value": {"stringValue": "checkout"}},
+          {"key": "host.name", "value": {"stringValue": "node-1"}}
+        ]
+      },
+      "scopeLogs": [
+        {
+          "scope": {"name": "checkout-logger", "version": "1.0"},
+          "logRecords": [
+            {
+              "timeUnixNano": "1570818238000000000",
+              "severityNumber": 17,
+              "body": {"stringValue": "payment failed"},
+              "attributes": [
+                {"key": "http.status_code", "value": {"intValue": "502"}},
+                {"key": "retry", "value": {"boolValue": true}},
+                {"key": "tags", "value": {"arrayValue": {"values": [{"stringValue": "a"}, {"doubleValue": 1.5}]}}}
+              ],
+              "traceId": "5b8efff798038103d269b633813fc60c",
+              "spanId": "eee19b7ec3c1b174"
+            }
+          ]
+        }
+      ]
+    },
+    {
+      "resource": {
+        "attributes": [
+          {"key": "service.name", "value": {"stringValue": "checkout"}}
+        ]
+      },
+      "scopeLogs": [
+        {
+          "logRecords": [
+            {"observedTimeUnixNano": 1570818239000000000, "severityText": "info", "body": {"stringValue": "done"}}
+          ]
+        }
+      ]
+    },
+    {
+      "scopeLogs": [
+        {
+          "logRecords": [
+            {"timeUnixNano": "1570818240000000000", "body": {"kvlistValue": {"values": [{"key": "msg", "value": {"stringValue": "hello"}}]}}}
+          ]
+        }
+      ]
+    }
+  ]
+}`
+
+var expectedOTLPStreams = []logproto.Stream{
+	{
+		Labels: `{service_name="checkout"}`,
+		Entries: []logproto.Entry{
+			{
+				Timestamp: time.Unix(0, 1570818238000000000).UTC(),
+				Line:      `payment failed host_name=node-1 scope_name=checkout-logger scope_version=1.0 http_status_code=502 retry=true tags="[\"a\",1.5]" severity=ERROR trace_id=5b8efff798038103d269b633813fc60c span_id=eee19b7ec3c1b174`,
+			},
+			{
+				Timestamp: time.Unix(0, 1570818239000000000).UTC(),
+				Line:      `done severity=info`,
+			},
+		},
+	},
+	{
+		Labels: `{service_name="unknown_service"}`,
+		Entries: []logproto.Entry{
+			{
+				Timestamp: time.Unix(0, 1570818240000000000).UTC(),
+				Line:      `{"msg":"hello"}`,
+			},
+		},
+	},
+}
+
+func TestParseOTLPRequest_JSON(t *testing.T) {
+	request := httptest.NewRequest("POST", "/otlp/v1/logs", strings.NewReader(gzipString(otlpJSONLogs)))
+	request.Header.Add("Content-Type", "application/json")
+	request.Header.Add("Content-Encoding", "gzip")
+
+	req, err := NewOTLPRequestParser(fakeOTLPLimits{"service.name"}, 1<<20)(util_log.Logger, "fake", request, nil)
+	require.NoError(t, err)
+	require.Equal(t, expectedOTLPStreams, req.Streams)
+}
+
+func TestParseOTLPRequest_Protobuf(t *testing.T) {
+	logs := plog.NewLogs()
+
+	rl := logs.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("service.name", "checkout")
+	rl.Resource().Attributes().PutStr("host.name", "node-1")
+	sl := rl.ScopeLogs().AppendEmpty()
+	sl.Scope().SetName("checkout-logger")
+	sl.Scope().SetVersion("1.0")
+	record := sl.LogRecords().AppendEmpty()
+	record.SetTimestamp(pcommon.Timestamp(1570818238000000000))
+	record.SetSeverityNumber(plog.SeverityNumberError)
+	record.Body().SetStr("payment failed")
+	record.Attributes().PutInt("http.status_code", 502)
+	record.Attributes().PutBool("retry", true)
+	tags := record.Attributes().PutEmptySlice("tags")
+	tags.AppendEmpty().SetStr("a")
+	tags.AppendEmpty().SetDouble(1.5)
+	record.SetTraceID(pcommon.TraceID{0x5b, 0x8e, 0xff, 0xf7, 0x98, 0x03, 0x81, 0x03, 0xd2, 0x69, 0xb6, 0x33, 0x81, 0x3f, 0xc6, 0x0c})
+	record.SetSpanID(pcommon.SpanID{0xee, 0xe1, 0x9b, 0x7e, 0xc3, 0xc1, 0xb1, 0x74})
+
+	rl = logs.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("service.name", "checkout")
+	record = rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	record.SetObservedTimestamp(pcommon.Timestamp(1570818239000000000))
+	record.SetSeverityText("info")
+	record.Body().SetStr("done")
+
+	record = logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	record.SetTimestamp(pcommon.Timestamp(1570818240000000000))
+	record.Body().SetEmptyMap().PutStr("msg", "hello")
+
+	body, err := plogotlp.NewExportRequestFromLogs(logs).MarshalProto()
+	require.NoError(t, err)
+
+	request := httptest.NewRequest("POST", "/otlp/v1/logs", strings.NewReader(string(body)))
+	request.Header.Add("Content-Type", "application/x-protobuf")
+
+	req, err := NewOTLPRequestParser(fakeOTLPLimits{"service.name"}, 1<<20)(util_log.Logger, "fake", request, nil)
+	require.NoError(t, err)
+	require.Equal(t, expectedOTLPStreams, req.Streams)
+
+	request = httptest.NewRequest("POST", "/otlp/v1/logs", strings.NewReader(string(body[:len(body)-1])))
+	request.Header.Add("Content-Type", "application/x-protobuf")
+	_, err = NewOTLPRequestParser(fakeOTLPLimits{"service.name"}, 1<<20)(util_log.Logger, "fake", request, nil)
+	require.Error(t, err)
+
+	request = httptest.NewRequest("POST", "/otlp/v1/logs", strings.NewReader(string(body)))
+	request.Header.Add("Content-Type", "application/x-protobuf")
+	_, err = NewOTLPRequestParser(fakeOTLPLimits{"service.name"}, len(body)-1)(util_log.Logger, "fake", request, nil)
+	require.ErrorIs(t, err, ErrOTLPRequestTooLarge)
+}
+
+func TestDecodeOTLPLogs_MaxSize(t *testing.T) {
+	_, err := DecodeOTLPLogs(strings.NewReader(otlpJSONLogs), "application/json", len(otlpJSONLogs))
+	require.NoError(t, err)
+
+	_, err = DecodeOTLPLogs(strings.NewReader(otlpJSONLogs), "application/json", len(otlpJSONLogs)-1)
+	require.EqualError(t, err, fmt.Sprintf("received message larger than max (%d vs %d)", len(otlpJSONLogs), len(otlpJSONLogs)-1))
+	require.ErrorIs(t, err, ErrOTLPRequestTooLarge)
+}
+
+func TestOTLPAttributeName(t *testing.T) {
+	for in, expected := range map[string]string{
+		"service.name":    "service_name",
+		"k8s.pod.name":    "k8s_pod_name",
+		"http-method":     "http_method",
+		"0day":            "_0day",
+		"already_a_label": "already_a_label",
+	} {
+		require.Equal(t, expected, OTLPAttributeName(in))
+	}
+}
@@ -51,7 +51,47 @@ type TenantsRetention interface {
 	RetentionPeriodFor(userID string, lbs labels.Labels) time.Duration
 }
 
+// RequestParser parses the push request of a tenant.
+type RequestParser func(logger log.Logger, userID string, r *http.Request, tenantsRetention TenantsRetention) (*logproto.PushRequest, error)
+
+// requestDecoder decodes the uncompressed body of a push request.
+type requestDecoder func(r *http.Request, body io.Reader, contentType string) (*logproto.PushRequest, error)
+
+// ParseRequest parses a Loki push request, sent as snappy-compressed protobuf or JSON.
 func ParseRequest(logger log.Logger, userID string, r *http.Request, tenantsRetention TenantsRetention) (*logproto.PushRequest, error) {
+	return parseRequest(logger, userID, r, tenantsRetention, decodeLokiRequest)
+}
+
+func decodeLokiRequest(r *http.Request, body io.Reader, contentType string) (*logproto.PushRequest, error) {
+	var req logproto.PushRequest
+	switch contentType {
+	case applicationJSON:
+
+		var err error
+
+		// todo once https://github.com/weaveworks/common/commit/73225442af7da93ec8f6a6e2f7c8aafaee3f8840 is in Loki.
+		// We can try to pass the body as bytes.buffer instead to avoid reading into another buffer.
+		if loghttp.GetVersion(r.RequestURI) == loghttp.VersionV1 {
+			err = unmarshal.DecodePushRequest(body, &req)
+		} else {
+			err = unmarshal2.DecodePushRequest(body, &req)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+	default:
+		// When no content-type header is set or when it is set to
+		// `application/x-protobuf`: expect snappy compression.
+		if err := util.ParseProtoReader(r.Context(), body, int(r.ContentLength), math.MaxInt32, &req, util.RawSnappy); err != nil {
+			return nil, err
+		}
+	}
+	return &req, nil
+}
+
+func parseRequest(logger log.Logger, userID string, r *http.Request, tenantsRetention TenantsRetention, decode requestDecoder) (*logproto.PushRequest, error) {
 	// Body
 	var body io.Reader
 	// bodySize should always reflect the compressed size of the request body
@@ -85,7 +125,6 @@ func ParseRequest(logger log.Logger, userID string, r *http.Request, tenantsRete
 		entriesSize      int64
 		streamLabelsSize int64
 		totalEntries     int64
-		req              logproto.PushRequest
 	)
 
 	contentType, _ /* params */, err := mime.ParseMediaType(contentType)
@@ -93,29 +132,9 @@ func ParseRequest(logger log.Logger, userID string, r *http.Request, tenantsRete
 		return nil, err
 	}
 
-	switch contentType {
-	case applicationJSON:
-
-		var err error
-
-		// todo once https://github.com/weaveworks/common/commit/73225442af7da93ec8f6a6e2f7c8aafaee3f8840 is in Loki.
-		// We can try to pass the body as bytes.buffer instead to avoid reading into another buffer.
-		if loghttp.GetVersion(r.RequestURI) == loghttp.VersionV1 {
-			err = unmarshal.DecodePushRequest(body, &req)
-		} else {
-			err = unmarshal2.DecodePushRequest(body, &req)
-		}
-
-		if err != nil {
-			return nil, err
-		}
-
-	default:
-		// When no content-type header is set or when it is set to
-		// `application/x-protobuf`: expect snappy compression.
-		if err := util.ParseProtoReader(r.Context(), body, int(r.ContentLength), math.MaxInt32, &req, util.RawSnappy); err != nil {
-			return nil, err
-		}
+	req, err := decode(r, body, contentType)
+	if err != nil {
+		return nil, err
 	}
 
 	mostRecentEntry := time.Unix(0, 0)
@@ -160,5 +179,5 @@ func ParseRequest(logger log.Logger, userID string, r *http.Request, tenantsRete
 		"totalSize", humanize.Bytes(uint64(entriesSize+streamLabelsSize)),
 		"mostRecentLagMs", time.Since(mostRecentEntry).Milliseconds(),
 	)
-	return &req, nil
+	return req, nil
 }
@@ -0,0 +1,62 @@
+package loghttp
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// VolumeQuery defines a query for the volume of the streams matching a selector,
+// aggregated by the values of some of their labels.
+type VolumeQuery struct {
+	Start        time.Time
+	End          time.Time
+	Query        string
+	TargetLabels []string
+	Limit        uint32
+	// Streams tells whether the volume of each stream is returned as well.
+	Streams bool
+}
+
+// ParseVolumeQuery parses a VolumeQuery request from an http request.
+func ParseVolumeQuery(r *http.Request) (*VolumeQuery, error) {
+	var result VolumeQuery
+	var err error
+
+	result.Query = query(r)
+	result.Start, result.End, err = bounds(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.End.Before(result.Start) {
+		return nil, errEndBeforeStart
+	}
+
+	result.Limit, err = limit(r)
+	if err != nil {
+		return nil, err
+	}
+
+	result.TargetLabels = targetLabels(r)
+
+	if s := r.Form.Get("streams"); s != "" {
+		result.Streams, err = strconv.ParseBool(s)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &result, nil
+}
+
+// targetLabels returns the labels given as a comma separated list in the targetLabels parameter.
+func targetLabels(r *http.Request) []string {
+	var res []string
+	for _, l := range strings.Split(r.Form.Get("targetLabels"), ",") {
+		if l = strings.TrimSpace(l); l != "" {
+			res = append(res, l)
+		}
+	}
+	return res
+}
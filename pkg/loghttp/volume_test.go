@@ -0,0 +1,44 @@
+package loghttp
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVolumeQuery(t *testing.T) {
+	req, err := ParseVolumeQuery(withForm(url.Values{
+		"query":        []string{`{app="foo"}`},
+		"start":        []string{"1000"},
+		"end":          []string{"2000"},
+		"targetLabels": []string{"namespace, app"},
+		"limit":        []string{"10"},
+		"streams":      []string{"true"},
+	}))
+	require.NoError(t, err)
+	require.Equal(t, &VolumeQuery{
+		Start:        time.Unix(1000, 0),
+		End:          time.Unix(2000, 0),
+		Query:        `{app="foo"}`,
+		TargetLabels: []string{"namespace", "app"},
+		Limit:        10,
+		Streams:      true,
+	}, req)
+
+	req, err = ParseVolumeQuery(withForm(url.Values{
+		"query": []string{`{app="foo"}`},
+	}))
+	require.NoError(t, err)
+	require.Nil(t, req.TargetLabels)
+	require.Equal(t, uint32(defaultQueryLimit), req.Limit)
+	require.False(t, req.Streams)
+
+	_, err = ParseVolumeQuery(withForm(url.Values{
+		"query": []string{`{app="foo"}`},
+		"start": []string{"2000"},
+		"end":   []string{"1000"},
+	}))
+	require.Error(t, err)
+}
@@ -28,29 +28,30 @@ const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
 func init() { proto.RegisterFile("pkg/logproto/indexgateway.proto", fileDescriptor_d27585148d0a52c8) }
 
 var fileDescriptor_d27585148d0a52c8 = []byte{
-	// 351 bytes of a gzipped FileDescriptorProto
+	// 368 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x92, 0xc1, 0x4a, 0xfb, 0x30,
-	0x1c, 0xc7, 0x13, 0xfe, 0xf0, 0x47, 0xa3, 0x78, 0x08, 0xc2, 0xa4, 0xd3, 0x08, 0xe2, 0x41, 0x2f,
-	0xab, 0xe8, 0x1b, 0x28, 0xac, 0x0c, 0xa6, 0xe0, 0x04, 0x0f, 0x3b, 0x88, 0xe9, 0xfc, 0x2d, 0x2b,
-	0xeb, 0x9a, 0xda, 0xa6, 0xe8, 0x6e, 0x3e, 0x82, 0x8f, 0xe1, 0xa3, 0x78, 0xdc, 0x71, 0x47, 0x97,
-	0x5d, 0x3c, 0xee, 0xec, 0x49, 0xda, 0xd2, 0x2d, 0x1b, 0x1d, 0x78, 0x6a, 0xfa, 0xfd, 0x7e, 0xf2,
-	0xf9, 0xd1, 0x26, 0xe4, 0x30, 0xec, 0x0b, 0xdb, 0x97, 0x22, 0x8c, 0xa4, 0x92, 0xb6, 0x17, 0x3c,
-	0xc1, 0xab, 0xe0, 0x0a, 0x5e, 0xf8, 0xb0, 0x96, 0x45, 0x74, 0xc7, 0xcc, 0x42, 0xd7, 0xda, 0x15,
-	0x52, 0xc8, 0x9c, 0x4e, 0x57, 0x39, 0x65, 0x55, 0x97, 0x34, 0xc5, 0x22, 0x2f, 0xcf, 0x7f, 0xfe,
-	0x91, 0xed, 0x46, 0x6a, 0x71, 0x72, 0x0b, 0x6d, 0x10, 0x72, 0x9b, 0x40, 0x34, 0xcc, 0x42, 0x5a,
-	0xad, 0xcd, 0xf9, 0x45, 0xda, 0x82, 0xe7, 0x04, 0x62, 0x65, 0xed, 0x97, 0x97, 0x71, 0x28, 0x83,
-	0x18, 0xce, 0x30, 0x6d, 0x92, 0x2d, 0x07, 0xd4, 0x55, 0x2f, 0x09, 0xfa, 0x2d, 0xe8, 0x52, 0x03,
-	0x37, 0xe2, 0x42, 0x76, 0xb0, 0xa6, 0xcd, 0x6d, 0x47, 0x88, 0xd6, 0xc9, 0xa6, 0x03, 0xea, 0x0e,
-	0x22, 0x0f, 0x62, 0x6a, 0x2d, 0xd1, 0x79, 0x58, 0x98, 0xaa, 0xa5, 0xdd, 0xdc, 0xf3, 0x40, 0x2a,
-	0x4d, 0xee, 0x82, 0x7f, 0xc3, 0x07, 0x10, 0xd7, 0x65, 0x74, 0x0d, 0x2a, 0xf2, 0x3a, 0xe9, 0x1b,
-	0x3d, 0x59, 0xec, 0x5c, 0x83, 0x14, 0x33, 0x2a, 0x2b, 0xa4, 0xe1, 0x7f, 0x24, 0x7b, 0x59, 0x74,
-	0xcf, 0xfd, 0x64, 0x75, 0xc0, 0xe9, 0xca, 0xb6, 0x12, 0xe6, 0x0f, 0x13, 0x1c, 0xb2, 0x91, 0x7e,
-	0x98, 0xe2, 0x2a, 0x36, 0x0f, 0x28, 0xfb, 0xfd, 0x59, 0x5a, 0x72, 0x40, 0x66, 0x59, 0x88, 0x2e,
-	0xdb, 0xa3, 0x09, 0x43, 0xe3, 0x09, 0x43, 0xb3, 0x09, 0xc3, 0x6f, 0x9a, 0xe1, 0x0f, 0xcd, 0xf0,
-	0xa7, 0x66, 0x78, 0xa4, 0x19, 0xfe, 0xd2, 0x0c, 0x7f, 0x6b, 0x86, 0x66, 0x9a, 0xe1, 0xf7, 0x29,
-	0x43, 0xa3, 0x29, 0x43, 0xe3, 0x29, 0x43, 0xed, 0x63, 0xe1, 0xa9, 0x5e, 0xe2, 0xd6, 0x3a, 0x72,
-	0x60, 0x8b, 0x88, 0x77, 0x79, 0xc0, 0x6d, 0x5f, 0xf6, 0x3d, 0xdb, 0xbc, 0x67, 0xee, 0xff, 0xec,
-	0x71, 0xf1, 0x1b, 0x00, 0x00, 0xff, 0xff, 0xbb, 0x22, 0x04, 0xe7, 0xc5, 0x02, 0x00, 0x00,
+	0x1c, 0xc7, 0x1b, 0xf8, 0xf3, 0x47, 0xa3, 0x78, 0x08, 0xc2, 0x46, 0xa7, 0x11, 0xc4, 0x83, 0x5e,
+	0x56, 0xd1, 0x17, 0x10, 0x85, 0x95, 0xc1, 0x14, 0x9c, 0xb0, 0xc3, 0x0e, 0x62, 0x3a, 0x7f, 0xeb,
+	0xca, 0xba, 0xa6, 0xb6, 0x29, 0xba, 0x9b, 0x8f, 0xe0, 0x63, 0xf8, 0x10, 0x3e, 0x80, 0xc7, 0x1d,
+	0x77, 0x74, 0xdd, 0xc5, 0xe3, 0x1e, 0x41, 0x9a, 0xd0, 0x2d, 0x9b, 0x1d, 0x78, 0x6a, 0xfa, 0xf9,
+	0x7e, 0xf3, 0xf9, 0xd1, 0xa4, 0xf8, 0x20, 0xec, 0xbb, 0x96, 0xcf, 0xdd, 0x30, 0xe2, 0x82, 0x5b,
+	0x5e, 0xf0, 0x08, 0x2f, 0x2e, 0x13, 0xf0, 0xcc, 0x86, 0x55, 0x89, 0xc8, 0x8e, 0xce, 0x42, 0xc7,
+	0xdc, 0x75, 0xb9, 0xcb, 0x55, 0x3b, 0x5b, 0xa9, 0x96, 0x59, 0x59, 0xd2, 0xe4, 0x0b, 0x15, 0x9e,
+	0x7d, 0xfc, 0xc3, 0xdb, 0xf5, 0xcc, 0x62, 0x2b, 0x0b, 0xa9, 0x63, 0x7c, 0x9b, 0x40, 0x34, 0x94,
+	0x90, 0x54, 0xaa, 0xf3, 0xfe, 0x82, 0x36, 0xe1, 0x29, 0x81, 0x58, 0x98, 0x7b, 0xc5, 0x61, 0x1c,
+	0xf2, 0x20, 0x86, 0x53, 0x44, 0x1a, 0x78, 0xcb, 0x06, 0x71, 0xd5, 0x4b, 0x82, 0x7e, 0x13, 0xba,
+	0x44, 0xab, 0x6b, 0x38, 0x97, 0xed, 0xaf, 0x49, 0x95, 0xed, 0xd0, 0x20, 0x35, 0xbc, 0x69, 0x83,
+	0xb8, 0x83, 0xc8, 0x83, 0x98, 0x98, 0x4b, 0x6d, 0x05, 0x73, 0x53, 0xa5, 0x30, 0x9b, 0x7b, 0xee,
+	0x71, 0xa9, 0xc1, 0x1c, 0xf0, 0x6f, 0xd8, 0x00, 0xe2, 0x1a, 0x8f, 0xae, 0x41, 0x44, 0x5e, 0x27,
+	0x7b, 0x23, 0xc7, 0x8b, 0x9d, 0x6b, 0x2a, 0xf9, 0x8c, 0xd2, 0x4a, 0x53, 0xf3, 0x3f, 0xe0, 0xb2,
+	0x44, 0x2d, 0xe6, 0x27, 0xab, 0x03, 0x4e, 0x56, 0xb6, 0x15, 0x74, 0xfe, 0x30, 0xc1, 0xc6, 0x1b,
+	0xd9, 0x87, 0x09, 0x26, 0x62, 0xfd, 0x82, 0xe4, 0xf1, 0x4b, 0x5a, 0x70, 0x41, 0x7a, 0x38, 0x17,
+	0x5d, 0xc8, 0x23, 0x6d, 0x71, 0x3f, 0x19, 0x00, 0xd1, 0x06, 0x2a, 0x92, 0x5b, 0xca, 0xbf, 0x83,
+	0xdc, 0x70, 0xd9, 0x1e, 0x4d, 0xa8, 0x31, 0x9e, 0x50, 0x63, 0x36, 0xa1, 0xe8, 0x35, 0xa5, 0xe8,
+	0x3d, 0xa5, 0xe8, 0x33, 0xa5, 0x68, 0x94, 0x52, 0xf4, 0x95, 0x52, 0xf4, 0x9d, 0x52, 0x63, 0x96,
+	0x52, 0xf4, 0x36, 0xa5, 0xc6, 0x68, 0x4a, 0x8d, 0xf1, 0x94, 0x1a, 0xed, 0x23, 0xd7, 0x13, 0xbd,
+	0xc4, 0xa9, 0x76, 0xf8, 0xc0, 0x72, 0x23, 0xd6, 0x65, 0x01, 0xb3, 0x7c, 0xde, 0xf7, 0x2c, 0xfd,
+	0x4f, 0x75, 0xfe, 0xcb, 0xc7, 0xf9, 0xcf, 0x00, 0x79, 0xe4, 0x24, 0x34, 0x07, 0x03, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -76,6 +77,9 @@ type IndexGatewayClient interface {
 	// Note: this MUST be the same as the variant defined in
 	// logproto.proto on the Querier service.
 	GetStats(ctx context.Context, in *IndexStatsRequest, opts ...grpc.CallOption) (*IndexStatsResponse, error)
+	// Note: this MUST be the same as the variant defined in
+	// logproto.proto on the Querier service.
+	GetVolume(ctx context.Context, in *VolumeRequest, opts ...grpc.CallOption) (*VolumeResponse, error)
 }
 
 type indexGatewayClient struct {
@@ -163,6 +167,15 @@ func (c *indexGatewayClient) GetStats(ctx context.Context, in *IndexStatsRequest
 	return out, nil
 }
 
+func (c *indexGatewayClient) GetVolume(ctx context.Context, in *VolumeRequest, opts ...grpc.CallOption) (*VolumeResponse, error) {
+	out := new(VolumeResponse)
+	err := c.cc.Invoke(ctx, "/indexgatewaypb.IndexGateway/GetVolume", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // IndexGatewayServer is the server API for IndexGateway service.
 type IndexGatewayServer interface {
 	/// QueryIndex reads the indexes required for given query & sends back the batch of rows
@@ -176,6 +189,9 @@ type IndexGatewayServer interface {
 	// Note: this MUST be the same as the variant defined in
 	// logproto.proto on the Querier service.
 	GetStats(context.Context, *IndexStatsRequest) (*IndexStatsResponse, error)
+	// Note: this MUST be the same as the variant defined in
+	// logproto.proto on the Querier service.
+	GetVolume(context.Context, *VolumeRequest) (*VolumeResponse, error)
 }
 
 // UnimplementedIndexGatewayServer can be embedded to have forward compatible implementations.
@@ -200,6 +216,9 @@ func (*UnimplementedIndexGatewayServer) LabelValuesForMetricName(ctx context.Con
 func (*UnimplementedIndexGatewayServer) GetStats(ctx context.Context, req *IndexStatsRequest) (*IndexStatsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetStats not implemented")
 }
+func (*UnimplementedIndexGatewayServer) GetVolume(ctx context.Context, req *VolumeRequest) (*VolumeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVolume not implemented")
+}
 
 func RegisterIndexGatewayServer(s *grpc.Server, srv IndexGatewayServer) {
 	s.RegisterService(&_IndexGateway_serviceDesc, srv)
@@ -316,6 +335,24 @@ func _IndexGateway_GetStats_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _IndexGateway_GetVolume_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VolumeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IndexGatewayServer).GetVolume(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/indexgatewaypb.IndexGateway/GetVolume",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IndexGatewayServer).GetVolume(ctx, req.(*VolumeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _IndexGateway_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "indexgatewaypb.IndexGateway",
 	HandlerType: (*IndexGatewayServer)(nil),
@@ -340,6 +377,10 @@ var _IndexGateway_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetStats",
 			Handler:    _IndexGateway_GetStats_Handler,
 		},
+		{
+			MethodName: "GetVolume",
+			Handler:    _IndexGateway_GetVolume_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
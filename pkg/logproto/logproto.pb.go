@@ -762,6 +762,8 @@ func (m *StreamAdapter) GetHash() uint64 {
 type EntryAdapter struct {
 	Timestamp time.Time `protobuf:"bytes,1,opt,name=timestamp,proto3,stdtime" json:"ts"`
 	Line      string    `protobuf:"bytes,2,opt,name=line,proto3" json:"line"`
+	// structuredMetadata holds key/value pairs attached to the entry which are not part of the stream labels.
+	StructuredMetadata []LabelAdapter `protobuf:"bytes,3,rep,name=structuredMetadata,proto3,customtype=LabelAdapter" json:"structuredMetadata,omitempty"`
 }
 
 func (m *EntryAdapter) Reset()      { *m = EntryAdapter{} }
@@ -2449,6 +2451,284 @@ func (m *IndexStatsResponse) GetEntries() uint64 {
 	return 0
 }
 
+type VolumeRequest struct {
+	From     github_com_prometheus_common_model.Time `protobuf:"varint,1,opt,name=from,proto3,customtype=github.com/prometheus/common/model.Time" json:"from"`
+	Through  github_com_prometheus_common_model.Time `protobuf:"varint,2,opt,name=through,proto3,customtype=github.com/prometheus/common/model.Time" json:"through"`
+	Matchers string                                  `protobuf:"bytes,3,opt,name=matchers,proto3" json:"matchers,omitempty"`
+	// The labels the volumes are aggregated by.
+	// The volume of all the matching streams is returned when empty.
+	TargetLabels []string `protobuf:"bytes,4,rep,name=targetLabels,proto3" json:"targetLabels,omitempty"`
+	// The maximum number of volumes to return, only used by the query frontend.
+	Limit uint32 `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`
+	// Whether the volumes of the streams are returned, only used by the query frontend.
+	Streams bool `protobuf:"varint,6,opt,name=streams,proto3" json:"streams,omitempty"`
+}
+
+func (m *VolumeRequest) Reset()      { *m = VolumeRequest{} }
+func (*VolumeRequest) ProtoMessage() {}
+func (*VolumeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c28a5f14f1f4c79a, []int{46}
+}
+func (m *VolumeRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *VolumeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_VolumeRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *VolumeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VolumeRequest.Merge(m, src)
+}
+func (m *VolumeRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *VolumeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_VolumeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VolumeRequest proto.InternalMessageInfo
+
+func (m *VolumeRequest) GetMatchers() string {
+	if m != nil {
+		return m.Matchers
+	}
+	return ""
+}
+
+func (m *VolumeRequest) GetTargetLabels() []string {
+	if m != nil {
+		return m.TargetLabels
+	}
+	return nil
+}
+
+func (m *VolumeRequest) GetLimit() uint32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *VolumeRequest) GetStreams() bool {
+	if m != nil {
+		return m.Streams
+	}
+	return false
+}
+
+type VolumeResponse struct {
+	Volumes []Volume `protobuf:"bytes,1,rep,name=volumes,proto3" json:"volumes"`
+	// The volume of each stream, so that the streams seen by several ingesters,
+	// by the store or by several queries are only counted once when merging responses.
+	Streams []StreamVolume `protobuf:"bytes,2,rep,name=streams,proto3" json:"streams,omitempty"`
+}
+
+func (m *VolumeResponse) Reset()      { *m = VolumeResponse{} }
+func (*VolumeResponse) ProtoMessage() {}
+func (*VolumeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c28a5f14f1f4c79a, []int{47}
+}
+func (m *VolumeResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *VolumeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_VolumeResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *VolumeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VolumeResponse.Merge(m, src)
+}
+func (m *VolumeResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *VolumeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_VolumeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VolumeResponse proto.InternalMessageInfo
+
+func (m *VolumeResponse) GetVolumes() []Volume {
+	if m != nil {
+		return m.Volumes
+	}
+	return nil
+}
+
+func (m *VolumeResponse) GetStreams() []StreamVolume {
+	if m != nil {
+		return m.Streams
+	}
+	return nil
+}
+
+type StreamVolume struct {
+	Fingerprint uint64 `protobuf:"varint,1,opt,name=fingerprint,proto3" json:"fingerprint"`
+	// The name of the volume the stream is aggregated into.
+	Name    string `protobuf:"bytes,2,opt,name=name,proto3" json:"name"`
+	Chunks  uint64 `protobuf:"varint,3,opt,name=chunks,proto3" json:"chunks"`
+	Bytes   uint64 `protobuf:"varint,4,opt,name=bytes,proto3" json:"bytes"`
+	Entries uint64 `protobuf:"varint,5,opt,name=entries,proto3" json:"entries"`
+}
+
+func (m *StreamVolume) Reset()      { *m = StreamVolume{} }
+func (*StreamVolume) ProtoMessage() {}
+func (*StreamVolume) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c28a5f14f1f4c79a, []int{48}
+}
+func (m *StreamVolume) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *StreamVolume) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_StreamVolume.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *StreamVolume) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StreamVolume.Merge(m, src)
+}
+func (m *StreamVolume) XXX_Size() int {
+	return m.Size()
+}
+func (m *StreamVolume) XXX_DiscardUnknown() {
+	xxx_messageInfo_StreamVolume.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StreamVolume proto.InternalMessageInfo
+
+func (m *StreamVolume) GetFingerprint() uint64 {
+	if m != nil {
+		return m.Fingerprint
+	}
+	return 0
+}
+
+func (m *StreamVolume) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *StreamVolume) GetChunks() uint64 {
+	if m != nil {
+		return m.Chunks
+	}
+	return 0
+}
+
+func (m *StreamVolume) GetBytes() uint64 {
+	if m != nil {
+		return m.Bytes
+	}
+	return 0
+}
+
+func (m *StreamVolume) GetEntries() uint64 {
+	if m != nil {
+		return m.Entries
+	}
+	return 0
+}
+
+type Volume struct {
+	// The label set the volume is aggregated by, e.g. {namespace="loki"}.
+	Name    string `protobuf:"bytes,1,opt,name=name,proto3" json:"name"`
+	Streams uint64 `protobuf:"varint,2,opt,name=streams,proto3" json:"streams"`
+	Chunks  uint64 `protobuf:"varint,3,opt,name=chunks,proto3" json:"chunks"`
+	Bytes   uint64 `protobuf:"varint,4,opt,name=bytes,proto3" json:"bytes"`
+	Entries uint64 `protobuf:"varint,5,opt,name=entries,proto3" json:"entries"`
+}
+
+func (m *Volume) Reset()      { *m = Volume{} }
+func (*Volume) ProtoMessage() {}
+func (*Volume) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c28a5f14f1f4c79a, []int{49}
+}
+func (m *Volume) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Volume) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Volume.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Volume) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Volume.Merge(m, src)
+}
+func (m *Volume) XXX_Size() int {
+	return m.Size()
+}
+func (m *Volume) XXX_DiscardUnknown() {
+	xxx_messageInfo_Volume.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Volume proto.InternalMessageInfo
+
+func (m *Volume) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Volume) GetStreams() uint64 {
+	if m != nil {
+		return m.Streams
+	}
+	return 0
+}
+
+func (m *Volume) GetChunks() uint64 {
+	if m != nil {
+		return m.Chunks
+	}
+	return 0
+}
+
+func (m *Volume) GetBytes() uint64 {
+	if m != nil {
+		return m.Bytes
+	}
+	return 0
+}
+
+func (m *Volume) GetEntries() uint64 {
+	if m != nil {
+		return m.Entries
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterEnum("logproto.Direction", Direction_name, Direction_value)
 	proto.RegisterType((*StreamRatesRequest)(nil), "logproto.StreamRatesRequest")
@@ -2498,149 +2778,165 @@ func init() {
 	proto.RegisterType((*IndexQuery)(nil), "logproto.IndexQuery")
 	proto.RegisterType((*IndexStatsRequest)(nil), "logproto.IndexStatsRequest")
 	proto.RegisterType((*IndexStatsResponse)(nil), "logproto.IndexStatsResponse")
+	proto.RegisterType((*VolumeRequest)(nil), "logproto.VolumeRequest")
+	proto.RegisterType((*VolumeResponse)(nil), "logproto.VolumeResponse")
+	proto.RegisterType((*StreamVolume)(nil), "logproto.StreamVolume")
+	proto.RegisterType((*Volume)(nil), "logproto.Volume")
 }
 
 func init() { proto.RegisterFile("pkg/logproto/logproto.proto", fileDescriptor_c28a5f14f1f4c79a) }
 
 var fileDescriptor_c28a5f14f1f4c79a = []byte{
-	// 2192 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x19, 0x4d, 0x6f, 0x1b, 0xc7,
-	0x95, 0x43, 0x2e, 0x29, 0xf2, 0x91, 0x92, 0xe8, 0x31, 0x6d, 0x33, 0xb4, 0x4d, 0xca, 0x8b, 0xd4,
-	0x16, 0x1c, 0x87, 0xaa, 0x95, 0x36, 0x71, 0xec, 0xa6, 0x85, 0x28, 0xc5, 0xb6, 0xfc, 0x9d, 0x91,
-	0xeb, 0x00, 0x01, 0x02, 0x63, 0x45, 0x0e, 0xc9, 0x85, 0xb8, 0x5c, 0x7a, 0x77, 0x18, 0x47, 0x40,
-	0x81, 0xf6, 0xd4, 0x53, 0x03, 0xa4, 0xa7, 0xa2, 0xf7, 0x02, 0x2d, 0x7a, 0xe8, 0xa1, 0x40, 0xaf,
-	0x6d, 0x6f, 0x75, 0x6f, 0xee, 0x2d, 0xc8, 0x81, 0xad, 0xe5, 0x4b, 0xa1, 0x53, 0x7e, 0x41, 0x51,
-	0xcc, 0xd7, 0xee, 0x90, 0xa2, 0x1a, 0xd3, 0x35, 0x10, 0xe4, 0x42, 0xce, 0xbc, 0x79, 0xf3, 0x66,
-	0xde, 0xf7, 0x7b, 0xb3, 0x70, 0x72, 0xb0, 0xd3, 0x59, 0xe9, 0xf9, 0x9d, 0x41, 0xe0, 0x33, 0x3f,
-	0x1a, 0xd4, 0xc5, 0x2f, 0xce, 0xea, 0x79, 0xa5, 0xd4, 0xf1, 0x3b, 0xbe, 0xc4, 0xe1, 0x23, 0xb9,
-	0x5e, 0xa9, 0x75, 0x7c, 0xbf, 0xd3, 0xa3, 0x2b, 0x62, 0xb6, 0x3d, 0x6c, 0xaf, 0x30, 0xd7, 0xa3,
-	0x21, 0x73, 0xbc, 0x81, 0x42, 0x58, 0x52, 0xd4, 0x1f, 0xf5, 0x3c, 0xbf, 0x45, 0x7b, 0x2b, 0x21,
-	0x73, 0x58, 0x28, 0x7f, 0x25, 0x86, 0x5d, 0x02, 0xbc, 0xc5, 0x02, 0xea, 0x78, 0xc4, 0x61, 0x34,
-	0x24, 0xf4, 0xd1, 0x90, 0x86, 0xcc, 0xbe, 0x0d, 0x47, 0xc7, 0xa0, 0xe1, 0xc0, 0xef, 0x87, 0x14,
-	0xbf, 0x0d, 0xf9, 0x30, 0x06, 0x97, 0xd1, 0x52, 0x6a, 0x39, 0xbf, 0x5a, 0xaa, 0x47, 0xb7, 0x8e,
-	0xf7, 0x10, 0x13, 0xd1, 0xfe, 0x39, 0x02, 0x88, 0xd7, 0x70, 0x15, 0x40, 0xae, 0x5e, 0x77, 0xc2,
-	0x6e, 0x19, 0x2d, 0xa1, 0x65, 0x8b, 0x18, 0x10, 0x7c, 0x01, 0x8e, 0xc4, 0xb3, 0x3b, 0xfe, 0x56,
-	0xd7, 0x09, 0x5a, 0xe5, 0xa4, 0x40, 0x3b, 0xb8, 0x80, 0x31, 0x58, 0x81, 0xc3, 0x68, 0x39, 0xb5,
-	0x84, 0x96, 0x53, 0x44, 0x8c, 0xf1, 0x71, 0xc8, 0x30, 0xda, 0x77, 0xfa, 0xac, 0x6c, 0x2d, 0xa1,
-	0xe5, 0x1c, 0x51, 0x33, 0xfb, 0x43, 0xc8, 0xdf, 0x1b, 0x86, 0x5d, 0xc5, 0x26, 0xbe, 0x0e, 0x73,
-	0x92, 0x9e, 0xe6, 0xe5, 0xc4, 0x24, 0x2f, 0x6b, 0x2d, 0x67, 0xc0, 0x68, 0xd0, 0x38, 0xf6, 0xe5,
-	0xa8, 0x96, 0x91, 0xa0, 0xfd, 0x51, 0x4d, 0xef, 0x22, 0x7a, 0x60, 0x2f, 0x40, 0x41, 0x12, 0x96,
-	0x92, 0xb2, 0xff, 0x96, 0x84, 0xc2, 0x07, 0x43, 0x1a, 0xec, 0xea, 0xa3, 0x2a, 0x90, 0x0d, 0x69,
-	0x8f, 0x36, 0x99, 0x1f, 0x08, 0x8e, 0x73, 0x24, 0x9a, 0xe3, 0x12, 0xa4, 0x7b, 0xae, 0xe7, 0x32,
-	0xc1, 0xe3, 0x3c, 0x91, 0x13, 0x7c, 0x19, 0xd2, 0x21, 0x73, 0x02, 0x26, 0x18, 0xcb, 0xaf, 0x56,
-	0xea, 0x52, 0xd9, 0x75, 0xad, 0xec, 0xfa, 0x7d, 0xad, 0xec, 0x46, 0xf6, 0xc9, 0xa8, 0x96, 0xf8,
-	0xfc, 0x9f, 0x35, 0x44, 0xe4, 0x16, 0xfc, 0x36, 0xa4, 0x68, 0xbf, 0x25, 0x98, 0x7f, 0xd1, 0x9d,
-	0x7c, 0x03, 0xbe, 0x08, 0xb9, 0x96, 0x1b, 0xd0, 0x26, 0x73, 0xfd, 0x7e, 0x39, 0xbd, 0x84, 0x96,
-	0x17, 0x56, 0x8f, 0xc6, 0x22, 0xd9, 0xd0, 0x4b, 0x24, 0xc6, 0xc2, 0x17, 0x20, 0x13, 0x72, 0x3d,
-	0x84, 0xe5, 0xb9, 0xa5, 0xd4, 0x72, 0xae, 0x51, 0xda, 0x1f, 0xd5, 0x8a, 0x12, 0x72, 0xc1, 0xf7,
-	0x5c, 0x46, 0xbd, 0x01, 0xdb, 0x25, 0x0a, 0x07, 0x9f, 0x87, 0xb9, 0x16, 0xed, 0x51, 0x6e, 0x3d,
-	0x59, 0x21, 0xf1, 0xa2, 0x41, 0x5e, 0x2c, 0x10, 0x8d, 0x70, 0xc3, 0xca, 0x66, 0x8a, 0x73, 0xf6,
-	0x7f, 0x10, 0xe0, 0x2d, 0xc7, 0x1b, 0xf4, 0xe8, 0x0b, 0xcb, 0x33, 0x92, 0x5c, 0xf2, 0xa5, 0x25,
-	0x97, 0x9a, 0x55, 0x72, 0xb1, 0x18, 0xac, 0xd9, 0xc4, 0x90, 0xfe, 0x1a, 0x31, 0xd8, 0xb7, 0x20,
-	0x23, 0x41, 0x5f, 0x67, 0x43, 0x31, 0xcf, 0x29, 0xcd, 0x4d, 0x31, 0xe6, 0x26, 0x25, 0xee, 0x69,
-	0xff, 0x14, 0xe6, 0x95, 0x1c, 0x95, 0x4f, 0xaf, 0xbd, 0xb0, 0x0f, 0x2c, 0x3c, 0x19, 0xd5, 0x50,
-	0xec, 0x07, 0x91, 0xf1, 0xe3, 0x37, 0xc4, 0xd9, 0x2c, 0x54, 0xf2, 0x5e, 0xac, 0xcb, 0x00, 0xb3,
-	0xd9, 0xef, 0xd0, 0x90, 0x6f, 0xb4, 0xb8, 0xa8, 0x88, 0xc4, 0xb1, 0x7f, 0x02, 0x47, 0xc7, 0xd4,
-	0xa9, 0xae, 0x71, 0x09, 0x32, 0x21, 0x0d, 0xdc, 0x28, 0xaa, 0x18, 0x02, 0xd9, 0x12, 0x70, 0xe3,
-	0x78, 0x31, 0x27, 0x0a, 0x7f, 0xb6, 0xd3, 0xff, 0x80, 0xa0, 0x70, 0xcb, 0xd9, 0xa6, 0x3d, 0x6d,
-	0x47, 0x18, 0xac, 0xbe, 0xe3, 0x51, 0x25, 0x4f, 0x31, 0xe6, 0xd1, 0xe3, 0x13, 0xa7, 0x37, 0xa4,
-	0x92, 0x64, 0x96, 0xa8, 0xd9, 0xac, 0x1e, 0x89, 0x5e, 0xda, 0x23, 0x51, 0x64, 0x57, 0xf6, 0x39,
-	0x98, 0x57, 0xf7, 0x55, 0x82, 0x8a, 0x2f, 0xc7, 0x05, 0x95, 0xd3, 0x97, 0xb3, 0x7f, 0x89, 0x60,
-	0x7e, 0x4c, 0x5f, 0xd8, 0x86, 0x4c, 0x8f, 0x6f, 0x0d, 0x25, 0x73, 0x0d, 0xd8, 0x1f, 0xd5, 0x14,
-	0x84, 0xa8, 0x7f, 0xae, 0x7d, 0xda, 0x67, 0x42, 0xee, 0x49, 0x21, 0xf7, 0xe3, 0xb1, 0xdc, 0xdf,
-	0xef, 0xb3, 0x60, 0x57, 0x2b, 0x7f, 0x91, 0x4b, 0x91, 0x87, 0x3e, 0x85, 0x4e, 0xf4, 0x00, 0xbf,
-	0x06, 0x56, 0x97, 0xc7, 0x71, 0x2e, 0x14, 0xab, 0x91, 0xde, 0x1f, 0xd5, 0xd0, 0x9b, 0x44, 0x80,
-	0xec, 0x4f, 0xa0, 0x60, 0x12, 0xc1, 0xd7, 0x21, 0x17, 0x65, 0x28, 0x71, 0xa9, 0xff, 0x2d, 0x8a,
-	0x05, 0x75, 0x66, 0x92, 0x85, 0x42, 0x20, 0xf1, 0x66, 0x7c, 0x0a, 0xac, 0x9e, 0xdb, 0xa7, 0x42,
-	0x41, 0xb9, 0x46, 0x76, 0x7f, 0x54, 0x13, 0x73, 0x22, 0x7e, 0x6d, 0x0f, 0x32, 0xd2, 0xc6, 0xf0,
-	0xeb, 0x93, 0x27, 0xa6, 0x1a, 0x19, 0x49, 0xd1, 0xa4, 0x56, 0x83, 0xb4, 0x90, 0xa2, 0x20, 0x87,
-	0x1a, 0xb9, 0xfd, 0x51, 0x4d, 0x02, 0x88, 0xfc, 0xe3, 0xc7, 0x19, 0x3c, 0x8a, 0xe3, 0xf8, 0x5c,
-	0xb1, 0x79, 0x0d, 0x0a, 0xb7, 0x68, 0xc7, 0x69, 0xee, 0xaa, 0x43, 0x4b, 0x9a, 0x1c, 0x3f, 0x10,
-	0x69, 0x1a, 0x67, 0xa0, 0x10, 0x9d, 0xf8, 0xd0, 0x0b, 0x95, 0xa3, 0xe6, 0x23, 0xd8, 0xed, 0xd0,
-	0xfe, 0x35, 0x02, 0x65, 0xdd, 0x2f, 0xa4, 0xbc, 0x2b, 0x30, 0x17, 0x8a, 0x13, 0xb5, 0xf2, 0x4c,
-	0xa7, 0x11, 0x0b, 0xb1, 0xda, 0x14, 0x22, 0xd1, 0x03, 0x5c, 0x1f, 0x4b, 0xc2, 0x92, 0xb1, 0x85,
-	0xfd, 0x51, 0xcd, 0x80, 0x9a, 0x49, 0xd9, 0xfe, 0x15, 0x82, 0xfc, 0x7d, 0xc7, 0x8d, 0x1c, 0xa7,
-	0x04, 0xe9, 0x47, 0xdc, 0x83, 0x95, 0xe7, 0xc8, 0x09, 0x0f, 0x51, 0x2d, 0xda, 0x73, 0x76, 0xaf,
-	0xfa, 0x81, 0xa0, 0x39, 0x4f, 0xa2, 0x79, 0x9c, 0xe6, 0xac, 0xa9, 0x69, 0x2e, 0x3d, 0x73, 0xb0,
-	0xbe, 0x61, 0x65, 0x93, 0xc5, 0x94, 0xfd, 0x0b, 0x04, 0x05, 0x79, 0x33, 0xe5, 0x22, 0x57, 0x20,
-	0x23, 0x2f, 0xae, 0x6c, 0xec, 0xd0, 0x88, 0x06, 0x46, 0x34, 0x53, 0x5b, 0xf0, 0x8f, 0x60, 0xa1,
-	0x15, 0xf8, 0x83, 0x01, 0x6d, 0x6d, 0xa9, 0xb0, 0x98, 0x9c, 0x0c, 0x8b, 0x1b, 0xe6, 0x3a, 0x99,
-	0x40, 0xb7, 0xff, 0xce, 0x1d, 0x51, 0x86, 0x28, 0x25, 0xaa, 0x88, 0x45, 0xf4, 0xd2, 0xf9, 0x28,
-	0x39, 0x6b, 0x3e, 0x3a, 0x0e, 0x99, 0x4e, 0xe0, 0x0f, 0x07, 0x61, 0x39, 0x25, 0xc3, 0x84, 0x9c,
-	0xcd, 0x96, 0xa7, 0xec, 0x1b, 0xb0, 0xa0, 0x59, 0x39, 0x24, 0x4e, 0x57, 0x26, 0xe3, 0xf4, 0x66,
-	0x8b, 0xf6, 0x99, 0xdb, 0x76, 0xa3, 0xc8, 0xab, 0xf0, 0xed, 0xcf, 0x10, 0x14, 0x27, 0x51, 0xf0,
-	0x0f, 0x0d, 0x33, 0xe7, 0xe4, 0xce, 0x1e, 0x4e, 0xae, 0x2e, 0xe2, 0x60, 0x28, 0x02, 0x8a, 0x76,
-	0x81, 0xca, 0xbb, 0x90, 0x37, 0xc0, 0x3c, 0xdf, 0xed, 0x50, 0x6d, 0x92, 0x7c, 0x18, 0xfb, 0x62,
-	0x52, 0x9a, 0xa9, 0x98, 0x5c, 0x4e, 0x5e, 0x42, 0xdc, 0xa0, 0xe7, 0xc7, 0x34, 0x89, 0x2f, 0x81,
-	0xd5, 0x0e, 0x7c, 0x6f, 0x26, 0x35, 0x89, 0x1d, 0xf8, 0x7b, 0x90, 0x64, 0xfe, 0x4c, 0x4a, 0x4a,
-	0x32, 0x9f, 0xeb, 0x48, 0x31, 0x9f, 0x92, 0x55, 0xaa, 0x9c, 0xd9, 0xbf, 0x47, 0xb0, 0xc8, 0xf7,
-	0x48, 0x09, 0xac, 0x77, 0x87, 0xfd, 0x1d, 0xbc, 0x0c, 0x45, 0x7e, 0xd2, 0x43, 0x57, 0xa5, 0xb5,
-	0x87, 0x6e, 0x4b, 0xb1, 0xb9, 0xc0, 0xe1, 0x3a, 0xdb, 0x6d, 0xb6, 0xf0, 0x09, 0x98, 0x1b, 0x86,
-	0x12, 0x41, 0xf2, 0x9c, 0xe1, 0xd3, 0xcd, 0x16, 0x7e, 0xc3, 0x38, 0x8e, 0xcb, 0xda, 0xa8, 0xec,
-	0x84, 0x0c, 0xef, 0x39, 0x6e, 0x10, 0xc5, 0x96, 0x73, 0x90, 0x69, 0xf2, 0x83, 0xa5, 0x9d, 0xf0,
-	0xb4, 0x1a, 0x21, 0x8b, 0x0b, 0x11, 0xb5, 0x6c, 0x7f, 0x1f, 0x72, 0xd1, 0xee, 0xa9, 0xd9, 0x74,
-	0xaa, 0x06, 0xec, 0x2b, 0xb0, 0x28, 0x63, 0xe6, 0xf4, 0xcd, 0x85, 0x69, 0x9b, 0x0b, 0x7a, 0xf3,
-	0x49, 0x48, 0x4b, 0xa9, 0x60, 0xb0, 0x5a, 0x0e, 0x73, 0xf4, 0x16, 0x3e, 0xb6, 0xcb, 0x70, 0xfc,
-	0x7e, 0xe0, 0xf4, 0xc3, 0x36, 0x0d, 0x04, 0x52, 0x64, 0xbb, 0xf6, 0x31, 0x38, 0xca, 0xe3, 0x04,
-	0x0d, 0xc2, 0x75, 0x7f, 0xd8, 0x67, 0xba, 0xd9, 0xb9, 0x00, 0xa5, 0x71, 0xb0, 0x32, 0xf5, 0x12,
-	0xa4, 0x9b, 0x1c, 0x20, 0xa8, 0xcf, 0x13, 0x39, 0xb1, 0x7f, 0x83, 0x00, 0x5f, 0xa3, 0x4c, 0x90,
-	0xde, 0xdc, 0x08, 0x8d, 0x7a, 0xd4, 0x73, 0x58, 0xb3, 0x4b, 0x83, 0x50, 0xd7, 0x66, 0x7a, 0xfe,
-	0x4d, 0xd4, 0xa3, 0xf6, 0x45, 0x38, 0x3a, 0x76, 0x4b, 0xc5, 0x53, 0x05, 0xb2, 0x4d, 0x05, 0x53,
-	0xf5, 0x43, 0x34, 0xb7, 0xff, 0x98, 0x84, 0xac, 0xd4, 0x2d, 0x6d, 0xe3, 0x8b, 0x90, 0x6f, 0x73,
-	0x5b, 0x0b, 0x06, 0x81, 0xab, 0x44, 0x60, 0x35, 0x16, 0xf7, 0x47, 0x35, 0x13, 0x4c, 0xcc, 0x09,
-	0x7e, 0x73, 0xc2, 0xf0, 0x1a, 0xa5, 0xbd, 0x51, 0x2d, 0xf3, 0x63, 0x6e, 0x7c, 0x1b, 0x3c, 0x7b,
-	0x09, 0x33, 0xdc, 0x88, 0xcc, 0xf1, 0xa6, 0xf2, 0x36, 0x51, 0x9c, 0x36, 0xde, 0xe1, 0xd7, 0xff,
-	0x72, 0x54, 0x3b, 0xd7, 0x71, 0x59, 0x77, 0xb8, 0x5d, 0x6f, 0xfa, 0x1e, 0x6f, 0x6b, 0x3d, 0xca,
-	0xba, 0x74, 0x18, 0xae, 0x34, 0x7d, 0xcf, 0xf3, 0xfb, 0x2b, 0xa2, 0x8b, 0x15, 0x4c, 0xf3, 0x14,
-	0xcc, 0xb7, 0x2b, 0x07, 0xbc, 0x0f, 0x73, 0xac, 0x1b, 0xf8, 0xc3, 0x4e, 0x57, 0x64, 0x97, 0x54,
-	0xe3, 0xf2, 0xec, 0xf4, 0x34, 0x05, 0xa2, 0x07, 0xf8, 0x0c, 0x97, 0x16, 0x6d, 0xee, 0x84, 0x43,
-	0x4f, 0xa4, 0xa7, 0x79, 0x5d, 0xde, 0x44, 0x60, 0xfb, 0xb3, 0x24, 0xd4, 0x84, 0x09, 0x3f, 0x10,
-	0x65, 0xd8, 0x55, 0x3f, 0xb8, 0x4d, 0x59, 0xe0, 0x36, 0xef, 0x38, 0x1e, 0xd5, 0xb6, 0x51, 0x83,
-	0xbc, 0x27, 0x80, 0x0f, 0x0d, 0xe7, 0x00, 0x2f, 0xc2, 0xc3, 0xa7, 0x01, 0x84, 0xdb, 0xc9, 0x75,
-	0xe9, 0x27, 0x39, 0x01, 0x11, 0xcb, 0xeb, 0x63, 0x92, 0x5a, 0x99, 0x91, 0x33, 0x25, 0xa1, 0xcd,
-	0x49, 0x09, 0xcd, 0x4c, 0x27, 0x12, 0x8b, 0x69, 0xeb, 0xe9, 0x71, 0x5b, 0xb7, 0xff, 0x81, 0xa0,
-	0x7a, 0x4b, 0xdf, 0xfc, 0x25, 0xc5, 0xa1, 0xf9, 0x4d, 0xbe, 0x22, 0x7e, 0x53, 0xff, 0x1f, 0xbf,
-	0xf6, 0x5f, 0x0d, 0x97, 0x27, 0xb4, 0xad, 0xf9, 0x58, 0x37, 0xd2, 0xc5, 0xab, 0xb8, 0x66, 0xf2,
-	0x15, 0xaa, 0x25, 0x35, 0xa1, 0x96, 0xf7, 0xe2, 0x70, 0x20, 0x38, 0x50, 0xe1, 0xe0, 0x2c, 0x58,
-	0x01, 0x6d, 0xeb, 0xe4, 0x8b, 0x27, 0x63, 0x3c, 0x6d, 0x13, 0xb1, 0x6e, 0xff, 0x19, 0x41, 0xf1,
-	0x1a, 0x65, 0xe3, 0x65, 0xcd, 0xb7, 0x89, 0xff, 0xeb, 0x70, 0xc4, 0xb8, 0xbf, 0xe2, 0xfe, 0xad,
-	0x89, 0x5a, 0xe6, 0x58, 0xcc, 0xff, 0x66, 0xbf, 0x45, 0x3f, 0x55, 0x8d, 0xe7, 0x78, 0x19, 0x73,
-	0x0f, 0xf2, 0xc6, 0x22, 0x5e, 0x9b, 0x28, 0x60, 0xa6, 0x25, 0xd5, 0x46, 0x49, 0xf1, 0x24, 0x5b,
-	0x4f, 0x55, 0x7d, 0x46, 0xe9, 0x7e, 0x0b, 0xb0, 0xe8, 0x85, 0x05, 0x59, 0x33, 0x52, 0x0b, 0xe8,
-	0xcd, 0xa8, 0x9e, 0x89, 0xe6, 0xf8, 0x0c, 0x58, 0x81, 0xff, 0x58, 0x57, 0xa6, 0xf3, 0xf1, 0x91,
-	0xc4, 0x7f, 0x4c, 0xc4, 0x92, 0x7d, 0x05, 0x52, 0xc4, 0x7f, 0x8c, 0xab, 0x00, 0x81, 0xd3, 0xef,
-	0xd0, 0x07, 0x51, 0x3f, 0x52, 0x20, 0x06, 0xe4, 0x90, 0xfc, 0xba, 0x0e, 0x47, 0xcc, 0x1b, 0x49,
-	0x75, 0xd7, 0x61, 0x8e, 0x03, 0xdd, 0x69, 0x0f, 0x7f, 0x02, 0x51, 0x36, 0xf4, 0x1a, 0x89, 0xdb,
-	0x0c, 0xc4, 0x70, 0x7c, 0x0a, 0x72, 0xcc, 0xd9, 0xee, 0xd1, 0x3b, 0xb1, 0xcf, 0xc7, 0x00, 0xbe,
-	0xca, 0x5b, 0xa9, 0x07, 0x46, 0xa1, 0x10, 0x03, 0xf0, 0x79, 0x28, 0xc6, 0x77, 0xbe, 0x17, 0xd0,
-	0xb6, 0xfb, 0xa9, 0xd0, 0x70, 0x81, 0x1c, 0x80, 0xe3, 0x65, 0x58, 0x8c, 0x61, 0x5b, 0x22, 0xed,
-	0x5a, 0x02, 0x75, 0x12, 0xcc, 0x65, 0x23, 0xd8, 0x7d, 0xff, 0xd1, 0xd0, 0xe9, 0x89, 0x40, 0x56,
-	0x20, 0x06, 0xc4, 0xfe, 0x0b, 0x82, 0x23, 0x52, 0xd5, 0xcc, 0x61, 0xdf, 0x4a, 0xab, 0xff, 0x2d,
-	0x02, 0x6c, 0x72, 0xa0, 0x4c, 0xeb, 0x3b, 0xe6, 0x93, 0x0f, 0xcf, 0xeb, 0xf9, 0x69, 0x6f, 0x9a,
-	0xbc, 0x05, 0x55, 0x25, 0xa0, 0x78, 0x7b, 0x95, 0x2d, 0xa8, 0x84, 0xe8, 0xea, 0x8f, 0x77, 0xce,
-	0xdb, 0xbb, 0x8c, 0x86, 0xaa, 0x81, 0x14, 0x9d, 0xb3, 0x00, 0x10, 0xf9, 0xc7, 0xcf, 0xd2, 0x0f,
-	0x0c, 0x56, 0x7c, 0xd6, 0xe4, 0x23, 0xc2, 0xf9, 0xb3, 0x90, 0x8b, 0x5e, 0x17, 0x71, 0x1e, 0xe6,
-	0xae, 0xde, 0x25, 0x1f, 0xae, 0x91, 0x8d, 0x62, 0x02, 0x17, 0x20, 0xdb, 0x58, 0x5b, 0xbf, 0x29,
-	0x66, 0x68, 0x75, 0x0d, 0x32, 0xf7, 0x86, 0x61, 0x97, 0x06, 0xf8, 0x1d, 0xb0, 0xf8, 0x08, 0x1b,
-	0x4e, 0x6b, 0x3c, 0xed, 0x56, 0x8e, 0x4f, 0x82, 0x55, 0x0d, 0x98, 0x58, 0xfd, 0x93, 0xa5, 0x0d,
-	0x39, 0xc0, 0x3f, 0x80, 0xb4, 0xb4, 0x4e, 0x03, 0xdd, 0x7c, 0x66, 0xac, 0x9c, 0x38, 0x00, 0xd7,
-	0x74, 0xbe, 0x8b, 0xf0, 0x1d, 0xc8, 0x0b, 0xa0, 0x6a, 0xfb, 0x4f, 0x4d, 0x76, 0xdf, 0x63, 0x94,
-	0x4e, 0x1f, 0xb2, 0x6a, 0xd0, 0xbb, 0x0c, 0x69, 0x11, 0x20, 0xcc, 0xdb, 0x98, 0x8f, 0x55, 0xe6,
-	0x6d, 0xc6, 0x1e, 0x85, 0xec, 0x04, 0x7e, 0x17, 0x2c, 0x5e, 0xc4, 0x9a, 0xe2, 0x30, 0xba, 0x75,
-	0x53, 0x1c, 0x66, 0xab, 0x2c, 0x8e, 0x7d, 0x2f, 0x7a, 0x74, 0x38, 0x31, 0xd9, 0x7d, 0xe9, 0xed,
-	0xe5, 0x83, 0x0b, 0xd1, 0xc9, 0x77, 0x65, 0xf7, 0xad, 0xcb, 0x67, 0x7c, 0x7a, 0xfc, 0xa8, 0x89,
-	0x6a, 0xbb, 0x52, 0x3d, 0x6c, 0x39, 0x22, 0x78, 0x0b, 0xf2, 0x46, 0xe9, 0x6a, 0x8a, 0xf5, 0x60,
-	0xdd, 0x6d, 0x8a, 0x75, 0x4a, 0xbd, 0x6b, 0x27, 0xf0, 0x35, 0xc8, 0xf2, 0xc8, 0xcf, 0x1d, 0x00,
-	0x9f, 0x9c, 0x0c, 0xf0, 0x86, 0x63, 0x57, 0x4e, 0x4d, 0x5f, 0x8c, 0xec, 0xe6, 0x63, 0xc8, 0xea,
-	0x2e, 0x0b, 0x7f, 0x00, 0x0b, 0xe3, 0x3d, 0x06, 0x7e, 0xcd, 0x60, 0x6b, 0xbc, 0x75, 0xab, 0x2c,
-	0x19, 0x4b, 0xd3, 0x1b, 0x93, 0xc4, 0x32, 0x5a, 0xfd, 0x58, 0x7f, 0x22, 0xd9, 0x70, 0x98, 0x83,
-	0xef, 0xc2, 0x82, 0xb8, 0x75, 0xf4, 0x0d, 0x65, 0xcc, 0xba, 0x0e, 0x7c, 0xb0, 0x19, 0xb3, 0xae,
-	0x83, 0x1f, 0x6e, 0xec, 0x44, 0xe3, 0xa3, 0xa7, 0xcf, 0xaa, 0x89, 0x2f, 0x9e, 0x55, 0x13, 0x5f,
-	0x3d, 0xab, 0xa2, 0x9f, 0xed, 0x55, 0xd1, 0xef, 0xf6, 0xaa, 0xe8, 0xc9, 0x5e, 0x15, 0x3d, 0xdd,
-	0xab, 0xa2, 0x7f, 0xed, 0x55, 0xd1, 0xbf, 0xf7, 0xaa, 0x89, 0xaf, 0xf6, 0xaa, 0xe8, 0xf3, 0xe7,
-	0xd5, 0xc4, 0xd3, 0xe7, 0xd5, 0xc4, 0x17, 0xcf, 0xab, 0x89, 0x8f, 0x5e, 0x37, 0xc2, 0x51, 0x27,
-	0x70, 0xda, 0x4e, 0xdf, 0x59, 0xe9, 0xf9, 0x3b, 0xee, 0x8a, 0xf9, 0xd5, 0x6a, 0x3b, 0x23, 0xfe,
-	0xde, 0xfa, 0x6f, 0x00, 0x00, 0x00, 0xff, 0xff, 0xa0, 0x90, 0x7e, 0x84, 0xcc, 0x1a, 0x00, 0x00,
+	// 2380 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x19, 0x4b, 0x6c, 0x1b, 0xc7,
+	0x55, 0x43, 0x2e, 0x29, 0xf2, 0x91, 0xa2, 0xe4, 0x11, 0x23, 0x31, 0xb4, 0x4c, 0xca, 0x8b, 0xc4,
+	0x16, 0x1c, 0x47, 0xaa, 0x95, 0x34, 0x71, 0xec, 0xa6, 0xad, 0x28, 0xc5, 0xb6, 0xfc, 0xf7, 0xc8,
+	0x75, 0x80, 0x00, 0x81, 0xb1, 0x22, 0x87, 0x14, 0x61, 0x2e, 0x97, 0xde, 0x1d, 0xc6, 0x11, 0x50,
+	0xa0, 0x3d, 0xf5, 0xd4, 0xa0, 0xe9, 0xa9, 0x68, 0xcf, 0x05, 0x5a, 0xf4, 0xd0, 0x43, 0x6f, 0xbd,
+	0xb4, 0xbd, 0xd5, 0x45, 0x2f, 0xee, 0x2d, 0xc8, 0x81, 0xad, 0xe5, 0x4b, 0xa1, 0x53, 0x7a, 0xea,
+	0xad, 0x28, 0xe6, 0xb7, 0x3b, 0x5c, 0x51, 0xb5, 0xe9, 0xba, 0x28, 0x7c, 0x21, 0xe7, 0xbd, 0x79,
+	0xf3, 0x66, 0xde, 0x9b, 0xf7, 0x9d, 0x85, 0xa3, 0xbd, 0x7b, 0xad, 0x95, 0x8e, 0xd7, 0xea, 0xf9,
+	0x1e, 0xf3, 0xc2, 0xc1, 0xb2, 0xf8, 0xc5, 0x19, 0x0d, 0x97, 0x8b, 0x2d, 0xaf, 0xe5, 0x49, 0x1a,
+	0x3e, 0x92, 0xf3, 0xe5, 0x6a, 0xcb, 0xf3, 0x5a, 0x1d, 0xba, 0x22, 0xa0, 0xed, 0x7e, 0x73, 0x85,
+	0xb5, 0x5d, 0x1a, 0x30, 0xc7, 0xed, 0x29, 0x82, 0x45, 0xc5, 0xfd, 0x7e, 0xc7, 0xf5, 0x1a, 0xb4,
+	0xb3, 0x12, 0x30, 0x87, 0x05, 0xf2, 0x57, 0x52, 0xd8, 0x45, 0xc0, 0x5b, 0xcc, 0xa7, 0x8e, 0x4b,
+	0x1c, 0x46, 0x03, 0x42, 0xef, 0xf7, 0x69, 0xc0, 0xec, 0x6b, 0x30, 0x3b, 0x84, 0x0d, 0x7a, 0x5e,
+	0x37, 0xa0, 0xf8, 0x1d, 0xc8, 0x05, 0x11, 0xba, 0x84, 0x16, 0x93, 0x4b, 0xb9, 0xd5, 0xe2, 0x72,
+	0x78, 0xea, 0x68, 0x0d, 0x31, 0x09, 0xed, 0x1f, 0x20, 0x80, 0x68, 0x0e, 0x57, 0x00, 0xe4, 0xec,
+	0x25, 0x27, 0xd8, 0x29, 0xa1, 0x45, 0xb4, 0x64, 0x11, 0x03, 0x83, 0x4f, 0xc3, 0x91, 0x08, 0xba,
+	0xee, 0x6d, 0xed, 0x38, 0x7e, 0xa3, 0x94, 0x10, 0x64, 0x07, 0x27, 0x30, 0x06, 0xcb, 0x77, 0x18,
+	0x2d, 0x25, 0x17, 0xd1, 0x52, 0x92, 0x88, 0x31, 0x9e, 0x83, 0x34, 0xa3, 0x5d, 0xa7, 0xcb, 0x4a,
+	0xd6, 0x22, 0x5a, 0xca, 0x12, 0x05, 0xd9, 0x1f, 0x42, 0xee, 0x66, 0x3f, 0xd8, 0x51, 0x62, 0xe2,
+	0x4b, 0x30, 0x29, 0xf9, 0x69, 0x59, 0xe6, 0xe3, 0xb2, 0xac, 0x35, 0x9c, 0x1e, 0xa3, 0x7e, 0xed,
+	0x95, 0x2f, 0x07, 0xd5, 0xb4, 0x44, 0xed, 0x0f, 0xaa, 0x7a, 0x15, 0xd1, 0x03, 0xbb, 0x00, 0x79,
+	0xc9, 0x58, 0x6a, 0xca, 0xfe, 0x63, 0x02, 0xf2, 0xb7, 0xfa, 0xd4, 0xdf, 0xd5, 0x5b, 0x95, 0x21,
+	0x13, 0xd0, 0x0e, 0xad, 0x33, 0xcf, 0x17, 0x12, 0x67, 0x49, 0x08, 0xe3, 0x22, 0xa4, 0x3a, 0x6d,
+	0xb7, 0xcd, 0x84, 0x8c, 0x53, 0x44, 0x02, 0xf8, 0x1c, 0xa4, 0x02, 0xe6, 0xf8, 0x4c, 0x08, 0x96,
+	0x5b, 0x2d, 0x2f, 0xcb, 0xcb, 0x5e, 0xd6, 0x97, 0xbd, 0x7c, 0x5b, 0x5f, 0x76, 0x2d, 0xf3, 0x70,
+	0x50, 0x9d, 0xf8, 0xfc, 0xaf, 0x55, 0x44, 0xe4, 0x12, 0xfc, 0x0e, 0x24, 0x69, 0xb7, 0x51, 0xb2,
+	0xc6, 0x58, 0xc9, 0x17, 0xe0, 0x33, 0x90, 0x6d, 0xb4, 0x7d, 0x5a, 0x67, 0x6d, 0xaf, 0x5b, 0x4a,
+	0x2d, 0xa2, 0xa5, 0xc2, 0xea, 0x6c, 0xa4, 0x92, 0x0d, 0x3d, 0x45, 0x22, 0x2a, 0x7c, 0x1a, 0xd2,
+	0x01, 0xbf, 0x87, 0xa0, 0x34, 0xb9, 0x98, 0x5c, 0xca, 0xd6, 0x8a, 0xfb, 0x83, 0xea, 0x8c, 0xc4,
+	0x9c, 0xf6, 0xdc, 0x36, 0xa3, 0x6e, 0x8f, 0xed, 0x12, 0x45, 0x83, 0x4f, 0xc1, 0x64, 0x83, 0x76,
+	0x28, 0xb7, 0x9e, 0x8c, 0xd0, 0xf8, 0x8c, 0xc1, 0x5e, 0x4c, 0x10, 0x4d, 0x70, 0xd9, 0xca, 0xa4,
+	0x67, 0x26, 0xed, 0x7f, 0x21, 0xc0, 0x5b, 0x8e, 0xdb, 0xeb, 0xd0, 0x67, 0xd6, 0x67, 0xa8, 0xb9,
+	0xc4, 0x73, 0x6b, 0x2e, 0x39, 0xae, 0xe6, 0x22, 0x35, 0x58, 0xe3, 0xa9, 0x21, 0xf5, 0x14, 0x35,
+	0xd8, 0x57, 0x21, 0x2d, 0x51, 0x4f, 0xb3, 0xa1, 0x48, 0xe6, 0xa4, 0x96, 0x66, 0x26, 0x92, 0x26,
+	0x29, 0xce, 0x69, 0x7f, 0x0f, 0xa6, 0x94, 0x1e, 0x95, 0x4f, 0xaf, 0x3d, 0xb3, 0x0f, 0x14, 0x1e,
+	0x0e, 0xaa, 0x28, 0xf2, 0x83, 0xd0, 0xf8, 0xf1, 0x1b, 0x62, 0x6f, 0x16, 0x28, 0x7d, 0x4f, 0x2f,
+	0x0b, 0x68, 0x79, 0xb3, 0xdb, 0xa2, 0x01, 0x5f, 0x68, 0x71, 0x55, 0x11, 0x49, 0x63, 0x7f, 0x17,
+	0x66, 0x87, 0xae, 0x53, 0x1d, 0xe3, 0x2c, 0xa4, 0x03, 0xea, 0xb7, 0xc3, 0xa8, 0x62, 0x28, 0x64,
+	0x4b, 0xe0, 0x8d, 0xed, 0x05, 0x4c, 0x14, 0xfd, 0x78, 0xbb, 0xff, 0x1a, 0x41, 0xfe, 0xaa, 0xb3,
+	0x4d, 0x3b, 0xda, 0x8e, 0x30, 0x58, 0x5d, 0xc7, 0xa5, 0x4a, 0x9f, 0x62, 0xcc, 0xa3, 0xc7, 0x27,
+	0x4e, 0xa7, 0x4f, 0x25, 0xcb, 0x0c, 0x51, 0xd0, 0xb8, 0x1e, 0x89, 0x9e, 0xdb, 0x23, 0x51, 0x68,
+	0x57, 0xf6, 0x49, 0x98, 0x52, 0xe7, 0x55, 0x8a, 0x8a, 0x0e, 0xc7, 0x15, 0x95, 0xd5, 0x87, 0xb3,
+	0x7f, 0x8c, 0x60, 0x6a, 0xe8, 0xbe, 0xb0, 0x0d, 0xe9, 0x0e, 0x5f, 0x1a, 0x48, 0xe1, 0x6a, 0xb0,
+	0x3f, 0xa8, 0x2a, 0x0c, 0x51, 0xff, 0xfc, 0xf6, 0x69, 0x97, 0x09, 0xbd, 0x27, 0x84, 0xde, 0xe7,
+	0x22, 0xbd, 0x7f, 0xd0, 0x65, 0xfe, 0xae, 0xbe, 0xfc, 0x69, 0xae, 0x45, 0x1e, 0xfa, 0x14, 0x39,
+	0xd1, 0x03, 0xfc, 0x2a, 0x58, 0x3b, 0x3c, 0x8e, 0x73, 0xa5, 0x58, 0xb5, 0xd4, 0xfe, 0xa0, 0x8a,
+	0xde, 0x24, 0x02, 0x65, 0xff, 0x03, 0x41, 0xde, 0xe4, 0x82, 0x2f, 0x41, 0x36, 0x4c, 0x51, 0x25,
+	0xf4, 0x54, 0x5d, 0x14, 0xd4, 0xa6, 0x09, 0x16, 0x08, 0x8d, 0x44, 0x8b, 0xf1, 0x02, 0x58, 0x9d,
+	0x76, 0x97, 0x8a, 0x1b, 0xca, 0xd6, 0x32, 0xfb, 0x83, 0xaa, 0x80, 0x89, 0xf8, 0xc5, 0xbb, 0x80,
+	0x03, 0xe6, 0xf7, 0xeb, 0xac, 0xef, 0xd3, 0xc6, 0x35, 0xca, 0x9c, 0x86, 0xc3, 0x9c, 0x52, 0x52,
+	0x48, 0x68, 0x04, 0x34, 0xa1, 0xd9, 0x9b, 0x4e, 0xdb, 0xaf, 0xbd, 0xcd, 0x77, 0xfa, 0x72, 0x50,
+	0x95, 0xc6, 0xa1, 0x8e, 0xbb, 0x3f, 0xa8, 0x2e, 0x1c, 0x64, 0x63, 0xb8, 0xf3, 0x88, 0x4d, 0x6c,
+	0x17, 0xd2, 0xd2, 0xbe, 0xf1, 0x6b, 0x71, 0x61, 0x93, 0xb5, 0xb4, 0x14, 0xc6, 0x14, 0xa4, 0x0a,
+	0x29, 0x71, 0x83, 0x42, 0x12, 0x54, 0xcb, 0xee, 0x0f, 0xaa, 0x12, 0x41, 0xe4, 0x1f, 0x97, 0xd4,
+	0xd0, 0xaf, 0x90, 0x94, 0xc3, 0x4a, 0xc5, 0x17, 0x21, 0x7f, 0x95, 0xb6, 0x9c, 0xfa, 0xae, 0xda,
+	0xb4, 0xa8, 0xd9, 0xf1, 0x0d, 0x91, 0xe6, 0x71, 0x1c, 0xf2, 0xe1, 0x8e, 0x77, 0xdd, 0x40, 0x05,
+	0x89, 0x5c, 0x88, 0xbb, 0x16, 0xd8, 0x3f, 0x45, 0xa0, 0x3c, 0xeb, 0x99, 0x0c, 0xe7, 0x3c, 0x4c,
+	0x06, 0x62, 0x47, 0x6d, 0x38, 0xa6, 0xc3, 0x8a, 0x89, 0xc8, 0x64, 0x14, 0x21, 0xd1, 0x03, 0xbc,
+	0x3c, 0x54, 0x00, 0x48, 0xc1, 0x0a, 0xfb, 0x83, 0xaa, 0x81, 0x35, 0x0b, 0x02, 0xfb, 0x27, 0x08,
+	0x72, 0xb7, 0x9d, 0x76, 0xe8, 0xb4, 0x45, 0x48, 0xdd, 0xe7, 0xd1, 0x43, 0x79, 0xad, 0x04, 0x78,
+	0x78, 0x6c, 0xd0, 0x8e, 0xb3, 0x7b, 0xc1, 0xf3, 0x05, 0xcf, 0x29, 0x12, 0xc2, 0x51, 0x8a, 0xb5,
+	0x46, 0xa6, 0xd8, 0xd4, 0xd8, 0x89, 0xe2, 0xb2, 0x95, 0x49, 0xcc, 0x24, 0xed, 0x1f, 0x22, 0xc8,
+	0xcb, 0x93, 0x29, 0xf7, 0x3c, 0x0f, 0x69, 0x79, 0x70, 0x65, 0xde, 0x87, 0x46, 0x53, 0x30, 0x22,
+	0xa9, 0x5a, 0x82, 0xbf, 0x05, 0x85, 0x86, 0xef, 0xf5, 0x7a, 0xb4, 0xb1, 0xa5, 0x42, 0x72, 0x22,
+	0x1e, 0x92, 0x37, 0xcc, 0x79, 0x12, 0x23, 0xb7, 0xff, 0xc4, 0x83, 0x80, 0x0c, 0x8f, 0x4a, 0x55,
+	0xa1, 0x88, 0xe8, 0xb9, 0x73, 0x61, 0x62, 0xdc, 0x5c, 0x38, 0x07, 0xe9, 0x96, 0xef, 0xf5, 0x7b,
+	0x81, 0xf0, 0xb8, 0x2c, 0x51, 0xd0, 0x78, 0x39, 0xd2, 0xbe, 0x0c, 0x05, 0x2d, 0xca, 0x21, 0x39,
+	0xa2, 0x1c, 0xcf, 0x11, 0x9b, 0x0d, 0xda, 0x65, 0xed, 0x66, 0x3b, 0x8c, 0xfa, 0x8a, 0xde, 0xfe,
+	0x0c, 0xc1, 0x4c, 0x9c, 0x04, 0x7f, 0xd3, 0x30, 0x73, 0xce, 0xee, 0xc4, 0xe1, 0xec, 0x64, 0xa4,
+	0x08, 0x44, 0x2c, 0xd3, 0x2e, 0x50, 0x7e, 0x0f, 0x72, 0x06, 0x9a, 0xe7, 0xda, 0x7b, 0x54, 0x9b,
+	0x24, 0x1f, 0x46, 0xbe, 0x98, 0x90, 0x66, 0x2a, 0x80, 0x73, 0x89, 0xb3, 0x88, 0x1b, 0xf4, 0xd4,
+	0xd0, 0x4d, 0xe2, 0xb3, 0x60, 0x35, 0x7d, 0xcf, 0x1d, 0xeb, 0x9a, 0xc4, 0x0a, 0xfc, 0x36, 0x24,
+	0x98, 0x37, 0xd6, 0x25, 0x25, 0x98, 0xc7, 0xef, 0x48, 0x09, 0x9f, 0x94, 0x15, 0xb2, 0x84, 0xec,
+	0x5f, 0x21, 0x98, 0xe6, 0x6b, 0xa4, 0x06, 0xd6, 0x77, 0xfa, 0xdd, 0x7b, 0x78, 0x09, 0x66, 0xf8,
+	0x4e, 0x77, 0xdb, 0x2a, 0xa5, 0xde, 0x6d, 0x37, 0x94, 0x98, 0x05, 0x8e, 0xd7, 0x99, 0x76, 0xb3,
+	0x81, 0xe7, 0x61, 0xb2, 0x1f, 0x48, 0x02, 0x29, 0x73, 0x9a, 0x83, 0x9b, 0x0d, 0xfc, 0x86, 0xb1,
+	0xdd, 0x61, 0x41, 0x38, 0x8c, 0x2d, 0x27, 0x21, 0x5d, 0xe7, 0x1b, 0x4b, 0x3b, 0xe1, 0x29, 0x3d,
+	0x24, 0x16, 0x07, 0x22, 0x6a, 0xda, 0xfe, 0x3a, 0x64, 0xc3, 0xd5, 0x23, 0x33, 0xf9, 0xc8, 0x1b,
+	0xb0, 0xcf, 0xc3, 0xb4, 0x8c, 0x99, 0xa3, 0x17, 0xe7, 0x47, 0x2d, 0xce, 0xeb, 0xc5, 0x47, 0x21,
+	0x25, 0xb5, 0x82, 0xc1, 0x12, 0x59, 0x45, 0x2d, 0xe1, 0x63, 0xbb, 0x04, 0x73, 0xb7, 0x7d, 0xa7,
+	0x1b, 0x34, 0xa9, 0x2f, 0x88, 0x42, 0xdb, 0xb5, 0x5f, 0x81, 0x59, 0x1e, 0x27, 0xa8, 0x1f, 0xac,
+	0x7b, 0xfd, 0x2e, 0xd3, 0x8d, 0xd6, 0x69, 0x28, 0x0e, 0xa3, 0x95, 0xa9, 0x17, 0x21, 0x55, 0xe7,
+	0x08, 0xc1, 0x7d, 0x8a, 0x48, 0xc0, 0xfe, 0x39, 0x02, 0x7c, 0x91, 0x32, 0xc1, 0x7a, 0x73, 0x23,
+	0x30, 0x6a, 0x61, 0xd7, 0x61, 0xf5, 0x1d, 0xea, 0x07, 0xba, 0x2e, 0xd4, 0xf0, 0xff, 0xa3, 0x16,
+	0xb6, 0xcf, 0xc0, 0xec, 0xd0, 0x29, 0x95, 0x4c, 0x65, 0xc8, 0xd4, 0x15, 0x4e, 0xd5, 0x2e, 0x21,
+	0x6c, 0xff, 0x26, 0x01, 0x19, 0x79, 0xb7, 0xb4, 0x89, 0xcf, 0x40, 0xae, 0xc9, 0x6d, 0xcd, 0xef,
+	0xf9, 0x6d, 0xa5, 0x02, 0xab, 0x36, 0xbd, 0x3f, 0xa8, 0x9a, 0x68, 0x62, 0x02, 0xf8, 0xcd, 0x98,
+	0xe1, 0xd5, 0x8a, 0x7b, 0x83, 0x6a, 0xfa, 0x3b, 0xdc, 0xf8, 0x36, 0x78, 0xf6, 0x12, 0x66, 0xb8,
+	0x11, 0x9a, 0xe3, 0x15, 0xe5, 0x6d, 0xa2, 0x30, 0xae, 0xbd, 0xab, 0x92, 0xff, 0xc9, 0x56, 0x9b,
+	0xed, 0xf4, 0xb7, 0x97, 0xeb, 0x9e, 0xcb, 0x5b, 0x6a, 0x97, 0xb2, 0x1d, 0xda, 0x0f, 0x56, 0xea,
+	0x9e, 0xeb, 0x7a, 0xdd, 0x15, 0xd1, 0x41, 0x0b, 0xa1, 0x79, 0x0a, 0xe6, 0xcb, 0x95, 0x03, 0xde,
+	0x86, 0x49, 0xb6, 0xe3, 0x7b, 0xfd, 0xd6, 0x8e, 0xc8, 0x2e, 0xc9, 0xda, 0xb9, 0xf1, 0xf9, 0x69,
+	0x0e, 0x44, 0x0f, 0xf0, 0x71, 0xae, 0x2d, 0x5a, 0xbf, 0x17, 0xf4, 0x5d, 0x91, 0x9e, 0xa6, 0x74,
+	0x69, 0x15, 0xa2, 0xed, 0xcf, 0x12, 0x50, 0x15, 0x26, 0x7c, 0x47, 0x94, 0x80, 0x17, 0x3c, 0xff,
+	0x1a, 0x65, 0x7e, 0xbb, 0x7e, 0xdd, 0x71, 0xa9, 0xb6, 0x8d, 0x2a, 0xe4, 0x5c, 0x81, 0xbc, 0x6b,
+	0x38, 0x07, 0xb8, 0x21, 0x1d, 0x3e, 0x06, 0x20, 0xdc, 0x4e, 0xce, 0x4b, 0x3f, 0xc9, 0x0a, 0x8c,
+	0x98, 0x5e, 0x1f, 0xd2, 0xd4, 0xca, 0x98, 0x92, 0x29, 0x0d, 0x6d, 0xc6, 0x35, 0x34, 0x36, 0x9f,
+	0x50, 0x2d, 0xa6, 0xad, 0xa7, 0x86, 0x6d, 0xdd, 0xfe, 0x0b, 0x82, 0xca, 0x55, 0x7d, 0xf2, 0xe7,
+	0x54, 0x87, 0x96, 0x37, 0xf1, 0x82, 0xe4, 0x4d, 0xfe, 0x77, 0xf2, 0xda, 0x7f, 0x30, 0x5c, 0x9e,
+	0xd0, 0xa6, 0x96, 0x63, 0xdd, 0x48, 0x17, 0x2f, 0xe2, 0x98, 0x89, 0x17, 0x78, 0x2d, 0xc9, 0xd8,
+	0xb5, 0xbc, 0x0f, 0xb3, 0x43, 0x12, 0xa8, 0x70, 0x70, 0x02, 0x2c, 0x9f, 0x36, 0x75, 0xf2, 0xc5,
+	0xf1, 0x18, 0x4f, 0x9b, 0x44, 0xcc, 0xdb, 0xbf, 0x43, 0x30, 0x73, 0x91, 0xb2, 0xe1, 0xb2, 0xe6,
+	0x65, 0x92, 0xff, 0x12, 0x1c, 0x31, 0xce, 0xaf, 0xa4, 0x7f, 0x2b, 0x56, 0xcb, 0xbc, 0x12, 0xc9,
+	0xbf, 0xd9, 0x6d, 0xd0, 0x4f, 0x55, 0xd3, 0x3b, 0x5c, 0xc6, 0xdc, 0x84, 0x9c, 0x31, 0x89, 0xd7,
+	0x62, 0x05, 0xcc, 0xc8, 0xce, 0xa6, 0x38, 0xaa, 0xb3, 0x09, 0xd3, 0xfd, 0x16, 0x60, 0xd1, 0x87,
+	0x0b, 0xb6, 0x66, 0xa4, 0x16, 0xd8, 0x2b, 0x61, 0x3d, 0x13, 0xc2, 0xf8, 0x38, 0x58, 0xbe, 0xf7,
+	0x40, 0x57, 0xa6, 0x53, 0xd1, 0x96, 0xc4, 0x7b, 0x40, 0xc4, 0x94, 0x7d, 0x1e, 0x92, 0xc4, 0x7b,
+	0xc0, 0x9f, 0xf9, 0x7c, 0xa7, 0xdb, 0xa2, 0x77, 0xc2, 0x7e, 0x24, 0x4f, 0x0c, 0xcc, 0x21, 0xf9,
+	0x75, 0x1d, 0x8e, 0x98, 0x27, 0x92, 0xd7, 0xbd, 0x0c, 0x93, 0xb7, 0xfa, 0xa6, 0xba, 0x8a, 0x31,
+	0x75, 0x89, 0x25, 0x44, 0x13, 0x71, 0x9b, 0x81, 0x08, 0x8f, 0x17, 0x20, 0xcb, 0x9c, 0xed, 0x0e,
+	0xbd, 0x1e, 0xf9, 0x7c, 0x84, 0xe0, 0xb3, 0xbc, 0x95, 0xba, 0x63, 0x14, 0x0a, 0x11, 0x02, 0x9f,
+	0x82, 0x99, 0xe8, 0xcc, 0x37, 0x7d, 0xda, 0x6c, 0x7f, 0x2a, 0x6e, 0x38, 0x4f, 0x0e, 0xe0, 0xf1,
+	0x12, 0x4c, 0x47, 0xb8, 0x2d, 0x91, 0x76, 0x2d, 0x41, 0x1a, 0x47, 0x73, 0xdd, 0x08, 0x71, 0x3f,
+	0xb8, 0xdf, 0x77, 0x3a, 0x22, 0x90, 0xe5, 0x89, 0x81, 0xb1, 0x7f, 0x8f, 0xe0, 0x88, 0xbc, 0x6a,
+	0xe6, 0xb0, 0x97, 0xd2, 0xea, 0x7f, 0x81, 0x00, 0x9b, 0x12, 0x28, 0xd3, 0x7a, 0xdd, 0x7c, 0x6e,
+	0xe2, 0x79, 0x3d, 0x37, 0xea, 0x3d, 0x95, 0xb7, 0xa0, 0xaa, 0x04, 0x14, 0xef, 0xbe, 0xb2, 0x05,
+	0x95, 0x18, 0x5d, 0xfd, 0xf1, 0xce, 0x79, 0x7b, 0x97, 0xd1, 0x40, 0x35, 0x90, 0xa2, 0x73, 0x16,
+	0x08, 0x22, 0xff, 0xf8, 0x5e, 0xfa, 0x71, 0xc3, 0x8a, 0xf6, 0x8a, 0x3f, 0x60, 0xd8, 0x3f, 0x4a,
+	0xc0, 0xd4, 0x1d, 0xaf, 0xd3, 0x77, 0xe9, 0x4b, 0xa8, 0x67, 0x6c, 0x43, 0x9e, 0x39, 0x7e, 0x8b,
+	0x32, 0xd9, 0x8b, 0xc8, 0xd6, 0x8a, 0x0c, 0xe1, 0xa2, 0xee, 0x37, 0x65, 0x76, 0xbf, 0xa5, 0xe8,
+	0x2a, 0xd2, 0xe2, 0x9d, 0x4b, 0x83, 0xf6, 0xcf, 0x10, 0x14, 0xb4, 0x46, 0xc2, 0xbe, 0x76, 0xf2,
+	0x13, 0x81, 0x19, 0xf1, 0x40, 0x27, 0x49, 0xa3, 0x7e, 0x5f, 0x11, 0x12, 0x3d, 0xc0, 0x57, 0xa2,
+	0x9d, 0x0e, 0xbc, 0x32, 0xc9, 0xfe, 0x47, 0xb1, 0x78, 0x55, 0xb1, 0x50, 0xcf, 0xfc, 0x66, 0x5b,
+	0x18, 0x1e, 0xee, 0xcf, 0x08, 0xf2, 0xe6, 0xa2, 0xe7, 0x29, 0x17, 0x17, 0x54, 0xb9, 0x6f, 0xbc,
+	0x1e, 0x71, 0x58, 0x15, 0xfe, 0x91, 0xf1, 0x25, 0x9f, 0x6e, 0x7c, 0xd6, 0xd3, 0x8d, 0x2f, 0xf5,
+	0x1f, 0x8c, 0xef, 0xb7, 0x08, 0xd2, 0x4a, 0x8e, 0x05, 0xb3, 0x81, 0x39, 0x70, 0xa8, 0xd7, 0x4d,
+	0x1d, 0x3e, 0x8b, 0xe3, 0xfc, 0xcf, 0xcf, 0x7e, 0xea, 0x04, 0x64, 0xc3, 0x4f, 0x02, 0x38, 0x07,
+	0x93, 0x17, 0x6e, 0x90, 0x0f, 0xd7, 0xc8, 0xc6, 0xcc, 0x04, 0xce, 0x43, 0xa6, 0xb6, 0xb6, 0x7e,
+	0x45, 0x40, 0x68, 0x75, 0x0d, 0xd2, 0xfc, 0xe3, 0x08, 0xf5, 0xf1, 0xbb, 0x60, 0xf1, 0x11, 0x36,
+	0xb2, 0x9d, 0xf1, 0x3d, 0xa6, 0x3c, 0x17, 0x47, 0xab, 0xe6, 0x69, 0x62, 0xf5, 0x9f, 0x96, 0xce,
+	0x00, 0x3e, 0xfe, 0x06, 0xa4, 0x64, 0x58, 0x37, 0xc8, 0xcd, 0x6f, 0x03, 0xe5, 0xf9, 0x03, 0x78,
+	0xcd, 0xe7, 0x6b, 0x08, 0x5f, 0x87, 0x9c, 0x40, 0xaa, 0xf7, 0xb2, 0x85, 0xf8, 0xb3, 0xd5, 0x10,
+	0xa7, 0x63, 0x87, 0xcc, 0x1a, 0xfc, 0xce, 0x41, 0x4a, 0x78, 0x99, 0x79, 0x1a, 0xf3, 0x85, 0xb9,
+	0x3c, 0x7f, 0x00, 0xaf, 0x57, 0xe3, 0xf7, 0xc0, 0xe2, 0xdd, 0x9f, 0xa9, 0x0e, 0xe3, 0x99, 0xab,
+	0x3c, 0x17, 0x47, 0x1b, 0xdb, 0xbe, 0x1f, 0xbe, 0xd6, 0xcd, 0xc7, 0x9f, 0x2d, 0xf4, 0xf2, 0xd2,
+	0xc1, 0x89, 0x70, 0xe7, 0x1b, 0x90, 0x37, 0xfb, 0x4e, 0x7c, 0x6c, 0x78, 0xab, 0x58, 0x9b, 0x5a,
+	0xae, 0x1c, 0x36, 0x1d, 0x32, 0xbc, 0x0a, 0x39, 0xa3, 0xe7, 0x33, 0xd5, 0x7a, 0xb0, 0x61, 0x2d,
+	0x1f, 0x3b, 0x64, 0x36, 0xe4, 0x76, 0x11, 0x32, 0xbc, 0x64, 0xe2, 0x99, 0x03, 0x1f, 0x8d, 0x57,
+	0x46, 0x46, 0x46, 0x2c, 0x2f, 0x8c, 0x9e, 0x0c, 0x19, 0x7d, 0x1b, 0xb2, 0x17, 0x29, 0x53, 0x0e,
+	0x36, 0x1f, 0x0f, 0x59, 0x23, 0x34, 0x35, 0x1c, 0xf6, 0xec, 0x89, 0xd5, 0x8f, 0x21, 0xa3, 0x1f,
+	0x38, 0xf0, 0x2d, 0x28, 0x0c, 0xb7, 0xf7, 0xf8, 0x55, 0x43, 0x31, 0xc3, 0xaf, 0x26, 0xe5, 0x45,
+	0x63, 0x6a, 0xf4, 0x9b, 0xc0, 0xc4, 0x12, 0x5a, 0xfd, 0x58, 0x7f, 0x19, 0xdd, 0x70, 0x98, 0x83,
+	0x6f, 0x40, 0x41, 0xc8, 0x1d, 0x7e, 0x3a, 0x1d, 0xb2, 0xcf, 0x03, 0xdf, 0x69, 0xcb, 0xc7, 0x0e,
+	0x99, 0xd5, 0x1b, 0xd4, 0x3e, 0x7a, 0xf4, 0xb8, 0x32, 0xf1, 0xc5, 0xe3, 0xca, 0xc4, 0x57, 0x8f,
+	0x2b, 0xe8, 0xfb, 0x7b, 0x15, 0xf4, 0xcb, 0xbd, 0x0a, 0x7a, 0xb8, 0x57, 0x41, 0x8f, 0xf6, 0x2a,
+	0xe8, 0x6f, 0x7b, 0x15, 0xf4, 0xf7, 0xbd, 0xca, 0xc4, 0x57, 0x7b, 0x15, 0xf4, 0xf9, 0x93, 0xca,
+	0xc4, 0xa3, 0x27, 0x95, 0x89, 0x2f, 0x9e, 0x54, 0x26, 0x3e, 0x7a, 0xcd, 0xc8, 0x50, 0x2d, 0xdf,
+	0x69, 0x3a, 0x5d, 0x67, 0xa5, 0xe3, 0xdd, 0x6b, 0xaf, 0x98, 0x1f, 0xab, 0xb7, 0xd3, 0xe2, 0xef,
+	0xad, 0x7f, 0x0f, 0x00, 0x4d, 0xc9, 0x11, 0xf6, 0xc3, 0x1e, 0x00, 0x00,
 }
 
 func (x Direction) String() string {
@@ -3105,6 +3401,14 @@ func (this *EntryAdapter) Equal(that interface{}) bool {
 	if this.Line != that1.Line {
 		return false
 	}
+	if len(this.StructuredMetadata) != len(that1.StructuredMetadata) {
+		return false
+	}
+	for i := range this.StructuredMetadata {
+		if !this.StructuredMetadata[i].Equal(that1.StructuredMetadata[i]) {
+			return false
+		}
+	}
 	return true
 }
 func (this *Sample) Equal(that interface{}) bool {
@@ -4081,62 +4385,215 @@ func (this *IndexStatsResponse) Equal(that interface{}) bool {
 	}
 	return true
 }
-func (this *StreamRatesRequest) GoString() string {
-	if this == nil {
-		return "nil"
+func (this *VolumeRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
 	}
-	s := make([]string, 0, 4)
-	s = append(s, "&logproto.StreamRatesRequest{")
-	s = append(s, "}")
-	return strings.Join(s, "")
-}
-func (this *StreamRatesResponse) GoString() string {
-	if this == nil {
-		return "nil"
+
+	that1, ok := that.(*VolumeRequest)
+	if !ok {
+		that2, ok := that.(VolumeRequest)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
-	s := make([]string, 0, 5)
-	s = append(s, "&logproto.StreamRatesResponse{")
-	if this.StreamRates != nil {
-		s = append(s, "StreamRates: "+fmt.Sprintf("%#v", this.StreamRates)+",\n")
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
 	}
-	s = append(s, "}")
-	return strings.Join(s, "")
-}
-func (this *StreamRate) GoString() string {
-	if this == nil {
-		return "nil"
+	if !this.From.Equal(that1.From) {
+		return false
 	}
-	s := make([]string, 0, 8)
-	s = append(s, "&logproto.StreamRate{")
-	s = append(s, "StreamHash: "+fmt.Sprintf("%#v", this.StreamHash)+",\n")
-	s = append(s, "StreamHashNoShard: "+fmt.Sprintf("%#v", this.StreamHashNoShard)+",\n")
-	s = append(s, "Rate: "+fmt.Sprintf("%#v", this.Rate)+",\n")
-	s = append(s, "Tenant: "+fmt.Sprintf("%#v", this.Tenant)+",\n")
-	s = append(s, "}")
-	return strings.Join(s, "")
-}
-func (this *PushRequest) GoString() string {
-	if this == nil {
-		return "nil"
+	if !this.Through.Equal(that1.Through) {
+		return false
 	}
-	s := make([]string, 0, 5)
-	s = append(s, "&logproto.PushRequest{")
-	s = append(s, "Streams: "+fmt.Sprintf("%#v", this.Streams)+",\n")
-	s = append(s, "}")
-	return strings.Join(s, "")
-}
-func (this *PushResponse) GoString() string {
-	if this == nil {
-		return "nil"
+	if this.Matchers != that1.Matchers {
+		return false
 	}
-	s := make([]string, 0, 4)
-	s = append(s, "&logproto.PushResponse{")
-	s = append(s, "}")
-	return strings.Join(s, "")
-}
-func (this *QueryRequest) GoString() string {
-	if this == nil {
-		return "nil"
+	if len(this.TargetLabels) != len(that1.TargetLabels) {
+		return false
+	}
+	for i := range this.TargetLabels {
+		if this.TargetLabels[i] != that1.TargetLabels[i] {
+			return false
+		}
+	}
+	if this.Limit != that1.Limit {
+		return false
+	}
+	if this.Streams != that1.Streams {
+		return false
+	}
+	return true
+}
+func (this *VolumeResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*VolumeResponse)
+	if !ok {
+		that2, ok := that.(VolumeResponse)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if len(this.Volumes) != len(that1.Volumes) {
+		return false
+	}
+	for i := range this.Volumes {
+		if !this.Volumes[i].Equal(&that1.Volumes[i]) {
+			return false
+		}
+	}
+	if len(this.Streams) != len(that1.Streams) {
+		return false
+	}
+	for i := range this.Streams {
+		if !this.Streams[i].Equal(&that1.Streams[i]) {
+			return false
+		}
+	}
+	return true
+}
+func (this *StreamVolume) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*StreamVolume)
+	if !ok {
+		that2, ok := that.(StreamVolume)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Fingerprint != that1.Fingerprint {
+		return false
+	}
+	if this.Name != that1.Name {
+		return false
+	}
+	if this.Chunks != that1.Chunks {
+		return false
+	}
+	if this.Bytes != that1.Bytes {
+		return false
+	}
+	if this.Entries != that1.Entries {
+		return false
+	}
+	return true
+}
+func (this *Volume) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Volume)
+	if !ok {
+		that2, ok := that.(Volume)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Name != that1.Name {
+		return false
+	}
+	if this.Streams != that1.Streams {
+		return false
+	}
+	if this.Chunks != that1.Chunks {
+		return false
+	}
+	if this.Bytes != that1.Bytes {
+		return false
+	}
+	if this.Entries != that1.Entries {
+		return false
+	}
+	return true
+}
+func (this *StreamRatesRequest) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 4)
+	s = append(s, "&logproto.StreamRatesRequest{")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *StreamRatesResponse) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&logproto.StreamRatesResponse{")
+	if this.StreamRates != nil {
+		s = append(s, "StreamRates: "+fmt.Sprintf("%#v", this.StreamRates)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *StreamRate) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 8)
+	s = append(s, "&logproto.StreamRate{")
+	s = append(s, "StreamHash: "+fmt.Sprintf("%#v", this.StreamHash)+",\n")
+	s = append(s, "StreamHashNoShard: "+fmt.Sprintf("%#v", this.StreamHashNoShard)+",\n")
+	s = append(s, "Rate: "+fmt.Sprintf("%#v", this.Rate)+",\n")
+	s = append(s, "Tenant: "+fmt.Sprintf("%#v", this.Tenant)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *PushRequest) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&logproto.PushRequest{")
+	s = append(s, "Streams: "+fmt.Sprintf("%#v", this.Streams)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *PushResponse) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 4)
+	s = append(s, "&logproto.PushResponse{")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *QueryRequest) GoString() string {
+	if this == nil {
+		return "nil"
 	}
 	s := make([]string, 0, 11)
 	s = append(s, "&logproto.QueryRequest{")
@@ -4247,10 +4704,11 @@ func (this *EntryAdapter) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 6)
+	s := make([]string, 0, 7)
 	s = append(s, "&logproto.EntryAdapter{")
 	s = append(s, "Timestamp: "+fmt.Sprintf("%#v", this.Timestamp)+",\n")
 	s = append(s, "Line: "+fmt.Sprintf("%#v", this.Line)+",\n")
+	s = append(s, "StructuredMetadata: "+fmt.Sprintf("%#v", this.StructuredMetadata)+",\n")
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -4660,6 +5118,72 @@ func (this *IndexStatsResponse) GoString() string {
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
+func (this *VolumeRequest) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 10)
+	s = append(s, "&logproto.VolumeRequest{")
+	s = append(s, "From: "+fmt.Sprintf("%#v", this.From)+",\n")
+	s = append(s, "Through: "+fmt.Sprintf("%#v", this.Through)+",\n")
+	s = append(s, "Matchers: "+fmt.Sprintf("%#v", this.Matchers)+",\n")
+	s = append(s, "TargetLabels: "+fmt.Sprintf("%#v", this.TargetLabels)+",\n")
+	s = append(s, "Limit: "+fmt.Sprintf("%#v", this.Limit)+",\n")
+	s = append(s, "Streams: "+fmt.Sprintf("%#v", this.Streams)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *VolumeResponse) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&logproto.VolumeResponse{")
+	if this.Volumes != nil {
+		vs := make([]*Volume, len(this.Volumes))
+		for i := range vs {
+			vs[i] = &this.Volumes[i]
+		}
+		s = append(s, "Volumes: "+fmt.Sprintf("%#v", vs)+",\n")
+	}
+	if this.Streams != nil {
+		vs := make([]*StreamVolume, len(this.Streams))
+		for i := range vs {
+			vs[i] = &this.Streams[i]
+		}
+		s = append(s, "Streams: "+fmt.Sprintf("%#v", vs)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *StreamVolume) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 9)
+	s = append(s, "&logproto.StreamVolume{")
+	s = append(s, "Fingerprint: "+fmt.Sprintf("%#v", this.Fingerprint)+",\n")
+	s = append(s, "Name: "+fmt.Sprintf("%#v", this.Name)+",\n")
+	s = append(s, "Chunks: "+fmt.Sprintf("%#v", this.Chunks)+",\n")
+	s = append(s, "Bytes: "+fmt.Sprintf("%#v", this.Bytes)+",\n")
+	s = append(s, "Entries: "+fmt.Sprintf("%#v", this.Entries)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *Volume) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 9)
+	s = append(s, "&logproto.Volume{")
+	s = append(s, "Name: "+fmt.Sprintf("%#v", this.Name)+",\n")
+	s = append(s, "Streams: "+fmt.Sprintf("%#v", this.Streams)+",\n")
+	s = append(s, "Chunks: "+fmt.Sprintf("%#v", this.Chunks)+",\n")
+	s = append(s, "Bytes: "+fmt.Sprintf("%#v", this.Bytes)+",\n")
+	s = append(s, "Entries: "+fmt.Sprintf("%#v", this.Entries)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
 func valueToGoStringLogproto(v interface{}, typ string) string {
 	rv := reflect.ValueOf(v)
 	if rv.IsNil() {
@@ -4763,6 +5287,9 @@ type QuerierClient interface {
 	// Note: this MUST be the same as the variant defined in
 	// indexgateway.proto on the IndexGateway service.
 	GetStats(ctx context.Context, in *IndexStatsRequest, opts ...grpc.CallOption) (*IndexStatsResponse, error)
+	// Note: this MUST be the same as the variant defined in
+	// indexgateway.proto on the IndexGateway service.
+	GetVolume(ctx context.Context, in *VolumeRequest, opts ...grpc.CallOption) (*VolumeResponse, error)
 }
 
 type querierClient struct {
@@ -4914,6 +5441,15 @@ func (c *querierClient) GetStats(ctx context.Context, in *IndexStatsRequest, opt
 	return out, nil
 }
 
+func (c *querierClient) GetVolume(ctx context.Context, in *VolumeRequest, opts ...grpc.CallOption) (*VolumeResponse, error) {
+	out := new(VolumeResponse)
+	err := c.cc.Invoke(ctx, "/logproto.Querier/GetVolume", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // QuerierServer is the server API for Querier service.
 type QuerierServer interface {
 	Query(*QueryRequest, Querier_QueryServer) error
@@ -4926,6 +5462,9 @@ type QuerierServer interface {
 	// Note: this MUST be the same as the variant defined in
 	// indexgateway.proto on the IndexGateway service.
 	GetStats(context.Context, *IndexStatsRequest) (*IndexStatsResponse, error)
+	// Note: this MUST be the same as the variant defined in
+	// indexgateway.proto on the IndexGateway service.
+	GetVolume(context.Context, *VolumeRequest) (*VolumeResponse, error)
 }
 
 // UnimplementedQuerierServer can be embedded to have forward compatible implementations.
@@ -4956,6 +5495,9 @@ func (*UnimplementedQuerierServer) GetChunkIDs(ctx context.Context, req *GetChun
 func (*UnimplementedQuerierServer) GetStats(ctx context.Context, req *IndexStatsRequest) (*IndexStatsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetStats not implemented")
 }
+func (*UnimplementedQuerierServer) GetVolume(ctx context.Context, req *VolumeRequest) (*VolumeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVolume not implemented")
+}
 
 func RegisterQuerierServer(s *grpc.Server, srv QuerierServer) {
 	s.RegisterService(&_Querier_serviceDesc, srv)
@@ -5114,6 +5656,24 @@ func _Querier_GetStats_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Querier_GetVolume_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VolumeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuerierServer).GetVolume(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/logproto.Querier/GetVolume",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuerierServer).GetVolume(ctx, req.(*VolumeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Querier_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "logproto.Querier",
 	HandlerType: (*QuerierServer)(nil),
@@ -5138,6 +5698,10 @@ var _Querier_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetStats",
 			Handler:    _Querier_GetStats_Handler,
 		},
+		{
+			MethodName: "GetVolume",
+			Handler:    _Querier_GetVolume_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -5945,6 +6509,20 @@ func (m *EntryAdapter) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.StructuredMetadata) > 0 {
+		for iNdEx := len(m.StructuredMetadata) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size := m.StructuredMetadata[iNdEx].Size()
+				i -= size
+				if _, err := m.StructuredMetadata[iNdEx].MarshalTo(dAtA[i:]); err != nil {
+					return 0, err
+				}
+				i = encodeVarintLogproto(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
 	if len(m.Line) > 0 {
 		i -= len(m.Line)
 		copy(dAtA[i:], m.Line)
@@ -7279,12 +7857,227 @@ func (m *IndexStatsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func encodeVarintLogproto(dAtA []byte, offset int, v uint64) int {
-	offset -= sovLogproto(v)
-	base := offset
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
+func (m *VolumeRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *VolumeRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *VolumeRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Streams {
+		i--
+		if m.Streams {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.Limit != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.Limit))
+		i--
+		dAtA[i] = 0x28
+	}
+	if len(m.TargetLabels) > 0 {
+		for iNdEx := len(m.TargetLabels) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.TargetLabels[iNdEx])
+			copy(dAtA[i:], m.TargetLabels[iNdEx])
+			i = encodeVarintLogproto(dAtA, i, uint64(len(m.TargetLabels[iNdEx])))
+			i--
+			dAtA[i] = 0x22
+		}
+	}
+	if len(m.Matchers) > 0 {
+		i -= len(m.Matchers)
+		copy(dAtA[i:], m.Matchers)
+		i = encodeVarintLogproto(dAtA, i, uint64(len(m.Matchers)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.Through != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.Through))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.From != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.From))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *VolumeResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *VolumeResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *VolumeResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Streams) > 0 {
+		for iNdEx := len(m.Streams) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Streams[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintLogproto(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.Volumes) > 0 {
+		for iNdEx := len(m.Volumes) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Volumes[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintLogproto(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *StreamVolume) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *StreamVolume) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *StreamVolume) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Entries != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.Entries))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.Bytes != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.Bytes))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.Chunks != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.Chunks))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintLogproto(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.Fingerprint != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.Fingerprint))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Volume) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Volume) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Volume) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Entries != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.Entries))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.Bytes != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.Bytes))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.Chunks != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.Chunks))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Streams != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.Streams))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintLogproto(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintLogproto(dAtA []byte, offset int, v uint64) int {
+	offset -= sovLogproto(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
 		offset++
 	}
 	dAtA[offset] = uint8(v)
@@ -7550,6 +8343,12 @@ func (m *EntryAdapter) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovLogproto(uint64(l))
 	}
+	if len(m.StructuredMetadata) > 0 {
+		for _, e := range m.StructuredMetadata {
+			l = e.Size()
+			n += 1 + l + sovLogproto(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -8134,41 +8933,143 @@ func (m *IndexStatsResponse) Size() (n int) {
 	return n
 }
 
-func sovLogproto(x uint64) (n int) {
-	return (math_bits.Len64(x|1) + 6) / 7
-}
-func sozLogproto(x uint64) (n int) {
-	return sovLogproto(uint64((x << 1) ^ uint64((int64(x) >> 63))))
-}
-func (this *StreamRatesRequest) String() string {
-	if this == nil {
-		return "nil"
+func (m *VolumeRequest) Size() (n int) {
+	if m == nil {
+		return 0
 	}
-	s := strings.Join([]string{`&StreamRatesRequest{`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *StreamRatesResponse) String() string {
-	if this == nil {
-		return "nil"
+	var l int
+	_ = l
+	if m.From != 0 {
+		n += 1 + sovLogproto(uint64(m.From))
 	}
-	repeatedStringForStreamRates := "[]*StreamRate{"
-	for _, f := range this.StreamRates {
-		repeatedStringForStreamRates += strings.Replace(f.String(), "StreamRate", "StreamRate", 1) + ","
+	if m.Through != 0 {
+		n += 1 + sovLogproto(uint64(m.Through))
 	}
-	repeatedStringForStreamRates += "}"
-	s := strings.Join([]string{`&StreamRatesResponse{`,
-		`StreamRates:` + repeatedStringForStreamRates + `,`,
-		`}`,
-	}, "")
-	return s
+	l = len(m.Matchers)
+	if l > 0 {
+		n += 1 + l + sovLogproto(uint64(l))
+	}
+	if len(m.TargetLabels) > 0 {
+		for _, s := range m.TargetLabels {
+			l = len(s)
+			n += 1 + l + sovLogproto(uint64(l))
+		}
+	}
+	if m.Limit != 0 {
+		n += 1 + sovLogproto(uint64(m.Limit))
+	}
+	if m.Streams {
+		n += 2
+	}
+	return n
 }
-func (this *StreamRate) String() string {
-	if this == nil {
-		return "nil"
+
+func (m *VolumeResponse) Size() (n int) {
+	if m == nil {
+		return 0
 	}
-	s := strings.Join([]string{`&StreamRate{`,
+	var l int
+	_ = l
+	if len(m.Volumes) > 0 {
+		for _, e := range m.Volumes {
+			l = e.Size()
+			n += 1 + l + sovLogproto(uint64(l))
+		}
+	}
+	if len(m.Streams) > 0 {
+		for _, e := range m.Streams {
+			l = e.Size()
+			n += 1 + l + sovLogproto(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *StreamVolume) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Fingerprint != 0 {
+		n += 1 + sovLogproto(uint64(m.Fingerprint))
+	}
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovLogproto(uint64(l))
+	}
+	if m.Chunks != 0 {
+		n += 1 + sovLogproto(uint64(m.Chunks))
+	}
+	if m.Bytes != 0 {
+		n += 1 + sovLogproto(uint64(m.Bytes))
+	}
+	if m.Entries != 0 {
+		n += 1 + sovLogproto(uint64(m.Entries))
+	}
+	return n
+}
+
+func (m *Volume) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovLogproto(uint64(l))
+	}
+	if m.Streams != 0 {
+		n += 1 + sovLogproto(uint64(m.Streams))
+	}
+	if m.Chunks != 0 {
+		n += 1 + sovLogproto(uint64(m.Chunks))
+	}
+	if m.Bytes != 0 {
+		n += 1 + sovLogproto(uint64(m.Bytes))
+	}
+	if m.Entries != 0 {
+		n += 1 + sovLogproto(uint64(m.Entries))
+	}
+	return n
+}
+
+func sovLogproto(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozLogproto(x uint64) (n int) {
+	return sovLogproto(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (this *StreamRatesRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&StreamRatesRequest{`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *StreamRatesResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForStreamRates := "[]*StreamRate{"
+	for _, f := range this.StreamRates {
+		repeatedStringForStreamRates += strings.Replace(f.String(), "StreamRate", "StreamRate", 1) + ","
+	}
+	repeatedStringForStreamRates += "}"
+	s := strings.Join([]string{`&StreamRatesResponse{`,
+		`StreamRates:` + repeatedStringForStreamRates + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *StreamRate) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&StreamRate{`,
 		`StreamHash:` + fmt.Sprintf("%v", this.StreamHash) + `,`,
 		`StreamHashNoShard:` + fmt.Sprintf("%v", this.StreamHashNoShard) + `,`,
 		`Rate:` + fmt.Sprintf("%v", this.Rate) + `,`,
@@ -8317,6 +9218,7 @@ func (this *EntryAdapter) String() string {
 	s := strings.Join([]string{`&EntryAdapter{`,
 		`Timestamp:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.Timestamp), "Timestamp", "types.Timestamp", 1), `&`, ``, 1) + `,`,
 		`Line:` + fmt.Sprintf("%v", this.Line) + `,`,
+		`StructuredMetadata:` + fmt.Sprintf("%v", this.StructuredMetadata) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -8740,6 +9642,70 @@ func (this *IndexStatsResponse) String() string {
 	}, "")
 	return s
 }
+func (this *VolumeRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&VolumeRequest{`,
+		`From:` + fmt.Sprintf("%v", this.From) + `,`,
+		`Through:` + fmt.Sprintf("%v", this.Through) + `,`,
+		`Matchers:` + fmt.Sprintf("%v", this.Matchers) + `,`,
+		`TargetLabels:` + fmt.Sprintf("%v", this.TargetLabels) + `,`,
+		`Limit:` + fmt.Sprintf("%v", this.Limit) + `,`,
+		`Streams:` + fmt.Sprintf("%v", this.Streams) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *VolumeResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForVolumes := "[]Volume{"
+	for _, f := range this.Volumes {
+		repeatedStringForVolumes += strings.Replace(strings.Replace(f.String(), "Volume", "Volume", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForVolumes += "}"
+	repeatedStringForStreams := "[]StreamVolume{"
+	for _, f := range this.Streams {
+		repeatedStringForStreams += strings.Replace(strings.Replace(f.String(), "StreamVolume", "StreamVolume", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForStreams += "}"
+	s := strings.Join([]string{`&VolumeResponse{`,
+		`Volumes:` + repeatedStringForVolumes + `,`,
+		`Streams:` + repeatedStringForStreams + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *StreamVolume) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&StreamVolume{`,
+		`Fingerprint:` + fmt.Sprintf("%v", this.Fingerprint) + `,`,
+		`Name:` + fmt.Sprintf("%v", this.Name) + `,`,
+		`Chunks:` + fmt.Sprintf("%v", this.Chunks) + `,`,
+		`Bytes:` + fmt.Sprintf("%v", this.Bytes) + `,`,
+		`Entries:` + fmt.Sprintf("%v", this.Entries) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Volume) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Volume{`,
+		`Name:` + fmt.Sprintf("%v", this.Name) + `,`,
+		`Streams:` + fmt.Sprintf("%v", this.Streams) + `,`,
+		`Chunks:` + fmt.Sprintf("%v", this.Chunks) + `,`,
+		`Bytes:` + fmt.Sprintf("%v", this.Bytes) + `,`,
+		`Entries:` + fmt.Sprintf("%v", this.Entries) + `,`,
+		`}`,
+	}, "")
+	return s
+}
 func valueToStringLogproto(v interface{}) string {
 	rv := reflect.ValueOf(v)
 	if rv.IsNil() {
@@ -10499,6 +11465,40 @@ func (m *EntryAdapter) Unmarshal(dAtA []byte) error {
 			}
 			m.Line = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StructuredMetadata", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.StructuredMetadata = append(m.StructuredMetadata, LabelAdapter{})
+			if err := m.StructuredMetadata[len(m.StructuredMetadata)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipLogproto(dAtA[iNdEx:])
@@ -14426,6 +15426,643 @@ func (m *IndexStatsResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *VolumeRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowLogproto
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: VolumeRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: VolumeRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field From", wireType)
+			}
+			m.From = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.From |= github_com_prometheus_common_model.Time(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Through", wireType)
+			}
+			m.Through = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Through |= github_com_prometheus_common_model.Time(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Matchers", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Matchers = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TargetLabels", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TargetLabels = append(m.TargetLabels, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Limit", wireType)
+			}
+			m.Limit = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Limit |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Streams", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Streams = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipLogproto(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *VolumeResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowLogproto
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: VolumeResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: VolumeResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Volumes", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Volumes = append(m.Volumes, Volume{})
+			if err := m.Volumes[len(m.Volumes)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Streams", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Streams = append(m.Streams, StreamVolume{})
+			if err := m.Streams[len(m.Streams)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipLogproto(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *StreamVolume) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowLogproto
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: StreamVolume: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: StreamVolume: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Fingerprint", wireType)
+			}
+			m.Fingerprint = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Fingerprint |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Chunks", wireType)
+			}
+			m.Chunks = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Chunks |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Bytes", wireType)
+			}
+			m.Bytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Bytes |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Entries", wireType)
+			}
+			m.Entries = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Entries |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipLogproto(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Volume) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowLogproto
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Volume: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Volume: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Streams", wireType)
+			}
+			m.Streams = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Streams |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Chunks", wireType)
+			}
+			m.Chunks = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Chunks |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Bytes", wireType)
+			}
+			m.Bytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Bytes |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Entries", wireType)
+			}
+			m.Entries = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Entries |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipLogproto(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipLogproto(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
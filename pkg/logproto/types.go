@@ -3,6 +3,7 @@ package logproto
 import (
 	fmt "fmt"
 	io "io"
+	"strings"
 	"time"
 
 	"github.com/prometheus/common/model"
@@ -21,6 +22,8 @@ type Stream struct {
 type Entry struct {
 	Timestamp time.Time `protobuf:"bytes,1,opt,name=timestamp,proto3,stdtime" json:"ts"`
 	Line      string    `protobuf:"bytes,2,opt,name=line,proto3" json:"line"`
+	// StructuredMetadata holds key/value pairs attached to the entry which are not part of the stream labels.
+	StructuredMetadata []LabelAdapter `protobuf:"bytes,3,rep,name=structuredMetadata,proto3,customtype=LabelAdapter" json:"structuredMetadata,omitempty"`
 }
 
 func (m *Stream) Marshal() (dAtA []byte, err error) {
@@ -92,6 +95,20 @@ func (m *Entry) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.StructuredMetadata) > 0 {
+		for iNdEx := len(m.StructuredMetadata) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size := m.StructuredMetadata[iNdEx].Size()
+				i -= size
+				if _, err := m.StructuredMetadata[iNdEx].MarshalTo(dAtA[i:]); err != nil {
+					return 0, err
+				}
+				i = encodeVarintLogproto(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
 	if len(m.Line) > 0 {
 		i -= len(m.Line)
 		copy(dAtA[i:], m.Line)
@@ -343,6 +360,43 @@ func (m *Entry) Unmarshal(dAtA []byte) error {
 			}
 			m.Line = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StructuredMetadata", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.StructuredMetadata = append(m.StructuredMetadata, LabelAdapter{})
+			lbl := &m.StructuredMetadata[len(m.StructuredMetadata)-1]
+			if err := lbl.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			// LabelAdapter doesn't copy the strings it unmarshals, unlike the line, but entries outlive the buffer.
+			lbl.Name, lbl.Value = strings.Clone(lbl.Name), strings.Clone(lbl.Value)
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipLogproto(dAtA[iNdEx:])
@@ -402,6 +456,10 @@ func (m *Entry) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovLogproto(uint64(l))
 	}
+	for _, e := range m.StructuredMetadata {
+		l = e.Size()
+		n += 1 + l + sovLogproto(uint64(l))
+	}
 	return n
 }
 
@@ -463,6 +521,14 @@ func (m *Entry) Equal(that interface{}) bool {
 	if m.Line != that1.Line {
 		return false
 	}
+	if len(m.StructuredMetadata) != len(that1.StructuredMetadata) {
+		return false
+	}
+	for i := range m.StructuredMetadata {
+		if !m.StructuredMetadata[i].Equal(that1.StructuredMetadata[i]) {
+			return false
+		}
+	}
 	return true
 }
 
@@ -307,6 +307,27 @@ func (ev *DownstreamEvaluator) StepEvaluator(
 
 		return ConcatEvaluator(xs)
 
+	case *QuantileSketchEvalExpr:
+		sketches, err := ev.StepEvaluator(ctx, nextEv, e.SampleExpr, params)
+		if err != nil {
+			return nil, err
+		}
+		return quantileSketchMergeEvaluator(sketches, e.quantile)
+
+	case *CountMinSketchEvalExpr:
+		sketches, err := ev.StepEvaluator(ctx, nextEv, e.SampleExpr, params)
+		if err != nil {
+			return nil, err
+		}
+		return sketchMergeEvaluator(sketches, mergeCountMinSketches)
+
+	case *HyperLogLogEvalExpr:
+		sketches, err := ev.StepEvaluator(ctx, nextEv, e.SampleExpr, params)
+		if err != nil {
+			return nil, err
+		}
+		return sketchMergeEvaluator(sketches, mergeHyperLogLogs)
+
 	default:
 		return ev.defaultEvaluator.StepEvaluator(ctx, nextEv, e, params)
 	}
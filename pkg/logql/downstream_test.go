@@ -12,6 +12,7 @@ import (
 	"github.com/weaveworks/common/user"
 
 	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql/sketch"
 )
 
 var nilShardMetrics = NewShardMapperMetrics(nil)
@@ -49,6 +50,11 @@ func TestMappingEquivalence(t *testing.T) {
 		{`sum(max(rate({a=~".+"}[1s])))`, false},
 		{`max(count(rate({a=~".+"}[1s])))`, false},
 		{`max(sum by (cluster) (rate({a=~".+"}[1s]))) / count(rate({a=~".+"}[1s]))`, false},
+		{`sort(sum by (a) (rate({a=~".+"}[1s])))`, false},
+		{`sort_desc(rate({a=~".+"}[1s]))`, false},
+		{`count_values("value", rate({a=~".+"}[1s]))`, false},
+		{`count_values("value", sum by (a) (count_over_time({a=~".+"}[1s]))) by (a)`, false},
+		{`quantile_over_time(0.5, {a=~".+"} | logfmt | unwrap line [2s])`, false},
 		// topk prefers already-seen values in tiebreakers. Since the test data generates
 		// the same log lines for each series & the resulting promql.Vectors aren't deterministically
 		// sorted by labels, we don't expect this to pass.
@@ -78,7 +84,7 @@ func TestMappingEquivalence(t *testing.T) {
 			qry := regular.Query(params)
 			ctx := user.InjectOrgID(context.Background(), "fake")
 
-			mapper := NewShardMapper(ConstantShards(shards), nilShardMetrics)
+			mapper := NewShardMapper(ConstantShards(shards), nilShardMetrics, false)
 			_, mapped, err := mapper.Parse(tc.query)
 			require.Nil(t, err)
 
@@ -99,6 +105,86 @@ func TestMappingEquivalence(t *testing.T) {
 	}
 }
 
+func TestProbabilisticMappingEquivalence(t *testing.T) {
+	var (
+		shards   = 3
+		nStreams = 60
+		rounds   = 20
+		streams  = randomStreams(nStreams, rounds+1, shards, []string{"a", "b", "c", "d"})
+		start    = time.Unix(0, 0)
+		end      = time.Unix(0, int64(time.Second*time.Duration(rounds)))
+		step     = time.Second
+		interval = time.Duration(0)
+		limit    = 100
+	)
+
+	for _, tc := range []struct {
+		query string
+		// relative error tolerated for the sharded results.
+		tolerance float64
+	}{
+		{`quantile_over_time(0.9, {a=~".+"} | logfmt | unwrap line [2s]) by (a)`, sketch.DefaultRelativeAccuracy},
+		{`quantile_over_time(0.1, {a=~".+"} | logfmt | unwrap line [2s]) by (a, b)`, sketch.DefaultRelativeAccuracy},
+		{`quantile_over_time(1, {a=~".+"} | logfmt | unwrap line [2s]) without (index)`, sketch.DefaultRelativeAccuracy},
+		{`max(quantile_over_time(0.9, {a=~".+"} | logfmt | unwrap line [2s]) by (a))`, sketch.DefaultRelativeAccuracy},
+		// k is larger than the number of series since ties are not broken the same way once sharded.
+		{`topk(10, sum by (a, b) (count_over_time({a=~".+"} | logfmt [2s])))`, 0},
+		{`topk(5, sum by (a, b) (rate({a=~".+"} | logfmt [2s]))) by (a)`, 0},
+		// HyperLogLog estimations have a standard error of 0.8%.
+		{`count(sum by (a, b) (bytes_over_time({a=~".+"} | logfmt [2s])))`, 0.05},
+		{`count by (a) (count_over_time({a=~".+"} | logfmt [2s]))`, 0.05},
+	} {
+		q := NewMockQuerier(
+			shards,
+			streams,
+		)
+
+		opts := EngineOpts{}
+		regular := NewEngine(opts, q, NoLimits, log.NewNopLogger())
+		sharded := NewDownstreamEngine(opts, MockDownstreamer{regular}, NoLimits, log.NewNopLogger())
+
+		t.Run(tc.query, func(t *testing.T) {
+			params := NewLiteralParams(
+				tc.query,
+				start,
+				end,
+				step,
+				interval,
+				logproto.FORWARD,
+				uint32(limit),
+				nil,
+			)
+			qry := regular.Query(params)
+			ctx := user.InjectOrgID(context.Background(), "fake")
+
+			mapper := NewShardMapper(ConstantShards(shards), nilShardMetrics, true)
+			noop, mapped, err := mapper.Parse(tc.query)
+			require.Nil(t, err)
+			require.False(t, noop)
+
+			shardedQry := sharded.Query(ctx, params, mapped)
+
+			res, err := qry.Exec(ctx)
+			require.Nil(t, err)
+
+			shardedRes, err := shardedQry.Exec(ctx)
+			require.Nil(t, err)
+
+			expected, actual := res.Data.(promql.Matrix), shardedRes.Data.(promql.Matrix)
+			require.Equal(t, len(expected), len(actual))
+			for i := range expected {
+				require.Equal(t, expected[i].Metric, actual[i].Metric)
+				require.Equal(t, len(expected[i].Points), len(actual[i].Points))
+				for j := range expected[i].Points {
+					require.Equal(t, expected[i].Points[j].T, actual[i].Points[j].T)
+					v := expected[i].Points[j].V
+					require.InDelta(t, v, actual[i].Points[j].V, math.Abs(v)*tc.tolerance)
+				}
+			}
+		})
+	}
+}
+
 func TestRangeMappingEquivalence(t *testing.T) {
 	var (
 		shards   = 3
@@ -140,6 +226,12 @@ func TestRangeMappingEquivalence(t *testing.T) {
 		{`sum(rate({a=~".+"} | unwrap b [2s]))`, time.Second},
 		{`sum(bytes_rate({a=~".+"}[2s]))`, time.Second},
 
+		// sort and count_values
+		{`sort(sum by (a) (count_over_time({a=~".+"}[2s])))`, time.Second},
+		{`sort_desc(count_over_time({a=~".+"}[2s]))`, time.Second},
+		{`count_values("value", count_over_time({a=~".+"}[2s]))`, time.Second},
+		{`count_values("value", sum by (a) (count_over_time({a=~".+"}[2s]))) by (a)`, time.Second},
+
 		// sum by
 		{`sum by (a) (bytes_over_time({a=~".+"}[2s]))`, time.Second},
 		{`sum by (a) (count_over_time({a=~".+"}[2s]))`, time.Second},
@@ -322,6 +322,11 @@ func (q *query) evalSample(ctx context.Context, expr syntax.SampleExpr) (promql_
 	}
 	maxSeries := validation.SmallestPositiveIntPerTenant(tenantIDs, q.limits.MaxQuerySeries)
 	seriesIndex := map[uint64]*promql.Series{}
+	seriesCount := func(_ promql.Vector, count int) int { return count }
+	if label, ok := sketchLabel(expr); ok {
+		// the samples of a sketch are accounted as a single series.
+		seriesCount = newSketchSeriesCounter(label)
+	}
 
 	next, ts, vec := stepEvaluator.Next()
 	if stepEvaluator.Error() != nil {
@@ -329,12 +334,15 @@ func (q *query) evalSample(ctx context.Context, expr syntax.SampleExpr) (promql_
 	}
 
 	// fail fast for the first step or instant query
-	if len(vec) > maxSeries {
+	if seriesCount(vec, len(vec)) > maxSeries {
 		return nil, logqlmodel.NewSeriesLimitError(maxSeries)
 	}
 
 	if GetRangeType(q.params) == InstantType {
-		sort.Slice(vec, func(i, j int) bool { return labels.Compare(vec[i].Metric, vec[j].Metric) < 0 })
+		// sort and sort_desc results are already ordered by value.
+		if !isSortByValue(expr) {
+			sort.Slice(vec, func(i, j int) bool { return labels.Compare(vec[i].Metric, vec[j].Metric) < 0 })
+		}
 		return vec, nil
 	}
 
@@ -365,7 +373,7 @@ func (q *query) evalSample(ctx context.Context, expr syntax.SampleExpr) (promql_
 			})
 		}
 		// as we slowly build the full query for each steps, make sure we don't go over the limit of unique series.
-		if len(seriesIndex) > maxSeries {
+		if seriesCount(vec, len(seriesIndex)) > maxSeries {
 			return nil, logqlmodel.NewSeriesLimitError(maxSeries)
 		}
 		next, ts, vec = stepEvaluator.Next()
@@ -384,6 +392,14 @@ func (q *query) evalSample(ctx context.Context, expr syntax.SampleExpr) (promql_
 	return result, stepEvaluator.Error()
 }
 
+// isSortByValue tells if the expression result must be ordered by sample value.
+func isSortByValue(expr syntax.SampleExpr) bool {
+	if e, ok := expr.(*syntax.VectorAggregationExpr); ok {
+		return e.Operation == syntax.OpTypeSort || e.Operation == syntax.OpTypeSortDesc
+	}
+	return false
+}
+
 func (q *query) evalLiteral(_ context.Context, expr *syntax.LiteralExpr) (promql_parser.Value, error) {
 	s := promql.Scalar{
 		T: q.params.Start().UnixNano() / int64(time.Millisecond),
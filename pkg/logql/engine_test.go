@@ -532,6 +532,74 @@ func TestEngine_LogsInstantQuery(t *testing.T) {
 				promql.Sample{Point: promql.Point{T: 60 * 1000, V: 0.2}, Metric: labels.Labels{labels.Label{Name: "app", Value: "fuzz"}}},
 			},
 		},
+		{
+			`sort(rate({app=~"foo|bar"}[1m]))`, time.Unix(60, 0), logproto.FORWARD, 100,
+			[][]logproto.Series{
+				{
+					newSeries(testSize, factor(10, identity), `{app="foo"}`), newSeries(testSize, offset(46, identity), `{app="bar"}`),
+					newSeries(testSize, factor(5, identity), `{app="fuzz"}`), newSeries(testSize, identity, `{app="buzz"}`),
+				},
+			},
+			[]SelectSampleParams{
+				{&logproto.SampleQueryRequest{Start: time.Unix(0, 0), End: time.Unix(60, 0), Selector: `rate({app=~"foo|bar"}[1m])`}},
+			},
+			promql.Vector{
+				promql.Sample{Point: promql.Point{T: 60 * 1000, V: 0.1}, Metric: labels.Labels{labels.Label{Name: "app", Value: "foo"}}},
+				promql.Sample{Point: promql.Point{T: 60 * 1000, V: 0.2}, Metric: labels.Labels{labels.Label{Name: "app", Value: "fuzz"}}},
+				promql.Sample{Point: promql.Point{T: 60 * 1000, V: 0.25}, Metric: labels.Labels{labels.Label{Name: "app", Value: "bar"}}},
+				promql.Sample{Point: promql.Point{T: 60 * 1000, V: 1}, Metric: labels.Labels{labels.Label{Name: "app", Value: "buzz"}}},
+			},
+		},
+		{
+			`sort_desc(rate({app=~"foo|bar"}[1m]))`, time.Unix(60, 0), logproto.FORWARD, 100,
+			[][]logproto.Series{
+				{
+					newSeries(testSize, factor(10, identity), `{app="foo"}`), newSeries(testSize, offset(46, identity), `{app="bar"}`),
+					newSeries(testSize, factor(5, identity), `{app="fuzz"}`), newSeries(testSize, identity, `{app="buzz"}`),
+				},
+			},
+			[]SelectSampleParams{
+				{&logproto.SampleQueryRequest{Start: time.Unix(0, 0), End: time.Unix(60, 0), Selector: `rate({app=~"foo|bar"}[1m])`}},
+			},
+			promql.Vector{
+				promql.Sample{Point: promql.Point{T: 60 * 1000, V: 1}, Metric: labels.Labels{labels.Label{Name: "app", Value: "buzz"}}},
+				promql.Sample{Point: promql.Point{T: 60 * 1000, V: 0.25}, Metric: labels.Labels{labels.Label{Name: "app", Value: "bar"}}},
+				promql.Sample{Point: promql.Point{T: 60 * 1000, V: 0.2}, Metric: labels.Labels{labels.Label{Name: "app", Value: "fuzz"}}},
+				promql.Sample{Point: promql.Point{T: 60 * 1000, V: 0.1}, Metric: labels.Labels{labels.Label{Name: "app", Value: "foo"}}},
+			},
+		},
+		{
+			`count_values("rate", rate({app=~"foo|bar"}[1m]))`, time.Unix(60, 0), logproto.FORWARD, 100,
+			[][]logproto.Series{
+				{
+					newSeries(testSize, factor(10, identity), `{app="foo"}`), newSeries(testSize, factor(10, identity), `{app="bar"}`),
+					newSeries(testSize, factor(5, identity), `{app="fuzz"}`),
+				},
+			},
+			[]SelectSampleParams{
+				{&logproto.SampleQueryRequest{Start: time.Unix(0, 0), End: time.Unix(60, 0), Selector: `rate({app=~"foo|bar"}[1m])`}},
+			},
+			promql.Vector{
+				promql.Sample{Point: promql.Point{T: 60 * 1000, V: 2}, Metric: labels.Labels{labels.Label{Name: "rate", Value: "0.1"}}},
+				promql.Sample{Point: promql.Point{T: 60 * 1000, V: 1}, Metric: labels.Labels{labels.Label{Name: "rate", Value: "0.2"}}},
+			},
+		},
+		{
+			`count_values("rate", rate({app=~"foo|bar"}[1m])) by (app)`, time.Unix(60, 0), logproto.FORWARD, 100,
+			[][]logproto.Series{
+				{
+					newSeries(testSize, factor(10, identity), `{app="foo", pod="a"}`), newSeries(testSize, factor(10, identity), `{app="foo", pod="b"}`),
+					newSeries(testSize, factor(10, identity), `{app="bar", pod="c"}`),
+				},
+			},
+			[]SelectSampleParams{
+				{&logproto.SampleQueryRequest{Start: time.Unix(0, 0), End: time.Unix(60, 0), Selector: `rate({app=~"foo|bar"}[1m])`}},
+			},
+			promql.Vector{
+				promql.Sample{Point: promql.Point{T: 60 * 1000, V: 1}, Metric: labels.Labels{labels.Label{Name: "app", Value: "bar"}, labels.Label{Name: "rate", Value: "0.1"}}},
+				promql.Sample{Point: promql.Point{T: 60 * 1000, V: 2}, Metric: labels.Labels{labels.Label{Name: "app", Value: "foo"}, labels.Label{Name: "rate", Value: "0.1"}}},
+			},
+		},
 		{
 			`bottomk(2,rate(({app=~"foo|bar"} |~".+bar")[1m]))`, time.Unix(60, 0), logproto.FORWARD, 100,
 			[][]logproto.Series{
@@ -1035,6 +1103,36 @@ func TestEngine_RangeQuery(t *testing.T) {
 				},
 			},
 		},
+		{
+			`sum_over_time(count_over_time({app="foo"}[30s])[2m:30s])`, time.Unix(120, 0), time.Unix(240, 0), time.Minute, 0, logproto.FORWARD, 10,
+			[][]logproto.Series{
+				{newSeries(testSize, factor(10, identity), `{app="foo"}`)}, // 3 per 30s step, 4 steps per 2m range
+			},
+			[]SelectSampleParams{
+				{&logproto.SampleQueryRequest{Start: time.Unix(-30, 0), End: time.Unix(240, 0), Selector: `count_over_time({app="foo"}[30s])`}},
+			},
+			promql.Matrix{
+				promql.Series{
+					Metric: labels.Labels{{Name: "app", Value: "foo"}},
+					Points: []promql.Point{{T: 120 * 1000, V: 12}, {T: 180 * 1000, V: 12}, {T: 240 * 1000, V: 12}},
+				},
+			},
+		},
+		{
+			`max_over_time(count_over_time({app="foo"}[30s])[1m:30s] offset 1m) by (app)`, time.Unix(120, 0), time.Unix(120, 0), 0, 0, logproto.FORWARD, 10,
+			[][]logproto.Series{
+				{newSeries(testSize, factor(10, identity), `{app="foo", bar="1"}`), newSeries(testSize, factor(20, identity), `{app="foo", bar="2"}`)},
+			},
+			[]SelectSampleParams{
+				{&logproto.SampleQueryRequest{Start: time.Unix(-30, 0), End: time.Unix(60, 0), Selector: `count_over_time({app="foo"}[30s])`}},
+			},
+			promql.Vector{
+				promql.Sample{
+					Metric: labels.Labels{{Name: "app", Value: "foo"}},
+					Point:  promql.Point{T: 120 * 1000, V: 3},
+				},
+			},
+		},
 		{
 			`count_over_time(({app="foo"} |~".+bar")[5m])`, time.Unix(5*60, 0), time.Unix(5*120, 0), 30 * time.Second, 0, logproto.BACKWARD, 10,
 			[][]logproto.Series{
@@ -2334,6 +2432,34 @@ func TestStepEvaluator_Error(t *testing.T) {
 	}
 }
 
+func TestEngine_SubqueryMaxPoints(t *testing.T) {
+	eng := NewEngine(EngineOpts{}, getLocalQuerier(100000), NoLimits, log.NewNopLogger())
+
+	for _, test := range []struct {
+		qs             string
+		expectLimitErr bool
+	}{
+		{`max_over_time(count_over_time({app="foo"}[1m])[1h:1s])`, false},
+		{`max_over_time(count_over_time({app="foo"}[1m])[1d:1s])`, true},
+	} {
+		t.Run(test.qs, func(t *testing.T) {
+			q := eng.Query(LiteralParams{
+				qs:        test.qs,
+				start:     time.Unix(100000, 0),
+				end:       time.Unix(100000, 0),
+				direction: logproto.FORWARD,
+				limit:     1000,
+			})
+			_, err := q.Exec(user.InjectOrgID(context.Background(), "fake"))
+			if test.expectLimitErr {
+				require.True(t, errors.Is(err, logqlmodel.ErrLimit))
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestEngine_MaxSeries(t *testing.T) {
 	eng := NewEngine(EngineOpts{}, getLocalQuerier(100000), &fakeLimits{maxSeries: 1}, log.NewNopLogger())
 
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math"
 	"sort"
+	"strconv"
 	"time"
 
 	"github.com/pkg/errors"
@@ -203,6 +204,8 @@ func (ev *DefaultEvaluator) StepEvaluator(
 			return nil, err
 		}
 		return rangeAggEvaluator(iter.NewPeekingSampleIterator(it), e, q, e.Left.Offset)
+	case *syntax.SubqueryExpr:
+		return subqueryEvaluator(ctx, nextEv, e, q)
 	case *syntax.BinOpExpr:
 		return binOpStepEvaluator(ctx, nextEv, e, q)
 	case *syntax.LabelReplaceExpr:
@@ -240,6 +243,17 @@ func vectorAggEvaluator(
 		if !next {
 			return false, 0, promql.Vector{}
 		}
+		switch expr.Operation {
+		case syntax.OpTypeSort, syntax.OpTypeSortDesc:
+			sortByValue(vec, expr.Operation == syntax.OpTypeSortDesc)
+			return next, ts, vec
+		case syntax.OpTypeCountValues:
+			return next, ts, countValues(vec, ts, expr, lb)
+		case syntax.OpTypeCountMinSketch:
+			return next, ts, countMinSketchVector(vec, ts, expr, lb)
+		case syntax.OpTypeHyperLogLog:
+			return next, ts, hyperLogLogVector(vec, ts, expr, lb)
+		}
 		result := map[uint64]*groupedAggregation{}
 		if expr.Operation == syntax.OpTypeTopK || expr.Operation == syntax.OpTypeBottomK {
 			if expr.Params < 1 {
@@ -414,6 +428,81 @@ func vectorAggEvaluator(
 	}, nextEvaluator.Close, nextEvaluator.Error)
 }
 
+// sortByValue sorts the vector by sample value, NaN values being always last.
+// Samples with the same value are sorted by labels.
+func sortByValue(vec promql.Vector, desc bool) {
+	sort.Slice(vec, func(i, j int) bool {
+		vi, vj := vec[i].V, vec[j].V
+		switch {
+		case math.IsNaN(vi) && math.IsNaN(vj), vi == vj:
+			return labels.Compare(vec[i].Metric, vec[j].Metric) < 0
+		case math.IsNaN(vi):
+			return false
+		case math.IsNaN(vj):
+			return true
+		case desc:
+			return vi > vj
+		default:
+			return vi < vj
+		}
+	})
+}
+
+// countValues counts the samples having the same value within each group.
+// The value is added to the group labels using the expression label name.
+func countValues(vec promql.Vector, ts int64, expr *syntax.VectorAggregationExpr, lb *labels.Builder) promql.Vector {
+	type countedValue struct {
+		labels labels.Labels
+		count  float64
+	}
+	var (
+		result = map[uint64]*countedValue{}
+		keys   = make([]uint64, 0, len(vec))
+	)
+	for _, s := range vec {
+		resetToGroupLabels(lb, s.Metric, expr.Grouping)
+		lb.Set(expr.Label, strconv.FormatFloat(s.V, 'f', -1, 64))
+		m := lb.Labels(nil)
+		key := m.Hash()
+		if counted, ok := result[key]; ok {
+			counted.count++
+			continue
+		}
+		result[key] = &countedValue{labels: m, count: 1}
+		keys = append(keys, key)
+	}
+	vec = vec[:0]
+	for _, key := range keys {
+		vec = append(vec, promql.Sample{
+			Metric: result[key].labels,
+			Point: promql.Point{
+				T: ts,
+				V: result[key].count,
+			},
+		})
+	}
+	return vec
+}
+
+// resetToGroupLabels resets the builder to the labels of the group of the metric.
+func resetToGroupLabels(lb *labels.Builder, metric labels.Labels, grouping *syntax.Grouping) {
+	if grouping.Without {
+		lb.Reset(metric)
+		lb.Del(grouping.Groups...)
+		lb.Del(labels.MetricName)
+		return
+	}
+	lb.Reset(nil)
+	for _, l := range metric {
+		for _, n := range grouping.Groups {
+			if l.Name == n {
+				lb.Set(l.Name, l.Value)
+				break
+			}
+		}
+	}
+}
+
 func rangeAggEvaluator(
 	it iter.PeekingSampleIterator,
 	expr *syntax.RangeAggregationExpr,
@@ -440,6 +529,118 @@ func rangeAggEvaluator(
 	}, nil
 }
 
+const (
+	// defaultSubqueryStep is the resolution used for subqueries without an explicit step within instant queries.
+	defaultSubqueryStep = time.Minute
+	// maxSubqueryPoints bounds the number of steps the inner expression of a subquery is evaluated at,
+	// the same way the resolution of the range queries is bounded.
+	maxSubqueryPoints = 11000
+)
+
+// subqueryEvaluator evaluates the inner expression of a subquery as a range query at the subquery resolution,
+// then aggregates the resulting samples over the subquery range for each step of the outer query.
+func subqueryEvaluator(
+	ctx context.Context,
+	ev SampleEvaluator,
+	expr *syntax.SubqueryExpr,
+	q Params,
+) (StepEvaluator, error) {
+	step := expr.Step
+	if step == 0 {
+		step = q.Step()
+	}
+	if step == 0 {
+		step = defaultSubqueryStep
+	}
+	// The start of the subquery is aligned to its step so that its samples don't depend
+	// on the start of the outer query, which may be split or sharded by the frontend.
+	start := q.Start().Add(-expr.Offset).Add(-expr.Range)
+	if rem := start.UnixNano() % step.Nanoseconds(); rem != 0 {
+		start = start.Add(step - time.Duration(rem))
+	}
+	end := q.End().Add(-expr.Offset)
+	if points := int64(end.Sub(start)/step) + 1; points > maxSubqueryPoints {
+		return nil, logqlmodel.NewSubqueryPointsLimitError(points, maxSubqueryPoints)
+	}
+
+	params := NewLiteralParams(expr.Left.String(), start, end, step, q.Interval(), q.Direction(), q.Limit(), q.Shards())
+	nextEvaluator, err := ev.StepEvaluator(ctx, ev, expr.Left, params)
+	if err != nil {
+		return nil, err
+	}
+	series, err := subquerySeries(nextEvaluator, expr.Grouping)
+	if err != nil {
+		return nil, err
+	}
+
+	// The samples of the subquery are aggregated by value, the same way unwrapped samples are.
+	rangeExpr := &syntax.RangeAggregationExpr{
+		Left:      &syntax.LogRange{Interval: expr.Range},
+		Operation: expr.Operation,
+		Params:    expr.Params,
+	}
+	it := iter.NewPeekingSampleIterator(iter.NewMultiSeriesIterator(series))
+	rangeIter, err := newRangeVectorIterator(
+		it, rangeExpr,
+		expr.Range.Nanoseconds(),
+		q.Step().Nanoseconds(),
+		q.Start().UnixNano(), q.End().UnixNano(), expr.Offset.Nanoseconds(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if expr.Operation == syntax.OpRangeTypeAbsent {
+		return &absentRangeVectorEvaluator{
+			iter: rangeIter,
+			lbs:  absentLabels(expr),
+		}, nil
+	}
+	return &rangeVectorEvaluator{
+		iter: rangeIter,
+	}, nil
+}
+
+// subquerySeries consumes a StepEvaluator and returns its samples as series, reducing their labels to the grouping if any.
+func subquerySeries(ev StepEvaluator, grouping *syntax.Grouping) ([]logproto.Series, error) {
+	defer util.LogError("closing subquery evaluator", ev.Close)
+
+	var (
+		lb     = labels.NewBuilder(nil)
+		index  = map[uint64]int{}
+		series []logproto.Series
+	)
+	next, ts, vec := ev.Next()
+	for ; next; next, ts, vec = ev.Next() {
+		for _, s := range vec {
+			metric := s.Metric
+			if grouping != nil {
+				lb.Reset(metric)
+				if grouping.Without {
+					lb.Del(grouping.Groups...)
+				} else {
+					lb.Keep(grouping.Groups...)
+				}
+				metric = lb.Labels(nil)
+			}
+			hash := metric.Hash()
+			i, ok := index[hash]
+			if !ok {
+				i = len(series)
+				index[hash] = i
+				series = append(series, logproto.Series{
+					Labels:     metric.String(),
+					StreamHash: hash,
+				})
+			}
+			series[i].Samples = append(series[i].Samples, logproto.Sample{
+				Timestamp: ts * int64(time.Millisecond),
+				Value:     s.V,
+			})
+		}
+	}
+	return series, ev.Error()
+}
+
 type rangeVectorEvaluator struct {
 	iter RangeVectorIterator
 
@@ -0,0 +1,85 @@
+package log
+
+import (
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/grafana/loki/pkg/logqlmodel"
+)
+
+// DropLabel is a label to drop from the result set.
+// When Matcher is set, the label is only dropped if its value matches, otherwise the label Name is always dropped.
+type DropLabel struct {
+	Matcher *labels.Matcher
+	Name    string
+}
+
+// NewDropLabel creates a new DropLabel.
+func NewDropLabel(matcher *labels.Matcher, name string) DropLabel {
+	return DropLabel{
+		Matcher: matcher,
+		Name:    name,
+	}
+}
+
+// DropLabels is a stage dropping labels from the result set.
+type DropLabels struct {
+	dropLabels []DropLabel
+}
+
+// NewDropLabels creates a DropLabels stage from the given labels.
+func NewDropLabels(dl []DropLabel) *DropLabels {
+	return &DropLabels{dropLabels: dl}
+}
+
+func (dl *DropLabels) Process(_ int64, line []byte, lbls *LabelsBuilder) ([]byte, bool) {
+	for _, dropLabel := range dl.dropLabels {
+		if dropLabel.Matcher != nil {
+			dropLabelMatches(dropLabel.Matcher, lbls)
+			continue
+		}
+		dropLabelName(dropLabel.Name, lbls)
+	}
+	return line, true
+}
+
+func (dl *DropLabels) RequiredLabelNames() []string {
+	var names []string
+	for _, dropLabel := range dl.dropLabels {
+		if dropLabel.Matcher != nil {
+			names = append(names, dropLabel.Matcher.Name)
+		}
+	}
+	return uniqueString(names)
+}
+
+func dropLabelName(name string, lbls *LabelsBuilder) {
+	switch name {
+	case logqlmodel.ErrorLabel:
+		lbls.SetErr("")
+	case logqlmodel.ErrorDetailsLabel:
+		lbls.SetErrorDetails("")
+	default:
+		if _, ok := lbls.Get(name); ok {
+			lbls.Del(name)
+		}
+	}
+}
+
+func dropLabelMatches(matcher *labels.Matcher, lbls *LabelsBuilder) {
+	var value string
+	switch matcher.Name {
+	case logqlmodel.ErrorLabel:
+		value = lbls.GetErr()
+	case logqlmodel.ErrorDetailsLabel:
+		value = lbls.GetErrorDetails()
+	default:
+		var ok bool
+		if value, ok = lbls.Get(matcher.Name); !ok {
+			return
+		}
+	}
+	if !matcher.Matches(value) {
+		return
+	}
+	dropLabelName(matcher.Name, lbls)
+}
@@ -0,0 +1,103 @@
+package log
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/logqlmodel"
+)
+
+func Test_DropLabels(t *testing.T) {
+	tests := []struct {
+		name       string
+		dropLabels []DropLabel
+		err        string
+		errDetails string
+		lbs        labels.Labels
+		want       labels.Labels
+	}{
+		{
+			"drop by name",
+			[]DropLabel{
+				NewDropLabel(nil, "app"),
+				NewDropLabel(nil, "namespace"),
+			},
+			"",
+			"",
+			labels.Labels{
+				{Name: "app", Value: "foo"},
+				{Name: "namespace", Value: "prod"},
+				{Name: "pod", Value: "pod1"},
+			},
+			labels.Labels{
+				{Name: "pod", Value: "pod1"},
+			},
+		},
+		{
+			"drop by matcher",
+			[]DropLabel{
+				NewDropLabel(labels.MustNewMatcher(labels.MatchEqual, "namespace", "prod"), ""),
+				NewDropLabel(labels.MustNewMatcher(labels.MatchRegexp, "pod", "pod2.*"), ""),
+				NewDropLabel(labels.MustNewMatcher(labels.MatchNotEqual, "missing", "foo"), ""),
+			},
+			"",
+			"",
+			labels.Labels{
+				{Name: "app", Value: "foo"},
+				{Name: "namespace", Value: "prod"},
+				{Name: "pod", Value: "pod1"},
+			},
+			labels.Labels{
+				{Name: "app", Value: "foo"},
+				{Name: "pod", Value: "pod1"},
+			},
+		},
+		{
+			"drop error labels",
+			[]DropLabel{
+				NewDropLabel(labels.MustNewMatcher(labels.MatchEqual, logqlmodel.ErrorLabel, errJSON), ""),
+				NewDropLabel(nil, logqlmodel.ErrorDetailsLabel),
+			},
+			errJSON,
+			"json error",
+			labels.Labels{
+				{Name: "app", Value: "foo"},
+			},
+			labels.Labels{
+				{Name: "app", Value: "foo"},
+			},
+		},
+		{
+			"keep error labels not matching",
+			[]DropLabel{
+				NewDropLabel(labels.MustNewMatcher(labels.MatchEqual, logqlmodel.ErrorLabel, errLogfmt), ""),
+			},
+			errJSON,
+			"",
+			labels.Labels{
+				{Name: "app", Value: "foo"},
+			},
+			labels.Labels{
+				{Name: logqlmodel.ErrorLabel, Value: errJSON},
+				{Name: "app", Value: "foo"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dropLabels := NewDropLabels(tt.dropLabels)
+			lbls := NewBaseLabelsBuilder().ForLabels(tt.lbs, tt.lbs.Hash())
+			lbls.Reset()
+			lbls.SetErr(tt.err)
+			lbls.SetErrorDetails(tt.errDetails)
+			line, ok := dropLabels.Process(0, []byte("line"), lbls)
+			require.True(t, ok)
+			require.Equal(t, []byte("line"), line)
+			sort.Sort(tt.want)
+			require.Equal(t, tt.want, lbls.LabelsResult().Labels())
+		})
+	}
+}
@@ -2,6 +2,7 @@ package log
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"unicode"
 	"unicode/utf8"
@@ -9,9 +10,44 @@ import (
 	"github.com/grafana/regexp"
 	"github.com/grafana/regexp/syntax"
 
-	"github.com/prometheus/prometheus/model/labels"
+	"github.com/grafana/loki/pkg/logql/log/pattern"
 )
 
+// LineMatchType is an enum for line filtering types.
+type LineMatchType int
+
+// Possible LineMatchType.
+const (
+	LineMatchEqual LineMatchType = iota
+	LineMatchNotEqual
+	LineMatchRegexp
+	LineMatchNotRegexp
+	LineMatchPattern
+	LineMatchNotPattern
+)
+
+func (t LineMatchType) String() string {
+	switch t {
+	case LineMatchEqual:
+		return "|="
+	case LineMatchNotEqual:
+		return "!="
+	case LineMatchRegexp:
+		return "|~"
+	case LineMatchNotRegexp:
+		return "!~"
+	case LineMatchPattern:
+		return "|>"
+	case LineMatchNotPattern:
+		return "!>"
+	default:
+		return ""
+	}
+}
+
+// ErrLineFilterInvalidMatchType is returned when creating a line filter with an unknown match type.
+var ErrLineFilterInvalidMatchType = errors.New("line filter: invalid match type")
+
 // Filterer is a interface to filter log lines.
 type Filterer interface {
 	Filter(line []byte) bool
@@ -114,7 +150,7 @@ func NewAndFilters(filters []Filterer) Filterer {
 
 				// Join all contain filters.
 				containsFilterAcc.Add(*c)
-			case regexpFilter:
+			case regexpFilter, patternFilter:
 				regexpFilters = append(regexpFilters, c)
 
 			default:
@@ -137,7 +173,7 @@ func NewAndFilters(filters []Filterer) Filterer {
 		filters = append(filters, containsFilterAcc)
 	}
 
-	// Push regex filters to end
+	// Push regex and pattern filters to end
 	if len(regexpFilters) > 0 {
 		filters = append(filters, regexpFilters...)
 	}
@@ -241,6 +277,42 @@ func (r regexpFilter) ToStage() Stage {
 	}
 }
 
+type patternFilter struct {
+	matcher pattern.Matcher
+}
+
+// newPatternFilter creates a new line filter for a given pattern.
+// If match is false the filter is the negation of the pattern.
+func newPatternFilter(p string, match bool) (Filterer, error) {
+	if p == "" {
+		if match {
+			return TrueFilter, nil
+		}
+		return newNotFilter(TrueFilter), nil
+	}
+	m, err := pattern.ParseLineFilter(p)
+	if err != nil {
+		return nil, err
+	}
+	f := patternFilter{matcher: m}
+	if match {
+		return f, nil
+	}
+	return newNotFilter(f), nil
+}
+
+func (p patternFilter) Filter(line []byte) bool {
+	return p.matcher.Test(line)
+}
+
+func (p patternFilter) ToStage() Stage {
+	return StageFunc{
+		process: func(_ int64, line []byte, _ *LabelsBuilder) ([]byte, bool) {
+			return line, p.Filter(line)
+		},
+	}
+}
+
 type containsFilter struct {
 	match           []byte
 	caseInsensitive bool
@@ -353,18 +425,22 @@ func (f containsAllFilter) ToStage() Stage {
 }
 
 // NewFilter creates a new line filter from a match string and type.
-func NewFilter(match string, mt labels.MatchType) (Filterer, error) {
+func NewFilter(match string, mt LineMatchType) (Filterer, error) {
 	switch mt {
-	case labels.MatchRegexp:
+	case LineMatchRegexp:
 		return parseRegexpFilter(match, true)
-	case labels.MatchNotRegexp:
+	case LineMatchNotRegexp:
 		return parseRegexpFilter(match, false)
-	case labels.MatchEqual:
+	case LineMatchEqual:
 		return newContainsFilter([]byte(match), false), nil
-	case labels.MatchNotEqual:
+	case LineMatchNotEqual:
 		return newNotFilter(newContainsFilter([]byte(match), false)), nil
+	case LineMatchPattern:
+		return newPatternFilter(match, true)
+	case LineMatchNotPattern:
+		return newPatternFilter(match, false)
 	default:
-		return nil, fmt.Errorf("unknown matcher: %v", match)
+		return nil, fmt.Errorf("%w %d for %q", ErrLineFilterInvalidMatchType, mt, match)
 	}
 }
 
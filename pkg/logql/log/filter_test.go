@@ -140,6 +140,62 @@ func Test_TrueFilter(t *testing.T) {
 	}
 }
 
+func Test_PatternFilter(t *testing.T) {
+	line := []byte(`127.0.0.1 - - [23/Jan/2023:10:00:00 +0000] "GET /api/v1/push HTTP/1.1" 500 42`)
+	for _, test := range []struct {
+		pattern string
+		ty      LineMatchType
+		match   bool
+	}{
+		{`<_> - - <_> "GET <_>" 500 <_>`, LineMatchPattern, true},
+		{`<_> - - <_> "GET <_>" 500 <_>`, LineMatchNotPattern, false},
+		{`<_> - - <_> "POST <_>" <_>`, LineMatchPattern, false},
+		{`<_> - - <_> "POST <_>" <_>`, LineMatchNotPattern, true},
+		{`127.0.0.1 <_>`, LineMatchPattern, true},
+		{`<_> 500`, LineMatchPattern, false},
+		{``, LineMatchPattern, true},
+	} {
+		t.Run(test.pattern, func(t *testing.T) {
+			f, err := NewFilter(test.pattern, test.ty)
+			require.NoError(t, err)
+			require.Equal(t, test.match, f.Filter(line))
+			_, ok := f.ToStage().Process(0, line, nil)
+			require.Equal(t, test.match, ok)
+		})
+	}
+}
+
+func Test_NewFilterInvalidMatchType(t *testing.T) {
+	_, err := NewFilter("foo", LineMatchNotPattern+1)
+	require.ErrorIs(t, err, ErrLineFilterInvalidMatchType)
+}
+
+func Test_LineMatchTypeString(t *testing.T) {
+	for ty, expected := range map[LineMatchType]string{
+		LineMatchEqual:          "|=",
+		LineMatchNotEqual:       "!=",
+		LineMatchRegexp:         "|~",
+		LineMatchNotRegexp:      "!~",
+		LineMatchPattern:        "|>",
+		LineMatchNotPattern:     "!>",
+		LineMatchNotPattern + 1: "",
+	} {
+		require.Equal(t, expected, ty.String())
+	}
+}
+
+func Test_AndFiltersOrdering(t *testing.T) {
+	contains := newContainsFilter([]byte("foo"), false)
+	re, err := newRegexpFilter("fo+", true)
+	require.NoError(t, err)
+	p, err := newPatternFilter("<_> foo <_>", true)
+	require.NoError(t, err)
+
+	// contains filters are evaluated first while regexp and pattern filters are pushed to the end.
+	f := NewAndFilters([]Filterer{p, contains, re})
+	require.Equal(t, andFilters{filters: []Filterer{&containsAllFilter{matches: []containsFilter{*contains.(*containsFilter)}}, p, re}}, f)
+}
+
 func Benchmark_LineFilter(b *testing.B) {
 	b.ReportAllocs()
 	logline := `level=bar ts=2020-02-22T14:57:59.398312973Z caller=logging.go:44 traceID=2107b6b551458908 msg="GET /buzz (200) 4.599635ms`
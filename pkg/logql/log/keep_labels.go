@@ -0,0 +1,75 @@
+package log
+
+import (
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/grafana/loki/pkg/logqlmodel"
+)
+
+// KeepLabel is a label to keep in the result set.
+// When Matcher is set, the label is only kept if its value matches, otherwise the label Name is always kept.
+type KeepLabel struct {
+	Matcher *labels.Matcher
+	Name    string
+}
+
+// NewKeepLabel creates a new KeepLabel.
+func NewKeepLabel(matcher *labels.Matcher, name string) KeepLabel {
+	return KeepLabel{
+		Matcher: matcher,
+		Name:    name,
+	}
+}
+
+// KeepLabels is a stage dropping all labels from the result set except the ones to keep.
+// Error labels are always kept.
+type KeepLabels struct {
+	keepLabels []KeepLabel
+}
+
+// NewKeepLabels creates a KeepLabels stage from the given labels.
+func NewKeepLabels(kl []KeepLabel) *KeepLabels {
+	return &KeepLabels{keepLabels: kl}
+}
+
+func (kl *KeepLabels) Process(_ int64, line []byte, lbls *LabelsBuilder) ([]byte, bool) {
+	if len(kl.keepLabels) == 0 {
+		return line, true
+	}
+	for _, lb := range lbls.UnsortedLabels(nil) {
+		if lb.Name == logqlmodel.ErrorLabel || lb.Name == logqlmodel.ErrorDetailsLabel {
+			continue
+		}
+		if !kl.keep(lb) {
+			lbls.Del(lb.Name)
+		}
+	}
+	return line, true
+}
+
+func (kl *KeepLabels) keep(lb labels.Label) bool {
+	for _, keepLabel := range kl.keepLabels {
+		if keepLabel.Matcher != nil {
+			if keepLabel.Matcher.Name == lb.Name && keepLabel.Matcher.Matches(lb.Value) {
+				return true
+			}
+			continue
+		}
+		if keepLabel.Name == lb.Name {
+			return true
+		}
+	}
+	return false
+}
+
+func (kl *KeepLabels) RequiredLabelNames() []string {
+	names := make([]string, 0, len(kl.keepLabels))
+	for _, keepLabel := range kl.keepLabels {
+		if keepLabel.Matcher != nil {
+			names = append(names, keepLabel.Matcher.Name)
+			continue
+		}
+		names = append(names, keepLabel.Name)
+	}
+	return uniqueString(names)
+}
@@ -0,0 +1,95 @@
+package log
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/logqlmodel"
+)
+
+func Test_KeepLabels(t *testing.T) {
+	tests := []struct {
+		name       string
+		keepLabels []KeepLabel
+		err        string
+		lbs        labels.Labels
+		want       labels.Labels
+	}{
+		{
+			"keep all",
+			[]KeepLabel{},
+			"",
+			labels.Labels{
+				{Name: "app", Value: "foo"},
+				{Name: "namespace", Value: "prod"},
+			},
+			labels.Labels{
+				{Name: "app", Value: "foo"},
+				{Name: "namespace", Value: "prod"},
+			},
+		},
+		{
+			"keep by name",
+			[]KeepLabel{
+				NewKeepLabel(nil, "app"),
+				NewKeepLabel(nil, "missing"),
+			},
+			"",
+			labels.Labels{
+				{Name: "app", Value: "foo"},
+				{Name: "namespace", Value: "prod"},
+				{Name: "pod", Value: "pod1"},
+			},
+			labels.Labels{
+				{Name: "app", Value: "foo"},
+			},
+		},
+		{
+			"keep by matcher",
+			[]KeepLabel{
+				NewKeepLabel(labels.MustNewMatcher(labels.MatchEqual, "namespace", "prod"), ""),
+				NewKeepLabel(labels.MustNewMatcher(labels.MatchRegexp, "pod", "pod2.*"), ""),
+			},
+			"",
+			labels.Labels{
+				{Name: "app", Value: "foo"},
+				{Name: "namespace", Value: "prod"},
+				{Name: "pod", Value: "pod1"},
+			},
+			labels.Labels{
+				{Name: "namespace", Value: "prod"},
+			},
+		},
+		{
+			"error labels are always kept",
+			[]KeepLabel{
+				NewKeepLabel(nil, "app"),
+			},
+			errJSON,
+			labels.Labels{
+				{Name: "app", Value: "foo"},
+				{Name: "namespace", Value: "prod"},
+			},
+			labels.Labels{
+				{Name: logqlmodel.ErrorLabel, Value: errJSON},
+				{Name: "app", Value: "foo"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keepLabels := NewKeepLabels(tt.keepLabels)
+			lbls := NewBaseLabelsBuilder().ForLabels(tt.lbs, tt.lbs.Hash())
+			lbls.Reset()
+			lbls.SetErr(tt.err)
+			line, ok := keepLabels.Process(0, []byte("line"), lbls)
+			require.True(t, ok)
+			require.Equal(t, []byte("line"), line)
+			sort.Sort(tt.want)
+			require.Equal(t, tt.want, lbls.LabelsResult().Labels())
+		})
+	}
+}
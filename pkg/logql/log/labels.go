@@ -226,6 +226,19 @@ func (b *LabelsBuilder) Set(n, v string) *LabelsBuilder {
 	return b
 }
 
+// Add the labels to the builder. If a label with the same name
+// already exists in the base labels, a suffix is added to the name.
+func (b *LabelsBuilder) Add(labels ...labels.Label) *LabelsBuilder {
+	for _, l := range labels {
+		name := l.Name
+		if b.BaseHas(name) {
+			name = name + duplicateSuffix
+		}
+		b.Set(name, l.Value)
+	}
+	return b
+}
+
 // Labels returns the labels from the builder. If no modifications
 // were made, the original labels are returned.
 func (b *LabelsBuilder) labels() labels.Labels {
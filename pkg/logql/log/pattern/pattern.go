@@ -13,6 +13,7 @@ var (
 type Matcher interface {
 	Matches(in []byte) [][]byte
 	Names() []string
+	Test(in []byte) bool
 }
 
 type matcher struct {
@@ -37,6 +38,22 @@ func New(in string) (Matcher, error) {
 	}, nil
 }
 
+// ParseLineFilter creates a Matcher used to filter lines.
+// Unlike New, captures are not required since nothing is extracted.
+func ParseLineFilter(in string) (Matcher, error) {
+	e, err := parseExpr(in)
+	if err != nil {
+		return nil, err
+	}
+	if err := e.validateNoConsecutiveCaptures(); err != nil {
+		return nil, err
+	}
+	return &matcher{
+		e:     e,
+		names: e.captures(),
+	}, nil
+}
+
 // Matches matches the given line with the provided pattern.
 // Matches invalidates the previous returned captures array.
 func (m *matcher) Matches(in []byte) [][]byte {
@@ -93,3 +110,34 @@ func (m *matcher) Matches(in []byte) [][]byte {
 func (m *matcher) Names() []string {
 	return m.names
 }
+
+// Test tells if the given line matches the pattern, without capturing anything.
+// Literals must be found in order. The pattern is anchored to the start and the end of the line
+// unless it respectively starts or ends with a capture.
+func (m *matcher) Test(in []byte) bool {
+	expr := m.e
+	if ls, ok := expr[0].(literals); ok {
+		if !bytes.HasPrefix(in, ls) {
+			return false
+		}
+		in = in[len(ls):]
+		expr = expr[1:]
+	}
+	// from now we have capture - literals - capture ... (literals)?
+	for len(expr) != 0 {
+		if len(expr) == 1 { // we're ending on a capture which takes the rest of the line.
+			return true
+		}
+		ls := expr[1].(literals)
+		expr = expr[2:]
+		if len(expr) == 0 { // we're ending on literals which must end the line.
+			return bytes.HasSuffix(in, ls)
+		}
+		i := bytes.Index(in, ls)
+		if i == -1 {
+			return false
+		}
+		in = in[len(ls)+i:]
+	}
+	return len(in) == 0
+}
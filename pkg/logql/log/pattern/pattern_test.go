@@ -162,3 +162,53 @@ func Test_Error(t *testing.T) {
 		})
 	}
 }
+
+func Test_matcher_Test(t *testing.T) {
+	for _, tt := range []struct {
+		pattern string
+		in      string
+		match   bool
+	}{
+		{"foo", "foo", true},
+		{"foo", "foo bar", false},
+		{"foo <_>", "foo bar", true},
+		{"foo <_>", "bar foo bar", false},
+		{"<_> bar", "foo bar", true},
+		{"<_> bar", "foo bar baz", false},
+		{"<_> bar <_>", "foo bar baz", true},
+		{"<_> bar <_>", "foo baz", false},
+		{"<a> - <b> end", "1 - 2 - 3 end", true},
+		{"<a> - <b> end", "1 - 2 - 3 end of line", false},
+		{"<_> \"GET <_>\" 500 <_>", `127.0.0.1 - - [2023] "GET /foo HTTP/1.1" 500 42`, true},
+		{"<_> \"GET <_>\" 500 <_>", `127.0.0.1 - - [2023] "GET /foo HTTP/1.1" 200 42`, false},
+		{"<_> \"GET <_>\" 500 <_>", `127.0.0.1 - - [2023] "POST /foo HTTP/1.1" 500 42`, false},
+		{"a<_>a", "a", false},
+		{"a<_>a", "aa", true},
+	} {
+		tt := tt
+		t.Run(tt.pattern+"_"+tt.in, func(t *testing.T) {
+			t.Parallel()
+			m, err := ParseLineFilter(tt.pattern)
+			require.NoError(t, err)
+			require.Equal(t, tt.match, m.Test([]byte(tt.in)))
+		})
+	}
+}
+
+func Test_ParseLineFilter_Error(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		err  error
+	}{
+		{"<_>", nil},
+		{"foo bar buzz", nil},
+		{"<f> f<f>", nil},
+		{"", newParseError("syntax error: unexpected $end, expecting IDENTIFIER or LITERAL", 1, 1)},
+		{"<f><f>", fmt.Errorf("found consecutive capture '<f><f>': %w", ErrInvalidExpr)},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseLineFilter(tt.name)
+			require.Equal(t, tt.err, err)
+		})
+	}
+}
@@ -50,6 +50,31 @@ func TestPipeline(t *testing.T) {
 	require.Equal(t, false, matches)
 }
 
+func TestPipelineWithStructuredMetadata(t *testing.T) {
+	lbs := labels.Labels{{Name: "foo", Value: "bar"}}
+	structuredMetadata := labels.Labels{{Name: "traceID", Value: "123"}, {Name: "foo", Value: "baz"}}
+	expectedLabels := labels.Labels{{Name: "foo", Value: "bar"}, {Name: "foo_extracted", Value: "baz"}, {Name: "traceID", Value: "123"}}
+
+	p := NewPipeline([]Stage{
+		NewStringLabelFilter(labels.MustNewMatcher(labels.MatchEqual, "traceID", "123")),
+		newMustLineFormatter("lbs {{.foo}} {{.traceID}}"),
+	})
+	sp := p.ForStream(lbs)
+	l, lbr, matches := sp.Process(0, []byte("line"), structuredMetadata...)
+	require.Equal(t, []byte("lbs bar 123"), l)
+	require.Equal(t, NewLabelsResult(expectedLabels, expectedLabels.Hash()), lbr)
+	require.Equal(t, true, matches)
+
+	// The structured metadata of an entry doesn't leak to the next one.
+	_, _, matches = sp.ProcessString(0, "line")
+	require.Equal(t, false, matches)
+
+	ls, lbr, matches := NewNoopPipeline().ForStream(lbs).ProcessString(0, "line", structuredMetadata...)
+	require.Equal(t, "line", ls)
+	require.Equal(t, NewLabelsResult(expectedLabels, expectedLabels.Hash()), lbr)
+	require.Equal(t, true, matches)
+}
+
 func TestFilteringPipeline(t *testing.T) {
 	p := NewFilteringPipeline([]PipelineFilter{
 		newPipelineFilter(2, 4, labels.Labels{{Name: "foo", Value: "bar"}, {Name: "bar", Value: "baz"}}, "e"),
@@ -91,7 +116,7 @@ func newPipelineFilter(start, end int64, lbls labels.Labels, filter string) Pipe
 		m := labels.MustNewMatcher(labels.MatchEqual, l.Name, l.Value)
 		matchers = append(matchers, m)
 	}
-	stages = append(stages, mustFilter(NewFilter(filter, labels.MatchEqual)).ToStage())
+	stages = append(stages, mustFilter(NewFilter(filter, LineMatchEqual)).ToStage())
 
 	return PipelineFilter{start, end, matchers, NewPipeline(stages)}
 }
@@ -118,11 +143,11 @@ func (p *stubStreamPipeline) BaseLabels() LabelsResult {
 	return nil
 }
 
-func (p *stubStreamPipeline) Process(ts int64, line []byte) ([]byte, LabelsResult, bool) {
+func (p *stubStreamPipeline) Process(ts int64, line []byte, structuredMetadata ...labels.Label) ([]byte, LabelsResult, bool) {
 	return nil, nil, true
 }
 
-func (p *stubStreamPipeline) ProcessString(ts int64, line string) (string, LabelsResult, bool) {
+func (p *stubStreamPipeline) ProcessString(ts int64, line string, structuredMetadata ...labels.Label) (string, LabelsResult, bool) {
 	return "", nil, true
 }
 
@@ -138,7 +163,7 @@ func Benchmark_Pipeline(b *testing.B) {
 	b.ReportAllocs()
 
 	stages := []Stage{
-		mustFilter(NewFilter("metrics.go", labels.MatchEqual)).ToStage(),
+		mustFilter(NewFilter("metrics.go", LineMatchEqual)).ToStage(),
 		NewLogfmtParser(),
 		NewAndLabelFilter(
 			NewDurationLabelFilter(LabelFilterGreaterThan, "duration", 10*time.Millisecond),
@@ -223,7 +248,7 @@ func jsonBenchmark(b *testing.B, parser Stage) {
 	b.ReportAllocs()
 
 	p := NewPipeline([]Stage{
-		mustFilter(NewFilter("metrics.go", labels.MatchEqual)).ToStage(),
+		mustFilter(NewFilter("metrics.go", LineMatchEqual)).ToStage(),
 		parser,
 	})
 	line := []byte(`{"ts":"2020-12-27T09:15:54.333026285Z","error":"action could not be completed", "context":{"file": "metrics.go"}}`)
@@ -256,7 +281,7 @@ func invalidJSONBenchmark(b *testing.B, parser Stage) {
 	b.ReportAllocs()
 
 	p := NewPipeline([]Stage{
-		mustFilter(NewFilter("invalid json", labels.MatchEqual)).ToStage(),
+		mustFilter(NewFilter("invalid json", LineMatchEqual)).ToStage(),
 		parser,
 	})
 	line := []byte(`invalid json`)
@@ -0,0 +1,280 @@
+package logql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+
+	"github.com/grafana/loki/pkg/logql/sketch"
+	"github.com/grafana/loki/pkg/logql/syntax"
+)
+
+const (
+	// countMinSketchCellLabel is the label holding the `row:column` position of a count-min sketch counter.
+	//
+	// A count-min sketch is returned by downstream queries as a vector with one sample per non zero counter,
+	// followed by the candidate series of the top k, which keep their original labels.
+	// Sketches from different shards are merged by adding up the counters at the same position.
+	countMinSketchCellLabel = "__count_min_sketch_cell__"
+
+	// hyperLogLogRegisterLabel is the label holding the index of a HyperLogLog register.
+	//
+	// A HyperLogLog sketch is returned by downstream queries as a vector with one sample per non zero register
+	// of each group, the sample value being the register value.
+	// Sketches from different shards are merged by keeping the maximum of each register.
+	hyperLogLogRegisterLabel = "__hyperloglog_register__"
+)
+
+// SketchLabels are the labels distinguishing the samples of a same sketch returned by downstream queries.
+// The samples of a sketch only count as one series, once the labels are ignored. Sorted.
+var SketchLabels = []string{countMinSketchCellLabel, hyperLogLogRegisterLabel, quantileSketchBinLabel}
+
+// sketchLabel returns the label distinguishing the samples of a same sketch, if the expression returns sketches.
+func sketchLabel(expr syntax.SampleExpr) (string, bool) {
+	switch e := expr.(type) {
+	case *syntax.RangeAggregationExpr:
+		if isQuantileSketch(e) {
+			return quantileSketchBinLabel, true
+		}
+	case *syntax.VectorAggregationExpr:
+		switch e.Operation {
+		case syntax.OpTypeCountMinSketch:
+			return countMinSketchCellLabel, true
+		case syntax.OpTypeHyperLogLog:
+			return hyperLogLogRegisterLabel, true
+		}
+	}
+	return "", false
+}
+
+// countMinSketchVector returns the counters of a count-min sketch of the sample values keyed by series,
+// followed by the k samples with the highest values of each group, which are the candidates for the top k.
+func countMinSketchVector(vec promql.Vector, ts int64, expr *syntax.VectorAggregationExpr, lb *labels.Builder) promql.Vector {
+	cms := sketch.NewDefaultCountMinSketch()
+	groups := map[uint64]promql.Vector{}
+	for _, s := range vec {
+		cms.Add(s.Metric.Hash(), s.V)
+		resetToGroupLabels(lb, s.Metric, expr.Grouping)
+		key := lb.Labels(nil).Hash()
+		groups[key] = append(groups[key], s)
+	}
+
+	var res promql.Vector
+	cms.ForEach(func(row, column uint32, value float64) bool {
+		res = append(res, promql.Sample{
+			Metric: labels.Labels{{Name: countMinSketchCellLabel, Value: fmt.Sprintf("%d:%d", row, column)}},
+			Point: promql.Point{
+				T: ts,
+				V: value,
+			},
+		})
+		return true
+	})
+	for _, group := range groups {
+		sortByValue(group, true)
+		if len(group) > expr.Params {
+			group = group[:expr.Params]
+		}
+		for _, s := range group {
+			res = append(res, promql.Sample{
+				Metric: s.Metric,
+				Point: promql.Point{
+					T: ts,
+					V: s.V,
+				},
+			})
+		}
+	}
+	return res
+}
+
+// hyperLogLogVector returns the registers of a HyperLogLog sketch of the series of each group.
+func hyperLogLogVector(vec promql.Vector, ts int64, expr *syntax.VectorAggregationExpr, lb *labels.Builder) promql.Vector {
+	type group struct {
+		metric labels.Labels
+		hll    *sketch.HyperLogLog
+	}
+	groups := map[uint64]*group{}
+	for _, s := range vec {
+		resetToGroupLabels(lb, s.Metric, expr.Grouping)
+		metric := lb.Labels(nil)
+		key := metric.Hash()
+		g, ok := groups[key]
+		if !ok {
+			g = &group{
+				metric: metric,
+				hll:    sketch.NewDefaultHyperLogLog(),
+			}
+			groups[key] = g
+		}
+		g.hll.Add(s.Metric.Hash())
+	}
+
+	var res promql.Vector
+	for _, g := range groups {
+		lb.Reset(g.metric)
+		g.hll.ForEach(func(index uint32, value uint8) bool {
+			lb.Set(hyperLogLogRegisterLabel, strconv.FormatUint(uint64(index), 10))
+			res = append(res, promql.Sample{
+				Metric: lb.Labels(nil),
+				Point: promql.Point{
+					T: ts,
+					V: float64(value),
+				},
+			})
+			return true
+		})
+	}
+	return res
+}
+
+// CountMinSketchEvalExpr estimates the values of the top k candidates returned by its downstream queries
+// using the merge of their count-min sketches.
+type CountMinSketchEvalExpr struct {
+	syntax.SampleExpr
+}
+
+func (e *CountMinSketchEvalExpr) String() string {
+	return fmt.Sprintf("countMinSketchEval<%s>", e.SampleExpr.String())
+}
+
+func (e *CountMinSketchEvalExpr) Walk(f syntax.WalkFn) {
+	f(e)
+	e.SampleExpr.Walk(f)
+}
+
+// HyperLogLogEvalExpr estimates the number of series of each group using the merge of
+// the HyperLogLog sketches returned by its downstream queries.
+type HyperLogLogEvalExpr struct {
+	syntax.SampleExpr
+}
+
+func (e *HyperLogLogEvalExpr) String() string {
+	return fmt.Sprintf("hyperLogLogEval<%s>", e.SampleExpr.String())
+}
+
+func (e *HyperLogLogEvalExpr) Walk(f syntax.WalkFn) {
+	f(e)
+	e.SampleExpr.Walk(f)
+}
+
+// sketchMergeEvaluator merges the sketches returned by the evaluator at every step.
+func sketchMergeEvaluator(ev StepEvaluator, merge func(promql.Vector, int64) (promql.Vector, error)) (StepEvaluator, error) {
+	var lastErr error
+	return newStepEvaluator(
+		func() (bool, int64, promql.Vector) {
+			next, ts, vec := ev.Next()
+			if !next {
+				return false, 0, promql.Vector{}
+			}
+			res, err := merge(vec, ts)
+			if err != nil {
+				lastErr = err
+				return false, 0, promql.Vector{}
+			}
+			return true, ts, res
+		},
+		ev.Close,
+		func() error {
+			if lastErr != nil {
+				return lastErr
+			}
+			return ev.Error()
+		},
+	)
+}
+
+func mergeCountMinSketches(vec promql.Vector, ts int64) (promql.Vector, error) {
+	var (
+		cms        = sketch.NewDefaultCountMinSketch()
+		candidates = map[uint64]labels.Labels{}
+	)
+	for _, s := range vec {
+		cell := s.Metric.Get(countMinSketchCellLabel)
+		if cell == "" {
+			candidates[s.Metric.Hash()] = s.Metric
+			continue
+		}
+		row, column, err := parseCountMinSketchCell(cell)
+		if err != nil {
+			return nil, err
+		}
+		if err := cms.AddCounter(row, column, s.V); err != nil {
+			return nil, err
+		}
+	}
+	res := make(promql.Vector, 0, len(candidates))
+	for hash, metric := range candidates {
+		res = append(res, promql.Sample{
+			Metric: metric,
+			Point: promql.Point{
+				T: ts,
+				V: cms.Count(hash),
+			},
+		})
+	}
+	return res, nil
+}
+
+func parseCountMinSketchCell(cell string) (uint32, uint32, error) {
+	r, c, ok := strings.Cut(cell, ":")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid count-min sketch cell %q", cell)
+	}
+	row, err := strconv.ParseUint(r, 10, 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid count-min sketch cell %q: %w", cell, err)
+	}
+	column, err := strconv.ParseUint(c, 10, 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid count-min sketch cell %q: %w", cell, err)
+	}
+	return uint32(row), uint32(column), nil
+}
+
+func mergeHyperLogLogs(vec promql.Vector, ts int64) (promql.Vector, error) {
+	type group struct {
+		metric labels.Labels
+		hll    *sketch.HyperLogLog
+	}
+	var (
+		groups = map[uint64]*group{}
+		lb     = labels.NewBuilder(nil)
+	)
+	for _, s := range vec {
+		register := s.Metric.Get(hyperLogLogRegisterLabel)
+		index, err := strconv.ParseUint(register, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid HyperLogLog register %q for series %s: %w", register, s.Metric, err)
+		}
+		lb.Reset(s.Metric)
+		lb.Del(hyperLogLogRegisterLabel)
+		metric := lb.Labels(nil)
+		key := metric.Hash()
+		g, ok := groups[key]
+		if !ok {
+			g = &group{
+				metric: metric,
+				hll:    sketch.NewDefaultHyperLogLog(),
+			}
+			groups[key] = g
+		}
+		if err := g.hll.SetRegister(uint32(index), uint8(s.V)); err != nil {
+			return nil, err
+		}
+	}
+	res := make(promql.Vector, 0, len(groups))
+	for _, g := range groups {
+		res = append(res, promql.Sample{
+			Metric: g.metric,
+			Point: promql.Point{
+				T: ts,
+				V: g.hll.Estimate(),
+			},
+		})
+	}
+	return res, nil
+}
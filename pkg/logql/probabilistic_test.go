@@ -0,0 +1,99 @@
+package logql
+
+import (
+	"testing"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/logql/sketch"
+	"github.com/grafana/loki/pkg/logql/syntax"
+)
+
+func sample(v float64, lbs ...string) promql.Sample {
+	return promql.Sample{Metric: labels.FromStrings(lbs...), Point: promql.Point{V: v}}
+}
+
+func TestMergeCountMinSketches(t *testing.T) {
+	expr := &syntax.VectorAggregationExpr{
+		Operation: syntax.OpTypeCountMinSketch,
+		Params:    1,
+		Grouping:  &syntax.Grouping{},
+	}
+	lb := labels.NewBuilder(nil)
+	// foo is the top series overall, but not within any of the shards.
+	shard1 := countMinSketchVector(promql.Vector{
+		sample(6, "user", "foo"),
+		sample(7, "user", "bar"),
+	}, 10, expr, lb)
+	shard2 := countMinSketchVector(promql.Vector{
+		sample(6, "user", "foo"),
+		sample(1, "user", "bar"),
+		sample(8, "user", "buzz"),
+	}, 10, expr, lb)
+	// one counter per row for each series, and the local top series.
+	require.Len(t, shard1, 2*sketch.DefaultCountMinSketchDepth+1)
+
+	res, err := mergeCountMinSketches(append(shard1, shard2...), 10)
+	require.NoError(t, err)
+	values := map[string]float64{}
+	for _, s := range res {
+		require.Equal(t, int64(10), s.T)
+		values[s.Metric.Get("user")] = s.V
+	}
+	// only the local top series are candidates, with their overall sums.
+	require.Equal(t, map[string]float64{"bar": 8, "buzz": 8}, values)
+
+	_, err = mergeCountMinSketches(promql.Vector{sample(1, countMinSketchCellLabel, "foo")}, 10)
+	require.Error(t, err)
+}
+
+func TestMergeHyperLogLogs(t *testing.T) {
+	expr := &syntax.VectorAggregationExpr{
+		Operation: syntax.OpTypeHyperLogLog,
+		Grouping:  &syntax.Grouping{Groups: []string{"app"}},
+	}
+	lb := labels.NewBuilder(nil)
+	shard1 := hyperLogLogVector(promql.Vector{
+		sample(1, "app", "foo", "user", "a"),
+		sample(1, "app", "foo", "user", "b"),
+		sample(1, "app", "bar", "user", "a"),
+	}, 10, expr, lb)
+	shard2 := hyperLogLogVector(promql.Vector{
+		sample(1, "app", "foo", "user", "b"),
+		sample(1, "app", "foo", "user", "c"),
+	}, 10, expr, lb)
+
+	res, err := mergeHyperLogLogs(append(shard1, shard2...), 10)
+	require.NoError(t, err)
+	counts := map[string]float64{}
+	for _, s := range res {
+		require.Equal(t, int64(10), s.T)
+		counts[s.Metric.String()] = s.V
+	}
+	require.Equal(t, map[string]float64{`{app="foo"}`: 3, `{app="bar"}`: 1}, counts)
+
+	_, err = mergeHyperLogLogs(promql.Vector{sample(1, "app", "foo")}, 10)
+	require.Error(t, err)
+}
+
+func TestSketchLabel(t *testing.T) {
+	for _, tc := range []struct {
+		query string
+		label string
+	}{
+		{`__quantile_sketch_over_time__({app="foo"} | unwrap latency [1m]) by (cluster)`, quantileSketchBinLabel},
+		{`__count_min_sketch__(10, sum by (user) (rate({app="foo"}[1m])))`, countMinSketchCellLabel},
+		{`__hyperloglog__(rate({app="foo"}[1m]))`, hyperLogLogRegisterLabel},
+		{`count(rate({app="foo"}[1m]))`, ""},
+	} {
+		t.Run(tc.query, func(t *testing.T) {
+			expr, err := syntax.ParseSampleExpr(tc.query)
+			require.NoError(t, err)
+			label, ok := sketchLabel(expr)
+			require.Equal(t, tc.label != "", ok)
+			require.Equal(t, tc.label, label)
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package logql
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+
+	"github.com/grafana/loki/pkg/iter"
+	"github.com/grafana/loki/pkg/logql/sketch"
+	"github.com/grafana/loki/pkg/logql/syntax"
+)
+
+// quantileSketchBinLabel is the label holding the representative value of a quantile sketch bin.
+//
+// A quantile sketch is returned by downstream queries as a vector with one sample per bin,
+// the sample value being the count of the bin.
+// Since bins only depend on the sketch accuracy, sketches from different shards are merged by adding up the counts of identical bins.
+const quantileSketchBinLabel = "__quantile_sketch_bin__"
+
+// quantileSketchBatchRangeVectorIterator returns the bins of a quantile sketch of the samples of each series within the range.
+type quantileSketchBatchRangeVectorIterator struct {
+	*batchRangeVectorIterator
+}
+
+func newQuantileSketchIterator(it iter.PeekingSampleIterator, selRange, step, start, end, offset int64) RangeVectorIterator {
+	return &quantileSketchBatchRangeVectorIterator{
+		batchRangeVectorIterator: &batchRangeVectorIterator{
+			iter:     it,
+			step:     step,
+			end:      end,
+			selRange: selRange,
+			metrics:  map[string]labels.Labels{},
+			window:   map[string]*promql.Series{},
+			current:  start - step, // first loop iteration will set it to start
+			offset:   offset,
+		},
+	}
+}
+
+func (r *quantileSketchBatchRangeVectorIterator) At() (int64, promql.Vector) {
+	if r.at == nil {
+		r.at = make([]promql.Sample, 0, len(r.window))
+	}
+	r.at = r.at[:0]
+	// convert ts from nano to milli seconds as the iterator work with nanoseconds
+	ts := r.current/1e+6 + r.offset/1e+6
+	for _, series := range r.window {
+		s := sketch.NewDefaultDDSketch()
+		for _, p := range series.Points {
+			s.Add(p.V)
+		}
+		lb := labels.NewBuilder(series.Metric)
+		s.ForEach(func(value, count float64) bool {
+			lb.Set(quantileSketchBinLabel, strconv.FormatFloat(value, 'g', -1, 64))
+			r.at = append(r.at, promql.Sample{
+				Point: promql.Point{
+					V: count,
+					T: ts,
+				},
+				Metric: lb.Labels(nil),
+			})
+			return true
+		})
+	}
+	return ts, r.at
+}
+
+// isQuantileSketch tells if the expression returns the bins of quantile sketches.
+func isQuantileSketch(expr syntax.SampleExpr) bool {
+	e, ok := expr.(*syntax.RangeAggregationExpr)
+	return ok && e.Operation == syntax.OpRangeTypeQuantileSketch
+}
+
+// newSketchSeriesCounter returns a function counting the unique series of the sketches seen so far, regardless of their sketchLabel.
+func newSketchSeriesCounter(sketchLabel string) func(promql.Vector, int) int {
+	var (
+		series = map[uint64]struct{}{}
+		buf    = make([]byte, 0, 1024)
+		hash   uint64
+	)
+	return func(vec promql.Vector, _ int) int {
+		for _, s := range vec {
+			hash, buf = s.Metric.HashWithoutLabels(buf, sketchLabel)
+			series[hash] = struct{}{}
+		}
+		return len(series)
+	}
+}
+
+// QuantileSketchEvalExpr evaluates a quantile from the quantile sketches returned by its downstream queries.
+type QuantileSketchEvalExpr struct {
+	syntax.SampleExpr
+	quantile float64
+}
+
+func (e *QuantileSketchEvalExpr) String() string {
+	return fmt.Sprintf("quantileSketchEval<%s, quantile=%s>", e.SampleExpr.String(), strconv.FormatFloat(e.quantile, 'f', -1, 64))
+}
+
+func (e *QuantileSketchEvalExpr) Walk(f syntax.WalkFn) {
+	f(e)
+	e.SampleExpr.Walk(f)
+}
+
+// quantileSketchMergeEvaluator merges the quantile sketches of each series at every step
+// and returns the quantile they estimate.
+func quantileSketchMergeEvaluator(ev StepEvaluator, q float64) (StepEvaluator, error) {
+	return sketchMergeEvaluator(ev, func(vec promql.Vector, ts int64) (promql.Vector, error) {
+		return mergeQuantileSketches(vec, ts, q)
+	})
+}
+
+func mergeQuantileSketches(vec promql.Vector, ts int64, q float64) (promql.Vector, error) {
+	type group struct {
+		metric labels.Labels
+		sketch *sketch.DDSketch
+	}
+	var (
+		groups = map[uint64]*group{}
+		lb     = labels.NewBuilder(nil)
+	)
+	for _, s := range vec {
+		bin := s.Metric.Get(quantileSketchBinLabel)
+		value, err := strconv.ParseFloat(bin, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid quantile sketch bin %q for series %s: %w", bin, s.Metric, err)
+		}
+		lb.Reset(s.Metric)
+		lb.Del(quantileSketchBinLabel)
+		metric := lb.Labels(nil)
+		hash := metric.Hash()
+		g, ok := groups[hash]
+		if !ok {
+			g = &group{
+				metric: metric,
+				sketch: sketch.NewDefaultDDSketch(),
+			}
+			groups[hash] = g
+		}
+		g.sketch.AddN(value, s.V)
+	}
+	res := make(promql.Vector, 0, len(groups))
+	for _, g := range groups {
+		res = append(res, promql.Sample{
+			Metric: g.metric,
+			Point: promql.Point{
+				T: ts,
+				V: g.sketch.Quantile(q),
+			},
+		})
+	}
+	return res, nil
+}
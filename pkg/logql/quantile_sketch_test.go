@@ -0,0 +1,68 @@
+package logql
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/logql/sketch"
+)
+
+func sketchVector(metric labels.Labels, values ...float64) promql.Vector {
+	s := sketch.NewDefaultDDSketch()
+	for _, v := range values {
+		s.Add(v)
+	}
+	var vec promql.Vector
+	s.ForEach(func(value, count float64) bool {
+		vec = append(vec, promql.Sample{
+			Metric: labels.NewBuilder(metric).Set(quantileSketchBinLabel, strconv.FormatFloat(value, 'g', -1, 64)).Labels(nil),
+			Point:  promql.Point{V: count},
+		})
+		return true
+	})
+	return vec
+}
+
+func TestMergeQuantileSketches(t *testing.T) {
+	var (
+		foo = labels.Labels{{Name: "app", Value: "foo"}}
+		bar = labels.Labels{{Name: "app", Value: "bar"}}
+		vec promql.Vector
+	)
+	vec = append(vec, sketchVector(foo, 1, 2, 3, 4, 5)...)
+	vec = append(vec, sketchVector(foo, 6, 7, 8, 9, 10)...)
+	vec = append(vec, sketchVector(bar, 100, 200)...)
+
+	res, err := mergeQuantileSketches(vec, 10, 0.9)
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+	for _, s := range res {
+		require.Equal(t, int64(10), s.T)
+		switch s.Metric.Get("app") {
+		case "foo":
+			require.InEpsilon(t, 9, s.V, sketch.DefaultRelativeAccuracy)
+		case "bar":
+			require.InEpsilon(t, 100, s.V, sketch.DefaultRelativeAccuracy)
+		default:
+			t.Fatalf("unexpected series %s", s.Metric)
+		}
+	}
+
+	_, err = mergeQuantileSketches(promql.Vector{{Metric: foo, Point: promql.Point{V: 1}}}, 10, 0.9)
+	require.Error(t, err)
+}
+
+func TestSketchSeriesCounter(t *testing.T) {
+	count := newSketchSeriesCounter(quantileSketchBinLabel)
+	foo := sketchVector(labels.Labels{{Name: "app", Value: "foo"}}, 1, 10, 100)
+	require.Len(t, foo, 3)
+	require.Equal(t, 1, count(foo, len(foo)))
+
+	bar := sketchVector(labels.Labels{{Name: "app", Value: "bar"}}, 1, 10)
+	require.Equal(t, 2, count(bar, len(bar)))
+	require.Equal(t, 2, count(foo, len(foo)))
+}
@@ -51,6 +51,9 @@ func newRangeVectorIterator(
 		start = start - offset
 		end = end - offset
 	}
+	if expr.Operation == syntax.OpRangeTypeQuantileSketch {
+		return newQuantileSketchIterator(it, selRange, step, start, end, offset), nil
+	}
 	var overlap bool
 	if selRange >= step && start != end {
 		overlap = true
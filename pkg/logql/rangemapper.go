@@ -19,6 +19,10 @@ var splittableVectorOp = map[string]struct{}{
 	syntax.OpTypeMin:   {},
 	syntax.OpTypeAvg:   {},
 	syntax.OpTypeTopK:  {},
+	// sort and count_values are applied on the results of the split inner expression.
+	syntax.OpTypeSort:        {},
+	syntax.OpTypeSortDesc:    {},
+	syntax.OpTypeCountValues: {},
 }
 
 var splittableRangeVectorOp = map[string]struct{}{
@@ -154,6 +158,15 @@ func (m RangeMapper) Map(expr syntax.SampleExpr, vectorAggrPushdown *syntax.Vect
 		}
 		e.Left = lhsMapped
 		return e, nil
+	case *syntax.SubqueryExpr:
+		// The subquery range itself is never split, since its samples depend on its step alignment,
+		// but its inner expression can be. The vector aggregation cannot be pushed down through the subquery.
+		lhsMapped, err := m.Map(e.Left, nil, recorder)
+		if err != nil {
+			return nil, err
+		}
+		e.Left = lhsMapped
+		return e, nil
 	case *syntax.LiteralExpr:
 		return e, nil
 	case *syntax.VectorExpr:
@@ -323,10 +336,10 @@ func (m RangeMapper) mapVectorAggregationExpr(expr *syntax.VectorAggregationExpr
 
 	// In order to minimize the amount of streams on the downstream query,
 	// we can push down the outer vector aggregation to the downstream query.
-	// This does not work for `count()` and `topk()`, though.
+	// This does not work for `count()`, `topk()`, `sort()` and `count_values()`, though.
 	// We also do not want to push down, if the inner expression is a binary operation.
 	var vectorAggrPushdown *syntax.VectorAggregationExpr
-	if _, ok := expr.Left.(*syntax.BinOpExpr); !ok && expr.Operation != syntax.OpTypeCount && expr.Operation != syntax.OpTypeTopK {
+	if _, ok := expr.Left.(*syntax.BinOpExpr); !ok && canPushDownVectorAggregation(expr.Operation) {
 		vectorAggrPushdown = expr
 	}
 
@@ -341,9 +354,20 @@ func (m RangeMapper) mapVectorAggregationExpr(expr *syntax.VectorAggregationExpr
 		Grouping:  expr.Grouping,
 		Params:    expr.Params,
 		Operation: expr.Operation,
+		Label:     expr.Label,
 	}, nil
 }
 
+// canPushDownVectorAggregation tells if a vector aggregation can be pushed down to the downstream queries.
+func canPushDownVectorAggregation(op string) bool {
+	switch op {
+	case syntax.OpTypeCount, syntax.OpTypeTopK, syntax.OpTypeSort, syntax.OpTypeSortDesc, syntax.OpTypeCountValues:
+		return false
+	default:
+		return true
+	}
+}
+
 // mapRangeAggregationExpr maps expr into a new SampleExpr with multiple downstream subqueries split by range interval
 // Optimization: in order to reduce the returned stream from the inner downstream functions, in case a range aggregation
 // expression is aggregated by a vector aggregation expression with a label grouping, the downstream expression can be
@@ -424,6 +448,7 @@ func (m RangeMapper) mapRangeAggregationExpr(expr *syntax.RangeAggregationExpr,
 // supported.
 // A binary expression is splittable, if both the left and the right-hand side
 // are splittable.
+// A subquery is splittable, if its inner expression is splittable.
 func isSplittableByRange(expr syntax.SampleExpr) bool {
 	switch e := expr.(type) {
 	case *syntax.VectorAggregationExpr:
@@ -440,6 +465,8 @@ func isSplittableByRange(expr syntax.SampleExpr) bool {
 		return isSplittableByRange(e.SampleExpr) || literalLHS && isSplittableByRange(e.RHS) || literalRHS
 	case *syntax.LabelReplaceExpr:
 		return isSplittableByRange(e.Left)
+	case *syntax.SubqueryExpr:
+		return isSplittableByRange(e.Left)
 	case *syntax.VectorExpr:
 		return false
 	default:
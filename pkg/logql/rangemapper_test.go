@@ -1546,6 +1546,18 @@ func Test_SplitRangeVectorMapping(t *testing.T) {
 				"foo", "$1", "service", "(.*):.*"
 			)`,
 		},
+
+		// Subqueries: only the inner expression is split
+		{
+			`max_over_time(count_over_time({app="foo"}[3m])[1h:1m])`,
+			`max_over_time(
+				sum without(
+					downstream<count_over_time({app="foo"}[1m] offset 2m0s), shard=<nil>>
+					++ downstream<count_over_time({app="foo"}[1m] offset 1m0s), shard=<nil>>
+					++ downstream<count_over_time({app="foo"}[1m]), shard=<nil>>
+				)[1h:1m]
+			)`,
+		},
 	} {
 		tc := tc
 		t.Run(tc.expr, func(t *testing.T) {
@@ -1728,6 +1740,12 @@ func Test_SplitRangeVectorMapping_Noop(t *testing.T) {
 			`5 * 5`,
 			`25`,
 		},
+		// should be noop if the inner expression of a subquery is not splittable
+		{
+			`max_over_time(count_over_time({app="foo"} | json [3m])[1h:1m])`,
+			`max_over_time(count_over_time({app="foo"} | json [3m])[1h:1m])`,
+		},
+
 		// should be noop if VectorExpr
 		{
 			`vector(0)`,
@@ -23,12 +23,15 @@ func (s ConstantShards) Shards(_ syntax.Expr) (int, error) { return int(s), nil
 type ShardMapper struct {
 	shards  ShardResolver
 	metrics *MapperMetrics
+	// probabilistic allows sharding queries that can only be merged approximately.
+	probabilistic bool
 }
 
-func NewShardMapper(resolver ShardResolver, metrics *MapperMetrics) ShardMapper {
+func NewShardMapper(resolver ShardResolver, metrics *MapperMetrics, probabilistic bool) ShardMapper {
 	return ShardMapper{
-		shards:  resolver,
-		metrics: metrics,
+		shards:        resolver,
+		metrics:       metrics,
+		probabilistic: probabilistic,
 	}
 }
 
@@ -84,6 +87,8 @@ func (m ShardMapper) Map(expr syntax.Expr, r *downstreamRecorder) (syntax.Expr,
 		return m.mapLabelReplaceExpr(e, r)
 	case *syntax.RangeAggregationExpr:
 		return m.mapRangeAggregationExpr(e, r)
+	case *syntax.SubqueryExpr:
+		return m.mapSubqueryExpr(e, r)
 	case *syntax.BinOpExpr:
 		lhsMapped, err := m.Map(e.SampleExpr, r)
 		if err != nil {
@@ -174,6 +179,12 @@ func (m ShardMapper) mapSampleExpr(expr syntax.SampleExpr, r *downstreamRecorder
 // technically, std{dev,var} are also parallelizable if there is no cross-shard merging
 // in descendent nodes in the AST. This optimization is currently avoided for simplicity.
 func (m ShardMapper) mapVectorAggregationExpr(expr *syntax.VectorAggregationExpr, r *downstreamRecorder) (syntax.SampleExpr, error) {
+	if m.probabilistic {
+		if mapped, ok, err := m.mapProbabilisticVectorAggregationExpr(expr, r); ok || err != nil {
+			return mapped, err
+		}
+	}
+
 	// if this AST contains unshardable operations, don't shard this at this level,
 	// but attempt to shard a child node.
 	if !expr.Shardable() {
@@ -191,6 +202,7 @@ func (m ShardMapper) mapVectorAggregationExpr(expr *syntax.VectorAggregationExpr
 			Grouping:  expr.Grouping,
 			Params:    expr.Params,
 			Operation: expr.Operation,
+			Label:     expr.Label,
 		}, nil
 
 	}
@@ -256,6 +268,79 @@ func (m ShardMapper) mapVectorAggregationExpr(expr *syntax.VectorAggregationExpr
 	}
 }
 
+// mapProbabilisticVectorAggregationExpr shards the vector aggregations which can be estimated by merging
+// a sketch from each shard, it returns false if the expression can't be estimated this way.
+func (m ShardMapper) mapProbabilisticVectorAggregationExpr(expr *syntax.VectorAggregationExpr, r *downstreamRecorder) (syntax.SampleExpr, bool, error) {
+	switch {
+	case expr.Operation == syntax.OpTypeTopK && isSummedRangeCount(expr.Left):
+		// the sums of the series spanning multiple shards are estimated by merging a count-min sketch from each shard,
+		// and the top k is selected among the local top k of each shard.
+		// topk(k, x) by (g) -> topk(k, cms(cms(k, x, shard=1) by (g) ++ cms(k, x, shard=2) by (g)...)) by (g)
+		sharded, err := m.mapSampleExpr(&syntax.VectorAggregationExpr{
+			Left:      expr.Left,
+			Grouping:  expr.Grouping,
+			Params:    expr.Params,
+			Operation: syntax.OpTypeCountMinSketch,
+		}, r)
+		if err != nil {
+			return nil, false, err
+		}
+		return &syntax.VectorAggregationExpr{
+			Left:      &CountMinSketchEvalExpr{SampleExpr: sharded},
+			Grouping:  expr.Grouping,
+			Params:    expr.Params,
+			Operation: expr.Operation,
+		}, true, nil
+
+	case expr.Operation == syntax.OpTypeCount && !expr.Shardable() && preservesSeries(expr.Left):
+		// the series spanning multiple shards are counted once by merging a HyperLogLog sketch from each shard.
+		// count(x) by (g) -> hll(hll(x, shard=1) by (g) ++ hll(x, shard=2) by (g)...)
+		sharded, err := m.mapSampleExpr(&syntax.VectorAggregationExpr{
+			Left:      expr.Left,
+			Grouping:  expr.Grouping,
+			Operation: syntax.OpTypeHyperLogLog,
+		}, r)
+		if err != nil {
+			return nil, false, err
+		}
+		return &HyperLogLogEvalExpr{SampleExpr: sharded}, true, nil
+	}
+	return nil, false, nil
+}
+
+// isSummedRangeCount tells if the expression sums log range aggregations whose shard results add up,
+// e.g. `sum by (host) (rate({app="foo"} | json [1m]))`.
+func isSummedRangeCount(expr syntax.SampleExpr) bool {
+	sum, ok := expr.(*syntax.VectorAggregationExpr)
+	if !ok || sum.Operation != syntax.OpTypeSum {
+		return false
+	}
+	rangeAgg, ok := sum.Left.(*syntax.RangeAggregationExpr)
+	if !ok || rangeAgg.Left.Unwrap != nil {
+		return false
+	}
+	switch rangeAgg.Operation {
+	case syntax.OpRangeTypeCount, syntax.OpRangeTypeRate, syntax.OpRangeTypeBytes, syntax.OpRangeTypeBytesRate:
+		return true
+	}
+	return false
+}
+
+// preservesSeries tells if every series of the expression is returned by each shard having samples for it,
+// so that the shards return together all its series.
+func preservesSeries(expr syntax.SampleExpr) bool {
+	switch e := expr.(type) {
+	case *syntax.RangeAggregationExpr:
+		return e.Operation != syntax.OpRangeTypeAbsent
+	case *syntax.VectorAggregationExpr:
+		switch e.Operation {
+		case syntax.OpTypeSum, syntax.OpTypeMin, syntax.OpTypeMax, syntax.OpTypeAvg, syntax.OpTypeCount, syntax.OpTypeStddev, syntax.OpTypeStdvar:
+			return preservesSeries(e.Left)
+		}
+	}
+	return false
+}
+
 func (m ShardMapper) mapLabelReplaceExpr(expr *syntax.LabelReplaceExpr, r *downstreamRecorder) (syntax.SampleExpr, error) {
 	subMapped, err := m.Map(expr.Left, r)
 	if err != nil {
@@ -266,6 +351,22 @@ func (m ShardMapper) mapLabelReplaceExpr(expr *syntax.LabelReplaceExpr, r *downs
 	return &cpy, nil
 }
 
+// mapSubqueryExpr shards the inner expression of a subquery, the aggregation over time
+// is then applied on the merged results of the downstream queries.
+func (m ShardMapper) mapSubqueryExpr(expr *syntax.SubqueryExpr, r *downstreamRecorder) (syntax.SampleExpr, error) {
+	subMapped, err := m.Map(expr.Left, r)
+	if err != nil {
+		return nil, err
+	}
+	sampleExpr, ok := subMapped.(syntax.SampleExpr)
+	if !ok {
+		return nil, badASTMapping(subMapped)
+	}
+	cpy := *expr
+	cpy.Left = sampleExpr
+	return &cpy, nil
+}
+
 func (m ShardMapper) mapRangeAggregationExpr(expr *syntax.RangeAggregationExpr, r *downstreamRecorder) (syntax.SampleExpr, error) {
 	if hasLabelModifier(expr) {
 		// if an expr can modify labels this means multiple shards can return the same labelset.
@@ -280,6 +381,29 @@ func (m ShardMapper) mapRangeAggregationExpr(expr *syntax.RangeAggregationExpr,
 		// rate(x) -> rate(x, shard=1) ++ rate(x, shard=2)...
 		// same goes for bytes_rate and bytes_over_time
 		return m.mapSampleExpr(expr, r)
+	case syntax.OpRangeTypeQuantile:
+		// without grouping, each series is computed entirely within the shard of its stream.
+		// quantile_over_time(q, x) -> quantile_over_time(q, x, shard=1) ++ quantile_over_time(q, x, shard=2)...
+		if expr.Grouping == nil {
+			return m.mapSampleExpr(expr, r)
+		}
+		if !m.probabilistic {
+			return expr, nil
+		}
+		// quantiles of groups spanning multiple shards are estimated by merging a sketch from each shard.
+		// quantile_over_time(q, x) by (g) -> quantile(q, sketch(x, shard=1) ++ sketch(x, shard=2)...)
+		sharded, err := m.mapSampleExpr(&syntax.RangeAggregationExpr{
+			Left:      expr.Left,
+			Operation: syntax.OpRangeTypeQuantileSketch,
+			Grouping:  expr.Grouping,
+		}, r)
+		if err != nil {
+			return nil, err
+		}
+		return &QuantileSketchEvalExpr{
+			SampleExpr: sharded,
+			quantile:   *expr.Params,
+		}, nil
 	default:
 		return expr, nil
 	}
@@ -293,7 +417,8 @@ func hasLabelModifier(expr *syntax.RangeAggregationExpr) bool {
 		return false
 	case *syntax.PipelineExpr:
 		for _, p := range ex.MultiStages {
-			if _, ok := p.(*syntax.LabelFmtExpr); ok {
+			switch p.(type) {
+			case *syntax.LabelFmtExpr, *syntax.DropLabelsExpr, *syntax.KeepLabelsExpr:
 				return true
 			}
 		}
@@ -7,6 +7,7 @@ import (
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/loki/pkg/logql/log"
 	"github.com/grafana/loki/pkg/logql/syntax"
 	"github.com/grafana/loki/pkg/logqlmodel"
 	"github.com/grafana/loki/pkg/querier/astmapper"
@@ -51,7 +52,7 @@ func TestShardedStringer(t *testing.T) {
 }
 
 func TestMapSampleExpr(t *testing.T) {
-	m := NewShardMapper(ConstantShards(2), nilShardMetrics)
+	m := NewShardMapper(ConstantShards(2), nilShardMetrics, false)
 
 	for _, tc := range []struct {
 		in  syntax.SampleExpr
@@ -113,7 +114,7 @@ func TestMapSampleExpr(t *testing.T) {
 }
 
 func TestMappingStrings(t *testing.T) {
-	m := NewShardMapper(ConstantShards(2), nilShardMetrics)
+	m := NewShardMapper(ConstantShards(2), nilShardMetrics, false)
 	for _, tc := range []struct {
 		in  string
 		out string
@@ -263,6 +264,37 @@ func TestMappingStrings(t *testing.T) {
 			in:  `avg_over_time({job=~"myapps.*"} |= "stats" | json busy="utilization" | unwrap busy [5m])`,
 			out: `avg_over_time({job=~"myapps.*"} |= "stats" | json busy="utilization" | unwrap busy [5m])`,
 		},
+		{
+			in: `sort_desc(sum by (foo) (rate({foo="bar"}[5m])))`,
+			out: `sort_desc(sum by (foo) (
+				downstream<sum by (foo) (rate({foo="bar"}[5m])), shard=0_of_2>
+				++ downstream<sum by (foo) (rate({foo="bar"}[5m])), shard=1_of_2>
+			))`,
+		},
+		{
+			in: `count_values("value", rate({foo="bar"}[5m]))`,
+			out: `count_values("value",
+				downstream<rate({foo="bar"}[5m]), shard=0_of_2>
+				++ downstream<rate({foo="bar"}[5m]), shard=1_of_2>
+			)`,
+		},
+		// dropping labels can merge series from different shards
+		{
+			in:  `rate({foo="bar"} | drop pod [5m])`,
+			out: `rate({foo="bar"} | drop pod [5m])`,
+		},
+		{
+			in:  `count_over_time({foo="bar"} | keep app [5m])`,
+			out: `count_over_time({foo="bar"} | keep app [5m])`,
+		},
+		// subqueries are not sharded themselves, but their inner expression is
+		{
+			in: `max_over_time(sum(rate({foo="bar"}[5m]))[1h:1m])`,
+			out: `max_over_time(sum(
+				downstream<sum(rate({foo="bar"}[5m])), shard=0_of_2>
+				++ downstream<sum(rate({foo="bar"}[5m])), shard=1_of_2>
+			)[1h:1m])`,
+		},
 		// should be noop if VectorExpr
 		{
 			in:  `vector(0)`,
@@ -281,8 +313,112 @@ func TestMappingStrings(t *testing.T) {
 	}
 }
 
+func TestMappingStrings_Probabilistic(t *testing.T) {
+	for _, tc := range []struct {
+		in            string
+		probabilistic bool
+		out           string
+	}{
+		{
+			in: `quantile_over_time(0.99, {foo="bar"} | unwrap latency [1m])`,
+			out: `downstream<quantile_over_time(0.99,{foo="bar"} | unwrap latency [1m]), shard=0_of_2>
+					++ downstream<quantile_over_time(0.99,{foo="bar"} | unwrap latency [1m]), shard=1_of_2>`,
+		},
+		{
+			in:  `quantile_over_time(0.99, {foo="bar"} | unwrap latency [1m]) by (cluster)`,
+			out: `quantile_over_time(0.99,{foo="bar"} | unwrap latency [1m]) by (cluster)`,
+		},
+		{
+			in:            `quantile_over_time(0.99, {foo="bar"} | unwrap latency [1m]) by (cluster)`,
+			probabilistic: true,
+			out: `quantileSketchEval<
+				downstream<__quantile_sketch_over_time__({foo="bar"} | unwrap latency [1m]) by (cluster), shard=0_of_2>
+				++ downstream<__quantile_sketch_over_time__({foo="bar"} | unwrap latency [1m]) by (cluster), shard=1_of_2>,
+				quantile=0.99>`,
+		},
+		{
+			in:            `max(quantile_over_time(0.5, {foo="bar"} | json | unwrap latency [1m]) without (pod))`,
+			probabilistic: true,
+			out: `max(quantileSketchEval<
+				downstream<__quantile_sketch_over_time__({foo="bar"} | json | unwrap latency [1m]) without (pod), shard=0_of_2>
+				++ downstream<__quantile_sketch_over_time__({foo="bar"} | json | unwrap latency [1m]) without (pod), shard=1_of_2>,
+				quantile=0.5>)`,
+		},
+		{
+			// label modifiers prevent sharding.
+			in:            `quantile_over_time(0.99, {foo="bar"} | label_format foo=bar | unwrap latency [1m]) by (cluster)`,
+			probabilistic: true,
+			out:           `quantile_over_time(0.99,{foo="bar"} | label_format foo=bar | unwrap latency [1m]) by (cluster)`,
+		},
+		{
+			in: `topk(10, sum by (user) (count_over_time({foo="bar"} | json [1m])))`,
+			out: `topk(10, sum by (user) (
+				downstream<sum by (user) (count_over_time({foo="bar"} | json [1m])), shard=0_of_2>
+				++ downstream<sum by (user) (count_over_time({foo="bar"} | json [1m])), shard=1_of_2>))`,
+		},
+		{
+			in:            `topk(10, sum by (user) (count_over_time({foo="bar"} | json [1m])))`,
+			probabilistic: true,
+			out: `topk(10, countMinSketchEval<
+				downstream<__count_min_sketch__(10, sum by (user) (count_over_time({foo="bar"} | json [1m]))), shard=0_of_2>
+				++ downstream<__count_min_sketch__(10, sum by (user) (count_over_time({foo="bar"} | json [1m]))), shard=1_of_2>>)`,
+		},
+		{
+			in:            `topk(5, sum by (user, path) (rate({foo="bar"} | json [1m]))) by (path)`,
+			probabilistic: true,
+			out: `topk by (path) (5, countMinSketchEval<
+				downstream<__count_min_sketch__ by (path) (5, sum by (user, path) (rate({foo="bar"} | json [1m]))), shard=0_of_2>
+				++ downstream<__count_min_sketch__ by (path) (5, sum by (user, path) (rate({foo="bar"} | json [1m]))), shard=1_of_2>>)`,
+		},
+		{
+			// only sums of log range aggregations are estimated.
+			in:            `topk(10, max by (user) (max_over_time({foo="bar"} | json | unwrap latency [1m])))`,
+			probabilistic: true,
+			out:           `topk(10, max by (user) (max_over_time({foo="bar"} | json | unwrap latency [1m])))`,
+		},
+		{
+			in: `count(sum by (user) (count_over_time({foo="bar"} | json [1m])))`,
+			out: `count(sum by (user) (
+				downstream<sum by (user) (count_over_time({foo="bar"} | json [1m])), shard=0_of_2>
+				++ downstream<sum by (user) (count_over_time({foo="bar"} | json [1m])), shard=1_of_2>))`,
+		},
+		{
+			in:            `count(sum by (user) (count_over_time({foo="bar"} | json [1m])))`,
+			probabilistic: true,
+			out: `hyperLogLogEval<
+				downstream<__hyperloglog__(sum by (user) (count_over_time({foo="bar"} | json [1m]))), shard=0_of_2>
+				++ downstream<__hyperloglog__(sum by (user) (count_over_time({foo="bar"} | json [1m]))), shard=1_of_2>>`,
+		},
+		{
+			in:            `count by (cluster) (rate({foo="bar"} | logfmt [1m]))`,
+			probabilistic: true,
+			out: `hyperLogLogEval<
+				downstream<__hyperloglog__ by (cluster) (rate({foo="bar"} | logfmt [1m])), shard=0_of_2>
+				++ downstream<__hyperloglog__ by (cluster) (rate({foo="bar"} | logfmt [1m])), shard=1_of_2>>`,
+		},
+		{
+			// count is sharded exactly when labels are not mutated.
+			in:            `count(rate({foo="bar"}[1m]))`,
+			probabilistic: true,
+			out: `sum(downstream<count(rate({foo="bar"}[1m])), shard=0_of_2>
+				++ downstream<count(rate({foo="bar"}[1m])), shard=1_of_2>)`,
+		},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			m := NewShardMapper(ConstantShards(2), nilShardMetrics, tc.probabilistic)
+			ast, err := syntax.ParseExpr(tc.in)
+			require.Nil(t, err)
+
+			mapped, err := m.Map(ast, nilShardMetrics.downstreamRecorder())
+			require.Nil(t, err)
+
+			require.Equal(t, removeWhiteSpace(tc.out), removeWhiteSpace(mapped.String()))
+		})
+	}
+}
+
 func TestMapping(t *testing.T) {
-	m := NewShardMapper(ConstantShards(2), nilShardMetrics)
+	m := NewShardMapper(ConstantShards(2), nilShardMetrics, false)
 
 	for _, tc := range []struct {
 		in   string
@@ -329,7 +465,7 @@ func TestMapping(t *testing.T) {
 						},
 						MultiStages: syntax.MultiStageExpr{
 							&syntax.LineFilterExpr{
-								Ty:    labels.MatchEqual,
+								Ty:    log.LineMatchEqual,
 								Match: "error",
 								Op:    "",
 							},
@@ -348,7 +484,7 @@ func TestMapping(t *testing.T) {
 							},
 							MultiStages: syntax.MultiStageExpr{
 								&syntax.LineFilterExpr{
-									Ty:    labels.MatchEqual,
+									Ty:    log.LineMatchEqual,
 									Match: "error",
 									Op:    "",
 								},
@@ -1247,7 +1383,7 @@ func TestStringTrimming(t *testing.T) {
 		},
 	} {
 		t.Run(tc.expr, func(t *testing.T) {
-			m := NewShardMapper(ConstantShards(tc.shards), nilShardMetrics)
+			m := NewShardMapper(ConstantShards(tc.shards), nilShardMetrics, false)
 			_, mappedExpr, err := m.Parse(tc.expr)
 			require.Nil(t, err)
 			require.Equal(t, removeWhiteSpace(tc.expected), removeWhiteSpace(mappedExpr.String()))
@@ -0,0 +1,109 @@
+package sketch
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	// DefaultCountMinSketchDepth is the number of rows of the default count-min sketch.
+	DefaultCountMinSketchDepth = 4
+	// DefaultCountMinSketchWidth is the number of counters per row of the default count-min sketch.
+	DefaultCountMinSketchWidth = 2048
+)
+
+// ErrIncompatibleCountMinSketch is returned when merging count-min sketches of different dimensions.
+var ErrIncompatibleCountMinSketch = errors.New("cannot merge count-min sketches with different dimensions")
+
+// CountMinSketch estimates the sum of the values added for each key, see https://en.wikipedia.org/wiki/Count%E2%80%93min_sketch.
+//
+// Each key is mapped to one counter per row, and its estimation is the minimum of those counters.
+// Estimations are never lower than the actual sum as long as values are not negative,
+// and they exceed it by at most e/width times the total of the values with probability 1-e^-depth.
+// Sketches with the same dimensions are merged by adding up their counters.
+type CountMinSketch struct {
+	depth, width uint32
+	counters     [][]float64
+}
+
+// NewCountMinSketch creates an empty count-min sketch with the given dimensions.
+func NewCountMinSketch(depth, width uint32) (*CountMinSketch, error) {
+	if depth == 0 || width == 0 {
+		return nil, fmt.Errorf("count-min sketch dimensions must be positive, got depth %d and width %d", depth, width)
+	}
+	counters := make([][]float64, depth)
+	for i := range counters {
+		counters[i] = make([]float64, width)
+	}
+	return &CountMinSketch{
+		depth:    depth,
+		width:    width,
+		counters: counters,
+	}, nil
+}
+
+// NewDefaultCountMinSketch creates an empty count-min sketch using DefaultCountMinSketchDepth and DefaultCountMinSketchWidth.
+func NewDefaultCountMinSketch() *CountMinSketch {
+	s, _ := NewCountMinSketch(DefaultCountMinSketchDepth, DefaultCountMinSketchWidth)
+	return s
+}
+
+// Add adds the value to the sum of the key identified by its hash, which must be uniformly distributed.
+func (s *CountMinSketch) Add(hash uint64, value float64) {
+	for row := uint32(0); row < s.depth; row++ {
+		s.counters[row][s.column(hash, row)] += value
+	}
+}
+
+// Count returns the estimated sum of the key identified by its hash.
+func (s *CountMinSketch) Count(hash uint64) float64 {
+	min := s.counters[0][s.column(hash, 0)]
+	for row := uint32(1); row < s.depth; row++ {
+		if v := s.counters[row][s.column(hash, row)]; v < min {
+			min = v
+		}
+	}
+	return min
+}
+
+// AddCounter adds the value to a single counter of the sketch, it is used to rebuild a sketch from its counters.
+func (s *CountMinSketch) AddCounter(row, column uint32, value float64) error {
+	if row >= s.depth || column >= s.width {
+		return fmt.Errorf("counter %d:%d out of the count-min sketch dimensions %dx%d", row, column, s.depth, s.width)
+	}
+	s.counters[row][column] += value
+	return nil
+}
+
+// Merge adds the counters of other to the sketch.
+func (s *CountMinSketch) Merge(other *CountMinSketch) error {
+	if s.depth != other.depth || s.width != other.width {
+		return ErrIncompatibleCountMinSketch
+	}
+	for row := range s.counters {
+		for column, v := range other.counters[row] {
+			s.counters[row][column] += v
+		}
+	}
+	return nil
+}
+
+// ForEach calls f with each non zero counter of the sketch until f returns false.
+func (s *CountMinSketch) ForEach(f func(row, column uint32, value float64) bool) {
+	for row := range s.counters {
+		for column, v := range s.counters[row] {
+			if v == 0 {
+				continue
+			}
+			if !f(uint32(row), uint32(column), v) {
+				return
+			}
+		}
+	}
+}
+
+// column returns the column of the key in the row, using double hashing to derive one hash per row.
+func (s *CountMinSketch) column(hash uint64, row uint32) uint32 {
+	h1, h2 := uint32(hash), uint32(hash>>32)
+	return (h1 + row*h2) % s.width
+}
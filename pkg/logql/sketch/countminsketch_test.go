@@ -0,0 +1,71 @@
+package sketch
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountMinSketch(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	var (
+		s     = NewDefaultCountMinSketch()
+		exact = map[uint64]float64{}
+		total float64
+	)
+	keys := make([]uint64, 1000)
+	for i := range keys {
+		keys[i] = r.Uint64()
+	}
+	for i := 0; i < 100000; i++ {
+		// a few heavy hitters and a long tail.
+		key := keys[int(r.ExpFloat64()*50)%len(keys)]
+		s.Add(key, 1)
+		exact[key]++
+		total++
+	}
+	for key, v := range exact {
+		count := s.Count(key)
+		require.GreaterOrEqual(t, count, v)
+		require.LessOrEqual(t, count-v, 2.72/DefaultCountMinSketchWidth*total)
+	}
+	require.Equal(t, 0.0, NewDefaultCountMinSketch().Count(keys[0]))
+}
+
+func TestCountMinSketch_Merge(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	var (
+		whole   = NewDefaultCountMinSketch()
+		merged  = NewDefaultCountMinSketch()
+		rebuilt = NewDefaultCountMinSketch()
+		parts   = []*CountMinSketch{NewDefaultCountMinSketch(), NewDefaultCountMinSketch()}
+		keys    []uint64
+	)
+	for i := 0; i < 1000; i++ {
+		key := r.Uint64()
+		keys = append(keys, key)
+		whole.Add(key, float64(i))
+		parts[i%len(parts)].Add(key, float64(i))
+	}
+	for _, p := range parts {
+		require.NoError(t, merged.Merge(p))
+		p.ForEach(func(row, column uint32, value float64) bool {
+			require.NoError(t, rebuilt.AddCounter(row, column, value))
+			return true
+		})
+	}
+	for _, key := range keys {
+		require.Equal(t, whole.Count(key), merged.Count(key))
+		require.Equal(t, whole.Count(key), rebuilt.Count(key))
+	}
+
+	other, err := NewCountMinSketch(2, 16)
+	require.NoError(t, err)
+	require.ErrorIs(t, merged.Merge(other), ErrIncompatibleCountMinSketch)
+	require.Error(t, other.AddCounter(2, 0, 1))
+	require.Error(t, other.AddCounter(0, 16, 1))
+
+	_, err = NewCountMinSketch(0, 16)
+	require.Error(t, err)
+}
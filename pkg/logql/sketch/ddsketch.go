@@ -0,0 +1,212 @@
+package sketch
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+const (
+	// DefaultRelativeAccuracy is the relative accuracy of the quantiles estimated by the default sketch.
+	DefaultRelativeAccuracy = 0.01
+	// DefaultMaxBins is the maximum number of bins per sign kept by the default sketch.
+	DefaultMaxBins = 2048
+)
+
+// ErrIncompatibleSketch is returned when merging sketches with different accuracies.
+var ErrIncompatibleSketch = errors.New("cannot merge sketches with different relative accuracies")
+
+// DDSketch is a quantile sketch with relative error guarantees, see https://arxiv.org/abs/1908.10693.
+//
+// Values are mapped to bins of logarithmically increasing width, the quantiles returned are
+// within the relative accuracy of the exact ones as long as the maximum number of bins is not reached.
+// Past that limit the bins of the smallest magnitude are collapsed together, which preserves the accuracy of
+// the highest quantiles of positive values.
+// Since the mapping of values to bins only depends on the relative accuracy,
+// sketches are merged by adding up the counts of their bins, regardless of the values they were built from.
+//
+// NaN and infinite values are ignored.
+type DDSketch struct {
+	relativeAccuracy float64
+	gamma            float64
+	logGamma         float64
+	maxBins          int
+
+	positive store
+	negative store
+	zeros    float64
+	count    float64
+}
+
+// NewDDSketch creates an empty sketch with the given relative accuracy and maximum number of bins per sign.
+func NewDDSketch(relativeAccuracy float64, maxBins int) (*DDSketch, error) {
+	if relativeAccuracy <= 0 || relativeAccuracy >= 1 {
+		return nil, fmt.Errorf("relative accuracy must be between 0 and 1, got %v", relativeAccuracy)
+	}
+	if maxBins <= 0 {
+		return nil, fmt.Errorf("maximum number of bins must be positive, got %d", maxBins)
+	}
+	gamma := (1 + relativeAccuracy) / (1 - relativeAccuracy)
+	return &DDSketch{
+		relativeAccuracy: relativeAccuracy,
+		gamma:            gamma,
+		logGamma:         math.Log(gamma),
+		maxBins:          maxBins,
+		positive:         newStore(),
+		negative:         newStore(),
+	}, nil
+}
+
+// NewDefaultDDSketch creates an empty sketch using DefaultRelativeAccuracy and DefaultMaxBins.
+func NewDefaultDDSketch() *DDSketch {
+	s, _ := NewDDSketch(DefaultRelativeAccuracy, DefaultMaxBins)
+	return s
+}
+
+// Add adds a value to the sketch.
+func (s *DDSketch) Add(v float64) {
+	s.AddN(v, 1)
+}
+
+// AddN adds a value to the sketch with the given count.
+func (s *DDSketch) AddN(v, n float64) {
+	if n <= 0 || math.IsNaN(v) || math.IsInf(v, 0) {
+		return
+	}
+	switch {
+	case v > 0:
+		s.positive.add(s.index(v), n, s.maxBins)
+	case v < 0:
+		s.negative.add(s.index(-v), n, s.maxBins)
+	default:
+		s.zeros += n
+	}
+	s.count += n
+}
+
+// Merge adds the content of other to the sketch.
+func (s *DDSketch) Merge(other *DDSketch) error {
+	if s.gamma != other.gamma {
+		return ErrIncompatibleSketch
+	}
+	for i, n := range other.positive.bins {
+		s.positive.add(i, n, s.maxBins)
+	}
+	for i, n := range other.negative.bins {
+		s.negative.add(i, n, s.maxBins)
+	}
+	s.zeros += other.zeros
+	s.count += other.count
+	return nil
+}
+
+// Count returns the number of values added to the sketch.
+func (s *DDSketch) Count() float64 {
+	return s.count
+}
+
+// Quantile returns an estimation of the q-quantile of the values added to the sketch.
+//
+// If the sketch is empty, NaN is returned.
+// If q<0, -Inf is returned.
+// If q>1, +Inf is returned.
+func (s *DDSketch) Quantile(q float64) float64 {
+	if s.count == 0 {
+		return math.NaN()
+	}
+	if q < 0 {
+		return math.Inf(-1)
+	}
+	if q > 1 {
+		return math.Inf(+1)
+	}
+	rank := q * (s.count - 1)
+	var (
+		cumulative float64
+		result     = math.NaN()
+	)
+	s.ForEach(func(value, count float64) bool {
+		cumulative += count
+		if cumulative > rank {
+			result = value
+			return false
+		}
+		return true
+	})
+	return result
+}
+
+// ForEach calls f with the representative value and the count of each non empty bin of the sketch,
+// in increasing order of values, until f returns false.
+func (s *DDSketch) ForEach(f func(value, count float64) bool) {
+	negatives := s.negative.sortedIndexes()
+	for i := len(negatives) - 1; i >= 0; i-- {
+		if !f(-s.value(negatives[i]), s.negative.bins[negatives[i]]) {
+			return
+		}
+	}
+	if s.zeros > 0 && !f(0, s.zeros) {
+		return
+	}
+	for _, i := range s.positive.sortedIndexes() {
+		if !f(s.value(i), s.positive.bins[i]) {
+			return
+		}
+	}
+}
+
+// index returns the index of the bin holding the positive value v.
+func (s *DDSketch) index(v float64) int {
+	return int(math.Ceil(math.Log(v) / s.logGamma))
+}
+
+// value returns the representative value of the bin i, which is within the relative accuracy
+// of all the values of the bin.
+func (s *DDSketch) value(i int) float64 {
+	return math.Exp(float64(i)*s.logGamma) * 2 / (1 + s.gamma)
+}
+
+// store holds the counts of the bins of a sketch for one sign.
+type store struct {
+	bins map[int]float64
+	// once collapsed, bins below minIndex are added to minIndex.
+	collapsed bool
+	minIndex  int
+}
+
+func newStore() store {
+	return store{bins: map[int]float64{}}
+}
+
+func (st *store) add(i int, n float64, maxBins int) {
+	if st.collapsed && i < st.minIndex {
+		i = st.minIndex
+	}
+	st.bins[i] += n
+	if len(st.bins) > maxBins {
+		st.collapse(maxBins)
+	}
+}
+
+// collapse merges the lowest bins together until the store holds maxBins bins.
+func (st *store) collapse(maxBins int) {
+	indexes := st.sortedIndexes()
+	excess := len(indexes) - maxBins
+	target := indexes[excess]
+	for _, i := range indexes[:excess] {
+		st.bins[target] += st.bins[i]
+		delete(st.bins, i)
+	}
+	st.collapsed = true
+	st.minIndex = target
+}
+
+func (st *store) sortedIndexes() []int {
+	indexes := make([]int, 0, len(st.bins))
+	for i := range st.bins {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	return indexes
+}
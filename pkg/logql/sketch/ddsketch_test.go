@@ -0,0 +1,131 @@
+package sketch
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// exactQuantile returns the value of rank q*(n-1) of the sorted values.
+func exactQuantile(q float64, sorted []float64) float64 {
+	return sorted[int(math.Floor(q*float64(len(sorted)-1)))]
+}
+
+func requireRelativelyEqual(t *testing.T, expected, actual, accuracy float64) {
+	t.Helper()
+	require.InDelta(t, expected, actual, math.Abs(expected)*accuracy+1e-12, "expected %v got %v", expected, actual)
+}
+
+func TestDDSketch_Quantile(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		generate func(r *rand.Rand) float64
+	}{
+		{"uniform", func(r *rand.Rand) float64 { return r.Float64() * 1000 }},
+		{"exponential", func(r *rand.Rand) float64 { return r.ExpFloat64() }},
+		{"normal", func(r *rand.Rand) float64 { return r.NormFloat64() * 100 }},
+		{"integers", func(r *rand.Rand) float64 { return float64(r.Intn(10)) }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := rand.New(rand.NewSource(42))
+			s := NewDefaultDDSketch()
+			values := make([]float64, 0, 10000)
+			for i := 0; i < 10000; i++ {
+				v := tc.generate(r)
+				values = append(values, v)
+				s.Add(v)
+			}
+			sort.Float64s(values)
+
+			require.Equal(t, float64(len(values)), s.Count())
+			for _, q := range []float64{0, 0.1, 0.25, 0.5, 0.75, 0.9, 0.99, 0.999, 1} {
+				requireRelativelyEqual(t, exactQuantile(q, values), s.Quantile(q), DefaultRelativeAccuracy)
+			}
+		})
+	}
+}
+
+func TestDDSketch_EdgeCases(t *testing.T) {
+	s := NewDefaultDDSketch()
+	require.True(t, math.IsNaN(s.Quantile(0.5)))
+
+	s.Add(math.NaN())
+	s.Add(math.Inf(1))
+	s.Add(math.Inf(-1))
+	require.Equal(t, 0.0, s.Count())
+
+	s.Add(0)
+	s.Add(0)
+	require.Equal(t, 0.0, s.Quantile(0.5))
+	require.Equal(t, math.Inf(-1), s.Quantile(-1))
+	require.Equal(t, math.Inf(1), s.Quantile(2))
+}
+
+func TestDDSketch_Merge(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	var (
+		whole  = NewDefaultDDSketch()
+		merged = NewDefaultDDSketch()
+		parts  = []*DDSketch{NewDefaultDDSketch(), NewDefaultDDSketch(), NewDefaultDDSketch()}
+	)
+	for i := 0; i < 3000; i++ {
+		v := r.NormFloat64() * 50
+		whole.Add(v)
+		parts[i%len(parts)].Add(v)
+	}
+	for _, p := range parts {
+		require.NoError(t, merged.Merge(p))
+	}
+	for _, q := range []float64{0, 0.5, 0.9, 0.99, 1} {
+		require.Equal(t, whole.Quantile(q), merged.Quantile(q))
+	}
+
+	other, err := NewDDSketch(0.05, DefaultMaxBins)
+	require.NoError(t, err)
+	require.ErrorIs(t, merged.Merge(other), ErrIncompatibleSketch)
+}
+
+func TestDDSketch_ForEach(t *testing.T) {
+	s := NewDefaultDDSketch()
+	for _, v := range []float64{-10, -1, 0, 1, 1, 10} {
+		s.Add(v)
+	}
+	var (
+		values []float64
+		counts []float64
+	)
+	s.ForEach(func(value, count float64) bool {
+		values = append(values, value)
+		counts = append(counts, count)
+		return true
+	})
+	require.True(t, sort.Float64sAreSorted(values))
+	require.Equal(t, []float64{1, 1, 1, 2, 1}, counts)
+
+	// Rebuilding a sketch from its bins gives the same sketch.
+	rebuilt := NewDefaultDDSketch()
+	for i := range values {
+		rebuilt.AddN(values[i], counts[i])
+	}
+	require.Equal(t, s, rebuilt)
+}
+
+func TestDDSketch_Collapse(t *testing.T) {
+	s, err := NewDDSketch(DefaultRelativeAccuracy, 10)
+	require.NoError(t, err)
+	for i := 0; i < 1000; i++ {
+		s.Add(math.Pow(1.1, float64(i)))
+	}
+	require.Len(t, s.positive.bins, 10)
+	require.Equal(t, 1000.0, s.Count())
+	// the highest quantiles are still accurate.
+	requireRelativelyEqual(t, math.Pow(1.1, 999), s.Quantile(1), DefaultRelativeAccuracy)
+
+	_, err = NewDDSketch(0, 10)
+	require.Error(t, err)
+	_, err = NewDDSketch(0.01, 0)
+	require.Error(t, err)
+}
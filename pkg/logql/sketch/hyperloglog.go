@@ -0,0 +1,122 @@
+package sketch
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+// DefaultHyperLogLogPrecision is the precision of the default HyperLogLog sketch,
+// which has a standard error of about 0.8%.
+const DefaultHyperLogLogPrecision = 14
+
+// ErrIncompatibleHyperLogLog is returned when merging HyperLogLog sketches of different precisions.
+var ErrIncompatibleHyperLogLog = errors.New("cannot merge HyperLogLog sketches with different precisions")
+
+// HyperLogLog estimates the number of distinct keys added to it, see http://algo.inria.fr/flajolet/Publications/FlFuGaMe07.pdf.
+//
+// Keys are added by hash, the first bits of the hash select a register which keeps the longest run of
+// leading zeros seen in the remaining bits. The standard error of the estimation is 1.04/sqrt(2^precision).
+// Sketches with the same precision are merged by keeping the maximum of each register.
+type HyperLogLog struct {
+	precision uint8
+	registers []uint8
+}
+
+// NewHyperLogLog creates an empty HyperLogLog sketch with 2^precision registers.
+func NewHyperLogLog(precision uint8) (*HyperLogLog, error) {
+	if precision < 4 || precision > 18 {
+		return nil, fmt.Errorf("HyperLogLog precision must be between 4 and 18, got %d", precision)
+	}
+	return &HyperLogLog{
+		precision: precision,
+		registers: make([]uint8, 1<<precision),
+	}, nil
+}
+
+// NewDefaultHyperLogLog creates an empty HyperLogLog sketch using DefaultHyperLogLogPrecision.
+func NewDefaultHyperLogLog() *HyperLogLog {
+	s, _ := NewHyperLogLog(DefaultHyperLogLogPrecision)
+	return s
+}
+
+// Add adds the key identified by its hash, which must be uniformly distributed.
+func (s *HyperLogLog) Add(hash uint64) {
+	index := hash >> (64 - s.precision)
+	// the sentinel bit bounds the rank when the remaining bits are all zeros.
+	rank := uint8(bits.LeadingZeros64(hash<<s.precision|1<<(s.precision-1))) + 1
+	if rank > s.registers[index] {
+		s.registers[index] = rank
+	}
+}
+
+// SetRegister raises the register to the given value, it is used to rebuild a sketch from its registers.
+func (s *HyperLogLog) SetRegister(index uint32, value uint8) error {
+	if int(index) >= len(s.registers) {
+		return fmt.Errorf("register %d out of the HyperLogLog sketch size %d", index, len(s.registers))
+	}
+	if value > s.registers[index] {
+		s.registers[index] = value
+	}
+	return nil
+}
+
+// Merge merges the registers of other into the sketch.
+func (s *HyperLogLog) Merge(other *HyperLogLog) error {
+	if s.precision != other.precision {
+		return ErrIncompatibleHyperLogLog
+	}
+	for i, v := range other.registers {
+		if v > s.registers[i] {
+			s.registers[i] = v
+		}
+	}
+	return nil
+}
+
+// Estimate returns the estimated number of distinct keys added to the sketch.
+func (s *HyperLogLog) Estimate() float64 {
+	m := float64(len(s.registers))
+	var (
+		sum   float64
+		zeros float64
+	)
+	for _, v := range s.registers {
+		sum += 1 / float64(uint64(1)<<v)
+		if v == 0 {
+			zeros++
+		}
+	}
+	estimate := alpha(m) * m * m / sum
+	// small cardinalities are better estimated with linear counting.
+	if estimate <= 2.5*m && zeros > 0 {
+		return math.Round(m * math.Log(m/zeros))
+	}
+	return math.Round(estimate)
+}
+
+// ForEach calls f with each non zero register of the sketch until f returns false.
+func (s *HyperLogLog) ForEach(f func(index uint32, value uint8) bool) {
+	for i, v := range s.registers {
+		if v == 0 {
+			continue
+		}
+		if !f(uint32(i), v) {
+			return
+		}
+	}
+}
+
+func alpha(m float64) float64 {
+	switch m {
+	case 16:
+		return 0.673
+	case 32:
+		return 0.697
+	case 64:
+		return 0.709
+	default:
+		return 0.7213 / (1 + 1.079/m)
+	}
+}
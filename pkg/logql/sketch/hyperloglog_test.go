@@ -0,0 +1,56 @@
+package sketch
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHyperLogLog_Estimate(t *testing.T) {
+	for _, cardinality := range []int{0, 1, 10, 100, 1000, 10000, 100000, 1000000} {
+		r := rand.New(rand.NewSource(int64(cardinality)))
+		s := NewDefaultHyperLogLog()
+		for i := 0; i < cardinality; i++ {
+			key := r.Uint64()
+			// duplicates don't change the estimation.
+			s.Add(key)
+			s.Add(key)
+		}
+		require.InDelta(t, float64(cardinality), s.Estimate(), float64(cardinality)*0.03, "cardinality %d", cardinality)
+	}
+}
+
+func TestHyperLogLog_Merge(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	var (
+		whole   = NewDefaultHyperLogLog()
+		merged  = NewDefaultHyperLogLog()
+		rebuilt = NewDefaultHyperLogLog()
+		parts   = []*HyperLogLog{NewDefaultHyperLogLog(), NewDefaultHyperLogLog(), NewDefaultHyperLogLog()}
+	)
+	for i := 0; i < 50000; i++ {
+		key := r.Uint64()
+		whole.Add(key)
+		// keys are spread over overlapping parts.
+		parts[i%len(parts)].Add(key)
+		parts[(i+1)%len(parts)].Add(key)
+	}
+	for _, p := range parts {
+		require.NoError(t, merged.Merge(p))
+		p.ForEach(func(index uint32, value uint8) bool {
+			require.NoError(t, rebuilt.SetRegister(index, value))
+			return true
+		})
+	}
+	require.Equal(t, whole, merged)
+	require.Equal(t, whole, rebuilt)
+
+	other, err := NewHyperLogLog(4)
+	require.NoError(t, err)
+	require.ErrorIs(t, merged.Merge(other), ErrIncompatibleHyperLogLog)
+	require.Error(t, other.SetRegister(16, 1))
+
+	_, err = NewHyperLogLog(3)
+	require.Error(t, err)
+}
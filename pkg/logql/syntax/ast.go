@@ -212,13 +212,13 @@ func (e *PipelineExpr) HasFilter() bool {
 
 type LineFilterExpr struct {
 	Left  *LineFilterExpr
-	Ty    labels.MatchType
+	Ty    log.LineMatchType
 	Match string
 	Op    string
 	implicit
 }
 
-func newLineFilterExpr(ty labels.MatchType, op, match string) *LineFilterExpr {
+func newLineFilterExpr(ty log.LineMatchType, op, match string) *LineFilterExpr {
 	return &LineFilterExpr{
 		Ty:    ty,
 		Match: match,
@@ -244,7 +244,7 @@ func (e *LineFilterExpr) Walk(f WalkFn) {
 }
 
 // AddFilterExpr adds a filter expression to a logselector expression.
-func AddFilterExpr(expr LogSelectorExpr, ty labels.MatchType, op, match string) (LogSelectorExpr, error) {
+func AddFilterExpr(expr LogSelectorExpr, ty log.LineMatchType, op, match string) (LogSelectorExpr, error) {
 	filter := newLineFilterExpr(ty, op, match)
 	switch e := expr.(type) {
 	case *MatchersExpr:
@@ -265,16 +265,7 @@ func (e *LineFilterExpr) String() string {
 		sb.WriteString(e.Left.String())
 		sb.WriteString(" ")
 	}
-	switch e.Ty {
-	case labels.MatchRegexp:
-		sb.WriteString("|~")
-	case labels.MatchNotRegexp:
-		sb.WriteString("!~")
-	case labels.MatchEqual:
-		sb.WriteString("|=")
-	case labels.MatchNotEqual:
-		sb.WriteString("!=")
-	}
+	sb.WriteString(e.Ty.String())
 	sb.WriteString(" ")
 	if e.Op == "" {
 		sb.WriteString(strconv.Quote(e.Match))
@@ -496,6 +487,72 @@ func (e *LabelFmtExpr) String() string {
 	return sb.String()
 }
 
+type DropLabelsExpr struct {
+	dropLabels []log.DropLabel
+	implicit
+}
+
+func newDropLabelsExpr(dropLabels []log.DropLabel) *DropLabelsExpr {
+	return &DropLabelsExpr{dropLabels: dropLabels}
+}
+
+func (e *DropLabelsExpr) Shardable() bool { return true }
+
+func (e *DropLabelsExpr) Stage() (log.Stage, error) {
+	return log.NewDropLabels(e.dropLabels), nil
+}
+
+func (e *DropLabelsExpr) String() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s %s ", OpPipe, OpDrop))
+	for i, dropLabel := range e.dropLabels {
+		if dropLabel.Matcher != nil {
+			sb.WriteString(dropLabel.Matcher.String())
+		} else {
+			sb.WriteString(dropLabel.Name)
+		}
+		if i+1 != len(e.dropLabels) {
+			sb.WriteString(",")
+		}
+	}
+	return sb.String()
+}
+
+func (e *DropLabelsExpr) Walk(f WalkFn) { f(e) }
+
+type KeepLabelsExpr struct {
+	keepLabels []log.KeepLabel
+	implicit
+}
+
+func newKeepLabelsExpr(keepLabels []log.KeepLabel) *KeepLabelsExpr {
+	return &KeepLabelsExpr{keepLabels: keepLabels}
+}
+
+func (e *KeepLabelsExpr) Shardable() bool { return true }
+
+func (e *KeepLabelsExpr) Stage() (log.Stage, error) {
+	return log.NewKeepLabels(e.keepLabels), nil
+}
+
+func (e *KeepLabelsExpr) String() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s %s ", OpPipe, OpKeep))
+	for i, keepLabel := range e.keepLabels {
+		if keepLabel.Matcher != nil {
+			sb.WriteString(keepLabel.Matcher.String())
+		} else {
+			sb.WriteString(keepLabel.Name)
+		}
+		if i+1 != len(e.keepLabels) {
+			sb.WriteString(",")
+		}
+	}
+	return sb.String()
+}
+
+func (e *KeepLabelsExpr) Walk(f WalkFn) { f(e) }
+
 type JSONExpressionParser struct {
 	Expressions []log.JSONExpression
 
@@ -642,15 +699,24 @@ func newOffsetExpr(offset time.Duration) *OffsetExpr {
 
 const (
 	// vector ops
-	OpTypeSum     = "sum"
-	OpTypeAvg     = "avg"
-	OpTypeMax     = "max"
-	OpTypeMin     = "min"
-	OpTypeCount   = "count"
-	OpTypeStddev  = "stddev"
-	OpTypeStdvar  = "stdvar"
-	OpTypeBottomK = "bottomk"
-	OpTypeTopK    = "topk"
+	OpTypeSum         = "sum"
+	OpTypeAvg         = "avg"
+	OpTypeMax         = "max"
+	OpTypeMin         = "min"
+	OpTypeCount       = "count"
+	OpTypeStddev      = "stddev"
+	OpTypeStdvar      = "stdvar"
+	OpTypeBottomK     = "bottomk"
+	OpTypeTopK        = "topk"
+	OpTypeSort        = "sort"
+	OpTypeSortDesc    = "sort_desc"
+	OpTypeCountValues = "count_values"
+
+	// internal vector ops, only used between the frontend and the queriers.
+	// OpTypeCountMinSketch returns the counters of a count-min sketch of the vector and its top k series.
+	OpTypeCountMinSketch = "__count_min_sketch__"
+	// OpTypeHyperLogLog returns the registers of a HyperLogLog sketch of the series of the vector.
+	OpTypeHyperLogLog = "__hyperloglog__"
 
 	// range vector ops
 	OpRangeTypeCount       = "count_over_time"
@@ -669,6 +735,10 @@ const (
 	OpRangeTypeLast        = "last_over_time"
 	OpRangeTypeAbsent      = "absent_over_time"
 
+	// internal range vector ops, only used between the frontend and the queriers.
+	// OpRangeTypeQuantileSketch returns the bins of a quantile sketch of the unwrapped values.
+	OpRangeTypeQuantileSketch = "__quantile_sketch_over_time__"
+
 	//vector
 	OpTypeVector = "vector"
 
@@ -703,6 +773,8 @@ const (
 	OpFmtLine    = "line_format"
 	OpFmtLabel   = "label_format"
 	OpDecolorize = "decolorize"
+	OpDrop       = "drop"
+	OpKeep       = "keep"
 
 	OpPipe   = "|"
 	OpUnwrap = "unwrap"
@@ -813,7 +885,8 @@ func (e *RangeAggregationExpr) MatcherGroups() []MatcherRange {
 func (e RangeAggregationExpr) validate() error {
 	if e.Grouping != nil {
 		switch e.Operation {
-		case OpRangeTypeAvg, OpRangeTypeStddev, OpRangeTypeStdvar, OpRangeTypeQuantile, OpRangeTypeMax, OpRangeTypeMin, OpRangeTypeFirst, OpRangeTypeLast:
+		case OpRangeTypeAvg, OpRangeTypeStddev, OpRangeTypeStdvar, OpRangeTypeQuantile, OpRangeTypeMax, OpRangeTypeMin, OpRangeTypeFirst, OpRangeTypeLast,
+			OpRangeTypeQuantileSketch:
 		default:
 			return fmt.Errorf("grouping not allowed for %s aggregation", e.Operation)
 		}
@@ -822,7 +895,7 @@ func (e RangeAggregationExpr) validate() error {
 		switch e.Operation {
 		case OpRangeTypeAvg, OpRangeTypeSum, OpRangeTypeMax, OpRangeTypeMin, OpRangeTypeStddev,
 			OpRangeTypeStdvar, OpRangeTypeQuantile, OpRangeTypeRate, OpRangeTypeRateCounter,
-			OpRangeTypeAbsent, OpRangeTypeFirst, OpRangeTypeLast:
+			OpRangeTypeAbsent, OpRangeTypeFirst, OpRangeTypeLast, OpRangeTypeQuantileSketch:
 			return nil
 		default:
 			return fmt.Errorf("invalid aggregation %s with unwrap", e.Operation)
@@ -870,6 +943,154 @@ func (e *RangeAggregationExpr) Walk(f WalkFn) {
 	e.Left.Walk(f)
 }
 
+// SubqueryExpr is a range aggregation over the samples of a metric expression
+// evaluated at a fixed resolution, e.g. `max_over_time(rate({app="foo"}[1m])[1h:1m])`.
+type SubqueryExpr struct {
+	Left SampleExpr
+	// Range is the range of the subquery and Step its resolution.
+	// A zero Step means the step of the outer query is used.
+	Range  time.Duration
+	Step   time.Duration
+	Offset time.Duration
+
+	Operation string
+	Params    *float64
+	Grouping  *Grouping
+	implicit
+}
+
+// SubqueryRange holds the `[range:step]` part of a subquery.
+type SubqueryRange struct {
+	Range, Step time.Duration
+}
+
+func newSubqueryExpr(left SampleExpr, r SubqueryRange, o *OffsetExpr) *SubqueryExpr {
+	var offset time.Duration
+	if o != nil {
+		offset = o.Offset
+	}
+	return &SubqueryExpr{
+		Left:   left,
+		Range:  r.Range,
+		Step:   r.Step,
+		Offset: offset,
+	}
+}
+
+func newSubqueryAggregationExpr(e *SubqueryExpr, operation string, gr *Grouping, stringParams *string) SampleExpr {
+	if stringParams != nil {
+		if operation != OpRangeTypeQuantile {
+			panic(logqlmodel.NewParseError(fmt.Sprintf("parameter %s not supported for operation %s", *stringParams, operation), 0, 0))
+		}
+		params, err := strconv.ParseFloat(*stringParams, 64)
+		if err != nil {
+			panic(logqlmodel.NewParseError(fmt.Sprintf("invalid parameter for operation %s: %s", operation, err), 0, 0))
+		}
+		e.Params = &params
+	} else if operation == OpRangeTypeQuantile {
+		panic(logqlmodel.NewParseError(fmt.Sprintf("parameter required for operation %s", operation), 0, 0))
+	}
+	e.Operation = operation
+	e.Grouping = gr
+	if err := e.validate(); err != nil {
+		panic(logqlmodel.NewParseError(err.Error(), 0, 0))
+	}
+	return e
+}
+
+func (e SubqueryExpr) validate() error {
+	if e.Range <= 0 {
+		return fmt.Errorf("invalid subquery range %s", e.Range)
+	}
+	if e.Step < 0 {
+		return fmt.Errorf("invalid subquery step %s", e.Step)
+	}
+	if e.Grouping != nil {
+		switch e.Operation {
+		case OpRangeTypeAvg, OpRangeTypeStddev, OpRangeTypeStdvar, OpRangeTypeQuantile, OpRangeTypeMax, OpRangeTypeMin, OpRangeTypeFirst, OpRangeTypeLast:
+		default:
+			return fmt.Errorf("grouping not allowed for %s aggregation", e.Operation)
+		}
+	}
+	switch e.Operation {
+	case OpRangeTypeAvg, OpRangeTypeSum, OpRangeTypeMax, OpRangeTypeMin, OpRangeTypeStddev, OpRangeTypeStdvar,
+		OpRangeTypeQuantile, OpRangeTypeRateCounter, OpRangeTypeCount, OpRangeTypeAbsent, OpRangeTypeFirst, OpRangeTypeLast:
+		return nil
+	default:
+		return fmt.Errorf("invalid aggregation %s over subquery", e.Operation)
+	}
+}
+
+func (e SubqueryExpr) Validate() error {
+	return e.validate()
+}
+
+func (e *SubqueryExpr) Selector() LogSelectorExpr {
+	return e.Left.Selector()
+}
+
+// Extractor is not supported on subqueries: they are always evaluated
+// by the engine from the samples of their inner expression.
+func (e *SubqueryExpr) Extractor() (SampleExtractor, error) {
+	return nil, fmt.Errorf("subquery cannot be used as a sample extractor: %s", e)
+}
+
+func (e *SubqueryExpr) MatcherGroups() []MatcherRange {
+	groups := e.Left.MatcherGroups()
+	for i := range groups {
+		groups[i].Interval += e.Range
+		groups[i].Offset += e.Offset
+	}
+	return groups
+}
+
+// RangeString returns the `[range:step] offset` part of the subquery.
+func (e *SubqueryExpr) RangeString() string {
+	var sb strings.Builder
+	sb.WriteString("[")
+	sb.WriteString(model.Duration(e.Range).String())
+	sb.WriteString(":")
+	if e.Step != 0 {
+		sb.WriteString(model.Duration(e.Step).String())
+	}
+	sb.WriteString("]")
+	if e.Offset != 0 {
+		offsetExpr := OffsetExpr{Offset: e.Offset}
+		sb.WriteString(offsetExpr.String())
+	}
+	return sb.String()
+}
+
+// impls Stringer
+func (e *SubqueryExpr) String() string {
+	var sb strings.Builder
+	sb.WriteString(e.Operation)
+	sb.WriteString("(")
+	if e.Params != nil {
+		sb.WriteString(strconv.FormatFloat(*e.Params, 'f', -1, 64))
+		sb.WriteString(",")
+	}
+	sb.WriteString(e.Left.String())
+	sb.WriteString(e.RangeString())
+	sb.WriteString(")")
+	if e.Grouping != nil {
+		sb.WriteString(e.Grouping.String())
+	}
+	return sb.String()
+}
+
+// Shardable returns false: the inner expression of a subquery can be sharded
+// independently but the aggregation over time needs all the inner samples.
+func (e *SubqueryExpr) Shardable() bool { return false }
+
+func (e *SubqueryExpr) Walk(f WalkFn) {
+	f(e)
+	if e.Left == nil {
+		return
+	}
+	e.Left.Walk(f)
+}
+
 type Grouping struct {
 	Groups  []string
 	Without bool
@@ -899,6 +1120,8 @@ type VectorAggregationExpr struct {
 	Grouping  *Grouping
 	Params    int
 	Operation string
+	// Label is the name of the label holding the counted values for count_values.
+	Label string
 	implicit
 }
 
@@ -906,7 +1129,7 @@ func mustNewVectorAggregationExpr(left SampleExpr, operation string, gr *Groupin
 	var p int
 	var err error
 	switch operation {
-	case OpTypeBottomK, OpTypeTopK:
+	case OpTypeBottomK, OpTypeTopK, OpTypeCountMinSketch:
 		if params == nil {
 			panic(logqlmodel.NewParseError(fmt.Sprintf("parameter required for operation %s", operation), 0, 0))
 		}
@@ -918,6 +1141,15 @@ func mustNewVectorAggregationExpr(left SampleExpr, operation string, gr *Groupin
 			panic(logqlmodel.NewParseError(fmt.Sprintf("invalid parameter (must be greater than 0) %s(%s", operation, *params), 0, 0))
 		}
 
+	case OpTypeSort, OpTypeSortDesc:
+		if params != nil {
+			panic(logqlmodel.NewParseError(fmt.Sprintf("unsupported parameter for operation %s(%s,", operation, *params), 0, 0))
+		}
+		if gr != nil && (gr.Without || len(gr.Groups) > 0) {
+			panic(logqlmodel.NewParseError(fmt.Sprintf("grouping not allowed for %s aggregation", operation), 0, 0))
+		}
+	case OpTypeCountValues:
+		panic(logqlmodel.NewParseError(fmt.Sprintf("parameter required for operation %s", operation), 0, 0))
 	default:
 		if params != nil {
 			panic(logqlmodel.NewParseError(fmt.Sprintf("unsupported parameter for operation %s(%s,", operation, *params), 0, 0))
@@ -934,6 +1166,21 @@ func mustNewVectorAggregationExpr(left SampleExpr, operation string, gr *Groupin
 	}
 }
 
+func mustNewCountValuesExpr(left SampleExpr, gr *Grouping, label string) SampleExpr {
+	if !model.LabelName(label).IsValid() {
+		panic(logqlmodel.NewParseError(fmt.Sprintf("invalid label name %q for operation %s", label, OpTypeCountValues), 0, 0))
+	}
+	if gr == nil {
+		gr = &Grouping{}
+	}
+	return &VectorAggregationExpr{
+		Left:      left,
+		Operation: OpTypeCountValues,
+		Grouping:  gr,
+		Label:     label,
+	}
+}
+
 func (e *VectorAggregationExpr) MatcherGroups() []MatcherRange {
 	return e.Left.MatcherGroups()
 }
@@ -971,8 +1218,10 @@ func (e *VectorAggregationExpr) String() string {
 	var params []string
 	switch e.Operation {
 	// bottomK and topk can have first parameter as 0
-	case OpTypeBottomK, OpTypeTopK:
+	case OpTypeBottomK, OpTypeTopK, OpTypeCountMinSketch:
 		params = []string{fmt.Sprintf("%d", e.Params), e.Left.String()}
+	case OpTypeCountValues:
+		params = []string{strconv.Quote(e.Label), e.Left.String()}
 	default:
 		if e.Params != 0 {
 			params = []string{fmt.Sprintf("%d", e.Params), e.Left.String()}
@@ -79,6 +79,9 @@ func Test_SampleExpr_String(t *testing.T) {
 		`sum(count_over_time({job="mysql"}[5m] offset 10m))`,
 		`sum(count_over_time({job="mysql"} | json [5m]))`,
 		`sum(count_over_time({job="mysql"} | json [5m] offset 10m))`,
+		`__quantile_sketch_over_time__({job="mysql"} | json | unwrap latency [5m]) by (cluster)`,
+		`__count_min_sketch__ by (name)(10, sum by (name, user) (rate({job="mysql"} | json [5m])))`,
+		`__hyperloglog__ by (cluster)(sum by (cluster, user) (count_over_time({job="mysql"} | json [5m])))`,
 		`sum(count_over_time({job="mysql"} | logfmt [5m]))`,
 		`sum(count_over_time({job="mysql"} | logfmt [5m] offset 10m))`,
 		`sum(count_over_time({job="mysql"} | pattern "<foo> bar <buzz>" | json [5m]))`,
@@ -170,6 +173,20 @@ func Test_SampleExpr_String(t *testing.T) {
 			"(.*):.*"
 		)
 		`,
+		`sum by (a) (count_over_time({app="foo"} | json | drop b, c="d", __error__ | keep a [5m]))`,
+		`{app="foo"} | logfmt | keep a, b=~"c.*"`,
+		`{app="foo"} |> "<_> foo <bar>" !> "<_> buzz"`,
+		`count_over_time({app="foo"} |= "a" |> "<_> 500 <_>" [5m])`,
+		`sort(sum by (a) (rate({app="foo"}[1m])))`,
+		`sort_desc(rate({app="foo"}[1m]))`,
+		`count_values("value", rate({app="foo"}[1m]))`,
+		`count_values without (a) ("value", rate({app="foo"}[1m]))`,
+		`max_over_time(rate({app="foo"}[1m])[1h:1m])`,
+		`avg_over_time(sum by (a) (rate({app="foo"}[1m]))[1h:])`,
+		`quantile_over_time(0.99, sum(rate({app="foo"}[1m]))[1h:5m] offset 5m) by (a)`,
+		`sum(max_over_time(count_over_time({app="foo"} |= "bar"[5m])[1d:1h]))`,
+		`count_over_time({app="foo"} |= "[a:b]" [5m])`,
+		`max_over_time(count_over_time({app="foo"} |= "[a:b]" [5m])[1h:1m])`,
 	} {
 		t.Run(tc, func(t *testing.T) {
 			expr, err := ParseExpr(tc)
@@ -197,6 +214,38 @@ func Test_SampleExpr_String_Fail(t *testing.T) {
 	}
 }
 
+func Test_VectorAggregationExpr_Fail(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []string{
+		`sort by (a) (rate({app="foo"}[1m]))`,
+		`sort_desc(2, rate({app="foo"}[1m]))`,
+		`count_values(rate({app="foo"}[1m]))`,
+		`count_values(2, rate({app="foo"}[1m]))`,
+		`count_values("not-a-label", rate({app="foo"}[1m]))`,
+		`sum("value", rate({app="foo"}[1m]))`,
+	} {
+		t.Run(tc, func(t *testing.T) {
+			_, err := ParseExpr(tc)
+			require.Error(t, err)
+		})
+	}
+}
+
+func Test_SubqueryExpr_Fail(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []string{
+		`rate(sum(rate({app="foo"}[1m]))[1h:1m])`,
+		`bytes_over_time(sum(rate({app="foo"}[1m]))[1h:1m])`,
+		`sum_over_time(sum(rate({app="foo"}[1m]))[1h:1m]) by (a)`,
+		`max_over_time(sum(rate({app="foo"}[1m]))[0s:1m])`,
+	} {
+		t.Run(tc, func(t *testing.T) {
+			_, err := ParseExpr(tc)
+			require.Error(t, err)
+		})
+	}
+}
+
 func TestMatcherGroups(t *testing.T) {
 	for i, tc := range []struct {
 		query string
@@ -230,6 +279,18 @@ func TestMatcherGroups(t *testing.T) {
 				},
 			},
 		},
+		{
+			query: `max_over_time(count_over_time({job="foo"}[5m] offset 1m)[1h:1m] offset 10m)`,
+			exp: []MatcherRange{
+				{
+					Interval: time.Hour + 5*time.Minute,
+					Offset:   11 * time.Minute,
+					Matchers: []*labels.Matcher{
+						labels.MustNewMatcher(labels.MatchEqual, "job", "foo"),
+					},
+				},
+			},
+		},
 	} {
 		t.Run(fmt.Sprint(i), func(t *testing.T) {
 			expr, err := ParseExpr(tc.query)
@@ -307,6 +368,13 @@ func Test_FilterMatcher(t *testing.T) {
 			},
 			[]linecheck{{"foobuzz", true}, {"bar", false}},
 		},
+		{
+			`{app="foo"} |> "foo <_>" !> "<_> bar"`,
+			[]*labels.Matcher{
+				mustNewMatcher(labels.MatchEqual, "app", "foo"),
+			},
+			[]linecheck{{"foo buzz", true}, {"foo bar", false}, {"buzz foo", false}},
+		},
 		{
 			`{app="foo"} |= "foo" !~ "f.*b"`,
 			[]*labels.Matcher{
@@ -11,7 +11,7 @@ import (
 
 %union{
   Expr                    Expr
-  Filter                  labels.MatchType
+  Filter                  log.LineMatchType
   Grouping                *Grouping
   Labels                  []string
   LogExpr                 LogSelectorExpr
@@ -58,7 +58,15 @@ import (
   JSONExpressionList      []log.JSONExpression
   UnwrapExpr              *UnwrapExpr
   DecolorizeExpr          *DecolorizeExpr
+  DropLabel               log.DropLabel
+  DropLabels              []log.DropLabel
+  DropLabelsExpr          *DropLabelsExpr
+  KeepLabel               log.KeepLabel
+  KeepLabels              []log.KeepLabel
+  KeepLabelsExpr          *KeepLabelsExpr
   OffsetExpr              *OffsetExpr
+  SubqueryExpr            *SubqueryExpr
+  subqueryRange           SubqueryRange
 }
 
 %start root
@@ -108,16 +116,25 @@ import (
 %type <UnitFilter>            unitFilter
 %type <IPLabelFilter>         ipLabelFilter
 %type <OffsetExpr>            offsetExpr
+%type <SubqueryExpr>          subqueryExpr
+%type <DropLabel>             dropLabel
+%type <DropLabels>            dropLabels
+%type <DropLabelsExpr>        dropLabelsExpr
+%type <KeepLabel>             keepLabel
+%type <KeepLabels>            keepLabels
+%type <KeepLabelsExpr>        keepLabelsExpr
 
 %token <bytes> BYTES
 %token <str>      IDENTIFIER STRING NUMBER
 %token <duration> DURATION RANGE
-%token <val>      MATCHERS LABELS EQ RE NRE OPEN_BRACE CLOSE_BRACE OPEN_BRACKET CLOSE_BRACKET COMMA DOT PIPE_MATCH PIPE_EXACT
+%token <subqueryRange> SUBQUERY_RANGE
+%token <str>      SUBQUERY_RANGE_OP
+%token <val>      MATCHERS LABELS EQ RE NRE OPEN_BRACE CLOSE_BRACE OPEN_BRACKET CLOSE_BRACKET COMMA DOT PIPE_MATCH PIPE_EXACT PIPE_PATTERN NPA
                   OPEN_PARENTHESIS CLOSE_PARENTHESIS BY WITHOUT COUNT_OVER_TIME RATE RATE_COUNTER SUM AVG MAX MIN COUNT STDDEV STDVAR BOTTOMK TOPK
                   BYTES_OVER_TIME BYTES_RATE BOOL JSON REGEXP LOGFMT PIPE LINE_FMT LABEL_FMT UNWRAP AVG_OVER_TIME SUM_OVER_TIME MIN_OVER_TIME
                   MAX_OVER_TIME STDVAR_OVER_TIME STDDEV_OVER_TIME QUANTILE_OVER_TIME BYTES_CONV DURATION_CONV DURATION_SECONDS_CONV
-                  FIRST_OVER_TIME LAST_OVER_TIME ABSENT_OVER_TIME VECTOR LABEL_REPLACE UNPACK OFFSET PATTERN IP ON IGNORING GROUP_LEFT GROUP_RIGHT
-                  DECOLORIZE
+                  FIRST_OVER_TIME LAST_OVER_TIME ABSENT_OVER_TIME QUANTILE_SKETCH_OVER_TIME COUNT_MIN_SKETCH HYPERLOGLOG VECTOR LABEL_REPLACE UNPACK OFFSET PATTERN IP ON IGNORING GROUP_LEFT GROUP_RIGHT
+                  DECOLORIZE DROP KEEP SORT SORT_DESC COUNT_VALUES
 
 // Operators are listed with increasing precedence.
 %left <binOp> OR
@@ -198,6 +215,15 @@ rangeAggregationExpr:
     | rangeOp OPEN_PARENTHESIS NUMBER COMMA logRangeExpr CLOSE_PARENTHESIS           { $$ = newRangeAggregationExpr($5, $1, nil, &$3) }
     | rangeOp OPEN_PARENTHESIS logRangeExpr CLOSE_PARENTHESIS grouping               { $$ = newRangeAggregationExpr($3, $1, $5, nil) }
     | rangeOp OPEN_PARENTHESIS NUMBER COMMA logRangeExpr CLOSE_PARENTHESIS grouping  { $$ = newRangeAggregationExpr($5, $1, $7, &$3) }
+    | SUBQUERY_RANGE_OP OPEN_PARENTHESIS subqueryExpr CLOSE_PARENTHESIS                        { $$ = newSubqueryAggregationExpr($3, $1, nil, nil) }
+    | SUBQUERY_RANGE_OP OPEN_PARENTHESIS NUMBER COMMA subqueryExpr CLOSE_PARENTHESIS           { $$ = newSubqueryAggregationExpr($5, $1, nil, &$3) }
+    | SUBQUERY_RANGE_OP OPEN_PARENTHESIS subqueryExpr CLOSE_PARENTHESIS grouping               { $$ = newSubqueryAggregationExpr($3, $1, $5, nil) }
+    | SUBQUERY_RANGE_OP OPEN_PARENTHESIS NUMBER COMMA subqueryExpr CLOSE_PARENTHESIS grouping  { $$ = newSubqueryAggregationExpr($5, $1, $7, &$3) }
+    ;
+
+subqueryExpr:
+      metricExpr SUBQUERY_RANGE             { $$ = newSubqueryExpr($1, $2, nil) }
+    | metricExpr SUBQUERY_RANGE offsetExpr  { $$ = newSubqueryExpr($1, $2, $3) }
     ;
 
 vectorAggregationExpr:
@@ -209,6 +235,10 @@ vectorAggregationExpr:
     | vectorOp OPEN_PARENTHESIS NUMBER COMMA metricExpr CLOSE_PARENTHESIS                 { $$ = mustNewVectorAggregationExpr($5, $1, nil, &$3) }
     | vectorOp OPEN_PARENTHESIS NUMBER COMMA metricExpr CLOSE_PARENTHESIS grouping        { $$ = mustNewVectorAggregationExpr($5, $1, $7, &$3) }
     | vectorOp grouping OPEN_PARENTHESIS NUMBER COMMA metricExpr CLOSE_PARENTHESIS        { $$ = mustNewVectorAggregationExpr($6, $1, $2, &$4) }
+    // count_values takes a label name as first argument.
+    | COUNT_VALUES OPEN_PARENTHESIS STRING COMMA metricExpr CLOSE_PARENTHESIS                 { $$ = mustNewCountValuesExpr($5, nil, $3) }
+    | COUNT_VALUES OPEN_PARENTHESIS STRING COMMA metricExpr CLOSE_PARENTHESIS grouping        { $$ = mustNewCountValuesExpr($5, $7, $3) }
+    | COUNT_VALUES grouping OPEN_PARENTHESIS STRING COMMA metricExpr CLOSE_PARENTHESIS        { $$ = mustNewCountValuesExpr($6, $2, $4) }
     ;
 
 labelReplaceExpr:
@@ -217,10 +247,12 @@ labelReplaceExpr:
     ;
 
 filter:
-      PIPE_MATCH                       { $$ = labels.MatchRegexp }
-    | PIPE_EXACT                       { $$ = labels.MatchEqual }
-    | NRE                              { $$ = labels.MatchNotRegexp }
-    | NEQ                              { $$ = labels.MatchNotEqual }
+      PIPE_MATCH                       { $$ = log.LineMatchRegexp }
+    | PIPE_EXACT                       { $$ = log.LineMatchEqual }
+    | NRE                              { $$ = log.LineMatchNotRegexp }
+    | NEQ                              { $$ = log.LineMatchNotEqual }
+    | PIPE_PATTERN                     { $$ = log.LineMatchPattern }
+    | NPA                              { $$ = log.LineMatchNotPattern }
     ;
 
 selector:
@@ -254,6 +286,8 @@ pipelineStage:
   | PIPE lineFormatExpr          { $$ = $2 }
   | PIPE decolorizeExpr          { $$ = $2 }
   | PIPE labelFormatExpr         { $$ = $2 }
+  | PIPE dropLabelsExpr          { $$ = $2 }
+  | PIPE keepLabelsExpr          { $$ = $2 }
   ;
 
 filterOp:
@@ -319,6 +353,30 @@ jsonExpressionList:
   | jsonExpressionList COMMA jsonExpression { $$ = append($1, $3) }
   ;
 
+dropLabel:
+    IDENTIFIER { $$ = log.NewDropLabel(nil, $1) }
+  | matcher    { $$ = log.NewDropLabel($1, "") }
+  ;
+
+dropLabels:
+    dropLabel                  { $$ = []log.DropLabel{$1} }
+  | dropLabels COMMA dropLabel { $$ = append($1, $3) }
+  ;
+
+dropLabelsExpr: DROP dropLabels { $$ = newDropLabelsExpr($2) };
+
+keepLabel:
+    IDENTIFIER { $$ = log.NewKeepLabel(nil, $1) }
+  | matcher    { $$ = log.NewKeepLabel($1, "") }
+  ;
+
+keepLabels:
+    keepLabel                  { $$ = []log.KeepLabel{$1} }
+  | keepLabels COMMA keepLabel { $$ = append($1, $3) }
+  ;
+
+keepLabelsExpr: KEEP keepLabels { $$ = newKeepLabelsExpr($2) };
+
 ipLabelFilter:
     IDENTIFIER EQ IP OPEN_PARENTHESIS STRING CLOSE_PARENTHESIS { $$ = log.NewIPLabelFilter($5, $1,log.LabelFilterEqual) }
   | IDENTIFIER NEQ IP OPEN_PARENTHESIS STRING CLOSE_PARENTHESIS { $$ = log.NewIPLabelFilter($5, $1, log.LabelFilterNotEqual) }
@@ -470,6 +528,10 @@ vectorOp:
       | STDVAR  { $$ = OpTypeStdvar }
       | BOTTOMK { $$ = OpTypeBottomK }
       | TOPK    { $$ = OpTypeTopK }
+      | SORT      { $$ = OpTypeSort }
+      | SORT_DESC { $$ = OpTypeSortDesc }
+      | COUNT_MIN_SKETCH { $$ = OpTypeCountMinSketch }
+      | HYPERLOGLOG      { $$ = OpTypeHyperLogLog }
       ;
 
 rangeOp:
@@ -485,6 +547,7 @@ rangeOp:
     | STDVAR_OVER_TIME   { $$ = OpRangeTypeStdvar }
     | STDDEV_OVER_TIME   { $$ = OpRangeTypeStddev }
     | QUANTILE_OVER_TIME { $$ = OpRangeTypeQuantile }
+    | QUANTILE_SKETCH_OVER_TIME { $$ = OpRangeTypeQuantileSketch }
     | FIRST_OVER_TIME    { $$ = OpRangeTypeFirst }
     | LAST_OVER_TIME     { $$ = OpRangeTypeLast }
     | ABSENT_OVER_TIME   { $$ = OpRangeTypeAbsent }
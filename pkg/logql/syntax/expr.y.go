@@ -1,9 +1,11 @@
-// Code generated by goyacc -p expr -o pkg/logql/syntax/expr.y.go pkg/logql/syntax/expr.y. DO NOT EDIT.
+// Code generated by goyacc -p expr -o expr.y.go expr.y. DO NOT EDIT.
 
+//line expr.y:2
 package syntax
 
 import __yyfmt__ "fmt"
 
+//line expr.y:2
 
 import (
 	"github.com/grafana/loki/pkg/logql/log"
@@ -11,10 +13,11 @@ import (
 	"time"
 )
 
+//line expr.y:12
 type exprSymType struct {
 	yys                   int
 	Expr                  Expr
-	Filter                labels.MatchType
+	Filter                log.LineMatchType
 	Grouping              *Grouping
 	Labels                []string
 	LogExpr               LogSelectorExpr
@@ -61,7 +64,15 @@ type exprSymType struct {
 	JSONExpressionList    []log.JSONExpression
 	UnwrapExpr            *UnwrapExpr
 	DecolorizeExpr        *DecolorizeExpr
+	DropLabel             log.DropLabel
+	DropLabels            []log.DropLabel
+	DropLabelsExpr        *DropLabelsExpr
+	KeepLabel             log.KeepLabel
+	KeepLabels            []log.KeepLabel
+	KeepLabelsExpr        *KeepLabelsExpr
 	OffsetExpr            *OffsetExpr
+	SubqueryExpr          *SubqueryExpr
+	subqueryRange         SubqueryRange
 }
 
 const BYTES = 57346
@@ -70,84 +81,96 @@ const STRING = 57348
 const NUMBER = 57349
 const DURATION = 57350
 const RANGE = 57351
-const MATCHERS = 57352
-const LABELS = 57353
-const EQ = 57354
-const RE = 57355
-const NRE = 57356
-const OPEN_BRACE = 57357
-const CLOSE_BRACE = 57358
-const OPEN_BRACKET = 57359
-const CLOSE_BRACKET = 57360
-const COMMA = 57361
-const DOT = 57362
-const PIPE_MATCH = 57363
-const PIPE_EXACT = 57364
-const OPEN_PARENTHESIS = 57365
-const CLOSE_PARENTHESIS = 57366
-const BY = 57367
-const WITHOUT = 57368
-const COUNT_OVER_TIME = 57369
-const RATE = 57370
-const RATE_COUNTER = 57371
-const SUM = 57372
-const AVG = 57373
-const MAX = 57374
-const MIN = 57375
-const COUNT = 57376
-const STDDEV = 57377
-const STDVAR = 57378
-const BOTTOMK = 57379
-const TOPK = 57380
-const BYTES_OVER_TIME = 57381
-const BYTES_RATE = 57382
-const BOOL = 57383
-const JSON = 57384
-const REGEXP = 57385
-const LOGFMT = 57386
-const PIPE = 57387
-const LINE_FMT = 57388
-const LABEL_FMT = 57389
-const UNWRAP = 57390
-const AVG_OVER_TIME = 57391
-const SUM_OVER_TIME = 57392
-const MIN_OVER_TIME = 57393
-const MAX_OVER_TIME = 57394
-const STDVAR_OVER_TIME = 57395
-const STDDEV_OVER_TIME = 57396
-const QUANTILE_OVER_TIME = 57397
-const BYTES_CONV = 57398
-const DURATION_CONV = 57399
-const DURATION_SECONDS_CONV = 57400
-const FIRST_OVER_TIME = 57401
-const LAST_OVER_TIME = 57402
-const ABSENT_OVER_TIME = 57403
-const VECTOR = 57404
-const LABEL_REPLACE = 57405
-const UNPACK = 57406
-const OFFSET = 57407
-const PATTERN = 57408
-const IP = 57409
-const ON = 57410
-const IGNORING = 57411
-const GROUP_LEFT = 57412
-const GROUP_RIGHT = 57413
-const DECOLORIZE = 57414
-const OR = 57415
-const AND = 57416
-const UNLESS = 57417
-const CMP_EQ = 57418
-const NEQ = 57419
-const LT = 57420
-const LTE = 57421
-const GT = 57422
-const GTE = 57423
-const ADD = 57424
-const SUB = 57425
-const MUL = 57426
-const DIV = 57427
-const MOD = 57428
-const POW = 57429
+const SUBQUERY_RANGE = 57352
+const SUBQUERY_RANGE_OP = 57353
+const MATCHERS = 57354
+const LABELS = 57355
+const EQ = 57356
+const RE = 57357
+const NRE = 57358
+const OPEN_BRACE = 57359
+const CLOSE_BRACE = 57360
+const OPEN_BRACKET = 57361
+const CLOSE_BRACKET = 57362
+const COMMA = 57363
+const DOT = 57364
+const PIPE_MATCH = 57365
+const PIPE_EXACT = 57366
+const PIPE_PATTERN = 57367
+const NPA = 57368
+const OPEN_PARENTHESIS = 57369
+const CLOSE_PARENTHESIS = 57370
+const BY = 57371
+const WITHOUT = 57372
+const COUNT_OVER_TIME = 57373
+const RATE = 57374
+const RATE_COUNTER = 57375
+const SUM = 57376
+const AVG = 57377
+const MAX = 57378
+const MIN = 57379
+const COUNT = 57380
+const STDDEV = 57381
+const STDVAR = 57382
+const BOTTOMK = 57383
+const TOPK = 57384
+const BYTES_OVER_TIME = 57385
+const BYTES_RATE = 57386
+const BOOL = 57387
+const JSON = 57388
+const REGEXP = 57389
+const LOGFMT = 57390
+const PIPE = 57391
+const LINE_FMT = 57392
+const LABEL_FMT = 57393
+const UNWRAP = 57394
+const AVG_OVER_TIME = 57395
+const SUM_OVER_TIME = 57396
+const MIN_OVER_TIME = 57397
+const MAX_OVER_TIME = 57398
+const STDVAR_OVER_TIME = 57399
+const STDDEV_OVER_TIME = 57400
+const QUANTILE_OVER_TIME = 57401
+const BYTES_CONV = 57402
+const DURATION_CONV = 57403
+const DURATION_SECONDS_CONV = 57404
+const FIRST_OVER_TIME = 57405
+const LAST_OVER_TIME = 57406
+const ABSENT_OVER_TIME = 57407
+const QUANTILE_SKETCH_OVER_TIME = 57408
+const COUNT_MIN_SKETCH = 57409
+const HYPERLOGLOG = 57410
+const VECTOR = 57411
+const LABEL_REPLACE = 57412
+const UNPACK = 57413
+const OFFSET = 57414
+const PATTERN = 57415
+const IP = 57416
+const ON = 57417
+const IGNORING = 57418
+const GROUP_LEFT = 57419
+const GROUP_RIGHT = 57420
+const DECOLORIZE = 57421
+const DROP = 57422
+const KEEP = 57423
+const SORT = 57424
+const SORT_DESC = 57425
+const COUNT_VALUES = 57426
+const OR = 57427
+const AND = 57428
+const UNLESS = 57429
+const CMP_EQ = 57430
+const NEQ = 57431
+const LT = 57432
+const LTE = 57433
+const GT = 57434
+const GTE = 57435
+const ADD = 57436
+const SUB = 57437
+const MUL = 57438
+const DIV = 57439
+const MOD = 57440
+const POW = 57441
 
 var exprToknames = [...]string{
 	"$end",
@@ -159,6 +182,8 @@ var exprToknames = [...]string{
 	"NUMBER",
 	"DURATION",
 	"RANGE",
+	"SUBQUERY_RANGE",
+	"SUBQUERY_RANGE_OP",
 	"MATCHERS",
 	"LABELS",
 	"EQ",
@@ -172,6 +197,8 @@ var exprToknames = [...]string{
 	"DOT",
 	"PIPE_MATCH",
 	"PIPE_EXACT",
+	"PIPE_PATTERN",
+	"NPA",
 	"OPEN_PARENTHESIS",
 	"CLOSE_PARENTHESIS",
 	"BY",
@@ -211,6 +238,9 @@ var exprToknames = [...]string{
 	"FIRST_OVER_TIME",
 	"LAST_OVER_TIME",
 	"ABSENT_OVER_TIME",
+	"QUANTILE_SKETCH_OVER_TIME",
+	"COUNT_MIN_SKETCH",
+	"HYPERLOGLOG",
 	"VECTOR",
 	"LABEL_REPLACE",
 	"UNPACK",
@@ -222,6 +252,11 @@ var exprToknames = [...]string{
 	"GROUP_LEFT",
 	"GROUP_RIGHT",
 	"DECOLORIZE",
+	"DROP",
+	"KEEP",
+	"SORT",
+	"SORT_DESC",
+	"COUNT_VALUES",
 	"OR",
 	"AND",
 	"UNLESS",
@@ -238,14 +273,17 @@ var exprToknames = [...]string{
 	"MOD",
 	"POW",
 }
+
 var exprStatenames = [...]string{}
 
 const exprEofCode = 1
 const exprErrCode = 2
 const exprInitialStackSize = 16
 
+//line expr.y:570
 
-var exprExca = [...]int{
+//line yacctab:1
+var exprExca = [...]int8{
 	-1, 1,
 	1, -1,
 	-2, 0,
@@ -253,242 +291,274 @@ var exprExca = [...]int{
 
 const exprPrivate = 57344
 
-const exprLast = 545
-
-var exprAct = [...]int{
-
-	256, 202, 80, 4, 183, 62, 171, 5, 176, 211,
-	71, 118, 54, 61, 259, 141, 73, 2, 49, 50,
-	51, 52, 53, 54, 129, 76, 46, 47, 48, 55,
-	56, 59, 60, 57, 58, 49, 50, 51, 52, 53,
-	54, 47, 48, 55, 56, 59, 60, 57, 58, 49,
-	50, 51, 52, 53, 54, 55, 56, 59, 60, 57,
-	58, 49, 50, 51, 52, 53, 54, 105, 185, 139,
-	140, 109, 51, 52, 53, 54, 137, 139, 140, 155,
-	156, 153, 154, 145, 264, 131, 143, 262, 302, 150,
-	69, 236, 69, 195, 237, 235, 261, 67, 68, 67,
-	68, 348, 310, 331, 152, 328, 328, 260, 157, 158,
-	159, 160, 161, 162, 163, 164, 165, 166, 167, 168,
-	169, 170, 309, 204, 261, 259, 90, 65, 213, 180,
-	343, 126, 191, 186, 189, 190, 187, 188, 81, 82,
-	302, 138, 16, 261, 336, 173, 260, 283, 193, 122,
-	13, 209, 205, 70, 234, 70, 198, 203, 6, 214,
-	206, 325, 21, 22, 23, 36, 37, 39, 40, 38,
-	41, 42, 43, 44, 24, 25, 261, 335, 294, 222,
-	223, 224, 261, 126, 26, 27, 28, 29, 30, 31,
-	32, 106, 198, 333, 33, 34, 35, 45, 19, 174,
-	172, 122, 254, 257, 213, 263, 312, 266, 143, 105,
-	269, 109, 270, 198, 268, 258, 255, 17, 18, 267,
-	113, 115, 114, 281, 123, 125, 264, 273, 277, 279,
-	282, 284, 319, 287, 285, 199, 201, 293, 126, 142,
-	303, 69, 116, 79, 117, 81, 82, 13, 67, 68,
-	124, 265, 173, 126, 262, 144, 122, 227, 295, 69,
-	297, 299, 271, 301, 105, 217, 67, 68, 300, 311,
-	296, 122, 204, 105, 69, 201, 313, 213, 273, 207,
-	69, 67, 68, 318, 273, 133, 132, 67, 68, 317,
-	204, 305, 306, 307, 126, 273, 280, 322, 323, 126,
-	316, 213, 105, 324, 70, 204, 174, 172, 173, 326,
-	327, 204, 122, 173, 273, 332, 273, 122, 13, 275,
-	278, 274, 70, 292, 210, 259, 144, 291, 338, 221,
-	339, 340, 13, 220, 219, 218, 192, 70, 149, 213,
-	6, 213, 344, 70, 21, 22, 23, 36, 37, 39,
-	40, 38, 41, 42, 43, 44, 24, 25, 215, 346,
-	212, 148, 147, 172, 86, 146, 26, 27, 28, 29,
-	30, 31, 32, 13, 85, 69, 33, 34, 35, 45,
-	19, 6, 67, 68, 342, 21, 22, 23, 36, 37,
-	39, 40, 38, 41, 42, 43, 44, 24, 25, 17,
-	18, 78, 315, 272, 228, 126, 204, 26, 27, 28,
-	29, 30, 31, 32, 135, 225, 216, 33, 34, 35,
-	45, 19, 69, 122, 208, 200, 77, 229, 134, 67,
-	68, 136, 226, 341, 330, 329, 308, 75, 70, 87,
-	17, 18, 113, 115, 114, 298, 123, 125, 232, 151,
-	194, 233, 231, 64, 251, 84, 248, 252, 250, 249,
-	247, 289, 290, 347, 116, 245, 117, 242, 246, 244,
-	243, 241, 124, 239, 83, 3, 240, 238, 345, 334,
-	321, 320, 72, 288, 286, 70, 184, 91, 92, 93,
-	94, 95, 96, 97, 98, 99, 100, 101, 102, 103,
-	104, 276, 253, 197, 196, 195, 194, 181, 179, 178,
-	337, 230, 314, 177, 77, 184, 119, 120, 175, 108,
-	182, 112, 111, 110, 63, 127, 121, 128, 107, 89,
-	88, 11, 10, 9, 130, 20, 12, 15, 8, 304,
-	14, 7, 74, 66, 1,
+const exprLast = 677
+
+var exprAct = [...]int16{
+	292, 231, 4, 192, 90, 69, 132, 211, 197, 80,
+	207, 68, 161, 204, 61, 5, 295, 157, 82, 2,
+	85, 18, 94, 72, 243, 15, 58, 59, 60, 61,
+	300, 13, 56, 57, 58, 59, 60, 61, 214, 155,
+	156, 6, 176, 177, 297, 23, 24, 25, 39, 40,
+	42, 43, 41, 44, 45, 46, 47, 26, 27, 174,
+	175, 153, 155, 156, 374, 374, 345, 28, 29, 30,
+	31, 32, 33, 34, 117, 121, 145, 36, 37, 38,
+	35, 50, 51, 52, 21, 377, 346, 295, 102, 142,
+	397, 163, 164, 227, 118, 392, 48, 49, 17, 93,
+	171, 91, 92, 159, 385, 194, 297, 384, 19, 20,
+	382, 136, 220, 215, 218, 219, 216, 217, 173, 337,
+	296, 371, 178, 179, 180, 181, 182, 183, 184, 185,
+	186, 187, 188, 189, 190, 191, 154, 345, 380, 352,
+	201, 348, 349, 350, 147, 91, 92, 209, 213, 89,
+	142, 91, 92, 359, 76, 296, 395, 356, 312, 222,
+	297, 74, 75, 78, 79, 365, 194, 355, 312, 241,
+	193, 232, 136, 261, 142, 364, 234, 297, 235, 54,
+	55, 62, 63, 66, 67, 64, 65, 56, 57, 58,
+	59, 60, 61, 246, 336, 297, 136, 256, 257, 258,
+	53, 54, 55, 62, 63, 66, 67, 64, 65, 56,
+	57, 58, 59, 60, 61, 62, 63, 66, 67, 64,
+	65, 56, 57, 58, 59, 60, 61, 77, 312, 245,
+	195, 193, 293, 290, 299, 363, 302, 121, 117, 307,
+	163, 305, 294, 309, 308, 159, 303, 291, 298, 312,
+	306, 315, 324, 312, 312, 76, 362, 310, 251, 239,
+	314, 313, 74, 75, 78, 79, 245, 353, 326, 142,
+	245, 209, 213, 333, 76, 332, 328, 318, 320, 323,
+	325, 74, 75, 78, 79, 194, 263, 236, 233, 322,
+	142, 136, 245, 321, 338, 227, 340, 342, 149, 344,
+	117, 343, 148, 335, 339, 354, 194, 233, 245, 117,
+	230, 245, 136, 227, 357, 319, 334, 76, 255, 360,
+	391, 304, 13, 158, 74, 75, 78, 79, 77, 301,
+	295, 247, 160, 13, 244, 254, 253, 252, 172, 228,
+	221, 369, 368, 160, 170, 117, 370, 77, 168, 167,
+	233, 166, 372, 373, 98, 97, 88, 87, 361, 316,
+	378, 379, 311, 265, 381, 264, 298, 262, 242, 259,
+	195, 193, 15, 76, 387, 250, 388, 389, 13, 248,
+	74, 75, 78, 79, 240, 237, 229, 260, 6, 238,
+	77, 393, 23, 24, 25, 39, 40, 42, 43, 41,
+	44, 45, 46, 47, 26, 27, 233, 272, 390, 224,
+	273, 271, 96, 376, 28, 29, 30, 31, 32, 33,
+	34, 86, 375, 351, 36, 37, 38, 35, 50, 51,
+	52, 21, 341, 396, 84, 230, 287, 165, 95, 288,
+	286, 15, 76, 48, 49, 17, 77, 13, 394, 74,
+	75, 78, 79, 330, 331, 19, 20, 6, 383, 367,
+	366, 23, 24, 25, 39, 40, 42, 43, 41, 44,
+	45, 46, 47, 26, 27, 233, 268, 270, 223, 269,
+	267, 327, 317, 28, 29, 30, 31, 32, 33, 34,
+	289, 249, 226, 36, 37, 38, 35, 50, 51, 52,
+	21, 284, 3, 386, 285, 283, 162, 225, 224, 81,
+	15, 76, 48, 49, 17, 77, 13, 223, 74, 75,
+	78, 79, 202, 200, 19, 20, 6, 199, 169, 151,
+	23, 24, 25, 39, 40, 42, 43, 41, 44, 45,
+	46, 47, 26, 27, 233, 150, 266, 281, 152, 358,
+	282, 280, 28, 29, 30, 31, 32, 33, 34, 126,
+	212, 208, 36, 37, 38, 35, 50, 51, 52, 21,
+	76, 278, 210, 142, 279, 277, 198, 74, 75, 78,
+	79, 48, 49, 17, 77, 142, 275, 125, 329, 276,
+	274, 205, 86, 19, 20, 136, 205, 206, 133, 134,
+	196, 120, 203, 71, 124, 123, 122, 136, 99, 70,
+	143, 135, 144, 119, 127, 129, 128, 101, 137, 139,
+	300, 100, 11, 10, 9, 146, 127, 129, 128, 22,
+	137, 139, 12, 16, 8, 347, 14, 7, 83, 130,
+	73, 131, 1, 77, 0, 0, 0, 138, 140, 141,
+	0, 130, 0, 131, 0, 0, 0, 0, 0, 138,
+	140, 141, 0, 103, 104, 105, 106, 107, 108, 109,
+	110, 111, 112, 113, 114, 115, 116,
 }
-var exprPact = [...]int{
-
-	135, -1000, -47, -1000, -1000, 408, 135, -1000, -1000, -1000,
-	-1000, -1000, -1000, 421, 378, 220, -1000, 467, 448, 351,
-	341, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
-	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
-	-1000, -1000, -1000, -1000, -1000, -1000, 85, 85, 85, 85,
-	85, 85, 85, 85, 85, 85, 85, 85, 85, 85,
-	85, 408, -1000, 76, 400, -1000, 18, -1000, -1000, -1000,
-	-1000, 262, 261, -47, 412, -1000, -1000, 64, 232, 358,
-	339, 338, 315, -1000, -1000, 135, 442, 135, 13, 9,
-	-1000, 135, 135, 135, 135, 135, 135, 135, 135, 135,
-	135, 135, 135, 135, 135, -1000, -1000, -1000, -1000, 126,
-	-1000, -1000, -1000, 508, -1000, 503, -1000, 502, -1000, -1000,
-	-1000, -1000, 248, 501, -1000, 510, 56, -1000, -1000, -1000,
-	313, -1000, -1000, -1000, -1000, -1000, 509, 500, 499, 498,
-	497, 211, 406, 266, 303, 255, 405, 317, 336, 334,
-	397, 241, -33, 312, 311, 310, 306, -21, -21, -12,
-	-12, -75, -75, -75, -75, -64, -64, -64, -64, -64,
-	-64, 126, 248, 248, 248, 396, -1000, 420, -1000, -1000,
-	233, -1000, 385, -1000, 415, 444, 87, 469, 463, 461,
-	452, 450, 496, -1000, -1000, -1000, -1000, -1000, -1000, 113,
-	303, 260, 137, 245, 178, 227, 190, 113, 135, 238,
-	384, 297, -1000, -1000, 295, -1000, 495, -1000, 296, 272,
-	199, 123, 294, 126, 289, 508, 478, -1000, 481, 456,
-	304, -1000, -1000, -1000, 300, -1000, -1000, -1000, -1000, -1000,
-	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
-	-1000, -1000, -1000, 213, -1000, 154, 361, 51, 361, 437,
-	-51, 248, -51, 131, 235, 427, 98, 78, -1000, -1000,
-	182, -1000, 135, 507, -1000, -1000, 383, 276, -1000, 265,
-	-1000, -1000, 259, -1000, 208, -1000, -1000, -1000, -1000, -1000,
-	-1000, 475, 474, -1000, 113, 51, 361, 51, -1000, -1000,
-	126, -1000, -51, -1000, 138, -1000, -1000, -1000, 60, 426,
-	425, 79, 113, 169, -1000, 473, -1000, -1000, -1000, -1000,
-	153, 120, -1000, 51, -1000, 505, 61, 51, 36, -51,
-	-51, 424, -1000, -1000, 365, -1000, -1000, 106, 51, -1000,
-	-1000, -51, 472, -1000, -1000, 340, 457, 77, -1000,
+
+var exprPact = [...]int16{
+	14, -32768, 115, -32768, -32768, 554, 14, -32768, -32768, -32768,
+	-32768, -32768, -32768, 416, 330, 329, 122, 72, -32768, 431,
+	405, 328, 327, -32768, -32768, -32768, -32768, -32768, -32768, -32768,
+	-32768, -32768, -32768, -32768, -32768, -32768, -32768, -32768, -32768, -32768,
+	-32768, -32768, -32768, -32768, -32768, -32768, -32768, -32768, -32768, -32768,
+	-32768, -32768, -32768, 43, 43, 43, 43, 43, 43, 43,
+	43, 43, 43, 43, 43, 43, 43, 43, 554, -32768,
+	138, 580, -32768, 70, -32768, -32768, -32768, -32768, -32768, -32768,
+	274, 270, 115, 527, -32768, -32768, 47, 316, 499, 430,
+	324, 322, 321, 522, 317, -32768, -32768, 14, 331, 14,
+	-16, -35, -32768, 14, 14, 14, 14, 14, 14, 14,
+	14, 14, 14, 14, 14, 14, 14, -32768, -32768, -32768,
+	-32768, 285, -32768, -32768, -32768, -32768, -32768, 571, -32768, 521,
+	-32768, 517, -32768, -32768, -32768, -32768, 169, 516, -32768, 591,
+	556, 555, 24, -32768, -32768, -32768, 313, -32768, -32768, -32768,
+	-32768, -32768, 587, 511, 502, 501, 486, 311, 365, 426,
+	305, 259, 364, 379, 231, 363, 361, 306, 303, 358,
+	485, 354, 230, 93, 310, 309, 308, 291, 127, 127,
+	-70, -70, -85, -85, -85, -85, -62, -62, -62, -62,
+	-62, -62, 285, 169, 169, 169, 348, -32768, 373, -32768,
+	-32768, 145, -32768, 346, -32768, 272, 344, -32768, 47, -32768,
+	342, -32768, 47, -32768, 472, 403, 582, 567, 543, 497,
+	432, 484, -32768, -32768, -32768, -32768, -32768, -32768, 116, 305,
+	258, 146, 357, 568, 301, 293, 116, 14, -56, 116,
+	14, 229, 341, 233, -32768, -32768, 232, -32768, 14, 338,
+	476, -32768, 287, 265, 261, 224, 264, 285, 84, 571,
+	475, -32768, 586, 448, 556, 555, 289, -32768, -32768, -32768,
+	276, -32768, -32768, -32768, -32768, -32768, -32768, -32768, -32768, -32768,
+	-32768, -32768, -32768, -32768, -32768, -32768, -32768, -32768, -32768, 166,
+	-32768, 91, 495, -5, 495, 424, -56, 169, -56, 128,
+	81, 414, 111, 239, -32768, -32768, 139, -32768, -32768, 129,
+	-32768, 14, 544, -32768, -32768, 125, 14, 337, 228, -32768,
+	207, -32768, -32768, 147, -32768, 137, -32768, -32768, -32768, -32768,
+	-32768, -32768, -32768, -32768, 454, 453, -32768, 116, -5, 495,
+	-5, -32768, -32768, 285, -32768, -56, -32768, 94, -32768, -32768,
+	-32768, 15, 413, 404, 57, 116, 116, 110, -32768, 116,
+	82, 452, -32768, -32768, -32768, -32768, 79, 76, -32768, -5,
+	-32768, 498, 16, -5, -22, -56, -56, 399, -32768, -32768,
+	-32768, -32768, -32768, 299, -32768, -32768, 67, -5, -32768, -32768,
+	-56, 442, -32768, -32768, 135, 427, 62, -32768,
 }
-var exprPgo = [...]int{
 
-	0, 544, 16, 543, 2, 9, 475, 3, 15, 11,
-	542, 541, 540, 539, 7, 538, 537, 536, 535, 534,
-	533, 532, 531, 439, 530, 529, 528, 13, 5, 527,
-	526, 525, 6, 524, 127, 523, 522, 521, 4, 520,
-	519, 8, 518, 1, 517, 516, 0,
+var exprPgo = [...]int16{
+	0, 642, 18, 640, 4, 24, 502, 2, 17, 6,
+	638, 637, 636, 635, 15, 634, 633, 632, 629, 625,
+	624, 623, 622, 608, 621, 617, 613, 11, 5, 612,
+	611, 610, 3, 609, 23, 606, 605, 604, 13, 602,
+	601, 8, 600, 1, 599, 598, 0, 12, 10, 597,
+	587, 7, 572, 559,
 }
-var exprR1 = [...]int{
 
+var exprR1 = [...]int8{
 	0, 1, 2, 2, 7, 7, 7, 7, 7, 7,
 	7, 6, 6, 6, 8, 8, 8, 8, 8, 8,
 	8, 8, 8, 8, 8, 8, 8, 8, 8, 8,
 	8, 8, 8, 8, 8, 8, 8, 8, 8, 8,
 	43, 43, 43, 13, 13, 13, 11, 11, 11, 11,
-	15, 15, 15, 15, 15, 15, 22, 3, 3, 3,
-	3, 14, 14, 14, 10, 10, 9, 9, 9, 9,
-	27, 27, 28, 28, 28, 28, 28, 28, 28, 19,
-	34, 34, 33, 33, 26, 26, 26, 26, 26, 40,
-	35, 36, 38, 38, 39, 39, 39, 37, 32, 32,
-	32, 32, 32, 32, 32, 32, 32, 41, 41, 42,
-	42, 45, 45, 44, 44, 31, 31, 31, 31, 31,
-	31, 31, 29, 29, 29, 29, 29, 29, 29, 30,
-	30, 30, 30, 30, 30, 30, 20, 20, 20, 20,
+	11, 11, 11, 11, 47, 47, 15, 15, 15, 15,
+	15, 15, 15, 15, 15, 22, 3, 3, 3, 3,
+	3, 3, 14, 14, 14, 10, 10, 9, 9, 9,
+	9, 27, 27, 28, 28, 28, 28, 28, 28, 28,
+	28, 28, 19, 34, 34, 33, 33, 26, 26, 26,
+	26, 26, 40, 35, 36, 38, 38, 39, 39, 39,
+	37, 32, 32, 32, 32, 32, 32, 32, 32, 32,
+	41, 41, 42, 42, 48, 48, 49, 49, 50, 51,
+	51, 52, 52, 53, 45, 45, 44, 44, 31, 31,
+	31, 31, 31, 31, 31, 29, 29, 29, 29, 29,
+	29, 29, 30, 30, 30, 30, 30, 30, 30, 20,
 	20, 20, 20, 20, 20, 20, 20, 20, 20, 20,
-	20, 24, 24, 25, 25, 25, 25, 23, 23, 23,
-	23, 23, 23, 23, 23, 21, 21, 21, 17, 18,
-	16, 16, 16, 16, 16, 16, 16, 16, 16, 12,
+	20, 20, 20, 20, 24, 24, 25, 25, 25, 25,
+	23, 23, 23, 23, 23, 23, 23, 23, 21, 21,
+	21, 17, 18, 16, 16, 16, 16, 16, 16, 16,
+	16, 16, 16, 16, 16, 16, 12, 12, 12, 12,
 	12, 12, 12, 12, 12, 12, 12, 12, 12, 12,
-	12, 12, 12, 12, 46, 5, 5, 4, 4, 4,
-	4,
+	12, 12, 46, 5, 5, 4, 4, 4, 4,
 }
-var exprR2 = [...]int{
 
+var exprR2 = [...]int8{
 	0, 1, 1, 1, 1, 1, 1, 1, 1, 1,
 	3, 1, 2, 3, 2, 3, 4, 5, 3, 4,
 	5, 6, 3, 4, 5, 6, 3, 4, 5, 6,
 	4, 5, 6, 7, 3, 4, 4, 5, 3, 2,
 	3, 6, 3, 1, 1, 1, 4, 6, 5, 7,
-	4, 5, 5, 6, 7, 7, 12, 1, 1, 1,
-	1, 3, 3, 2, 1, 3, 3, 3, 3, 3,
-	1, 2, 1, 2, 2, 2, 2, 2, 2, 1,
-	2, 5, 1, 2, 1, 1, 2, 1, 2, 2,
-	2, 1, 3, 3, 1, 3, 3, 2, 1, 1,
-	1, 1, 3, 2, 3, 3, 3, 3, 1, 1,
-	3, 6, 6, 1, 1, 3, 3, 3, 3, 3,
+	4, 6, 5, 7, 2, 3, 4, 5, 5, 6,
+	7, 7, 6, 7, 7, 12, 1, 1, 1, 1,
+	1, 1, 3, 3, 2, 1, 3, 3, 3, 3,
+	3, 1, 2, 1, 2, 2, 2, 2, 2, 2,
+	2, 2, 1, 2, 5, 1, 2, 1, 1, 2,
+	1, 2, 2, 2, 1, 3, 3, 1, 3, 3,
+	2, 1, 1, 1, 1, 3, 2, 3, 3, 3,
+	3, 1, 1, 3, 1, 1, 1, 3, 2, 1,
+	1, 1, 3, 2, 6, 6, 1, 1, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	3, 3, 3, 3, 3, 3, 4, 4, 4, 4,
+	3, 3, 3, 3, 3, 3, 3, 3, 3, 4,
 	4, 4, 4, 4, 4, 4, 4, 4, 4, 4,
-	4, 0, 1, 5, 4, 5, 4, 1, 1, 2,
-	4, 5, 2, 4, 5, 1, 2, 2, 4, 1,
+	4, 4, 4, 4, 0, 1, 5, 4, 5, 4,
+	1, 1, 2, 4, 5, 2, 4, 5, 1, 2,
+	2, 4, 1, 1, 1, 1, 1, 1, 1, 1,
 	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
 	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
-	1, 1, 1, 1, 2, 1, 3, 4, 4, 3,
-	3,
+	1, 1, 2, 1, 3, 4, 4, 3, 3,
 }
-var exprChk = [...]int{
-
-	-1000, -1, -2, -6, -7, -14, 23, -11, -15, -20,
-	-21, -22, -17, 15, -12, -16, 7, 82, 83, 63,
-	-18, 27, 28, 29, 39, 40, 49, 50, 51, 52,
-	53, 54, 55, 59, 60, 61, 30, 31, 34, 32,
-	33, 35, 36, 37, 38, 62, 73, 74, 75, 82,
-	83, 84, 85, 86, 87, 76, 77, 80, 81, 78,
-	79, -27, -28, -33, 45, -34, -3, 21, 22, 14,
-	77, -7, -6, -2, -10, 16, -9, 5, 23, 23,
-	-4, 25, 26, 7, 7, 23, 23, -23, -24, -25,
-	41, -23, -23, -23, -23, -23, -23, -23, -23, -23,
-	-23, -23, -23, -23, -23, -28, -34, -26, -40, -32,
-	-35, -36, -37, 42, 44, 43, 64, 66, -9, -45,
-	-44, -30, 23, 46, 72, 47, 5, -31, -29, 6,
-	-19, 67, 24, 24, 16, 2, 19, 12, 77, 13,
-	14, -8, 7, -14, 23, -7, 7, 23, 23, 23,
-	-7, 7, -2, 68, 69, 70, 71, -2, -2, -2,
+
+var exprChk = [...]int16{
+	-32768, -1, -2, -6, -7, -14, 27, -11, -15, -20,
+	-21, -22, -17, 17, -12, 11, -16, 84, 7, 94,
+	95, 70, -18, 31, 32, 33, 43, 44, 53, 54,
+	55, 56, 57, 58, 59, 66, 63, 64, 65, 34,
+	35, 38, 36, 37, 39, 40, 41, 42, 82, 83,
+	67, 68, 69, 85, 86, 87, 94, 95, 96, 97,
+	98, 99, 88, 89, 92, 93, 90, 91, -27, -28,
+	-33, 49, -34, -3, 23, 24, 16, 89, 25, 26,
+	-7, -6, -2, -10, 18, -9, 5, 27, 27, 27,
+	-4, 29, 30, 27, -4, 7, 7, 27, 27, -23,
+	-24, -25, 45, -23, -23, -23, -23, -23, -23, -23,
+	-23, -23, -23, -23, -23, -23, -23, -28, -34, -26,
+	-40, -32, -35, -36, -37, -50, -53, 46, 48, 47,
+	71, 73, -9, -45, -44, -30, 27, 50, 79, 51,
+	80, 81, 5, -31, -29, 6, -19, 74, 28, 28,
+	18, 2, 21, 14, 89, 15, 16, -8, 7, -14,
+	27, -47, 7, -7, -7, 7, 27, 27, 27, 6,
+	27, -7, 7, -2, 75, 76, 77, 78, -2, -2,
 	-2, -2, -2, -2, -2, -2, -2, -2, -2, -2,
-	-2, -32, 74, 19, 73, -42, -41, 5, 6, 6,
-	-32, 6, -39, -38, 5, 12, 77, 80, 81, 78,
-	79, 76, 23, -9, 6, 6, 6, 6, 2, 24,
-	19, 9, -43, -27, 45, -14, -8, 24, 19, -7,
-	7, -5, 24, 5, -5, 24, 19, 24, 23, 23,
-	23, 23, -32, -32, -32, 19, 12, 24, 19, 12,
-	67, 8, 4, 7, 67, 8, 4, 7, 8, 4,
-	7, 8, 4, 7, 8, 4, 7, 8, 4, 7,
-	8, 4, 7, 6, -4, -8, -46, -43, -27, 65,
-	9, 45, 9, -43, 48, 24, -43, -27, 24, -4,
-	-7, 24, 19, 19, 24, 24, 6, -5, 24, -5,
-	24, 24, -5, 24, -5, -41, 6, -38, 2, 5,
-	6, 23, 23, 24, 24, -43, -27, -43, 8, -46,
-	-32, -46, 9, 5, -13, 56, 57, 58, 9, 24,
-	24, -43, 24, -7, 5, 19, 24, 24, 24, 24,
-	6, 6, -4, -43, -46, 23, -46, -43, 45, 9,
-	9, 24, -4, 24, 6, 24, 24, 5, -43, -46,
-	-46, 9, 19, 24, -46, 6, 19, 6, 24,
+	-2, -2, -32, 86, 21, 85, -42, -41, 5, 6,
+	6, -32, 6, -39, -38, 5, -49, -48, 5, -9,
+	-52, -51, 5, -9, 14, 89, 92, 93, 90, 91,
+	88, 27, -9, 6, 6, 6, 6, 2, 28, 21,
+	9, -43, -27, 49, -14, -8, 28, 21, 10, 28,
+	21, -7, 7, -5, 28, 5, -5, 28, 21, 6,
+	21, 28, 27, 27, 27, 27, -32, -32, -32, 21,
+	14, 28, 21, 14, 21, 21, 74, 8, 4, 7,
+	74, 8, 4, 7, 8, 4, 7, 8, 4, 7,
+	8, 4, 7, 8, 4, 7, 8, 4, 7, 6,
+	-4, -8, -46, -43, -27, 72, 9, 49, 9, -43,
+	52, 28, -43, -27, 28, -4, -47, -46, -4, -7,
+	28, 21, 21, 28, 28, -7, 21, 6, -5, 28,
+	-5, 28, 28, -5, 28, -5, -41, 6, -38, 2,
+	5, 6, -48, -51, 27, 27, 28, 28, -43, -27,
+	-43, 8, -46, -32, -46, 9, 5, -13, 60, 61,
+	62, 9, 28, 28, -43, 28, 28, -7, 5, 28,
+	-7, 21, 28, 28, 28, 28, 6, 6, -4, -43,
+	-46, 27, -46, -43, 49, 9, 9, 28, -4, -4,
+	28, -4, 28, 6, 28, 28, 5, -43, -46, -46,
+	9, 21, 28, -46, 6, 21, 6, 28,
 }
-var exprDef = [...]int{
 
+var exprDef = [...]int16{
 	0, -2, 1, 2, 3, 11, 0, 4, 5, 6,
-	7, 8, 9, 0, 0, 0, 165, 0, 0, 0,
-	0, 179, 180, 181, 182, 183, 184, 185, 186, 187,
-	188, 189, 190, 191, 192, 193, 170, 171, 172, 173,
-	174, 175, 176, 177, 178, 169, 151, 151, 151, 151,
-	151, 151, 151, 151, 151, 151, 151, 151, 151, 151,
-	151, 12, 70, 72, 0, 82, 0, 57, 58, 59,
-	60, 3, 2, 0, 0, 63, 64, 0, 0, 0,
-	0, 0, 0, 166, 167, 0, 0, 0, 157, 158,
-	152, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 71, 83, 73, 74, 75,
-	76, 77, 78, 84, 85, 0, 87, 0, 98, 99,
-	100, 101, 0, 0, 91, 0, 0, 113, 114, 80,
-	0, 79, 10, 13, 61, 62, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 3, 165, 0, 0, 0,
-	3, 0, 136, 0, 0, 159, 162, 137, 138, 139,
-	140, 141, 142, 143, 144, 145, 146, 147, 148, 149,
-	150, 103, 0, 0, 0, 89, 109, 108, 86, 88,
-	0, 90, 97, 94, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 65, 66, 67, 68, 69, 39, 46,
-	0, 14, 0, 0, 0, 0, 0, 50, 0, 3,
-	165, 0, 199, 195, 0, 200, 0, 168, 0, 0,
-	0, 0, 104, 105, 106, 0, 0, 102, 0, 0,
-	0, 120, 127, 134, 0, 119, 126, 133, 115, 122,
-	129, 116, 123, 130, 117, 124, 131, 118, 125, 132,
-	121, 128, 135, 0, 48, 0, 15, 18, 34, 0,
-	22, 0, 26, 0, 0, 0, 0, 0, 38, 52,
-	3, 51, 0, 0, 197, 198, 0, 0, 154, 0,
-	156, 160, 0, 163, 0, 110, 107, 95, 96, 92,
-	93, 0, 0, 81, 47, 19, 35, 36, 194, 23,
-	42, 27, 30, 40, 0, 43, 44, 45, 16, 0,
-	0, 0, 53, 3, 196, 0, 153, 155, 161, 164,
-	0, 0, 49, 37, 31, 0, 17, 20, 0, 24,
-	28, 0, 54, 55, 0, 111, 112, 0, 21, 25,
-	29, 32, 0, 41, 33, 0, 0, 0, 56,
+	7, 8, 9, 0, 0, 0, 0, 0, 188, 0,
+	0, 0, 0, 206, 207, 208, 209, 210, 211, 212,
+	213, 214, 215, 216, 217, 218, 219, 220, 221, 193,
+	194, 195, 196, 197, 198, 199, 200, 201, 202, 203,
+	204, 205, 192, 174, 174, 174, 174, 174, 174, 174,
+	174, 174, 174, 174, 174, 174, 174, 174, 12, 81,
+	83, 0, 95, 0, 66, 67, 68, 69, 70, 71,
+	3, 2, 0, 0, 74, 75, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 189, 190, 0, 0, 0,
+	180, 181, 175, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 82, 96, 84,
+	85, 86, 87, 88, 89, 90, 91, 97, 98, 0,
+	100, 0, 111, 112, 113, 114, 0, 0, 104, 0,
+	0, 0, 0, 136, 137, 93, 0, 92, 10, 13,
+	72, 73, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 188, 3, 3, 188, 0, 0, 0, 0,
+	0, 3, 0, 159, 0, 0, 182, 185, 160, 161,
+	162, 163, 164, 165, 166, 167, 168, 169, 170, 171,
+	172, 173, 116, 0, 0, 0, 102, 122, 121, 99,
+	101, 0, 103, 110, 107, 0, 128, 126, 124, 125,
+	133, 131, 129, 130, 0, 0, 0, 0, 0, 0,
+	0, 0, 76, 77, 78, 79, 80, 39, 46, 0,
+	14, 0, 0, 0, 0, 0, 50, 0, 54, 56,
+	0, 3, 188, 0, 227, 223, 0, 228, 0, 0,
+	0, 191, 0, 0, 0, 0, 117, 118, 119, 0,
+	0, 115, 0, 0, 0, 0, 0, 143, 150, 157,
+	0, 142, 149, 156, 138, 145, 152, 139, 146, 153,
+	140, 147, 154, 141, 148, 155, 144, 151, 158, 0,
+	48, 0, 15, 18, 34, 0, 22, 0, 26, 0,
+	0, 0, 0, 0, 38, 52, 0, 55, 58, 3,
+	57, 0, 0, 225, 226, 3, 0, 0, 0, 177,
+	0, 179, 183, 0, 186, 0, 123, 120, 108, 109,
+	105, 106, 127, 132, 0, 0, 94, 47, 19, 35,
+	36, 222, 23, 42, 27, 30, 40, 0, 43, 44,
+	45, 16, 0, 0, 0, 51, 59, 3, 224, 62,
+	3, 0, 176, 178, 184, 187, 0, 0, 49, 37,
+	31, 0, 17, 20, 0, 24, 28, 0, 53, 60,
+	61, 63, 64, 0, 134, 135, 0, 21, 25, 29,
+	32, 0, 41, 33, 0, 0, 0, 65,
 }
-var exprTok1 = [...]int{
 
+var exprTok1 = [...]int8{
 	1,
 }
-var exprTok2 = [...]int{
 
+var exprTok2 = [...]int8{
 	2, 3, 4, 5, 6, 7, 8, 9, 10, 11,
 	12, 13, 14, 15, 16, 17, 18, 19, 20, 21,
 	22, 23, 24, 25, 26, 27, 28, 29, 30, 31,
@@ -497,9 +567,11 @@ var exprTok2 = [...]int{
 	52, 53, 54, 55, 56, 57, 58, 59, 60, 61,
 	62, 63, 64, 65, 66, 67, 68, 69, 70, 71,
 	72, 73, 74, 75, 76, 77, 78, 79, 80, 81,
-	82, 83, 84, 85, 86, 87,
+	82, 83, 84, 85, 86, 87, 88, 89, 90, 91,
+	92, 93, 94, 95, 96, 97, 98, 99,
 }
-var exprTok3 = [...]int{
+
+var exprTok3 = [...]int8{
 	0,
 }
 
@@ -509,6 +581,7 @@ var exprErrorMessages = [...]struct {
 	msg   string
 }{}
 
+//line yaccpar:1
 
 /*	parser for yacc output	*/
 
@@ -541,7 +614,7 @@ func exprNewParser() exprParser {
 	return &exprParserImpl{}
 }
 
-const exprFlag = -1000
+const exprFlag = -32768
 
 func exprTokname(c int) string {
 	if c >= 1 && c-1 < len(exprToknames) {
@@ -580,9 +653,9 @@ func exprErrorMessage(state, lookAhead int) string {
 	expected := make([]int, 0, 4)
 
 	// Look for shiftable tokens.
-	base := exprPact[state]
+	base := int(exprPact[state])
 	for tok := TOKSTART; tok-1 < len(exprToknames); tok++ {
-		if n := base + tok; n >= 0 && n < exprLast && exprChk[exprAct[n]] == tok {
+		if n := base + tok; n >= 0 && n < exprLast && int(exprChk[int(exprAct[n])]) == tok {
 			if len(expected) == cap(expected) {
 				return res
 			}
@@ -592,13 +665,13 @@ func exprErrorMessage(state, lookAhead int) string {
 
 	if exprDef[state] == -2 {
 		i := 0
-		for exprExca[i] != -1 || exprExca[i+1] != state {
+		for exprExca[i] != -1 || int(exprExca[i+1]) != state {
 			i += 2
 		}
 
 		// Look for tokens that we accept or reduce.
 		for i += 2; exprExca[i] >= 0; i += 2 {
-			tok := exprExca[i]
+			tok := int(exprExca[i])
 			if tok < TOKSTART || exprExca[i+1] == 0 {
 				continue
 			}
@@ -629,30 +702,30 @@ func exprlex1(lex exprLexer, lval *exprSymType) (char, token int) {
 	token = 0
 	char = lex.Lex(lval)
 	if char <= 0 {
-		token = exprTok1[0]
+		token = int(exprTok1[0])
 		goto out
 	}
 	if char < len(exprTok1) {
-		token = exprTok1[char]
+		token = int(exprTok1[char])
 		goto out
 	}
 	if char >= exprPrivate {
 		if char < exprPrivate+len(exprTok2) {
-			token = exprTok2[char-exprPrivate]
+			token = int(exprTok2[char-exprPrivate])
 			goto out
 		}
 	}
 	for i := 0; i < len(exprTok3); i += 2 {
-		token = exprTok3[i+0]
+		token = int(exprTok3[i+0])
 		if token == char {
-			token = exprTok3[i+1]
+			token = int(exprTok3[i+1])
 			goto out
 		}
 	}
 
 out:
 	if token == 0 {
-		token = exprTok2[1] /* unknown char */
+		token = int(exprTok2[1]) /* unknown char */
 	}
 	if exprDebug >= 3 {
 		__yyfmt__.Printf("lex %s(%d)\n", exprTokname(token), uint(char))
@@ -707,7 +780,7 @@ exprstack:
 	exprS[exprp].yys = exprstate
 
 exprnewstate:
-	exprn = exprPact[exprstate]
+	exprn = int(exprPact[exprstate])
 	if exprn <= exprFlag {
 		goto exprdefault /* simple state */
 	}
@@ -718,8 +791,8 @@ exprnewstate:
 	if exprn < 0 || exprn >= exprLast {
 		goto exprdefault
 	}
-	exprn = exprAct[exprn]
-	if exprChk[exprn] == exprtoken { /* valid shift */
+	exprn = int(exprAct[exprn])
+	if int(exprChk[exprn]) == exprtoken { /* valid shift */
 		exprrcvr.char = -1
 		exprtoken = -1
 		exprVAL = exprrcvr.lval
@@ -732,7 +805,7 @@ exprnewstate:
 
 exprdefault:
 	/* default state action */
-	exprn = exprDef[exprstate]
+	exprn = int(exprDef[exprstate])
 	if exprn == -2 {
 		if exprrcvr.char < 0 {
 			exprrcvr.char, exprtoken = exprlex1(exprlex, &exprrcvr.lval)
@@ -741,18 +814,18 @@ exprdefault:
 		/* look through exception table */
 		xi := 0
 		for {
-			if exprExca[xi+0] == -1 && exprExca[xi+1] == exprstate {
+			if exprExca[xi+0] == -1 && int(exprExca[xi+1]) == exprstate {
 				break
 			}
 			xi += 2
 		}
 		for xi += 2; ; xi += 2 {
-			exprn = exprExca[xi+0]
+			exprn = int(exprExca[xi+0])
 			if exprn < 0 || exprn == exprtoken {
 				break
 			}
 		}
-		exprn = exprExca[xi+1]
+		exprn = int(exprExca[xi+1])
 		if exprn < 0 {
 			goto ret0
 		}
@@ -774,10 +847,10 @@ exprdefault:
 
 			/* find a state where "error" is a legal shift action */
 			for exprp >= 0 {
-				exprn = exprPact[exprS[exprp].yys] + exprErrCode
+				exprn = int(exprPact[exprS[exprp].yys]) + exprErrCode
 				if exprn >= 0 && exprn < exprLast {
-					exprstate = exprAct[exprn] /* simulate a shift of "error" */
-					if exprChk[exprstate] == exprErrCode {
+					exprstate = int(exprAct[exprn]) /* simulate a shift of "error" */
+					if int(exprChk[exprstate]) == exprErrCode {
 						goto exprstack
 					}
 				}
@@ -813,7 +886,7 @@ exprdefault:
 	exprpt := exprp
 	_ = exprpt // guard against "declared and not used"
 
-	exprp -= exprR2[exprn]
+	exprp -= int(exprR2[exprn])
 	// exprp is now the index of $0. Perform the default action. Iff the
 	// reduced production is ε, $1 is possibly out of range.
 	if exprp+1 >= len(exprS) {
@@ -824,16 +897,16 @@ exprdefault:
 	exprVAL = exprS[exprp+1]
 
 	/* consult goto table to find next state */
-	exprn = exprR1[exprn]
-	exprg := exprPgo[exprn]
+	exprn = int(exprR1[exprn])
+	exprg := int(exprPgo[exprn])
 	exprj := exprg + exprS[exprp].yys + 1
 
 	if exprj >= exprLast {
-		exprstate = exprAct[exprg]
+		exprstate = int(exprAct[exprg])
 	} else {
-		exprstate = exprAct[exprj]
-		if exprChk[exprstate] != -exprn {
-			exprstate = exprAct[exprg]
+		exprstate = int(exprAct[exprj])
+		if int(exprChk[exprstate]) != -exprn {
+			exprstate = int(exprAct[exprg])
 		}
 	}
 	// dummy call; replaced with literal code
@@ -841,1002 +914,1368 @@ exprdefault:
 
 	case 1:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:149
 		{
 			exprlex.(*parser).expr = exprDollar[1].Expr
 		}
 	case 2:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:152
 		{
 			exprVAL.Expr = exprDollar[1].LogExpr
 		}
 	case 3:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:153
 		{
 			exprVAL.Expr = exprDollar[1].MetricExpr
 		}
 	case 4:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:157
 		{
 			exprVAL.MetricExpr = exprDollar[1].RangeAggregationExpr
 		}
 	case 5:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:158
 		{
 			exprVAL.MetricExpr = exprDollar[1].VectorAggregationExpr
 		}
 	case 6:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:159
 		{
 			exprVAL.MetricExpr = exprDollar[1].BinOpExpr
 		}
 	case 7:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:160
 		{
 			exprVAL.MetricExpr = exprDollar[1].LiteralExpr
 		}
 	case 8:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:161
 		{
 			exprVAL.MetricExpr = exprDollar[1].LabelReplaceExpr
 		}
 	case 9:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:162
 		{
 			exprVAL.MetricExpr = exprDollar[1].VectorExpr
 		}
 	case 10:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:163
 		{
 			exprVAL.MetricExpr = exprDollar[2].MetricExpr
 		}
 	case 11:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:167
 		{
 			exprVAL.LogExpr = newMatcherExpr(exprDollar[1].Selector)
 		}
 	case 12:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
+//line expr.y:168
 		{
 			exprVAL.LogExpr = newPipelineExpr(newMatcherExpr(exprDollar[1].Selector), exprDollar[2].PipelineExpr)
 		}
 	case 13:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:169
 		{
 			exprVAL.LogExpr = exprDollar[2].LogExpr
 		}
 	case 14:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
+//line expr.y:173
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[1].Selector), exprDollar[2].duration, nil, nil)
 		}
 	case 15:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:174
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[1].Selector), exprDollar[2].duration, nil, exprDollar[3].OffsetExpr)
 		}
 	case 16:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
+//line expr.y:175
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[2].Selector), exprDollar[4].duration, nil, nil)
 		}
 	case 17:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
+//line expr.y:176
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[2].Selector), exprDollar[4].duration, nil, exprDollar[5].OffsetExpr)
 		}
 	case 18:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:177
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[1].Selector), exprDollar[2].duration, exprDollar[3].UnwrapExpr, nil)
 		}
 	case 19:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
+//line expr.y:178
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[1].Selector), exprDollar[2].duration, exprDollar[4].UnwrapExpr, exprDollar[3].OffsetExpr)
 		}
 	case 20:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
+//line expr.y:179
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[2].Selector), exprDollar[4].duration, exprDollar[5].UnwrapExpr, nil)
 		}
 	case 21:
 		exprDollar = exprS[exprpt-6 : exprpt+1]
+//line expr.y:180
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[2].Selector), exprDollar[4].duration, exprDollar[6].UnwrapExpr, exprDollar[5].OffsetExpr)
 		}
 	case 22:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:181
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[1].Selector), exprDollar[3].duration, exprDollar[2].UnwrapExpr, nil)
 		}
 	case 23:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
+//line expr.y:182
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[1].Selector), exprDollar[3].duration, exprDollar[2].UnwrapExpr, exprDollar[4].OffsetExpr)
 		}
 	case 24:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
+//line expr.y:183
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[2].Selector), exprDollar[5].duration, exprDollar[3].UnwrapExpr, nil)
 		}
 	case 25:
 		exprDollar = exprS[exprpt-6 : exprpt+1]
+//line expr.y:184
 		{
 			exprVAL.LogRangeExpr = newLogRange(newMatcherExpr(exprDollar[2].Selector), exprDollar[5].duration, exprDollar[3].UnwrapExpr, exprDollar[6].OffsetExpr)
 		}
 	case 26:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:185
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[1].Selector), exprDollar[2].PipelineExpr), exprDollar[3].duration, nil, nil)
 		}
 	case 27:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
+//line expr.y:186
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[1].Selector), exprDollar[2].PipelineExpr), exprDollar[3].duration, nil, exprDollar[4].OffsetExpr)
 		}
 	case 28:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
+//line expr.y:187
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[2].Selector), exprDollar[3].PipelineExpr), exprDollar[5].duration, nil, nil)
 		}
 	case 29:
 		exprDollar = exprS[exprpt-6 : exprpt+1]
+//line expr.y:188
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[2].Selector), exprDollar[3].PipelineExpr), exprDollar[5].duration, nil, exprDollar[6].OffsetExpr)
 		}
 	case 30:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
+//line expr.y:189
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[1].Selector), exprDollar[2].PipelineExpr), exprDollar[4].duration, exprDollar[3].UnwrapExpr, nil)
 		}
 	case 31:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
+//line expr.y:190
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[1].Selector), exprDollar[2].PipelineExpr), exprDollar[4].duration, exprDollar[3].UnwrapExpr, exprDollar[5].OffsetExpr)
 		}
 	case 32:
 		exprDollar = exprS[exprpt-6 : exprpt+1]
+//line expr.y:191
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[2].Selector), exprDollar[3].PipelineExpr), exprDollar[6].duration, exprDollar[4].UnwrapExpr, nil)
 		}
 	case 33:
 		exprDollar = exprS[exprpt-7 : exprpt+1]
+//line expr.y:192
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[2].Selector), exprDollar[3].PipelineExpr), exprDollar[6].duration, exprDollar[4].UnwrapExpr, exprDollar[7].OffsetExpr)
 		}
 	case 34:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:193
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[1].Selector), exprDollar[3].PipelineExpr), exprDollar[2].duration, nil, nil)
 		}
 	case 35:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
+//line expr.y:194
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[1].Selector), exprDollar[4].PipelineExpr), exprDollar[2].duration, nil, exprDollar[3].OffsetExpr)
 		}
 	case 36:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
+//line expr.y:195
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[1].Selector), exprDollar[3].PipelineExpr), exprDollar[2].duration, exprDollar[4].UnwrapExpr, nil)
 		}
 	case 37:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
+//line expr.y:196
 		{
 			exprVAL.LogRangeExpr = newLogRange(newPipelineExpr(newMatcherExpr(exprDollar[1].Selector), exprDollar[4].PipelineExpr), exprDollar[2].duration, exprDollar[5].UnwrapExpr, exprDollar[3].OffsetExpr)
 		}
 	case 38:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:197
 		{
 			exprVAL.LogRangeExpr = exprDollar[2].LogRangeExpr
 		}
 	case 40:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:202
 		{
 			exprVAL.UnwrapExpr = newUnwrapExpr(exprDollar[3].str, "")
 		}
 	case 41:
 		exprDollar = exprS[exprpt-6 : exprpt+1]
+//line expr.y:203
 		{
 			exprVAL.UnwrapExpr = newUnwrapExpr(exprDollar[5].str, exprDollar[3].ConvOp)
 		}
 	case 42:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:204
 		{
 			exprVAL.UnwrapExpr = exprDollar[1].UnwrapExpr.addPostFilter(exprDollar[3].LabelFilter)
 		}
 	case 43:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:208
 		{
 			exprVAL.ConvOp = OpConvBytes
 		}
 	case 44:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:209
 		{
 			exprVAL.ConvOp = OpConvDuration
 		}
 	case 45:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:210
 		{
 			exprVAL.ConvOp = OpConvDurationSeconds
 		}
 	case 46:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
+//line expr.y:214
 		{
 			exprVAL.RangeAggregationExpr = newRangeAggregationExpr(exprDollar[3].LogRangeExpr, exprDollar[1].RangeOp, nil, nil)
 		}
 	case 47:
 		exprDollar = exprS[exprpt-6 : exprpt+1]
+//line expr.y:215
 		{
 			exprVAL.RangeAggregationExpr = newRangeAggregationExpr(exprDollar[5].LogRangeExpr, exprDollar[1].RangeOp, nil, &exprDollar[3].str)
 		}
 	case 48:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
+//line expr.y:216
 		{
 			exprVAL.RangeAggregationExpr = newRangeAggregationExpr(exprDollar[3].LogRangeExpr, exprDollar[1].RangeOp, exprDollar[5].Grouping, nil)
 		}
 	case 49:
 		exprDollar = exprS[exprpt-7 : exprpt+1]
+//line expr.y:217
 		{
 			exprVAL.RangeAggregationExpr = newRangeAggregationExpr(exprDollar[5].LogRangeExpr, exprDollar[1].RangeOp, exprDollar[7].Grouping, &exprDollar[3].str)
 		}
 	case 50:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
+//line expr.y:218
 		{
-			exprVAL.VectorAggregationExpr = mustNewVectorAggregationExpr(exprDollar[3].MetricExpr, exprDollar[1].VectorOp, nil, nil)
+			exprVAL.RangeAggregationExpr = newSubqueryAggregationExpr(exprDollar[3].SubqueryExpr, exprDollar[1].str, nil, nil)
 		}
 	case 51:
+		exprDollar = exprS[exprpt-6 : exprpt+1]
+//line expr.y:219
+		{
+			exprVAL.RangeAggregationExpr = newSubqueryAggregationExpr(exprDollar[5].SubqueryExpr, exprDollar[1].str, nil, &exprDollar[3].str)
+		}
+	case 52:
+		exprDollar = exprS[exprpt-5 : exprpt+1]
+//line expr.y:220
+		{
+			exprVAL.RangeAggregationExpr = newSubqueryAggregationExpr(exprDollar[3].SubqueryExpr, exprDollar[1].str, exprDollar[5].Grouping, nil)
+		}
+	case 53:
+		exprDollar = exprS[exprpt-7 : exprpt+1]
+//line expr.y:221
+		{
+			exprVAL.RangeAggregationExpr = newSubqueryAggregationExpr(exprDollar[5].SubqueryExpr, exprDollar[1].str, exprDollar[7].Grouping, &exprDollar[3].str)
+		}
+	case 54:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+//line expr.y:225
+		{
+			exprVAL.SubqueryExpr = newSubqueryExpr(exprDollar[1].MetricExpr, exprDollar[2].subqueryRange, nil)
+		}
+	case 55:
+		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:226
+		{
+			exprVAL.SubqueryExpr = newSubqueryExpr(exprDollar[1].MetricExpr, exprDollar[2].subqueryRange, exprDollar[3].OffsetExpr)
+		}
+	case 56:
+		exprDollar = exprS[exprpt-4 : exprpt+1]
+//line expr.y:231
+		{
+			exprVAL.VectorAggregationExpr = mustNewVectorAggregationExpr(exprDollar[3].MetricExpr, exprDollar[1].VectorOp, nil, nil)
+		}
+	case 57:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
+//line expr.y:232
 		{
 			exprVAL.VectorAggregationExpr = mustNewVectorAggregationExpr(exprDollar[4].MetricExpr, exprDollar[1].VectorOp, exprDollar[2].Grouping, nil)
 		}
-	case 52:
+	case 58:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
+//line expr.y:233
 		{
 			exprVAL.VectorAggregationExpr = mustNewVectorAggregationExpr(exprDollar[3].MetricExpr, exprDollar[1].VectorOp, exprDollar[5].Grouping, nil)
 		}
-	case 53:
+	case 59:
 		exprDollar = exprS[exprpt-6 : exprpt+1]
+//line expr.y:235
 		{
 			exprVAL.VectorAggregationExpr = mustNewVectorAggregationExpr(exprDollar[5].MetricExpr, exprDollar[1].VectorOp, nil, &exprDollar[3].str)
 		}
-	case 54:
+	case 60:
 		exprDollar = exprS[exprpt-7 : exprpt+1]
+//line expr.y:236
 		{
 			exprVAL.VectorAggregationExpr = mustNewVectorAggregationExpr(exprDollar[5].MetricExpr, exprDollar[1].VectorOp, exprDollar[7].Grouping, &exprDollar[3].str)
 		}
-	case 55:
+	case 61:
 		exprDollar = exprS[exprpt-7 : exprpt+1]
+//line expr.y:237
 		{
 			exprVAL.VectorAggregationExpr = mustNewVectorAggregationExpr(exprDollar[6].MetricExpr, exprDollar[1].VectorOp, exprDollar[2].Grouping, &exprDollar[4].str)
 		}
-	case 56:
+	case 62:
+		exprDollar = exprS[exprpt-6 : exprpt+1]
+//line expr.y:239
+		{
+			exprVAL.VectorAggregationExpr = mustNewCountValuesExpr(exprDollar[5].MetricExpr, nil, exprDollar[3].str)
+		}
+	case 63:
+		exprDollar = exprS[exprpt-7 : exprpt+1]
+//line expr.y:240
+		{
+			exprVAL.VectorAggregationExpr = mustNewCountValuesExpr(exprDollar[5].MetricExpr, exprDollar[7].Grouping, exprDollar[3].str)
+		}
+	case 64:
+		exprDollar = exprS[exprpt-7 : exprpt+1]
+//line expr.y:241
+		{
+			exprVAL.VectorAggregationExpr = mustNewCountValuesExpr(exprDollar[6].MetricExpr, exprDollar[2].Grouping, exprDollar[4].str)
+		}
+	case 65:
 		exprDollar = exprS[exprpt-12 : exprpt+1]
+//line expr.y:246
 		{
 			exprVAL.LabelReplaceExpr = mustNewLabelReplaceExpr(exprDollar[3].MetricExpr, exprDollar[5].str, exprDollar[7].str, exprDollar[9].str, exprDollar[11].str)
 		}
-	case 57:
+	case 66:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:250
 		{
-			exprVAL.Filter = labels.MatchRegexp
+			exprVAL.Filter = log.LineMatchRegexp
 		}
-	case 58:
+	case 67:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:251
 		{
-			exprVAL.Filter = labels.MatchEqual
+			exprVAL.Filter = log.LineMatchEqual
 		}
-	case 59:
+	case 68:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:252
 		{
-			exprVAL.Filter = labels.MatchNotRegexp
+			exprVAL.Filter = log.LineMatchNotRegexp
 		}
-	case 60:
+	case 69:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:253
 		{
-			exprVAL.Filter = labels.MatchNotEqual
+			exprVAL.Filter = log.LineMatchNotEqual
 		}
-	case 61:
+	case 70:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:254
+		{
+			exprVAL.Filter = log.LineMatchPattern
+		}
+	case 71:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:255
+		{
+			exprVAL.Filter = log.LineMatchNotPattern
+		}
+	case 72:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:259
 		{
 			exprVAL.Selector = exprDollar[2].Matchers
 		}
-	case 62:
+	case 73:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:260
 		{
 			exprVAL.Selector = exprDollar[2].Matchers
 		}
-	case 63:
+	case 74:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
+//line expr.y:261
 		{
 		}
-	case 64:
+	case 75:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:265
 		{
 			exprVAL.Matchers = []*labels.Matcher{exprDollar[1].Matcher}
 		}
-	case 65:
+	case 76:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:266
 		{
 			exprVAL.Matchers = append(exprDollar[1].Matchers, exprDollar[3].Matcher)
 		}
-	case 66:
+	case 77:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:270
 		{
 			exprVAL.Matcher = mustNewMatcher(labels.MatchEqual, exprDollar[1].str, exprDollar[3].str)
 		}
-	case 67:
+	case 78:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:271
 		{
 			exprVAL.Matcher = mustNewMatcher(labels.MatchNotEqual, exprDollar[1].str, exprDollar[3].str)
 		}
-	case 68:
+	case 79:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:272
 		{
 			exprVAL.Matcher = mustNewMatcher(labels.MatchRegexp, exprDollar[1].str, exprDollar[3].str)
 		}
-	case 69:
+	case 80:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:273
 		{
 			exprVAL.Matcher = mustNewMatcher(labels.MatchNotRegexp, exprDollar[1].str, exprDollar[3].str)
 		}
-	case 70:
+	case 81:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:277
 		{
 			exprVAL.PipelineExpr = MultiStageExpr{exprDollar[1].PipelineStage}
 		}
-	case 71:
+	case 82:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
+//line expr.y:278
 		{
 			exprVAL.PipelineExpr = append(exprDollar[1].PipelineExpr, exprDollar[2].PipelineStage)
 		}
-	case 72:
+	case 83:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:282
 		{
 			exprVAL.PipelineStage = exprDollar[1].LineFilters
 		}
-	case 73:
+	case 84:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
+//line expr.y:283
 		{
 			exprVAL.PipelineStage = exprDollar[2].LabelParser
 		}
-	case 74:
+	case 85:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
+//line expr.y:284
 		{
 			exprVAL.PipelineStage = exprDollar[2].JSONExpressionParser
 		}
-	case 75:
+	case 86:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
+//line expr.y:285
 		{
 			exprVAL.PipelineStage = &LabelFilterExpr{LabelFilterer: exprDollar[2].LabelFilter}
 		}
-	case 76:
+	case 87:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
+//line expr.y:286
 		{
 			exprVAL.PipelineStage = exprDollar[2].LineFormatExpr
 		}
-	case 77:
+	case 88:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
+//line expr.y:287
 		{
 			exprVAL.PipelineStage = exprDollar[2].DecolorizeExpr
 		}
-	case 78:
+	case 89:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
+//line expr.y:288
 		{
 			exprVAL.PipelineStage = exprDollar[2].LabelFormatExpr
 		}
-	case 79:
+	case 90:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+//line expr.y:289
+		{
+			exprVAL.PipelineStage = exprDollar[2].DropLabelsExpr
+		}
+	case 91:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+//line expr.y:290
+		{
+			exprVAL.PipelineStage = exprDollar[2].KeepLabelsExpr
+		}
+	case 92:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:294
 		{
 			exprVAL.FilterOp = OpFilterIP
 		}
-	case 80:
+	case 93:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
+//line expr.y:298
 		{
 			exprVAL.LineFilter = newLineFilterExpr(exprDollar[1].Filter, "", exprDollar[2].str)
 		}
-	case 81:
+	case 94:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
+//line expr.y:299
 		{
 			exprVAL.LineFilter = newLineFilterExpr(exprDollar[1].Filter, exprDollar[2].FilterOp, exprDollar[4].str)
 		}
-	case 82:
+	case 95:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:303
 		{
 			exprVAL.LineFilters = exprDollar[1].LineFilter
 		}
-	case 83:
+	case 96:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
+//line expr.y:304
 		{
 			exprVAL.LineFilters = newNestedLineFilterExpr(exprDollar[1].LineFilters, exprDollar[2].LineFilter)
 		}
-	case 84:
+	case 97:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:308
 		{
 			exprVAL.LabelParser = newLabelParserExpr(OpParserTypeJSON, "")
 		}
-	case 85:
+	case 98:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:309
 		{
 			exprVAL.LabelParser = newLabelParserExpr(OpParserTypeLogfmt, "")
 		}
-	case 86:
+	case 99:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
+//line expr.y:310
 		{
 			exprVAL.LabelParser = newLabelParserExpr(OpParserTypeRegexp, exprDollar[2].str)
 		}
-	case 87:
+	case 100:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:311
 		{
 			exprVAL.LabelParser = newLabelParserExpr(OpParserTypeUnpack, "")
 		}
-	case 88:
+	case 101:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
+//line expr.y:312
 		{
 			exprVAL.LabelParser = newLabelParserExpr(OpParserTypePattern, exprDollar[2].str)
 		}
-	case 89:
+	case 102:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
+//line expr.y:316
 		{
 			exprVAL.JSONExpressionParser = newJSONExpressionParser(exprDollar[2].JSONExpressionList)
 		}
-	case 90:
+	case 103:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
+//line expr.y:318
 		{
 			exprVAL.LineFormatExpr = newLineFmtExpr(exprDollar[2].str)
 		}
-	case 91:
+	case 104:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:320
 		{
 			exprVAL.DecolorizeExpr = newDecolorizeExpr()
 		}
-	case 92:
+	case 105:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:323
 		{
 			exprVAL.LabelFormat = log.NewRenameLabelFmt(exprDollar[1].str, exprDollar[3].str)
 		}
-	case 93:
+	case 106:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:324
 		{
 			exprVAL.LabelFormat = log.NewTemplateLabelFmt(exprDollar[1].str, exprDollar[3].str)
 		}
-	case 94:
+	case 107:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:328
 		{
 			exprVAL.LabelsFormat = []log.LabelFmt{exprDollar[1].LabelFormat}
 		}
-	case 95:
+	case 108:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:329
 		{
 			exprVAL.LabelsFormat = append(exprDollar[1].LabelsFormat, exprDollar[3].LabelFormat)
 		}
-	case 97:
+	case 110:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
+//line expr.y:333
 		{
 			exprVAL.LabelFormatExpr = newLabelFmtExpr(exprDollar[2].LabelsFormat)
 		}
-	case 98:
+	case 111:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:336
 		{
 			exprVAL.LabelFilter = log.NewStringLabelFilter(exprDollar[1].Matcher)
 		}
-	case 99:
+	case 112:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:337
 		{
 			exprVAL.LabelFilter = exprDollar[1].IPLabelFilter
 		}
-	case 100:
+	case 113:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:338
 		{
 			exprVAL.LabelFilter = exprDollar[1].UnitFilter
 		}
-	case 101:
+	case 114:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:339
 		{
 			exprVAL.LabelFilter = exprDollar[1].NumberFilter
 		}
-	case 102:
+	case 115:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:340
 		{
 			exprVAL.LabelFilter = exprDollar[2].LabelFilter
 		}
-	case 103:
+	case 116:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
+//line expr.y:341
 		{
 			exprVAL.LabelFilter = log.NewAndLabelFilter(exprDollar[1].LabelFilter, exprDollar[2].LabelFilter)
 		}
-	case 104:
+	case 117:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:342
 		{
 			exprVAL.LabelFilter = log.NewAndLabelFilter(exprDollar[1].LabelFilter, exprDollar[3].LabelFilter)
 		}
-	case 105:
+	case 118:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:343
 		{
 			exprVAL.LabelFilter = log.NewAndLabelFilter(exprDollar[1].LabelFilter, exprDollar[3].LabelFilter)
 		}
-	case 106:
+	case 119:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:344
 		{
 			exprVAL.LabelFilter = log.NewOrLabelFilter(exprDollar[1].LabelFilter, exprDollar[3].LabelFilter)
 		}
-	case 107:
+	case 120:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:348
 		{
 			exprVAL.JSONExpression = log.NewJSONExpr(exprDollar[1].str, exprDollar[3].str)
 		}
-	case 108:
+	case 121:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:349
 		{
 			exprVAL.JSONExpression = log.NewJSONExpr(exprDollar[1].str, exprDollar[1].str)
 		}
-	case 109:
+	case 122:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:352
 		{
 			exprVAL.JSONExpressionList = []log.JSONExpression{exprDollar[1].JSONExpression}
 		}
-	case 110:
+	case 123:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:353
 		{
 			exprVAL.JSONExpressionList = append(exprDollar[1].JSONExpressionList, exprDollar[3].JSONExpression)
 		}
-	case 111:
+	case 124:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:357
+		{
+			exprVAL.DropLabel = log.NewDropLabel(nil, exprDollar[1].str)
+		}
+	case 125:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:358
+		{
+			exprVAL.DropLabel = log.NewDropLabel(exprDollar[1].Matcher, "")
+		}
+	case 126:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:362
+		{
+			exprVAL.DropLabels = []log.DropLabel{exprDollar[1].DropLabel}
+		}
+	case 127:
+		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:363
+		{
+			exprVAL.DropLabels = append(exprDollar[1].DropLabels, exprDollar[3].DropLabel)
+		}
+	case 128:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+//line expr.y:366
+		{
+			exprVAL.DropLabelsExpr = newDropLabelsExpr(exprDollar[2].DropLabels)
+		}
+	case 129:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:369
+		{
+			exprVAL.KeepLabel = log.NewKeepLabel(nil, exprDollar[1].str)
+		}
+	case 130:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:370
+		{
+			exprVAL.KeepLabel = log.NewKeepLabel(exprDollar[1].Matcher, "")
+		}
+	case 131:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:374
+		{
+			exprVAL.KeepLabels = []log.KeepLabel{exprDollar[1].KeepLabel}
+		}
+	case 132:
+		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:375
+		{
+			exprVAL.KeepLabels = append(exprDollar[1].KeepLabels, exprDollar[3].KeepLabel)
+		}
+	case 133:
+		exprDollar = exprS[exprpt-2 : exprpt+1]
+//line expr.y:378
+		{
+			exprVAL.KeepLabelsExpr = newKeepLabelsExpr(exprDollar[2].KeepLabels)
+		}
+	case 134:
 		exprDollar = exprS[exprpt-6 : exprpt+1]
+//line expr.y:381
 		{
 			exprVAL.IPLabelFilter = log.NewIPLabelFilter(exprDollar[5].str, exprDollar[1].str, log.LabelFilterEqual)
 		}
-	case 112:
+	case 135:
 		exprDollar = exprS[exprpt-6 : exprpt+1]
+//line expr.y:382
 		{
 			exprVAL.IPLabelFilter = log.NewIPLabelFilter(exprDollar[5].str, exprDollar[1].str, log.LabelFilterNotEqual)
 		}
-	case 113:
+	case 136:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:386
 		{
 			exprVAL.UnitFilter = exprDollar[1].DurationFilter
 		}
-	case 114:
+	case 137:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:387
 		{
 			exprVAL.UnitFilter = exprDollar[1].BytesFilter
 		}
-	case 115:
+	case 138:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:390
 		{
 			exprVAL.DurationFilter = log.NewDurationLabelFilter(log.LabelFilterGreaterThan, exprDollar[1].str, exprDollar[3].duration)
 		}
-	case 116:
+	case 139:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:391
 		{
 			exprVAL.DurationFilter = log.NewDurationLabelFilter(log.LabelFilterGreaterThanOrEqual, exprDollar[1].str, exprDollar[3].duration)
 		}
-	case 117:
+	case 140:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:392
 		{
 			exprVAL.DurationFilter = log.NewDurationLabelFilter(log.LabelFilterLesserThan, exprDollar[1].str, exprDollar[3].duration)
 		}
-	case 118:
+	case 141:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:393
 		{
 			exprVAL.DurationFilter = log.NewDurationLabelFilter(log.LabelFilterLesserThanOrEqual, exprDollar[1].str, exprDollar[3].duration)
 		}
-	case 119:
+	case 142:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:394
 		{
 			exprVAL.DurationFilter = log.NewDurationLabelFilter(log.LabelFilterNotEqual, exprDollar[1].str, exprDollar[3].duration)
 		}
-	case 120:
+	case 143:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:395
 		{
 			exprVAL.DurationFilter = log.NewDurationLabelFilter(log.LabelFilterEqual, exprDollar[1].str, exprDollar[3].duration)
 		}
-	case 121:
+	case 144:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:396
 		{
 			exprVAL.DurationFilter = log.NewDurationLabelFilter(log.LabelFilterEqual, exprDollar[1].str, exprDollar[3].duration)
 		}
-	case 122:
+	case 145:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:400
 		{
 			exprVAL.BytesFilter = log.NewBytesLabelFilter(log.LabelFilterGreaterThan, exprDollar[1].str, exprDollar[3].bytes)
 		}
-	case 123:
+	case 146:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:401
 		{
 			exprVAL.BytesFilter = log.NewBytesLabelFilter(log.LabelFilterGreaterThanOrEqual, exprDollar[1].str, exprDollar[3].bytes)
 		}
-	case 124:
+	case 147:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:402
 		{
 			exprVAL.BytesFilter = log.NewBytesLabelFilter(log.LabelFilterLesserThan, exprDollar[1].str, exprDollar[3].bytes)
 		}
-	case 125:
+	case 148:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:403
 		{
 			exprVAL.BytesFilter = log.NewBytesLabelFilter(log.LabelFilterLesserThanOrEqual, exprDollar[1].str, exprDollar[3].bytes)
 		}
-	case 126:
+	case 149:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:404
 		{
 			exprVAL.BytesFilter = log.NewBytesLabelFilter(log.LabelFilterNotEqual, exprDollar[1].str, exprDollar[3].bytes)
 		}
-	case 127:
+	case 150:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:405
 		{
 			exprVAL.BytesFilter = log.NewBytesLabelFilter(log.LabelFilterEqual, exprDollar[1].str, exprDollar[3].bytes)
 		}
-	case 128:
+	case 151:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:406
 		{
 			exprVAL.BytesFilter = log.NewBytesLabelFilter(log.LabelFilterEqual, exprDollar[1].str, exprDollar[3].bytes)
 		}
-	case 129:
+	case 152:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:410
 		{
 			exprVAL.NumberFilter = log.NewNumericLabelFilter(log.LabelFilterGreaterThan, exprDollar[1].str, mustNewFloat(exprDollar[3].str))
 		}
-	case 130:
+	case 153:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:411
 		{
 			exprVAL.NumberFilter = log.NewNumericLabelFilter(log.LabelFilterGreaterThanOrEqual, exprDollar[1].str, mustNewFloat(exprDollar[3].str))
 		}
-	case 131:
+	case 154:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:412
 		{
 			exprVAL.NumberFilter = log.NewNumericLabelFilter(log.LabelFilterLesserThan, exprDollar[1].str, mustNewFloat(exprDollar[3].str))
 		}
-	case 132:
+	case 155:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:413
 		{
 			exprVAL.NumberFilter = log.NewNumericLabelFilter(log.LabelFilterLesserThanOrEqual, exprDollar[1].str, mustNewFloat(exprDollar[3].str))
 		}
-	case 133:
+	case 156:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:414
 		{
 			exprVAL.NumberFilter = log.NewNumericLabelFilter(log.LabelFilterNotEqual, exprDollar[1].str, mustNewFloat(exprDollar[3].str))
 		}
-	case 134:
+	case 157:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:415
 		{
 			exprVAL.NumberFilter = log.NewNumericLabelFilter(log.LabelFilterEqual, exprDollar[1].str, mustNewFloat(exprDollar[3].str))
 		}
-	case 135:
+	case 158:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:416
 		{
 			exprVAL.NumberFilter = log.NewNumericLabelFilter(log.LabelFilterEqual, exprDollar[1].str, mustNewFloat(exprDollar[3].str))
 		}
-	case 136:
+	case 159:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
+//line expr.y:421
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("or", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 137:
+	case 160:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
+//line expr.y:422
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("and", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 138:
+	case 161:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
+//line expr.y:423
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("unless", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 139:
+	case 162:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
+//line expr.y:424
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("+", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 140:
+	case 163:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
+//line expr.y:425
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("-", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 141:
+	case 164:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
+//line expr.y:426
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("*", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 142:
+	case 165:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
+//line expr.y:427
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("/", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 143:
+	case 166:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
+//line expr.y:428
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("%", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 144:
+	case 167:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
+//line expr.y:429
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("^", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 145:
+	case 168:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
+//line expr.y:430
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("==", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 146:
+	case 169:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
+//line expr.y:431
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("!=", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 147:
+	case 170:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
+//line expr.y:432
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr(">", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 148:
+	case 171:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
+//line expr.y:433
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr(">=", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 149:
+	case 172:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
+//line expr.y:434
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("<", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 150:
+	case 173:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
+//line expr.y:435
 		{
 			exprVAL.BinOpExpr = mustNewBinOpExpr("<=", exprDollar[3].BinOpModifier, exprDollar[1].Expr, exprDollar[4].Expr)
 		}
-	case 151:
+	case 174:
 		exprDollar = exprS[exprpt-0 : exprpt+1]
+//line expr.y:439
 		{
 			exprVAL.BoolModifier = &BinOpOptions{VectorMatching: &VectorMatching{Card: CardOneToOne}}
 		}
-	case 152:
+	case 175:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:443
 		{
 			exprVAL.BoolModifier = &BinOpOptions{VectorMatching: &VectorMatching{Card: CardOneToOne}, ReturnBool: true}
 		}
-	case 153:
+	case 176:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
+//line expr.y:450
 		{
 			exprVAL.OnOrIgnoringModifier = exprDollar[1].BoolModifier
 			exprVAL.OnOrIgnoringModifier.VectorMatching.On = true
 			exprVAL.OnOrIgnoringModifier.VectorMatching.MatchingLabels = exprDollar[4].Labels
 		}
-	case 154:
+	case 177:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
+//line expr.y:456
 		{
 			exprVAL.OnOrIgnoringModifier = exprDollar[1].BoolModifier
 			exprVAL.OnOrIgnoringModifier.VectorMatching.On = true
 		}
-	case 155:
+	case 178:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
+//line expr.y:461
 		{
 			exprVAL.OnOrIgnoringModifier = exprDollar[1].BoolModifier
 			exprVAL.OnOrIgnoringModifier.VectorMatching.MatchingLabels = exprDollar[4].Labels
 		}
-	case 156:
+	case 179:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
+//line expr.y:466
 		{
 			exprVAL.OnOrIgnoringModifier = exprDollar[1].BoolModifier
 		}
-	case 157:
+	case 180:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:472
 		{
 			exprVAL.BinOpModifier = exprDollar[1].BoolModifier
 		}
-	case 158:
+	case 181:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:473
 		{
 			exprVAL.BinOpModifier = exprDollar[1].OnOrIgnoringModifier
 		}
-	case 159:
+	case 182:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
+//line expr.y:475
 		{
 			exprVAL.BinOpModifier = exprDollar[1].OnOrIgnoringModifier
 			exprVAL.BinOpModifier.VectorMatching.Card = CardManyToOne
 		}
-	case 160:
+	case 183:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
+//line expr.y:480
 		{
 			exprVAL.BinOpModifier = exprDollar[1].OnOrIgnoringModifier
 			exprVAL.BinOpModifier.VectorMatching.Card = CardManyToOne
 		}
-	case 161:
+	case 184:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
+//line expr.y:485
 		{
 			exprVAL.BinOpModifier = exprDollar[1].OnOrIgnoringModifier
 			exprVAL.BinOpModifier.VectorMatching.Card = CardManyToOne
 			exprVAL.BinOpModifier.VectorMatching.Include = exprDollar[4].Labels
 		}
-	case 162:
+	case 185:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
+//line expr.y:491
 		{
 			exprVAL.BinOpModifier = exprDollar[1].OnOrIgnoringModifier
 			exprVAL.BinOpModifier.VectorMatching.Card = CardOneToMany
 		}
-	case 163:
+	case 186:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
+//line expr.y:496
 		{
 			exprVAL.BinOpModifier = exprDollar[1].OnOrIgnoringModifier
 			exprVAL.BinOpModifier.VectorMatching.Card = CardOneToMany
 		}
-	case 164:
+	case 187:
 		exprDollar = exprS[exprpt-5 : exprpt+1]
+//line expr.y:501
 		{
 			exprVAL.BinOpModifier = exprDollar[1].OnOrIgnoringModifier
 			exprVAL.BinOpModifier.VectorMatching.Card = CardOneToMany
 			exprVAL.BinOpModifier.VectorMatching.Include = exprDollar[4].Labels
 		}
-	case 165:
+	case 188:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:509
 		{
 			exprVAL.LiteralExpr = mustNewLiteralExpr(exprDollar[1].str, false)
 		}
-	case 166:
+	case 189:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
+//line expr.y:510
 		{
 			exprVAL.LiteralExpr = mustNewLiteralExpr(exprDollar[2].str, false)
 		}
-	case 167:
+	case 190:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
+//line expr.y:511
 		{
 			exprVAL.LiteralExpr = mustNewLiteralExpr(exprDollar[2].str, true)
 		}
-	case 168:
+	case 191:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
+//line expr.y:515
 		{
 			exprVAL.VectorExpr = NewVectorExpr(exprDollar[3].str)
 		}
-	case 169:
+	case 192:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:518
 		{
 			exprVAL.Vector = OpTypeVector
 		}
-	case 170:
+	case 193:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:522
 		{
 			exprVAL.VectorOp = OpTypeSum
 		}
-	case 171:
+	case 194:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:523
 		{
 			exprVAL.VectorOp = OpTypeAvg
 		}
-	case 172:
+	case 195:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:524
 		{
 			exprVAL.VectorOp = OpTypeCount
 		}
-	case 173:
+	case 196:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:525
 		{
 			exprVAL.VectorOp = OpTypeMax
 		}
-	case 174:
+	case 197:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:526
 		{
 			exprVAL.VectorOp = OpTypeMin
 		}
-	case 175:
+	case 198:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:527
 		{
 			exprVAL.VectorOp = OpTypeStddev
 		}
-	case 176:
+	case 199:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:528
 		{
 			exprVAL.VectorOp = OpTypeStdvar
 		}
-	case 177:
+	case 200:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:529
 		{
 			exprVAL.VectorOp = OpTypeBottomK
 		}
-	case 178:
+	case 201:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:530
 		{
 			exprVAL.VectorOp = OpTypeTopK
 		}
-	case 179:
+	case 202:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:531
+		{
+			exprVAL.VectorOp = OpTypeSort
+		}
+	case 203:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:532
+		{
+			exprVAL.VectorOp = OpTypeSortDesc
+		}
+	case 204:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:533
+		{
+			exprVAL.VectorOp = OpTypeCountMinSketch
+		}
+	case 205:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:534
+		{
+			exprVAL.VectorOp = OpTypeHyperLogLog
+		}
+	case 206:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:538
 		{
 			exprVAL.RangeOp = OpRangeTypeCount
 		}
-	case 180:
+	case 207:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:539
 		{
 			exprVAL.RangeOp = OpRangeTypeRate
 		}
-	case 181:
+	case 208:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:540
 		{
 			exprVAL.RangeOp = OpRangeTypeRateCounter
 		}
-	case 182:
+	case 209:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:541
 		{
 			exprVAL.RangeOp = OpRangeTypeBytes
 		}
-	case 183:
+	case 210:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:542
 		{
 			exprVAL.RangeOp = OpRangeTypeBytesRate
 		}
-	case 184:
+	case 211:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:543
 		{
 			exprVAL.RangeOp = OpRangeTypeAvg
 		}
-	case 185:
+	case 212:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:544
 		{
 			exprVAL.RangeOp = OpRangeTypeSum
 		}
-	case 186:
+	case 213:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:545
 		{
 			exprVAL.RangeOp = OpRangeTypeMin
 		}
-	case 187:
+	case 214:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:546
 		{
 			exprVAL.RangeOp = OpRangeTypeMax
 		}
-	case 188:
+	case 215:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:547
 		{
 			exprVAL.RangeOp = OpRangeTypeStdvar
 		}
-	case 189:
+	case 216:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:548
 		{
 			exprVAL.RangeOp = OpRangeTypeStddev
 		}
-	case 190:
+	case 217:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:549
 		{
 			exprVAL.RangeOp = OpRangeTypeQuantile
 		}
-	case 191:
+	case 218:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:550
+		{
+			exprVAL.RangeOp = OpRangeTypeQuantileSketch
+		}
+	case 219:
+		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:551
 		{
 			exprVAL.RangeOp = OpRangeTypeFirst
 		}
-	case 192:
+	case 220:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:552
 		{
 			exprVAL.RangeOp = OpRangeTypeLast
 		}
-	case 193:
+	case 221:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:553
 		{
 			exprVAL.RangeOp = OpRangeTypeAbsent
 		}
-	case 194:
+	case 222:
 		exprDollar = exprS[exprpt-2 : exprpt+1]
+//line expr.y:557
 		{
 			exprVAL.OffsetExpr = newOffsetExpr(exprDollar[2].duration)
 		}
-	case 195:
+	case 223:
 		exprDollar = exprS[exprpt-1 : exprpt+1]
+//line expr.y:560
 		{
 			exprVAL.Labels = []string{exprDollar[1].str}
 		}
-	case 196:
+	case 224:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:561
 		{
 			exprVAL.Labels = append(exprDollar[1].Labels, exprDollar[3].str)
 		}
-	case 197:
+	case 225:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
+//line expr.y:565
 		{
 			exprVAL.Grouping = &Grouping{Without: false, Groups: exprDollar[3].Labels}
 		}
-	case 198:
+	case 226:
 		exprDollar = exprS[exprpt-4 : exprpt+1]
+//line expr.y:566
 		{
 			exprVAL.Grouping = &Grouping{Without: true, Groups: exprDollar[3].Labels}
 		}
-	case 199:
+	case 227:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:567
 		{
 			exprVAL.Grouping = &Grouping{Without: false, Groups: nil}
 		}
-	case 200:
+	case 228:
 		exprDollar = exprS[exprpt-3 : exprpt+1]
+//line expr.y:568
 		{
 			exprVAL.Grouping = &Grouping{Without: true, Groups: nil}
 		}
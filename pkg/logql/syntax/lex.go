@@ -25,6 +25,8 @@ var tokens = map[string]int{
 	"!~":           NRE,
 	"|=":           PIPE_EXACT,
 	"|~":           PIPE_MATCH,
+	"|>":           PIPE_PATTERN,
+	"!>":           NPA,
 	OpPipe:         PIPE,
 	OpUnwrap:       UNWRAP,
 	"(":            OPEN_PARENTHESIS,
@@ -72,6 +74,10 @@ var tokens = map[string]int{
 	// filter functions
 	OpFilterIP:   IP,
 	OpDecolorize: DECOLORIZE,
+
+	// drop/keep labels
+	OpDrop: DROP,
+	OpKeep: KEEP,
 }
 
 // functionTokens are tokens that needs to be suffixes with parenthesis
@@ -94,17 +100,27 @@ var functionTokens = map[string]int{
 	OpRangeTypeAbsent:      ABSENT_OVER_TIME,
 	OpTypeVector:           VECTOR,
 
+	// internal range vec ops
+	OpRangeTypeQuantileSketch: QUANTILE_SKETCH_OVER_TIME,
+
+	// internal vec ops
+	OpTypeCountMinSketch: COUNT_MIN_SKETCH,
+	OpTypeHyperLogLog:    HYPERLOGLOG,
+
 	// vec ops
-	OpTypeSum:      SUM,
-	OpTypeAvg:      AVG,
-	OpTypeMax:      MAX,
-	OpTypeMin:      MIN,
-	OpTypeCount:    COUNT,
-	OpTypeStddev:   STDDEV,
-	OpTypeStdvar:   STDVAR,
-	OpTypeBottomK:  BOTTOMK,
-	OpTypeTopK:     TOPK,
-	OpLabelReplace: LABEL_REPLACE,
+	OpTypeSum:         SUM,
+	OpTypeAvg:         AVG,
+	OpTypeMax:         MAX,
+	OpTypeMin:         MIN,
+	OpTypeCount:       COUNT,
+	OpTypeStddev:      STDDEV,
+	OpTypeStdvar:      STDVAR,
+	OpTypeBottomK:     BOTTOMK,
+	OpTypeTopK:        TOPK,
+	OpTypeSort:        SORT,
+	OpTypeSortDesc:    SORT_DESC,
+	OpTypeCountValues: COUNT_VALUES,
+	OpLabelReplace:    LABEL_REPLACE,
 
 	// conversion Op
 	OpConvBytes:           BYTES_CONV,
@@ -115,10 +131,33 @@ var functionTokens = map[string]int{
 	OpFilterIP: IP,
 }
 
+// rangeOpTokens are the range vector operations, which aggregate either a log range or a subquery.
+var rangeOpTokens = map[int]struct{}{
+	COUNT_OVER_TIME:           {},
+	RATE:                      {},
+	RATE_COUNTER:              {},
+	BYTES_OVER_TIME:           {},
+	BYTES_RATE:                {},
+	AVG_OVER_TIME:             {},
+	SUM_OVER_TIME:             {},
+	MIN_OVER_TIME:             {},
+	MAX_OVER_TIME:             {},
+	STDVAR_OVER_TIME:          {},
+	STDDEV_OVER_TIME:          {},
+	QUANTILE_OVER_TIME:        {},
+	QUANTILE_SKETCH_OVER_TIME: {},
+	FIRST_OVER_TIME:           {},
+	LAST_OVER_TIME:            {},
+	ABSENT_OVER_TIME:          {},
+}
+
 type lexer struct {
 	scanner.Scanner
 	errs    []logqlmodel.ParseError
 	builder strings.Builder
+
+	// input is the whole input being scanned, to look ahead of the scanner.
+	input string
 }
 
 func (l *lexer) Lex(lval *exprSymType) int {
@@ -173,6 +212,9 @@ func (l *lexer) Lex(lval *exprSymType) int {
 		l.builder.Reset()
 		for r := l.Next(); r != scanner.EOF; r = l.Next() {
 			if r == ']' {
+				if rng, step, ok := strings.Cut(l.builder.String(), ":"); ok {
+					return l.lexSubqueryRange(lval, rng, step)
+				}
 				i, err := model.ParseDuration(l.builder.String())
 				if err != nil {
 					l.Error(err.Error())
@@ -204,6 +246,10 @@ func (l *lexer) Lex(lval *exprSymType) int {
 			lval.str = tokenText
 			return IDENTIFIER
 		}
+		if _, ok := rangeOpTokens[tok]; ok && l.isSubqueryAggregation() {
+			lval.str = tokenText
+			return SUBQUERY_RANGE_OP
+		}
 		return tok
 	}
 
@@ -220,6 +266,66 @@ func (l *lexer) Lex(lval *exprSymType) int {
 	return IDENTIFIER
 }
 
+// lexSubqueryRange scans the `range:step` durations of a subquery, the step being optional.
+func (l *lexer) lexSubqueryRange(lval *exprSymType, rng, step string) int {
+	r, err := model.ParseDuration(rng)
+	if err != nil {
+		l.Error(err.Error())
+		return 0
+	}
+	lval.subqueryRange = SubqueryRange{Range: time.Duration(r)}
+	if step != "" {
+		s, err := model.ParseDuration(step)
+		if err != nil {
+			l.Error(err.Error())
+			return 0
+		}
+		lval.subqueryRange.Step = time.Duration(s)
+	}
+	return SUBQUERY_RANGE
+}
+
+// isSubqueryAggregation reports whether the parenthesis following the range operation scanned last hold a subquery,
+// as in `max_over_time(rate({app="foo"}[1m])[1h:1m])`. The range operations of the subqueries get their own token,
+// so that the parser keeps expecting a log range after the other range operations.
+func (l *lexer) isSubqueryAggregation() bool {
+	offset := l.Pos().Offset
+	if offset > len(l.input) {
+		return false
+	}
+
+	var sc scanner.Scanner
+	sc.Init(strings.NewReader(l.input[offset:]))
+	sc.Error = func(_ *scanner.Scanner, _ string) {}
+
+	depth := 0
+	for r := sc.Scan(); r != scanner.EOF; r = sc.Scan() {
+		switch r {
+		case '#':
+			for next := sc.Peek(); !(next == '\n' || next == scanner.EOF); next = sc.Next() {
+			}
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth <= 0 {
+				return false
+			}
+		case '[':
+			if depth != 1 {
+				continue
+			}
+			// Subqueries are the only ranges with a step.
+			for next := sc.Next(); next != ']' && next != scanner.EOF; next = sc.Next() {
+				if next == ':' {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
 func (l *lexer) Error(msg string) {
 	l.errs = append(l.errs, logqlmodel.NewParseError(msg, l.Line, l.Column))
 }
@@ -97,6 +97,7 @@ func parseExprWithoutValidation(input string) (expr Expr, err error) {
 
 	p.Reader.Reset(input)
 	p.lexer.Init(p.Reader)
+	p.lexer.input = input
 	return p.Parse()
 }
 
@@ -167,6 +168,8 @@ func validateSampleExpr(expr SampleExpr) error {
 		}
 
 		return validateSampleExpr(e.RHS)
+	case *SubqueryExpr:
+		return validateSampleExpr(e.Left)
 	case *LiteralExpr, *VectorExpr:
 		return nil
 	default:
@@ -31,7 +31,7 @@ func TestParse(t *testing.T) {
 				Left: &LogRange{
 					Left: &PipelineExpr{
 						MultiStages: MultiStageExpr{
-							newLineFilterExpr(labels.MatchRegexp, "", "error\\"),
+							newLineFilterExpr(log.LineMatchRegexp, "", "error\\"),
 						},
 						Left: &MatchersExpr{
 							Mts: []*labels.Matcher{
@@ -52,6 +52,82 @@ func TestParse(t *testing.T) {
 				},
 			),
 		},
+		{
+			in: `{app="nginx"} |> "<_> - - <_> \"GET <_>\" 500 <_>" !> "<_> /healthz <_>"`,
+			exp: newPipelineExpr(
+				newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "app", "nginx")}),
+				MultiStageExpr{
+					newNestedLineFilterExpr(
+						newLineFilterExpr(log.LineMatchPattern, "", `<_> - - <_> "GET <_>" 500 <_>`),
+						newLineFilterExpr(log.LineMatchNotPattern, "", `<_> /healthz <_>`),
+					),
+				},
+			),
+		},
+		{
+			in: `count_over_time({app="nginx"} |= "GET" |> "<_> 500 <_>" [5m])`,
+			exp: newRangeAggregationExpr(
+				newLogRange(
+					newPipelineExpr(
+						newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "app", "nginx")}),
+						MultiStageExpr{
+							newNestedLineFilterExpr(
+								newLineFilterExpr(log.LineMatchEqual, "", "GET"),
+								newLineFilterExpr(log.LineMatchPattern, "", "<_> 500 <_>"),
+							),
+						},
+					),
+					5*time.Minute, nil, nil),
+				OpRangeTypeCount, nil, nil),
+		},
+		{
+			in: `{app="foo"} | json | drop foo, bar="buzz", __error__`,
+			exp: newPipelineExpr(
+				newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "app", "foo")}),
+				MultiStageExpr{
+					newLabelParserExpr(OpParserTypeJSON, ""),
+					newDropLabelsExpr([]log.DropLabel{
+						log.NewDropLabel(nil, "foo"),
+						log.NewDropLabel(mustNewMatcher(labels.MatchEqual, "bar", "buzz"), ""),
+						log.NewDropLabel(nil, "__error__"),
+					}),
+				},
+			),
+		},
+		{
+			in: `{app="foo"} | logfmt | keep foo, bar=~"b.*"`,
+			exp: newPipelineExpr(
+				newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "app", "foo")}),
+				MultiStageExpr{
+					newLabelParserExpr(OpParserTypeLogfmt, ""),
+					newKeepLabelsExpr([]log.KeepLabel{
+						log.NewKeepLabel(nil, "foo"),
+						log.NewKeepLabel(mustNewMatcher(labels.MatchRegexp, "bar", "b.*"), ""),
+					}),
+				},
+			),
+		},
+		{
+			in: `sum by (foo) (count_over_time({app="foo"} | json | drop bar!="buzz" | keep foo [5m]))`,
+			exp: mustNewVectorAggregationExpr(
+				newRangeAggregationExpr(
+					newLogRange(
+						newPipelineExpr(
+							newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "app", "foo")}),
+							MultiStageExpr{
+								newLabelParserExpr(OpParserTypeJSON, ""),
+								newDropLabelsExpr([]log.DropLabel{
+									log.NewDropLabel(mustNewMatcher(labels.MatchNotEqual, "bar", "buzz"), ""),
+								}),
+								newKeepLabelsExpr([]log.KeepLabel{
+									log.NewKeepLabel(nil, "foo"),
+								}),
+							},
+						),
+						5*time.Minute, nil, nil),
+					OpRangeTypeCount, nil, nil),
+				OpTypeSum, &Grouping{Groups: []string{"foo"}}, nil),
+		},
 		{
 			// test [12h] before filter expr
 			in: `count_over_time({foo="bar"}[12h] |= "error")`,
@@ -61,7 +137,7 @@ func TestParse(t *testing.T) {
 					Left: newPipelineExpr(
 						newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "foo", Value: "bar"}}),
 						MultiStageExpr{
-							newLineFilterExpr(labels.MatchEqual, "", "error"),
+							newLineFilterExpr(log.LineMatchEqual, "", "error"),
 						},
 					),
 					Interval: 12 * time.Hour,
@@ -76,7 +152,7 @@ func TestParse(t *testing.T) {
 				Left: &LogRange{
 					Left: newPipelineExpr(
 						newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "foo", Value: "bar"}}),
-						MultiStageExpr{newLineFilterExpr(labels.MatchEqual, "", "error")},
+						MultiStageExpr{newLineFilterExpr(log.LineMatchEqual, "", "error")},
 					),
 					Interval: 12 * time.Hour,
 				},
@@ -374,8 +450,8 @@ func TestParse(t *testing.T) {
 				newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "foo", "bar")}),
 				MultiStageExpr{
 					newNestedLineFilterExpr(
-						newLineFilterExpr(labels.MatchEqual, "", "baz"),
-						newLineFilterExpr(labels.MatchEqual, OpFilterIP, "123.123.123.123"),
+						newLineFilterExpr(log.LineMatchEqual, "", "baz"),
+						newLineFilterExpr(log.LineMatchEqual, OpFilterIP, "123.123.123.123"),
 					),
 				},
 			),
@@ -386,7 +462,7 @@ func TestParse(t *testing.T) {
 				newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "foo", "bar"), mustNewMatcher(labels.MatchEqual, "ip", "foo")}),
 				MultiStageExpr{
 					newLabelParserExpr(OpParserTypeLogfmt, ""),
-					newLineFilterExpr(labels.MatchEqual, OpFilterIP, "127.0.0.1"),
+					newLineFilterExpr(log.LineMatchEqual, OpFilterIP, "127.0.0.1"),
 					newLabelFilterExpr(log.NewStringLabelFilter(mustNewMatcher(labels.MatchEqual, "ip", "2.3.4.5"))),
 					newLabelFilterExpr(log.NewStringLabelFilter(mustNewMatcher(labels.MatchEqual, "ip", "abc"))),
 					newLabelFilterExpr(log.NewIPLabelFilter("4.5.6.7", "ipaddr", log.LabelFilterEqual)),
@@ -399,7 +475,7 @@ func TestParse(t *testing.T) {
 			exp: newPipelineExpr(
 				newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "foo", "bar")}),
 				MultiStageExpr{
-					newLineFilterExpr(labels.MatchEqual, OpFilterIP, "123.123.123.123"),
+					newLineFilterExpr(log.LineMatchEqual, OpFilterIP, "123.123.123.123"),
 				},
 			),
 		},
@@ -409,8 +485,8 @@ func TestParse(t *testing.T) {
 				newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "foo", "bar")}),
 				MultiStageExpr{
 					newNestedLineFilterExpr(
-						newLineFilterExpr(labels.MatchEqual, OpFilterIP, "123.123.123.123"),
-						newLineFilterExpr(labels.MatchEqual, "", "baz"),
+						newLineFilterExpr(log.LineMatchEqual, OpFilterIP, "123.123.123.123"),
+						newLineFilterExpr(log.LineMatchEqual, "", "baz"),
 					),
 				},
 			),
@@ -422,10 +498,10 @@ func TestParse(t *testing.T) {
 				MultiStageExpr{
 					newNestedLineFilterExpr(
 						newNestedLineFilterExpr(
-							newLineFilterExpr(labels.MatchEqual, OpFilterIP, "123.123.123.123"),
-							newLineFilterExpr(labels.MatchEqual, "", "baz"),
+							newLineFilterExpr(log.LineMatchEqual, OpFilterIP, "123.123.123.123"),
+							newLineFilterExpr(log.LineMatchEqual, "", "baz"),
 						),
-						newLineFilterExpr(labels.MatchEqual, OpFilterIP, "123.123.123.123"),
+						newLineFilterExpr(log.LineMatchEqual, OpFilterIP, "123.123.123.123"),
 					),
 				},
 			),
@@ -436,8 +512,8 @@ func TestParse(t *testing.T) {
 				newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "foo", "bar")}),
 				MultiStageExpr{
 					newNestedLineFilterExpr(
-						newLineFilterExpr(labels.MatchEqual, "", "baz"),
-						newLineFilterExpr(labels.MatchEqual, OpFilterIP, "123.123.123.123"),
+						newLineFilterExpr(log.LineMatchEqual, "", "baz"),
+						newLineFilterExpr(log.LineMatchEqual, OpFilterIP, "123.123.123.123"),
 					),
 				},
 			),
@@ -447,7 +523,7 @@ func TestParse(t *testing.T) {
 			exp: newPipelineExpr(
 				newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "foo", "bar")}),
 				MultiStageExpr{
-					newLineFilterExpr(labels.MatchNotEqual, OpFilterIP, "123.123.123.123"),
+					newLineFilterExpr(log.LineMatchNotEqual, OpFilterIP, "123.123.123.123"),
 				},
 			),
 		},
@@ -457,8 +533,8 @@ func TestParse(t *testing.T) {
 				newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "foo", "bar")}),
 				MultiStageExpr{
 					newNestedLineFilterExpr(
-						newLineFilterExpr(labels.MatchNotEqual, OpFilterIP, "123.123.123.123"),
-						newLineFilterExpr(labels.MatchEqual, "", "baz"),
+						newLineFilterExpr(log.LineMatchNotEqual, OpFilterIP, "123.123.123.123"),
+						newLineFilterExpr(log.LineMatchEqual, "", "baz"),
 					),
 				},
 			),
@@ -470,10 +546,10 @@ func TestParse(t *testing.T) {
 				MultiStageExpr{
 					newNestedLineFilterExpr(
 						newNestedLineFilterExpr(
-							newLineFilterExpr(labels.MatchNotEqual, OpFilterIP, "123.123.123.123"),
-							newLineFilterExpr(labels.MatchEqual, "", "baz"),
+							newLineFilterExpr(log.LineMatchNotEqual, OpFilterIP, "123.123.123.123"),
+							newLineFilterExpr(log.LineMatchEqual, "", "baz"),
 						),
-						newLineFilterExpr(labels.MatchNotEqual, OpFilterIP, "123.123.123.123"),
+						newLineFilterExpr(log.LineMatchNotEqual, OpFilterIP, "123.123.123.123"),
 					),
 				},
 			),
@@ -644,7 +720,7 @@ func TestParse(t *testing.T) {
 			in: `{foo="bar"} |= "baz"`,
 			exp: newPipelineExpr(
 				newMatcherExpr([]*labels.Matcher{mustNewMatcher(labels.MatchEqual, "foo", "bar")}),
-				MultiStageExpr{newLineFilterExpr(labels.MatchEqual, "", "baz")},
+				MultiStageExpr{newLineFilterExpr(log.LineMatchEqual, "", "baz")},
 			),
 		},
 		{
@@ -655,12 +731,12 @@ func TestParse(t *testing.T) {
 					newNestedLineFilterExpr(
 						newNestedLineFilterExpr(
 							newNestedLineFilterExpr(
-								newLineFilterExpr(labels.MatchEqual, "", "baz"),
-								newLineFilterExpr(labels.MatchRegexp, "", "blip"),
+								newLineFilterExpr(log.LineMatchEqual, "", "baz"),
+								newLineFilterExpr(log.LineMatchRegexp, "", "blip"),
 							),
-							newLineFilterExpr(labels.MatchNotEqual, "", "flip"),
+							newLineFilterExpr(log.LineMatchNotEqual, "", "flip"),
 						),
-						newLineFilterExpr(labels.MatchNotRegexp, "", "flap"),
+						newLineFilterExpr(log.LineMatchNotRegexp, "", "flap"),
 					),
 				},
 			),
@@ -675,12 +751,12 @@ func TestParse(t *testing.T) {
 							newNestedLineFilterExpr(
 								newNestedLineFilterExpr(
 									newNestedLineFilterExpr(
-										newLineFilterExpr(labels.MatchEqual, "", "baz"),
-										newLineFilterExpr(labels.MatchRegexp, "", "blip"),
+										newLineFilterExpr(log.LineMatchEqual, "", "baz"),
+										newLineFilterExpr(log.LineMatchRegexp, "", "blip"),
 									),
-									newLineFilterExpr(labels.MatchNotEqual, "", "flip"),
+									newLineFilterExpr(log.LineMatchNotEqual, "", "flip"),
 								),
-								newLineFilterExpr(labels.MatchNotRegexp, "", "flap"),
+								newLineFilterExpr(log.LineMatchNotRegexp, "", "flap"),
 							),
 						},
 					),
@@ -697,12 +773,12 @@ func TestParse(t *testing.T) {
 							newNestedLineFilterExpr(
 								newNestedLineFilterExpr(
 									newNestedLineFilterExpr(
-										newLineFilterExpr(labels.MatchEqual, "", "baz"),
-										newLineFilterExpr(labels.MatchRegexp, "", "blip"),
+										newLineFilterExpr(log.LineMatchEqual, "", "baz"),
+										newLineFilterExpr(log.LineMatchRegexp, "", "blip"),
 									),
-									newLineFilterExpr(labels.MatchNotEqual, "", "flip"),
+									newLineFilterExpr(log.LineMatchNotEqual, "", "flip"),
 								),
-								newLineFilterExpr(labels.MatchNotRegexp, "", "flap"),
+								newLineFilterExpr(log.LineMatchNotRegexp, "", "flap"),
 							),
 						},
 					),
@@ -719,12 +795,12 @@ func TestParse(t *testing.T) {
 							newNestedLineFilterExpr(
 								newNestedLineFilterExpr(
 									newNestedLineFilterExpr(
-										newLineFilterExpr(labels.MatchEqual, "", "baz"),
-										newLineFilterExpr(labels.MatchRegexp, "", "blip"),
+										newLineFilterExpr(log.LineMatchEqual, "", "baz"),
+										newLineFilterExpr(log.LineMatchRegexp, "", "blip"),
 									),
-									newLineFilterExpr(labels.MatchNotEqual, "", "flip"),
+									newLineFilterExpr(log.LineMatchNotEqual, "", "flip"),
 								),
-								newLineFilterExpr(labels.MatchNotRegexp, "", "flap"),
+								newLineFilterExpr(log.LineMatchNotRegexp, "", "flap"),
 							),
 							newLabelParserExpr(OpParserTypeUnpack, ""),
 						},
@@ -751,12 +827,12 @@ func TestParse(t *testing.T) {
 								newNestedLineFilterExpr(
 									newNestedLineFilterExpr(
 										newNestedLineFilterExpr(
-											newLineFilterExpr(labels.MatchEqual, "", "baz"),
-											newLineFilterExpr(labels.MatchRegexp, "", "blip"),
+											newLineFilterExpr(log.LineMatchEqual, "", "baz"),
+											newLineFilterExpr(log.LineMatchRegexp, "", "blip"),
 										),
-										newLineFilterExpr(labels.MatchNotEqual, "", "flip"),
+										newLineFilterExpr(log.LineMatchNotEqual, "", "flip"),
 									),
-									newLineFilterExpr(labels.MatchNotRegexp, "", "flap"),
+									newLineFilterExpr(log.LineMatchNotRegexp, "", "flap"),
 								),
 							},
 						),
@@ -778,12 +854,12 @@ func TestParse(t *testing.T) {
 							newNestedLineFilterExpr(
 								newNestedLineFilterExpr(
 									newNestedLineFilterExpr(
-										newLineFilterExpr(labels.MatchEqual, "", "baz"),
-										newLineFilterExpr(labels.MatchRegexp, "", "blip"),
+										newLineFilterExpr(log.LineMatchEqual, "", "baz"),
+										newLineFilterExpr(log.LineMatchRegexp, "", "blip"),
 									),
-									newLineFilterExpr(labels.MatchNotEqual, "", "flip"),
+									newLineFilterExpr(log.LineMatchNotEqual, "", "flip"),
 								),
-								newLineFilterExpr(labels.MatchNotRegexp, "", "flap"),
+								newLineFilterExpr(log.LineMatchNotRegexp, "", "flap"),
 							),
 						},
 					),
@@ -806,12 +882,12 @@ func TestParse(t *testing.T) {
 							newNestedLineFilterExpr(
 								newNestedLineFilterExpr(
 									newNestedLineFilterExpr(
-										newLineFilterExpr(labels.MatchEqual, "", "baz"),
-										newLineFilterExpr(labels.MatchRegexp, "", "blip"),
+										newLineFilterExpr(log.LineMatchEqual, "", "baz"),
+										newLineFilterExpr(log.LineMatchRegexp, "", "blip"),
 									),
-									newLineFilterExpr(labels.MatchNotEqual, "", "flip"),
+									newLineFilterExpr(log.LineMatchNotEqual, "", "flip"),
 								),
-								newLineFilterExpr(labels.MatchNotRegexp, "", "flap"),
+								newLineFilterExpr(log.LineMatchNotRegexp, "", "flap"),
 							),
 						},
 					),
@@ -834,12 +910,12 @@ func TestParse(t *testing.T) {
 							newNestedLineFilterExpr(
 								newNestedLineFilterExpr(
 									newNestedLineFilterExpr(
-										newLineFilterExpr(labels.MatchEqual, "", "baz"),
-										newLineFilterExpr(labels.MatchRegexp, "", "blip"),
+										newLineFilterExpr(log.LineMatchEqual, "", "baz"),
+										newLineFilterExpr(log.LineMatchRegexp, "", "blip"),
 									),
-									newLineFilterExpr(labels.MatchNotEqual, "", "flip"),
+									newLineFilterExpr(log.LineMatchNotEqual, "", "flip"),
 								),
-								newLineFilterExpr(labels.MatchNotRegexp, "", "flap"),
+								newLineFilterExpr(log.LineMatchNotRegexp, "", "flap"),
 							),
 						},
 					),
@@ -864,12 +940,12 @@ func TestParse(t *testing.T) {
 									newNestedLineFilterExpr(
 										newNestedLineFilterExpr(
 											newNestedLineFilterExpr(
-												newLineFilterExpr(labels.MatchEqual, "", "baz"),
-												newLineFilterExpr(labels.MatchRegexp, "", "blip"),
+												newLineFilterExpr(log.LineMatchEqual, "", "baz"),
+												newLineFilterExpr(log.LineMatchRegexp, "", "blip"),
 											),
-											newLineFilterExpr(labels.MatchNotEqual, "", "flip"),
+											newLineFilterExpr(log.LineMatchNotEqual, "", "flip"),
 										),
-										newLineFilterExpr(labels.MatchNotRegexp, "", "flap"),
+										newLineFilterExpr(log.LineMatchNotRegexp, "", "flap"),
 									),
 								},
 							),
@@ -895,12 +971,12 @@ func TestParse(t *testing.T) {
 							newNestedLineFilterExpr(
 								newNestedLineFilterExpr(
 									newNestedLineFilterExpr(
-										newLineFilterExpr(labels.MatchEqual, "", "baz"),
-										newLineFilterExpr(labels.MatchRegexp, "", "blip"),
+										newLineFilterExpr(log.LineMatchEqual, "", "baz"),
+										newLineFilterExpr(log.LineMatchRegexp, "", "blip"),
 									),
-									newLineFilterExpr(labels.MatchNotEqual, "", "flip"),
+									newLineFilterExpr(log.LineMatchNotEqual, "", "flip"),
 								),
-								newLineFilterExpr(labels.MatchNotRegexp, "", "flap"),
+								newLineFilterExpr(log.LineMatchNotRegexp, "", "flap"),
 							),
 						},
 					),
@@ -917,12 +993,12 @@ func TestParse(t *testing.T) {
 							newNestedLineFilterExpr(
 								newNestedLineFilterExpr(
 									newNestedLineFilterExpr(
-										newLineFilterExpr(labels.MatchEqual, "", "baz"),
-										newLineFilterExpr(labels.MatchRegexp, "", "blip"),
+										newLineFilterExpr(log.LineMatchEqual, "", "baz"),
+										newLineFilterExpr(log.LineMatchRegexp, "", "blip"),
 									),
-									newLineFilterExpr(labels.MatchNotEqual, "", "flip"),
+									newLineFilterExpr(log.LineMatchNotEqual, "", "flip"),
 								),
-								newLineFilterExpr(labels.MatchNotRegexp, "", "flap"),
+								newLineFilterExpr(log.LineMatchNotRegexp, "", "flap"),
 							),
 						},
 					),
@@ -945,12 +1021,12 @@ func TestParse(t *testing.T) {
 							newNestedLineFilterExpr(
 								newNestedLineFilterExpr(
 									newNestedLineFilterExpr(
-										newLineFilterExpr(labels.MatchEqual, "", "baz"),
-										newLineFilterExpr(labels.MatchRegexp, "", "blip"),
+										newLineFilterExpr(log.LineMatchEqual, "", "baz"),
+										newLineFilterExpr(log.LineMatchRegexp, "", "blip"),
 									),
-									newLineFilterExpr(labels.MatchNotEqual, "", "flip"),
+									newLineFilterExpr(log.LineMatchNotEqual, "", "flip"),
 								),
-								newLineFilterExpr(labels.MatchNotRegexp, "", "flap"),
+								newLineFilterExpr(log.LineMatchNotRegexp, "", "flap"),
 							),
 						},
 					),
@@ -975,12 +1051,12 @@ func TestParse(t *testing.T) {
 									newNestedLineFilterExpr(
 										newNestedLineFilterExpr(
 											newNestedLineFilterExpr(
-												newLineFilterExpr(labels.MatchEqual, "", "baz"),
-												newLineFilterExpr(labels.MatchRegexp, "", "blip"),
+												newLineFilterExpr(log.LineMatchEqual, "", "baz"),
+												newLineFilterExpr(log.LineMatchRegexp, "", "blip"),
 											),
-											newLineFilterExpr(labels.MatchNotEqual, "", "flip"),
+											newLineFilterExpr(log.LineMatchNotEqual, "", "flip"),
 										),
-										newLineFilterExpr(labels.MatchNotRegexp, "", "flap"),
+										newLineFilterExpr(log.LineMatchNotRegexp, "", "flap"),
 									),
 								},
 							),
@@ -1239,7 +1315,7 @@ func TestParse(t *testing.T) {
 									mustNewMatcher(labels.MatchEqual, "namespace", "tns"),
 								}),
 								MultiStageExpr{
-									newLineFilterExpr(labels.MatchEqual, "", "level=error"),
+									newLineFilterExpr(log.LineMatchEqual, "", "level=error"),
 								}),
 							Interval: 5 * time.Minute,
 						}, OpRangeTypeCount, nil, nil),
@@ -1273,7 +1349,7 @@ func TestParse(t *testing.T) {
 									mustNewMatcher(labels.MatchEqual, "namespace", "tns"),
 								}),
 								MultiStageExpr{
-									newLineFilterExpr(labels.MatchEqual, "", "level=error"),
+									newLineFilterExpr(log.LineMatchEqual, "", "level=error"),
 								}),
 							Interval: 5 * time.Minute,
 						}, OpRangeTypeCount, nil, nil),
@@ -1350,7 +1426,7 @@ func TestParse(t *testing.T) {
 			exp: &PipelineExpr{
 				Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
 				MultiStages: MultiStageExpr{
-					newLineFilterExpr(labels.MatchEqual, "", "bar"),
+					newLineFilterExpr(log.LineMatchEqual, "", "bar"),
 					newLabelParserExpr(OpParserTypeJSON, ""),
 					&LabelFilterExpr{
 						LabelFilterer: log.NewOrLabelFilter(
@@ -1369,7 +1445,7 @@ func TestParse(t *testing.T) {
 			exp: &PipelineExpr{
 				Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
 				MultiStages: MultiStageExpr{
-					newLineFilterExpr(labels.MatchEqual, "", "bar"),
+					newLineFilterExpr(log.LineMatchEqual, "", "bar"),
 					newLabelParserExpr(OpParserTypeUnpack, ""),
 					newLabelParserExpr(OpParserTypeJSON, ""),
 					&LabelFilterExpr{
@@ -1389,7 +1465,7 @@ func TestParse(t *testing.T) {
 			exp: &PipelineExpr{
 				Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
 				MultiStages: MultiStageExpr{
-					newLineFilterExpr(labels.MatchEqual, "", "bar"),
+					newLineFilterExpr(log.LineMatchEqual, "", "bar"),
 					newLabelParserExpr(OpParserTypeJSON, ""),
 					&LabelFilterExpr{
 						LabelFilterer: log.NewAndLabelFilter(
@@ -1408,7 +1484,7 @@ func TestParse(t *testing.T) {
 			exp: &PipelineExpr{
 				Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
 				MultiStages: MultiStageExpr{
-					newLineFilterExpr(labels.MatchEqual, "", "bar"),
+					newLineFilterExpr(log.LineMatchEqual, "", "bar"),
 					newLabelParserExpr(OpParserTypePattern, "<foo> bar <buzz>"),
 					&LabelFilterExpr{
 						LabelFilterer: log.NewAndLabelFilter(
@@ -1427,7 +1503,7 @@ func TestParse(t *testing.T) {
 			exp: &PipelineExpr{
 				Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
 				MultiStages: MultiStageExpr{
-					newLineFilterExpr(labels.MatchEqual, "", "bar"),
+					newLineFilterExpr(log.LineMatchEqual, "", "bar"),
 					newLabelParserExpr(OpParserTypeJSON, ""),
 					&LabelFilterExpr{
 						LabelFilterer: log.NewOrLabelFilter(
@@ -1446,7 +1522,7 @@ func TestParse(t *testing.T) {
 			exp: &PipelineExpr{
 				Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
 				MultiStages: MultiStageExpr{
-					newLineFilterExpr(labels.MatchEqual, "", "bar"),
+					newLineFilterExpr(log.LineMatchEqual, "", "bar"),
 					newLabelParserExpr(OpParserTypeJSON, ""),
 					&LabelFilterExpr{
 						LabelFilterer: log.NewAndLabelFilter(
@@ -1465,7 +1541,7 @@ func TestParse(t *testing.T) {
 			exp: &PipelineExpr{
 				Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
 				MultiStages: MultiStageExpr{
-					newLineFilterExpr(labels.MatchEqual, "", "bar"),
+					newLineFilterExpr(log.LineMatchEqual, "", "bar"),
 					newLabelParserExpr(OpParserTypeJSON, ""),
 					&LabelFilterExpr{
 						LabelFilterer: log.NewOrLabelFilter(
@@ -1485,7 +1561,7 @@ func TestParse(t *testing.T) {
 			exp: &PipelineExpr{
 				Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
 				MultiStages: MultiStageExpr{
-					newLineFilterExpr(labels.MatchEqual, "", "bar"),
+					newLineFilterExpr(log.LineMatchEqual, "", "bar"),
 					newLabelParserExpr(OpParserTypeJSON, ""),
 					&LabelFilterExpr{
 						LabelFilterer: log.NewOrLabelFilter(
@@ -1516,7 +1592,7 @@ func TestParse(t *testing.T) {
 			exp: &PipelineExpr{
 				Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
 				MultiStages: MultiStageExpr{
-					newLineFilterExpr(labels.MatchEqual, "", "bar"),
+					newLineFilterExpr(log.LineMatchEqual, "", "bar"),
 					newLineFmtExpr("blip{{ .foo }}blop"),
 				},
 			},
@@ -1527,7 +1603,7 @@ func TestParse(t *testing.T) {
 			exp: &PipelineExpr{
 				Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
 				MultiStages: MultiStageExpr{
-					newLineFilterExpr(labels.MatchEqual, "", "bar"),
+					newLineFilterExpr(log.LineMatchEqual, "", "bar"),
 					newLabelParserExpr(OpParserTypeJSON, ""),
 					&LabelFilterExpr{
 						LabelFilterer: log.NewOrLabelFilter(
@@ -1548,7 +1624,7 @@ func TestParse(t *testing.T) {
 			exp: &PipelineExpr{
 				Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
 				MultiStages: MultiStageExpr{
-					newLineFilterExpr(labels.MatchEqual, "", "bar"),
+					newLineFilterExpr(log.LineMatchEqual, "", "bar"),
 					newLabelParserExpr(OpParserTypeJSON, ""),
 					&LabelFilterExpr{
 						LabelFilterer: log.NewOrLabelFilter(
@@ -1574,7 +1650,7 @@ func TestParse(t *testing.T) {
 				newLogRange(&PipelineExpr{
 					Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
 					MultiStages: MultiStageExpr{
-						newLineFilterExpr(labels.MatchEqual, "", "bar"),
+						newLineFilterExpr(log.LineMatchEqual, "", "bar"),
 						newLabelParserExpr(OpParserTypeJSON, ""),
 						&LabelFilterExpr{
 							LabelFilterer: log.NewOrLabelFilter(
@@ -1620,7 +1696,7 @@ func TestParse(t *testing.T) {
 			exp: &PipelineExpr{
 				Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
 				MultiStages: MultiStageExpr{
-					newLineFilterExpr(labels.MatchEqual, "", "bar"),
+					newLineFilterExpr(log.LineMatchEqual, "", "bar"),
 					newLabelParserExpr(OpParserTypeJSON, ""),
 					&LabelFilterExpr{
 						LabelFilterer: log.NewOrLabelFilter(
@@ -1641,7 +1717,7 @@ func TestParse(t *testing.T) {
 				newLogRange(&PipelineExpr{
 					Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
 					MultiStages: MultiStageExpr{
-						newLineFilterExpr(labels.MatchEqual, "", "bar"),
+						newLineFilterExpr(log.LineMatchEqual, "", "bar"),
 						newLabelParserExpr(OpParserTypeJSON, ""),
 						&LabelFilterExpr{
 							LabelFilterer: log.NewOrLabelFilter(
@@ -1672,7 +1748,7 @@ func TestParse(t *testing.T) {
 				newLogRange(&PipelineExpr{
 					Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
 					MultiStages: MultiStageExpr{
-						newLineFilterExpr(labels.MatchEqual, "", "bar"),
+						newLineFilterExpr(log.LineMatchEqual, "", "bar"),
 						newLabelParserExpr(OpParserTypeJSON, ""),
 						&LabelFilterExpr{
 							LabelFilterer: log.NewOrLabelFilter(
@@ -1702,7 +1778,7 @@ func TestParse(t *testing.T) {
 				newLogRange(&PipelineExpr{
 					Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "namespace", Value: "tns"}}),
 					MultiStages: MultiStageExpr{
-						newLineFilterExpr(labels.MatchEqual, "", "level=error"),
+						newLineFilterExpr(log.LineMatchEqual, "", "level=error"),
 						newLabelParserExpr(OpParserTypeJSON, ""),
 						&LabelFilterExpr{
 							LabelFilterer: log.NewAndLabelFilter(
@@ -1724,7 +1800,7 @@ func TestParse(t *testing.T) {
 				newLogRange(&PipelineExpr{
 					Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "namespace", Value: "tns"}}),
 					MultiStages: MultiStageExpr{
-						newLineFilterExpr(labels.MatchEqual, "", "level=error"),
+						newLineFilterExpr(log.LineMatchEqual, "", "level=error"),
 						newLabelParserExpr(OpParserTypeJSON, ""),
 						&LabelFilterExpr{
 							LabelFilterer: log.NewAndLabelFilter(
@@ -1746,7 +1822,7 @@ func TestParse(t *testing.T) {
 				newLogRange(&PipelineExpr{
 					Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "namespace", Value: "tns"}}),
 					MultiStages: MultiStageExpr{
-						newLineFilterExpr(labels.MatchEqual, "", "level=error"),
+						newLineFilterExpr(log.LineMatchEqual, "", "level=error"),
 						newLabelParserExpr(OpParserTypeJSON, ""),
 						&LabelFilterExpr{
 							LabelFilterer: log.NewAndLabelFilter(
@@ -1768,7 +1844,7 @@ func TestParse(t *testing.T) {
 				newLogRange(&PipelineExpr{
 					Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "namespace", Value: "tns"}}),
 					MultiStages: MultiStageExpr{
-						newLineFilterExpr(labels.MatchEqual, "", "level=error"),
+						newLineFilterExpr(log.LineMatchEqual, "", "level=error"),
 						newLabelParserExpr(OpParserTypeJSON, ""),
 						&LabelFilterExpr{
 							LabelFilterer: log.NewAndLabelFilter(
@@ -1790,7 +1866,7 @@ func TestParse(t *testing.T) {
 				newLogRange(&PipelineExpr{
 					Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
 					MultiStages: MultiStageExpr{
-						newLineFilterExpr(labels.MatchEqual, "", "bar"),
+						newLineFilterExpr(log.LineMatchEqual, "", "bar"),
 					},
 				},
 					5*time.Minute,
@@ -1861,7 +1937,7 @@ func TestParse(t *testing.T) {
 				newLogRange(&PipelineExpr{
 					Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
 					MultiStages: MultiStageExpr{
-						newLineFilterExpr(labels.MatchEqual, "", "bar"),
+						newLineFilterExpr(log.LineMatchEqual, "", "bar"),
 						newLabelParserExpr(OpParserTypeJSON, ""),
 						&LabelFilterExpr{
 							LabelFilterer: log.NewOrLabelFilter(
@@ -1892,7 +1968,7 @@ func TestParse(t *testing.T) {
 				newLogRange(&PipelineExpr{
 					Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
 					MultiStages: MultiStageExpr{
-						newLineFilterExpr(labels.MatchEqual, "", "bar"),
+						newLineFilterExpr(log.LineMatchEqual, "", "bar"),
 						newLabelParserExpr(OpParserTypeJSON, ""),
 						&LabelFilterExpr{
 							LabelFilterer: log.NewOrLabelFilter(
@@ -1923,7 +1999,7 @@ func TestParse(t *testing.T) {
 				newLogRange(&PipelineExpr{
 					Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
 					MultiStages: MultiStageExpr{
-						newLineFilterExpr(labels.MatchEqual, "", "bar"),
+						newLineFilterExpr(log.LineMatchEqual, "", "bar"),
 						newLabelParserExpr(OpParserTypeJSON, ""),
 						&LabelFilterExpr{
 							LabelFilterer: log.NewOrLabelFilter(
@@ -1954,7 +2030,7 @@ func TestParse(t *testing.T) {
 				newLogRange(&PipelineExpr{
 					Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
 					MultiStages: MultiStageExpr{
-						newLineFilterExpr(labels.MatchEqual, "", "bar"),
+						newLineFilterExpr(log.LineMatchEqual, "", "bar"),
 						newLabelParserExpr(OpParserTypeJSON, ""),
 						&LabelFilterExpr{
 							LabelFilterer: log.NewOrLabelFilter(
@@ -1989,7 +2065,7 @@ func TestParse(t *testing.T) {
 					newLogRange(&PipelineExpr{
 						Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
 						MultiStages: MultiStageExpr{
-							newLineFilterExpr(labels.MatchEqual, "", "bar"),
+							newLineFilterExpr(log.LineMatchEqual, "", "bar"),
 							newLabelParserExpr(OpParserTypeJSON, ""),
 							&LabelFilterExpr{
 								LabelFilterer: log.NewOrLabelFilter(
@@ -2028,7 +2104,7 @@ func TestParse(t *testing.T) {
 					newLogRange(&PipelineExpr{
 						Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
 						MultiStages: MultiStageExpr{
-							newLineFilterExpr(labels.MatchEqual, "", "bar"),
+							newLineFilterExpr(log.LineMatchEqual, "", "bar"),
 							newLabelParserExpr(OpParserTypeJSON, ""),
 							&LabelFilterExpr{
 								LabelFilterer: log.NewOrLabelFilter(
@@ -2067,7 +2143,7 @@ func TestParse(t *testing.T) {
 					newLogRange(&PipelineExpr{
 						Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
 						MultiStages: MultiStageExpr{
-							newLineFilterExpr(labels.MatchEqual, "", "bar"),
+							newLineFilterExpr(log.LineMatchEqual, "", "bar"),
 							newLabelParserExpr(OpParserTypeJSON, ""),
 							&LabelFilterExpr{
 								LabelFilterer: log.NewOrLabelFilter(
@@ -2106,7 +2182,7 @@ func TestParse(t *testing.T) {
 					newLogRange(&PipelineExpr{
 						Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
 						MultiStages: MultiStageExpr{
-							newLineFilterExpr(labels.MatchEqual, "", "bar"),
+							newLineFilterExpr(log.LineMatchEqual, "", "bar"),
 							newLabelParserExpr(OpParserTypeJSON, ""),
 							&LabelFilterExpr{
 								LabelFilterer: log.NewOrLabelFilter(
@@ -2145,7 +2221,7 @@ func TestParse(t *testing.T) {
 					newLogRange(&PipelineExpr{
 						Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
 						MultiStages: MultiStageExpr{
-							newLineFilterExpr(labels.MatchEqual, "", "bar"),
+							newLineFilterExpr(log.LineMatchEqual, "", "bar"),
 							newLabelParserExpr(OpParserTypeJSON, ""),
 							&LabelFilterExpr{
 								LabelFilterer: log.NewOrLabelFilter(
@@ -2184,7 +2260,7 @@ func TestParse(t *testing.T) {
 					newLogRange(&PipelineExpr{
 						Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
 						MultiStages: MultiStageExpr{
-							newLineFilterExpr(labels.MatchEqual, "", "bar"),
+							newLineFilterExpr(log.LineMatchEqual, "", "bar"),
 							newLabelParserExpr(OpParserTypeJSON, ""),
 							&LabelFilterExpr{
 								LabelFilterer: log.NewOrLabelFilter(
@@ -2234,7 +2310,7 @@ func TestParse(t *testing.T) {
 						newLogRange(&PipelineExpr{
 							Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
 							MultiStages: MultiStageExpr{
-								newLineFilterExpr(labels.MatchEqual, "", "bar"),
+								newLineFilterExpr(log.LineMatchEqual, "", "bar"),
 								newLabelParserExpr(OpParserTypeJSON, ""),
 								&LabelFilterExpr{
 									LabelFilterer: log.NewOrLabelFilter(
@@ -2266,7 +2342,7 @@ func TestParse(t *testing.T) {
 						newLogRange(&PipelineExpr{
 							Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
 							MultiStages: MultiStageExpr{
-								newLineFilterExpr(labels.MatchEqual, "", "bar"),
+								newLineFilterExpr(log.LineMatchEqual, "", "bar"),
 								newLabelParserExpr(OpParserTypeJSON, ""),
 								&LabelFilterExpr{
 									LabelFilterer: log.NewOrLabelFilter(
@@ -2315,7 +2391,7 @@ func TestParse(t *testing.T) {
 						newLogRange(&PipelineExpr{
 							Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
 							MultiStages: MultiStageExpr{
-								newLineFilterExpr(labels.MatchEqual, "", "bar"),
+								newLineFilterExpr(log.LineMatchEqual, "", "bar"),
 								newLabelParserExpr(OpParserTypeJSON, ""),
 								&LabelFilterExpr{
 									LabelFilterer: log.NewOrLabelFilter(
@@ -2347,7 +2423,7 @@ func TestParse(t *testing.T) {
 						newLogRange(&PipelineExpr{
 							Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
 							MultiStages: MultiStageExpr{
-								newLineFilterExpr(labels.MatchEqual, "", "bar"),
+								newLineFilterExpr(log.LineMatchEqual, "", "bar"),
 								newLabelParserExpr(OpParserTypeJSON, ""),
 								&LabelFilterExpr{
 									LabelFilterer: log.NewOrLabelFilter(
@@ -2396,7 +2472,7 @@ func TestParse(t *testing.T) {
 						newLogRange(&PipelineExpr{
 							Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
 							MultiStages: MultiStageExpr{
-								newLineFilterExpr(labels.MatchEqual, "", "bar"),
+								newLineFilterExpr(log.LineMatchEqual, "", "bar"),
 								newLabelParserExpr(OpParserTypeJSON, ""),
 								&LabelFilterExpr{
 									LabelFilterer: log.NewOrLabelFilter(
@@ -2428,7 +2504,7 @@ func TestParse(t *testing.T) {
 						newLogRange(&PipelineExpr{
 							Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
 							MultiStages: MultiStageExpr{
-								newLineFilterExpr(labels.MatchEqual, "", "bar"),
+								newLineFilterExpr(log.LineMatchEqual, "", "bar"),
 								newLabelParserExpr(OpParserTypeJSON, ""),
 								&LabelFilterExpr{
 									LabelFilterer: log.NewOrLabelFilter(
@@ -2477,7 +2553,7 @@ func TestParse(t *testing.T) {
 						newLogRange(&PipelineExpr{
 							Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
 							MultiStages: MultiStageExpr{
-								newLineFilterExpr(labels.MatchEqual, "", "bar"),
+								newLineFilterExpr(log.LineMatchEqual, "", "bar"),
 								newLabelParserExpr(OpParserTypeJSON, ""),
 								&LabelFilterExpr{
 									LabelFilterer: log.NewOrLabelFilter(
@@ -2509,7 +2585,7 @@ func TestParse(t *testing.T) {
 						newLogRange(&PipelineExpr{
 							Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
 							MultiStages: MultiStageExpr{
-								newLineFilterExpr(labels.MatchEqual, "", "bar"),
+								newLineFilterExpr(log.LineMatchEqual, "", "bar"),
 								newLabelParserExpr(OpParserTypeJSON, ""),
 								&LabelFilterExpr{
 									LabelFilterer: log.NewOrLabelFilter(
@@ -2626,7 +2702,7 @@ func TestParse(t *testing.T) {
 							newLogRange(&PipelineExpr{
 								Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
 								MultiStages: MultiStageExpr{
-									newLineFilterExpr(labels.MatchEqual, "", "bar"),
+									newLineFilterExpr(log.LineMatchEqual, "", "bar"),
 									newLabelParserExpr(OpParserTypeJSON, ""),
 									&LabelFilterExpr{
 										LabelFilterer: log.NewOrLabelFilter(
@@ -2658,7 +2734,7 @@ func TestParse(t *testing.T) {
 							newLogRange(&PipelineExpr{
 								Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
 								MultiStages: MultiStageExpr{
-									newLineFilterExpr(labels.MatchEqual, "", "bar"),
+									newLineFilterExpr(log.LineMatchEqual, "", "bar"),
 									newLabelParserExpr(OpParserTypeJSON, ""),
 									&LabelFilterExpr{
 										LabelFilterer: log.NewOrLabelFilter(
@@ -2886,7 +2962,7 @@ func TestParse(t *testing.T) {
 			exp: &PipelineExpr{
 				Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
 				MultiStages: MultiStageExpr{
-					newLineFilterExpr(labels.MatchEqual, "", "bar"),
+					newLineFilterExpr(log.LineMatchEqual, "", "bar"),
 					newLabelParserExpr(OpParserTypeJSON, ""),
 				},
 			},
@@ -2917,7 +2993,7 @@ func TestParse(t *testing.T) {
 			exp: &PipelineExpr{
 				Left: newMatcherExpr([]*labels.Matcher{{Type: labels.MatchEqual, Name: "app", Value: "foo"}}),
 				MultiStages: MultiStageExpr{
-					newLineFilterExpr(labels.MatchEqual, "", "#"),
+					newLineFilterExpr(log.LineMatchEqual, "", "#"),
 				},
 			},
 		},
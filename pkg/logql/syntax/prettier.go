@@ -130,6 +130,16 @@ func (e *LabelFmtExpr) Pretty(level int) string {
 	return commonPrefixIndent(level, e)
 }
 
+// e.g: | drop foo, bar="buzz"
+func (e *DropLabelsExpr) Pretty(level int) string {
+	return commonPrefixIndent(level, e)
+}
+
+// e.g: | keep foo, bar="buzz"
+func (e *KeepLabelsExpr) Pretty(level int) string {
+	return commonPrefixIndent(level, e)
+}
+
 // e.g: | json label="expression", another="expression"
 func (e *JSONExpressionParser) Pretty(level int) string {
 	return commonPrefixIndent(level, e)
@@ -210,13 +220,41 @@ func (e *RangeAggregationExpr) Pretty(level int) string {
 	return s
 }
 
+// e.g: max_over_time(rate({foo="bar"}[5m])[1h:1m])
+func (e *SubqueryExpr) Pretty(level int) string {
+	s := indent(level)
+	if !needSplit(e) {
+		return s + e.String()
+	}
+
+	s += e.Operation
+
+	s += "(\n"
+
+	if e.Params != nil {
+		s = fmt.Sprintf("%s%s%s,", s, indent(level+1), fmt.Sprint(*e.Params))
+		s += "\n"
+	}
+
+	// NOTE: [range:step] stays on the same line as the closing parenthesis of its inner expression.
+	s += e.Left.Pretty(level+1) + e.RangeString()
+
+	s += "\n" + indent(level) + ")"
+
+	if e.Grouping != nil {
+		s += e.Grouping.Pretty(level)
+	}
+
+	return s
+}
+
 // e.g:
 // sum(count_over_time({foo="bar"}[5m])) by (container)
 // topk(10, count_over_time({foo="bar"}[5m])) by (container)
 
 // Syntax: <aggr-op>([parameter,] <vector expression>) [without|by (<label list>)]
 // <aggr-op> - sum, avg, bottomk, topk, etc.
-// [parameters,] - optional params, used only by bottomk and topk for now.
+// [parameters,] - optional params, used only by bottomk, topk and count_values for now.
 // <vector expression> - vector on which aggregation is done.
 // [without|by (<label list)] - optional labels to aggregate either with `by` or `without` clause.
 func (e *VectorAggregationExpr) Pretty(level int) string {
@@ -232,8 +270,10 @@ func (e *VectorAggregationExpr) Pretty(level int) string {
 	left := e.Left.Pretty(level + 1)
 	switch e.Operation {
 	// e.Params default value (0) can mean a legit param for topk and bottomk
-	case OpTypeBottomK, OpTypeTopK:
+	case OpTypeBottomK, OpTypeTopK, OpTypeCountMinSketch:
 		params = []string{fmt.Sprintf("%s%d", indent(level+1), e.Params), left}
+	case OpTypeCountValues:
+		params = []string{fmt.Sprintf("%s%s", indent(level+1), strconv.Quote(e.Label)), left}
 
 	default:
 		if e.Params != 0 {
@@ -88,6 +88,12 @@ func NewSeriesLimitError(limit int) *LimitError {
 	}
 }
 
+func NewSubqueryPointsLimitError(points, limit int64) *LimitError {
+	return &LimitError{
+		error: fmt.Errorf("subquery evaluated at %d points, exceeding the maximum of %d points: increase the subquery step or decrease its range", points, limit),
+	}
+}
+
 // Is allows to use errors.Is(err,ErrLimit) on this error.
 func (e LimitError) Is(target error) bool {
 	return target == ErrLimit
@@ -273,6 +273,8 @@ func (t *Loki) initTenantConfigs() (_ services.Service, err error) {
 }
 
 func (t *Loki) initDistributor() (services.Service, error) {
+	t.Cfg.Distributor.MaxRecvMsgSize = t.Cfg.Server.GPRCServerMaxRecvMsgSize
+
 	var err error
 	t.distributor, err = distributor.New(
 		t.Cfg.Distributor,
@@ -302,6 +304,10 @@ func (t *Loki) initDistributor() (services.Service, error) {
 		serverutil.RecoveryHTTPMiddleware,
 		t.HTTPAuthMiddleware,
 	).Wrap(http.HandlerFunc(t.distributor.PushHandler))
+	otlpPushHandler := middleware.Merge(
+		serverutil.RecoveryHTTPMiddleware,
+		t.HTTPAuthMiddleware,
+	).Wrap(http.HandlerFunc(t.distributor.OTLPPushHandler))
 
 	t.Server.HTTP.Path("/distributor/ring").Methods("GET", "POST").Handler(t.distributor)
 
@@ -311,6 +317,7 @@ func (t *Loki) initDistributor() (services.Service, error) {
 
 	t.Server.HTTP.Path("/api/prom/push").Methods("POST").Handler(pushHandler)
 	t.Server.HTTP.Path("/loki/api/v1/push").Methods("POST").Handler(pushHandler)
+	t.Server.HTTP.Path("/otlp/v1/logs").Methods("POST").Handler(otlpPushHandler)
 	return t.distributor, nil
 }
 
@@ -378,8 +385,9 @@ func (t *Loki) initQuerier() (services.Service, error) {
 		"/loki/api/v1/labels":              querier.WrapQuerySpanAndTimeout("query.Label", t.querierAPI).Wrap(http.HandlerFunc(t.querierAPI.LabelHandler)),
 		"/loki/api/v1/label/{name}/values": querier.WrapQuerySpanAndTimeout("query.Label", t.querierAPI).Wrap(http.HandlerFunc(t.querierAPI.LabelHandler)),
 
-		"/loki/api/v1/series":      querier.WrapQuerySpanAndTimeout("query.Series", t.querierAPI).Wrap(http.HandlerFunc(t.querierAPI.SeriesHandler)),
-		"/loki/api/v1/index/stats": querier.WrapQuerySpanAndTimeout("query.IndexStats", t.querierAPI).Wrap(http.HandlerFunc(t.querierAPI.IndexStatsHandler)),
+		"/loki/api/v1/series":       querier.WrapQuerySpanAndTimeout("query.Series", t.querierAPI).Wrap(http.HandlerFunc(t.querierAPI.SeriesHandler)),
+		"/loki/api/v1/index/stats":  querier.WrapQuerySpanAndTimeout("query.IndexStats", t.querierAPI).Wrap(http.HandlerFunc(t.querierAPI.IndexStatsHandler)),
+		"/loki/api/v1/index/volume": querier.WrapQuerySpanAndTimeout("query.Volume", t.querierAPI).Wrap(http.HandlerFunc(t.querierAPI.VolumeHandler)),
 
 		"/api/prom/query": middleware.Merge(
 			httpMiddleware,
@@ -652,8 +660,10 @@ func (t *Loki) initIngesterQuerier() (_ services.Service, err error) {
 	return services.NewIdleService(nil, nil), nil
 }
 
-// Placeholder limits type to pass to cortex frontend
-type disabledShuffleShardingLimits struct{}
+// Limits passed to the cortex frontend: shuffle sharding is disabled, but the tenants priorities and weights apply.
+type disabledShuffleShardingLimits struct {
+	*validation.Overrides
+}
 
 func (disabledShuffleShardingLimits) MaxQueriersPerUser(userID string) int { return 0 }
 
@@ -742,7 +752,7 @@ func (t *Loki) initQueryFrontend() (_ services.Service, err error) {
 	roundTripper, frontendV1, frontendV2, err := frontend.InitFrontend(
 		combinedCfg,
 		scheduler.SafeReadRing(t.queryScheduler),
-		disabledShuffleShardingLimits{},
+		disabledShuffleShardingLimits{t.overrides},
 		t.Cfg.Server.GRPCListenPort,
 		util_log.Logger,
 		prometheus.DefaultRegisterer)
@@ -763,6 +773,9 @@ func (t *Loki) initQueryFrontend() (_ services.Service, err error) {
 	}
 
 	roundTripper = t.QueryFrontEndTripperware(roundTripper)
+	if frontendV2 != nil {
+		roundTripper = frontendV2.TrackQueries(roundTripper)
+	}
 
 	frontendHandler := transport.NewHandler(t.Cfg.Frontend.Handler, roundTripper, util_log.Logger, prometheus.DefaultRegisterer)
 	if t.Cfg.Frontend.CompressResponses {
@@ -818,11 +831,18 @@ func (t *Loki) initQueryFrontend() (_ services.Service, err error) {
 	t.Server.HTTP.Path("/loki/api/v1/label/{name}/values").Methods("GET", "POST").Handler(frontendHandler)
 	t.Server.HTTP.Path("/loki/api/v1/series").Methods("GET", "POST").Handler(frontendHandler)
 	t.Server.HTTP.Path("/loki/api/v1/index/stats").Methods("GET", "POST").Handler(frontendHandler)
+	t.Server.HTTP.Path("/loki/api/v1/index/volume").Methods("GET", "POST").Handler(frontendHandler)
 	t.Server.HTTP.Path("/api/prom/query").Methods("GET", "POST").Handler(frontendHandler)
 	t.Server.HTTP.Path("/api/prom/label").Methods("GET", "POST").Handler(frontendHandler)
 	t.Server.HTTP.Path("/api/prom/label/{name}/values").Methods("GET", "POST").Handler(frontendHandler)
 	t.Server.HTTP.Path("/api/prom/series").Methods("GET", "POST").Handler(frontendHandler)
 
+	if frontendV2 != nil {
+		queriesHandler := middleware.Merge(serverutil.RecoveryHTTPMiddleware, t.HTTPAuthMiddleware)
+		t.Server.HTTP.Path("/loki/api/v1/queries").Methods("GET").Handler(queriesHandler.Wrap(http.HandlerFunc(frontendV2.RunningQueriesHandler)))
+		t.Server.HTTP.Path("/loki/api/v1/queries").Methods("DELETE").Handler(queriesHandler.Wrap(http.HandlerFunc(frontendV2.CancelQueryHandler)))
+	}
+
 	// Only register tailing requests if this process does not act as a Querier
 	// If this process is also a Querier the Querier will register the tail endpoints.
 	if !t.isModuleActive(Querier) {
@@ -899,11 +919,21 @@ func (t *Loki) initRuler() (_ services.Service, err error) {
 		return nil, err
 	}
 
-	engine := logql.NewEngine(t.Cfg.Querier.Engine, q, t.overrides, log.With(util_log.Logger, "component", "ruler"))
+	var evaluator ruler.Evaluator
+	switch t.Cfg.Ruler.Evaluation.Mode {
+	case ruler.EvalModeRemote:
+		evaluator, err = ruler.NewRemoteEvaluator(t.Cfg.Ruler.Evaluation.QueryFrontend, t.overrides, util_log.Logger, prometheus.DefaultRegisterer)
+	default:
+		engine := logql.NewEngine(t.Cfg.Querier.Engine, q, t.overrides, log.With(util_log.Logger, "component", "ruler"))
+		evaluator, err = ruler.NewLocalEvaluator(engine)
+	}
+	if err != nil {
+		return nil, err
+	}
 
 	t.ruler, err = ruler.NewRuler(
 		t.Cfg.Ruler,
-		engine,
+		evaluator,
 		prometheus.DefaultRegisterer,
 		util_log.Logger,
 		t.RulerStorage,
@@ -991,6 +1021,7 @@ func (t *Loki) initMemberlistKV() (services.Service, error) {
 func (t *Loki) initCompactor() (services.Service, error) {
 	// Set some config sections from other config sections in the config struct
 	t.Cfg.CompactorConfig.CompactorRing.ListenPort = t.Cfg.Server.GRPCListenPort
+	t.Cfg.CompactorConfig.DeleteChunkBloomFilters = t.Cfg.Ingester.ChunkBloomFilters
 
 	if !config.UsingObjectStorageIndex(t.Cfg.SchemaConfig.Configs) {
 		level.Info(util_log.Logger).Log("msg", "Not using object storage index, not starting compactor")
@@ -1024,6 +1055,8 @@ func (t *Loki) initCompactor() (services.Service, error) {
 		t.Server.HTTP.Path("/loki/api/v1/delete").Methods("PUT", "POST").Handler(t.addCompactorMiddleware(t.compactor.DeleteRequestsHandler.AddDeleteRequestHandler))
 		t.Server.HTTP.Path("/loki/api/v1/delete").Methods("GET").Handler(t.addCompactorMiddleware(t.compactor.DeleteRequestsHandler.GetAllDeleteRequestsHandler))
 		t.Server.HTTP.Path("/loki/api/v1/delete").Methods("DELETE").Handler(t.addCompactorMiddleware(t.compactor.DeleteRequestsHandler.CancelDeleteRequestHandler))
+		t.Server.HTTP.Path("/loki/api/v1/delete/requeue").Methods("PUT", "POST").Handler(t.addCompactorMiddleware(t.compactor.DeleteRequestsHandler.RequeueDeleteRequestHandler))
+		t.Server.HTTP.Path("/loki/api/v1/delete/preview").Methods("GET", "POST").Handler(t.addCompactorMiddleware(t.compactor.DeleteRequestsHandler.PreviewDeleteRequestHandler))
 		t.Server.HTTP.Path("/loki/api/v1/cache/generation_numbers").Methods("GET").Handler(t.addCompactorMiddleware(t.compactor.DeleteRequestsHandler.GetCacheGenerationNumberHandler))
 		grpc.RegisterCompactorServer(t.Server.GRPC, t.compactor.DeleteRequestsGRPCHandler)
 	}
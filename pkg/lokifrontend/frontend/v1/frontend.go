@@ -43,6 +43,12 @@ func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 type Limits interface {
 	// Returns max queriers to use per tenant, or 0 if shuffle sharding is disabled.
 	MaxQueriersPerUser(user string) int
+
+	// Returns the priority class of the queries of the tenant.
+	QueryPriority(user string) int
+
+	// Returns the share of the queriers given to the tenant, relative to the tenants of the same priority class.
+	QueryWeight(user string) float64
 }
 
 // Frontend queues HTTP requests, dispatches them to backends, and handles retries
@@ -318,11 +324,16 @@ func (f *Frontend) queueRequest(ctx context.Context, req *request) error {
 
 	// aggregate the max queriers limit in the case of a multi tenant query
 	maxQueriers := validation.SmallestPositiveNonZeroIntPerTenant(tenantIDs, f.limits.MaxQueriersPerUser)
+	// a multi tenant query gets the lowest priority and weight of its tenants.
+	share := queue.TenantShare{
+		Priority: validation.SmallestPositiveIntPerTenant(tenantIDs, f.limits.QueryPriority),
+		Weight:   validation.SmallestPositiveNonZeroFloat64PerTenant(tenantIDs, f.limits.QueryWeight),
+	}
 
 	joinedTenantID := tenant.JoinTenantIDs(tenantIDs)
 	f.activeUsers.UpdateUserTimestamp(joinedTenantID, now)
 
-	err = f.requestQueue.EnqueueRequest(joinedTenantID, req, maxQueriers, nil)
+	err = f.requestQueue.EnqueueRequest(joinedTenantID, req, maxQueriers, share, nil)
 	if err == queue.ErrTooManyRequests {
 		return errTooManyRequest
 	}
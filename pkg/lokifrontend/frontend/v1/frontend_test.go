@@ -298,3 +298,11 @@ type limits struct {
 func (l limits) MaxQueriersPerUser(_ string) int {
 	return l.queriers
 }
+
+func (l limits) QueryPriority(_ string) int {
+	return 0
+}
+
+func (l limits) QueryWeight(_ string) float64 {
+	return 1
+}
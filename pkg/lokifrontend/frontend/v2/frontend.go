@@ -74,6 +74,7 @@ type Frontend struct {
 
 	schedulerWorkers *frontendSchedulerWorkers
 	requests         *requestsInProgress
+	runningQueries   *runningQueries
 }
 
 type frontendRequest struct {
@@ -119,6 +120,7 @@ func NewFrontend(cfg Config, ring ring.ReadRing, log log.Logger, reg prometheus.
 		requestsCh:       requestsCh,
 		schedulerWorkers: schedulerWorkers,
 		requests:         newRequestsInProgress(),
+		runningQueries:   newRunningQueries(),
 	}
 	// Randomize to avoid getting responses from queries sent before restart, which could lead to mixing results
 	// between different queries. Note that frontend verifies the user, so it cannot leak results between tenants.
@@ -175,6 +177,7 @@ func (f *Frontend) RoundTripGRPC(ctx context.Context, req *httpgrpc.HTTPRequest)
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
+	query := runningQueryFromContext(ctx)
 	freq := &frontendRequest{
 		queryID:      f.lastQueryID.Inc(),
 		request:      req,
@@ -192,6 +195,12 @@ func (f *Frontend) RoundTripGRPC(ctx context.Context, req *httpgrpc.HTTPRequest)
 	f.requests.put(freq)
 	defer f.requests.delete(freq.queryID)
 
+	var res *frontendv2pb.QueryResultRequest
+	if query != nil {
+		query.subqueries.Inc()
+		defer func() { query.subqueryDone(res) }()
+	}
+
 	retries := f.cfg.WorkerConcurrency + 1 // To make sure we hit at least two different schedulers.
 
 enqueueAgain:
@@ -230,13 +239,12 @@ enqueueAgain:
 		}
 		return nil, ctx.Err()
 
-	case resp := <-freq.response:
-		if stats.ShouldTrackHTTPGRPCResponse(resp.HttpResponse) {
+	case res = <-freq.response:
+		if stats.ShouldTrackHTTPGRPCResponse(res.HttpResponse) {
 			stats := stats.FromContext(ctx)
-			stats.Merge(resp.Stats) // Safe if stats is nil.
+			stats.Merge(res.Stats) // Safe if stats is nil.
 		}
-
-		return resp.HttpResponse, nil
+		return res.HttpResponse, nil
 	}
 }
 
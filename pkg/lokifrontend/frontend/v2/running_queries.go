@@ -0,0 +1,272 @@
+package v2
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/grafana/dskit/tenant"
+	"github.com/weaveworks/common/httpgrpc"
+	"go.uber.org/atomic"
+
+	"github.com/grafana/loki/pkg/lokifrontend/frontend/v2/frontendv2pb"
+	serverutil "github.com/grafana/loki/pkg/util/server"
+)
+
+// QueryIDHeader is the response header holding the ID of the query, which can be used to cancel it.
+const QueryIDHeader = "X-Loki-Query-Id"
+
+var errQueryCanceled = errors.New("query canceled")
+
+// RunningQuery describes a query in progress in the frontend.
+type RunningQuery struct {
+	ID             string          `json:"id"`
+	Path           string          `json:"path"`
+	Query          string          `json:"query,omitempty"`
+	StartTime      time.Time       `json:"start_time"`
+	Subqueries     SubqueriesCount `json:"subqueries"`
+	BytesProcessed int64           `json:"bytes_processed"`
+}
+
+// RunningQueries lists the queries in progress in a frontend replica.
+type RunningQueries struct {
+	// Frontend is the address of the frontend replica the queries are in progress in.
+	Frontend string         `json:"frontend"`
+	Queries  []RunningQuery `json:"queries"`
+}
+
+// SubqueriesCount counts the subqueries a query has been split into.
+type SubqueriesCount struct {
+	Total      int64 `json:"total"`
+	InProgress int64 `json:"in_progress"`
+	Completed  int64 `json:"completed"`
+	Failed     int64 `json:"failed"`
+}
+
+type runningQuery struct {
+	id        string
+	userID    string
+	path      string
+	query     string
+	startTime time.Time
+	cancel    context.CancelFunc
+
+	subqueries          atomic.Int64
+	completedSubqueries atomic.Int64
+	failedSubqueries    atomic.Int64
+	bytesProcessed      atomic.Int64
+}
+
+type runningQueryContextKey struct{}
+
+func runningQueryFromContext(ctx context.Context) *runningQuery {
+	q, _ := ctx.Value(runningQueryContextKey{}).(*runningQuery)
+	return q
+}
+
+// subqueryDone records the result of a subquery, which has failed if the result is nil.
+func (q *runningQuery) subqueryDone(res *frontendv2pb.QueryResultRequest) {
+	if res == nil || res.HttpResponse.Code/100 != 2 {
+		q.failedSubqueries.Inc()
+		return
+	}
+
+	q.completedSubqueries.Inc()
+	q.bytesProcessed.Add(int64(res.Stats.LoadProcessedBytes()))
+}
+
+func (q *runningQuery) describe() RunningQuery {
+	completed, failed := q.completedSubqueries.Load(), q.failedSubqueries.Load()
+	total := q.subqueries.Load()
+	return RunningQuery{
+		ID:        q.id,
+		Path:      q.path,
+		Query:     q.query,
+		StartTime: q.startTime,
+		Subqueries: SubqueriesCount{
+			Total:      total,
+			InProgress: total - completed - failed,
+			Completed:  completed,
+			Failed:     failed,
+		},
+		BytesProcessed: q.bytesProcessed.Load(),
+	}
+}
+
+type runningQueries struct {
+	mtx     sync.Mutex
+	queries map[string]*runningQuery
+}
+
+func newRunningQueries() *runningQueries {
+	return &runningQueries{queries: map[string]*runningQuery{}}
+}
+
+func (r *runningQueries) add(q *runningQuery) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.queries[q.id] = q
+}
+
+func (r *runningQueries) remove(id string) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	delete(r.queries, id)
+}
+
+func (r *runningQueries) get(userID, id string) *runningQuery {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	q := r.queries[id]
+	if q == nil || q.userID != userID {
+		return nil
+	}
+	return q
+}
+
+func (r *runningQueries) list(userID string) []RunningQuery {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	res := []RunningQuery{}
+	for _, q := range r.queries {
+		if q.userID == userID {
+			res = append(res, q.describe())
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].StartTime.Before(res[j].StartTime)
+	})
+	return res
+}
+
+// TrackQueries wraps the round tripper receiving the queries, before they are split into subqueries, to keep track
+// of the queries in progress so that they can be listed and canceled.
+func (f *Frontend) TrackQueries(next http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		tenantIDs, err := tenant.TenantIDs(r.Context())
+		if err != nil {
+			return next.RoundTrip(r)
+		}
+		if err := r.ParseForm(); err != nil {
+			return nil, httpgrpc.Errorf(http.StatusBadRequest, err.Error())
+		}
+
+		id, err := newRunningQueryID()
+		if err != nil {
+			return nil, err
+		}
+
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+
+		q := &runningQuery{
+			id:        id,
+			userID:    tenant.JoinTenantIDs(tenantIDs),
+			path:      r.URL.Path,
+			query:     r.Form.Get("query"),
+			startTime: time.Now(),
+			cancel:    cancel,
+		}
+		f.runningQueries.add(q)
+		defer f.runningQueries.remove(q.id)
+
+		resp, err := next.RoundTrip(r.WithContext(context.WithValue(ctx, runningQueryContextKey{}, q)))
+		if err != nil {
+			if r.Context().Err() != nil {
+				// The client is gone, there is no one to return the ID to.
+				return nil, err
+			}
+			if ctx.Err() != nil {
+				// The query has been canceled through the API, not by the client.
+				err = httpgrpc.Errorf(http.StatusServiceUnavailable, errQueryCanceled.Error())
+			}
+			return nil, withQueryIDHeader(err, q.id)
+		}
+		resp.Header.Set(QueryIDHeader, q.id)
+		return resp, nil
+	})
+}
+
+// newRunningQueryID returns a random query ID, unique across the frontend replicas and their restarts.
+func newRunningQueryID() (string, error) {
+	var id [8]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return "", fmt.Errorf("generating query ID: %w", err)
+	}
+	return hex.EncodeToString(id[:]), nil
+}
+
+// withQueryIDHeader returns the error as an HTTP response holding the query ID header.
+func withQueryIDHeader(err error, id string) error {
+	resp, ok := httpgrpc.HTTPResponseFromError(err)
+	if !ok {
+		status, cerr := serverutil.ClientHTTPStatusAndError(err)
+		resp = &httpgrpc.HTTPResponse{Code: int32(status), Body: []byte(cerr.Error())}
+	}
+	resp.Headers = append(resp.Headers, &httpgrpc.Header{Key: QueryIDHeader, Values: []string{id}})
+	return httpgrpc.ErrorFromHTTPResponse(resp)
+}
+
+// address returns the address of the frontend replica, as advertised to the queriers.
+func (f *Frontend) address() string {
+	return fmt.Sprintf("%s:%d", f.cfg.Addr, f.cfg.Port)
+}
+
+// RunningQueriesHandler lists the queries of the tenant in progress in this frontend.
+// The queries received by other frontend replicas are not listed, so the response holds the address of this replica.
+func (f *Frontend) RunningQueriesHandler(w http.ResponseWriter, r *http.Request) {
+	tenantIDs, err := tenant.TenantIDs(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	userID := tenant.JoinTenantIDs(tenantIDs)
+
+	res := RunningQueries{
+		Frontend: f.address(),
+		Queries:  f.runningQueries.list(userID),
+	}
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		http.Error(w, fmt.Sprintf("error marshalling response: %v", err), http.StatusInternalServerError)
+	}
+}
+
+// CancelQueryHandler cancels a query of the tenant in progress in this frontend, aborting all its queued and running subqueries.
+// The queries received by other frontend replicas can't be canceled.
+func (f *Frontend) CancelQueryHandler(w http.ResponseWriter, r *http.Request) {
+	tenantIDs, err := tenant.TenantIDs(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	userID := tenant.JoinTenantIDs(tenantIDs)
+
+	queryID := r.URL.Query().Get("query_id")
+	if queryID == "" {
+		http.Error(w, "query_id is required", http.StatusBadRequest)
+		return
+	}
+
+	q := f.runningQueries.get(userID, queryID)
+	if q == nil {
+		http.Error(w, fmt.Sprintf("query not found in the query frontend %s: queries can only be canceled in the query frontend which received them", f.address()), http.StatusNotFound)
+		return
+	}
+
+	q.cancel()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (fn roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return fn(r)
+}
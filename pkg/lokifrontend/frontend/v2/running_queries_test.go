@@ -0,0 +1,176 @@
+package v2
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/httpgrpc"
+	"github.com/weaveworks/common/user"
+
+	"github.com/grafana/loki/pkg/lokifrontend/frontend/v2/frontendv2pb"
+	"github.com/grafana/loki/pkg/querier/stats"
+	"github.com/grafana/loki/pkg/scheduler/schedulerpb"
+	"github.com/grafana/loki/pkg/util/test"
+)
+
+// setupTrackedFrontend returns a round tripper sending the requests as subqueries to the frontend one after the other,
+// and a channel receiving the IDs of the subqueries enqueued to the scheduler.
+func setupTrackedFrontend(t *testing.T, subqueries int) (*Frontend, http.RoundTripper, chan uint64) {
+	enqueued := make(chan uint64, 10)
+	f, _ := setupFrontend(t, func(f *Frontend, msg *schedulerpb.FrontendToScheduler) *schedulerpb.SchedulerToFrontend {
+		if msg.Type == schedulerpb.ENQUEUE {
+			enqueued <- msg.QueryID
+		}
+		return &schedulerpb.SchedulerToFrontend{Status: schedulerpb.OK}
+	})
+
+	rt := f.TrackQueries(roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		var resp *httpgrpc.HTTPResponse
+		for i := 0; i < subqueries; i++ {
+			var err error
+			resp, err = f.RoundTripGRPC(r.Context(), &httpgrpc.HTTPRequest{Url: r.URL.String()})
+			if err != nil {
+				return nil, err
+			}
+		}
+		return &http.Response{StatusCode: int(resp.Code), Header: http.Header{}, Body: io.NopCloser(strings.NewReader(string(resp.Body)))}, nil
+	}))
+	return f, rt, enqueued
+}
+
+func listRunningQueries(t *testing.T, f *Frontend, userID string) []RunningQuery {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/loki/api/v1/queries", nil)
+	f.RunningQueriesHandler(w, r.WithContext(user.InjectOrgID(r.Context(), userID)))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var res RunningQueries
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	require.Equal(t, f.address(), res.Frontend)
+	return res.Queries
+}
+
+func TestFrontendRunningQueries(t *testing.T) {
+	f, rt, enqueued := setupTrackedFrontend(t, 2)
+	sendResult := func(queryID uint64) {
+		_, _ = f.QueryResult(user.InjectOrgID(context.Background(), "test"), &frontendv2pb.QueryResultRequest{
+			QueryID:      queryID,
+			HttpResponse: &httpgrpc.HTTPResponse{Code: 200, Body: []byte(`{"status":"success"}`)},
+			Stats:        &stats.Stats{ProcessedBytes: 1024},
+		})
+	}
+
+	type result struct {
+		resp *http.Response
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		// The queriers report the bytes processed in their stats.
+		_, ctx := stats.ContextWithEmptyStats(user.InjectOrgID(context.Background(), "test"))
+		req := httptest.NewRequest("GET", `/loki/api/v1/query_range?query={app="foo"}`, nil)
+		resp, err := rt.RoundTrip(req.WithContext(ctx))
+		done <- result{resp, err}
+	}()
+
+	queryID := <-enqueued
+	queries := listRunningQueries(t, f, "test")
+	require.Len(t, queries, 1)
+	require.Equal(t, "/loki/api/v1/query_range", queries[0].Path)
+	require.Equal(t, `{app="foo"}`, queries[0].Query)
+	require.Equal(t, SubqueriesCount{Total: 1, InProgress: 1}, queries[0].Subqueries)
+
+	// Queries are only listed to their tenant.
+	require.Empty(t, listRunningQueries(t, f, "other"))
+
+	sendResult(queryID)
+	queryID = <-enqueued
+	queries = listRunningQueries(t, f, "test")
+	require.Len(t, queries, 1)
+	require.Equal(t, SubqueriesCount{Total: 2, InProgress: 1, Completed: 1}, queries[0].Subqueries)
+	require.Equal(t, int64(1024), queries[0].BytesProcessed)
+
+	sendResult(queryID)
+	res := <-done
+	require.NoError(t, res.err)
+	require.Equal(t, queries[0].ID, res.resp.Header.Get(QueryIDHeader))
+	require.Empty(t, listRunningQueries(t, f, "test"))
+}
+
+func TestFrontendCancelQuery(t *testing.T) {
+	f, rt, enqueued := setupTrackedFrontend(t, 1)
+
+	done := make(chan error, 1)
+	go func() {
+		req := httptest.NewRequest("GET", `/loki/api/v1/query_range?query={app="foo"}`, nil)
+		_, err := rt.RoundTrip(req.WithContext(user.InjectOrgID(context.Background(), "test")))
+		done <- err
+	}()
+
+	<-enqueued
+	queries := listRunningQueries(t, f, "test")
+	require.Len(t, queries, 1)
+
+	cancel := func(userID, queryID string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("DELETE", "/loki/api/v1/queries?query_id="+queryID, nil)
+		f.CancelQueryHandler(w, r.WithContext(user.InjectOrgID(r.Context(), userID)))
+		return w
+	}
+
+	// Queries can only be canceled by their tenant.
+	w := cancel("other", queries[0].ID)
+	require.Equal(t, http.StatusNotFound, w.Code)
+	require.Contains(t, w.Body.String(), f.address())
+	require.Equal(t, http.StatusNoContent, cancel("test", queries[0].ID).Code)
+
+	select {
+	case err := <-done:
+		resp, ok := httpgrpc.HTTPResponseFromError(err)
+		require.True(t, ok)
+		require.Equal(t, int32(http.StatusServiceUnavailable), resp.Code)
+		require.Equal(t, []*httpgrpc.Header{{Key: QueryIDHeader, Values: []string{queries[0].ID}}}, resp.Headers)
+	case <-time.After(time.Second):
+		t.Fatal("the query hasn't been canceled")
+	}
+
+	test.Poll(t, time.Second, 0, func() interface{} {
+		return len(listRunningQueries(t, f, "test"))
+	})
+}
+
+func TestFrontendTrackQueriesError(t *testing.T) {
+	f, _ := setupFrontend(t, nil)
+
+	for _, tc := range []struct {
+		name     string
+		err      error
+		expected int32
+	}{
+		{name: "http error", err: httpgrpc.Errorf(http.StatusBadRequest, "bad request"), expected: http.StatusBadRequest},
+		{name: "other error", err: errors.New("failed"), expected: http.StatusInternalServerError},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var queryID string
+			rt := f.TrackQueries(roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+				queryID = runningQueryFromContext(r.Context()).id
+				return nil, tc.err
+			}))
+
+			req := httptest.NewRequest("GET", `/loki/api/v1/query_range?query={app="foo"}`, nil)
+			_, err := rt.RoundTrip(req.WithContext(user.InjectOrgID(context.Background(), "test")))
+			resp, ok := httpgrpc.HTTPResponseFromError(err)
+			require.True(t, ok)
+			require.Equal(t, tc.expected, resp.Code)
+			require.Equal(t, []*httpgrpc.Header{{Key: QueryIDHeader, Values: []string{queryID}}}, resp.Headers)
+		})
+	}
+}
@@ -11,7 +11,6 @@ import (
 	"github.com/go-kit/log/level"
 	"github.com/gorilla/websocket"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/promql/parser"
 	"github.com/weaveworks/common/httpgrpc"
 	"github.com/weaveworks/common/middleware"
@@ -21,9 +20,11 @@ import (
 	"github.com/grafana/loki/pkg/loghttp"
 	loghttp_legacy "github.com/grafana/loki/pkg/loghttp/legacy"
 	"github.com/grafana/loki/pkg/logql"
+	logqllog "github.com/grafana/loki/pkg/logql/log"
 	"github.com/grafana/loki/pkg/logql/syntax"
 	"github.com/grafana/loki/pkg/logqlmodel"
 	"github.com/grafana/loki/pkg/logqlmodel/stats"
+	querier_stats "github.com/grafana/loki/pkg/querier/stats"
 	index_stats "github.com/grafana/loki/pkg/storage/stores/index/stats"
 	"github.com/grafana/loki/pkg/util/httpreq"
 	util_log "github.com/grafana/loki/pkg/util/log"
@@ -94,6 +95,8 @@ func (q *QuerierAPI) RangeQueryHandler(w http.ResponseWriter, r *http.Request) {
 		serverutil.WriteError(err, w)
 		return
 	}
+	// The frontend reports the bytes processed by queries in progress.
+	querier_stats.FromContext(ctx).AddProcessedBytes(uint64(result.Statistics.Summary.TotalBytesProcessed))
 	if err := marshal.WriteQueryResponseJSON(result, w); err != nil {
 		serverutil.WriteError(err, w)
 		return
@@ -130,6 +133,8 @@ func (q *QuerierAPI) InstantQueryHandler(w http.ResponseWriter, r *http.Request)
 		serverutil.WriteError(err, w)
 		return
 	}
+	// The frontend reports the bytes processed by queries in progress.
+	querier_stats.FromContext(ctx).AddProcessedBytes(uint64(result.Statistics.Summary.TotalBytesProcessed))
 
 	if err := marshal.WriteQueryResponseJSON(result, w); err != nil {
 		serverutil.WriteError(err, w)
@@ -185,6 +190,8 @@ func (q *QuerierAPI) LogQueryHandler(w http.ResponseWriter, r *http.Request) {
 		serverutil.WriteError(err, w)
 		return
 	}
+	// The frontend reports the bytes processed by queries in progress.
+	querier_stats.FromContext(ctx).AddProcessedBytes(uint64(result.Statistics.Summary.TotalBytesProcessed))
 
 	if err := marshal_legacy.WriteQueryResponseJSON(result, w); err != nil {
 		serverutil.WriteError(err, w)
@@ -438,6 +445,30 @@ func (q *QuerierAPI) IndexStatsHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// VolumeHandler queries the index for the volume of the streams matching a query, aggregated by some of their labels.
+func (q *QuerierAPI) VolumeHandler(w http.ResponseWriter, r *http.Request) {
+	req, err := loghttp.ParseVolumeQuery(r)
+	if err != nil {
+		serverutil.WriteError(httpgrpc.Errorf(http.StatusBadRequest, err.Error()), w)
+		return
+	}
+
+	resp, err := q.querier.Volume(r.Context(), req)
+	if err != nil {
+		serverutil.WriteError(err, w)
+		return
+	}
+	if !req.Streams {
+		resp.Streams = nil
+	}
+
+	err = marshal.WriteVolumeResponseJSON(resp, w)
+	if err != nil {
+		serverutil.WriteError(err, w)
+		return
+	}
+}
+
 // parseRegexQuery parses regex and query querystring from httpRequest and returns the combined LogQL query.
 // This is used only to keep regexp query string support until it gets fully deprecated.
 func parseRegexQuery(httpRequest *http.Request) (string, error) {
@@ -448,7 +479,7 @@ func parseRegexQuery(httpRequest *http.Request) (string, error) {
 		if err != nil {
 			return "", err
 		}
-		newExpr, err := syntax.AddFilterExpr(expr, labels.MatchRegexp, "", regexp)
+		newExpr, err := syntax.AddFilterExpr(expr, logqllog.LineMatchRegexp, "", regexp)
 		if err != nil {
 			return "", err
 		}
@@ -317,6 +317,34 @@ func (q *IngesterQuerier) Stats(ctx context.Context, userID string, from, throug
 	return &merged, nil
 }
 
+func (q *IngesterQuerier) Volume(ctx context.Context, userID string, from, through model.Time, targetLabels []string, matchers ...*labels.Matcher) (*index_stats.Volumes, error) {
+	resps, err := q.forAllIngesters(ctx, func(ctx context.Context, querierClient logproto.QuerierClient) (interface{}, error) {
+		return querierClient.GetVolume(ctx, &logproto.VolumeRequest{
+			From:         from,
+			Through:      through,
+			Matchers:     syntax.MatchersString(matchers),
+			TargetLabels: targetLabels,
+		})
+	})
+
+	if err != nil {
+		if isUnimplementedCallError(err) {
+			// Handle communication with older ingesters gracefully
+			return &index_stats.Volumes{}, nil
+		}
+		return nil, err
+	}
+
+	casted := make([]*index_stats.Volumes, 0, len(resps))
+	for _, resp := range resps {
+		casted = append(casted, resp.response.(*index_stats.Volumes))
+	}
+
+	// the streams are replicated to several ingesters.
+	merged := index_stats.MergeReplicaVolumes(0, casted...)
+	return &merged, nil
+}
+
 func convertMatchersToString(matchers []*labels.Matcher) string {
 	out := strings.Builder{}
 	out.WriteRune('{')
@@ -3,6 +3,7 @@ package querier
 import (
 	"context"
 
+	"github.com/cespare/xxhash/v2"
 	"github.com/go-kit/log"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/weaveworks/common/user"
@@ -186,6 +187,37 @@ func (q *MultiTenantQuerier) IndexStats(ctx context.Context, req *loghttp.RangeQ
 	return &merged, nil
 }
 
+func (q *MultiTenantQuerier) Volume(ctx context.Context, req *loghttp.VolumeQuery) (*stats.Volumes, error) {
+	tenantIDs, err := tenant.TenantIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tenantIDs) == 1 {
+		return q.Querier.Volume(ctx, req)
+	}
+
+	responses := make([]*stats.Volumes, len(tenantIDs))
+	for i, id := range tenantIDs {
+		singleContext := user.InjectOrgID(ctx, id)
+		resp, err := q.Querier.Volume(singleContext, req)
+		if err != nil {
+			return nil, err
+		}
+
+		// streams with the same labels in different tenants are different streams.
+		tenantHash := xxhash.Sum64String(id)
+		for j := range resp.Streams {
+			resp.Streams[j].Fingerprint ^= tenantHash
+		}
+		responses[i] = resp
+	}
+
+	merged := stats.MergeVolumes(int(req.Limit), responses...)
+
+	return &merged, nil
+}
+
 // removeTenantSelector filters the given tenant IDs based on any tenant ID filter the in passed selector.
 func removeTenantSelector(params logql.SelectSampleParams, tenantIDs []string) (map[string]struct{}, syntax.Expr, error) {
 	expr, err := params.Expr()
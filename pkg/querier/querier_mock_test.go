@@ -355,6 +355,10 @@ func (s *storeMock) Stats(ctx context.Context, userID string, from, through mode
 	return nil, nil
 }
 
+func (s *storeMock) Volume(ctx context.Context, userID string, from, through model.Time, targetLabels []string, matchers ...*labels.Matcher) (*stats.Volumes, error) {
+	return nil, nil
+}
+
 func (s *storeMock) Stop() {
 }
 
@@ -520,3 +524,7 @@ func (q *querierMock) Tail(ctx context.Context, req *logproto.TailRequest) (*Tai
 func (q *querierMock) IndexStats(ctx context.Context, req *loghttp.RangeQuery) (*stats.Stats, error) {
 	return nil, nil
 }
+
+func (q *querierMock) Volume(ctx context.Context, req *loghttp.VolumeQuery) (*stats.Volumes, error) {
+	return nil, nil
+}
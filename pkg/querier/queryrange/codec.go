@@ -26,6 +26,7 @@ import (
 	"github.com/grafana/loki/pkg/logqlmodel"
 	"github.com/grafana/loki/pkg/logqlmodel/stats"
 	"github.com/grafana/loki/pkg/querier/queryrange/queryrangebase"
+	index_stats "github.com/grafana/loki/pkg/storage/stores/index/stats"
 	"github.com/grafana/loki/pkg/util"
 	"github.com/grafana/loki/pkg/util/httpreq"
 	"github.com/grafana/loki/pkg/util/marshal"
@@ -271,6 +272,20 @@ func (Codec) DecodeRequest(_ context.Context, r *http.Request, forwardHeaders []
 			Through:  through,
 			Matchers: req.Query,
 		}, err
+	case VolumeOp:
+		req, err := loghttp.ParseVolumeQuery(r)
+		if err != nil {
+			return nil, httpgrpc.Errorf(http.StatusBadRequest, err.Error())
+		}
+		from, through := util.RoundToMilliseconds(req.Start, req.End)
+		return &logproto.VolumeRequest{
+			From:         from,
+			Through:      through,
+			Matchers:     req.Query,
+			TargetLabels: req.TargetLabels,
+			Limit:        req.Limit,
+			Streams:      req.Streams,
+		}, nil
 	default:
 		return nil, httpgrpc.Errorf(http.StatusBadRequest, fmt.Sprintf("unknown request path: %s", r.URL.Path))
 	}
@@ -396,6 +411,31 @@ func (Codec) EncodeRequest(ctx context.Context, r queryrangebase.Request) (*http
 			Header:     header,
 		}
 		return req.WithContext(ctx), nil
+	case *logproto.VolumeRequest:
+		params := url.Values{
+			"start": []string{fmt.Sprintf("%d", request.From.Time().UnixNano())},
+			"end":   []string{fmt.Sprintf("%d", request.Through.Time().UnixNano())},
+			"query": []string{request.GetQuery()},
+			"limit": []string{fmt.Sprintf("%d", request.Limit)},
+		}
+		if request.Streams {
+			params["streams"] = []string{"true"}
+		}
+		if len(request.TargetLabels) > 0 {
+			params["targetLabels"] = []string{strings.Join(request.TargetLabels, ",")}
+		}
+		u := &url.URL{
+			Path:     "/loki/api/v1/index/volume",
+			RawQuery: params.Encode(),
+		}
+		req := &http.Request{
+			Method:     "GET",
+			RequestURI: u.String(), // This is what the httpgrpc code looks at.
+			URL:        u,
+			Body:       http.NoBody,
+			Header:     header,
+		}
+		return req.WithContext(ctx), nil
 	default:
 		return nil, httpgrpc.Errorf(http.StatusInternalServerError, "invalid request format")
 	}
@@ -463,6 +503,15 @@ func (Codec) DecodeResponse(ctx context.Context, r *http.Response, req queryrang
 			Response: &resp,
 			Headers:  httpResponseHeadersToPromResponseHeaders(r.Header),
 		}, nil
+	case *logproto.VolumeRequest:
+		var resp logproto.VolumeResponse
+		if err := json.Unmarshal(buf, &resp); err != nil {
+			return nil, httpgrpc.Errorf(http.StatusInternalServerError, "error decoding response: %v", err)
+		}
+		return &VolumeResponse{
+			Response: &resp,
+			Headers:  httpResponseHeadersToPromResponseHeaders(r.Header),
+		}, nil
 	default:
 		var resp loghttp.QueryResponse
 		if err := resp.UnmarshalJSON(buf); err != nil {
@@ -579,6 +628,10 @@ func (Codec) EncodeResponse(ctx context.Context, res queryrangebase.Response) (*
 		if err := marshal.WriteIndexStatsResponseJSON(response.Response, &buf); err != nil {
 			return nil, err
 		}
+	case *VolumeResponse:
+		if err := marshal.WriteVolumeResponseJSON(response.Response, &buf); err != nil {
+			return nil, err
+		}
 
 	default:
 		return nil, httpgrpc.Errorf(http.StatusInternalServerError, "invalid response format")
@@ -664,6 +717,16 @@ func (Codec) MergeResponse(responses ...queryrangebase.Response) (queryrangebase
 			Version: labelNameRes.Version,
 			Data:    names,
 		}, nil
+	case *VolumeResponse:
+		volumes := make([]*logproto.VolumeResponse, 0, len(responses))
+		for _, res := range responses {
+			volumes = append(volumes, res.(*VolumeResponse).Response)
+		}
+		// The responses cover different time ranges: the volumes of each stream are summed.
+		merged := index_stats.MergeVolumes(0, volumes...)
+		return &VolumeResponse{
+			Response: &merged,
+		}, nil
 	default:
 		return nil, errors.New("unknown response in merging responses")
 	}
@@ -986,6 +1049,10 @@ func NewEmptyResponse(r queryrangebase.Request) (queryrangebase.Response, error)
 				},
 			},
 		}, nil
+	case *logproto.VolumeRequest:
+		return &VolumeResponse{
+			Response: &logproto.VolumeResponse{},
+		}, nil
 	case *LokiRequest:
 		// range query can either be metrics or logs
 		expr, err := syntax.ParseExpr(req.Query)
@@ -8,6 +8,8 @@ import (
 	"sync"
 	"time"
 
+	"github.com/dustin/go-humanize"
+	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
 	"github.com/grafana/dskit/tenant"
 	"github.com/opentracing/opentracing-go"
@@ -18,8 +20,10 @@ import (
 
 	"github.com/grafana/loki/pkg/logproto"
 	"github.com/grafana/loki/pkg/logql"
+	"github.com/grafana/loki/pkg/logql/syntax"
 	"github.com/grafana/loki/pkg/querier/queryrange/queryrangebase"
 	"github.com/grafana/loki/pkg/storage/config"
+	"github.com/grafana/loki/pkg/storage/stores/index/stats"
 	"github.com/grafana/loki/pkg/util"
 	util_log "github.com/grafana/loki/pkg/util/log"
 	"github.com/grafana/loki/pkg/util/spanlogger"
@@ -27,7 +31,13 @@ import (
 )
 
 const (
-	limitErrTmpl = "maximum of series (%d) reached for a single query"
+	limitErrTmpl                  = "maximum of series (%d) reached for a single query"
+	limErrQueryTooManyBytesTmpl   = "the query would read too many bytes (query: %s, limit: %s); consider adding more specific stream selectors or reduce the time range of the query"
+	limErrQuerierTooManyBytesTmpl = "query too large to execute on a single querier: (query: %s, limit: %s); consider adding more specific stream selectors, reduce the time range of the query, or adjust parallelization settings"
+
+	// maxConcurrentIndexStatsRequests is the number of matcher groups whose index stats
+	// are queried in parallel to estimate the bytes read by a query.
+	maxConcurrentIndexStatsRequests = 10
 )
 
 var (
@@ -42,9 +52,15 @@ type Limits interface {
 	MaxQuerySeries(string) int
 	MaxEntriesLimitPerQuery(string) int
 	MinShardingLookback(string) time.Duration
+	// ProbabilisticQueries returns whether queries with approximate results can be sharded.
+	ProbabilisticQueries(string) bool
 	// TSDBMaxQueryParallelism returns the limit to the number of split queries the
 	// frontend will process in parallel for TSDB queries.
 	TSDBMaxQueryParallelism(string) int
+	// MaxQueryBytesRead returns the limit to the number of bytes a query can read.
+	MaxQueryBytesRead(string) int
+	// MaxQuerierBytesRead returns the limit to the number of bytes a single subquery can read.
+	MaxQuerierBytesRead(string) int
 }
 
 type limits struct {
@@ -152,6 +168,94 @@ func (l limitsMiddleware) Do(ctx context.Context, r queryrangebase.Request) (que
 	return l.next.Do(ctx, r)
 }
 
+type querySizeLimiter struct {
+	configs      []config.PeriodConfig
+	logger       log.Logger
+	next         queryrangebase.Handler
+	statsHandler queryrangebase.Handler
+	limitFunc    func(string) int
+	limitErrTmpl string
+}
+
+func newQuerySizeLimiter(configs []config.PeriodConfig, logger log.Logger, statsHandler queryrangebase.Handler, limitFunc func(string) int, limitErrTmpl string) queryrangebase.Middleware {
+	return queryrangebase.MiddlewareFunc(func(next queryrangebase.Handler) queryrangebase.Handler {
+		return &querySizeLimiter{
+			configs:      configs,
+			logger:       logger,
+			next:         next,
+			statsHandler: statsHandler,
+			limitFunc:    limitFunc,
+			limitErrTmpl: limitErrTmpl,
+		}
+	})
+}
+
+// NewQuerySizeLimiterMiddleware creates a new Middleware that rejects queries reading more bytes than
+// the max_query_bytes_read limit. The bytes read are estimated from the index stats of the query
+// sent to the statsHandler. It is meant to be used after the results cache, so that the cached
+// parts of the query aren't counted.
+func NewQuerySizeLimiterMiddleware(configs []config.PeriodConfig, logger log.Logger, limits Limits, statsHandler queryrangebase.Handler) queryrangebase.Middleware {
+	return newQuerySizeLimiter(configs, logger, statsHandler, limits.MaxQueryBytesRead, limErrQueryTooManyBytesTmpl)
+}
+
+// NewQuerierSizeLimiterMiddleware creates a new Middleware that rejects subqueries reading more bytes than
+// the max_querier_bytes_read limit. It is meant to be used after the query is split, for subqueries
+// which are executed by a single querier.
+func NewQuerierSizeLimiterMiddleware(configs []config.PeriodConfig, logger log.Logger, limits Limits, statsHandler queryrangebase.Handler) queryrangebase.Middleware {
+	return newQuerySizeLimiter(configs, logger, statsHandler, limits.MaxQuerierBytesRead, limErrQuerierTooManyBytesTmpl)
+}
+
+func (q *querySizeLimiter) Do(ctx context.Context, r queryrangebase.Request) (queryrangebase.Response, error) {
+	sp, ctx := spanlogger.NewWithLogger(ctx, q.logger, "query_size_limits")
+	defer sp.Finish()
+
+	tenantIDs, err := tenant.TenantIDs(ctx)
+	if err != nil {
+		return nil, httpgrpc.Errorf(http.StatusBadRequest, err.Error())
+	}
+
+	maxBytesRead := validation.SmallestPositiveNonZeroIntPerTenant(tenantIDs, q.limitFunc)
+	if maxBytesRead == 0 {
+		return q.next.Do(ctx, r)
+	}
+
+	// The index stats are only available for TSDB, so the limit isn't enforced on queries
+	// which aren't entirely in a TSDB period.
+	if conf, err := ShardingConfigs(q.configs).ValidRange(r.GetStart(), r.GetEnd()); err != nil || conf.IndexType != config.TSDBType {
+		level.Debug(sp).Log("msg", "skipping the bytes read limit of a query outside of a TSDB period", "query", r.GetQuery())
+		return q.next.Do(ctx, r)
+	}
+
+	bytesRead, err := q.bytesRead(ctx, sp, r)
+	if err != nil {
+		return nil, err
+	}
+	if bytesRead > uint64(maxBytesRead) {
+		level.Warn(sp).Log("msg", "query exceeds the bytes read limit", "query", r.GetQuery(), "bytes", bytesRead, "limit", maxBytesRead)
+		return nil, httpgrpc.Errorf(http.StatusBadRequest, q.limitErrTmpl, humanize.IBytes(bytesRead), humanize.IBytes(uint64(maxBytesRead)))
+	}
+	return q.next.Do(ctx, r)
+}
+
+// bytesRead estimates the bytes read by the request by summing up the index stats of its matcher groups.
+func (q *querySizeLimiter) bytesRead(ctx context.Context, logger log.Logger, r queryrangebase.Request) (uint64, error) {
+	expr, err := syntax.ParseExpr(r.GetQuery())
+	if err != nil {
+		return 0, httpgrpc.Errorf(http.StatusBadRequest, err.Error())
+	}
+
+	grps := syntax.MatcherGroups(expr)
+	if len(grps) == 0 {
+		grps = append(grps, syntax.MatcherRange{})
+	}
+
+	results, err := getStatsForMatchers(ctx, logger, q.statsHandler, model.Time(r.GetStart()), model.Time(r.GetEnd()), grps, maxConcurrentIndexStatsRequests, 0)
+	if err != nil {
+		return 0, err
+	}
+	return stats.MergeStats(results...).Bytes, nil
+}
+
 type seriesLimiter struct {
 	hashes map[uint64]struct{}
 	rw     sync.RWMutex
@@ -199,7 +303,8 @@ func (sl *seriesLimiter) Do(ctx context.Context, req queryrangebase.Request) (qu
 	var hash uint64
 	for _, s := range promResponse.Response.Data.Result {
 		lbs := logproto.FromLabelAdaptersToLabels(s.Labels)
-		hash, sl.buf = lbs.HashWithoutLabels(sl.buf, []string(nil)...)
+		// The samples of the sketches of probabilistic queries are series of the same group.
+		hash, sl.buf = lbs.HashWithoutLabels(sl.buf, logql.SketchLabels...)
 		sl.hashes[hash] = struct{}{}
 	}
 	sl.rw.Unlock()
@@ -16,6 +16,7 @@ import (
 	"go.uber.org/atomic"
 	"gopkg.in/yaml.v2"
 
+	"github.com/grafana/loki/pkg/loghttp"
 	"github.com/grafana/loki/pkg/logproto"
 	"github.com/grafana/loki/pkg/logqlmodel"
 	"github.com/grafana/loki/pkg/querier/queryrange/queryrangebase"
@@ -140,6 +141,32 @@ func Test_seriesLimiter(t *testing.T) {
 	require.LessOrEqual(t, *c, 4)
 }
 
+func Test_seriesLimiterSketches(t *testing.T) {
+	// the bins of the quantile sketch of a group only count as one series.
+	var result []queryrangebase.SampleStream
+	for i := 0; i < 10; i++ {
+		result = append(result, queryrangebase.SampleStream{
+			Labels: []logproto.LabelAdapter{
+				{Name: "__quantile_sketch_bin__", Value: fmt.Sprintf("%d", i)},
+				{Name: "app", Value: "foo"},
+			},
+		})
+	}
+	handler := queryrangebase.HandlerFunc(func(context.Context, queryrangebase.Request) (queryrangebase.Response, error) {
+		return &LokiPromResponse{Response: &queryrangebase.PrometheusResponse{
+			Data: queryrangebase.PrometheusData{ResultType: loghttp.ResultTypeMatrix, Result: result},
+		}}, nil
+	})
+
+	_, err := newSeriesLimiter(1).Wrap(handler).Do(context.Background(), &LokiRequest{})
+	require.NoError(t, err)
+
+	// other series are still counted.
+	result[1].Labels = []logproto.LabelAdapter{{Name: "app", Value: "bar"}}
+	_, err = newSeriesLimiter(1).Wrap(handler).Do(context.Background(), &LokiRequest{})
+	require.Error(t, err)
+}
+
 func Test_MaxQueryParallelism(t *testing.T) {
 	maxQueryParallelism := 2
 	f, err := newfakeRoundTripper()
@@ -432,3 +459,112 @@ func Test_WeightedParallelism_DivideByZeroError(t *testing.T) {
 		require.Equal(t, 1, result)
 	})
 }
+
+func Test_querySizeLimiter(t *testing.T) {
+	var (
+		mtx           sync.Mutex
+		statsRequests []*logproto.IndexStatsRequest
+	)
+	statsHandler := queryrangebase.HandlerFunc(func(_ context.Context, r queryrangebase.Request) (queryrangebase.Response, error) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		statsRequests = append(statsRequests, r.(*logproto.IndexStatsRequest))
+		return &IndexStatsResponse{Response: &logproto.IndexStatsResponse{Bytes: 1 << 20}}, nil
+	})
+	called := 0
+	next := queryrangebase.HandlerFunc(func(_ context.Context, _ queryrangebase.Request) (queryrangebase.Response, error) {
+		called++
+		return &LokiPromResponse{}, nil
+	})
+
+	req := &LokiRequest{
+		Query:   `sum(rate({app="foo"}[1m])) / sum(rate({app="bar"} |= "err" [5m]))`,
+		StartTs: testTime.Add(-time.Hour),
+		EndTs:   testTime,
+		Path:    "/query_range",
+	}
+	ctx := user.InjectOrgID(context.Background(), "1")
+	tsdbConfigs := []config.PeriodConfig{
+		{
+			From:      config.DayTime{Time: model.TimeFromUnixNano(testTime.Add(-24 * time.Hour).UnixNano())},
+			IndexType: config.TSDBType,
+		},
+	}
+
+	for _, tc := range []struct {
+		name    string
+		limits  fakeLimits
+		configs []config.PeriodConfig
+		querier bool
+		err     string
+		stats   int
+	}{
+		{
+			name:   "disabled",
+			limits: fakeLimits{},
+		},
+		{
+			name:    "not tsdb",
+			limits:  fakeLimits{maxQueryBytesRead: 1},
+			configs: testSchemas,
+		},
+		{
+			name:   "query under limit",
+			limits: fakeLimits{maxQueryBytesRead: 2 << 20},
+			stats:  2,
+		},
+		{
+			name:   "query over limit",
+			limits: fakeLimits{maxQueryBytesRead: 1<<20 + 1},
+			err:    "the query would read too many bytes (query: 2.0 MiB, limit: 1.0 MiB)",
+			stats:  2,
+		},
+		{
+			name:    "querier over limit",
+			limits:  fakeLimits{maxQueryBytesRead: 10 << 20, maxQuerierBytesRead: 1 << 20},
+			querier: true,
+			err:     "query too large to execute on a single querier: (query: 2.0 MiB, limit: 1.0 MiB)",
+			stats:   2,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			statsRequests = nil
+			called = 0
+
+			configs := tsdbConfigs
+			if tc.configs != nil {
+				configs = tc.configs
+			}
+			mware := NewQuerySizeLimiterMiddleware(configs, util_log.Logger, tc.limits, statsHandler)
+			if tc.querier {
+				mware = NewQuerierSizeLimiterMiddleware(configs, util_log.Logger, tc.limits, statsHandler)
+			}
+			_, err := mware.Wrap(next).Do(ctx, req)
+			require.Len(t, statsRequests, tc.stats)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				require.Equal(t, 0, called)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, 1, called)
+		})
+	}
+
+	// the time range of the index stats is adjusted by the range of each matcher group.
+	statsRequests = nil
+	_, err := NewQuerySizeLimiterMiddleware(tsdbConfigs, util_log.Logger, fakeLimits{maxQueryBytesRead: 10 << 20}, statsHandler).Wrap(next).Do(ctx, req)
+	require.NoError(t, err)
+	require.Len(t, statsRequests, 2)
+	for _, r := range statsRequests {
+		switch r.Matchers {
+		case `{app="foo"}`:
+			require.Equal(t, model.TimeFromUnixNano(req.StartTs.Add(-time.Minute).UnixNano()), r.From)
+		case `{app="bar"}`:
+			require.Equal(t, model.TimeFromUnixNano(req.StartTs.Add(-5*time.Minute).UnixNano()), r.From)
+		default:
+			t.Fatalf("unexpected matchers %s", r.Matchers)
+		}
+		require.Equal(t, model.TimeFromUnixNano(req.EndTs.UnixNano()), r.Through)
+	}
+}
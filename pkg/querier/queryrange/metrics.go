@@ -13,6 +13,7 @@ type Metrics struct {
 	*MiddlewareMapperMetrics
 	*SplitByMetrics
 	*LogResultCacheMetrics
+	*VolumeCacheMetrics
 	*queryrangebase.ResultsCacheMetrics
 }
 
@@ -35,6 +36,7 @@ func NewMetrics(registerer prometheus.Registerer) *Metrics {
 		MiddlewareMapperMetrics:     NewMiddlewareMapperMetrics(registerer),
 		SplitByMetrics:              NewSplitByMetrics(registerer),
 		LogResultCacheMetrics:       NewLogResultCacheMetrics(registerer),
+		VolumeCacheMetrics:          NewVolumeCacheMetrics(registerer),
 		ResultsCacheMetrics:         queryrangebase.NewResultsCacheMetrics(registerer),
 	}
 }
@@ -728,6 +728,43 @@ func (m *IndexStatsResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_IndexStatsResponse proto.InternalMessageInfo
 
+type VolumeResponse struct {
+	Response *github_com_grafana_loki_pkg_logproto.VolumeResponse                                                 `protobuf:"bytes,1,opt,name=response,proto3,customtype=github.com/grafana/loki/pkg/logproto.VolumeResponse" json:"response,omitempty"`
+	Headers  []github_com_grafana_loki_pkg_querier_queryrange_queryrangebase_definitions.PrometheusResponseHeader `protobuf:"bytes,2,rep,name=Headers,proto3,customtype=github.com/grafana/loki/pkg/querier/queryrange/queryrangebase/definitions.PrometheusResponseHeader" json:"-"`
+}
+
+func (m *VolumeResponse) Reset()      { *m = VolumeResponse{} }
+func (*VolumeResponse) ProtoMessage() {}
+func (*VolumeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_51b9d53b40d11902, []int{10}
+}
+func (m *VolumeResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *VolumeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_VolumeResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *VolumeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VolumeResponse.Merge(m, src)
+}
+func (m *VolumeResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *VolumeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_VolumeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VolumeResponse proto.InternalMessageInfo
+
 func init() {
 	proto.RegisterType((*LokiRequest)(nil), "queryrange.LokiRequest")
 	proto.RegisterType((*LokiInstantRequest)(nil), "queryrange.LokiInstantRequest")
@@ -739,6 +776,7 @@ func init() {
 	proto.RegisterType((*LokiData)(nil), "queryrange.LokiData")
 	proto.RegisterType((*LokiPromResponse)(nil), "queryrange.LokiPromResponse")
 	proto.RegisterType((*IndexStatsResponse)(nil), "queryrange.IndexStatsResponse")
+	proto.RegisterType((*VolumeResponse)(nil), "queryrange.VolumeResponse")
 }
 
 func init() {
@@ -746,69 +784,71 @@ func init() {
 }
 
 var fileDescriptor_51b9d53b40d11902 = []byte{
-	// 978 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x55, 0xdd, 0x6e, 0x23, 0x35,
-	0x14, 0x8e, 0xf3, 0xd7, 0xc4, 0x65, 0x0b, 0xb8, 0x65, 0x77, 0x54, 0xd0, 0x4c, 0x14, 0x09, 0x08,
-	0x02, 0x66, 0x44, 0x97, 0x1f, 0x89, 0x3f, 0xb1, 0x43, 0x41, 0x54, 0x5a, 0x21, 0x34, 0x9b, 0x17,
-	0x70, 0x3a, 0xee, 0x64, 0xd4, 0xf9, 0xab, 0xed, 0xac, 0xe8, 0x1d, 0x0f, 0x00, 0xd2, 0xbe, 0x05,
-	0x08, 0x78, 0x02, 0x9e, 0xa0, 0x97, 0xbd, 0x5c, 0x55, 0x62, 0xa0, 0xe9, 0x0d, 0xe4, 0xaa, 0x8f,
-	0x80, 0x6c, 0xcf, 0x24, 0x4e, 0x7f, 0xd8, 0xa4, 0x7b, 0xb3, 0x17, 0x7b, 0x93, 0x9c, 0x73, 0x7c,
-	0x3e, 0xdb, 0xe7, 0x3b, 0xdf, 0xf1, 0xc0, 0x37, 0xb3, 0xfd, 0xc0, 0x39, 0x18, 0x11, 0x1a, 0x12,
-	0x2a, 0xff, 0x0f, 0x29, 0x4e, 0x02, 0xa2, 0x99, 0x76, 0x46, 0x53, 0x9e, 0x22, 0x38, 0x8b, 0x6c,
-	0x6e, 0x04, 0x69, 0x90, 0xca, 0xb0, 0x23, 0x2c, 0x95, 0xb1, 0x69, 0x05, 0x69, 0x1a, 0x44, 0xc4,
-	0x91, 0xde, 0x60, 0xb4, 0xe7, 0xf0, 0x30, 0x26, 0x8c, 0xe3, 0x38, 0x2b, 0x12, 0x5e, 0x15, 0x67,
-	0x45, 0x69, 0xa0, 0x90, 0xa5, 0x51, 0x2c, 0x76, 0x8a, 0xc5, 0x83, 0x28, 0x4e, 0x7d, 0x12, 0x39,
-	0x8c, 0x63, 0xce, 0xd4, 0x6f, 0x91, 0xf1, 0xe5, 0x13, 0xaf, 0x3a, 0xc0, 0x8c, 0x38, 0x3e, 0xd9,
-	0x0b, 0x93, 0x90, 0x87, 0x69, 0xc2, 0x74, 0xbb, 0xd8, 0xe4, 0xc3, 0xc5, 0x36, 0xb9, 0x58, 0x7e,
-	0xf7, 0xb8, 0x0a, 0x57, 0xef, 0xa7, 0xfb, 0xa1, 0x47, 0x0e, 0x46, 0x84, 0x71, 0xb4, 0x01, 0x1b,
-	0x32, 0xc7, 0x00, 0x1d, 0xd0, 0x6b, 0x7b, 0xca, 0x11, 0xd1, 0x28, 0x8c, 0x43, 0x6e, 0x54, 0x3b,
-	0xa0, 0x77, 0xcb, 0x53, 0x0e, 0x42, 0xb0, 0xce, 0x38, 0xc9, 0x8c, 0x5a, 0x07, 0xf4, 0x6a, 0x9e,
-	0xb4, 0xd1, 0x26, 0x6c, 0x85, 0x09, 0x27, 0xf4, 0x21, 0x8e, 0x8c, 0xb6, 0x8c, 0x4f, 0x7d, 0xf4,
-	0x39, 0x5c, 0x61, 0x1c, 0x53, 0xde, 0x67, 0x46, 0xbd, 0x03, 0x7a, 0xab, 0x5b, 0x9b, 0xb6, 0xa2,
-	0xd6, 0x2e, 0xa9, 0xb5, 0xfb, 0x25, 0xb5, 0x6e, 0xeb, 0x28, 0xb7, 0x2a, 0x8f, 0xfe, 0xb2, 0x80,
-	0x57, 0x82, 0xd0, 0xc7, 0xb0, 0x41, 0x12, 0xbf, 0xcf, 0x8c, 0xc6, 0x12, 0x68, 0x05, 0x41, 0xef,
-	0xc1, 0xb6, 0x1f, 0x52, 0xb2, 0x2b, 0x38, 0x33, 0x9a, 0x1d, 0xd0, 0x5b, 0xdb, 0x5a, 0xb7, 0xa7,
-	0xad, 0xda, 0x2e, 0x97, 0xbc, 0x59, 0x96, 0x28, 0x2f, 0xc3, 0x7c, 0x68, 0xac, 0x48, 0x26, 0xa4,
-	0x8d, 0xba, 0xb0, 0xc9, 0x86, 0x98, 0xfa, 0xcc, 0x68, 0x75, 0x6a, 0xbd, 0xb6, 0x0b, 0x27, 0xb9,
-	0x55, 0x44, 0xbc, 0xe2, 0xbf, 0xfb, 0x2f, 0x80, 0x48, 0x50, 0xba, 0x93, 0x30, 0x8e, 0x13, 0x7e,
-	0x13, 0x66, 0x3f, 0x85, 0x4d, 0x21, 0xb2, 0x3e, 0x93, 0xdc, 0x2e, 0x5a, 0x6a, 0x81, 0x99, 0xaf,
-	0xb5, 0xbe, 0x54, 0xad, 0x8d, 0x2b, 0x6b, 0x6d, 0x5e, 0x5b, 0xeb, 0x6f, 0x75, 0xf8, 0x82, 0x92,
-	0x0f, 0xcb, 0xd2, 0x84, 0x11, 0x01, 0x7a, 0xc0, 0x31, 0x1f, 0x31, 0x55, 0x66, 0x01, 0x92, 0x11,
-	0xaf, 0x58, 0x41, 0x5f, 0xc0, 0xfa, 0x36, 0xe6, 0x58, 0x96, 0xbc, 0xba, 0xb5, 0x61, 0x6b, 0xa2,
-	0x14, 0x7b, 0x89, 0x35, 0xf7, 0xb6, 0xa8, 0x6a, 0x92, 0x5b, 0x6b, 0x3e, 0xe6, 0xf8, 0x9d, 0x34,
-	0x0e, 0x39, 0x89, 0x33, 0x7e, 0xe8, 0x49, 0x24, 0xfa, 0x00, 0xb6, 0xbf, 0xa2, 0x34, 0xa5, 0xfd,
-	0xc3, 0x8c, 0x48, 0x8a, 0xda, 0xee, 0x9d, 0x49, 0x6e, 0xad, 0x93, 0x32, 0xa8, 0x21, 0x66, 0x99,
-	0xe8, 0x2d, 0xd8, 0x90, 0x8e, 0x24, 0xa5, 0xed, 0xae, 0x4f, 0x72, 0xeb, 0x45, 0x09, 0xd1, 0xd2,
-	0x55, 0xc6, 0x3c, 0x87, 0x8d, 0x85, 0x38, 0x9c, 0xb6, 0xb2, 0xa9, 0xb7, 0xd2, 0x80, 0x2b, 0x0f,
-	0x09, 0x65, 0x62, 0x9b, 0x15, 0x19, 0x2f, 0x5d, 0x74, 0x0f, 0x42, 0x41, 0x4c, 0xc8, 0x78, 0xb8,
-	0x2b, 0xf4, 0x24, 0xc8, 0xb8, 0x65, 0xab, 0x97, 0xc1, 0x23, 0x6c, 0x14, 0x71, 0x17, 0x15, 0x2c,
-	0x68, 0x89, 0x9e, 0x66, 0xa3, 0xdf, 0x01, 0x5c, 0xf9, 0x86, 0x60, 0x9f, 0x50, 0x66, 0xb4, 0x3b,
-	0xb5, 0xde, 0xea, 0xd6, 0xeb, 0xb6, 0xfe, 0x36, 0x7c, 0x47, 0xd3, 0x98, 0xf0, 0x21, 0x19, 0xb1,
-	0xb2, 0x41, 0x2a, 0xdb, 0xdd, 0x3f, 0xc9, 0xad, 0x41, 0x10, 0xf2, 0xe1, 0x68, 0x60, 0xef, 0xa6,
-	0xb1, 0x13, 0x50, 0xbc, 0x87, 0x13, 0xec, 0x44, 0xe9, 0x7e, 0xe8, 0x2c, 0xfd, 0x1e, 0x5d, 0x7b,
-	0xce, 0x24, 0xb7, 0xc0, 0xbb, 0x5e, 0x79, 0xc5, 0xee, 0x9f, 0x00, 0xbe, 0x2c, 0x3a, 0xfc, 0x40,
-	0xec, 0xcd, 0xb4, 0xc1, 0x88, 0x31, 0xdf, 0x1d, 0x1a, 0x40, 0xc8, 0xcc, 0x53, 0x8e, 0xfe, 0x58,
-	0x54, 0x9f, 0xea, 0xb1, 0xa8, 0x2d, 0xff, 0x58, 0x94, 0xd3, 0x50, 0xbf, 0x72, 0x1a, 0x1a, 0xd7,
-	0x4e, 0xc3, 0x8f, 0x35, 0x35, 0xf9, 0x65, 0x7d, 0x4b, 0xcc, 0xc4, 0xd7, 0xd3, 0x99, 0xa8, 0xc9,
-	0xdb, 0x4e, 0xa5, 0xa6, 0xf6, 0xda, 0xf1, 0x49, 0xc2, 0xc3, 0xbd, 0x90, 0xd0, 0x27, 0x4c, 0x86,
-	0x26, 0xb7, 0xda, 0xbc, 0xdc, 0x74, 0xad, 0xd4, 0x9f, 0x79, 0xad, 0x5c, 0x98, 0x8e, 0xc6, 0x0d,
-	0xa6, 0xa3, 0xfb, 0x33, 0x80, 0xaf, 0x88, 0x76, 0xdc, 0xc7, 0x03, 0x12, 0x7d, 0x8b, 0xe3, 0x99,
-	0xe4, 0x34, 0x71, 0x81, 0xa7, 0x12, 0x57, 0xf5, 0xe6, 0xe2, 0xaa, 0xcd, 0xc4, 0xd5, 0x3d, 0xaf,
-	0xc2, 0xdb, 0x17, 0x6f, 0xba, 0x84, 0x78, 0xde, 0xd0, 0xc4, 0xd3, 0x76, 0xd1, 0x73, 0x71, 0x2c,
-	0x20, 0x8e, 0x5f, 0x01, 0x6c, 0x95, 0x5f, 0x1b, 0x64, 0x43, 0xa8, 0x60, 0xf2, 0x83, 0xa2, 0x88,
-	0x5e, 0x13, 0x60, 0x3a, 0x8d, 0x7a, 0x5a, 0x06, 0x4a, 0x60, 0x53, 0x79, 0xc5, 0xbc, 0xde, 0xd1,
-	0xe6, 0x95, 0x53, 0x82, 0xe3, 0x7b, 0x3e, 0xce, 0x38, 0xa1, 0xee, 0x67, 0xe2, 0x16, 0x27, 0xb9,
-	0xf5, 0xf6, 0xff, 0x51, 0x74, 0x01, 0x2b, 0x1a, 0xac, 0xce, 0xf5, 0x8a, 0x53, 0xba, 0x3f, 0x01,
-	0xf8, 0x92, 0xb8, 0xac, 0xa0, 0x67, 0xaa, 0x8c, 0x6d, 0xd8, 0xa2, 0x85, 0x5d, 0xa8, 0xb8, 0x6b,
-	0xcf, 0x53, 0x7b, 0x05, 0x9d, 0x6e, 0xfd, 0x28, 0xb7, 0x80, 0x37, 0x45, 0xa2, 0xbb, 0x73, 0x54,
-	0x56, 0xaf, 0xa2, 0x52, 0x40, 0x2a, 0x73, 0xe4, 0xfd, 0x51, 0x85, 0x68, 0x27, 0xf1, 0xc9, 0xf7,
-	0x42, 0x80, 0x33, 0xad, 0x8e, 0x2e, 0xdd, 0xe8, 0xb5, 0x19, 0x31, 0x97, 0xf3, 0xdd, 0x4f, 0x4e,
-	0x72, 0xeb, 0xa3, 0x85, 0x98, 0xb9, 0x0c, 0xd6, 0x4a, 0xd0, 0xc5, 0x5b, 0x7d, 0xe6, 0xc5, 0xeb,
-	0xbe, 0x7f, 0x7c, 0x6a, 0x56, 0x1e, 0x9f, 0x9a, 0x95, 0xf3, 0x53, 0x13, 0xfc, 0x30, 0x36, 0xc1,
-	0x2f, 0x63, 0x13, 0x1c, 0x8d, 0x4d, 0x70, 0x3c, 0x36, 0xc1, 0xdf, 0x63, 0x13, 0xfc, 0x33, 0x36,
-	0x2b, 0xe7, 0x63, 0x13, 0x3c, 0x3a, 0x33, 0x2b, 0xc7, 0x67, 0x66, 0xe5, 0xf1, 0x99, 0x59, 0x19,
-	0x34, 0x25, 0x11, 0x77, 0xff, 0x0b, 0x00, 0x00, 0xff, 0xff, 0xb4, 0xd5, 0xfa, 0x96, 0xd9, 0x0c,
-	0x00, 0x00,
+	// 1009 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x55, 0xdd, 0x6e, 0x23, 0xb5,
+	0x17, 0x8f, 0x33, 0xf9, 0x74, 0xff, 0xdb, 0x3f, 0xb8, 0x65, 0x77, 0x54, 0xd0, 0x4c, 0x14, 0x09,
+	0x08, 0x02, 0x26, 0xa2, 0x05, 0x56, 0xe2, 0x4b, 0xec, 0x50, 0x10, 0x95, 0x56, 0x08, 0xcd, 0x46,
+	0xdc, 0x3b, 0x1d, 0x37, 0x19, 0x75, 0x3e, 0x52, 0xdb, 0x59, 0xd1, 0x3b, 0x1e, 0x00, 0xa4, 0x7d,
+	0x0b, 0x10, 0x20, 0x1e, 0x80, 0x27, 0xe8, 0x65, 0x2f, 0x57, 0x95, 0x18, 0x68, 0x7a, 0x03, 0xb9,
+	0xea, 0x23, 0x20, 0xdb, 0x33, 0x13, 0xa7, 0x1f, 0x6c, 0xd2, 0xbd, 0x59, 0x24, 0x6e, 0x92, 0x73,
+	0x8e, 0xcf, 0xcf, 0xf6, 0xf9, 0x9d, 0xdf, 0xf1, 0xc0, 0x57, 0x47, 0xfb, 0x83, 0xee, 0xc1, 0x98,
+	0xd0, 0x80, 0x50, 0xf9, 0x7f, 0x48, 0x71, 0x3c, 0x20, 0x9a, 0xe9, 0x8c, 0x68, 0xc2, 0x13, 0x04,
+	0x67, 0x91, 0x8d, 0xf5, 0x41, 0x32, 0x48, 0x64, 0xb8, 0x2b, 0x2c, 0x95, 0xb1, 0x61, 0x0f, 0x92,
+	0x64, 0x10, 0x92, 0xae, 0xf4, 0xfa, 0xe3, 0xbd, 0x2e, 0x0f, 0x22, 0xc2, 0x38, 0x8e, 0x46, 0x59,
+	0xc2, 0x8b, 0xe2, 0xac, 0x30, 0x19, 0x28, 0x64, 0x6e, 0x64, 0x8b, 0xad, 0x6c, 0xf1, 0x20, 0x8c,
+	0x12, 0x9f, 0x84, 0x5d, 0xc6, 0x31, 0x67, 0xea, 0x37, 0xcb, 0xf8, 0xe4, 0x89, 0x57, 0xed, 0x63,
+	0x46, 0xba, 0x3e, 0xd9, 0x0b, 0xe2, 0x80, 0x07, 0x49, 0xcc, 0x74, 0x3b, 0xdb, 0xe4, 0xdd, 0xc5,
+	0x36, 0xb9, 0x58, 0x7e, 0xfb, 0xb8, 0x0c, 0x57, 0xee, 0x27, 0xfb, 0x81, 0x47, 0x0e, 0xc6, 0x84,
+	0x71, 0xb4, 0x0e, 0xab, 0x32, 0xc7, 0x04, 0x2d, 0xd0, 0x69, 0x7a, 0xca, 0x11, 0xd1, 0x30, 0x88,
+	0x02, 0x6e, 0x96, 0x5b, 0xa0, 0x73, 0xcb, 0x53, 0x0e, 0x42, 0xb0, 0xc2, 0x38, 0x19, 0x99, 0x46,
+	0x0b, 0x74, 0x0c, 0x4f, 0xda, 0x68, 0x03, 0x36, 0x82, 0x98, 0x13, 0xfa, 0x10, 0x87, 0x66, 0x53,
+	0xc6, 0x0b, 0x1f, 0x7d, 0x04, 0xeb, 0x8c, 0x63, 0xca, 0x7b, 0xcc, 0xac, 0xb4, 0x40, 0x67, 0x65,
+	0x73, 0xc3, 0x51, 0xd4, 0x3a, 0x39, 0xb5, 0x4e, 0x2f, 0xa7, 0xd6, 0x6d, 0x1c, 0xa5, 0x76, 0xe9,
+	0xd1, 0xef, 0x36, 0xf0, 0x72, 0x10, 0x7a, 0x0f, 0x56, 0x49, 0xec, 0xf7, 0x98, 0x59, 0x5d, 0x02,
+	0xad, 0x20, 0xe8, 0x2d, 0xd8, 0xf4, 0x03, 0x4a, 0x76, 0x05, 0x67, 0x66, 0xad, 0x05, 0x3a, 0xab,
+	0x9b, 0x6b, 0x4e, 0xd1, 0xaa, 0xed, 0x7c, 0xc9, 0x9b, 0x65, 0x89, 0xf2, 0x46, 0x98, 0x0f, 0xcd,
+	0xba, 0x64, 0x42, 0xda, 0xa8, 0x0d, 0x6b, 0x6c, 0x88, 0xa9, 0xcf, 0xcc, 0x46, 0xcb, 0xe8, 0x34,
+	0x5d, 0x38, 0x4d, 0xed, 0x2c, 0xe2, 0x65, 0xff, 0xed, 0xbf, 0x00, 0x44, 0x82, 0xd2, 0x9d, 0x98,
+	0x71, 0x1c, 0xf3, 0x9b, 0x30, 0xfb, 0x01, 0xac, 0x09, 0x91, 0xf5, 0x98, 0x69, 0x2c, 0x51, 0x6a,
+	0x86, 0x99, 0xaf, 0xb5, 0xb2, 0x54, 0xad, 0xd5, 0x2b, 0x6b, 0xad, 0x5d, 0x5b, 0xeb, 0x4f, 0x15,
+	0xf8, 0x3f, 0x25, 0x1f, 0x36, 0x4a, 0x62, 0x46, 0x04, 0xe8, 0x01, 0xc7, 0x7c, 0xcc, 0x54, 0x99,
+	0x19, 0x48, 0x46, 0xbc, 0x6c, 0x05, 0x7d, 0x0c, 0x2b, 0xdb, 0x98, 0x63, 0x59, 0xf2, 0xca, 0xe6,
+	0xba, 0xa3, 0x89, 0x52, 0xec, 0x25, 0xd6, 0xdc, 0xdb, 0xa2, 0xaa, 0x69, 0x6a, 0xaf, 0xfa, 0x98,
+	0xe3, 0x37, 0x92, 0x28, 0xe0, 0x24, 0x1a, 0xf1, 0x43, 0x4f, 0x22, 0xd1, 0x3b, 0xb0, 0xf9, 0x29,
+	0xa5, 0x09, 0xed, 0x1d, 0x8e, 0x88, 0xa4, 0xa8, 0xe9, 0xde, 0x99, 0xa6, 0xf6, 0x1a, 0xc9, 0x83,
+	0x1a, 0x62, 0x96, 0x89, 0x5e, 0x83, 0x55, 0xe9, 0x48, 0x52, 0x9a, 0xee, 0xda, 0x34, 0xb5, 0xff,
+	0x2f, 0x21, 0x5a, 0xba, 0xca, 0x98, 0xe7, 0xb0, 0xba, 0x10, 0x87, 0x45, 0x2b, 0x6b, 0x7a, 0x2b,
+	0x4d, 0x58, 0x7f, 0x48, 0x28, 0x13, 0xdb, 0xd4, 0x65, 0x3c, 0x77, 0xd1, 0x3d, 0x08, 0x05, 0x31,
+	0x01, 0xe3, 0xc1, 0xae, 0xd0, 0x93, 0x20, 0xe3, 0x96, 0xa3, 0x5e, 0x06, 0x8f, 0xb0, 0x71, 0xc8,
+	0x5d, 0x94, 0xb1, 0xa0, 0x25, 0x7a, 0x9a, 0x8d, 0x7e, 0x06, 0xb0, 0xfe, 0x39, 0xc1, 0x3e, 0xa1,
+	0xcc, 0x6c, 0xb6, 0x8c, 0xce, 0xca, 0xe6, 0xcb, 0x8e, 0xfe, 0x36, 0x7c, 0x49, 0x93, 0x88, 0xf0,
+	0x21, 0x19, 0xb3, 0xbc, 0x41, 0x2a, 0xdb, 0xdd, 0x3f, 0x49, 0xed, 0xfe, 0x20, 0xe0, 0xc3, 0x71,
+	0xdf, 0xd9, 0x4d, 0xa2, 0xee, 0x80, 0xe2, 0x3d, 0x1c, 0xe3, 0x6e, 0x98, 0xec, 0x07, 0xdd, 0xa5,
+	0xdf, 0xa3, 0x6b, 0xcf, 0x99, 0xa6, 0x36, 0x78, 0xd3, 0xcb, 0xaf, 0xd8, 0xfe, 0x0d, 0xc0, 0xe7,
+	0x45, 0x87, 0x1f, 0x88, 0xbd, 0x99, 0x36, 0x18, 0x11, 0xe6, 0xbb, 0x43, 0x13, 0x08, 0x99, 0x79,
+	0xca, 0xd1, 0x1f, 0x8b, 0xf2, 0x53, 0x3d, 0x16, 0xc6, 0xf2, 0x8f, 0x45, 0x3e, 0x0d, 0x95, 0x2b,
+	0xa7, 0xa1, 0x7a, 0xed, 0x34, 0x7c, 0x6b, 0x40, 0xa4, 0xd7, 0xb7, 0xc4, 0x4c, 0x7c, 0x56, 0xcc,
+	0x84, 0x21, 0x6f, 0x5b, 0x48, 0x4d, 0xed, 0xb5, 0xe3, 0x93, 0x98, 0x07, 0x7b, 0x01, 0xa1, 0x4f,
+	0x98, 0x0c, 0x4d, 0x6e, 0xc6, 0xbc, 0xdc, 0x74, 0xad, 0x54, 0x9e, 0x79, 0xad, 0x5c, 0x98, 0x8e,
+	0xea, 0x0d, 0xa6, 0xa3, 0xfd, 0x3d, 0x80, 0x2f, 0x88, 0x76, 0xdc, 0xc7, 0x7d, 0x12, 0x7e, 0x81,
+	0xa3, 0x99, 0xe4, 0x34, 0x71, 0x81, 0xa7, 0x12, 0x57, 0xf9, 0xe6, 0xe2, 0x32, 0x66, 0xe2, 0x6a,
+	0x9f, 0x97, 0xe1, 0xed, 0x8b, 0x37, 0x5d, 0x42, 0x3c, 0xaf, 0x68, 0xe2, 0x69, 0xba, 0xe8, 0x3f,
+	0x71, 0x2c, 0x20, 0x8e, 0x1f, 0x01, 0x6c, 0xe4, 0x5f, 0x1b, 0xe4, 0x40, 0xa8, 0x60, 0xf2, 0x83,
+	0xa2, 0x88, 0x5e, 0x15, 0x60, 0x5a, 0x44, 0x3d, 0x2d, 0x03, 0xc5, 0xb0, 0xa6, 0xbc, 0x6c, 0x5e,
+	0xef, 0x68, 0xf3, 0xca, 0x29, 0xc1, 0xd1, 0x3d, 0x1f, 0x8f, 0x38, 0xa1, 0xee, 0x87, 0xe2, 0x16,
+	0x27, 0xa9, 0xfd, 0xfa, 0x3f, 0x51, 0x74, 0x01, 0x2b, 0x1a, 0xac, 0xce, 0xf5, 0xb2, 0x53, 0xda,
+	0xdf, 0x01, 0xf8, 0x9c, 0xb8, 0xac, 0xa0, 0xa7, 0x50, 0xc6, 0x36, 0x6c, 0xd0, 0xcc, 0xce, 0x54,
+	0xdc, 0x76, 0xe6, 0xa9, 0xbd, 0x82, 0x4e, 0xb7, 0x72, 0x94, 0xda, 0xc0, 0x2b, 0x90, 0x68, 0x6b,
+	0x8e, 0xca, 0xf2, 0x55, 0x54, 0x0a, 0x48, 0x69, 0x8e, 0xbc, 0x5f, 0xcb, 0x10, 0xed, 0xc4, 0x3e,
+	0xf9, 0x5a, 0x08, 0x70, 0xa6, 0xd5, 0xf1, 0xa5, 0x1b, 0xbd, 0x34, 0x23, 0xe6, 0x72, 0xbe, 0xfb,
+	0xfe, 0x49, 0x6a, 0xdf, 0x5d, 0x88, 0x99, 0xcb, 0x60, 0xad, 0x04, 0x5d, 0xbc, 0xe5, 0x67, 0xff,
+	0x2b, 0xf8, 0x4b, 0x19, 0xae, 0x7e, 0x95, 0x84, 0xe3, 0x88, 0x14, 0xc4, 0x45, 0x97, 0x88, 0x33,
+	0x67, 0xc4, 0xcd, 0xe7, 0xba, 0x77, 0x4f, 0x52, 0x7b, 0x6b, 0x21, 0xd2, 0xe6, 0x81, 0xff, 0x5a,
+	0xc2, 0xdc, 0xb7, 0x8f, 0x4f, 0xad, 0xd2, 0xe3, 0x53, 0xab, 0x74, 0x7e, 0x6a, 0x81, 0x6f, 0x26,
+	0x16, 0xf8, 0x61, 0x62, 0x81, 0xa3, 0x89, 0x05, 0x8e, 0x27, 0x16, 0xf8, 0x63, 0x62, 0x81, 0x3f,
+	0x27, 0x56, 0xe9, 0x7c, 0x62, 0x81, 0x47, 0x67, 0x56, 0xe9, 0xf8, 0xcc, 0x2a, 0x3d, 0x3e, 0xb3,
+	0x4a, 0xfd, 0x9a, 0x24, 0x61, 0xeb, 0xef, 0x01, 0x00, 0x32, 0xf0, 0x5d, 0x64, 0x0a, 0x0e, 0x00,
+	0x00,
 }
 
 func (this *LokiRequest) Equal(that interface{}) bool {
@@ -1224,6 +1264,42 @@ func (this *IndexStatsResponse) Equal(that interface{}) bool {
 	}
 	return true
 }
+func (this *VolumeResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*VolumeResponse)
+	if !ok {
+		that2, ok := that.(VolumeResponse)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if that1.Response == nil {
+		if this.Response != nil {
+			return false
+		}
+	} else if !this.Response.Equal(*that1.Response) {
+		return false
+	}
+	if len(this.Headers) != len(that1.Headers) {
+		return false
+	}
+	for i := range this.Headers {
+		if !this.Headers[i].Equal(that1.Headers[i]) {
+			return false
+		}
+	}
+	return true
+}
 func (this *LokiRequest) GoString() string {
 	if this == nil {
 		return "nil"
@@ -1370,6 +1446,17 @@ func (this *IndexStatsResponse) GoString() string {
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
+func (this *VolumeResponse) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&queryrange.VolumeResponse{")
+	s = append(s, "Response: "+fmt.Sprintf("%#v", this.Response)+",\n")
+	s = append(s, "Headers: "+fmt.Sprintf("%#v", this.Headers)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
 func valueToGoStringQueryrange(v interface{}, typ string) string {
 	rv := reflect.ValueOf(v)
 	if rv.IsNil() {
@@ -2006,6 +2093,55 @@ func (m *IndexStatsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *VolumeResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *VolumeResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *VolumeResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Headers) > 0 {
+		for iNdEx := len(m.Headers) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size := m.Headers[iNdEx].Size()
+				i -= size
+				if _, err := m.Headers[iNdEx].MarshalTo(dAtA[i:]); err != nil {
+					return 0, err
+				}
+				i = encodeVarintQueryrange(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if m.Response != nil {
+		{
+			size := m.Response.Size()
+			i -= size
+			if _, err := m.Response.MarshalTo(dAtA[i:]); err != nil {
+				return 0, err
+			}
+			i = encodeVarintQueryrange(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
 func encodeVarintQueryrange(dAtA []byte, offset int, v uint64) int {
 	offset -= sovQueryrange(v)
 	base := offset
@@ -2286,6 +2422,25 @@ func (m *IndexStatsResponse) Size() (n int) {
 	return n
 }
 
+func (m *VolumeResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Response != nil {
+		l = m.Response.Size()
+		n += 1 + l + sovQueryrange(uint64(l))
+	}
+	if len(m.Headers) > 0 {
+		for _, e := range m.Headers {
+			l = e.Size()
+			n += 1 + l + sovQueryrange(uint64(l))
+		}
+	}
+	return n
+}
+
 func sovQueryrange(x uint64) (n int) {
 	return (math_bits.Len64(x|1) + 6) / 7
 }
@@ -2435,6 +2590,17 @@ func (this *IndexStatsResponse) String() string {
 	}, "")
 	return s
 }
+func (this *VolumeResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&VolumeResponse{`,
+		`Response:` + fmt.Sprintf("%v", this.Response) + `,`,
+		`Headers:` + fmt.Sprintf("%v", this.Headers) + `,`,
+		`}`,
+	}, "")
+	return s
+}
 func valueToStringQueryrange(v interface{}) string {
 	rv := reflect.ValueOf(v)
 	if rv.IsNil() {
@@ -4398,6 +4564,129 @@ func (m *IndexStatsResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *VolumeResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQueryrange
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: VolumeResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: VolumeResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Response", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQueryrange
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Response == nil {
+				m.Response = &github_com_grafana_loki_pkg_logproto.VolumeResponse{}
+			}
+			if err := m.Response.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Headers", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQueryrange
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Headers = append(m.Headers, github_com_grafana_loki_pkg_querier_queryrange_queryrangebase_definitions.PrometheusResponseHeader{})
+			if err := m.Headers[len(m.Headers)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQueryrange(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipQueryrange(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
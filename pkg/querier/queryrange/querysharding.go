@@ -48,19 +48,18 @@ func NewQueryShardMiddleware(
 		return queryrangebase.PassthroughMiddleware
 	}
 
-	mapperware := queryrangebase.MiddlewareFunc(func(next queryrangebase.Handler) queryrangebase.Handler {
-		return newASTMapperware(confs, next, logger, shardingMetrics, limits)
-	})
-
 	return queryrangebase.MiddlewareFunc(func(next queryrangebase.Handler) queryrangebase.Handler {
+		// Queries which are not sharded are executed by a single querier,
+		// so they are subject to the max_querier_bytes_read limit.
+		bypass := NewQuerierSizeLimiterMiddleware(confs, logger, limits, next).Wrap(next)
+
 		return &shardSplitter{
 			limits: limits,
-			shardingware: queryrangebase.MergeMiddlewares(
-				queryrangebase.InstrumentMiddleware("shardingware", middlewareMetrics),
-				mapperware,
-			).Wrap(next),
+			shardingware: queryrangebase.InstrumentMiddleware("shardingware", middlewareMetrics).Wrap(
+				newASTMapperware(confs, next, bypass, logger, shardingMetrics, limits),
+			),
 			now:  time.Now,
-			next: queryrangebase.InstrumentMiddleware("sharding-bypass", middlewareMetrics).Wrap(next),
+			next: queryrangebase.InstrumentMiddleware("sharding-bypass", middlewareMetrics).Wrap(bypass),
 		}
 	})
 }
@@ -68,6 +67,7 @@ func NewQueryShardMiddleware(
 func newASTMapperware(
 	confs ShardingConfigs,
 	next queryrangebase.Handler,
+	bypass queryrangebase.Handler,
 	logger log.Logger,
 	metrics *logql.MapperMetrics,
 	limits Limits,
@@ -77,6 +77,7 @@ func newASTMapperware(
 		logger:  log.With(logger, "middleware", "QueryShard.astMapperware"),
 		limits:  limits,
 		next:    next,
+		bypass:  bypass,
 		ng:      logql.NewDownstreamEngine(logql.EngineOpts{}, DownstreamHandler{next: next, limits: limits}, limits, logger),
 		metrics: metrics,
 	}
@@ -86,7 +87,8 @@ type astMapperware struct {
 	confs   ShardingConfigs
 	logger  log.Logger
 	limits  Limits
-	next    queryrangebase.Handler
+	next    queryrangebase.Handler // handler for sharded queries
+	bypass  queryrangebase.Handler // handler for queries which cannot be sharded
 	ng      *logql.DownstreamEngine
 	metrics *logql.MapperMetrics
 }
@@ -96,14 +98,14 @@ func (ast *astMapperware) Do(ctx context.Context, r queryrangebase.Request) (que
 	maxRVDuration, maxOffset, err := maxRangeVectorAndOffsetDuration(r.GetQuery())
 	if err != nil {
 		level.Warn(logger).Log("err", err.Error(), "msg", "failed to get range-vector and offset duration so skipped AST mapper for request")
-		return ast.next.Do(ctx, r)
+		return ast.bypass.Do(ctx, r)
 	}
 
 	conf, err := ast.confs.GetConf(int64(model.Time(r.GetStart()).Add(-maxRVDuration).Add(-maxOffset)), int64(model.Time(r.GetEnd()).Add(-maxOffset)))
 	// cannot shard with this timerange
 	if err != nil {
 		level.Warn(logger).Log("err", err.Error(), "msg", "skipped AST mapper for request")
-		return ast.next.Do(ctx, r)
+		return ast.bypass.Do(ctx, r)
 	}
 
 	tenants, err := tenant.TenantIDs(ctx)
@@ -117,14 +119,16 @@ func (ast *astMapperware) Do(ctx context.Context, r queryrangebase.Request) (que
 		ast.ng.Opts().MaxLookBackPeriod,
 		ast.logger,
 		MinWeightedParallelism(ctx, tenants, ast.confs, ast.limits, model.Time(r.GetStart()), model.Time(r.GetEnd())),
+		validation.SmallestPositiveNonZeroIntPerTenant(tenants, ast.limits.MaxQuerierBytesRead),
 		r,
 		ast.next,
 	)
 	if !ok {
-		return ast.next.Do(ctx, r)
+		return ast.bypass.Do(ctx, r)
 	}
 
-	mapper := logql.NewShardMapper(resolver, ast.metrics)
+	probabilistic := validation.AllTrueBooleansPerTenant(tenants, ast.limits.ProbabilisticQueries)
+	mapper := logql.NewShardMapper(resolver, ast.metrics, probabilistic)
 	if err != nil {
 		return nil, err
 	}
@@ -139,7 +143,7 @@ func (ast *astMapperware) Do(ctx context.Context, r queryrangebase.Request) (que
 	if noop {
 		// the ast can't be mapped to a sharded equivalent
 		// so we can bypass the sharding engine.
-		return ast.next.Do(ctx, r)
+		return ast.bypass.Do(ctx, r)
 	}
 
 	params, err := paramsFromRequest(r)
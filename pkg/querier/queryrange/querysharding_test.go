@@ -164,6 +164,7 @@ func Test_astMapper(t *testing.T) {
 			},
 		},
 		handler,
+		handler,
 		log.NewNopLogger(),
 		nilShardingMetrics,
 		fakeLimits{maxSeries: math.MaxInt32, maxQueryParallelism: 1, queryTimeout: time.Second},
@@ -197,6 +198,7 @@ func Test_ShardingByPass(t *testing.T) {
 			},
 		},
 		handler,
+		handler,
 		log.NewNopLogger(),
 		nilShardingMetrics,
 		fakeLimits{maxSeries: math.MaxInt32, maxQueryParallelism: 1},
@@ -550,6 +552,7 @@ func TestShardingAcrossConfigs_ASTMapper(t *testing.T) {
 			mware := newASTMapperware(
 				confs,
 				handler,
+				handler,
 				log.NewNopLogger(),
 				nilShardingMetrics,
 				fakeLimits{maxSeries: math.MaxInt32, maxQueryParallelism: 1, queryTimeout: time.Second},
@@ -13,6 +13,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/promql"
 	"github.com/prometheus/prometheus/promql/parser"
@@ -347,6 +348,62 @@ func TestLabelsTripperware(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestVolumeTripperware(t *testing.T) {
+	tpw, stopper, err := NewTripperware(testConfig, util_log.Logger, fakeLimits{maxQueryLength: 48 * time.Hour, maxQueryParallelism: 1}, config.SchemaConfig{Configs: testSchemas}, nil, false, nil)
+	if stopper != nil {
+		defer stopper.Stop()
+	}
+	require.NoError(t, err)
+	rt, err := newfakeRoundTripper()
+	require.NoError(t, err)
+	defer rt.Close()
+
+	lreq := &logproto.VolumeRequest{
+		From:     model.TimeFromUnixNano(testTime.Add(-25 * time.Hour).UnixNano()), // bigger than the split interval
+		Through:  model.TimeFromUnixNano(testTime.UnixNano()),
+		Matchers: `{app=~".+"}`,
+		Limit:    1,
+	}
+
+	ctx := user.InjectOrgID(context.Background(), "1")
+	req, err := LokiCodec.EncodeRequest(ctx, lreq)
+	require.NoError(t, err)
+
+	req = req.WithContext(ctx)
+	err = user.InjectOrgIDIntoHTTPRequest(ctx, req)
+	require.NoError(t, err)
+
+	handler := newFakeHandler(
+		// we expect 2 calls, both asking for the volumes of the streams.
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "true", r.FormValue("streams"))
+			require.NoError(t, marshal.WriteVolumeResponseJSON(&logproto.VolumeResponse{Streams: []logproto.StreamVolume{
+				{Fingerprint: 1, Name: `{app="foo"}`, Chunks: 1, Bytes: 10, Entries: 1},
+				{Fingerprint: 2, Name: `{app="bar"}`, Chunks: 1, Bytes: 15, Entries: 1},
+			}}, w))
+		}),
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "true", r.FormValue("streams"))
+			require.NoError(t, marshal.WriteVolumeResponseJSON(&logproto.VolumeResponse{Streams: []logproto.StreamVolume{
+				{Fingerprint: 1, Name: `{app="foo"}`, Chunks: 1, Bytes: 10, Entries: 1},
+			}}, w))
+		}),
+	)
+	rt.setHandler(handler)
+	resp, err := tpw(rt).RoundTrip(req)
+	// verify 2 calls have been made to downstream.
+	require.Equal(t, 2, handler.count)
+	require.NoError(t, err)
+	volumeResponse, err := LokiCodec.DecodeResponse(ctx, resp, lreq)
+	require.NoError(t, err)
+	res, ok := volumeResponse.(*VolumeResponse)
+	require.Equal(t, true, ok)
+	// the stream present in both splits is only counted once, and the streams are not returned.
+	require.Equal(t, &logproto.VolumeResponse{Volumes: []logproto.Volume{
+		{Name: `{app="foo"}`, Streams: 1, Chunks: 2, Bytes: 20, Entries: 2},
+	}}, res.Response)
+}
+
 func TestLogNoFilter(t *testing.T) {
 	tpw, stopper, err := NewTripperware(testConfig, util_log.Logger, fakeLimits{maxQueryParallelism: 1}, config.SchemaConfig{Configs: testSchemas}, nil, false, nil)
 	if stopper != nil {
@@ -460,6 +517,10 @@ func TestPostQueries(t *testing.T) {
 			t.Error("unexpected instant roundtripper called")
 			return nil, nil
 		}),
+		queryrangebase.RoundTripFunc(func(*http.Request) (*http.Response, error) {
+			t.Error("unexpected volume roundtripper called")
+			return nil, nil
+		}),
 		fakeLimits{},
 	).RoundTrip(req)
 	require.NoError(t, err)
@@ -557,6 +618,16 @@ func Test_getOperation(t *testing.T) {
 			path:       "/prom/label/__name__/values",
 			expectedOp: LabelNamesOp,
 		},
+		{
+			name:       "index_stats",
+			path:       "/loki/api/v1/index/stats",
+			expectedOp: IndexStatsOp,
+		},
+		{
+			name:       "volume",
+			path:       "/loki/api/v1/index/volume",
+			expectedOp: VolumeOp,
+		},
 	}
 
 	for _, tc := range cases {
@@ -577,6 +648,9 @@ type fakeLimits struct {
 	splits                  map[string]time.Duration
 	minShardingLookback     time.Duration
 	queryTimeout            time.Duration
+	probabilisticQueries    bool
+	maxQueryBytesRead       int
+	maxQuerierBytesRead     int
 }
 
 func (f fakeLimits) QuerySplitDuration(key string) time.Duration {
@@ -621,6 +695,18 @@ func (f fakeLimits) MinShardingLookback(string) time.Duration {
 	return f.minShardingLookback
 }
 
+func (f fakeLimits) ProbabilisticQueries(string) bool {
+	return f.probabilisticQueries
+}
+
+func (f fakeLimits) MaxQueryBytesRead(string) int {
+	return f.maxQueryBytesRead
+}
+
+func (f fakeLimits) MaxQuerierBytesRead(string) int {
+	return f.maxQuerierBytesRead
+}
+
 func (f fakeLimits) QueryTimeout(string) time.Duration {
 	return f.queryTimeout
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	math "math"
+	"net/http"
 	strings "strings"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/go-kit/log/level"
 	"github.com/grafana/dskit/concurrency"
 	"github.com/prometheus/common/model"
+	"github.com/weaveworks/common/httpgrpc"
 
 	"github.com/grafana/loki/pkg/logproto"
 	"github.com/grafana/loki/pkg/logql"
@@ -28,18 +30,20 @@ func shardResolverForConf(
 	defaultLookback time.Duration,
 	logger log.Logger,
 	maxParallelism int,
+	maxShardedQueryBytes int,
 	r queryrangebase.Request,
 	handler queryrangebase.Handler,
 ) (logql.ShardResolver, bool) {
 	if conf.IndexType == config.TSDBType {
 		return &dynamicShardResolver{
-			ctx:             ctx,
-			logger:          logger,
-			handler:         handler,
-			from:            model.Time(r.GetStart()),
-			through:         model.Time(r.GetEnd()),
-			maxParallelism:  maxParallelism,
-			defaultLookback: defaultLookback,
+			ctx:                  ctx,
+			logger:               logger,
+			handler:              handler,
+			from:                 model.Time(r.GetStart()),
+			through:              model.Time(r.GetEnd()),
+			maxParallelism:       maxParallelism,
+			maxShardedQueryBytes: maxShardedQueryBytes,
+			defaultLookback:      defaultLookback,
 		}, true
 	}
 	if conf.RowShards < 2 {
@@ -53,39 +57,36 @@ type dynamicShardResolver struct {
 	handler queryrangebase.Handler
 	logger  log.Logger
 
-	from, through   model.Time
-	maxParallelism  int
-	defaultLookback time.Duration
+	from, through        model.Time
+	maxParallelism       int
+	maxShardedQueryBytes int
+	defaultLookback      time.Duration
 }
 
-func (r *dynamicShardResolver) Shards(e syntax.Expr) (int, error) {
-	sp, ctx := spanlogger.NewWithLogger(r.ctx, r.logger, "dynamicShardResolver.Shards")
-	defer sp.Finish()
-	// We try to shard subtrees in the AST independently if possible, although
-	// nested binary expressions can make this difficult. In this case,
-	// we query the index stats for all matcher groups then sum the results.
-	grps := syntax.MatcherGroups(e)
-
-	// If there are zero matchers groups, we'll inject one to query everything
-	if len(grps) == 0 {
-		grps = append(grps, syntax.MatcherRange{})
-	}
-
-	results := make([]*stats.Stats, 0, len(grps))
-
-	start := time.Now()
-	if err := concurrency.ForEachJob(ctx, len(grps), r.maxParallelism, func(ctx context.Context, i int) error {
+// getStatsForMatchers queries the index stats of every matcher group, adjusting the
+// time range of each group by its range interval and offset.
+func getStatsForMatchers(
+	ctx context.Context,
+	logger log.Logger,
+	handler queryrangebase.Handler,
+	from, through model.Time,
+	grps []syntax.MatcherRange,
+	maxParallelism int,
+	defaultLookback time.Duration,
+) ([]*stats.Stats, error) {
+	results := make([]*stats.Stats, len(grps))
+	if err := concurrency.ForEachJob(ctx, len(grps), maxParallelism, func(ctx context.Context, i int) error {
 		matchers := syntax.MatchersString(grps[i].Matchers)
 		diff := grps[i].Interval + grps[i].Offset
-		adjustedFrom := r.from.Add(-diff)
+		adjustedFrom := from.Add(-diff)
 		if grps[i].Interval == 0 {
-			adjustedFrom = adjustedFrom.Add(-r.defaultLookback)
+			adjustedFrom = adjustedFrom.Add(-defaultLookback)
 		}
 
-		adjustedThrough := r.through.Add(-grps[i].Offset)
+		adjustedThrough := through.Add(-grps[i].Offset)
 
 		start := time.Now()
-		resp, err := r.handler.Do(r.ctx, &logproto.IndexStatsRequest{
+		resp, err := handler.Do(ctx, &logproto.IndexStatsRequest{
 			From:     adjustedFrom,
 			Through:  adjustedThrough,
 			Matchers: matchers,
@@ -99,8 +100,8 @@ func (r *dynamicShardResolver) Shards(e syntax.Expr) (int, error) {
 			return fmt.Errorf("expected *IndexStatsResponse while querying index, got %T", resp)
 		}
 
-		results = append(results, casted.Response)
-		level.Debug(sp).Log(
+		results[i] = casted.Response
+		level.Debug(logger).Log(
 			"msg", "queried index",
 			"type", "single",
 			"matchers", matchers,
@@ -115,6 +116,27 @@ func (r *dynamicShardResolver) Shards(e syntax.Expr) (int, error) {
 		)
 		return nil
 	}); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (r *dynamicShardResolver) Shards(e syntax.Expr) (int, error) {
+	sp, ctx := spanlogger.NewWithLogger(r.ctx, r.logger, "dynamicShardResolver.Shards")
+	defer sp.Finish()
+	// We try to shard subtrees in the AST independently if possible, although
+	// nested binary expressions can make this difficult. In this case,
+	// we query the index stats for all matcher groups then sum the results.
+	grps := syntax.MatcherGroups(e)
+
+	// If there are zero matchers groups, we'll inject one to query everything
+	if len(grps) == 0 {
+		grps = append(grps, syntax.MatcherRange{})
+	}
+
+	start := time.Now()
+	results, err := getStatsForMatchers(ctx, sp, r.handler, r.from, r.through, grps, r.maxParallelism, r.defaultLookback)
+	if err != nil {
 		return 0, err
 	}
 
@@ -137,6 +159,10 @@ func (r *dynamicShardResolver) Shards(e syntax.Expr) (int, error) {
 		"factor", factor,
 		"bytes_per_shard", strings.Replace(humanize.Bytes(bytesPerShard), " ", "", 1),
 	)
+
+	if r.maxShardedQueryBytes > 0 && bytesPerShard > uint64(r.maxShardedQueryBytes) {
+		return 0, httpgrpc.Errorf(http.StatusBadRequest, limErrQuerierTooManyBytesTmpl, humanize.IBytes(bytesPerShard), humanize.IBytes(uint64(r.maxShardedQueryBytes)))
+	}
 	return factor, nil
 }
 
@@ -1,11 +1,16 @@
 package queryrange
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
+	"github.com/go-kit/log"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql/syntax"
+	"github.com/grafana/loki/pkg/querier/queryrange/queryrangebase"
 	"github.com/grafana/loki/pkg/storage/stores/index/stats"
 )
 
@@ -49,3 +54,38 @@ func TestGuessShardFactor(t *testing.T) {
 		})
 	}
 }
+
+func TestDynamicShardResolverMaxBytesPerShard(t *testing.T) {
+	handler := queryrangebase.HandlerFunc(func(_ context.Context, _ queryrangebase.Request) (queryrangebase.Response, error) {
+		return &IndexStatsResponse{Response: &logproto.IndexStatsResponse{Bytes: maxBytesPerShard * 4}}, nil
+	})
+	expr, err := syntax.ParseExpr(`sum(rate({app="foo"}[1m]))`)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name     string
+		maxBytes int
+		err      bool
+	}{
+		{name: "disabled"},
+		{name: "under limit", maxBytes: maxBytesPerShard},
+		{name: "over limit", maxBytes: maxBytesPerShard - 1, err: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &dynamicShardResolver{
+				ctx:                  context.Background(),
+				handler:              handler,
+				logger:               log.NewNopLogger(),
+				maxParallelism:       1,
+				maxShardedQueryBytes: tc.maxBytes,
+			}
+			factor, err := r.Shards(expr)
+			if tc.err {
+				require.ErrorContains(t, err, "query too large to execute on a single querier")
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, 4, factor)
+		})
+	}
+}
@@ -205,8 +205,8 @@ func (h *splitByInterval) Do(ctx context.Context, r queryrangebase.Request) (que
 				intervals[i], intervals[j] = intervals[j], intervals[i]
 			}
 		}
-	case *LokiSeriesRequest, *LokiLabelNamesRequest:
-		// Set this to 0 since this is not used in Series/Labels Request.
+	case *LokiSeriesRequest, *LokiLabelNamesRequest, *logproto.VolumeRequest:
+		// Set this to 0 since this is not used in Series/Labels/Volume Request.
 		limit = 0
 	default:
 		return nil, httpgrpc.Errorf(http.StatusBadRequest, "unknown request type")
@@ -270,6 +270,11 @@ func splitByTime(req queryrangebase.Request, interval time.Duration) ([]queryran
 				EndTs:   end,
 			})
 		})
+	case *logproto.VolumeRequest:
+		// index queries have end time inclusive, like metadata queries.
+		util.ForInterval(interval, r.From.Time(), r.Through.Time(), true, func(start, end time.Time) {
+			reqs = append(reqs, r.WithStartEnd(start.UnixMilli(), end.UnixMilli()))
+		})
 	default:
 		return nil, nil
 	}
@@ -287,18 +292,33 @@ func maxRangeVectorAndOffsetDuration(q string) (time.Duration, time.Duration, er
 		return 0, 0, nil
 	}
 
+	maxRVDuration, maxOffset := maxRangeVectorAndOffsetDurationFromExpr(expr)
+	return maxRVDuration, maxOffset, nil
+}
+
+func maxRangeVectorAndOffsetDurationFromExpr(expr syntax.Expr) (time.Duration, time.Duration) {
 	var maxRVDuration, maxOffset time.Duration
 	expr.Walk(func(e interface{}) {
-		if r, ok := e.(*syntax.LogRange); ok {
+		switch r := e.(type) {
+		case *syntax.LogRange:
 			if r.Interval > maxRVDuration {
 				maxRVDuration = r.Interval
 			}
 			if r.Offset > maxOffset {
 				maxOffset = r.Offset
 			}
+		case *syntax.SubqueryExpr:
+			// the range vectors within a subquery are evaluated over the whole subquery range.
+			innerRVDuration, innerOffset := maxRangeVectorAndOffsetDurationFromExpr(r.Left)
+			if d := r.Range + innerRVDuration; d > maxRVDuration {
+				maxRVDuration = d
+			}
+			if o := r.Offset + innerOffset; o > maxOffset {
+				maxOffset = o
+			}
 		}
 	})
-	return maxRVDuration, maxOffset, nil
+	return maxRVDuration, maxOffset
 }
 
 // reduceSplitIntervalForRangeVector reduces the split interval for a range query based on the duration of the range vector.
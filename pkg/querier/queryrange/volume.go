@@ -0,0 +1,220 @@
+package queryrange
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/common/model"
+	"github.com/weaveworks/common/httpgrpc"
+
+	"github.com/grafana/dskit/tenant"
+
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/querier/queryrange/queryrangebase"
+	"github.com/grafana/loki/pkg/storage/chunk/cache"
+	"github.com/grafana/loki/pkg/storage/config"
+	index_stats "github.com/grafana/loki/pkg/storage/stores/index/stats"
+	"github.com/grafana/loki/pkg/util/validation"
+)
+
+// NewVolumeTripperware creates a new frontend tripperware responsible for handling volume requests.
+func NewVolumeTripperware(
+	cfg Config,
+	log log.Logger,
+	limits Limits,
+	schema config.SchemaConfig,
+	codec queryrangebase.Codec,
+	c cache.Cache,
+	metrics *Metrics,
+) (queryrangebase.Tripperware, error) {
+	queryRangeMiddleware := []queryrangebase.Middleware{
+		StatsCollectorMiddleware(),
+		NewLimitsMiddleware(limits),
+		newVolumeLimitMiddleware(),
+		queryrangebase.InstrumentMiddleware("split_by_interval", metrics.InstrumentMiddlewareMetrics),
+		// Force a 24 hours split by for volume API, like for the other index-only APIs.
+		SplitByIntervalMiddleware(schema.Configs, WithSplitByLimits(limits, 24*time.Hour), codec, splitByTime, metrics.SplitByMetrics),
+	}
+
+	if cfg.CacheResults {
+		queryRangeMiddleware = append(queryRangeMiddleware,
+			queryrangebase.InstrumentMiddleware("volume_results_cache", metrics.InstrumentMiddlewareMetrics),
+			NewVolumeCache(log, limits, c, cfg.Transformer, metrics.VolumeCacheMetrics),
+		)
+	}
+
+	if cfg.MaxRetries > 0 {
+		queryRangeMiddleware = append(queryRangeMiddleware,
+			queryrangebase.InstrumentMiddleware("retry", metrics.InstrumentMiddlewareMetrics),
+			queryrangebase.NewRetryMiddleware(log, cfg.MaxRetries, metrics.RetryMiddlewareMetrics),
+		)
+	}
+
+	return func(next http.RoundTripper) http.RoundTripper {
+		return NewLimitedRoundTripper(next, codec, limits, schema.Configs, queryRangeMiddleware...)
+	}, nil
+}
+
+// newVolumeLimitMiddleware returns the requested number of volumes, out of the merged volumes of all the streams.
+// The volumes of the streams are only returned when they are requested.
+func newVolumeLimitMiddleware() queryrangebase.Middleware {
+	return queryrangebase.MiddlewareFunc(func(next queryrangebase.Handler) queryrangebase.Handler {
+		return queryrangebase.HandlerFunc(func(ctx context.Context, r queryrangebase.Request) (queryrangebase.Response, error) {
+			req, ok := r.(*logproto.VolumeRequest)
+			if !ok {
+				return nil, httpgrpc.Errorf(http.StatusInternalServerError, "invalid request type %T", r)
+			}
+
+			// The volumes of the streams are always requested from the queriers,
+			// so that a stream is only counted once when merging the split responses.
+			streamsReq := *req
+			streamsReq.Streams = true
+			resp, err := next.Do(ctx, &streamsReq)
+			if err != nil {
+				return nil, err
+			}
+			volumes, ok := resp.(*VolumeResponse)
+			if !ok {
+				return nil, httpgrpc.Errorf(http.StatusInternalServerError, "invalid response type %T", resp)
+			}
+
+			merged := index_stats.MergeVolumes(int(req.Limit), volumes.Response)
+			if !req.Streams {
+				merged.Streams = nil
+			}
+			return &VolumeResponse{
+				Response: &merged,
+				Headers:  volumes.Headers,
+			}, nil
+		})
+	})
+}
+
+// VolumeCacheMetrics is the metrics wrapper used in the volume cache.
+type VolumeCacheMetrics struct {
+	CacheHit  prometheus.Counter
+	CacheMiss prometheus.Counter
+}
+
+// NewVolumeCacheMetrics creates metrics to be used in the volume cache.
+func NewVolumeCacheMetrics(registerer prometheus.Registerer) *VolumeCacheMetrics {
+	return &VolumeCacheMetrics{
+		CacheHit: promauto.With(registerer).NewCounter(prometheus.CounterOpts{
+			Namespace: "loki",
+			Name:      "query_frontend_volume_cache_hit_total",
+		}),
+		CacheMiss: promauto.With(registerer).NewCounter(prometheus.CounterOpts{
+			Namespace: "loki",
+			Name:      "query_frontend_volume_cache_miss_total",
+		}),
+	}
+}
+
+// NewVolumeCache creates a new volume cache middleware.
+// It caches the volumes of the requests split by time which end before the max cache freshness,
+// keyed by their exact time range, so only the aligned intervals of a query are usually reused.
+func NewVolumeCache(logger log.Logger, limits Limits, cache cache.Cache, transformer UserIDTransformer, metrics *VolumeCacheMetrics) queryrangebase.Middleware {
+	if metrics == nil {
+		metrics = NewVolumeCacheMetrics(nil)
+	}
+	return queryrangebase.MiddlewareFunc(func(next queryrangebase.Handler) queryrangebase.Handler {
+		return &volumeCache{
+			next:        next,
+			limits:      limits,
+			cache:       cache,
+			logger:      logger,
+			transformer: transformer,
+			metrics:     metrics,
+		}
+	})
+}
+
+type volumeCache struct {
+	next        queryrangebase.Handler
+	limits      Limits
+	cache       cache.Cache
+	transformer UserIDTransformer
+
+	metrics *VolumeCacheMetrics
+	logger  log.Logger
+}
+
+func (v *volumeCache) Do(ctx context.Context, r queryrangebase.Request) (queryrangebase.Response, error) {
+	tenantIDs, err := tenant.TenantIDs(ctx)
+	if err != nil {
+		return nil, httpgrpc.Errorf(http.StatusBadRequest, err.Error())
+	}
+
+	req, ok := r.(*logproto.VolumeRequest)
+	if !ok {
+		return nil, httpgrpc.Errorf(http.StatusInternalServerError, "invalid request type %T", r)
+	}
+
+	maxCacheFreshness := validation.MaxDurationPerTenant(tenantIDs, v.limits.MaxCacheFreshness)
+	maxCacheTime := int64(model.Now().Add(-maxCacheFreshness))
+	if req.GetEnd() > maxCacheTime {
+		return v.next.Do(ctx, req)
+	}
+
+	transformedTenantIDs := tenantIDs
+	if v.transformer != nil {
+		transformedTenantIDs = make([]string, 0, len(tenantIDs))
+
+		for _, tenantID := range tenantIDs {
+			transformedTenantIDs = append(transformedTenantIDs, v.transformer(ctx, tenantID))
+		}
+	}
+
+	// generate the cache key based on tenant, query, target labels and time range.
+	cacheKey := fmt.Sprintf("volume:%s:%s:%s:%d:%d", tenant.JoinTenantIDs(transformedTenantIDs), req.GetQuery(), strings.Join(req.TargetLabels, ","), req.GetStart(), req.GetEnd())
+
+	_, buff, _, err := v.cache.Fetch(ctx, []string{cache.HashKey(cacheKey)})
+	if err != nil {
+		level.Warn(v.logger).Log("msg", "error fetching cache", "err", err, "cacheKey", cacheKey)
+		return v.next.Do(ctx, req)
+	}
+	// we expect only one key to be found or missing.
+	if len(buff) > 1 {
+		level.Warn(v.logger).Log("msg", "unexpected length of cache return values", "buff", len(buff))
+		return v.next.Do(ctx, req)
+	}
+
+	if len(buff) == 1 {
+		var cached logproto.VolumeResponse
+		err := cached.Unmarshal(buff[0])
+		if err == nil {
+			v.metrics.CacheHit.Inc()
+			return &VolumeResponse{Response: &cached}, nil
+		}
+		level.Warn(v.logger).Log("msg", "error unmarshalling volumes from cache", "err", err)
+	}
+
+	v.metrics.CacheMiss.Inc()
+	resp, err := v.next.Do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	volumes, ok := resp.(*VolumeResponse)
+	if !ok {
+		return nil, httpgrpc.Errorf(http.StatusInternalServerError, "invalid response type %T", resp)
+	}
+
+	data, err := volumes.Response.Marshal()
+	if err != nil {
+		level.Warn(v.logger).Log("msg", "error marshalling volumes", "err", err)
+		return resp, nil
+	}
+	// cache the result
+	err = v.cache.Store(ctx, []string{cache.HashKey(cacheKey)}, [][]byte{data})
+	if err != nil {
+		level.Warn(v.logger).Log("msg", "error storing cache", "err", err)
+	}
+	return resp, nil
+}
@@ -36,6 +36,8 @@ type Stats struct {
 	FetchedSeriesCount uint64 `protobuf:"varint,2,opt,name=fetched_series_count,json=fetchedSeriesCount,proto3" json:"fetched_series_count,omitempty"`
 	// The number of bytes of the chunks fetched for the query
 	FetchedChunkBytes uint64 `protobuf:"varint,3,opt,name=fetched_chunk_bytes,json=fetchedChunkBytes,proto3" json:"fetched_chunk_bytes,omitempty"`
+	// The number of bytes processed to execute the query
+	ProcessedBytes uint64 `protobuf:"varint,4,opt,name=processed_bytes,json=processedBytes,proto3" json:"processed_bytes,omitempty"`
 }
 
 func (m *Stats) Reset()      { *m = Stats{} }
@@ -91,6 +93,13 @@ func (m *Stats) GetFetchedChunkBytes() uint64 {
 	return 0
 }
 
+func (m *Stats) GetProcessedBytes() uint64 {
+	if m != nil {
+		return m.ProcessedBytes
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*Stats)(nil), "stats.Stats")
 }
@@ -98,26 +107,27 @@ func init() {
 func init() { proto.RegisterFile("pkg/querier/stats/stats.proto", fileDescriptor_8ca2404f80bab2e8) }
 
 var fileDescriptor_8ca2404f80bab2e8 = []byte{
-	// 302 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0xd0, 0xb1, 0x4e, 0x3a, 0x31,
-	0x1c, 0xc0, 0xf1, 0xfe, 0xfe, 0x7f, 0x31, 0x78, 0x4e, 0x9e, 0x0c, 0x48, 0xe2, 0x0f, 0xe2, 0x84,
-	0xcb, 0xd5, 0xe8, 0x0b, 0x18, 0xf0, 0x09, 0xc0, 0xc9, 0xe5, 0xd2, 0x3b, 0x4a, 0x69, 0x38, 0xae,
-	0x78, 0xd7, 0xc6, 0xb8, 0xf9, 0x08, 0x8e, 0x3e, 0x82, 0x89, 0x2f, 0xc2, 0xc8, 0xc8, 0xa4, 0x52,
-	0x16, 0x47, 0x1e, 0xc1, 0xb4, 0x07, 0x93, 0x4b, 0xd3, 0x5f, 0x3f, 0xf9, 0x26, 0x6d, 0x83, 0xf3,
-	0xf9, 0x54, 0xd0, 0x47, 0xc3, 0x0b, 0xc9, 0x0b, 0x5a, 0x6a, 0xa6, 0xcb, 0x6a, 0x8d, 0xe6, 0x85,
-	0xd2, 0x2a, 0xac, 0xf9, 0xa1, 0xd5, 0x10, 0x4a, 0x28, 0x7f, 0x42, 0xdd, 0xae, 0xc2, 0x16, 0x0a,
-	0xa5, 0x44, 0xc6, 0xa9, 0x9f, 0x12, 0x33, 0xa6, 0x23, 0x53, 0x30, 0x2d, 0x55, 0x5e, 0xf9, 0xc5,
-	0x07, 0x04, 0xb5, 0xa1, 0xeb, 0xc3, 0xdb, 0xe0, 0xe8, 0x89, 0x65, 0x59, 0xac, 0xe5, 0x8c, 0x37,
-	0xa1, 0x03, 0xdd, 0xe3, 0xeb, 0xb3, 0xa8, 0xaa, 0xa3, 0x7d, 0x1d, 0xdd, 0xed, 0xea, 0x5e, 0x7d,
-	0xf1, 0xd9, 0x26, 0x6f, 0x5f, 0x6d, 0x18, 0xd4, 0x5d, 0x75, 0x2f, 0x67, 0x3c, 0xbc, 0x0a, 0x1a,
-	0x63, 0xae, 0xd3, 0x09, 0x1f, 0xc5, 0xa5, 0xbb, 0x6c, 0x19, 0xa7, 0xca, 0xe4, 0xba, 0xf9, 0xaf,
-	0x03, 0xdd, 0x83, 0x41, 0xb8, 0xb3, 0xa1, 0xa7, 0xbe, 0x93, 0x30, 0x0a, 0x4e, 0xf7, 0x45, 0x3a,
-	0x31, 0xf9, 0x34, 0x4e, 0x9e, 0x35, 0x2f, 0x9b, 0xff, 0x7d, 0x70, 0xb2, 0xa3, 0xbe, 0x93, 0x9e,
-	0x83, 0x5e, 0xbc, 0x5c, 0x23, 0x59, 0xad, 0x91, 0x6c, 0xd7, 0x08, 0x2f, 0x16, 0xe1, 0xdd, 0x22,
-	0x2c, 0x2c, 0xc2, 0xd2, 0x22, 0x7c, 0x5b, 0x84, 0x1f, 0x8b, 0x64, 0x6b, 0x11, 0x5e, 0x37, 0x48,
-	0x96, 0x1b, 0x24, 0xab, 0x0d, 0x92, 0x87, 0x4b, 0x21, 0xf5, 0xc4, 0x24, 0x51, 0xaa, 0x66, 0x54,
-	0x14, 0x6c, 0xcc, 0x72, 0x46, 0x33, 0x35, 0x95, 0xf4, 0xcf, 0xbf, 0x26, 0x87, 0xfe, 0xa5, 0x37,
-	0xbf, 0x01, 0x00, 0x00, 0xff, 0xff, 0xb4, 0xe6, 0x22, 0x2b, 0x73, 0x01, 0x00, 0x00,
+	// 319 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x91, 0x31, 0x4e, 0xc3, 0x30,
+	0x14, 0x86, 0xfd, 0xa0, 0x45, 0x25, 0x48, 0x20, 0x42, 0x87, 0x52, 0x89, 0xd7, 0x8a, 0x85, 0xb2,
+	0xc4, 0x08, 0x2e, 0x80, 0x5a, 0x4e, 0xd0, 0x32, 0xb1, 0x44, 0x49, 0xea, 0xba, 0x51, 0xd3, 0xb8,
+	0xc4, 0x8e, 0x10, 0x1b, 0x47, 0x60, 0xe4, 0x08, 0x1c, 0xa5, 0x63, 0x07, 0x86, 0x4e, 0x40, 0xdd,
+	0x85, 0xb1, 0x47, 0x40, 0x76, 0x52, 0x16, 0x16, 0xcb, 0xef, 0xff, 0xfe, 0x4f, 0xb2, 0xfc, 0x9c,
+	0xb3, 0xd9, 0x84, 0xd3, 0xc7, 0x9c, 0x65, 0x31, 0xcb, 0xa8, 0x54, 0x81, 0x92, 0xc5, 0xe9, 0xcd,
+	0x32, 0xa1, 0x84, 0x5b, 0xb5, 0x43, 0xb3, 0xce, 0x05, 0x17, 0x36, 0xa1, 0xe6, 0x56, 0xc0, 0x26,
+	0x72, 0x21, 0x78, 0xc2, 0xa8, 0x9d, 0xc2, 0x7c, 0x44, 0x87, 0x79, 0x16, 0xa8, 0x58, 0xa4, 0x05,
+	0x3f, 0xff, 0x00, 0xa7, 0x3a, 0x30, 0xbe, 0x7b, 0xeb, 0xec, 0x3f, 0x05, 0x49, 0xe2, 0xab, 0x78,
+	0xca, 0x1a, 0xd0, 0x86, 0xce, 0xc1, 0xf5, 0xa9, 0x57, 0xd8, 0xde, 0xd6, 0xf6, 0xee, 0x4a, 0xbb,
+	0x5b, 0x9b, 0x7f, 0xb6, 0xc8, 0xdb, 0x57, 0x0b, 0xfa, 0x35, 0x63, 0xdd, 0xc7, 0x53, 0xe6, 0x5e,
+	0x39, 0xf5, 0x11, 0x53, 0xd1, 0x98, 0x0d, 0x7d, 0x69, 0x1e, 0x2b, 0xfd, 0x48, 0xe4, 0xa9, 0x6a,
+	0xec, 0xb4, 0xa1, 0x53, 0xe9, 0xbb, 0x25, 0x1b, 0x58, 0xd4, 0x33, 0xc4, 0xf5, 0x9c, 0x93, 0xad,
+	0x11, 0x8d, 0xf3, 0x74, 0xe2, 0x87, 0xcf, 0x8a, 0xc9, 0xc6, 0xae, 0x15, 0x8e, 0x4b, 0xd4, 0x33,
+	0xa4, 0x6b, 0x80, 0x7b, 0xe1, 0x1c, 0xcd, 0x32, 0x11, 0x31, 0x29, 0xd9, 0xb0, 0xec, 0x56, 0x6c,
+	0xf7, 0xf0, 0x2f, 0xb6, 0xc5, 0xae, 0xbf, 0x58, 0x21, 0x59, 0xae, 0x90, 0x6c, 0x56, 0x08, 0x2f,
+	0x1a, 0xe1, 0x5d, 0x23, 0xcc, 0x35, 0xc2, 0x42, 0x23, 0x7c, 0x6b, 0x84, 0x1f, 0x8d, 0x64, 0xa3,
+	0x11, 0x5e, 0xd7, 0x48, 0x16, 0x6b, 0x24, 0xcb, 0x35, 0x92, 0x87, 0x4b, 0x1e, 0xab, 0x71, 0x1e,
+	0x7a, 0x91, 0x98, 0x52, 0x9e, 0x05, 0xa3, 0x20, 0x0d, 0x68, 0x22, 0x26, 0x31, 0xfd, 0xb7, 0x80,
+	0x70, 0xcf, 0x7e, 0xc9, 0xcd, 0xef, 0x00, 0x33, 0x1a, 0x28, 0xb6, 0x9c, 0x01, 0x00, 0x00,
 }
 
 func (this *Stats) Equal(that interface{}) bool {
@@ -148,17 +158,21 @@ func (this *Stats) Equal(that interface{}) bool {
 	if this.FetchedChunkBytes != that1.FetchedChunkBytes {
 		return false
 	}
+	if this.ProcessedBytes != that1.ProcessedBytes {
+		return false
+	}
 	return true
 }
 func (this *Stats) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 7)
+	s := make([]string, 0, 8)
 	s = append(s, "&stats.Stats{")
 	s = append(s, "WallTime: "+fmt.Sprintf("%#v", this.WallTime)+",\n")
 	s = append(s, "FetchedSeriesCount: "+fmt.Sprintf("%#v", this.FetchedSeriesCount)+",\n")
 	s = append(s, "FetchedChunkBytes: "+fmt.Sprintf("%#v", this.FetchedChunkBytes)+",\n")
+	s = append(s, "ProcessedBytes: "+fmt.Sprintf("%#v", this.ProcessedBytes)+",\n")
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -190,6 +204,11 @@ func (m *Stats) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.ProcessedBytes != 0 {
+		i = encodeVarintStats(dAtA, i, uint64(m.ProcessedBytes))
+		i--
+		dAtA[i] = 0x20
+	}
 	if m.FetchedChunkBytes != 0 {
 		i = encodeVarintStats(dAtA, i, uint64(m.FetchedChunkBytes))
 		i--
@@ -236,6 +255,9 @@ func (m *Stats) Size() (n int) {
 	if m.FetchedChunkBytes != 0 {
 		n += 1 + sovStats(uint64(m.FetchedChunkBytes))
 	}
+	if m.ProcessedBytes != 0 {
+		n += 1 + sovStats(uint64(m.ProcessedBytes))
+	}
 	return n
 }
 
@@ -253,6 +275,7 @@ func (this *Stats) String() string {
 		`WallTime:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.WallTime), "Duration", "duration.Duration", 1), `&`, ``, 1) + `,`,
 		`FetchedSeriesCount:` + fmt.Sprintf("%v", this.FetchedSeriesCount) + `,`,
 		`FetchedChunkBytes:` + fmt.Sprintf("%v", this.FetchedChunkBytes) + `,`,
+		`ProcessedBytes:` + fmt.Sprintf("%v", this.ProcessedBytes) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -365,6 +388,25 @@ func (m *Stats) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ProcessedBytes", wireType)
+			}
+			m.ProcessedBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStats
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ProcessedBytes |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipStats(dAtA[iNdEx:])
@@ -0,0 +1,193 @@
+// Package backfill evaluates the recording rules of rule groups over a past
+// time range, so that the series they record aren't empty when they're added.
+package backfill
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/rulefmt"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/weaveworks/common/user"
+
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql"
+	"github.com/grafana/loki/pkg/ruler"
+)
+
+const defaultEvaluationInterval = time.Minute
+
+// Config describes the time range to backfill and how it's evaluated.
+type Config struct {
+	Start  time.Time
+	End    time.Time
+	Tenant string
+
+	// EvaluationInterval overrides the interval of the rule groups when set.
+	EvaluationInterval time.Duration
+	// BlockDuration is the duration of the time ranges evaluated at once. The
+	// results of each range are written at once, and the progress is saved
+	// after each of them.
+	BlockDuration time.Duration
+	// ProgressFile stores the time ranges already backfilled, so that an
+	// interrupted backfill resumes where it stopped. Disabled when empty.
+	ProgressFile string
+}
+
+func (cfg *Config) Validate() error {
+	if cfg.Start.IsZero() || cfg.End.IsZero() {
+		return errors.New("start and end must be set")
+	}
+	if !cfg.Start.Before(cfg.End) {
+		return errors.New("start must be before end")
+	}
+	if cfg.BlockDuration <= 0 {
+		return errors.New("block duration must be positive")
+	}
+	if cfg.EvaluationInterval < 0 {
+		return errors.New("evaluation interval must not be negative")
+	}
+	if cfg.Tenant == "" {
+		return errors.New("tenant must be set")
+	}
+	return nil
+}
+
+// Writer writes the results of the recording rules.
+type Writer interface {
+	// Write writes the series recorded between start and end.
+	Write(ctx context.Context, start, end time.Time, series promql.Matrix) error
+}
+
+// Backfiller evaluates the recording rules of rule groups with a LogQL engine,
+// a block of time at a time.
+type Backfiller struct {
+	cfg    Config
+	engine *logql.Engine
+	writer Writer
+	logger log.Logger
+}
+
+func New(cfg Config, engine *logql.Engine, writer Writer, logger log.Logger) (*Backfiller, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &Backfiller{
+		cfg:    cfg,
+		engine: engine,
+		writer: writer,
+		logger: logger,
+	}, nil
+}
+
+// Run backfills the recording rules of the rule files. Alerting rules are
+// ignored.
+func (b *Backfiller) Run(ctx context.Context, files []string) error {
+	progress, err := loadProgress(b.cfg.ProgressFile)
+	if err != nil {
+		return err
+	}
+
+	ctx = user.InjectOrgID(ctx, b.cfg.Tenant)
+	for _, file := range files {
+		groups, errs := ruler.GroupLoader{}.Load(file)
+		if len(errs) > 0 {
+			return fmt.Errorf("loading rule file %s: %w", file, errs[0])
+		}
+		for _, group := range groups.Groups {
+			if err := b.backfillGroup(ctx, file, group, progress); err != nil {
+				return fmt.Errorf("backfilling group %s of rule file %s: %w", group.Name, file, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (b *Backfiller) backfillGroup(ctx context.Context, file string, group rulefmt.RuleGroup, progress *progress) error {
+	interval := b.cfg.EvaluationInterval
+	if interval == 0 {
+		interval = time.Duration(group.Interval)
+	}
+	if interval == 0 {
+		interval = defaultEvaluationInterval
+	}
+
+	key := file + ":" + group.Name
+	logger := log.With(b.logger, "file", file, "group", group.Name)
+
+	start := b.cfg.Start
+	if done, ok := progress.done(key); ok && done.After(start) {
+		level.Info(logger).Log("msg", "resuming backfill", "from", done)
+		start = done
+	}
+
+	// The blocks are aligned on their duration, so that blocks written by
+	// different runs don't overlap.
+	for blockStart := start.Truncate(b.cfg.BlockDuration); blockStart.Before(b.cfg.End); blockStart = blockStart.Add(b.cfg.BlockDuration) {
+		from, through := blockStart, blockStart.Add(b.cfg.BlockDuration)
+		if from.Before(start) {
+			from = start
+		}
+		if through.After(b.cfg.End) {
+			through = b.cfg.End
+		}
+
+		series, err := b.evalBlock(ctx, group, interval, from, through)
+		if err != nil {
+			return err
+		}
+		if err := b.writer.Write(ctx, from, through, series); err != nil {
+			return fmt.Errorf("writing series between %s and %s: %w", from, through, err)
+		}
+		if err := progress.save(key, through); err != nil {
+			return err
+		}
+		level.Info(logger).Log("msg", "backfilled block", "from", from, "through", through, "series", len(series))
+	}
+	return nil
+}
+
+// evalBlock evaluates the recording rules of the group at each evaluation
+// timestamp in [from, through).
+func (b *Backfiller) evalBlock(ctx context.Context, group rulefmt.RuleGroup, interval time.Duration, from, through time.Time) (promql.Matrix, error) {
+	first := from.Truncate(interval)
+	if first.Before(from) {
+		first = first.Add(interval)
+	}
+	last := through.Add(-1).Truncate(interval)
+	if last.Before(first) {
+		return nil, nil
+	}
+
+	var series promql.Matrix
+	for _, rule := range group.Rules {
+		if rule.Record.Value == "" {
+			continue
+		}
+
+		params := logql.NewLiteralParams(rule.Expr.Value, first, last, interval, 0, logproto.FORWARD, 0, nil)
+		res, err := b.engine.Query(params).Exec(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("evaluating rule %s: %w", rule.Record.Value, err)
+		}
+		matrix, ok := res.Data.(promql.Matrix)
+		if !ok {
+			return nil, fmt.Errorf("rule %s result is not a metric", rule.Record.Value)
+		}
+
+		for _, s := range matrix {
+			lb := labels.NewBuilder(s.Metric)
+			lb.Set(labels.MetricName, rule.Record.Value)
+			for name, value := range rule.Labels {
+				lb.Set(name, value)
+			}
+			series = append(series, promql.Series{Metric: lb.Labels(nil), Points: s.Points})
+		}
+	}
+	return series, nil
+}
@@ -0,0 +1,259 @@
+package backfill
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/grafana/dskit/backoff"
+	config_util "github.com/prometheus/common/config"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/storage/remote"
+	"github.com/prometheus/prometheus/tsdb"
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/user"
+
+	"github.com/grafana/loki/pkg/iter"
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql"
+)
+
+const testRules = `
+groups:
+  - name: test
+    interval: 1m
+    rules:
+      - record: foo:count
+        expr: sum(count_over_time({app="foo"}[1m]))
+        labels:
+          team: a
+      - alert: FooMissing
+        expr: absent_over_time({app="foo"}[5m])
+`
+
+// fakeQuerier returns a sample every 10 seconds for the stream {app="foo"}.
+type fakeQuerier struct {
+	tenants []string
+}
+
+func (q *fakeQuerier) SelectLogs(context.Context, logql.SelectLogParams) (iter.EntryIterator, error) {
+	return iter.NoopIterator, nil
+}
+
+func (q *fakeQuerier) SelectSamples(ctx context.Context, req logql.SelectSampleParams) (iter.SampleIterator, error) {
+	orgID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	q.tenants = append(q.tenants, orgID)
+
+	series := logproto.Series{Labels: `{app="foo"}`}
+	for ts := req.Start.Truncate(10 * time.Second); !ts.After(req.End); ts = ts.Add(10 * time.Second) {
+		series.Samples = append(series.Samples, logproto.Sample{Timestamp: ts.UnixNano(), Value: 1})
+	}
+	return iter.NewSeriesIterator(series), nil
+}
+
+type writeCall struct {
+	start, end time.Time
+	series     promql.Matrix
+}
+
+type recordingWriter struct {
+	calls []writeCall
+}
+
+func (w *recordingWriter) Write(_ context.Context, start, end time.Time, series promql.Matrix) error {
+	w.calls = append(w.calls, writeCall{start: start, end: end, series: series})
+	return nil
+}
+
+func writeRules(t *testing.T) string {
+	file := filepath.Join(t.TempDir(), "rules.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(testRules), 0o600))
+	return file
+}
+
+func newTestBackfiller(t *testing.T, cfg Config, q logql.Querier, w Writer) *Backfiller {
+	engine := logql.NewEngine(logql.EngineOpts{}, q, logql.NoLimits, log.NewNopLogger())
+	b, err := New(cfg, engine, w, log.NewNopLogger())
+	require.NoError(t, err)
+	return b
+}
+
+func TestBackfiller_Run(t *testing.T) {
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	q := &fakeQuerier{}
+	w := &recordingWriter{}
+	b := newTestBackfiller(t, Config{
+		Start:         start,
+		End:           start.Add(10 * time.Minute),
+		Tenant:        "tenant",
+		BlockDuration: 5 * time.Minute,
+	}, q, w)
+
+	require.NoError(t, b.Run(context.Background(), []string{writeRules(t)}))
+
+	require.Len(t, w.calls, 2)
+	for i, call := range w.calls {
+		blockStart := start.Add(time.Duration(i) * 5 * time.Minute)
+		require.Equal(t, blockStart, call.start)
+		require.Equal(t, blockStart.Add(5*time.Minute), call.end)
+
+		// Only the recording rule is evaluated, once a minute.
+		require.Len(t, call.series, 1)
+		require.Equal(t, labels.FromStrings(labels.MetricName, "foo:count", "team", "a"), call.series[0].Metric)
+		require.Len(t, call.series[0].Points, 5)
+		for j, p := range call.series[0].Points {
+			require.Equal(t, blockStart.Add(time.Duration(j)*time.Minute).UnixMilli(), p.T)
+			require.Equal(t, 6.0, p.V)
+		}
+	}
+	require.NotEmpty(t, q.tenants)
+	for _, tenant := range q.tenants {
+		require.Equal(t, "tenant", tenant)
+	}
+}
+
+func TestBackfiller_Resume(t *testing.T) {
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	rules := writeRules(t)
+	progressFile := filepath.Join(t.TempDir(), "progress.json")
+	cfg := Config{
+		Start:         start,
+		End:           start.Add(15 * time.Minute),
+		Tenant:        "tenant",
+		BlockDuration: 5 * time.Minute,
+		ProgressFile:  progressFile,
+	}
+
+	// A previous run stopped after the first block.
+	p, err := loadProgress(progressFile)
+	require.NoError(t, err)
+	require.NoError(t, p.save(rules+":test", start.Add(5*time.Minute)))
+
+	w := &recordingWriter{}
+	require.NoError(t, newTestBackfiller(t, cfg, &fakeQuerier{}, w).Run(context.Background(), []string{rules}))
+	require.Len(t, w.calls, 2)
+	require.Equal(t, start.Add(5*time.Minute), w.calls[0].start)
+	require.Equal(t, start.Add(15*time.Minute), w.calls[1].end)
+
+	p, err = loadProgress(progressFile)
+	require.NoError(t, err)
+	done, ok := p.done(rules + ":test")
+	require.True(t, ok)
+	require.True(t, start.Add(15*time.Minute).Equal(done))
+
+	// Nothing is left to backfill.
+	w = &recordingWriter{}
+	require.NoError(t, newTestBackfiller(t, cfg, &fakeQuerier{}, w).Run(context.Background(), []string{rules}))
+	require.Empty(t, w.calls)
+}
+
+func TestConfig_Validate(t *testing.T) {
+	start := time.Now()
+	valid := Config{Start: start, End: start.Add(time.Hour), Tenant: "fake", BlockDuration: time.Hour}
+	require.NoError(t, valid.Validate())
+
+	for name, modify := range map[string]func(*Config){
+		"missing start":       func(cfg *Config) { cfg.Start = time.Time{} },
+		"end before start":    func(cfg *Config) { cfg.End = cfg.Start.Add(-time.Hour) },
+		"no block duration":   func(cfg *Config) { cfg.BlockDuration = 0 },
+		"negative interval":   func(cfg *Config) { cfg.EvaluationInterval = -time.Minute },
+		"missing tenant name": func(cfg *Config) { cfg.Tenant = "" },
+	} {
+		t.Run(name, func(t *testing.T) {
+			cfg := valid
+			modify(&cfg)
+			require.Error(t, cfg.Validate())
+		})
+	}
+}
+
+func testMatrix(start time.Time) promql.Matrix {
+	var matrix promql.Matrix
+	for _, name := range []string{"a", "b"} {
+		s := promql.Series{Metric: labels.FromStrings(labels.MetricName, "foo:count", "name", name)}
+		for i := 0; i < 3; i++ {
+			s.Points = append(s.Points, promql.Point{T: start.Add(time.Duration(i) * time.Minute).UnixMilli(), V: float64(i)})
+		}
+		matrix = append(matrix, s)
+	}
+	return matrix
+}
+
+func TestTSDBWriter(t *testing.T) {
+	dir := t.TempDir()
+	w := NewTSDBWriter(dir, log.NewNopLogger())
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	require.NoError(t, w.Write(context.Background(), start, start.Add(time.Hour), testMatrix(start)))
+	// Empty blocks aren't written.
+	require.NoError(t, w.Write(context.Background(), start.Add(time.Hour), start.Add(2*time.Hour), nil))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	block, err := tsdb.OpenBlock(log.NewNopLogger(), filepath.Join(dir, entries[0].Name()), nil)
+	require.NoError(t, err)
+	defer block.Close()
+	require.Equal(t, uint64(2), block.Meta().Stats.NumSeries)
+	require.Equal(t, uint64(6), block.Meta().Stats.NumSamples)
+}
+
+func TestRemoteWriteWriter(t *testing.T) {
+	var (
+		requests int
+		samples  int
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		// The first request fails with a recoverable error, and is retried.
+		if requests == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		compressed, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		b, err := snappy.Decode(nil, compressed)
+		require.NoError(t, err)
+		var req prompb.WriteRequest
+		require.NoError(t, proto.Unmarshal(b, &req))
+		for _, ts := range req.Timeseries {
+			require.LessOrEqual(t, len(ts.Samples), 4)
+			samples += len(ts.Samples)
+		}
+	}))
+	defer server.Close()
+
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	client, err := remote.NewWriteClient("test", &remote.ClientConfig{
+		URL:              &config_util.URL{URL: u},
+		Timeout:          model.Duration(time.Second),
+		HTTPClientConfig: config_util.DefaultHTTPClientConfig,
+	})
+	require.NoError(t, err)
+
+	w := NewRemoteWriteWriter(client, 4, backoff.Config{MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond, MaxRetries: 3})
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, w.Write(context.Background(), start, start.Add(time.Hour), testMatrix(start)))
+
+	// 6 samples sent 4 at a time, plus the failed request.
+	require.Equal(t, 3, requests)
+	require.Equal(t, 6, samples)
+}
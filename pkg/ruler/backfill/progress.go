@@ -0,0 +1,75 @@
+package backfill
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// progress records, for each rule group, the time until which it has been
+// backfilled.
+type progress struct {
+	file   string
+	Groups map[string]time.Time `json:"groups"`
+}
+
+func loadProgress(file string) (*progress, error) {
+	p := &progress{file: file, Groups: map[string]time.Time{}}
+	if file == "" {
+		return p, nil
+	}
+
+	b, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return p, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading progress file: %w", err)
+	}
+	if err := json.Unmarshal(b, p); err != nil {
+		return nil, fmt.Errorf("decoding progress file %s: %w", file, err)
+	}
+	if p.Groups == nil {
+		p.Groups = map[string]time.Time{}
+	}
+	return p, nil
+}
+
+func (p *progress) done(key string) (time.Time, bool) {
+	t, ok := p.Groups[key]
+	return t, ok
+}
+
+// save records the progress of the group and persists it. The file is
+// replaced atomically, so that it's never left partially written.
+func (p *progress) save(key string, through time.Time) error {
+	p.Groups[key] = through
+	if p.file == "" {
+		return nil
+	}
+
+	b, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(p.file), filepath.Base(p.file)+".tmp")
+	if err != nil {
+		return fmt.Errorf("saving progress: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return fmt.Errorf("saving progress: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("saving progress: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), p.file); err != nil {
+		return fmt.Errorf("saving progress: %w", err)
+	}
+	return nil
+}
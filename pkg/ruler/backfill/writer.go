@@ -0,0 +1,143 @@
+package backfill
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/grafana/dskit/backoff"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/storage/remote"
+	"github.com/prometheus/prometheus/tsdb"
+)
+
+// TSDBWriter writes the series of each block as a Prometheus TSDB block in a
+// directory, which can be moved into the data directory of Prometheus.
+type TSDBWriter struct {
+	dir    string
+	logger log.Logger
+}
+
+func NewTSDBWriter(dir string, logger log.Logger) *TSDBWriter {
+	return &TSDBWriter{dir: dir, logger: logger}
+}
+
+func (w *TSDBWriter) Write(ctx context.Context, start, end time.Time, series promql.Matrix) (err error) {
+	if len(series) == 0 {
+		return nil
+	}
+
+	bw, err := tsdb.NewBlockWriter(w.logger, w.dir, timestampMs(end)-timestampMs(start))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := bw.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	app := bw.Appender(ctx)
+	for _, s := range series {
+		for _, p := range s.Points {
+			if _, err := app.Append(0, s.Metric, p.T, p.V); err != nil {
+				_ = app.Rollback()
+				return err
+			}
+		}
+	}
+	if err := app.Commit(); err != nil {
+		return err
+	}
+
+	id, err := bw.Flush(ctx)
+	if err != nil {
+		return err
+	}
+	level.Info(w.logger).Log("msg", "wrote TSDB block", "block", id.String())
+	return nil
+}
+
+// RemoteWriteWriter sends the series to a Prometheus remote-write endpoint.
+type RemoteWriteWriter struct {
+	client            remote.WriteClient
+	maxSamplesPerSend int
+	backoff           backoff.Config
+}
+
+func NewRemoteWriteWriter(client remote.WriteClient, maxSamplesPerSend int, backoffConfig backoff.Config) *RemoteWriteWriter {
+	return &RemoteWriteWriter{
+		client:            client,
+		maxSamplesPerSend: maxSamplesPerSend,
+		backoff:           backoffConfig,
+	}
+}
+
+func (w *RemoteWriteWriter) Write(ctx context.Context, _, _ time.Time, series promql.Matrix) error {
+	var (
+		req     prompb.WriteRequest
+		samples int
+	)
+	for _, s := range series {
+		ts := prompb.TimeSeries{Labels: make([]prompb.Label, 0, len(s.Metric))}
+		for _, l := range s.Metric {
+			ts.Labels = append(ts.Labels, prompb.Label{Name: l.Name, Value: l.Value})
+		}
+
+		for _, p := range s.Points {
+			ts.Samples = append(ts.Samples, prompb.Sample{Timestamp: p.T, Value: p.V})
+			samples++
+
+			if samples >= w.maxSamplesPerSend {
+				req.Timeseries = append(req.Timeseries, ts)
+				if err := w.send(ctx, &req); err != nil {
+					return err
+				}
+				req.Timeseries, samples = req.Timeseries[:0], 0
+				ts.Samples = nil
+			}
+		}
+		if len(ts.Samples) > 0 {
+			req.Timeseries = append(req.Timeseries, ts)
+		}
+	}
+
+	if len(req.Timeseries) == 0 {
+		return nil
+	}
+	return w.send(ctx, &req)
+}
+
+// send sends the request, and retries it when it fails with a recoverable error.
+func (w *RemoteWriteWriter) send(ctx context.Context, req *prompb.WriteRequest) error {
+	b, err := proto.Marshal(req)
+	if err != nil {
+		return err
+	}
+	buf := snappy.Encode(nil, b)
+
+	retries := backoff.New(ctx, w.backoff)
+	for retries.Ongoing() {
+		err = w.client.Store(ctx, buf)
+		if err == nil {
+			return nil
+		}
+		if !errors.As(err, &remote.RecoverableError{}) {
+			return err
+		}
+		retries.Wait()
+	}
+	return fmt.Errorf("sending samples to %s: %w", w.client.Endpoint(), err)
+}
+
+// timestampMs returns the timestamp of t in milliseconds.
+func timestampMs(t time.Time) int64 {
+	return int64(model.TimeFromUnixNano(t.UnixNano()))
+}
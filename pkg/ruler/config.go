@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/grafana/dskit/grpcclient"
 	"github.com/pkg/errors"
 	"github.com/prometheus/prometheus/config"
 	"gopkg.in/yaml.v2"
@@ -22,6 +23,8 @@ type Config struct {
 
 	WALCleaner  cleaner.Config    `yaml:"wal_cleaner,omitempty"`
 	RemoteWrite RemoteWriteConfig `yaml:"remote_write,omitempty" doc:"description=Remote-write configuration to send rule samples to a Prometheus remote-write endpoint."`
+
+	Evaluation EvaluationConfig `yaml:"evaluation,omitempty" doc:"description=Configuration for rule evaluation."`
 }
 
 func (c *Config) RegisterFlags(f *flag.FlagSet) {
@@ -29,6 +32,7 @@ func (c *Config) RegisterFlags(f *flag.FlagSet) {
 	c.RemoteWrite.RegisterFlags(f)
 	c.WAL.RegisterFlags(f)
 	c.WALCleaner.RegisterFlags(f)
+	c.Evaluation.RegisterFlags(f)
 
 	// TODO(owen-d, 3.0.0): remove deprecated experimental prefix in Cortex if they'll accept it.
 	f.BoolVar(&c.Config.EnableAPI, "ruler.enable-api", true, "Enable the ruler API.")
@@ -48,6 +52,10 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("invalid ruler wal cleaner config: %w", err)
 	}
 
+	if err := c.Evaluation.Validate(); err != nil {
+		return fmt.Errorf("invalid ruler evaluation config: %w", err)
+	}
+
 	return nil
 }
 
@@ -115,3 +123,46 @@ func (c *RemoteWriteConfig) RegisterFlags(f *flag.FlagSet) {
 		c.Clients = make(map[string]config.RemoteWriteConfig)
 	}
 }
+
+const (
+	EvalModeLocal  = "local"
+	EvalModeRemote = "remote"
+)
+
+type EvaluationConfig struct {
+	Mode string `yaml:"mode,omitempty"`
+
+	QueryFrontend QueryFrontendConfig `yaml:"query_frontend,omitempty"`
+}
+
+func (c *EvaluationConfig) RegisterFlags(f *flag.FlagSet) {
+	f.StringVar(&c.Mode, "ruler.evaluation.mode", EvalModeLocal, "The evaluation mode for the ruler. Can be either 'local' or 'remote'. If set to 'local', the ruler will evaluate rules locally. If set to 'remote', the ruler will evaluate rules remotely. If unset, the ruler will evaluate rules locally.")
+	c.QueryFrontend.RegisterFlags(f)
+}
+
+func (c *EvaluationConfig) Validate() error {
+	switch c.Mode {
+	case "", EvalModeLocal:
+		return nil
+	case EvalModeRemote:
+		if c.QueryFrontend.Address == "" {
+			return errors.New("remote evaluation enabled but no query-frontend address is configured")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown evaluation mode %q, must be one of %q or %q", c.Mode, EvalModeLocal, EvalModeRemote)
+	}
+}
+
+type QueryFrontendConfig struct {
+	// The address of the remote querier to connect to.
+	// See https://github.com/grpc/grpc/blob/master/doc/naming.md.
+	Address string `yaml:"address"`
+
+	GRPCClientConfig grpcclient.Config `yaml:"grpc_client_config" doc:"description=Configures the gRPC connection to the query-frontend."`
+}
+
+func (c *QueryFrontendConfig) RegisterFlags(f *flag.FlagSet) {
+	f.StringVar(&c.Address, "ruler.evaluation.query-frontend.address", "", "GRPC listen address of the query-frontend(s). Must be a DNS address (prefixed with dns:///) to enable client side load balancing.")
+	c.GRPCClientConfig.RegisterFlagsWithPrefix("ruler.evaluation.query-frontend", f)
+}
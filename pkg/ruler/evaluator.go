@@ -0,0 +1,14 @@
+package ruler
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/loki/pkg/logqlmodel"
+)
+
+// Evaluator is the interface that must be satisfied in order to accept rule evaluations from the Ruler.
+type Evaluator interface {
+	// Eval evaluates the given rule and returns the result.
+	Eval(ctx context.Context, qs string, now time.Time) (*logqlmodel.Result, error)
+}
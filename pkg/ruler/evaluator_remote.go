@@ -0,0 +1,244 @@
+package ruler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/backoff"
+	otgrpc "github.com/opentracing-contrib/go-grpc"
+	"github.com/opentracing/opentracing-go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/weaveworks/common/httpgrpc"
+	"github.com/weaveworks/common/middleware"
+	"github.com/weaveworks/common/user"
+	"google.golang.org/grpc"
+
+	"github.com/grafana/loki/pkg/loghttp"
+	"github.com/grafana/loki/pkg/logqlmodel"
+	"github.com/grafana/loki/pkg/util/build"
+)
+
+const (
+	instantQueryPath = "/loki/api/v1/query"
+
+	// The gRPC service config enables the client side load balancing across the
+	// addresses the query-frontend DNS name resolves to.
+	serviceConfig = `{"loadBalancingPolicy": "round_robin"}`
+
+	// Failure reasons of the remote evaluations.
+	failureReasonRequest  = "request_error"
+	failureReasonServer   = "server_error"
+	failureReasonClient   = "client_error"
+	failureReasonResponse = "invalid_response"
+)
+
+var (
+	userAgent = fmt.Sprintf("loki-ruler/%s", build.Version)
+
+	remoteEvalBackoff = backoff.Config{
+		MinBackoff: 100 * time.Millisecond,
+		MaxBackoff: 2 * time.Second,
+	}
+)
+
+type remoteEvaluationMetrics struct {
+	duration *prometheus.HistogramVec
+	success  *prometheus.CounterVec
+	failures *prometheus.CounterVec
+	retries  *prometheus.CounterVec
+}
+
+func newRemoteEvaluationMetrics(reg prometheus.Registerer) *remoteEvaluationMetrics {
+	return &remoteEvaluationMetrics{
+		duration: promauto.With(reg).NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "loki",
+			Name:      "ruler_remote_eval_request_duration_seconds",
+			Help:      "Duration of the rule evaluation requests sent to the query-frontend.",
+			Buckets:   prometheus.ExponentialBuckets(0.05, 2, 10),
+		}, []string{"user"}),
+		success: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: "loki",
+			Name:      "ruler_remote_eval_success_total",
+			Help:      "Total number of successful rule evaluations by the query-frontend.",
+		}, []string{"user"}),
+		failures: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: "loki",
+			Name:      "ruler_remote_eval_failure_total",
+			Help:      "Total number of failed rule evaluations by the query-frontend, after all retries.",
+		}, []string{"reason", "user"}),
+		retries: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: "loki",
+			Name:      "ruler_remote_eval_retries_total",
+			Help:      "Total number of retried rule evaluation requests sent to the query-frontend.",
+		}, []string{"user"}),
+	}
+}
+
+// RemoteEvaluator evaluates rules by sending them as instant queries to the
+// query-frontend, so that they're split, sharded and cached like any other
+// query instead of being evaluated in the ruler.
+type RemoteEvaluator struct {
+	client    httpgrpc.HTTPClient
+	overrides RulesLimits
+	logger    log.Logger
+	metrics   *remoteEvaluationMetrics
+}
+
+func NewRemoteEvaluator(cfg QueryFrontendConfig, overrides RulesLimits, logger log.Logger, reg prometheus.Registerer) (*RemoteEvaluator, error) {
+	client, err := dialQueryFrontend(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("dialing query-frontend %s: %w", cfg.Address, err)
+	}
+	return newRemoteEvaluator(client, overrides, logger, reg), nil
+}
+
+func newRemoteEvaluator(client httpgrpc.HTTPClient, overrides RulesLimits, logger log.Logger, reg prometheus.Registerer) *RemoteEvaluator {
+	return &RemoteEvaluator{
+		client:    client,
+		overrides: overrides,
+		logger:    log.With(logger, "component", "remote-evaluator"),
+		metrics:   newRemoteEvaluationMetrics(reg),
+	}
+}
+
+func dialQueryFrontend(cfg QueryFrontendConfig) (httpgrpc.HTTPClient, error) {
+	opts, err := cfg.GRPCClientConfig.DialOption([]grpc.UnaryClientInterceptor{
+		otgrpc.OpenTracingClientInterceptor(opentracing.GlobalTracer()),
+		middleware.ClientUserHeaderInterceptor,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, grpc.WithDefaultServiceConfig(serviceConfig))
+
+	conn, err := grpc.Dial(cfg.Address, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return httpgrpc.NewHTTPClient(conn), nil
+}
+
+// Eval sends the rule as an instant query to the query-frontend, and retries
+// the requests failing with a server error until the max retries of the
+// tenant or its timeout are reached.
+func (r *RemoteEvaluator) Eval(ctx context.Context, qs string, now time.Time) (*logqlmodel.Result, error) {
+	orgID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if timeout := r.overrides.RulerRemoteEvaluationTimeout(orgID); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	args := url.Values{}
+	args.Set("query", qs)
+	args.Set("time", now.Format(time.RFC3339Nano))
+	req := &httpgrpc.HTTPRequest{
+		Method: http.MethodPost,
+		Url:    instantQueryPath,
+		Body:   []byte(args.Encode()),
+		Headers: []*httpgrpc.Header{
+			{Key: textproto.CanonicalMIMEHeaderKey("Content-Type"), Values: []string{"application/x-www-form-urlencoded"}},
+			{Key: textproto.CanonicalMIMEHeaderKey("User-Agent"), Values: []string{userAgent}},
+			{Key: textproto.CanonicalMIMEHeaderKey(user.OrgIDHeaderName), Values: []string{orgID}},
+		},
+	}
+
+	maxRetries := r.overrides.RulerRemoteEvaluationMaxRetries(orgID)
+	retries := backoff.New(ctx, remoteEvalBackoff)
+	for {
+		res, reason, err := r.query(ctx, orgID, req)
+		if err == nil {
+			r.metrics.success.WithLabelValues(orgID).Inc()
+			return res, nil
+		}
+
+		if reason == failureReasonClient || reason == failureReasonResponse || retries.NumRetries() >= maxRetries {
+			r.metrics.failures.WithLabelValues(reason, orgID).Inc()
+			return nil, err
+		}
+
+		level.Warn(r.logger).Log("msg", "rule evaluation failed, will retry", "user", orgID, "query", qs, "err", err)
+		r.metrics.retries.WithLabelValues(orgID).Inc()
+		retries.Wait()
+		if !retries.Ongoing() {
+			r.metrics.failures.WithLabelValues(reason, orgID).Inc()
+			return nil, err
+		}
+	}
+}
+
+// query sends a single request to the query-frontend. When it fails, it also
+// returns the reason of the failure.
+func (r *RemoteEvaluator) query(ctx context.Context, orgID string, req *httpgrpc.HTTPRequest) (*logqlmodel.Result, string, error) {
+	start := time.Now()
+	resp, err := r.client.Handle(ctx, req)
+	r.metrics.duration.WithLabelValues(orgID).Observe(time.Since(start).Seconds())
+
+	if err != nil {
+		// Errors of the HTTP handler are sent as gRPC errors.
+		var ok bool
+		if resp, ok = httpgrpc.HTTPResponseFromError(err); !ok {
+			return nil, failureReasonRequest, fmt.Errorf("rule evaluation request failed: %w", err)
+		}
+	}
+
+	switch {
+	case resp.Code/100 == 5 || resp.Code == http.StatusTooManyRequests:
+		return nil, failureReasonServer, fmt.Errorf("rule evaluation failed with status code %d: %s", resp.Code, resp.Body)
+	case resp.Code/100 != 2:
+		return nil, failureReasonClient, fmt.Errorf("rule evaluation failed with status code %d: %s", resp.Code, resp.Body)
+	}
+
+	res, err := decodeQueryResponse(resp.Body)
+	if err != nil {
+		return nil, failureReasonResponse, fmt.Errorf("decoding rule evaluation response: %w", err)
+	}
+	return res, "", nil
+}
+
+// decodeQueryResponse converts the JSON response of an instant query into
+// the result of its evaluation.
+func decodeQueryResponse(body []byte) (*logqlmodel.Result, error) {
+	var resp loghttp.QueryResponse
+	if err := resp.UnmarshalJSON(body); err != nil {
+		return nil, err
+	}
+	if resp.Status != loghttp.QueryStatusSuccess {
+		return nil, fmt.Errorf("unexpected response status %q", resp.Status)
+	}
+
+	switch v := resp.Data.Result.(type) {
+	case loghttp.Vector:
+		vec := make(promql.Vector, 0, len(v))
+		for _, s := range v {
+			lbls := make(labels.Labels, 0, len(s.Metric))
+			for name, value := range s.Metric {
+				lbls = append(lbls, labels.Label{Name: string(name), Value: string(value)})
+			}
+			sort.Sort(lbls)
+			vec = append(vec, promql.Sample{
+				Point:  promql.Point{T: int64(s.Timestamp), V: float64(s.Value)},
+				Metric: lbls,
+			})
+		}
+		return &logqlmodel.Result{Data: vec}, nil
+	case loghttp.Scalar:
+		return &logqlmodel.Result{Data: promql.Scalar{T: int64(v.Timestamp), V: float64(v.Value)}}, nil
+	default:
+		return nil, fmt.Errorf("unsupported result type %q", resp.Data.ResultType)
+	}
+}
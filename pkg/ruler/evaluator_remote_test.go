@@ -0,0 +1,178 @@
+package ruler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/httpgrpc"
+	"github.com/weaveworks/common/user"
+	"google.golang.org/grpc"
+
+	"github.com/grafana/loki/pkg/util/log"
+	"github.com/grafana/loki/pkg/validation"
+)
+
+type mockHTTPClient func(ctx context.Context, in *httpgrpc.HTTPRequest) (*httpgrpc.HTTPResponse, error)
+
+func (m mockHTTPClient) Handle(ctx context.Context, in *httpgrpc.HTTPRequest, _ ...grpc.CallOption) (*httpgrpc.HTTPResponse, error) {
+	return m(ctx, in)
+}
+
+func newTestRemoteEvaluator(t *testing.T, client mockHTTPClient, maxRetries int) *RemoteEvaluator {
+	limits := validation.Limits{}
+	limits.RulerRemoteEvaluationMaxRetries = maxRetries
+	limits.RulerRemoteEvaluationTimeout = model.Duration(5 * time.Second)
+	overrides, err := validation.NewOverrides(limits, nil)
+	require.NoError(t, err)
+
+	return newRemoteEvaluator(client, overrides, log.Logger, prometheus.NewRegistry())
+}
+
+func TestRemoteEvaluator_Vector(t *testing.T) {
+	now := time.Unix(1000, 0)
+	ev := newTestRemoteEvaluator(t, func(ctx context.Context, req *httpgrpc.HTTPRequest) (*httpgrpc.HTTPResponse, error) {
+		require.Equal(t, http.MethodPost, req.Method)
+		require.Equal(t, "/loki/api/v1/query", req.Url)
+
+		args, err := url.ParseQuery(string(req.Body))
+		require.NoError(t, err)
+		require.Equal(t, `sum(rate({app="foo"}[1m]))`, args.Get("query"))
+		require.Equal(t, now.Format(time.RFC3339Nano), args.Get("time"))
+
+		var orgID string
+		for _, h := range req.Headers {
+			if h.Key == textproto.CanonicalMIMEHeaderKey(user.OrgIDHeaderName) {
+				orgID = h.Values[0]
+			}
+		}
+		require.Equal(t, "tenant", orgID)
+
+		return &httpgrpc.HTTPResponse{
+			Code: http.StatusOK,
+			Body: []byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{"b":"2","a":"1"},"value":[1000,"3.5"]}]}}`),
+		}, nil
+	}, 3)
+
+	res, err := ev.Eval(user.InjectOrgID(context.Background(), "tenant"), `sum(rate({app="foo"}[1m]))`, now)
+	require.NoError(t, err)
+	require.Equal(t, promql.Vector{
+		{Point: promql.Point{T: 1000000, V: 3.5}, Metric: labels.FromStrings("a", "1", "b", "2")},
+	}, res.Data)
+	require.Equal(t, 1.0, testutil.ToFloat64(ev.metrics.success.WithLabelValues("tenant")))
+	require.Equal(t, 1, testutil.CollectAndCount(ev.metrics.duration))
+}
+
+func TestRemoteEvaluator_Scalar(t *testing.T) {
+	ev := newTestRemoteEvaluator(t, func(ctx context.Context, req *httpgrpc.HTTPRequest) (*httpgrpc.HTTPResponse, error) {
+		return &httpgrpc.HTTPResponse{
+			Code: http.StatusOK,
+			Body: []byte(`{"status":"success","data":{"resultType":"scalar","result":[1000,"2"]}}`),
+		}, nil
+	}, 3)
+
+	res, err := ev.Eval(user.InjectOrgID(context.Background(), "tenant"), `vector(2)`, time.Unix(1000, 0))
+	require.NoError(t, err)
+	require.Equal(t, promql.Scalar{T: 1000000, V: 2}, res.Data)
+}
+
+func TestRemoteEvaluator_Retries(t *testing.T) {
+	for _, tc := range []struct {
+		name             string
+		resp             *httpgrpc.HTTPResponse
+		err              error
+		expectedRequests int
+		expectedReason   string
+	}{
+		{
+			name:             "server errors are retried",
+			err:              httpgrpc.Errorf(http.StatusInternalServerError, "boom"),
+			expectedRequests: 3,
+			expectedReason:   failureReasonServer,
+		},
+		{
+			name:             "rate limited requests are retried",
+			resp:             &httpgrpc.HTTPResponse{Code: http.StatusTooManyRequests},
+			expectedRequests: 3,
+			expectedReason:   failureReasonServer,
+		},
+		{
+			name:             "transport errors are retried",
+			err:              errors.New("connection refused"),
+			expectedRequests: 3,
+			expectedReason:   failureReasonRequest,
+		},
+		{
+			name:             "client errors are not retried",
+			resp:             &httpgrpc.HTTPResponse{Code: http.StatusBadRequest, Body: []byte("parse error")},
+			expectedRequests: 1,
+			expectedReason:   failureReasonClient,
+		},
+		{
+			name:             "invalid responses are not retried",
+			resp:             &httpgrpc.HTTPResponse{Code: http.StatusOK, Body: []byte(`{"status":"success","data":{"resultType":"streams","result":[]}}`)},
+			expectedRequests: 1,
+			expectedReason:   failureReasonResponse,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			requests := 0
+			ev := newTestRemoteEvaluator(t, func(ctx context.Context, req *httpgrpc.HTTPRequest) (*httpgrpc.HTTPResponse, error) {
+				requests++
+				return tc.resp, tc.err
+			}, 2)
+
+			_, err := ev.Eval(user.InjectOrgID(context.Background(), "tenant"), `sum(rate({app="foo"}[1m]))`, time.Now())
+			require.Error(t, err)
+			require.Equal(t, tc.expectedRequests, requests)
+			require.Equal(t, 1.0, testutil.ToFloat64(ev.metrics.failures.WithLabelValues(tc.expectedReason, "tenant")))
+			require.Equal(t, float64(tc.expectedRequests-1), testutil.ToFloat64(ev.metrics.retries.WithLabelValues("tenant")))
+		})
+	}
+}
+
+func TestRemoteEvaluator_RetryThenSuccess(t *testing.T) {
+	requests := 0
+	ev := newTestRemoteEvaluator(t, func(ctx context.Context, req *httpgrpc.HTTPRequest) (*httpgrpc.HTTPResponse, error) {
+		requests++
+		if requests == 1 {
+			return nil, httpgrpc.Errorf(http.StatusServiceUnavailable, "unavailable")
+		}
+		return &httpgrpc.HTTPResponse{
+			Code: http.StatusOK,
+			Body: []byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`),
+		}, nil
+	}, 2)
+
+	res, err := ev.Eval(user.InjectOrgID(context.Background(), "tenant"), `sum(rate({app="foo"}[1m]))`, time.Now())
+	require.NoError(t, err)
+	require.Equal(t, promql.Vector{}, res.Data)
+	require.Equal(t, 2, requests)
+}
+
+func TestRemoteEvaluator_MissingOrgID(t *testing.T) {
+	ev := newTestRemoteEvaluator(t, func(ctx context.Context, req *httpgrpc.HTTPRequest) (*httpgrpc.HTTPResponse, error) {
+		t.Fatal("unexpected request")
+		return nil, nil
+	}, 2)
+
+	_, err := ev.Eval(context.Background(), `sum(rate({app="foo"}[1m]))`, time.Now())
+	require.Error(t, err)
+}
+
+func TestEvaluationConfig_Validate(t *testing.T) {
+	require.NoError(t, (&EvaluationConfig{Mode: EvalModeLocal}).Validate())
+	require.Error(t, (&EvaluationConfig{Mode: EvalModeRemote}).Validate())
+	require.NoError(t, (&EvaluationConfig{Mode: EvalModeRemote, QueryFrontend: QueryFrontendConfig{Address: "dns:///query-frontend:9095"}}).Validate())
+	require.Error(t, (&EvaluationConfig{Mode: "other"}).Validate())
+}
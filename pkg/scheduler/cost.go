@@ -0,0 +1,53 @@
+package scheduler
+
+import (
+	"bytes"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/weaveworks/common/httpgrpc"
+
+	"github.com/grafana/loki/pkg/loghttp"
+	"github.com/grafana/loki/pkg/querier/astmapper"
+)
+
+// minRequestCost is the cost of the requests without a time range, such as instant queries,
+// and the lowest cost of a request.
+const minRequestCost = 1
+
+// requestCost estimates the querier resources needed by a request as the minutes of logs it queries: the length
+// of its time range, divided by the number of shards of the query for the subqueries of a sharded query.
+// Requests which can't be parsed get the lowest cost, as it is only used to share the queriers between tenants.
+func requestCost(r *httpgrpc.HTTPRequest) float64 {
+	req, err := http.NewRequest(r.Method, r.Url, bytes.NewReader(r.Body))
+	if err != nil {
+		return minRequestCost
+	}
+	for _, h := range r.Headers {
+		req.Header[h.Key] = h.Values
+	}
+	if err := req.ParseForm(); err != nil {
+		return minRequestCost
+	}
+
+	if req.Form.Get("start") == "" || req.Form.Get("end") == "" {
+		return minRequestCost
+	}
+	start, err := loghttp.ParseTimestamp(req.Form.Get("start"), time.Time{})
+	if err != nil {
+		return minRequestCost
+	}
+	end, err := loghttp.ParseTimestamp(req.Form.Get("end"), time.Time{})
+	if err != nil {
+		return minRequestCost
+	}
+
+	cost := end.Sub(start).Minutes()
+	if shards := req.Form["shards"]; len(shards) > 0 {
+		if shard, err := astmapper.ParseShard(shards[0]); err == nil && shard.Of > 0 {
+			cost /= float64(shard.Of)
+		}
+	}
+	return math.Max(cost, minRequestCost)
+}
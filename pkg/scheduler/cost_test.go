@@ -0,0 +1,56 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/httpgrpc"
+)
+
+func TestRequestCost(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		request  *httpgrpc.HTTPRequest
+		expected float64
+	}{
+		{
+			name:     "range query",
+			request:  &httpgrpc.HTTPRequest{Method: "GET", Url: "/loki/api/v1/query_range?query=%7Bapp%3D%22foo%22%7D&start=1660000000000000000&end=1660003600000000000"},
+			expected: 60,
+		},
+		{
+			name:     "sharded subquery",
+			request:  &httpgrpc.HTTPRequest{Method: "GET", Url: "/loki/api/v1/query_range?query=%7Bapp%3D%22foo%22%7D&start=1660000000&end=1660003600&shards=1_of_16"},
+			expected: 3.75,
+		},
+		{
+			name: "form encoded body",
+			request: &httpgrpc.HTTPRequest{
+				Method:  "POST",
+				Url:     "/loki/api/v1/query_range",
+				Headers: []*httpgrpc.Header{{Key: "Content-Type", Values: []string{"application/x-www-form-urlencoded"}}},
+				Body:    []byte("query=%7Bapp%3D%22foo%22%7D&start=2022-08-08T00:00:00Z&end=2022-08-08T00:30:00Z"),
+			},
+			expected: 30,
+		},
+		{
+			name:     "short subquery",
+			request:  &httpgrpc.HTTPRequest{Method: "GET", Url: "/loki/api/v1/query_range?query=%7Bapp%3D%22foo%22%7D&start=1660000000&end=1660000010"},
+			expected: minRequestCost,
+		},
+		{
+			name:     "instant query",
+			request:  &httpgrpc.HTTPRequest{Method: "GET", Url: "/loki/api/v1/query?query=count_over_time(%7Bapp%3D%22foo%22%7D%5B1h%5D)"},
+			expected: minRequestCost,
+		},
+		{
+			name:     "invalid time range",
+			request:  &httpgrpc.HTTPRequest{Method: "GET", Url: "/loki/api/v1/query_range?start=foo&end=bar"},
+			expected: minRequestCost,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, requestCost(tc.request))
+		})
+	}
+}
@@ -25,12 +25,17 @@ var (
 // of RequestQueue.GetNextRequestForQuerier method.
 type UserIndex struct {
 	last int
+
+	// User of the last returned request, and whether it was discarded by the querier.
+	user      string
+	discarded bool
 }
 
 // Modify index to start iteration on the same user, for which last queue was returned.
+// The user isn't charged for the last returned request, as it is discarded.
 func (ui UserIndex) ReuseLastUser() UserIndex {
 	if ui.last >= 0 {
-		return UserIndex{last: ui.last - 1}
+		return UserIndex{last: ui.last - 1, user: ui.user, discarded: true}
 	}
 	return ui
 }
@@ -43,6 +48,39 @@ func FirstUser() UserIndex {
 // Request stored into the queue.
 type Request interface{}
 
+// CostEstimator is implemented by the requests estimating the querier resources they need, in an arbitrary
+// unit shared by all the requests of the queue. Users are charged the cost of their dequeued requests, so that
+// users sending expensive requests don't get a larger share of the queriers than users sending cheap ones.
+// Requests which don't implement it have a cost of 1.
+type CostEstimator interface {
+	Cost() float64
+}
+
+func requestCost(req Request) float64 {
+	if r, ok := req.(CostEstimator); ok && r.Cost() > 0 {
+		return r.Cost()
+	}
+	return 1
+}
+
+// TenantShare defines the share of the queriers given to a tenant.
+type TenantShare struct {
+	// Priority class of the tenant. Requests of the tenants with the highest priority class are always dequeued
+	// first, the tenants of lower priority classes only get the queriers left idle.
+	Priority int
+
+	// Weight of the tenant relative to the other tenants of the same priority class: a tenant with a weight of 2
+	// gets twice the share of a tenant with a weight of 1. Weights <= 0 are treated as 1.
+	Weight float64
+}
+
+func (s TenantShare) weight() float64 {
+	if s.Weight <= 0 {
+		return 1
+	}
+	return s.Weight
+}
+
 // RequestQueue holds incoming requests in per-user queues. It also assigns each user specified number of queriers,
 // and when querier asks for next request to handle (using GetNextRequestForQuerier), it returns requests
 // in a fair fashion.
@@ -75,11 +113,11 @@ func NewRequestQueue(maxOutstandingPerTenant int, forgetDelay time.Duration, que
 }
 
 // EnqueueRequest puts the request into the queue. MaxQueries is user-specific value that specifies how many queriers can
-// this user use (zero or negative = all queriers). It is passed to each EnqueueRequest, because it can change
-// between calls.
+// this user use (zero or negative = all queriers), and share its priority and weight. They are passed to each EnqueueRequest,
+// because they can change between calls.
 //
 // If request is successfully enqueued, successFn is called with the lock held, before any querier can receive the request.
-func (q *RequestQueue) EnqueueRequest(userID string, req Request, maxQueriers int, successFn func()) error {
+func (q *RequestQueue) EnqueueRequest(userID string, req Request, maxQueriers int, share TenantShare, successFn func()) error {
 	q.mtx.Lock()
 	defer q.mtx.Unlock()
 
@@ -87,7 +125,7 @@ func (q *RequestQueue) EnqueueRequest(userID string, req Request, maxQueriers in
 		return ErrStopped
 	}
 
-	queue := q.queues.getOrAddQueue(userID, maxQueriers)
+	queue := q.queues.getOrAddQueue(userID, maxQueriers, share)
 	if queue == nil {
 		// This can only happen if userID is "".
 		return errors.New("no queue found")
@@ -115,6 +153,11 @@ func (q *RequestQueue) GetNextRequestForQuerier(ctx context.Context, last UserIn
 	q.mtx.Lock()
 	defer q.mtx.Unlock()
 
+	if last.discarded {
+		q.queues.refundUser(last.user)
+		last.discarded = false
+	}
+
 	querierWait := false
 
 FindQueue:
@@ -142,6 +185,8 @@ FindQueue:
 		// Pick next request from the queue.
 		for {
 			request := <-queue
+			q.queues.chargeUser(userID, requestCost(request))
+			last.user = userID
 			if len(queue) == 0 {
 				q.queues.deleteQueue(userID)
 			}
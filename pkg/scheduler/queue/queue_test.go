@@ -36,7 +36,7 @@ func BenchmarkGetNextRequest(b *testing.B) {
 			for j := 0; j < numTenants; j++ {
 				userID := strconv.Itoa(j)
 
-				err := queue.EnqueueRequest(userID, "request", 0, nil)
+				err := queue.EnqueueRequest(userID, "request", 0, TenantShare{}, nil)
 				if err != nil {
 					b.Fatal(err)
 				}
@@ -100,7 +100,7 @@ func BenchmarkQueueRequest(b *testing.B) {
 	for n := 0; n < b.N; n++ {
 		for i := 0; i < maxOutstandingPerTenant; i++ {
 			for j := 0; j < numTenants; j++ {
-				err := queues[n].EnqueueRequest(users[j], requests[j], 0, nil)
+				err := queues[n].EnqueueRequest(users[j], requests[j], 0, TenantShare{}, nil)
 				if err != nil {
 					b.Fatal(err)
 				}
@@ -141,7 +141,7 @@ func TestRequestQueue_GetNextRequestForQuerier_ShouldGetRequestAfterReshardingBe
 
 	// Enqueue a request from an user which would be assigned to querier-1.
 	// NOTE: "user-1" hash falls in the querier-1 shard.
-	require.NoError(t, queue.EnqueueRequest("user-1", "request", 1, nil))
+	require.NoError(t, queue.EnqueueRequest("user-1", "request", 1, TenantShare{}, nil))
 
 	startTime := time.Now()
 	querier2wg.Wait()
@@ -151,6 +151,100 @@ func TestRequestQueue_GetNextRequestForQuerier_ShouldGetRequestAfterReshardingBe
 	assert.GreaterOrEqual(t, waitTime.Milliseconds(), forgetDelay.Milliseconds())
 }
 
+type costedRequest struct {
+	cost float64
+}
+
+func (r costedRequest) Cost() float64 {
+	return r.cost
+}
+
+func TestRequestQueue_TenantShares(t *testing.T) {
+	newQueue := func() *RequestQueue {
+		queue := NewRequestQueue(100, 0,
+			prometheus.NewGaugeVec(prometheus.GaugeOpts{}, []string{"user"}),
+			prometheus.NewCounterVec(prometheus.CounterOpts{}, []string{"user"}))
+		queue.RegisterQuerierConnection("querier-1")
+		return queue
+	}
+
+	// dequeue returns the users of the next n dequeued requests.
+	dequeue := func(t *testing.T, queue *RequestQueue, n int) []string {
+		var (
+			users []string
+			last  = FirstUser()
+		)
+		for i := 0; i < n; i++ {
+			req, idx, err := queue.GetNextRequestForQuerier(context.Background(), last, "querier-1")
+			require.NoError(t, err)
+			last = idx
+			users = append(users, req.(string))
+		}
+		return users
+	}
+
+	t.Run("higher priority classes are dequeued first", func(t *testing.T) {
+		queue := newQueue()
+		for i := 0; i < 2; i++ {
+			require.NoError(t, queue.EnqueueRequest("low", "low", 0, TenantShare{Priority: 0}, nil))
+			require.NoError(t, queue.EnqueueRequest("high", "high", 0, TenantShare{Priority: 1}, nil))
+		}
+		require.Equal(t, []string{"high", "high", "low", "low"}, dequeue(t, queue, 4))
+	})
+
+	t.Run("users are dequeued according to their weights", func(t *testing.T) {
+		queue := newQueue()
+		for i := 0; i < 6; i++ {
+			require.NoError(t, queue.EnqueueRequest("a", "a", 0, TenantShare{Weight: 2}, nil))
+			require.NoError(t, queue.EnqueueRequest("b", "b", 0, TenantShare{Weight: 1}, nil))
+		}
+		require.Equal(t, []string{"a", "b", "a", "b", "a", "a"}, dequeue(t, queue, 6))
+	})
+
+	t.Run("users are charged the cost of their requests", func(t *testing.T) {
+		queue := newQueue()
+		for i := 0; i < 4; i++ {
+			require.NoError(t, queue.EnqueueRequest("big", costedRequest{cost: 3}, 0, TenantShare{}, nil))
+			require.NoError(t, queue.EnqueueRequest("small", costedRequest{cost: 1}, 0, TenantShare{}, nil))
+		}
+
+		var users []string
+		last := FirstUser()
+		for i := 0; i < 5; i++ {
+			req, idx, err := queue.GetNextRequestForQuerier(context.Background(), last, "querier-1")
+			require.NoError(t, err)
+			last = idx
+			if req.(costedRequest).cost == 3 {
+				users = append(users, "big")
+			} else {
+				users = append(users, "small")
+			}
+		}
+		require.Equal(t, []string{"big", "small", "small", "small", "big"}, users)
+	})
+
+	t.Run("users aren't charged for discarded requests", func(t *testing.T) {
+		queue := newQueue()
+		for i := 0; i < 3; i++ {
+			require.NoError(t, queue.EnqueueRequest("a", "a", 0, TenantShare{}, nil))
+			require.NoError(t, queue.EnqueueRequest("b", "b", 0, TenantShare{}, nil))
+		}
+
+		req, last, err := queue.GetNextRequestForQuerier(context.Background(), FirstUser(), "querier-1")
+		require.NoError(t, err)
+		require.Equal(t, "a", req)
+
+		// The request expired: the next request of the same user is dequeued.
+		req, last, err = queue.GetNextRequestForQuerier(context.Background(), last.ReuseLastUser(), "querier-1")
+		require.NoError(t, err)
+		require.Equal(t, "a", req)
+
+		req, _, err = queue.GetNextRequestForQuerier(context.Background(), last, "querier-1")
+		require.NoError(t, err)
+		require.Equal(t, "b", req)
+	})
+}
+
 func TestContextCond(t *testing.T) {
 	t.Run("wait until broadcast", func(t *testing.T) {
 		t.Parallel()
@@ -62,6 +62,17 @@ type userQueue struct {
 
 	// Points back to 'users' field in queues. Enables quick cleanup.
 	index int
+
+	// Priority class and weight of the user, see TenantShare.
+	priority int
+	weight   float64
+
+	// Virtual time of the user: the cost of its dequeued requests divided by its weight. Among the users of the
+	// highest priority class, the one with the lowest virtual time is dequeued first.
+	vtime float64
+
+	// Virtual time charged for the last dequeued request, refunded if the querier discards the request.
+	lastCharge float64
 }
 
 func newUserQueues(maxUserQueueSize int, forgetDelay time.Duration) *queues {
@@ -98,7 +109,8 @@ func (q *queues) deleteQueue(userID string) {
 // MaxQueriers is used to compute which queriers should handle requests for this user.
 // If maxQueriers is <= 0, all queriers can handle this user's requests.
 // If maxQueriers has changed since the last call, queriers for this are recomputed.
-func (q *queues) getOrAddQueue(userID string, maxQueriers int) chan Request {
+// The share of the user is updated on each call, as it can change between calls too.
+func (q *queues) getOrAddQueue(userID string, maxQueriers int, share TenantShare) chan Request {
 	// Empty user is not allowed, as that would break our users list ("" is used for free spot).
 	if userID == "" {
 		return nil
@@ -115,6 +127,9 @@ func (q *queues) getOrAddQueue(userID string, maxQueriers int) chan Request {
 			ch:    make(chan Request, q.maxUserQueueSize),
 			seed:  util.ShuffleShardSeed(userID, ""),
 			index: -1,
+			// Users don't accumulate credit while they have no requests queued, so a new queue starts
+			// at the virtual time of the least served user of its priority class.
+			vtime: q.minVirtualTime(share.Priority),
 		}
 		q.userQueues[userID] = uq
 
@@ -139,12 +154,56 @@ func (q *queues) getOrAddQueue(userID string, maxQueriers int) chan Request {
 		uq.queriers = shuffleQueriersForUser(uq.seed, maxQueriers, q.sortedQueriers, nil)
 	}
 
+	uq.priority = share.Priority
+	uq.weight = share.weight()
+
 	return uq.ch
 }
 
-// Finds next queue for the querier. To support fair scheduling between users, client is expected
-// to pass last user index returned by this function as argument. Is there was no previous
-// last user index, use -1.
+// minVirtualTime returns the lowest virtual time of the users of the given priority class, or 0 if there are none.
+func (q *queues) minVirtualTime(priority int) float64 {
+	var (
+		min   float64
+		found bool
+	)
+	for _, uq := range q.userQueues {
+		if uq.priority != priority {
+			continue
+		}
+		if !found || uq.vtime < min {
+			min = uq.vtime
+			found = true
+		}
+	}
+	return min
+}
+
+// chargeUser charges the cost of a dequeued request to the user, relative to its weight.
+func (q *queues) chargeUser(userID string, cost float64) {
+	uq := q.userQueues[userID]
+	if uq == nil {
+		return
+	}
+
+	uq.lastCharge = cost / uq.weight
+	uq.vtime += uq.lastCharge
+}
+
+// refundUser refunds the user for the last request dequeued, when it has been discarded.
+func (q *queues) refundUser(userID string) {
+	uq := q.userQueues[userID]
+	if uq == nil {
+		return
+	}
+
+	uq.vtime -= uq.lastCharge
+	uq.lastCharge = 0
+}
+
+// Finds next queue for the querier. Users of the highest priority class are picked first, and among them the
+// user with the lowest virtual time. To support fair scheduling between users with the same virtual time, client
+// is expected to pass last user index returned by this function as argument: such users are picked in a round-robin
+// fashion, starting after the last user. Is there was no previous last user index, use -1.
 func (q *queues) getNextQueueForQuerier(lastUserIndex int, querierID string) (chan Request, string, int) {
 	uid := lastUserIndex
 
@@ -154,6 +213,10 @@ func (q *queues) getNextQueueForQuerier(lastUserIndex int, querierID string) (ch
 		return nil, "", uid
 	}
 
+	var (
+		next    *userQueue
+		nextUID = -1
+	)
 	for iters := 0; iters < len(q.users); iters++ {
 		uid = uid + 1
 
@@ -177,9 +240,16 @@ func (q *queues) getNextQueueForQuerier(lastUserIndex int, querierID string) (ch
 			}
 		}
 
-		return q.ch, u, uid
+		if next == nil || q.priority > next.priority || (q.priority == next.priority && q.vtime < next.vtime) {
+			next = q
+			nextUID = uid
+		}
+	}
+
+	if next == nil {
+		return nil, "", uid
 	}
-	return nil, "", uid
+	return next.ch, q.users[nextUID], nextUID
 }
 
 func (q *queues) addQuerierConnection(querierID string) {
@@ -6,6 +6,7 @@ import (
 	"io"
 	"net/http"
 	"net/textproto"
+	"strconv"
 	"sync"
 	"time"
 
@@ -92,6 +93,7 @@ type Scheduler struct {
 	connectedQuerierClients  prometheus.GaugeFunc
 	connectedFrontendClients prometheus.GaugeFunc
 	queueDuration            prometheus.Histogram
+	tenantQueueDuration      *prometheus.HistogramVec
 	schedulerRunning         prometheus.Gauge
 	inflightRequests         prometheus.Summary
 
@@ -162,6 +164,11 @@ func NewScheduler(cfg Config, limits Limits, log log.Logger, registerer promethe
 		Help:    "Time spend by requests in queue before getting picked up by a querier.",
 		Buckets: prometheus.DefBuckets,
 	})
+	s.tenantQueueDuration = promauto.With(registerer).NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "cortex_query_scheduler_tenant_queue_duration_seconds",
+		Help:    "Time spend by requests in queue before getting picked up by a querier, per tenant and priority class.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"user", "priority"})
 	s.connectedQuerierClients = promauto.With(registerer).NewGaugeFunc(prometheus.GaugeOpts{
 		Name: "cortex_query_scheduler_connected_querier_clients",
 		Help: "Number of querier worker clients currently connected to the query-scheduler.",
@@ -242,6 +249,12 @@ func NewScheduler(cfg Config, limits Limits, log log.Logger, registerer promethe
 type Limits interface {
 	// MaxQueriersPerUser returns max queriers to use per tenant, or 0 if shuffle sharding is disabled.
 	MaxQueriersPerUser(user string) int
+
+	// QueryPriority returns the priority class of the queries of the tenant.
+	QueryPriority(user string) int
+
+	// QueryWeight returns the share of the queriers given to the tenant, relative to the tenants of the same priority class.
+	QueryWeight(user string) float64
 }
 
 type schedulerRequest struct {
@@ -252,6 +265,8 @@ type schedulerRequest struct {
 	statsEnabled    bool
 
 	queueTime time.Time
+	share     queue.TenantShare
+	cost      float64
 
 	ctx       context.Context
 	ctxCancel context.CancelFunc
@@ -261,6 +276,11 @@ type schedulerRequest struct {
 	parentSpanContext opentracing.SpanContext
 }
 
+// Cost implements queue.CostEstimator.
+func (s *schedulerRequest) Cost() float64 {
+	return s.cost
+}
+
 // FrontendLoop handles connection from frontend.
 func (s *Scheduler) FrontendLoop(frontend schedulerpb.SchedulerForFrontend_FrontendLoopServer) error {
 	frontendAddress, frontendCtx, err := s.frontendConnected(frontend)
@@ -402,6 +422,7 @@ func (s *Scheduler) enqueueRequest(frontendContext context.Context, frontendAddr
 	req.queueSpan, req.ctx = opentracing.StartSpanFromContextWithTracer(ctx, tracer, "queued", opentracing.ChildOf(parentSpanContext))
 	req.queueTime = now
 	req.ctxCancel = cancel
+	req.cost = requestCost(msg.HttpRequest)
 
 	// aggregate the max queriers limit in the case of a multi tenant query
 	tenantIDs, err := tenant.TenantIDsFromOrgID(userID)
@@ -409,9 +430,14 @@ func (s *Scheduler) enqueueRequest(frontendContext context.Context, frontendAddr
 		return err
 	}
 	maxQueriers := validation.SmallestPositiveNonZeroIntPerTenant(tenantIDs, s.limits.MaxQueriersPerUser)
+	// a multi tenant query gets the lowest priority and weight of its tenants.
+	req.share = queue.TenantShare{
+		Priority: validation.SmallestPositiveIntPerTenant(tenantIDs, s.limits.QueryPriority),
+		Weight:   validation.SmallestPositiveNonZeroFloat64PerTenant(tenantIDs, s.limits.QueryWeight),
+	}
 
 	s.activeUsers.UpdateUserTimestamp(userID, now)
-	return s.requestQueue.EnqueueRequest(userID, req, maxQueriers, func() {
+	return s.requestQueue.EnqueueRequest(userID, req, maxQueriers, req.share, func() {
 		shouldCancel = false
 
 		s.pendingRequestsMu.Lock()
@@ -460,6 +486,7 @@ func (s *Scheduler) QuerierLoop(querier schedulerpb.SchedulerForQuerier_QuerierL
 
 		reqQueueTime := time.Since(r.queueTime)
 		s.queueDuration.Observe(reqQueueTime.Seconds())
+		s.tenantQueueDuration.WithLabelValues(r.userID, strconv.Itoa(r.share.Priority)).Observe(reqQueueTime.Seconds())
 		r.queueSpan.Finish()
 
 		// Add HTTP header to the request containing the query queue time
@@ -713,6 +740,7 @@ func (s *Scheduler) stopping(_ error) error {
 func (s *Scheduler) cleanupMetricsForInactiveUser(user string) {
 	s.queueLength.DeleteLabelValues(user)
 	s.discardedRequests.DeleteLabelValues(user)
+	s.tenantQueueDuration.DeletePartialMatch(prometheus.Labels{"user": user})
 }
 
 func (s *Scheduler) getConnectedFrontendClientsMetric() float64 {
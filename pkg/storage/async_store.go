@@ -22,6 +22,7 @@ import (
 type IngesterQuerier interface {
 	GetChunkIDs(ctx context.Context, from, through model.Time, matchers ...*labels.Matcher) ([]string, error)
 	Stats(ctx context.Context, userID string, from, through model.Time, matchers ...*labels.Matcher) (*stats.Stats, error)
+	Volume(ctx context.Context, userID string, from, through model.Time, targetLabels []string, matchers ...*labels.Matcher) (*stats.Volumes, error)
 }
 
 type AsyncStoreCfg struct {
@@ -135,6 +136,42 @@ func (a *AsyncStore) Stats(ctx context.Context, userID string, from, through mod
 	return &merged, nil
 }
 
+func (a *AsyncStore) Volume(ctx context.Context, userID string, from, through model.Time, targetLabels []string, matchers ...*labels.Matcher) (*stats.Volumes, error) {
+	if a.queryIngestersWithin != 0 {
+		// don't query ingesters if the query does not overlap with queryIngestersWithin.
+		if !through.After(model.Now().Add(-a.queryIngestersWithin)) {
+			return a.Store.Volume(ctx, userID, from, through, targetLabels, matchers...)
+		}
+	}
+
+	type f func() (*stats.Volumes, error)
+	jobs := []f{
+		f(func() (*stats.Volumes, error) {
+			return a.ingesterQuerier.Volume(ctx, userID, from, through, targetLabels, matchers...)
+		}),
+		f(func() (*stats.Volumes, error) {
+			return a.Store.Volume(ctx, userID, from, through, targetLabels, matchers...)
+		}),
+	}
+	resps := make([]*stats.Volumes, len(jobs))
+
+	if err := concurrency.ForEachJob(
+		ctx,
+		len(jobs),
+		2,
+		func(ctx context.Context, i int) error {
+			resp, err := jobs[i]()
+			resps[i] = resp
+			return err
+		},
+	); err != nil {
+		return nil, err
+	}
+
+	merged := stats.MergeVolumes(0, resps...)
+	return &merged, nil
+}
+
 func (a *AsyncStore) mergeIngesterAndStoreChunks(userID string, storeChunks [][]chunk.Chunk, fetchers []*fetcher.Fetcher, ingesterChunkIDs []string) ([][]chunk.Chunk, []*fetcher.Fetcher, error) {
 	ingesterChunkIDs = filterDuplicateChunks(a.scfg, storeChunks, ingesterChunkIDs)
 	level.Debug(util_log.Logger).Log("msg", "post-filtering ingester chunks", "count", len(ingesterChunkIDs))
@@ -0,0 +1,94 @@
+// Package bloom implements the bloom filters written alongside chunks, which tell
+// if a chunk may contain lines matching a line filter without downloading it.
+package bloom
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	willf "github.com/willf/bloom"
+)
+
+const (
+	// NGramSize is the number of bytes of the n-grams added to the filters.
+	// Substrings shorter than this can't be looked up.
+	NGramSize = 4
+
+	// FalsePositiveRate is the probability of a single n-gram lookup being a false positive.
+	FalsePositiveRate = 0.01
+
+	formatV1 = byte(1)
+)
+
+// ErrInvalidFormat is returned when decoding a filter with an unknown format.
+var ErrInvalidFormat = errors.New("invalid chunk bloom filter format")
+
+// Builder collects the n-grams of the lines of a chunk.
+type Builder struct {
+	ngrams map[uint32]struct{}
+}
+
+// NewBuilder creates an empty filter builder.
+func NewBuilder() *Builder {
+	return &Builder{ngrams: map[uint32]struct{}{}}
+}
+
+// Add adds the n-grams of the line.
+func (b *Builder) Add(line string) {
+	for i := 0; i+NGramSize <= len(line); i++ {
+		b.ngrams[binary.LittleEndian.Uint32([]byte(line[i:i+NGramSize]))] = struct{}{}
+	}
+}
+
+// Build returns a filter of the n-grams added so far, sized for FalsePositiveRate.
+func (b *Builder) Build() *Filter {
+	n := uint(len(b.ngrams))
+	if n == 0 {
+		n = 1
+	}
+	f := willf.NewWithEstimates(n, FalsePositiveRate)
+	var ngram [NGramSize]byte
+	for v := range b.ngrams {
+		binary.LittleEndian.PutUint32(ngram[:], v)
+		f.Add(ngram[:])
+	}
+	return &Filter{filter: f}
+}
+
+// Filter tells if the lines of a chunk may contain a substring.
+type Filter struct {
+	filter *willf.BloomFilter
+}
+
+// MayContain returns false if no line of the chunk contains s.
+// It always returns true for strings shorter than NGramSize.
+func (f *Filter) MayContain(s string) bool {
+	for i := 0; i+NGramSize <= len(s); i++ {
+		if !f.filter.Test([]byte(s[i : i+NGramSize])) {
+			return false
+		}
+	}
+	return true
+}
+
+// Encode returns the binary representation of the filter.
+func (f *Filter) Encode() ([]byte, error) {
+	b, err := f.filter.GobEncode()
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{formatV1}, b...), nil
+}
+
+// Decode decodes a filter encoded with Encode.
+func Decode(b []byte) (*Filter, error) {
+	if len(b) == 0 || b[0] != formatV1 {
+		return nil, ErrInvalidFormat
+	}
+	f := &willf.BloomFilter{}
+	if err := f.GobDecode(b[1:]); err != nil {
+		return nil, fmt.Errorf("decoding chunk bloom filter: %w", err)
+	}
+	return &Filter{filter: f}, nil
+}
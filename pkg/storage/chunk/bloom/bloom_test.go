@@ -0,0 +1,59 @@
+package bloom
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilter(t *testing.T) {
+	b := NewBuilder()
+	for i := 0; i < 1000; i++ {
+		b.Add(fmt.Sprintf(`level=info msg="request served" traceID=%08x duration=%dms`, i*7919, i))
+	}
+	f := b.Build()
+
+	for _, s := range []string{
+		`traceID=00001eef`,
+		`request served`,
+		`duration=999ms`,
+		`ms`,
+		``,
+	} {
+		require.True(t, f.MayContain(s), s)
+	}
+	require.False(t, f.MayContain(`traceID=zzzzzzzz`))
+	require.False(t, f.MayContain(`level=error`))
+
+	falsePositives := 0
+	for i := 0; i < 1000; i++ {
+		if f.MayContain(fmt.Sprintf("%x-not-found", i)) {
+			falsePositives++
+		}
+	}
+	require.Less(t, falsePositives, 10)
+}
+
+func TestFilterEncoding(t *testing.T) {
+	b := NewBuilder()
+	b.Add("foo bar buzz")
+	f := b.Build()
+
+	encoded, err := f.Encode()
+	require.NoError(t, err)
+	decoded, err := Decode(encoded)
+	require.NoError(t, err)
+	require.True(t, decoded.MayContain("bar buzz"))
+	require.False(t, decoded.MayContain("fizz"))
+
+	_, err = Decode(encoded[1:])
+	require.ErrorIs(t, err, ErrInvalidFormat)
+
+	empty, err := NewBuilder().Build().Encode()
+	require.NoError(t, err)
+	decoded, err = Decode(empty)
+	require.NoError(t, err)
+	require.False(t, decoded.MayContain("fizz"))
+	require.True(t, decoded.MayContain("f"))
+}
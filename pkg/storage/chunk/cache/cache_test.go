@@ -207,6 +207,13 @@ func TestFifoCache(t *testing.T) {
 	testCache(t, cache)
 }
 
+func TestDiskCache(t *testing.T) {
+	cache, err := cache.NewDiskCache("test", cache.DiskCacheConfig{Enabled: true, Directory: t.TempDir(), MaxSizeMB: 100, TTL: 1 * time.Hour},
+		nil, log.NewNopLogger(), "test")
+	require.NoError(t, err)
+	testCache(t, cache)
+}
+
 func TestSnappyCache(t *testing.T) {
 	cache := cache.NewSnappy(cache.NewMockCache(), log.NewNopLogger())
 	testCache(t, cache)
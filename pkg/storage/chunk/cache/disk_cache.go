@@ -0,0 +1,387 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"encoding/binary"
+	"errors"
+	"flag"
+	"fmt"
+	"hash/crc32"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/loki/pkg/logqlmodel/stats"
+	util_log "github.com/grafana/loki/pkg/util/log"
+)
+
+const (
+	diskCacheTmpSuffix = ".tmp"
+	// Entries are spread over 256 sub-directories, to keep the directories small.
+	diskCacheDirs = 256
+
+	corruptedReason = "corrupted"
+)
+
+var (
+	errDiskCacheCorrupted = errors.New("corrupted disk cache entry")
+	diskCacheCastagnoli   = crc32.MakeTable(crc32.Castagnoli)
+)
+
+// DiskCacheConfig configures a cache storing its entries in a local directory, which persist across restarts.
+type DiskCacheConfig struct {
+	Enabled   bool          `yaml:"enabled"`
+	Directory string        `yaml:"directory"`
+	MaxSizeMB int64         `yaml:"max_size_mb"`
+	TTL       time.Duration `yaml:"ttl"`
+}
+
+func (cfg *DiskCacheConfig) RegisterFlagsWithPrefix(prefix, description string, f *flag.FlagSet) {
+	f.BoolVar(&cfg.Enabled, prefix+"disk-cache.enabled", false, description+"Whether the disk cache is enabled. The disk cache is checked after the embedded cache and before memcached or redis.")
+	f.StringVar(&cfg.Directory, prefix+"disk-cache.directory", "", description+"Directory the disk cache stores its entries in. It must not be shared with another cache.")
+	f.Int64Var(&cfg.MaxSizeMB, prefix+"disk-cache.max-size-mb", 10000, description+"Maximum disk size of the cache in MB. The least recently used entries are evicted when it is exceeded.")
+	f.DurationVar(&cfg.TTL, prefix+"disk-cache.ttl", 24*time.Hour, description+"The time to live for items in the cache. 0 to keep them until they are evicted.")
+}
+
+func (cfg *DiskCacheConfig) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.Directory == "" {
+		return errors.New("the disk cache directory is required")
+	}
+	if cfg.MaxSizeMB <= 0 {
+		return errors.New("the disk cache max size must be positive")
+	}
+	return nil
+}
+
+// DiskCache is a cache storing each entry in a file of a local directory, evicting the least recently used entries
+// once the directory exceeds its maximum size.
+//
+// The files are the index of the cache: they are written to a temporary file renamed once complete, and hold a
+// checksum of their content, so that the cache can be loaded back from the directory after a restart or a crash.
+// Their modification time is updated on each hit, to restore the order of the entries.
+type DiskCache struct {
+	cacheType stats.CacheType
+	logger    log.Logger
+
+	directory    string
+	maxSizeBytes int64
+	ttl          time.Duration
+
+	mtx       sync.Mutex
+	entries   map[string]*list.Element
+	lru       *list.List
+	sizeBytes int64
+
+	entriesCurrent prometheus.Gauge
+	entriesEvicted *prometheus.CounterVec
+	diskBytes      prometheus.Gauge
+}
+
+type diskCacheEntry struct {
+	path string
+	size int64
+}
+
+// NewDiskCache returns a DiskCache loading the entries already in the directory.
+func NewDiskCache(name string, cfg DiskCacheConfig, reg prometheus.Registerer, logger log.Logger, cacheType stats.CacheType) (*DiskCache, error) {
+	util_log.WarnExperimentalUse(fmt.Sprintf("Disk cache - %s", name), logger)
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	c := &DiskCache{
+		cacheType:    cacheType,
+		logger:       log.With(logger, "cache", name),
+		directory:    cfg.Directory,
+		maxSizeBytes: cfg.MaxSizeMB * 1e6,
+		ttl:          cfg.TTL,
+		entries:      map[string]*list.Element{},
+		lru:          list.New(),
+
+		entriesCurrent: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Namespace:   "loki",
+			Subsystem:   "disk_cache",
+			Name:        "entries",
+			Help:        "The total number of entries in the disk cache",
+			ConstLabels: prometheus.Labels{"cache": name},
+		}),
+		entriesEvicted: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace:   "loki",
+			Subsystem:   "disk_cache",
+			Name:        "evicted_total",
+			Help:        "The total number of entries evicted from the disk cache",
+			ConstLabels: prometheus.Labels{"cache": name},
+		}, []string{"reason"}),
+		diskBytes: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Namespace:   "loki",
+			Subsystem:   "disk_cache",
+			Name:        "disk_bytes",
+			Help:        "The current size of the disk cache in bytes",
+			ConstLabels: prometheus.Labels{"cache": name},
+		}),
+	}
+
+	if err := c.load(); err != nil {
+		return nil, fmt.Errorf("failed to load disk cache from %s: %w", cfg.Directory, err)
+	}
+	return c, nil
+}
+
+// load rebuilds the index of the cache from the files of the directory, removing the files left incomplete.
+func (c *DiskCache) load() error {
+	for i := 0; i < diskCacheDirs; i++ {
+		if err := os.MkdirAll(filepath.Join(c.directory, fmt.Sprintf("%02x", i)), 0o750); err != nil {
+			return err
+		}
+	}
+
+	type file struct {
+		diskCacheEntry
+		modTime time.Time
+	}
+	var files []file
+	err := filepath.WalkDir(c.directory, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		if strings.HasSuffix(path, diskCacheTmpSuffix) {
+			return os.Remove(path)
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		files = append(files, file{diskCacheEntry: diskCacheEntry{path: path, size: info.Size()}, modTime: info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// The most recently used files are at the front of the list.
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.After(files[j].modTime)
+	})
+
+	c.mtx.Lock()
+	for _, f := range files {
+		entry := f.diskCacheEntry
+		c.entries[entry.path] = c.lru.PushBack(&entry)
+		c.sizeBytes += entry.size
+	}
+	evicted := c.evict(0)
+	c.updateMetrics()
+	c.mtx.Unlock()
+
+	c.remove(evicted)
+	level.Info(c.logger).Log("msg", "loaded disk cache", "entries", len(files)-len(evicted), "bytes", c.sizeBytes)
+	return nil
+}
+
+// Fetch implements Cache.
+func (c *DiskCache) Fetch(_ context.Context, keys []string) (found []string, bufs [][]byte, missing []string, err error) {
+	found, missing, bufs = make([]string, 0, len(keys)), make([]string, 0, len(keys)), make([][]byte, 0, len(keys))
+	for _, key := range keys {
+		val, ok := c.get(key)
+		if !ok {
+			missing = append(missing, key)
+			continue
+		}
+
+		found = append(found, key)
+		bufs = append(bufs, val)
+	}
+	return
+}
+
+func (c *DiskCache) get(key string) ([]byte, bool) {
+	path := c.path(key)
+
+	c.mtx.Lock()
+	element, ok := c.entries[path]
+	if ok {
+		c.lru.MoveToFront(element)
+	}
+	c.mtx.Unlock()
+	if !ok {
+		return nil, false
+	}
+
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			level.Warn(c.logger).Log("msg", "failed to read disk cache entry", "path", path, "err", err)
+		}
+		c.drop(path, element, corruptedReason)
+		return nil, false
+	}
+
+	created, entryKey, value, err := decodeDiskCacheEntry(buf)
+	if err != nil {
+		level.Warn(c.logger).Log("msg", "dropping disk cache entry", "path", path, "err", err)
+		c.drop(path, element, corruptedReason)
+		return nil, false
+	}
+	if c.ttl > 0 && time.Since(created) > c.ttl {
+		c.drop(path, element, expiredReason)
+		return nil, false
+	}
+	if entryKey != key {
+		// Another key with the same hash.
+		return nil, false
+	}
+
+	// Persist the access, to keep the order of the entries across restarts.
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+	return value, true
+}
+
+// Store implements Cache.
+func (c *DiskCache) Store(_ context.Context, keys []string, bufs [][]byte) error {
+	var lastErr error
+	for i := range keys {
+		if err := c.put(keys[i], bufs[i]); err != nil {
+			level.Warn(c.logger).Log("msg", "failed to write disk cache entry", "err", err)
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+func (c *DiskCache) put(key string, value []byte) error {
+	buf := encodeDiskCacheEntry(time.Now(), key, value)
+	if int64(len(buf)) > c.maxSizeBytes {
+		c.entriesEvicted.WithLabelValues(tooBigReason).Inc()
+		return nil
+	}
+
+	path := c.path(key)
+	tmp := fmt.Sprintf("%s.%d%s", path, time.Now().UnixNano(), diskCacheTmpSuffix)
+	if err := os.WriteFile(tmp, buf, 0o640); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+
+	c.mtx.Lock()
+	if element, ok := c.entries[path]; ok {
+		entry := element.Value.(*diskCacheEntry)
+		c.sizeBytes += int64(len(buf)) - entry.size
+		entry.size = int64(len(buf))
+		c.lru.MoveToFront(element)
+	} else {
+		c.entries[path] = c.lru.PushFront(&diskCacheEntry{path: path, size: int64(len(buf))})
+		c.sizeBytes += int64(len(buf))
+	}
+	evicted := c.evict(1)
+	c.updateMetrics()
+	c.mtx.Unlock()
+
+	c.remove(evicted)
+	return nil
+}
+
+// evict removes the least recently used entries from the index until the cache fits its maximum size,
+// keeping the first keep entries, and returns the paths of the files to remove. Must be called with the lock held.
+func (c *DiskCache) evict(keep int) []string {
+	var evicted []string
+	for c.sizeBytes > c.maxSizeBytes && c.lru.Len() > keep {
+		entry := c.lru.Remove(c.lru.Back()).(*diskCacheEntry)
+		delete(c.entries, entry.path)
+		c.sizeBytes -= entry.size
+		c.entriesEvicted.WithLabelValues(fullReason).Inc()
+		evicted = append(evicted, entry.path)
+	}
+	return evicted
+}
+
+// drop removes an entry which can't be returned from the cache.
+func (c *DiskCache) drop(path string, element *list.Element, reason string) {
+	c.mtx.Lock()
+	if c.entries[path] != element {
+		// The entry has been replaced meanwhile.
+		c.mtx.Unlock()
+		return
+	}
+	entry := c.lru.Remove(element).(*diskCacheEntry)
+	delete(c.entries, path)
+	c.sizeBytes -= entry.size
+	c.entriesEvicted.WithLabelValues(reason).Inc()
+	c.updateMetrics()
+	c.mtx.Unlock()
+
+	c.remove([]string{path})
+}
+
+func (c *DiskCache) remove(paths []string) {
+	for _, path := range paths {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			level.Warn(c.logger).Log("msg", "failed to remove disk cache entry", "path", path, "err", err)
+		}
+	}
+}
+
+func (c *DiskCache) updateMetrics() {
+	c.entriesCurrent.Set(float64(c.lru.Len()))
+	c.diskBytes.Set(float64(c.sizeBytes))
+}
+
+func (c *DiskCache) path(key string) string {
+	hash := xxhash.Sum64String(key)
+	return filepath.Join(c.directory, fmt.Sprintf("%02x", hash%diskCacheDirs), fmt.Sprintf("%016x", hash))
+}
+
+// Stop implements Cache. The entries are kept on disk, to be loaded back on the next start.
+func (c *DiskCache) Stop() {}
+
+func (c *DiskCache) GetCacheType() stats.CacheType {
+	return c.cacheType
+}
+
+// encodeDiskCacheEntry encodes an entry as its creation time, the length of its key, its key and its value,
+// followed by a checksum of the previous fields.
+func encodeDiskCacheEntry(created time.Time, key string, value []byte) []byte {
+	buf := make([]byte, 0, 8+binary.MaxVarintLen64+len(key)+len(value)+4)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(created.UnixNano()))
+	buf = binary.AppendUvarint(buf, uint64(len(key)))
+	buf = append(buf, key...)
+	buf = append(buf, value...)
+	return binary.BigEndian.AppendUint32(buf, crc32.Checksum(buf, diskCacheCastagnoli))
+}
+
+func decodeDiskCacheEntry(buf []byte) (time.Time, string, []byte, error) {
+	if len(buf) < 8+1+4 {
+		return time.Time{}, "", nil, errDiskCacheCorrupted
+	}
+	data, checksum := buf[:len(buf)-4], binary.BigEndian.Uint32(buf[len(buf)-4:])
+	if crc32.Checksum(data, diskCacheCastagnoli) != checksum {
+		return time.Time{}, "", nil, errDiskCacheCorrupted
+	}
+
+	created := time.Unix(0, int64(binary.BigEndian.Uint64(data)))
+	keyLen, n := binary.Uvarint(data[8:])
+	if n <= 0 || uint64(len(data)-8-n) < keyLen {
+		return time.Time{}, "", nil, errDiskCacheCorrupted
+	}
+	data = data[8+n:]
+	return created, string(data[:keyLen]), data[keyLen:], nil
+}
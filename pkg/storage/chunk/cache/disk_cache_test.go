@@ -0,0 +1,124 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestDiskCache(t *testing.T, dir string, ttl time.Duration) *DiskCache {
+	c, err := NewDiskCache("test", DiskCacheConfig{Enabled: true, Directory: dir, MaxSizeMB: 1, TTL: ttl}, nil, log.NewNopLogger(), "test")
+	require.NoError(t, err)
+	return c
+}
+
+func TestDiskCacheEviction(t *testing.T) {
+	c := newTestDiskCache(t, t.TempDir(), time.Hour)
+	ctx := context.Background()
+
+	entrySize := int64(len(encodeDiskCacheEntry(time.Now(), "key-00", []byte("value-00"))))
+	c.maxSizeBytes = 5 * entrySize
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, c.Store(ctx, []string{fmt.Sprintf("key-%02d", i)}, [][]byte{[]byte(fmt.Sprintf("value-%02d", i))}))
+	}
+	// key-00 is now the most recently used entry.
+	found, _, _, err := c.Fetch(ctx, []string{"key-00"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"key-00"}, found)
+
+	require.NoError(t, c.Store(ctx, []string{"key-05", "key-06"}, [][]byte{[]byte("value-05"), []byte("value-06")}))
+
+	found, bufs, missing, err := c.Fetch(ctx, []string{"key-00", "key-01", "key-02", "key-03", "key-04", "key-05", "key-06"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"key-00", "key-03", "key-04", "key-05", "key-06"}, found)
+	require.Equal(t, [][]byte{[]byte("value-00"), []byte("value-03"), []byte("value-04"), []byte("value-05"), []byte("value-06")}, bufs)
+	require.Equal(t, []string{"key-01", "key-02"}, missing)
+
+	require.Equal(t, float64(2), testutil.ToFloat64(c.entriesEvicted.WithLabelValues(fullReason)))
+	require.Equal(t, float64(5), testutil.ToFloat64(c.entriesCurrent))
+	require.Equal(t, float64(5*entrySize), testutil.ToFloat64(c.diskBytes))
+
+	// Entries bigger than the cache are not stored.
+	require.NoError(t, c.Store(ctx, []string{"big"}, [][]byte{make([]byte, 5*entrySize)}))
+	_, _, missing, err = c.Fetch(ctx, []string{"big"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"big"}, missing)
+	require.Equal(t, float64(1), testutil.ToFloat64(c.entriesEvicted.WithLabelValues(tooBigReason)))
+}
+
+func TestDiskCacheReload(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	c := newTestDiskCache(t, dir, time.Hour)
+	require.NoError(t, c.Store(ctx, []string{"foo", "bar"}, [][]byte{[]byte("1"), []byte("2")}))
+	c.Stop()
+
+	// A write interrupted by a crash leaves a temporary file.
+	tmp := c.path("baz") + ".1" + diskCacheTmpSuffix
+	require.NoError(t, os.WriteFile(tmp, []byte("partial"), 0o640))
+
+	c = newTestDiskCache(t, dir, time.Hour)
+	found, bufs, missing, err := c.Fetch(ctx, []string{"foo", "bar", "baz"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"foo", "bar"}, found)
+	require.Equal(t, [][]byte{[]byte("1"), []byte("2")}, bufs)
+	require.Equal(t, []string{"baz"}, missing)
+	require.Equal(t, float64(2), testutil.ToFloat64(c.entriesCurrent))
+	require.NoFileExists(t, tmp)
+
+	// The order of the entries is restored from the modification time of the files.
+	past := time.Now().Add(-time.Minute)
+	require.NoError(t, os.Chtimes(c.path("bar"), past, past))
+	c = newTestDiskCache(t, dir, time.Hour)
+	c.maxSizeBytes = c.sizeBytes
+	require.NoError(t, c.Store(ctx, []string{"qux"}, [][]byte{[]byte("3")}))
+
+	_, _, missing, err = c.Fetch(ctx, []string{"foo", "bar", "qux"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"bar"}, missing)
+}
+
+func TestDiskCacheCorruptedEntries(t *testing.T) {
+	c := newTestDiskCache(t, t.TempDir(), time.Hour)
+	ctx := context.Background()
+
+	require.NoError(t, c.Store(ctx, []string{"foo", "bar"}, [][]byte{[]byte("1"), []byte("2")}))
+	path := c.path("foo")
+	buf, err := os.ReadFile(path)
+	require.NoError(t, err)
+	buf[len(buf)-5] ^= 0xff
+	require.NoError(t, os.WriteFile(path, buf, 0o640))
+
+	found, _, missing, err := c.Fetch(ctx, []string{"foo", "bar"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"bar"}, found)
+	require.Equal(t, []string{"foo"}, missing)
+	require.NoFileExists(t, path)
+	require.Equal(t, float64(1), testutil.ToFloat64(c.entriesEvicted.WithLabelValues(corruptedReason)))
+	require.Equal(t, float64(1), testutil.ToFloat64(c.entriesCurrent))
+}
+
+func TestDiskCacheExpiredEntries(t *testing.T) {
+	c := newTestDiskCache(t, t.TempDir(), time.Minute)
+	ctx := context.Background()
+
+	buf := encodeDiskCacheEntry(time.Now().Add(-2*time.Minute), "foo", []byte("1"))
+	require.NoError(t, os.WriteFile(c.path("foo"), buf, 0o640))
+	c = newTestDiskCache(t, c.directory, time.Minute)
+	require.NoError(t, c.Store(ctx, []string{"bar"}, [][]byte{[]byte("2")}))
+
+	found, _, missing, err := c.Fetch(ctx, []string{"foo", "bar"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"bar"}, found)
+	require.Equal(t, []string{"foo"}, missing)
+	require.NoFileExists(t, c.path("foo"))
+	require.Equal(t, float64(1), testutil.ToFloat64(c.entriesEvicted.WithLabelValues(expiredReason)))
+}
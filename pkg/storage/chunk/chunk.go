@@ -18,6 +18,7 @@ import (
 	errs "github.com/weaveworks/common/errors"
 
 	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/storage/chunk/bloom"
 )
 
 const (
@@ -45,6 +46,9 @@ type Chunk struct {
 	Encoding Encoding `json:"encoding"`
 	Data     Data     `json:"-"`
 
+	// The optional bloom filter of the lines of the chunk, written alongside it by object clients.
+	Bloom *bloom.Filter `json:"-"`
+
 	// The encoded version of the chunk, held so we don't need to re-encode it
 	encoded []byte
 }
@@ -5,6 +5,7 @@ import (
 	"errors"
 
 	"github.com/grafana/loki/pkg/storage/chunk"
+	"github.com/grafana/loki/pkg/storage/chunk/bloom"
 	"github.com/grafana/loki/pkg/storage/stores/series/index"
 )
 
@@ -28,3 +29,10 @@ type Client interface {
 type ObjectAndIndexClient interface {
 	PutChunksAndIndex(ctx context.Context, chunks []chunk.Chunk, index index.WriteBatch) error
 }
+
+// BloomClient is implemented by the clients storing the bloom filters of chunks alongside them.
+type BloomClient interface {
+	// GetBlooms retrieves the bloom filters of the chunks, in the same order.
+	// The filter of a chunk stored without one is nil.
+	GetBlooms(ctx context.Context, chunks []chunk.Chunk) ([]*bloom.Filter, error)
+}
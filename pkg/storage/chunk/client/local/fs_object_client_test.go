@@ -10,9 +10,17 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/loki/pkg/chunkenc"
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/storage/chunk"
+	"github.com/grafana/loki/pkg/storage/chunk/bloom"
+	"github.com/grafana/loki/pkg/storage/chunk/client"
 	"github.com/grafana/loki/pkg/storage/chunk/client/util"
+	"github.com/grafana/loki/pkg/storage/config"
 )
 
 func TestFSObjectClient_DeleteChunksBefore(t *testing.T) {
@@ -199,3 +207,55 @@ func TestFSObjectClient_DeleteObject(t *testing.T) {
 	require.Len(t, commonPrefixes, 0)
 	require.Len(t, files, len(foldersWithFiles["folder2/"]))*/
 }
+
+func TestFSObjectClient_ChunkBlooms(t *testing.T) {
+	fsObjectClient, err := NewFSObjectClient(FSConfig{
+		Directory: t.TempDir(),
+	})
+	require.NoError(t, err)
+	schema := config.SchemaConfig{Configs: []config.PeriodConfig{{Schema: "v12"}}}
+	chunkClient := client.NewClientWithBloomDeletes(fsObjectClient, client.FSEncoder, schema)
+
+	newChunk := func(line string, withBloom bool) chunk.Chunk {
+		mc := chunkenc.NewMemChunk(chunkenc.EncSnappy, chunkenc.UnorderedHeadBlockFmt, 256*1024, 0)
+		require.NoError(t, mc.Append(&logproto.Entry{Timestamp: time.Unix(1, 0), Line: line}))
+		c := chunk.NewChunk("fake", model.Fingerprint(len(line)), labels.Labels{{Name: "foo", Value: line}}, chunkenc.NewFacade(mc, 0, 0), model.TimeFromUnix(1), model.TimeFromUnix(1))
+		require.NoError(t, c.Encode())
+		if withBloom {
+			b := bloom.NewBuilder()
+			b.Add(line)
+			c.Bloom = b.Build()
+		}
+		return c
+	}
+	chunks := []chunk.Chunk{
+		newChunk("traceID=abc123", true),
+		newChunk("traceID=def456789", false),
+	}
+	require.NoError(t, chunkClient.PutChunks(context.Background(), chunks))
+
+	bloomClient, ok := chunkClient.(client.BloomClient)
+	require.True(t, ok)
+	blooms, err := bloomClient.GetBlooms(context.Background(), chunks)
+	require.NoError(t, err)
+	require.Len(t, blooms, 2)
+	require.NotNil(t, blooms[0])
+	require.True(t, blooms[0].MayContain("abc123"))
+	require.False(t, blooms[0].MayContain("def456"))
+	require.Nil(t, blooms[1])
+
+	// the bloom filter is deleted along with its chunk.
+	for _, c := range chunks {
+		require.NoError(t, chunkClient.DeleteChunk(context.Background(), c.UserID, schema.ExternalKey(c.ChunkRef)))
+	}
+	objects, _, err := fsObjectClient.List(context.Background(), "", "")
+	require.NoError(t, err)
+	require.Empty(t, objects)
+
+	// the bloom filter is left when the client doesn't delete them.
+	require.NoError(t, chunkClient.PutChunks(context.Background(), chunks[:1]))
+	require.NoError(t, client.NewClient(fsObjectClient, client.FSEncoder, schema).DeleteChunk(context.Background(), chunks[0].UserID, schema.ExternalKey(chunks[0].ChunkRef)))
+	objects, _, err = fsObjectClient.List(context.Background(), "", "")
+	require.NoError(t, err)
+	require.Len(t, objects, 1)
+}
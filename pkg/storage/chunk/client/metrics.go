@@ -7,6 +7,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promauto"
 
 	"github.com/grafana/loki/pkg/storage/chunk"
+	"github.com/grafana/loki/pkg/storage/chunk/bloom"
 )
 
 // takes a chunk client and exposes metrics for its operations.
@@ -105,6 +106,13 @@ func (c MetricsChunkClient) GetChunks(ctx context.Context, chunks []chunk.Chunk)
 	return chks, nil
 }
 
+func (c MetricsChunkClient) GetBlooms(ctx context.Context, chunks []chunk.Chunk) ([]*bloom.Filter, error) {
+	if bc, ok := c.Client.(BloomClient); ok {
+		return bc.GetBlooms(ctx, chunks)
+	}
+	return make([]*bloom.Filter, len(chunks)), nil
+}
+
 func (c MetricsChunkClient) DeleteChunk(ctx context.Context, userID, chunkID string) error {
 	return c.Client.DeleteChunk(ctx, userID, chunkID)
 }
@@ -8,9 +8,11 @@ import (
 	"strings"
 	"time"
 
+	"github.com/grafana/dskit/concurrency"
 	"github.com/pkg/errors"
 
 	"github.com/grafana/loki/pkg/storage/chunk"
+	"github.com/grafana/loki/pkg/storage/chunk/bloom"
 	"github.com/grafana/loki/pkg/storage/chunk/client/util"
 	"github.com/grafana/loki/pkg/storage/config"
 )
@@ -73,7 +75,12 @@ var FSEncoder = func(schema config.SchemaConfig, chk chunk.Chunk) string {
 	return base64Encoder(key)
 }
 
-const defaultMaxParallel = 150
+const (
+	defaultMaxParallel = 150
+
+	// bloomKeySuffix is appended to the key of a chunk to get the key of its bloom filter.
+	bloomKeySuffix = ".bloom"
+)
 
 // client is used to store chunks in object store backends
 type client struct {
@@ -81,6 +88,7 @@ type client struct {
 	keyEncoder          KeyEncoder
 	getChunkMaxParallel int
 	schema              config.SchemaConfig
+	deleteBlooms        bool
 }
 
 // NewClient wraps the provided ObjectClient with a chunk.Client implementation
@@ -97,6 +105,17 @@ func NewClientWithMaxParallel(store ObjectClient, encoder KeyEncoder, maxParalle
 	}
 }
 
+// NewClientWithBloomDeletes returns a client which also deletes the bloom filters stored alongside the chunks it deletes.
+func NewClientWithBloomDeletes(store ObjectClient, encoder KeyEncoder, schema config.SchemaConfig) Client {
+	return &client{
+		store:               store,
+		keyEncoder:          encoder,
+		getChunkMaxParallel: defaultMaxParallel,
+		schema:              schema,
+		deleteBlooms:        true,
+	}
+}
+
 // Stop shuts down the object store and any underlying clients
 func (o *client) Stop() {
 	o.store.Stop()
@@ -116,15 +135,18 @@ func (o *client) PutChunks(ctx context.Context, chunks []chunk.Chunk) error {
 			return err
 		}
 
-		var key string
-		if o.keyEncoder != nil {
-			key = o.keyEncoder(o.schema, chunks[i])
-		} else {
-			key = o.schema.ExternalKey(chunks[i].ChunkRef)
-		}
-
+		key := o.chunkKey(chunks[i])
 		chunkKeys = append(chunkKeys, key)
 		chunkBufs = append(chunkBufs, buf)
+
+		if chunks[i].Bloom != nil {
+			buf, err := chunks[i].Bloom.Encode()
+			if err != nil {
+				return err
+			}
+			chunkKeys = append(chunkKeys, key+bloomKeySuffix)
+			chunkBufs = append(chunkBufs, buf)
+		}
 	}
 
 	incomingErrors := make(chan error)
@@ -158,12 +180,7 @@ func (o *client) getChunk(ctx context.Context, decodeContext *chunk.DecodeContex
 		return chunk.Chunk{}, ctx.Err()
 	}
 
-	key := o.schema.ExternalKey(c.ChunkRef)
-	if o.keyEncoder != nil {
-		key = o.keyEncoder(o.schema, c)
-	}
-
-	readCloser, size, err := o.store.GetObject(ctx, key)
+	readCloser, size, err := o.store.GetObject(ctx, o.chunkKey(c))
 	if err != nil {
 		return chunk.Chunk{}, errors.WithStack(err)
 	}
@@ -187,7 +204,38 @@ func (o *client) getChunk(ctx context.Context, decodeContext *chunk.DecodeContex
 	return c, nil
 }
 
-// GetChunks retrieves the specified chunks from the configured backend
+// GetBlooms retrieves the bloom filters stored alongside the specified chunks.
+func (o *client) GetBlooms(ctx context.Context, chunks []chunk.Chunk) ([]*bloom.Filter, error) {
+	getChunkMaxParallel := o.getChunkMaxParallel
+	if getChunkMaxParallel == 0 {
+		getChunkMaxParallel = defaultMaxParallel
+	}
+	blooms := make([]*bloom.Filter, len(chunks))
+	err := concurrency.ForEachJob(ctx, len(chunks), getChunkMaxParallel, func(ctx context.Context, i int) error {
+		readCloser, _, err := o.store.GetObject(ctx, o.chunkKey(chunks[i])+bloomKeySuffix)
+		if err != nil {
+			if o.store.IsObjectNotFoundErr(err) {
+				return nil
+			}
+			return errors.WithStack(err)
+		}
+		defer readCloser.Close()
+
+		buf, err := io.ReadAll(readCloser)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		blooms[i], err = bloom.Decode(buf)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return blooms, nil
+}
+
+// DeleteChunk deletes the specified chunk from the configured backend, along with its bloom filter
+// if the client was created with NewClientWithBloomDeletes.
 func (o *client) DeleteChunk(ctx context.Context, userID, chunkID string) error {
 	key := chunkID
 	if o.keyEncoder != nil {
@@ -197,9 +245,25 @@ func (o *client) DeleteChunk(ctx context.Context, userID, chunkID string) error
 		}
 		key = o.keyEncoder(o.schema, c)
 	}
-	return o.store.DeleteObject(ctx, key)
+	if err := o.store.DeleteObject(ctx, key); err != nil {
+		return err
+	}
+	if !o.deleteBlooms {
+		return nil
+	}
+	if err := o.store.DeleteObject(ctx, key+bloomKeySuffix); err != nil && !o.store.IsObjectNotFoundErr(err) {
+		return err
+	}
+	return nil
 }
 
 func (o *client) IsChunkNotFoundErr(err error) bool {
 	return o.store.IsObjectNotFoundErr(err)
 }
+
+func (o *client) chunkKey(c chunk.Chunk) string {
+	if o.keyEncoder != nil {
+		return o.keyEncoder(o.schema, c)
+	}
+	return o.schema.ExternalKey(c.ChunkRef)
+}
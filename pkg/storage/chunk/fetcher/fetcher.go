@@ -5,6 +5,7 @@ import (
 	"errors"
 	"sync"
 
+	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
@@ -12,6 +13,7 @@ import (
 
 	"github.com/grafana/loki/pkg/logqlmodel/stats"
 	"github.com/grafana/loki/pkg/storage/chunk"
+	"github.com/grafana/loki/pkg/storage/chunk/bloom"
 	"github.com/grafana/loki/pkg/storage/chunk/cache"
 	"github.com/grafana/loki/pkg/storage/chunk/client"
 	"github.com/grafana/loki/pkg/storage/config"
@@ -40,7 +42,12 @@ var (
 	})
 )
 
-const chunkDecodeParallelism = 16
+const (
+	chunkDecodeParallelism = 16
+
+	// bloomCacheKeySuffix is appended to the cache key of a chunk to get the cache key of its bloom filter.
+	bloomCacheKeySuffix = ".bloom"
+)
 
 // Fetcher deals with fetching chunk contents from the cache/store,
 // and writing back any misses to the cache.  Also responsible for decoding
@@ -211,6 +218,116 @@ func (c *Fetcher) FetchChunks(ctx context.Context, chunks []chunk.Chunk, keys []
 	return allChunks, nil
 }
 
+// FetchBlooms fetches the bloom filters stored alongside the chunks, in the same order.
+// The filter of a chunk is nil if it was stored without one, or if the storage doesn't support them.
+// The filters are cached in the chunk cache, including the absence of a filter, so that they're read only once from the storage.
+func (c *Fetcher) FetchBlooms(ctx context.Context, chunks []chunk.Chunk) ([]*bloom.Filter, error) {
+	bc, ok := c.storage.(client.BloomClient)
+	if !ok {
+		return make([]*bloom.Filter, len(chunks)), nil
+	}
+	log, ctx := spanlogger.New(ctx, "ChunkStore.FetchBlooms")
+	defer log.Span.Finish()
+
+	blooms := make([]*bloom.Filter, len(chunks))
+	missing := make([]int, 0, len(chunks))
+	if c.cacheStubs {
+		// The stubs don't tell apart a chunk without a filter from a cached filter.
+		for i := range chunks {
+			missing = append(missing, i)
+		}
+	} else {
+		missing = c.fetchBloomsFromCache(ctx, log, chunks, blooms)
+	}
+	if len(missing) == 0 {
+		return blooms, nil
+	}
+
+	missingChunks := make([]chunk.Chunk, 0, len(missing))
+	for _, i := range missing {
+		missingChunks = append(missingChunks, chunks[i])
+	}
+	fromStorage, err := bc.GetBlooms(ctx, missingChunks)
+	if err != nil {
+		// Don't rely on Cortex error translation here.
+		return nil, promql.ErrStorage{Err: err}
+	}
+	for j, i := range missing {
+		blooms[i] = fromStorage[j]
+	}
+
+	if !c.cacheStubs {
+		c.writeBackBloomsCache(ctx, log, missingChunks, fromStorage)
+	}
+	return blooms, nil
+}
+
+// fetchBloomsFromCache sets the filters of the chunks found in the cache, and returns the indexes of the chunks which aren't.
+func (c *Fetcher) fetchBloomsFromCache(ctx context.Context, logger log.Logger, chunks []chunk.Chunk, blooms []*bloom.Filter) []int {
+	keys := make([]string, 0, len(chunks))
+	indexes := make(map[string]int, len(chunks))
+	for i := range chunks {
+		key := c.bloomCacheKey(chunks[i])
+		keys = append(keys, key)
+		indexes[key] = i
+	}
+
+	found, bufs, _, err := c.cache.Fetch(ctx, keys)
+	if err != nil {
+		level.Warn(logger).Log("msg", "error fetching bloom filters from cache", "err", err)
+	}
+	cached := make([]bool, len(chunks))
+	for j, key := range found {
+		i, ok := indexes[key]
+		if !ok {
+			continue
+		}
+		// An empty value is cached for the chunks stored without a filter.
+		if len(bufs[j]) > 0 {
+			b, err := bloom.Decode(bufs[j])
+			if err != nil {
+				level.Warn(logger).Log("msg", "error decoding bloom filter from cache", "err", err)
+				continue
+			}
+			blooms[i] = b
+		}
+		cached[i] = true
+	}
+
+	var missing []int
+	for i := range chunks {
+		if !cached[i] {
+			missing = append(missing, i)
+		}
+	}
+	return missing
+}
+
+func (c *Fetcher) writeBackBloomsCache(ctx context.Context, logger log.Logger, chunks []chunk.Chunk, blooms []*bloom.Filter) {
+	keys := make([]string, 0, len(chunks))
+	bufs := make([][]byte, 0, len(chunks))
+	for i := range chunks {
+		var buf []byte
+		if blooms[i] != nil {
+			var err error
+			buf, err = blooms[i].Encode()
+			if err != nil {
+				level.Warn(logger).Log("msg", "error encoding bloom filter", "err", err)
+				continue
+			}
+		}
+		keys = append(keys, c.bloomCacheKey(chunks[i]))
+		bufs = append(bufs, buf)
+	}
+	if err := c.cache.Store(ctx, keys, bufs); err != nil {
+		level.Warn(logger).Log("msg", "could not store bloom filters in chunk cache", "err", err)
+	}
+}
+
+func (c *Fetcher) bloomCacheKey(chk chunk.Chunk) string {
+	return c.schema.ExternalKey(chk.ChunkRef) + bloomCacheKeySuffix
+}
+
 func (c *Fetcher) WriteBackCache(ctx context.Context, chunks []chunk.Chunk) error {
 	keys := make([]string, 0, len(chunks))
 	bufs := make([][]byte, 0, len(chunks))
@@ -227,6 +344,15 @@ func (c *Fetcher) WriteBackCache(ctx context.Context, chunks []chunk.Chunk) erro
 
 		keys = append(keys, c.schema.ExternalKey(chunks[i].ChunkRef))
 		bufs = append(bufs, encoded)
+
+		if chunks[i].Bloom != nil && !c.cacheStubs {
+			encoded, err := chunks[i].Bloom.Encode()
+			if err != nil {
+				return err
+			}
+			keys = append(keys, c.bloomCacheKey(chunks[i]))
+			bufs = append(bufs, encoded)
+		}
 	}
 
 	err := c.cache.Store(ctx, keys, bufs)
@@ -0,0 +1,88 @@
+package fetcher
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/storage/chunk"
+	"github.com/grafana/loki/pkg/storage/chunk/bloom"
+	"github.com/grafana/loki/pkg/storage/chunk/cache"
+	"github.com/grafana/loki/pkg/storage/config"
+)
+
+type mockBloomClient struct {
+	blooms    map[string]*bloom.Filter
+	schema    config.SchemaConfig
+	requested []int
+}
+
+func (m *mockBloomClient) Stop() {}
+
+func (m *mockBloomClient) PutChunks(_ context.Context, _ []chunk.Chunk) error {
+	return nil
+}
+
+func (m *mockBloomClient) GetChunks(_ context.Context, chunks []chunk.Chunk) ([]chunk.Chunk, error) {
+	return chunks, nil
+}
+
+func (m *mockBloomClient) GetBlooms(_ context.Context, chunks []chunk.Chunk) ([]*bloom.Filter, error) {
+	m.requested = append(m.requested, len(chunks))
+	res := make([]*bloom.Filter, 0, len(chunks))
+	for _, c := range chunks {
+		res = append(res, m.blooms[m.schema.ExternalKey(c.ChunkRef)])
+	}
+	return res, nil
+}
+
+func (m *mockBloomClient) DeleteChunk(_ context.Context, _, _ string) error {
+	return nil
+}
+
+func (m *mockBloomClient) IsChunkNotFoundErr(_ error) bool {
+	return false
+}
+
+func TestFetcher_FetchBlooms(t *testing.T) {
+	var schema config.SchemaConfig
+	chunks := []chunk.Chunk{
+		{ChunkRef: logproto.ChunkRef{UserID: "fake", Fingerprint: 1, From: 1, Through: 2, Checksum: 1}},
+		{ChunkRef: logproto.ChunkRef{UserID: "fake", Fingerprint: 2, From: 1, Through: 2, Checksum: 2}},
+	}
+	b := bloom.NewBuilder()
+	b.Add("traceID=abc123")
+
+	for _, tc := range []struct {
+		name       string
+		cacheStubs bool
+		requested  []int
+	}{
+		// the filters, and the absence of a filter of the second chunk, are read from the cache the second time.
+		{"cached", false, []int{2}},
+		{"cache stubs", true, []int{2, 2}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			client := &mockBloomClient{
+				blooms: map[string]*bloom.Filter{schema.ExternalKey(chunks[0].ChunkRef): b.Build()},
+				schema: schema,
+			}
+			f, err := New(cache.NewMockCache(), tc.cacheStubs, schema, client, 1, 10)
+			require.NoError(t, err)
+			defer f.Stop()
+
+			for i := 0; i < 2; i++ {
+				blooms, err := f.FetchBlooms(context.Background(), chunks)
+				require.NoError(t, err)
+				require.Len(t, blooms, 2)
+				require.NotNil(t, blooms[0])
+				require.True(t, blooms[0].MayContain("abc123"))
+				require.False(t, blooms[0].MayContain("def456"))
+				require.Nil(t, blooms[1])
+			}
+			require.Equal(t, tc.requested, client.requested)
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/go-kit/log/level"
+
+	"github.com/grafana/loki/pkg/logql/log"
+	"github.com/grafana/loki/pkg/logql/syntax"
+	"github.com/grafana/loki/pkg/storage/chunk"
+	"github.com/grafana/loki/pkg/storage/chunk/bloom"
+	"github.com/grafana/loki/pkg/storage/chunk/fetcher"
+	util_log "github.com/grafana/loki/pkg/util/log"
+)
+
+// requiredLineSubstrings returns the substrings which every line selected by the expression contains,
+// those are the arguments of the `|=` line filters applied before the line is modified.
+func requiredLineSubstrings(expr syntax.LogSelectorExpr) []string {
+	p, ok := expr.(*syntax.PipelineExpr)
+	if !ok {
+		return nil
+	}
+	var res []string
+	for _, stage := range p.MultiStages {
+		switch st := stage.(type) {
+		case *syntax.LineFilterExpr:
+			for f := st; f != nil; f = f.Left {
+				if f.Ty == log.LineMatchEqual && f.Op == "" && len(f.Match) >= bloom.NGramSize {
+					res = append(res, f.Match)
+				}
+			}
+		case *syntax.LineFmtExpr, *syntax.DecolorizeExpr:
+			// the following filters don't apply to the stored line.
+			return res
+		}
+	}
+	return res
+}
+
+// filterChunksByBloom removes the chunks whose bloom filter tells that none of their lines contain one of the substrings.
+// Chunks stored without a bloom filter are kept, as well as all chunks if the filters can't be fetched.
+// The filters are fetched by batches of batchSize chunks.
+func filterChunksByBloom(ctx context.Context, chunks []*LazyChunk, substrings []string, batchSize int, metrics *ChunkMetrics) []*LazyChunk {
+	if len(substrings) == 0 {
+		return chunks
+	}
+	logger := util_log.WithContext(ctx, util_log.Logger)
+
+	chksByFetcher := map[*fetcher.Fetcher][]*LazyChunk{}
+	for _, c := range chunks {
+		chksByFetcher[c.Fetcher] = append(chksByFetcher[c.Fetcher], c)
+	}
+
+	res := make([]*LazyChunk, 0, len(chunks))
+	for f, fetcherChunks := range chksByFetcher {
+		for len(fetcherChunks) > 0 {
+			lazyChunks := fetcherChunks
+			if batchSize > 0 && len(lazyChunks) > batchSize {
+				lazyChunks = lazyChunks[:batchSize]
+			}
+			fetcherChunks = fetcherChunks[len(lazyChunks):]
+
+			chks := make([]chunk.Chunk, 0, len(lazyChunks))
+			for _, c := range lazyChunks {
+				chks = append(chks, c.Chunk)
+			}
+			blooms, err := f.FetchBlooms(ctx, chks)
+			if err != nil {
+				level.Warn(logger).Log("msg", "error fetching chunk bloom filters, skipping bloom filtering", "err", err)
+				res = append(res, lazyChunks...)
+				continue
+			}
+			for i, b := range blooms {
+				if b == nil || mayContainAll(b, substrings) {
+					res = append(res, lazyChunks[i])
+				}
+			}
+		}
+	}
+	metrics.chunks.WithLabelValues(statusDiscarded).Add(float64(len(chunks) - len(res)))
+	level.Debug(logger).Log("msg", "filtered chunks by bloom filters", "chunks", len(chunks), "filtered", len(chunks)-len(res))
+	return res
+}
+
+func mayContainAll(b *bloom.Filter, substrings []string) bool {
+	for _, s := range substrings {
+		if !b.MayContain(s) {
+			return false
+		}
+	}
+	return true
+}
@@ -454,6 +454,10 @@ func (s *store) SelectLogs(ctx context.Context, req logql.SelectLogParams) (iter
 		return nil, err
 	}
 
+	if s.cfg.ChunkBloomFilters {
+		lazyChunks = filterChunksByBloom(ctx, lazyChunks, requiredLineSubstrings(expr), s.cfg.MaxChunkBatchSize, s.chunkMetrics)
+	}
+
 	pipeline, err := expr.Pipeline()
 	if err != nil {
 		return nil, err
@@ -492,6 +496,10 @@ func (s *store) SelectSamples(ctx context.Context, req logql.SelectSampleParams)
 		return nil, err
 	}
 
+	if s.cfg.ChunkBloomFilters {
+		lazyChunks = filterChunksByBloom(ctx, lazyChunks, requiredLineSubstrings(expr.Selector()), s.cfg.MaxChunkBatchSize, s.chunkMetrics)
+	}
+
 	extractor, err := expr.Extractor()
 	if err != nil {
 		return nil, err
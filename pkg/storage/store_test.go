@@ -24,6 +24,7 @@ import (
 	"github.com/grafana/loki/pkg/logql"
 	"github.com/grafana/loki/pkg/querier/astmapper"
 	"github.com/grafana/loki/pkg/storage/chunk"
+	"github.com/grafana/loki/pkg/storage/chunk/bloom"
 	"github.com/grafana/loki/pkg/storage/chunk/client/local"
 	"github.com/grafana/loki/pkg/storage/config"
 	"github.com/grafana/loki/pkg/storage/stores/indexshipper"
@@ -1137,6 +1138,79 @@ func timeToModelTime(t time.Time) model.Time {
 	return model.TimeFromUnixNano(t.UnixNano())
 }
 
+func Test_ChunkBloomFilters(t *testing.T) {
+	newBloom := func(lines ...string) *bloom.Filter {
+		b := bloom.NewBuilder()
+		for _, l := range lines {
+			b.Add(l)
+		}
+		return b.Build()
+	}
+	chunks := []chunk.Chunk{
+		newChunk(logproto.Stream{
+			Labels:  `{foo="bar"}`,
+			Entries: []logproto.Entry{{Timestamp: time.Unix(0, 1), Line: "traceID=abc123"}},
+		}),
+		newChunk(logproto.Stream{
+			Labels:  `{foo="buzz"}`,
+			Entries: []logproto.Entry{{Timestamp: time.Unix(0, 2), Line: "traceID=abc123"}},
+		}),
+		newChunk(logproto.Stream{
+			Labels:  `{foo="fizz"}`,
+			Entries: []logproto.Entry{{Timestamp: time.Unix(0, 3), Line: "traceID=abc123"}},
+		}),
+	}
+	var schemas config.SchemaConfig
+	blooms := map[string]*bloom.Filter{
+		schemas.ExternalKey(chunks[0].ChunkRef): newBloom("traceID=abc123"),
+		// the bloom filter of the second chunk doesn't match its lines to tell if it has been used.
+		schemas.ExternalKey(chunks[1].ChunkRef): newBloom("traceID=def456"),
+		// the third chunk has no bloom filter.
+	}
+
+	for _, tc := range []struct {
+		name    string
+		enabled bool
+		query   string
+		streams []string
+	}{
+		{"disabled", false, `{foo=~".+"} |= "abc123"`, []string{`{foo="bar"}`, `{foo="buzz"}`, `{foo="fizz"}`}},
+		{"enabled", true, `{foo=~".+"} |= "abc123"`, []string{`{foo="bar"}`, `{foo="fizz"}`}},
+		{"short filter", true, `{foo=~".+"} |= "abc"`, []string{`{foo="bar"}`, `{foo="buzz"}`, `{foo="fizz"}`}},
+		{"after line_format", true, `{foo=~".+"} | line_format "{{.foo}} abc123" |= "abc123"`, []string{`{foo="bar"}`, `{foo="buzz"}`, `{foo="fizz"}`}},
+		{"regexp filter", true, `{foo=~".+"} |~ "abc123"`, []string{`{foo="bar"}`, `{foo="buzz"}`, `{foo="fizz"}`}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &store{
+				Store: &mockChunkStore{chunks: chunks, client: &mockChunkStoreClient{chunks: chunks, blooms: blooms}},
+				cfg: Config{
+					// the bloom filters are fetched by batches.
+					MaxChunkBatchSize: 2,
+					ChunkBloomFilters: tc.enabled,
+				},
+				chunkMetrics: NilMetrics,
+			}
+			ctx := user.InjectOrgID(context.Background(), "test-user")
+			it, err := s.SelectLogs(ctx, logql.SelectLogParams{QueryRequest: &logproto.QueryRequest{
+				Selector:  tc.query,
+				Limit:     1000,
+				Direction: logproto.FORWARD,
+				Start:     time.Unix(0, 0),
+				End:       time.Unix(0, 10),
+			}})
+			require.NoError(t, err)
+			defer it.Close()
+
+			var streams []string
+			for it.Next() {
+				streams = append(streams, it.Labels())
+			}
+			require.NoError(t, it.Error())
+			require.Equal(t, tc.streams, streams)
+		})
+	}
+}
+
 func Test_OverlappingChunks(t *testing.T) {
 	chunks := []chunk.Chunk{
 		newChunk(logproto.Stream{
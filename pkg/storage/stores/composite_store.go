@@ -27,6 +27,7 @@ type Store interface {
 	GetChunkFetcher(tm model.Time) *fetcher.Fetcher
 	SetChunkFilterer(chunkFilter chunk.RequestChunkFilterer)
 	Stats(ctx context.Context, userID string, from, through model.Time, matchers ...*labels.Matcher) (*stats.Stats, error)
+	Volume(ctx context.Context, userID string, from, through model.Time, targetLabels []string, matchers ...*labels.Matcher) (*stats.Volumes, error)
 	Stop()
 }
 
@@ -179,6 +180,21 @@ func (c compositeStore) Stats(ctx context.Context, userID string, from, through
 	return &res, err
 }
 
+func (c compositeStore) Volume(ctx context.Context, userID string, from, through model.Time, targetLabels []string, matchers ...*labels.Matcher) (*stats.Volumes, error) {
+	xs := make([]*stats.Volumes, 0, len(c.stores))
+	err := c.forStores(ctx, from, through, func(innerCtx context.Context, from, through model.Time, store Store) error {
+		x, err := store.Volume(innerCtx, userID, from, through, targetLabels, matchers...)
+		xs = append(xs, x)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	res := stats.MergeVolumes(0, xs...)
+	return &res, err
+}
+
 func (c compositeStore) GetChunkFetcher(tm model.Time) *fetcher.Fetcher {
 	// find the schema with the lowest start _after_ tm
 	j := sort.Search(len(c.stores), func(j int) bool {
@@ -52,6 +52,10 @@ func (m mockStore) Stats(ctx context.Context, userID string, from, through model
 	return nil, nil
 }
 
+func (m mockStore) Volume(ctx context.Context, userID string, from, through model.Time, targetLabels []string, matchers ...*labels.Matcher) (*stats.Volumes, error) {
+	return nil, nil
+}
+
 func (m mockStore) Stop() {}
 
 func TestCompositeStore(t *testing.T) {
@@ -19,6 +19,7 @@ type Reader interface {
 	LabelValuesForMetricName(ctx context.Context, userID string, from, through model.Time, metricName string, labelName string, matchers ...*labels.Matcher) ([]string, error)
 	LabelNamesForMetricName(ctx context.Context, userID string, from, through model.Time, metricName string) ([]string, error)
 	Stats(ctx context.Context, userID string, from, through model.Time, matchers ...*labels.Matcher) (*stats.Stats, error)
+	Volume(ctx context.Context, userID string, from, through model.Time, targetLabels []string, matchers ...*labels.Matcher) (*stats.Volumes, error)
 	// SetChunkFilterer sets a chunk filter to be used when retrieving chunks.
 	// This is only used for GetSeries implementation.
 	// Todo we might want to pass it as a parameter to GetSeries instead.
@@ -113,6 +114,19 @@ func (m monitoredReaderWriter) Stats(ctx context.Context, userID string, from, t
 
 }
 
+func (m monitoredReaderWriter) Volume(ctx context.Context, userID string, from, through model.Time, targetLabels []string, matchers ...*labels.Matcher) (*stats.Volumes, error) {
+	var vols *stats.Volumes
+	if err := instrument.CollectedRequest(ctx, "volume", instrument.NewHistogramCollector(m.metrics.indexQueryLatency), instrument.ErrorCode, func(ctx context.Context) error {
+		var err error
+		vols, err = m.rw.Volume(ctx, userID, from, through, targetLabels, matchers...)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return vols, nil
+}
+
 func (m monitoredReaderWriter) SetChunkFilterer(chunkFilter chunk.RequestChunkFilterer) {
 	m.rw.SetChunkFilterer(chunkFilter)
 }
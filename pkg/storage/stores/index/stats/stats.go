@@ -44,6 +44,9 @@ func (p *PoolBloom) Get() *Blooms {
 
 func (p *PoolBloom) Put(x *Blooms) {
 	x.Streams.ClearAll()
+	if x.Chunks != nil {
+		x.Chunks.ClearAll()
+	}
 	x.stats = &Stats{}
 	p.pool.Put(x)
 }
@@ -83,7 +86,9 @@ func newBlooms() *Blooms {
 type Blooms struct {
 	sync.RWMutex
 	Streams *bloom.BloomFilter
-	stats   *Stats
+	// Chunks is only allocated for the accumulators deduplicating chunks, see NewVolumeAccumulator.
+	Chunks *bloom.BloomFilter
+	stats  *Stats
 }
 
 func (b *Blooms) Stats() Stats { return b.stats.Stats() }
@@ -100,6 +105,17 @@ func (b *Blooms) AddChunk(fp model.Fingerprint, chk index.ChunkMeta) {
 	b.stats.AddChunk(fp, chk)
 }
 
+// addChunkOnce calls update unless the chunk was already added, e.g. from the index of another ingester
+// which hasn't been compacted yet.
+func (b *Blooms) addChunkOnce(fp model.Fingerprint, chk index.ChunkMeta, update func()) {
+	key := make([]byte, 28)
+	binary.BigEndian.PutUint64(key, uint64(fp))
+	binary.BigEndian.PutUint64(key[8:], uint64(chk.MinTime))
+	binary.BigEndian.PutUint64(key[16:], uint64(chk.MaxTime))
+	binary.BigEndian.PutUint32(key[24:], chk.Checksum)
+	b.add(b.Chunks, key, update)
+}
+
 func (b *Blooms) add(filter *bloom.BloomFilter, key []byte, update func()) {
 	b.RLock()
 	ok := filter.Test(key)
@@ -0,0 +1,228 @@
+package stats
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/willf/bloom"
+
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/storage/stores/tsdb/index"
+)
+
+type Volumes = logproto.VolumeResponse
+
+// VolumeAccumulator aggregates the volume of the streams by the values of the target labels.
+// Streams seen several times, e.g. from multiple TSDB files, are only counted once.
+// Safe for concurrent use.
+type VolumeAccumulator struct {
+	targetLabels []string
+	blooms       *Blooms
+
+	mtx     sync.Mutex
+	streams map[model.Fingerprint]*logproto.StreamVolume
+}
+
+// NewVolumeAccumulator returns an accumulator of the volumes by the values of the target labels.
+// When blooms is not nil, the chunks added with AddChunk are deduplicated with its bloom filter,
+// since the same chunk is indexed by each replica until the tables are compacted.
+func NewVolumeAccumulator(targetLabels []string, blooms *Blooms) *VolumeAccumulator {
+	if blooms != nil && blooms.Chunks == nil {
+		// 10 million chunks @ 1% error =~ 11.4MB
+		blooms.Chunks = bloom.NewWithEstimates(1e7, 0.01)
+	}
+	return &VolumeAccumulator{
+		targetLabels: targetLabels,
+		blooms:       blooms,
+		streams:      map[model.Fingerprint]*logproto.StreamVolume{},
+	}
+}
+
+// AddStream adds a stream to the volume of the label set its target labels belong to.
+func (a *VolumeAccumulator) AddStream(ls labels.Labels, fp model.Fingerprint) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	if _, ok := a.streams[fp]; ok {
+		return
+	}
+	a.streams[fp] = &logproto.StreamVolume{
+		Fingerprint: uint64(fp),
+		Name:        VolumeName(ls, a.targetLabels),
+	}
+}
+
+// AddChunk adds a chunk of a stream previously added with AddStream.
+func (a *VolumeAccumulator) AddChunk(fp model.Fingerprint, chk index.ChunkMeta) {
+	if a.blooms == nil {
+		a.AddChunkData(fp, uint64(chk.KB<<10), uint64(chk.Entries))
+		return
+	}
+	a.blooms.addChunkOnce(fp, chk, func() {
+		a.AddChunkData(fp, uint64(chk.KB<<10), uint64(chk.Entries))
+	})
+}
+
+// AddChunkData adds the size and the number of entries of a chunk of a stream previously added with AddStream.
+func (a *VolumeAccumulator) AddChunkData(fp model.Fingerprint, bytes, entries uint64) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	stream, ok := a.streams[fp]
+	if !ok {
+		return
+	}
+	stream.Chunks++
+	stream.Bytes += bytes
+	stream.Entries += entries
+}
+
+// Volumes returns the volumes by label set, along with the volume of each stream.
+func (a *VolumeAccumulator) Volumes() Volumes {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	streams := make([]logproto.StreamVolume, 0, len(a.streams))
+	for _, stream := range a.streams {
+		streams = append(streams, *stream)
+	}
+	return MergeVolumes(0, &Volumes{Streams: streams})
+}
+
+// VolumeName returns the name of the label set the volume of a stream is aggregated into,
+// which only keeps the target labels of the stream.
+func VolumeName(ls labels.Labels, targetLabels []string) string {
+	b := labels.NewBuilder(nil)
+	for _, name := range targetLabels {
+		if v := ls.Get(name); v != "" {
+			b.Set(name, v)
+		}
+	}
+	return b.Labels(nil).String()
+}
+
+// VolumeTargetLabels returns the labels to aggregate the volumes of a query by:
+// the requested target labels if any, the labels of the matchers otherwise.
+func VolumeTargetLabels(targetLabels []string, matchers []*labels.Matcher) []string {
+	if len(targetLabels) > 0 {
+		return targetLabels
+	}
+
+	seen := map[string]struct{}{}
+	for _, m := range matchers {
+		if _, ok := seen[m.Name]; ok || m.Name == labels.MetricName {
+			continue
+		}
+		seen[m.Name] = struct{}{}
+		targetLabels = append(targetLabels, m.Name)
+	}
+	sort.Strings(targetLabels)
+	return targetLabels
+}
+
+// MergeVolumes merges the volumes of disjoint sets of chunks, e.g. from the ingesters and the store
+// or from different time ranges: the volumes of a same stream are summed, and each stream is counted once.
+// At most limit volumes are returned if limit is positive, along with the volumes of all the streams.
+func MergeVolumes(limit int, xs ...*Volumes) Volumes {
+	return mergeVolumes(limit, xs, func(dst *logproto.StreamVolume, src logproto.StreamVolume) {
+		dst.Chunks += src.Chunks
+		dst.Bytes += src.Bytes
+		dst.Entries += src.Entries
+	})
+}
+
+// MergeReplicaVolumes merges the volumes returned by several replicas of the same streams, e.g. the ingesters:
+// the largest volume of each stream is kept, and each stream is counted once.
+// At most limit volumes are returned if limit is positive, along with the volumes of all the streams.
+func MergeReplicaVolumes(limit int, xs ...*Volumes) Volumes {
+	return mergeVolumes(limit, xs, func(dst *logproto.StreamVolume, src logproto.StreamVolume) {
+		if src.Bytes > dst.Bytes {
+			dst.Chunks, dst.Bytes, dst.Entries = src.Chunks, src.Bytes, src.Entries
+		}
+	})
+}
+
+type streamVolumeKey struct {
+	fingerprint uint64
+	name        string
+}
+
+// mergeVolumes merges the volumes of the same streams with merge, and aggregates them by label set.
+// The volumes of the responses without the volumes of their streams, e.g. from older ingesters, are summed by label set.
+func mergeVolumes(limit int, xs []*Volumes, merge func(dst *logproto.StreamVolume, src logproto.StreamVolume)) (v Volumes) {
+	streams := map[streamVolumeKey]*logproto.StreamVolume{}
+	merged := map[string]*logproto.Volume{}
+	for _, x := range xs {
+		if x == nil {
+			continue
+		}
+		if len(x.Streams) == 0 {
+			for _, volume := range x.Volumes {
+				addVolume(merged, volume)
+			}
+			continue
+		}
+		for _, stream := range x.Streams {
+			key := streamVolumeKey{fingerprint: stream.Fingerprint, name: stream.Name}
+			m, ok := streams[key]
+			if !ok {
+				m = &logproto.StreamVolume{Fingerprint: stream.Fingerprint, Name: stream.Name}
+				streams[key] = m
+			}
+			merge(m, stream)
+		}
+	}
+
+	if len(streams) > 0 {
+		v.Streams = make([]logproto.StreamVolume, 0, len(streams))
+		for _, stream := range streams {
+			v.Streams = append(v.Streams, *stream)
+			addVolume(merged, logproto.Volume{
+				Name:    stream.Name,
+				Streams: 1,
+				Chunks:  stream.Chunks,
+				Bytes:   stream.Bytes,
+				Entries: stream.Entries,
+			})
+		}
+		sort.Slice(v.Streams, func(i, j int) bool {
+			if v.Streams[i].Fingerprint != v.Streams[j].Fingerprint {
+				return v.Streams[i].Fingerprint < v.Streams[j].Fingerprint
+			}
+			return v.Streams[i].Name < v.Streams[j].Name
+		})
+	}
+
+	v.Volumes = make([]logproto.Volume, 0, len(merged))
+	for _, volume := range merged {
+		v.Volumes = append(v.Volumes, *volume)
+	}
+	sortVolumes(v.Volumes)
+	if limit > 0 && len(v.Volumes) > limit {
+		v.Volumes = v.Volumes[:limit]
+	}
+	return v
+}
+
+func addVolume(volumes map[string]*logproto.Volume, volume logproto.Volume) {
+	m, ok := volumes[volume.Name]
+	if !ok {
+		m = &logproto.Volume{Name: volume.Name}
+		volumes[volume.Name] = m
+	}
+	m.Streams += volume.Streams
+	m.Chunks += volume.Chunks
+	m.Bytes += volume.Bytes
+	m.Entries += volume.Entries
+}
+
+func sortVolumes(volumes []logproto.Volume) {
+	sort.Slice(volumes, func(i, j int) bool {
+		if volumes[i].Bytes != volumes[j].Bytes {
+			return volumes[i].Bytes > volumes[j].Bytes
+		}
+		return volumes[i].Name < volumes[j].Name
+	})
+}
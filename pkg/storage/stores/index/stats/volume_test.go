@@ -0,0 +1,117 @@
+package stats
+
+import (
+	"testing"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/storage/stores/tsdb/index"
+)
+
+func TestVolumeAccumulator(t *testing.T) {
+	acc := NewVolumeAccumulator([]string{"namespace"}, nil)
+
+	acc.AddStream(labels.FromStrings("namespace", "loki", "app", "distributor"), 1)
+	acc.AddChunk(1, index.ChunkMeta{KB: 1, Entries: 10})
+	acc.AddChunk(1, index.ChunkMeta{KB: 2, Entries: 20})
+	acc.AddStream(labels.FromStrings("namespace", "loki", "app", "ingester"), 2)
+	acc.AddChunkData(2, 100, 1)
+	acc.AddStream(labels.FromStrings("namespace", "mimir", "app", "ingester"), 3)
+	acc.AddChunk(3, index.ChunkMeta{KB: 4, Entries: 40})
+	acc.AddStream(labels.FromStrings("app", "grafana"), 4)
+
+	// streams are only counted once.
+	acc.AddStream(labels.FromStrings("namespace", "loki", "app", "ingester"), 2)
+	// chunks of unknown streams are ignored.
+	acc.AddChunk(5, index.ChunkMeta{KB: 1, Entries: 1})
+
+	require.Equal(t, Volumes{
+		Volumes: []logproto.Volume{
+			{Name: `{namespace="mimir"}`, Streams: 1, Chunks: 1, Bytes: 4 << 10, Entries: 40},
+			{Name: `{namespace="loki"}`, Streams: 2, Chunks: 3, Bytes: 3<<10 + 100, Entries: 31},
+			{Name: `{}`, Streams: 1},
+		},
+		Streams: []logproto.StreamVolume{
+			{Fingerprint: 1, Name: `{namespace="loki"}`, Chunks: 2, Bytes: 3 << 10, Entries: 30},
+			{Fingerprint: 2, Name: `{namespace="loki"}`, Chunks: 1, Bytes: 100, Entries: 1},
+			{Fingerprint: 3, Name: `{namespace="mimir"}`, Chunks: 1, Bytes: 4 << 10, Entries: 40},
+			{Fingerprint: 4, Name: `{}`},
+		},
+	}, acc.Volumes())
+}
+
+func TestMergeVolumes(t *testing.T) {
+	a := &Volumes{Volumes: []logproto.Volume{
+		{Name: `{app="foo"}`, Streams: 1, Chunks: 1, Bytes: 10, Entries: 1},
+		{Name: `{app="bar"}`, Streams: 1, Chunks: 1, Bytes: 20, Entries: 2},
+	}}
+	b := &Volumes{Volumes: []logproto.Volume{
+		{Name: `{app="foo"}`, Streams: 1, Chunks: 2, Bytes: 15, Entries: 3},
+		{Name: `{app="baz"}`, Streams: 1, Chunks: 1, Bytes: 5, Entries: 1},
+	}}
+
+	require.Equal(t, Volumes{Volumes: []logproto.Volume{
+		{Name: `{app="foo"}`, Streams: 2, Chunks: 3, Bytes: 25, Entries: 4},
+		{Name: `{app="bar"}`, Streams: 1, Chunks: 1, Bytes: 20, Entries: 2},
+		{Name: `{app="baz"}`, Streams: 1, Chunks: 1, Bytes: 5, Entries: 1},
+	}}, MergeVolumes(0, a, nil, b))
+
+	require.Equal(t, Volumes{Volumes: []logproto.Volume{
+		{Name: `{app="foo"}`, Streams: 2, Chunks: 3, Bytes: 25, Entries: 4},
+	}}, MergeVolumes(1, a, b))
+
+	require.Equal(t, Volumes{Volumes: []logproto.Volume{}}, MergeVolumes(0))
+}
+
+func TestMergeVolumesStreams(t *testing.T) {
+	ingester1 := &Volumes{Streams: []logproto.StreamVolume{
+		{Fingerprint: 1, Name: `{app="foo"}`, Chunks: 1, Bytes: 10, Entries: 1},
+		{Fingerprint: 2, Name: `{app="bar"}`, Chunks: 1, Bytes: 20, Entries: 2},
+	}}
+	ingester2 := &Volumes{Streams: []logproto.StreamVolume{
+		{Fingerprint: 1, Name: `{app="foo"}`, Chunks: 2, Bytes: 15, Entries: 3},
+		{Fingerprint: 2, Name: `{app="bar"}`, Chunks: 1, Bytes: 20, Entries: 2},
+	}}
+
+	// the replicas of a stream are only counted once.
+	ingesters := MergeReplicaVolumes(0, ingester1, nil, ingester2)
+	require.Equal(t, Volumes{
+		Volumes: []logproto.Volume{
+			{Name: `{app="bar"}`, Streams: 1, Chunks: 1, Bytes: 20, Entries: 2},
+			{Name: `{app="foo"}`, Streams: 1, Chunks: 2, Bytes: 15, Entries: 3},
+		},
+		Streams: []logproto.StreamVolume{
+			{Fingerprint: 1, Name: `{app="foo"}`, Chunks: 2, Bytes: 15, Entries: 3},
+			{Fingerprint: 2, Name: `{app="bar"}`, Chunks: 1, Bytes: 20, Entries: 2},
+		},
+	}, ingesters)
+
+	// a stream both in the ingesters and in the store is counted once, with the volume of both.
+	store := &Volumes{Streams: []logproto.StreamVolume{
+		{Fingerprint: 1, Name: `{app="foo"}`, Chunks: 1, Bytes: 100, Entries: 10},
+		{Fingerprint: 3, Name: `{app="foo"}`, Chunks: 1, Bytes: 5, Entries: 1},
+	}}
+	require.Equal(t, Volumes{
+		Volumes: []logproto.Volume{
+			{Name: `{app="foo"}`, Streams: 2, Chunks: 4, Bytes: 120, Entries: 14},
+		},
+		Streams: []logproto.StreamVolume{
+			{Fingerprint: 1, Name: `{app="foo"}`, Chunks: 3, Bytes: 115, Entries: 13},
+			{Fingerprint: 2, Name: `{app="bar"}`, Chunks: 1, Bytes: 20, Entries: 2},
+			{Fingerprint: 3, Name: `{app="foo"}`, Chunks: 1, Bytes: 5, Entries: 1},
+		},
+	}, MergeVolumes(1, &ingesters, store))
+}
+
+func TestVolumeTargetLabels(t *testing.T) {
+	matchers := []*labels.Matcher{
+		labels.MustNewMatcher(labels.MatchEqual, "namespace", "loki"),
+		labels.MustNewMatcher(labels.MatchRegexp, "app", ".+"),
+		labels.MustNewMatcher(labels.MatchNotEqual, "app", "ingester"),
+	}
+
+	require.Equal(t, []string{"cluster"}, VolumeTargetLabels([]string{"cluster"}, matchers))
+	require.Equal(t, []string{"app", "namespace"}, VolumeTargetLabels(nil, matchers))
+}
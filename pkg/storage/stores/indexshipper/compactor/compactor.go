@@ -84,12 +84,17 @@ type Config struct {
 	DeleteBatchSize           int             `yaml:"delete_batch_size"`
 	DeleteRequestCancelPeriod time.Duration   `yaml:"delete_request_cancel_period"`
 	DeleteMaxInterval         time.Duration   `yaml:"delete_max_interval"`
+	DeletePreviewMaxChunks    int             `yaml:"delete_preview_max_chunks"`
+	DeletePreviewMaxTables    int             `yaml:"delete_preview_max_tables"`
 	MaxCompactionParallelism  int             `yaml:"max_compaction_parallelism"`
 	UploadParallelism         int             `yaml:"upload_parallelism"`
 	CompactorRing             util.RingConfig `yaml:"compactor_ring,omitempty" doc:"description=The hash ring configuration used by compactors to elect a single instance for running compactions. The CLI flags prefix for this block config is: boltdb.shipper.compactor.ring"`
 	RunOnce                   bool            `yaml:"_" doc:"hidden"`
 	TablesToCompact           int             `yaml:"tables_to_compact"`
 	SkipLatestNTables         int             `yaml:"skip_latest_n_tables"`
+	// DeleteChunkBloomFilters is set when the ingesters write bloom filters alongside the chunks,
+	// which are then deleted with them.
+	DeleteChunkBloomFilters bool `yaml:"-"`
 
 	// Deprecated
 	DeletionMode string `yaml:"deletion_mode" doc:"deprecated|description=Use deletion_mode per tenant configuration instead."`
@@ -108,6 +113,8 @@ func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 	f.IntVar(&cfg.DeleteBatchSize, "boltdb.shipper.compactor.delete-batch-size", 70, "The max number of delete requests to run per compaction cycle.")
 	f.DurationVar(&cfg.DeleteRequestCancelPeriod, "boltdb.shipper.compactor.delete-request-cancel-period", 24*time.Hour, "Allow cancellation of delete request until duration after they are created. Data would be deleted only after delete requests have been older than this duration. Ideally this should be set to at least 24h.")
 	f.DurationVar(&cfg.DeleteMaxInterval, "boltdb.shipper.compactor.delete-max-interval", 0, "Constrain the size of any single delete request. When a delete request > delete_max_interval is input, the request is sharded into smaller requests of no more than delete_max_interval")
+	f.IntVar(&cfg.DeletePreviewMaxChunks, "boltdb.shipper.compactor.delete-preview-max-chunks", 1000, "The maximum number of chunks read to preview a delete request. The lines and bytes of the other matching chunks are estimated from the chunks read. 0 means no limit.")
+	f.IntVar(&cfg.DeletePreviewMaxTables, "boltdb.shipper.compactor.delete-preview-max-tables", 31, "The maximum number of index tables read to preview a delete request. Previews of delete requests spanning more tables are rejected. 0 means no limit.")
 	f.DurationVar(&cfg.RetentionTableTimeout, "boltdb.shipper.compactor.retention-table-timeout", 0, "The maximum amount of time to spend running retention and deletion on any given table in the index.")
 	f.IntVar(&cfg.MaxCompactionParallelism, "boltdb.shipper.compactor.max-compaction-parallelism", 1, "Maximum number of tables to compact in parallel. While increasing this value, please make sure compactor has enough disk space allocated to be able to store and compact as many tables.")
 	f.IntVar(&cfg.UploadParallelism, "boltdb.shipper.compactor.upload-parallelism", 10, "Number of upload/remove operations to execute in parallel when finalizing a compaction. NOTE: This setting is per compaction operation, which can be executed in parallel. The upper bound on the number of concurrent uploads is upload_parallelism * max_compaction_parallelism.")
@@ -251,6 +258,9 @@ func (c *Compactor) init(objectClient client.ObjectClient, schemaConfig config.S
 		}
 
 		chunkClient := client.NewClient(objectClient, encoder, schemaConfig)
+		if c.cfg.DeleteChunkBloomFilters {
+			chunkClient = client.NewClientWithBloomDeletes(objectClient, encoder, schemaConfig)
+		}
 
 		retentionWorkDir := filepath.Join(c.cfg.WorkingDirectory, "retention")
 		c.sweeper, err = retention.NewSweeper(retentionWorkDir, chunkClient, c.cfg.RetentionDeleteWorkCount, c.cfg.RetentionDeleteDelay, r)
@@ -258,7 +268,7 @@ func (c *Compactor) init(objectClient client.ObjectClient, schemaConfig config.S
 			return err
 		}
 
-		if err := c.initDeletes(r, limits); err != nil {
+		if err := c.initDeletes(chunkClient, r, limits, retentionWorkDir); err != nil {
 			return err
 		}
 
@@ -271,7 +281,7 @@ func (c *Compactor) init(objectClient client.ObjectClient, schemaConfig config.S
 	return nil
 }
 
-func (c *Compactor) initDeletes(r prometheus.Registerer, limits *validation.Overrides) error {
+func (c *Compactor) initDeletes(chunkClient client.Client, r prometheus.Registerer, limits *validation.Overrides, retentionWorkDir string) error {
 	deletionWorkDir := filepath.Join(c.cfg.WorkingDirectory, "deletion")
 
 	store, err := deletion.NewDeleteStore(deletionWorkDir, c.indexStorageClient)
@@ -282,6 +292,7 @@ func (c *Compactor) initDeletes(r prometheus.Registerer, limits *validation.Over
 
 	c.DeleteRequestsHandler = deletion.NewDeleteRequestHandler(
 		c.deleteRequestsStore,
+		deletion.NewDeleteRequestPreviewer(userChunkIterator{compactor: c, maxTables: c.cfg.DeletePreviewMaxTables}, chunkClient, c.cfg.DeletePreviewMaxChunks),
 		c.cfg.DeleteMaxInterval,
 		r,
 	)
@@ -296,7 +307,7 @@ func (c *Compactor) initDeletes(r prometheus.Registerer, limits *validation.Over
 		r,
 	)
 
-	c.expirationChecker = newExpirationChecker(retention.NewExpirationChecker(limits), c.deleteRequestsManager)
+	c.expirationChecker = newExpirationChecker(retention.NewExpirationChecker(limits, retentionWorkDir), c.deleteRequestsManager)
 	return nil
 }
 
@@ -585,6 +596,10 @@ func (c *Compactor) RunCompaction(ctx context.Context, applyRetention bool) erro
 		return err
 	}
 
+	if applyRetention && c.deleteRequestsManager != nil {
+		c.deleteRequestsManager.MarkTablesPending(tables)
+	}
+
 	compactTablesChan := make(chan string)
 	errChan := make(chan error)
 
@@ -604,6 +619,9 @@ func (c *Compactor) RunCompaction(ctx context.Context, applyRetention bool) erro
 
 					level.Info(util_log.Logger).Log("msg", "compacting table", "table-name", tableName)
 					err = c.CompactTable(ctx, tableName, applyRetention)
+					if applyRetention && c.deleteRequestsManager != nil {
+						c.deleteRequestsManager.MarkTableProcessed(tableName, err == nil)
+					}
 					if err != nil {
 						return
 					}
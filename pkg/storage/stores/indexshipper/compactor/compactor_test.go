@@ -15,6 +15,7 @@ import (
 
 	"github.com/grafana/loki/pkg/storage/chunk/client/local"
 	"github.com/grafana/loki/pkg/storage/config"
+	"github.com/grafana/loki/pkg/storage/stores/indexshipper/compactor/deletion"
 	loki_net "github.com/grafana/loki/pkg/util/net"
 )
 
@@ -185,3 +186,22 @@ func Test_tableSort(t *testing.T) {
 	sortTablesByRange(intervals)
 	require.Equal(t, []string{"index_19195", "index_19192", "index_19191"}, intervals)
 }
+
+func TestTablesInInterval(t *testing.T) {
+	tables := []string{deletion.DeleteRequestsTableName, "table_19000", "table_19001", "table_19002", "table_19003"}
+	interval := model.Interval{
+		Start: model.TimeFromUnix(19001 * 86400),
+		End:   model.TimeFromUnix(19002*86400 + 3600),
+	}
+
+	res, err := tablesInInterval(tables, interval, 0)
+	require.NoError(t, err)
+	require.Equal(t, []string{"table_19001", "table_19002"}, res)
+
+	res, err = tablesInInterval(tables, interval, 2)
+	require.NoError(t, err)
+	require.Equal(t, []string{"table_19001", "table_19002"}, res)
+
+	_, err = tablesInInterval(tables, interval, 1)
+	require.ErrorIs(t, err, deletion.ErrPreviewTooManyTables)
+}
@@ -1,6 +1,8 @@
 package deletion
 
 import (
+	"time"
+
 	"github.com/go-kit/log/level"
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/model/labels"
@@ -11,6 +13,9 @@ import (
 	util_log "github.com/grafana/loki/pkg/util/log"
 )
 
+// secondsInDay is the period of the index tables.
+const secondsInDay = int64(24 * time.Hour / time.Second)
+
 type DeleteRequest struct {
 	RequestID string              `json:"request_id"`
 	StartTime model.Time          `json:"start_time"`
@@ -18,6 +23,8 @@ type DeleteRequest struct {
 	Query     string              `json:"query"`
 	Status    DeleteRequestStatus `json:"status"`
 	CreatedAt model.Time          `json:"created_at"`
+	// Progress is set when listing the requests or processing them.
+	Progress *DeleteRequestProgress `json:"progress,omitempty"`
 
 	UserID          string                 `json:"-"`
 	SequenceNum     int64                  `json:"-"`
@@ -26,6 +33,11 @@ type DeleteRequest struct {
 
 	Metrics      *deleteRequestsManagerMetrics `json:"-"`
 	DeletedLines int32                         `json:"-"`
+
+	// processedTableIntervals holds the intervals of the tables which are already processed for the request.
+	processedTableIntervals []model.Interval
+	// deletedMultiTableChunks holds the IDs of the deleted chunks which span several tables, and are seen once per table.
+	deletedMultiTableChunks map[string]struct{}
 }
 
 func (d *DeleteRequest) SetQuery(logQL string) error {
@@ -54,22 +66,22 @@ func (d *DeleteRequest) FilterFunction(labels labels.Labels) (filter.Func, error
 		return nil, nil
 	}
 
-	p, err := d.logSelectorExpr.Pipeline()
-	if err != nil {
-		return nil, err
-	}
-
 	if !allMatch(d.matchers, labels) {
 		return func(s string) bool {
 			return false
 		}, nil
 	}
 
-	f := p.ForStream(labels).ProcessString
+	f, err := retention.LineFilterFunc(d.logSelectorExpr, labels)
+	if err != nil {
+		return nil, err
+	}
 	return func(s string) bool {
-		result, _, skip := f(0, s)
-		if len(result) != 0 || skip {
-			d.Metrics.deletedLinesTotal.WithLabelValues(d.UserID).Inc()
+		if f(s) {
+			// the metrics are not set when previewing delete requests.
+			if d.Metrics != nil {
+				d.Metrics.deletedLinesTotal.WithLabelValues(d.UserID).Inc()
+			}
 			d.DeletedLines++
 			return true
 		}
@@ -77,6 +89,61 @@ func (d *DeleteRequest) FilterFunction(labels labels.Labels) (filter.Func, error
 	}, nil
 }
 
+// setProgress sets the progress of the request loaded from the store.
+func (d *DeleteRequest) setProgress(progress DeleteRequestProgress) {
+	d.Progress = &progress
+	d.processedTableIntervals = d.processedTableIntervals[:0]
+	for tableName, status := range progress.Tables {
+		if status == TableStatusProcessed {
+			d.processedTableIntervals = append(d.processedTableIntervals, retention.ExtractIntervalFromTableName(tableName))
+		}
+	}
+}
+
+// setTableStatus updates the status of a table in the progress of the request.
+func (d *DeleteRequest) setTableStatus(tableName string, status TableStatus) {
+	if d.Progress == nil {
+		d.Progress = &DeleteRequestProgress{}
+	}
+	d.Progress.setTableStatus(tableName, status)
+	if status == TableStatusProcessed {
+		d.processedTableIntervals = append(d.processedTableIntervals, retention.ExtractIntervalFromTableName(tableName))
+	}
+}
+
+// isProcessed returns true if the table having the given interval, or the table holding the whole interval,
+// is already processed for the request.
+func (d *DeleteRequest) isProcessed(interval model.Interval) bool {
+	for _, tableInterval := range d.processedTableIntervals {
+		if tableInterval.Start <= interval.Start && interval.End <= tableInterval.End {
+			return true
+		}
+	}
+	return false
+}
+
+// addDeletedChunk counts a chunk deleted by the request in its progress.
+// Chunks spanning several tables are counted once per compaction, when processing the first of their tables.
+func (d *DeleteRequest) addDeletedChunk(ref retention.ChunkEntry) {
+	if ref.From.Unix()/secondsInDay != ref.Through.Unix()/secondsInDay {
+		if d.deletedMultiTableChunks == nil {
+			d.deletedMultiTableChunks = map[string]struct{}{}
+		}
+		if _, ok := d.deletedMultiTableChunks[string(ref.ChunkID)]; ok {
+			return
+		}
+		d.deletedMultiTableChunks[string(ref.ChunkID)] = struct{}{}
+	}
+	d.Progress.ChunksDeleted++
+}
+
+// progressToStore returns the progress of the request including the lines deleted while processing it.
+func (d *DeleteRequest) progressToStore() DeleteRequestProgress {
+	progress := *d.Progress
+	progress.LinesDeleted += int64(d.DeletedLines)
+	return progress
+}
+
 func allMatch(matchers []*labels.Matcher, labels labels.Labels) bool {
 	for _, m := range matchers {
 		if !m.Matches(labels.Get(m.Name)) {
@@ -127,13 +127,18 @@ func (d *DeleteRequestsManager) loadDeleteRequestsToProcess() error {
 	if err != nil {
 		return err
 	}
+	if len(deleteRequests) > d.batchSize {
+		logBatchTruncation(d.batchSize, len(deleteRequests))
+		deleteRequests = deleteRequests[:d.batchSize]
+	}
+
+	progresses, err := d.deleteRequestsStore.GetDeleteRequestsProgress(context.Background(), deleteRequests)
+	if err != nil {
+		return err
+	}
 
 	for i := range deleteRequests {
 		deleteRequest := deleteRequests[i]
-		if i >= d.batchSize {
-			logBatchTruncation(i, len(deleteRequests))
-			break
-		}
 
 		level.Info(util_log.Logger).Log(
 			"msg", "Started processing delete request for user",
@@ -143,6 +148,8 @@ func (d *DeleteRequestsManager) loadDeleteRequestsToProcess() error {
 
 		deleteRequest.Metrics = d.metrics
 
+		deleteRequest.setProgress(progresses[i])
+
 		ur := d.requestsForUser(deleteRequest)
 		ur.requests = append(ur.requests, &deleteRequest)
 		if deleteRequest.StartTime < ur.requestsInterval.Start {
@@ -254,6 +261,12 @@ func (d *DeleteRequestsManager) Expired(ref retention.ChunkEntry, _ model.Time)
 	})
 
 	for _, deleteRequest := range d.deleteRequestsToProcess[userIDStr].requests {
+		// skip the requests which have already processed the table holding the chunk.
+		if deleteRequest.isProcessed(model.Interval{Start: ref.From, End: ref.Through}) {
+			continue
+		}
+
+		deletedChunk := false
 		rebuiltIntervals := make([]retention.IntervalFilter, 0, len(d.chunkIntervalsToRetain))
 		for _, ivf := range d.chunkIntervalsToRetain {
 			if ivf.Filter != nil {
@@ -272,9 +285,13 @@ func (d *DeleteRequestsManager) Expired(ref retention.ChunkEntry, _ model.Time)
 				rebuiltIntervals = append(rebuiltIntervals, ivf)
 			} else {
 				isExpired = true
+				deletedChunk = true
 				rebuiltIntervals = append(rebuiltIntervals, newIntervalsToRetain...)
 			}
 		}
+		if deletedChunk {
+			deleteRequest.addDeletedChunk(ref)
+		}
 
 		d.chunkIntervalsToRetain = rebuiltIntervals
 		if isExpired && len(d.chunkIntervalsToRetain) == 0 {
@@ -327,6 +344,9 @@ func (d *DeleteRequestsManager) MarkPhaseFinished() {
 	d.deleteRequestsToProcessMtx.Lock()
 	defer d.deleteRequestsToProcessMtx.Unlock()
 
+	// store the lines deleted since the last processed table.
+	d.updateProgress()
+
 	for _, userDeleteRequests := range d.deleteRequestsToProcess {
 		if userDeleteRequests == nil {
 			continue
@@ -356,18 +376,113 @@ func (d *DeleteRequestsManager) MarkPhaseFinished() {
 	}
 }
 
-func (d *DeleteRequestsManager) IntervalMayHaveExpiredChunks(_ model.Interval, userID string) bool {
+func (d *DeleteRequestsManager) IntervalMayHaveExpiredChunks(interval model.Interval, userID string) bool {
 	d.deleteRequestsToProcessMtx.Lock()
 	defer d.deleteRequestsToProcessMtx.Unlock()
 
 	// We can't do the overlap check between the passed interval and delete requests interval from a user because
 	// if a request is issued just for today and there are chunks spanning today and yesterday then
 	// the overlap check would skip processing yesterday's index which would result in the index pointing to deleted chunks.
+	// We can however skip the tables which have already been processed for all the requests.
 	if userID != "" {
-		return d.deleteRequestsToProcess[userID] != nil
+		return d.deleteRequestsToProcess[userID] != nil && d.deleteRequestsToProcess[userID].hasUnprocessedRequests(interval)
 	}
 
-	return len(d.deleteRequestsToProcess) != 0
+	for _, ur := range d.deleteRequestsToProcess {
+		if ur.hasUnprocessedRequests(interval) {
+			return true
+		}
+	}
+	return false
+}
+
+func (u *userDeleteRequests) hasUnprocessedRequests(tableInterval model.Interval) bool {
+	for _, deleteRequest := range u.requests {
+		if !deleteRequest.isProcessed(tableInterval) {
+			return true
+		}
+	}
+	return false
+}
+
+// MarkTablesPending records the tables overlapping the delete requests being processed as pending,
+// unless they were processed by a previous compaction.
+func (d *DeleteRequestsManager) MarkTablesPending(tableNames []string) {
+	d.deleteRequestsToProcessMtx.Lock()
+	defer d.deleteRequestsToProcessMtx.Unlock()
+
+	pendingTables := 0
+	for _, tableName := range tableNames {
+		if tableName == DeleteRequestsTableName {
+			continue
+		}
+
+		tableInterval := retention.ExtractIntervalFromTableName(tableName)
+		d.forEachRequestOverlapping(tableInterval, func(deleteRequest *DeleteRequest) {
+			if deleteRequest.isProcessed(tableInterval) {
+				return
+			}
+
+			deleteRequest.setTableStatus(tableName, TableStatusPending)
+			pendingTables++
+		})
+	}
+	d.metrics.pendingTables.Set(float64(pendingTables))
+
+	d.updateProgress()
+}
+
+// MarkTableProcessed records whether the delete requests were successfully processed on the given table.
+func (d *DeleteRequestsManager) MarkTableProcessed(tableName string, success bool) {
+	d.deleteRequestsToProcessMtx.Lock()
+	defer d.deleteRequestsToProcessMtx.Unlock()
+
+	status, tableStatus := statusSuccess, TableStatusProcessed
+	if !success {
+		status, tableStatus = statusFail, TableStatusFailed
+	}
+
+	tableInterval := retention.ExtractIntervalFromTableName(tableName)
+	d.forEachRequestOverlapping(tableInterval, func(deleteRequest *DeleteRequest) {
+		if deleteRequest.isProcessed(tableInterval) {
+			return
+		}
+
+		if deleteRequest.Progress.Tables[tableName] == TableStatusPending {
+			d.metrics.pendingTables.Dec()
+		}
+		deleteRequest.setTableStatus(tableName, tableStatus)
+		d.metrics.deleteRequestTablesProcessedTotal.WithLabelValues(deleteRequest.UserID, status).Inc()
+	})
+
+	d.updateProgress()
+}
+
+func (d *DeleteRequestsManager) forEachRequestOverlapping(interval model.Interval, callback func(deleteRequest *DeleteRequest)) {
+	for _, ur := range d.deleteRequestsToProcess {
+		for _, deleteRequest := range ur.requests {
+			if intervalsOverlap(interval, model.Interval{Start: deleteRequest.StartTime, End: deleteRequest.EndTime}) {
+				callback(deleteRequest)
+			}
+		}
+	}
+}
+
+// updateProgress stores the progress of the delete requests being processed.
+func (d *DeleteRequestsManager) updateProgress() {
+	for _, ur := range d.deleteRequestsToProcess {
+		for _, deleteRequest := range ur.requests {
+			if err := d.deleteRequestsStore.UpdateDeleteRequestProgress(context.Background(), *deleteRequest, deleteRequest.progressToStore()); err != nil {
+				level.Error(util_log.Logger).Log(
+					"msg", "failed to update progress of delete request",
+					"delete_request_id", deleteRequest.RequestID,
+					"sequence_num", deleteRequest.SequenceNum,
+					"user", deleteRequest.UserID,
+					"err", err,
+				)
+			}
+		}
+	}
 }
 
 func (d *DeleteRequestsManager) DropFromIndex(_ retention.ChunkEntry, _ model.Time, _ model.Time) bool {
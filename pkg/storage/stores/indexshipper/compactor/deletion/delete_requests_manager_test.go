@@ -661,6 +661,127 @@ func TestDeleteRequestsManager_IntervalMayHaveExpiredChunks(t *testing.T) {
 	}
 }
 
+func TestDeleteRequestsManager_Progress(t *testing.T) {
+	lblFoo, err := syntax.ParseLabels(`{foo="bar"}`)
+	require.NoError(t, err)
+
+	dayStart := func(day int64) model.Time {
+		return model.TimeFromUnix(day * 86400)
+	}
+	chunkInTable := func(day int64) retention.ChunkEntry {
+		return retention.ChunkEntry{
+			ChunkRef: retention.ChunkRef{
+				UserID:  []byte(testUserID),
+				From:    dayStart(day).Add(time.Hour),
+				Through: dayStart(day).Add(2 * time.Hour),
+			},
+			Labels: lblFoo,
+		}
+	}
+	tables := []string{"index_19000", "index_19001", "index_19002", DeleteRequestsTableName}
+	deleteRequest := DeleteRequest{
+		RequestID: "test-request",
+		UserID:    testUserID,
+		Query:     lblFoo.String(),
+		StartTime: dayStart(19000),
+		EndTime:   dayStart(19002) - 1,
+	}
+	store := &mockDeleteRequestsStore{deleteRequests: []DeleteRequest{deleteRequest}}
+	progress := func() DeleteRequestProgress {
+		p, err := store.GetDeleteRequestsProgress(context.Background(), []DeleteRequest{deleteRequest})
+		require.NoError(t, err)
+		return p[0]
+	}
+
+	// the first compaction fails to process the second table of the request.
+	mgr := NewDeleteRequestsManager(store, time.Hour, 70, &fakeLimits{mode: deletionmode.FilterAndDelete.String()}, nil)
+	require.NoError(t, mgr.loadDeleteRequestsToProcess())
+	mgr.MarkTablesPending(tables)
+	require.Equal(t, map[string]TableStatus{"index_19000": TableStatusPending, "index_19001": TableStatusPending}, progress().Tables)
+
+	isExpired, _ := mgr.Expired(chunkInTable(19000), model.Now())
+	require.True(t, isExpired)
+	mgr.MarkTableProcessed("index_19000", true)
+	mgr.MarkTableProcessed("index_19001", false)
+	mgr.MarkTableProcessed("index_19002", true)
+	mgr.MarkPhaseFailed()
+
+	require.Equal(t, DeleteRequestProgress{
+		TablesTotal:     2,
+		TablesProcessed: 1,
+		Tables:          map[string]TableStatus{"index_19000": TableStatusProcessed, "index_19001": TableStatusFailed},
+		ChunksDeleted:   1,
+	}, progress())
+	require.Empty(t, store.updatedStatus)
+
+	// the next compaction only processes the failed table.
+	mgr = NewDeleteRequestsManager(store, time.Hour, 70, &fakeLimits{mode: deletionmode.FilterAndDelete.String()}, nil)
+	require.NoError(t, mgr.loadDeleteRequestsToProcess())
+	require.False(t, mgr.IntervalMayHaveExpiredChunks(retention.ExtractIntervalFromTableName("index_19000"), testUserID))
+	require.True(t, mgr.IntervalMayHaveExpiredChunks(retention.ExtractIntervalFromTableName("index_19001"), testUserID))
+	require.True(t, mgr.IntervalMayHaveExpiredChunks(retention.ExtractIntervalFromTableName("index_19001"), ""))
+
+	mgr.MarkTablesPending(tables)
+	require.Equal(t, map[string]TableStatus{"index_19000": TableStatusProcessed, "index_19001": TableStatusPending}, progress().Tables)
+
+	isExpired, _ = mgr.Expired(chunkInTable(19000), model.Now())
+	require.False(t, isExpired)
+	isExpired, _ = mgr.Expired(chunkInTable(19001), model.Now())
+	require.True(t, isExpired)
+	mgr.MarkTableProcessed("index_19001", true)
+	mgr.MarkPhaseFinished()
+
+	require.Equal(t, DeleteRequestProgress{
+		TablesTotal:     2,
+		TablesProcessed: 2,
+		Tables:          map[string]TableStatus{"index_19000": TableStatusProcessed, "index_19001": TableStatusProcessed},
+		ChunksDeleted:   2,
+	}, progress())
+	require.Equal(t, map[string]DeleteRequestStatus{"test-user:test-request": StatusProcessed}, store.updatedStatus)
+}
+
+func TestDeleteRequestsManager_ChunksDeletedSpanningTables(t *testing.T) {
+	lblFoo, err := syntax.ParseLabels(`{foo="bar"}`)
+	require.NoError(t, err)
+
+	dayStart := model.TimeFromUnix(19000 * 86400)
+	deleteRequest := DeleteRequest{
+		RequestID: "test-request",
+		UserID:    testUserID,
+		Query:     lblFoo.String(),
+		StartTime: dayStart,
+		EndTime:   dayStart.Add(48*time.Hour) - 1,
+	}
+	store := &mockDeleteRequestsStore{deleteRequests: []DeleteRequest{deleteRequest}}
+	mgr := NewDeleteRequestsManager(store, time.Hour, 70, &fakeLimits{mode: deletionmode.FilterAndDelete.String()}, nil)
+	require.NoError(t, mgr.loadDeleteRequestsToProcess())
+	mgr.MarkTablesPending([]string{"index_19000", "index_19001"})
+
+	chunk := func(id string, from, through model.Time) retention.ChunkEntry {
+		return retention.ChunkEntry{
+			ChunkRef: retention.ChunkRef{UserID: []byte(testUserID), ChunkID: []byte(id), From: from, Through: through},
+			Labels:   lblFoo,
+		}
+	}
+	// the chunk spanning both tables is seen when processing each of them.
+	for _, entry := range []retention.ChunkEntry{
+		chunk("spanning", dayStart.Add(23*time.Hour), dayStart.Add(25*time.Hour)),
+		chunk("first", dayStart.Add(time.Hour), dayStart.Add(2*time.Hour)),
+		chunk("spanning", dayStart.Add(23*time.Hour), dayStart.Add(25*time.Hour)),
+		chunk("second", dayStart.Add(26*time.Hour), dayStart.Add(27*time.Hour)),
+	} {
+		isExpired, _ := mgr.Expired(entry, model.Now())
+		require.True(t, isExpired)
+	}
+	mgr.MarkTableProcessed("index_19000", true)
+	mgr.MarkTableProcessed("index_19001", true)
+	mgr.MarkPhaseFinished()
+
+	progress, err := store.GetDeleteRequestsProgress(context.Background(), []DeleteRequest{deleteRequest})
+	require.NoError(t, err)
+	require.Equal(t, int64(3), progress[0].ChunksDeleted)
+}
+
 type mockDeleteRequestsStore struct {
 	DeleteRequestsStore
 	deleteRequests           []DeleteRequest
@@ -681,6 +802,9 @@ type mockDeleteRequestsStore struct {
 	getAllErr    error
 
 	genNumber string
+
+	progress      map[string]DeleteRequestProgress
+	updatedStatus map[string]DeleteRequestStatus
 }
 
 func (m *mockDeleteRequestsStore) GetDeleteRequestsByStatus(_ context.Context, _ DeleteRequestStatus) ([]DeleteRequest, error) {
@@ -714,3 +838,27 @@ func (m *mockDeleteRequestsStore) GetAllDeleteRequestsForUser(ctx context.Contex
 func (m *mockDeleteRequestsStore) GetCacheGenerationNumber(ctx context.Context, userID string) (string, error) {
 	return m.genNumber, m.getErr
 }
+
+func (m *mockDeleteRequestsStore) GetDeleteRequestsProgress(_ context.Context, reqs []DeleteRequest) ([]DeleteRequestProgress, error) {
+	progresses := make([]DeleteRequestProgress, 0, len(reqs))
+	for _, req := range reqs {
+		progresses = append(progresses, m.progress[backwardCompatibleDeleteRequestHash(req.UserID, req.RequestID, req.SequenceNum)])
+	}
+	return progresses, nil
+}
+
+func (m *mockDeleteRequestsStore) UpdateDeleteRequestProgress(_ context.Context, req DeleteRequest, progress DeleteRequestProgress) error {
+	if m.progress == nil {
+		m.progress = map[string]DeleteRequestProgress{}
+	}
+	m.progress[backwardCompatibleDeleteRequestHash(req.UserID, req.RequestID, req.SequenceNum)] = progress
+	return nil
+}
+
+func (m *mockDeleteRequestsStore) UpdateStatus(_ context.Context, req DeleteRequest, newStatus DeleteRequestStatus) error {
+	if m.updatedStatus == nil {
+		m.updatedStatus = map[string]DeleteRequestStatus{}
+	}
+	m.updatedStatus[backwardCompatibleDeleteRequestHash(req.UserID, req.RequestID, req.SequenceNum)] = newStatus
+	return nil
+}
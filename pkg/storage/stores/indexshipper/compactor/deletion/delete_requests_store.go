@@ -10,6 +10,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 	"unsafe"
 
@@ -23,6 +24,7 @@ import (
 
 type (
 	DeleteRequestStatus string
+	TableStatus         string
 	indexType           string
 )
 
@@ -30,9 +32,15 @@ const (
 	StatusReceived  DeleteRequestStatus = "received"
 	StatusProcessed DeleteRequestStatus = "processed"
 
-	deleteRequestID      indexType = "1"
-	deleteRequestDetails indexType = "2"
-	cacheGenNum          indexType = "3"
+	TableStatusPending   TableStatus = "pending"
+	TableStatusProcessed TableStatus = "processed"
+	TableStatusFailed    TableStatus = "failed"
+
+	deleteRequestID       indexType = "1"
+	deleteRequestDetails  indexType = "2"
+	cacheGenNum           indexType = "3"
+	deleteRequestTables   indexType = "4"
+	deleteRequestProgress indexType = "5"
 
 	tempFileSuffix          = ".temp"
 	DeleteRequestsTableName = "delete_requests"
@@ -48,10 +56,42 @@ type DeleteRequestsStore interface {
 	GetDeleteRequestGroup(ctx context.Context, userID, requestID string) ([]DeleteRequest, error)
 	RemoveDeleteRequests(ctx context.Context, req []DeleteRequest) error
 	GetCacheGenerationNumber(ctx context.Context, userID string) (string, error)
+	GetDeleteRequestsProgress(ctx context.Context, reqs []DeleteRequest) ([]DeleteRequestProgress, error)
+	UpdateDeleteRequestProgress(ctx context.Context, req DeleteRequest, progress DeleteRequestProgress) error
 	Stop()
 	Name() string
 }
 
+// DeleteRequestProgress tracks the processing of a delete request across the index tables it spans.
+type DeleteRequestProgress struct {
+	TablesTotal     int                    `json:"tables_total"`
+	TablesProcessed int                    `json:"tables_processed"`
+	Tables          map[string]TableStatus `json:"tables"`
+	// ChunksDeleted counts the chunks deleted entirely or rewritten without the deleted lines.
+	// Chunks spanning several tables are counted once per compaction processing some of their tables.
+	ChunksDeleted int64 `json:"chunks_deleted"`
+	// LinesDeleted counts the lines removed by the line filters of the request.
+	LinesDeleted int64 `json:"lines_deleted"`
+}
+
+func (p *DeleteRequestProgress) setTableStatus(tableName string, status TableStatus) {
+	if p.Tables == nil {
+		p.Tables = map[string]TableStatus{}
+	}
+	p.Tables[tableName] = status
+	p.updateTableCounts()
+}
+
+func (p *DeleteRequestProgress) updateTableCounts() {
+	p.TablesTotal = len(p.Tables)
+	p.TablesProcessed = 0
+	for _, status := range p.Tables {
+		if status == TableStatusProcessed {
+			p.TablesProcessed++
+		}
+	}
+}
+
 // deleteRequestsStore provides all the methods required to manage lifecycle of delete request and things related to it.
 type deleteRequestsStore struct {
 	indexClient index.Client
@@ -241,6 +281,92 @@ func (ds *deleteRequestsStore) GetCacheGenerationNumber(ctx context.Context, use
 	return genNumber, nil
 }
 
+// GetDeleteRequestsProgress returns the processing progress of the given delete requests, in the same order.
+// The progress of all the requests is read with a single batch of index queries.
+func (ds *deleteRequestsStore) GetDeleteRequestsProgress(ctx context.Context, reqs []DeleteRequest) ([]DeleteRequestProgress, error) {
+	progresses := make([]DeleteRequestProgress, len(reqs))
+	queries := make([]index.Query, 0, 2*len(reqs))
+	// requestIndexes maps the hash values queried to the index of their request.
+	requestIndexes := make(map[string]int, 2*len(reqs))
+	for i, req := range reqs {
+		progresses[i].Tables = map[string]TableStatus{}
+
+		userIDAndRequestID := backwardCompatibleDeleteRequestHash(req.UserID, req.RequestID, req.SequenceNum)
+		for _, typ := range []indexType{deleteRequestTables, deleteRequestProgress} {
+			hashValue := fmt.Sprintf("%s:%s", typ, userIDAndRequestID)
+			requestIndexes[hashValue] = i
+			queries = append(queries, index.Query{TableName: DeleteRequestsTableName, HashValue: hashValue})
+		}
+	}
+
+	var (
+		mtx      sync.Mutex
+		parseErr error
+	)
+	err := ds.indexClient.QueryPages(ctx, queries, func(query index.Query, batch index.ReadBatchResult) (shouldContinue bool) {
+		mtx.Lock()
+		defer mtx.Unlock()
+
+		progress := &progresses[requestIndexes[query.HashValue]]
+		itr := batch.Iterator()
+		for itr.Next() {
+			if strings.HasPrefix(query.HashValue, string(deleteRequestTables)+":") {
+				progress.Tables[string(itr.RangeValue())] = TableStatus(itr.Value())
+				continue
+			}
+
+			if progress.ChunksDeleted, progress.LinesDeleted, parseErr = parseDeleteRequestProgress(itr.Value()); parseErr != nil {
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if parseErr != nil {
+		return nil, parseErr
+	}
+
+	for i := range progresses {
+		progresses[i].updateTableCounts()
+	}
+	return progresses, nil
+}
+
+// UpdateDeleteRequestProgress stores the status of the tables and the counters of the given progress.
+// Tables missing from the progress are left untouched.
+func (ds *deleteRequestsStore) UpdateDeleteRequestProgress(ctx context.Context, req DeleteRequest, progress DeleteRequestProgress) error {
+	userIDAndRequestID := backwardCompatibleDeleteRequestHash(req.UserID, req.RequestID, req.SequenceNum)
+
+	writeBatch := ds.indexClient.NewWriteBatch()
+	for tableName, status := range progress.Tables {
+		writeBatch.Add(DeleteRequestsTableName, fmt.Sprintf("%s:%s", deleteRequestTables, userIDAndRequestID), []byte(tableName), []byte(status))
+	}
+	value := fmt.Sprintf("%x:%x", progress.ChunksDeleted, progress.LinesDeleted)
+	writeBatch.Add(DeleteRequestsTableName, fmt.Sprintf("%s:%s", deleteRequestProgress, userIDAndRequestID), []byte{}, []byte(value))
+
+	return ds.indexClient.BatchWrite(ctx, writeBatch)
+}
+
+func parseDeleteRequestProgress(value []byte) (int64, int64, error) {
+	hexParts := strings.Split(string(value), ":")
+	if len(hexParts) != 2 {
+		return 0, 0, errors.New("invalid delete request progress")
+	}
+
+	chunks, err := strconv.ParseInt(hexParts[0], 16, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	lines, err := strconv.ParseInt(hexParts[1], 16, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return chunks, lines, nil
+}
+
 func (ds *deleteRequestsStore) queryDeleteRequests(ctx context.Context, deleteQuery index.Query) ([]DeleteRequest, error) {
 	var deleteRequests []DeleteRequest
 	var err error
@@ -328,16 +454,20 @@ func unmarshalDeleteRequestDetails(itr index.ReadBatchIterator, req DeleteReques
 
 // RemoveDeleteRequests the passed delete requests
 func (ds *deleteRequestsStore) RemoveDeleteRequests(ctx context.Context, reqs []DeleteRequest) error {
-	writeBatch := ds.indexClient.NewWriteBatch()
+	progresses, err := ds.GetDeleteRequestsProgress(ctx, reqs)
+	if err != nil {
+		return err
+	}
 
-	for _, r := range reqs {
-		ds.removeRequest(r, writeBatch)
+	writeBatch := ds.indexClient.NewWriteBatch()
+	for i, r := range reqs {
+		ds.removeRequest(r, progresses[i], writeBatch)
 	}
 
 	return ds.indexClient.BatchWrite(ctx, writeBatch)
 }
 
-func (ds *deleteRequestsStore) removeRequest(req DeleteRequest, writeBatch index.WriteBatch) {
+func (ds *deleteRequestsStore) removeRequest(req DeleteRequest, progress DeleteRequestProgress, writeBatch index.WriteBatch) {
 	userIDAndRequestID := backwardCompatibleDeleteRequestHash(req.UserID, req.RequestID, req.SequenceNum)
 	writeBatch.Delete(DeleteRequestsTableName, string(deleteRequestID), []byte(userIDAndRequestID))
 
@@ -345,6 +475,12 @@ func (ds *deleteRequestsStore) removeRequest(req DeleteRequest, writeBatch index
 	rangeValue := fmt.Sprintf("%x:%x:%x", int64(req.CreatedAt), int64(req.StartTime), int64(req.EndTime))
 	writeBatch.Delete(DeleteRequestsTableName, fmt.Sprintf("%s:%s", deleteRequestDetails, userIDAndRequestID), []byte(rangeValue))
 
+	// remove the progress of the request, if it was partially processed
+	for tableName := range progress.Tables {
+		writeBatch.Delete(DeleteRequestsTableName, fmt.Sprintf("%s:%s", deleteRequestTables, userIDAndRequestID), []byte(tableName))
+	}
+	writeBatch.Delete(DeleteRequestsTableName, fmt.Sprintf("%s:%s", deleteRequestProgress, userIDAndRequestID), []byte{})
+
 	// ensure caches are invalidated
 	writeBatch.Add(DeleteRequestsTableName, fmt.Sprintf("%s:%s", cacheGenNum, req.UserID), []byte{}, []byte(strconv.FormatInt(time.Now().UnixNano(), 10)))
 }
@@ -194,6 +194,62 @@ func TestBatchCreateGet(t *testing.T) {
 	})
 }
 
+func TestDeleteRequestProgress(t *testing.T) {
+	tc := setup(t)
+	defer tc.store.Stop()
+
+	savedRequests, err := tc.store.AddDeleteRequestGroup(context.Background(), tc.user1Requests[:2])
+	require.NoError(t, err)
+
+	// requests which were not processed yet have no progress
+	progresses, err := tc.store.GetDeleteRequestsProgress(context.Background(), savedRequests)
+	require.NoError(t, err)
+	require.Equal(t, []DeleteRequestProgress{{Tables: map[string]TableStatus{}}, {Tables: map[string]TableStatus{}}}, progresses)
+
+	require.NoError(t, tc.store.UpdateDeleteRequestProgress(context.Background(), savedRequests[0], DeleteRequestProgress{
+		Tables:        map[string]TableStatus{"index_1": TableStatusProcessed, "index_2": TableStatusPending},
+		ChunksDeleted: 10,
+		LinesDeleted:  100,
+	}))
+	// tables missing from the progress are left untouched
+	require.NoError(t, tc.store.UpdateDeleteRequestProgress(context.Background(), savedRequests[0], DeleteRequestProgress{
+		Tables:        map[string]TableStatus{"index_3": TableStatusFailed},
+		ChunksDeleted: 20,
+		LinesDeleted:  200,
+	}))
+	require.NoError(t, tc.store.UpdateDeleteRequestProgress(context.Background(), savedRequests[1], DeleteRequestProgress{
+		Tables:        map[string]TableStatus{"index_1": TableStatusProcessed},
+		ChunksDeleted: 1,
+		LinesDeleted:  2,
+	}))
+
+	// the progress of the requests of the group is tracked separately
+	progresses, err = tc.store.GetDeleteRequestsProgress(context.Background(), savedRequests)
+	require.NoError(t, err)
+	require.Equal(t, []DeleteRequestProgress{
+		{
+			TablesTotal:     3,
+			TablesProcessed: 1,
+			Tables:          map[string]TableStatus{"index_1": TableStatusProcessed, "index_2": TableStatusPending, "index_3": TableStatusFailed},
+			ChunksDeleted:   20,
+			LinesDeleted:    200,
+		},
+		{
+			TablesTotal:     1,
+			TablesProcessed: 1,
+			Tables:          map[string]TableStatus{"index_1": TableStatusProcessed},
+			ChunksDeleted:   1,
+			LinesDeleted:    2,
+		},
+	}, progresses)
+
+	// the progress is removed along with the request
+	require.NoError(t, tc.store.RemoveDeleteRequests(context.Background(), savedRequests))
+	progresses, err = tc.store.GetDeleteRequestsProgress(context.Background(), savedRequests[:1])
+	require.NoError(t, err)
+	require.Equal(t, []DeleteRequestProgress{{Tables: map[string]TableStatus{}}}, progresses)
+}
+
 func compareRequests(t *testing.T, expected []DeleteRequest, actual []DeleteRequest) {
 	require.Len(t, actual, len(expected))
 	sort.Slice(expected, func(i, j int) bool {
@@ -52,6 +52,8 @@ type deleteRequestsManagerMetrics struct {
 	oldestPendingDeleteRequestAgeSeconds prometheus.Gauge
 	pendingDeleteRequestsCount           prometheus.Gauge
 	deletedLinesTotal                    *prometheus.CounterVec
+	deleteRequestTablesProcessedTotal    *prometheus.CounterVec
+	pendingTables                        prometheus.Gauge
 }
 
 func newDeleteRequestsManagerMetrics(r prometheus.Registerer) *deleteRequestsManagerMetrics {
@@ -92,6 +94,16 @@ func newDeleteRequestsManagerMetrics(r prometheus.Registerer) *deleteRequestsMan
 		Name:      "compactor_deleted_lines",
 		Help:      "Number of deleted lines per user",
 	}, []string{"user"})
+	m.deleteRequestTablesProcessedTotal = promauto.With(r).NewCounterVec(prometheus.CounterOpts{
+		Namespace: "loki",
+		Name:      "compactor_delete_request_tables_processed_total",
+		Help:      "Number of tables processed for delete requests per user and status",
+	}, []string{"user", "status"})
+	m.pendingTables = promauto.With(r).NewGauge(prometheus.GaugeOpts{
+		Namespace: "loki",
+		Name:      "compactor_delete_request_pending_tables",
+		Help:      "Number of tables left to process for the delete requests being processed, counted once per delete request",
+	})
 
 	return &m
 }
@@ -38,6 +38,14 @@ func (d *noOpDeleteRequestsStore) GetCacheGenerationNumber(ctx context.Context,
 	return "", nil
 }
 
+func (d *noOpDeleteRequestsStore) GetDeleteRequestsProgress(ctx context.Context, reqs []DeleteRequest) ([]DeleteRequestProgress, error) {
+	return make([]DeleteRequestProgress, len(reqs)), nil
+}
+
+func (d *noOpDeleteRequestsStore) UpdateDeleteRequestProgress(ctx context.Context, req DeleteRequest, progress DeleteRequestProgress) error {
+	return nil
+}
+
 func (d *noOpDeleteRequestsStore) Stop() {}
 
 func (d *noOpDeleteRequestsStore) Name() string {
@@ -0,0 +1,180 @@
+package deletion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/grafana/loki/pkg/chunkenc"
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql/log"
+	"github.com/grafana/loki/pkg/storage/chunk"
+	"github.com/grafana/loki/pkg/storage/chunk/client"
+	"github.com/grafana/loki/pkg/storage/stores/indexshipper/compactor/retention"
+)
+
+// ErrPreviewTooManyTables is returned when a delete request spans too many index tables to be previewed.
+var ErrPreviewTooManyTables = errors.New("delete request spans too many index tables to be previewed")
+
+// DeleteRequestPreview describes what a delete request would delete.
+// When Estimated is true, only some of the chunks were read, and the chunks, lines and bytes
+// of the remaining ones are extrapolated from them.
+type DeleteRequestPreview struct {
+	Streams   []string `json:"streams"`
+	Chunks    int      `json:"chunks"`
+	Lines     int64    `json:"lines"`
+	Bytes     int64    `json:"bytes"`
+	Estimated bool     `json:"estimated"`
+}
+
+// DeleteRequestPreviewer evaluates a delete request without processing it.
+type DeleteRequestPreviewer interface {
+	PreviewDeleteRequest(ctx context.Context, req DeleteRequest) (*DeleteRequestPreview, error)
+}
+
+// UserChunkIterator iterates over the chunks of a user indexed in the given interval.
+type UserChunkIterator interface {
+	ForEachUserChunk(ctx context.Context, userID string, interval model.Interval, callback func(retention.ChunkEntry) error) error
+}
+
+type deleteRequestPreviewer struct {
+	chunkIterator UserChunkIterator
+	chunkClient   client.Client
+	maxChunks     int
+}
+
+// NewDeleteRequestPreviewer returns a DeleteRequestPreviewer which finds the chunks a delete request
+// would delete in the index, and reads them to count the lines it would delete.
+// At most maxChunks chunks are read per preview, the lines of the other chunks are estimated; 0 means no limit.
+func NewDeleteRequestPreviewer(chunkIterator UserChunkIterator, chunkClient client.Client, maxChunks int) DeleteRequestPreviewer {
+	return &deleteRequestPreviewer{
+		chunkIterator: chunkIterator,
+		chunkClient:   chunkClient,
+		maxChunks:     maxChunks,
+	}
+}
+
+func (p *deleteRequestPreviewer) PreviewDeleteRequest(ctx context.Context, req DeleteRequest) (*DeleteRequestPreview, error) {
+	if err := req.SetQuery(req.Query); err != nil {
+		return nil, err
+	}
+
+	preview := &DeleteRequestPreview{Streams: []string{}}
+	streams := map[string]struct{}{}
+	// chunks spanning multiple tables are indexed in each of them.
+	seenChunks := map[string]struct{}{}
+	// chunks matching the request which were read, and which were skipped once maxChunks chunks were read.
+	var readChunks, unreadChunks int
+
+	err := p.chunkIterator.ForEachUserChunk(ctx, req.UserID, model.Interval{Start: req.StartTime, End: req.EndTime}, func(entry retention.ChunkEntry) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		chunkID := string(entry.ChunkID)
+		if _, ok := seenChunks[chunkID]; ok {
+			return nil
+		}
+		seenChunks[chunkID] = struct{}{}
+
+		if isDeleted, _ := req.IsDeleted(entry); !isDeleted {
+			return nil
+		}
+
+		if p.maxChunks > 0 && readChunks >= p.maxChunks {
+			unreadChunks++
+			streams[entry.Labels.String()] = struct{}{}
+			return nil
+		}
+
+		readChunks++
+		lines, bytes, err := p.deletedLines(ctx, req, entry)
+		if err != nil {
+			return err
+		}
+		if lines == 0 {
+			return nil
+		}
+
+		preview.Chunks++
+		preview.Lines += lines
+		preview.Bytes += bytes
+		streams[entry.Labels.String()] = struct{}{}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if unreadChunks > 0 {
+		ratio := float64(readChunks+unreadChunks) / float64(readChunks)
+		preview.Chunks = int(math.Round(float64(preview.Chunks) * ratio))
+		preview.Lines = int64(math.Round(float64(preview.Lines) * ratio))
+		preview.Bytes = int64(math.Round(float64(preview.Bytes) * ratio))
+		preview.Estimated = true
+	}
+
+	for stream := range streams {
+		preview.Streams = append(preview.Streams, stream)
+	}
+	sort.Strings(preview.Streams)
+	return preview, nil
+}
+
+// deletedLines reads the chunk to count the lines and bytes the delete request would delete from it.
+func (p *deleteRequestPreviewer) deletedLines(ctx context.Context, req DeleteRequest, entry retention.ChunkEntry) (int64, int64, error) {
+	chk, err := chunk.ParseExternalKey(req.UserID, string(entry.ChunkID))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	chks, err := p.chunkClient.GetChunks(ctx, []chunk.Chunk{chk})
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(chks) != 1 {
+		return 0, 0, fmt.Errorf("expected 1 entry for chunk %s but found %d in storage", entry.ChunkID, len(chks))
+	}
+
+	facade, ok := chks[0].Data.(*chunkenc.Facade)
+	if !ok {
+		return 0, 0, errors.New("invalid chunk type")
+	}
+
+	filter, err := req.FilterFunction(entry.Labels)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	from, through := entry.From, entry.Through
+	if req.StartTime > from {
+		from = req.StartTime
+	}
+	if req.EndTime < through {
+		through = req.EndTime
+	}
+
+	// add a millisecond to end time because the Chunk.Iterator considers end time to be non-inclusive.
+	itr, err := facade.LokiChunk().Iterator(ctx, from.Time(), through.Time().Add(time.Millisecond), logproto.FORWARD, log.NewNoopPipeline().ForStream(labels.Labels{}))
+	if err != nil {
+		return 0, 0, err
+	}
+	defer itr.Close()
+
+	var lines, bytes int64
+	for itr.Next() {
+		line := itr.Entry().Line
+		if filter != nil && !filter(line) {
+			continue
+		}
+		lines++
+		bytes += int64(len(line))
+	}
+	return lines, bytes, itr.Error()
+}
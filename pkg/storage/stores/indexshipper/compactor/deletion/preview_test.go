@@ -0,0 +1,248 @@
+package deletion
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/chunkenc"
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/storage/chunk"
+	"github.com/grafana/loki/pkg/storage/config"
+	"github.com/grafana/loki/pkg/storage/stores/indexshipper/compactor/retention"
+)
+
+var previewSchemaConfig = config.SchemaConfig{
+	Configs: []config.PeriodConfig{
+		{From: config.DayTime{Time: 0}, Schema: "v12", RowShards: 16},
+	},
+}
+
+type mockChunkClient struct {
+	chunks map[string]chunk.Chunk
+}
+
+func (m *mockChunkClient) Stop() {}
+
+func (m *mockChunkClient) PutChunks(_ context.Context, _ []chunk.Chunk) error {
+	return nil
+}
+
+func (m *mockChunkClient) GetChunks(_ context.Context, chunks []chunk.Chunk) ([]chunk.Chunk, error) {
+	var res []chunk.Chunk
+	for _, c := range chunks {
+		chk, ok := m.chunks[previewSchemaConfig.ExternalKey(c.ChunkRef)]
+		if !ok {
+			return nil, fmt.Errorf("chunk not found")
+		}
+		res = append(res, chk)
+	}
+	return res, nil
+}
+
+func (m *mockChunkClient) DeleteChunk(_ context.Context, _, _ string) error {
+	return nil
+}
+
+func (m *mockChunkClient) IsChunkNotFoundErr(_ error) bool {
+	return false
+}
+
+type mockUserChunkIterator []retention.ChunkEntry
+
+func (m mockUserChunkIterator) ForEachUserChunk(_ context.Context, userID string, _ model.Interval, callback func(retention.ChunkEntry) error) error {
+	for _, entry := range m {
+		if string(entry.UserID) != userID {
+			continue
+		}
+		if err := callback(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// buildChunk builds a chunk with a line every minute between from and through.
+func buildChunk(t *testing.T, userID, lbls string, from, through model.Time, chunkClient *mockChunkClient) retention.ChunkEntry {
+	metric := mustParseLabel(lbls)
+	memChunk := chunkenc.NewMemChunk(chunkenc.EncSnappy, chunkenc.UnorderedHeadBlockFmt, 256*1024, 0)
+	for ts := from; ts <= through; ts = ts.Add(time.Minute) {
+		level := "info"
+		if ts.Sub(from)/time.Minute%2 == 0 {
+			level = "debug"
+		}
+		require.NoError(t, memChunk.Append(&logproto.Entry{Timestamp: ts.Time(), Line: "level=" + level}))
+	}
+
+	chk := chunk.NewChunk(userID, model.Fingerprint(metric.Hash()), metric, chunkenc.NewFacade(memChunk, 0, 0), from, through)
+	require.NoError(t, chk.Encode())
+	chunkID := previewSchemaConfig.ExternalKey(chk.ChunkRef)
+	chunkClient.chunks[chunkID] = chk
+
+	return retention.ChunkEntry{
+		ChunkRef: retention.ChunkRef{
+			UserID:  []byte(userID),
+			ChunkID: []byte(chunkID),
+			From:    from,
+			Through: through,
+		},
+		Labels: metric,
+	}
+}
+
+func TestDeleteRequestPreviewer(t *testing.T) {
+	now := model.TimeFromUnixNano(time.Now().Truncate(time.Minute).UnixNano())
+	chunkClient := &mockChunkClient{chunks: map[string]chunk.Chunk{}}
+
+	fooChunk := buildChunk(t, "user1", `{foo="bar"}`, now.Add(-2*time.Hour), now.Add(-time.Hour-time.Minute), chunkClient)
+	chunks := mockUserChunkIterator{
+		fooChunk,
+		buildChunk(t, "user1", `{foo="bar"}`, now.Add(-time.Hour), now.Add(-time.Minute), chunkClient),
+		buildChunk(t, "user1", `{fizz="buzz"}`, now.Add(-2*time.Hour), now.Add(-time.Minute), chunkClient),
+		buildChunk(t, "user2", `{foo="bar"}`, now.Add(-2*time.Hour), now.Add(-time.Minute), chunkClient),
+		// chunks spanning multiple tables are seen once per table.
+		fooChunk,
+	}
+	previewer := NewDeleteRequestPreviewer(chunks, chunkClient, 0)
+
+	for _, tc := range []struct {
+		name     string
+		req      DeleteRequest
+		expected DeleteRequestPreview
+	}{
+		{
+			name: "whole chunks",
+			req: DeleteRequest{
+				UserID:    "user1",
+				StartTime: now.Add(-3 * time.Hour),
+				EndTime:   now,
+				Query:     `{foo="bar"}`,
+			},
+			expected: DeleteRequestPreview{Streams: []string{`{foo="bar"}`}, Chunks: 2, Lines: 120, Bytes: int64(120*len("level=info") + 60)},
+		},
+		{
+			name: "partially deleted chunk",
+			req: DeleteRequest{
+				UserID:    "user1",
+				StartTime: now.Add(-90 * time.Minute),
+				EndTime:   now.Add(-80 * time.Minute),
+				Query:     `{foo="bar"}`,
+			},
+			expected: DeleteRequestPreview{Streams: []string{`{foo="bar"}`}, Chunks: 1, Lines: 11, Bytes: int64(11*len("level=info") + 6)},
+		},
+		{
+			name: "line filter",
+			req: DeleteRequest{
+				UserID:    "user1",
+				StartTime: now.Add(-3 * time.Hour),
+				EndTime:   now,
+				Query:     `{foo=~"bar|buzz"} |= "debug"`,
+			},
+			expected: DeleteRequestPreview{Streams: []string{`{foo="bar"}`}, Chunks: 2, Lines: 60, Bytes: int64(60 * len("level=debug"))},
+		},
+		{
+			name: "no matching stream",
+			req: DeleteRequest{
+				UserID:    "user1",
+				StartTime: now.Add(-3 * time.Hour),
+				EndTime:   now,
+				Query:     `{foo="other"}`,
+			},
+			expected: DeleteRequestPreview{Streams: []string{}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			preview, err := previewer.PreviewDeleteRequest(context.Background(), tc.req)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, *preview)
+		})
+	}
+
+	t.Run("max chunks", func(t *testing.T) {
+		preview, err := NewDeleteRequestPreviewer(chunks, chunkClient, 1).PreviewDeleteRequest(context.Background(), DeleteRequest{
+			UserID:    "user1",
+			StartTime: now.Add(-3 * time.Hour),
+			EndTime:   now,
+			Query:     `{foo="bar"}`,
+		})
+		require.NoError(t, err)
+		require.Equal(t, DeleteRequestPreview{Streams: []string{`{foo="bar"}`}, Chunks: 2, Lines: 120, Bytes: int64(120*len("level=info") + 60), Estimated: true}, *preview)
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := previewer.PreviewDeleteRequest(ctx, DeleteRequest{
+			UserID:    "user1",
+			StartTime: now.Add(-3 * time.Hour),
+			EndTime:   now,
+			Query:     `{foo="bar"}`,
+		})
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+type mockDeleteRequestPreviewer struct {
+	req DeleteRequest
+	err error
+}
+
+func (m *mockDeleteRequestPreviewer) PreviewDeleteRequest(_ context.Context, req DeleteRequest) (*DeleteRequestPreview, error) {
+	m.req = req
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &DeleteRequestPreview{Streams: []string{`{foo="bar"}`}, Chunks: 1, Lines: 2, Bytes: 3}, nil
+}
+
+func TestPreviewDeleteRequestHandler(t *testing.T) {
+	t.Run("it previews the delete request without adding it", func(t *testing.T) {
+		store := &mockDeleteRequestsStore{}
+		previewer := &mockDeleteRequestPreviewer{}
+		h := NewDeleteRequestHandler(store, previewer, 0, nil)
+
+		w := httptest.NewRecorder()
+		h.PreviewDeleteRequestHandler(w, buildRequest("org-id", `{foo="bar"} |= "debug"`, "0000000000", "0000000001"))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Empty(t, store.addReqs)
+		require.Equal(t, DeleteRequest{
+			UserID:    "org-id",
+			Query:     `{foo="bar"} |= "debug"`,
+			StartTime: toTime("0000000000"),
+			EndTime:   toTime("0000000001"),
+		}, previewer.req)
+
+		var preview DeleteRequestPreview
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &preview))
+		require.Equal(t, DeleteRequestPreview{Streams: []string{`{foo="bar"}`}, Chunks: 1, Lines: 2, Bytes: 3}, preview)
+	})
+
+	t.Run("it validates the request", func(t *testing.T) {
+		h := NewDeleteRequestHandler(&mockDeleteRequestsStore{}, &mockDeleteRequestPreviewer{}, 0, nil)
+
+		w := httptest.NewRecorder()
+		h.PreviewDeleteRequestHandler(w, buildRequest("org-id", `not a query`, "0000000000", "0000000001"))
+		require.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = httptest.NewRecorder()
+		h.PreviewDeleteRequestHandler(w, buildRequest("", `{foo="bar"}`, "0000000000", "0000000001"))
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("it rejects requests spanning too many tables", func(t *testing.T) {
+		previewer := &mockDeleteRequestPreviewer{err: fmt.Errorf("%w: 40 tables, the limit is 31", ErrPreviewTooManyTables)}
+		h := NewDeleteRequestHandler(&mockDeleteRequestsStore{}, previewer, 0, nil)
+
+		w := httptest.NewRecorder()
+		h.PreviewDeleteRequestHandler(w, buildRequest("org-id", `{foo="bar"}`, "0000000000", "0000000001"))
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
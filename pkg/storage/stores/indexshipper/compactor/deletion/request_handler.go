@@ -24,14 +24,16 @@ import (
 // DeleteRequestHandler provides handlers for delete requests
 type DeleteRequestHandler struct {
 	deleteRequestsStore DeleteRequestsStore
+	previewer           DeleteRequestPreviewer
 	metrics             *deleteRequestHandlerMetrics
 	maxInterval         time.Duration
 }
 
 // NewDeleteRequestHandler creates a DeleteRequestHandler
-func NewDeleteRequestHandler(deleteStore DeleteRequestsStore, maxInterval time.Duration, registerer prometheus.Registerer) *DeleteRequestHandler {
+func NewDeleteRequestHandler(deleteStore DeleteRequestsStore, previewer DeleteRequestPreviewer, maxInterval time.Duration, registerer prometheus.Registerer) *DeleteRequestHandler {
 	deleteMgr := DeleteRequestHandler{
 		deleteRequestsStore: deleteStore,
+		previewer:           previewer,
 		maxInterval:         maxInterval,
 		metrics:             newDeleteRequestHandlerMetrics(registerer),
 	}
@@ -99,6 +101,62 @@ func (dm *DeleteRequestHandler) AddDeleteRequestHandler(w http.ResponseWriter, r
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// PreviewDeleteRequestHandler reports the streams, chunks, lines and bytes a delete request would delete,
+// without adding it.
+func (dm *DeleteRequestHandler) PreviewDeleteRequestHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, err := tenant.TenantID(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if dm.previewer == nil {
+		http.Error(w, "delete request preview is not supported", http.StatusNotImplemented)
+		return
+	}
+
+	params := r.URL.Query()
+	query, err := query(params)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	startTime, err := startTime(params)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	endTime, err := endTime(params, startTime)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	preview, err := dm.previewer.PreviewDeleteRequest(ctx, DeleteRequest{
+		StartTime: startTime,
+		EndTime:   endTime,
+		Query:     query,
+		UserID:    userID,
+	})
+	if errors.Is(err, ErrPreviewTooManyTables) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		level.Error(util_log.Logger).Log("msg", "error previewing delete request", "user", userID, "query", query, "err", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(preview); err != nil {
+		level.Error(util_log.Logger).Log("msg", "error marshalling response", "err", err)
+		http.Error(w, fmt.Sprintf("Error marshalling response: %v", err), http.StatusInternalServerError)
+	}
+}
+
 func shardDeleteRequestsByInterval(startTime, endTime model.Time, query, userID string, interval time.Duration) []DeleteRequest {
 	deleteRequests := make([]DeleteRequest, 0, endTime.Sub(startTime)/interval)
 	for start := startTime; start.Before(endTime); start = start.Add(interval) + 1 {
@@ -167,7 +225,19 @@ func (dm *DeleteRequestHandler) GetAllDeleteRequestsHandler(w http.ResponseWrite
 		return
 	}
 
-	deletesPerRequest := partitionByRequestID(deleteGroups)
+	progresses, err := dm.deleteRequestsStore.GetDeleteRequestsProgress(ctx, deleteGroups)
+	if err != nil {
+		level.Error(util_log.Logger).Log("msg", "error getting delete request progress from the store", "err", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	deletesWithProgress := make([]DeleteRequest, 0, len(deleteGroups))
+	for i, deleteRequest := range deleteGroups {
+		deleteRequest.Progress = &progresses[i]
+		deletesWithProgress = append(deletesWithProgress, deleteRequest)
+	}
+
+	deletesPerRequest := partitionByRequestID(deletesWithProgress)
 	deleteRequests := mergeDeletes(deletesPerRequest)
 
 	sort.Slice(deleteRequests, func(i, j int) bool {
@@ -188,6 +258,7 @@ func mergeDeletes(groups map[string][]DeleteRequest) []DeleteRequest {
 		newDelete.StartTime = startTime
 		newDelete.EndTime = endTime
 		newDelete.Status = status
+		newDelete.Progress = mergeProgress(deletes)
 
 		mergedRequests = append(mergedRequests, newDelete)
 	}
@@ -218,6 +289,44 @@ func mergeData(deletes []DeleteRequest) (model.Time, model.Time, DeleteRequestSt
 	return startTime, endTime, deleteRequestStatus(numProcessed, len(deletes))
 }
 
+// mergeProgress merges the progress of the delete requests of a group. A table is processed for the group
+// only when it is processed for all the requests of the group spanning it.
+func mergeProgress(deletes []DeleteRequest) *DeleteRequestProgress {
+	merged := DeleteRequestProgress{Tables: map[string]TableStatus{}}
+	for _, del := range deletes {
+		if del.Progress == nil {
+			continue
+		}
+
+		for tableName, status := range del.Progress.Tables {
+			if current, ok := merged.Tables[tableName]; !ok || tableStatusPriority(status) > tableStatusPriority(current) {
+				merged.Tables[tableName] = status
+			}
+		}
+		merged.ChunksDeleted += del.Progress.ChunksDeleted
+		merged.LinesDeleted += del.Progress.LinesDeleted
+	}
+
+	// requests processed before their progress was tracked have none.
+	if len(merged.Tables) == 0 && merged.ChunksDeleted == 0 && merged.LinesDeleted == 0 {
+		return nil
+	}
+
+	merged.updateTableCounts()
+	return &merged
+}
+
+func tableStatusPriority(status TableStatus) int {
+	switch status {
+	case TableStatusFailed:
+		return 2
+	case TableStatusPending:
+		return 1
+	default:
+		return 0
+	}
+}
+
 func deleteRequestStatus(processed, total int) DeleteRequestStatus {
 	if processed == 0 {
 		return StatusReceived
@@ -274,6 +383,79 @@ func (dm *DeleteRequestHandler) CancelDeleteRequestHandler(w http.ResponseWriter
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// RequeueDeleteRequestHandler requeues the tables which failed, or were not processed, for a delete request
+// so that they are processed by the next compactions. The tables already processed are not processed again.
+func (dm *DeleteRequestHandler) RequeueDeleteRequestHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, err := tenant.TenantID(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	params := r.URL.Query()
+	requestID := params.Get("request_id")
+	deleteRequests, err := dm.deleteRequestsStore.GetDeleteRequestGroup(ctx, userID, requestID)
+	if err != nil {
+		if errors.Is(err, ErrDeleteRequestNotFound) {
+			http.Error(w, "could not find delete request with given id", http.StatusNotFound)
+			return
+		}
+
+		level.Error(util_log.Logger).Log("msg", "error getting delete request from the store", "err", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	progresses, err := dm.deleteRequestsStore.GetDeleteRequestsProgress(ctx, deleteRequests)
+	if err != nil {
+		level.Error(util_log.Logger).Log("msg", "error getting delete request progress from the store", "err", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	requeued := 0
+	for i, deleteRequest := range deleteRequests {
+		progress := progresses[i]
+		if progress.TablesTotal == progress.TablesProcessed {
+			continue
+		}
+
+		failedTables := DeleteRequestProgress{Tables: map[string]TableStatus{}, ChunksDeleted: progress.ChunksDeleted, LinesDeleted: progress.LinesDeleted}
+		for tableName, status := range progress.Tables {
+			if status == TableStatusFailed {
+				failedTables.Tables[tableName] = TableStatusPending
+			}
+		}
+		if err := dm.deleteRequestsStore.UpdateDeleteRequestProgress(ctx, deleteRequest, failedTables); err != nil {
+			level.Error(util_log.Logger).Log("msg", "error requeuing the delete request", "err", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if deleteRequest.Status == StatusProcessed {
+			if err := dm.deleteRequestsStore.UpdateStatus(ctx, deleteRequest, StatusReceived); err != nil {
+				level.Error(util_log.Logger).Log("msg", "error requeuing the delete request", "err", err)
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+		requeued++
+	}
+
+	if requeued == 0 {
+		http.Error(w, "delete request has no failed or unprocessed tables to requeue", http.StatusBadRequest)
+		return
+	}
+
+	level.Info(util_log.Logger).Log(
+		"msg", "delete request for user requeued",
+		"delete_request_id", requestID,
+		"user", userID,
+	)
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func filterProcessed(reqs []DeleteRequest) []DeleteRequest {
 	var unprocessed []DeleteRequest
 	for _, r := range reqs {
@@ -23,7 +23,7 @@ import (
 func TestAddDeleteRequestHandler(t *testing.T) {
 	t.Run("it adds the delete request to the store", func(t *testing.T) {
 		store := &mockDeleteRequestsStore{}
-		h := NewDeleteRequestHandler(store, 0, nil)
+		h := NewDeleteRequestHandler(store, nil, 0, nil)
 
 		req := buildRequest("org-id", `{foo="bar"}`, "0000000000", "0000000001")
 
@@ -40,7 +40,7 @@ func TestAddDeleteRequestHandler(t *testing.T) {
 
 	t.Run("an error is returned if adding delete request group returned zero", func(t *testing.T) {
 		store := &mockDeleteRequestsStore{returnZeroDeleteRequests: true}
-		h := NewDeleteRequestHandler(store, 0, nil)
+		h := NewDeleteRequestHandler(store, nil, 0, nil)
 
 		req := buildRequest("org-id", `{foo="bar"}`, "0000000000", "0000000001")
 
@@ -52,7 +52,7 @@ func TestAddDeleteRequestHandler(t *testing.T) {
 
 	t.Run("it shards deletes based on a query param", func(t *testing.T) {
 		store := &mockDeleteRequestsStore{}
-		h := NewDeleteRequestHandler(store, 0, nil)
+		h := NewDeleteRequestHandler(store, nil, 0, nil)
 
 		from := model.TimeFromUnix(model.Now().Add(-3 * time.Hour).Unix())
 		to := model.TimeFromUnix(from.Add(3 * time.Hour).Unix())
@@ -82,7 +82,7 @@ func TestAddDeleteRequestHandler(t *testing.T) {
 
 	t.Run("it uses the default for sharding when the query param isn't present", func(t *testing.T) {
 		store := &mockDeleteRequestsStore{}
-		h := NewDeleteRequestHandler(store, time.Hour, nil)
+		h := NewDeleteRequestHandler(store, nil, time.Hour, nil)
 
 		from := model.TimeFromUnix(model.Now().Add(-3 * time.Hour).Unix())
 		to := model.TimeFromUnix(from.Add(3 * time.Hour).Unix())
@@ -109,7 +109,7 @@ func TestAddDeleteRequestHandler(t *testing.T) {
 
 	t.Run("it works with RFC3339", func(t *testing.T) {
 		store := &mockDeleteRequestsStore{}
-		h := NewDeleteRequestHandler(store, 0, nil)
+		h := NewDeleteRequestHandler(store, nil, 0, nil)
 
 		req := buildRequest("org-id", `{foo="bar"}`, "2006-01-02T15:04:05Z", "2006-01-03T15:04:05Z")
 
@@ -126,7 +126,7 @@ func TestAddDeleteRequestHandler(t *testing.T) {
 
 	t.Run("it fills in end time if blank", func(t *testing.T) {
 		store := &mockDeleteRequestsStore{}
-		h := NewDeleteRequestHandler(store, 0, nil)
+		h := NewDeleteRequestHandler(store, nil, 0, nil)
 
 		req := buildRequest("org-id", `{foo="bar"}`, "0000000000", "")
 
@@ -143,7 +143,7 @@ func TestAddDeleteRequestHandler(t *testing.T) {
 
 	t.Run("it returns 500 when the delete store errors", func(t *testing.T) {
 		store := &mockDeleteRequestsStore{addErr: errors.New("something bad")}
-		h := NewDeleteRequestHandler(store, 0, nil)
+		h := NewDeleteRequestHandler(store, nil, 0, nil)
 
 		req := buildRequest("org-id", `{foo="bar"}`, "0000000000", "0000000001")
 
@@ -153,7 +153,7 @@ func TestAddDeleteRequestHandler(t *testing.T) {
 	})
 
 	t.Run("Validation", func(t *testing.T) {
-		h := NewDeleteRequestHandler(&mockDeleteRequestsStore{}, time.Minute, nil)
+		h := NewDeleteRequestHandler(&mockDeleteRequestsStore{}, nil, time.Minute, nil)
 
 		for _, tc := range []struct {
 			orgID, query, startTime, endTime, interval, error string
@@ -197,7 +197,7 @@ func TestCancelDeleteRequestHandler(t *testing.T) {
 		store := &mockDeleteRequestsStore{}
 		store.getResult = stored
 
-		h := NewDeleteRequestHandler(store, 0, nil)
+		h := NewDeleteRequestHandler(store, nil, 0, nil)
 
 		req := buildRequest("org-id", ``, "", "")
 		params := req.URL.Query()
@@ -224,7 +224,7 @@ func TestCancelDeleteRequestHandler(t *testing.T) {
 		store := &mockDeleteRequestsStore{}
 		store.getResult = stored
 
-		h := NewDeleteRequestHandler(store, 0, nil)
+		h := NewDeleteRequestHandler(store, nil, 0, nil)
 
 		req := buildRequest("org-id", ``, "", "")
 		params := req.URL.Query()
@@ -242,7 +242,7 @@ func TestCancelDeleteRequestHandler(t *testing.T) {
 	t.Run("error getting from store", func(t *testing.T) {
 		store := &mockDeleteRequestsStore{}
 		store.getErr = errors.New("something bad")
-		h := NewDeleteRequestHandler(store, 0, nil)
+		h := NewDeleteRequestHandler(store, nil, 0, nil)
 
 		req := buildRequest("org id", ``, "", "")
 		params := req.URL.Query()
@@ -262,7 +262,7 @@ func TestCancelDeleteRequestHandler(t *testing.T) {
 		store.getResult = stored
 		store.removeErr = errors.New("something bad")
 
-		h := NewDeleteRequestHandler(store, 0, nil)
+		h := NewDeleteRequestHandler(store, nil, 0, nil)
 
 		req := buildRequest("org-id", ``, "", "")
 		params := req.URL.Query()
@@ -278,7 +278,7 @@ func TestCancelDeleteRequestHandler(t *testing.T) {
 
 	t.Run("Validation", func(t *testing.T) {
 		t.Run("no org id", func(t *testing.T) {
-			h := NewDeleteRequestHandler(&mockDeleteRequestsStore{}, 0, nil)
+			h := NewDeleteRequestHandler(&mockDeleteRequestsStore{}, nil, 0, nil)
 
 			req := buildRequest("", ``, "", "")
 			params := req.URL.Query()
@@ -293,7 +293,7 @@ func TestCancelDeleteRequestHandler(t *testing.T) {
 		})
 
 		t.Run("request not found", func(t *testing.T) {
-			h := NewDeleteRequestHandler(&mockDeleteRequestsStore{getErr: ErrDeleteRequestNotFound}, 0, nil)
+			h := NewDeleteRequestHandler(&mockDeleteRequestsStore{getErr: ErrDeleteRequestNotFound}, nil, 0, nil)
 
 			req := buildRequest("org-id", ``, "", "")
 			params := req.URL.Query()
@@ -312,7 +312,7 @@ func TestCancelDeleteRequestHandler(t *testing.T) {
 			store := &mockDeleteRequestsStore{}
 			store.getResult = stored
 
-			h := NewDeleteRequestHandler(store, 0, nil)
+			h := NewDeleteRequestHandler(store, nil, 0, nil)
 
 			req := buildRequest("org-id", ``, "", "")
 			params := req.URL.Query()
@@ -332,7 +332,7 @@ func TestGetAllDeleteRequestsHandler(t *testing.T) {
 	t.Run("it gets all the delete requests for the user", func(t *testing.T) {
 		store := &mockDeleteRequestsStore{}
 		store.getAllResult = []DeleteRequest{{RequestID: "test-request-1", Status: StatusReceived}, {RequestID: "test-request-2", Status: StatusReceived}}
-		h := NewDeleteRequestHandler(store, 0, nil)
+		h := NewDeleteRequestHandler(store, nil, 0, nil)
 
 		req := buildRequest("org-id", ``, "", "")
 
@@ -355,7 +355,7 @@ func TestGetAllDeleteRequestsHandler(t *testing.T) {
 			{RequestID: "test-request-2", CreatedAt: now.Add(time.Minute), StartTime: now.Add(30 * time.Minute), EndTime: now.Add(90 * time.Minute)},
 			{RequestID: "test-request-1", CreatedAt: now, StartTime: now.Add(time.Hour), EndTime: now.Add(2 * time.Hour)},
 		}
-		h := NewDeleteRequestHandler(store, 0, nil)
+		h := NewDeleteRequestHandler(store, nil, 0, nil)
 
 		req := buildRequest("org-id", ``, "", "")
 
@@ -385,7 +385,7 @@ func TestGetAllDeleteRequestsHandler(t *testing.T) {
 			{RequestID: "test-request-2", CreatedAt: now.Add(time.Minute), Status: StatusProcessed},
 			{RequestID: "test-request-3", CreatedAt: now.Add(2 * time.Minute), Status: StatusReceived},
 		}
-		h := NewDeleteRequestHandler(store, 0, nil)
+		h := NewDeleteRequestHandler(store, nil, 0, nil)
 
 		req := buildRequest("org-id", ``, "", "")
 
@@ -405,10 +405,49 @@ func TestGetAllDeleteRequestsHandler(t *testing.T) {
 		}, result)
 	})
 
+	t.Run("it merges the progress of the requests with the same requestID", func(t *testing.T) {
+		store := &mockDeleteRequestsStore{}
+		store.getAllResult = []DeleteRequest{
+			{RequestID: "test-request-1", UserID: "org-id", SequenceNum: 0, CreatedAt: now, Status: StatusProcessed},
+			{RequestID: "test-request-1", UserID: "org-id", SequenceNum: 1, CreatedAt: now, Status: StatusReceived},
+			{RequestID: "test-request-2", UserID: "org-id", CreatedAt: now.Add(time.Minute), Status: StatusProcessed},
+		}
+		store.progress = map[string]DeleteRequestProgress{
+			"org-id:test-request-1": {
+				Tables:        map[string]TableStatus{"index_1": TableStatusProcessed, "index_2": TableStatusProcessed},
+				ChunksDeleted: 2,
+				LinesDeleted:  20,
+			},
+			"org-id:test-request-1:1": {
+				Tables:        map[string]TableStatus{"index_2": TableStatusFailed, "index_3": TableStatusProcessed},
+				ChunksDeleted: 1,
+			},
+		}
+		h := NewDeleteRequestHandler(store, nil, 0, nil)
+
+		w := httptest.NewRecorder()
+		h.GetAllDeleteRequestsHandler(w, buildRequest("org-id", ``, "", ""))
+		require.Equal(t, w.Code, http.StatusOK)
+
+		var result []DeleteRequest
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		require.Equal(t, []DeleteRequest{
+			{RequestID: "test-request-1", CreatedAt: now, Status: "50% Complete", Progress: &DeleteRequestProgress{
+				TablesTotal:     3,
+				TablesProcessed: 2,
+				Tables:          map[string]TableStatus{"index_1": TableStatusProcessed, "index_2": TableStatusFailed, "index_3": TableStatusProcessed},
+				ChunksDeleted:   3,
+				LinesDeleted:    20,
+			}},
+			// requests without progress don't report any
+			{RequestID: "test-request-2", CreatedAt: now.Add(time.Minute), Status: StatusProcessed},
+		}, result)
+	})
+
 	t.Run("error getting from store", func(t *testing.T) {
 		store := &mockDeleteRequestsStore{}
 		store.getAllErr = errors.New("something bad")
-		h := NewDeleteRequestHandler(store, 0, nil)
+		h := NewDeleteRequestHandler(store, nil, 0, nil)
 
 		req := buildRequest("org id", ``, "", "")
 		params := req.URL.Query()
@@ -424,7 +463,7 @@ func TestGetAllDeleteRequestsHandler(t *testing.T) {
 
 	t.Run("validation", func(t *testing.T) {
 		t.Run("no org id", func(t *testing.T) {
-			h := NewDeleteRequestHandler(&mockDeleteRequestsStore{}, 0, nil)
+			h := NewDeleteRequestHandler(&mockDeleteRequestsStore{}, nil, 0, nil)
 
 			req := buildRequest("", ``, "", "")
 
@@ -437,6 +476,75 @@ func TestGetAllDeleteRequestsHandler(t *testing.T) {
 	})
 }
 
+func TestRequeueDeleteRequestHandler(t *testing.T) {
+	t.Run("it requeues the failed and unprocessed tables", func(t *testing.T) {
+		store := &mockDeleteRequestsStore{}
+		store.getResult = []DeleteRequest{
+			{RequestID: "test-request", UserID: "org-id", SequenceNum: 0, Status: StatusProcessed},
+			{RequestID: "test-request", UserID: "org-id", SequenceNum: 1, Status: StatusProcessed},
+			{RequestID: "test-request", UserID: "org-id", SequenceNum: 2, Status: StatusReceived},
+		}
+		store.progress = map[string]DeleteRequestProgress{
+			"org-id:test-request": {
+				TablesTotal: 2, TablesProcessed: 1,
+				Tables:       map[string]TableStatus{"index_1": TableStatusProcessed, "index_2": TableStatusFailed},
+				LinesDeleted: 10,
+			},
+			"org-id:test-request:1": {
+				TablesTotal: 1, TablesProcessed: 1,
+				Tables: map[string]TableStatus{"index_2": TableStatusProcessed},
+			},
+			"org-id:test-request:2": {
+				TablesTotal: 1,
+				Tables:      map[string]TableStatus{"index_3": TableStatusFailed},
+			},
+		}
+		h := NewDeleteRequestHandler(store, nil, 0, nil)
+
+		req := buildRequest("org-id", ``, "", "")
+		params := req.URL.Query()
+		params.Set("request_id", "test-request")
+		req.URL.RawQuery = params.Encode()
+
+		w := httptest.NewRecorder()
+		h.RequeueDeleteRequestHandler(w, req)
+
+		require.Equal(t, http.StatusNoContent, w.Code)
+		require.Equal(t, "test-request", store.getID)
+		// only the processed request with a failed table is set back to received
+		require.Equal(t, map[string]DeleteRequestStatus{"org-id:test-request": StatusReceived}, store.updatedStatus)
+		require.Equal(t, DeleteRequestProgress{
+			Tables:       map[string]TableStatus{"index_2": TableStatusPending},
+			LinesDeleted: 10,
+		}, store.progress["org-id:test-request"])
+		require.Equal(t, DeleteRequestProgress{
+			Tables: map[string]TableStatus{"index_3": TableStatusPending},
+		}, store.progress["org-id:test-request:2"])
+	})
+
+	t.Run("it fails when there is nothing to requeue", func(t *testing.T) {
+		store := &mockDeleteRequestsStore{}
+		store.getResult = []DeleteRequest{{RequestID: "test-request", UserID: "org-id", Status: StatusProcessed}}
+		h := NewDeleteRequestHandler(store, nil, 0, nil)
+
+		w := httptest.NewRecorder()
+		h.RequeueDeleteRequestHandler(w, buildRequest("org-id", ``, "", ""))
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		require.Empty(t, store.updatedStatus)
+	})
+
+	t.Run("it fails when the request does not exist", func(t *testing.T) {
+		store := &mockDeleteRequestsStore{getErr: ErrDeleteRequestNotFound}
+		h := NewDeleteRequestHandler(store, nil, 0, nil)
+
+		w := httptest.NewRecorder()
+		h.RequeueDeleteRequestHandler(w, buildRequest("org-id", ``, "", ""))
+
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
 func buildRequest(orgID, query, start, end string) *http.Request {
 	var req *http.Request
 	if orgID == "" {
@@ -132,7 +132,11 @@ func (is *indexSet) GetSourceFile(indexFile storage.IndexFile) (string, error) {
 	err := storage.DownloadFileFromStorage(dst, storage.IsCompressedFile(indexFile.Name),
 		false, storage.LoggerWithFilename(is.logger, indexFile.Name),
 		func() (io.ReadCloser, error) {
-			return is.baseIndexSet.GetFile(is.ctx, is.tableName, is.userID, indexFile.Name)
+			rc, err := is.baseIndexSet.GetFile(is.ctx, is.tableName, is.userID, indexFile.Name)
+			if err != nil {
+				return nil, err
+			}
+			return contextReadCloser{ctx: is.ctx, ReadCloser: rc}, nil
 		})
 	if err != nil {
 		return "", err
@@ -141,6 +145,20 @@ func (is *indexSet) GetSourceFile(indexFile storage.IndexFile) (string, error) {
 	return dst, nil
 }
 
+// contextReadCloser stops reading once its context is done, so that the download of a large file
+// stops when the operation it is downloaded for is cancelled.
+type contextReadCloser struct {
+	ctx context.Context
+	io.ReadCloser
+}
+
+func (r contextReadCloser) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.ReadCloser.Read(p)
+}
+
 func (is *indexSet) GetLogger() log.Logger {
 	return is.logger
 }
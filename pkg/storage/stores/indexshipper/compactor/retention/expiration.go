@@ -1,13 +1,19 @@
 package retention
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
 	"time"
 
 	"github.com/go-kit/log/level"
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/model/labels"
 
+	"github.com/grafana/loki/pkg/logql/syntax"
+	chunk_util "github.com/grafana/loki/pkg/storage/chunk/client/util"
 	"github.com/grafana/loki/pkg/util/filter"
 	util_log "github.com/grafana/loki/pkg/util/log"
 	"github.com/grafana/loki/pkg/validation"
@@ -31,9 +37,37 @@ type ExpirationChecker interface {
 	DropFromIndex(ref ChunkEntry, tableEndTime model.Time, now model.Time) bool
 }
 
+// lineRetentionStartsFile is the file storing, for each line retention rule of each tenant, the start of the
+// last completed retention run that applied it, so that the chunks already processed are not rewritten after a restart.
+const lineRetentionStartsFile = "line_retention_starts.json"
+
+// lineRetentionRule identifies a line retention rule of a tenant. A rule whose selector
+// or period changes is a new rule, which is applied again to all the chunks.
+type lineRetentionRule struct {
+	UserID   string         `json:"user"`
+	Selector string         `json:"selector"`
+	Period   model.Duration `json:"period"`
+}
+
+type lineRetentionStart struct {
+	lineRetentionRule
+	Start model.Time `json:"start"`
+}
+
 type expirationChecker struct {
 	tenantsRetention         *TenantsRetention
 	latestRetentionStartTime latestRetentionStartTime
+
+	mtx sync.Mutex
+	// lineRetentionStarts holds the start of the last completed retention run of each line retention rule.
+	// The chunks that were already out of the period of the rule at that time have had their matching
+	// lines deleted by that run.
+	lineRetentionStarts map[lineRetentionRule]model.Time
+	// appliedLineRetentionRules holds the line retention rules applied by the current run.
+	appliedLineRetentionRules map[lineRetentionRule]struct{}
+	currentLineRetentionStart model.Time
+	timedOut                  bool
+	workingDirectory          string
 }
 
 type Limits interface {
@@ -43,17 +77,143 @@ type Limits interface {
 	DefaultLimits() *validation.Limits
 }
 
-func NewExpirationChecker(limits Limits) ExpirationChecker {
-	return &expirationChecker{
-		tenantsRetention: NewTenantsRetention(limits),
+// NewExpirationChecker returns an expiration checker applying the retention rules of the limits.
+// The start of the last completed run of each line retention rule is persisted in the working directory,
+// unless it is empty.
+func NewExpirationChecker(limits Limits, workingDirectory string) ExpirationChecker {
+	e := &expirationChecker{
+		tenantsRetention:    NewTenantsRetention(limits),
+		workingDirectory:    workingDirectory,
+		lineRetentionStarts: map[lineRetentionRule]model.Time{},
+	}
+	if workingDirectory != "" {
+		lineRetentionStarts, err := loadLineRetentionStarts(workingDirectory)
+		if err != nil {
+			// The line retention rules are checked again for all the chunks, which are only rewritten if they still have matching lines.
+			level.Warn(util_log.Logger).Log("msg", "failed to load the start of the last line retention runs", "err", err)
+		} else {
+			e.lineRetentionStarts = lineRetentionStarts
+		}
+	}
+	return e
+}
+
+func loadLineRetentionStarts(workingDirectory string) (map[lineRetentionRule]model.Time, error) {
+	starts := map[lineRetentionRule]model.Time{}
+	b, err := os.ReadFile(filepath.Join(workingDirectory, lineRetentionStartsFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return starts, nil
+		}
+		return nil, err
+	}
+	var entries []lineRetentionStart
+	if err := json.Unmarshal(b, &entries); err != nil {
+		return nil, fmt.Errorf("invalid line retention starts: %w", err)
+	}
+	for _, entry := range entries {
+		starts[entry.lineRetentionRule] = entry.Start
+	}
+	return starts, nil
+}
+
+// storeLineRetentionStarts atomically replaces the file storing the start of the last completed run of each line retention rule.
+func storeLineRetentionStarts(workingDirectory string, starts map[lineRetentionRule]model.Time) error {
+	if err := chunk_util.EnsureDirectory(workingDirectory); err != nil {
+		return err
+	}
+	entries := make([]lineRetentionStart, 0, len(starts))
+	for rule, start := range starts {
+		entries = append(entries, lineRetentionStart{lineRetentionRule: rule, Start: start})
+	}
+	b, err := json.Marshal(entries)
+	if err != nil {
+		return err
 	}
+	path := filepath.Join(workingDirectory, lineRetentionStartsFile)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o640); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
 }
 
 // Expired tells if a ref chunk is expired based on retention rules.
+// A chunk which is not expired can still have lines expired by the line retention
+// rules, in which case the returned filter deletes them.
 func (e *expirationChecker) Expired(ref ChunkEntry, now model.Time) (bool, []IntervalFilter) {
 	userID := unsafeGetString(ref.UserID)
 	period := e.tenantsRetention.RetentionPeriodFor(userID, ref.Labels)
-	return now.Sub(ref.Through) > period, nil
+	if now.Sub(ref.Through) > period {
+		return true, nil
+	}
+	return e.expiredLines(userID, ref, now)
+}
+
+// expiredLines applies the line retention rules matching the stream of the chunk,
+// once all its lines are out of their period. A rule is only applied to the chunks
+// that went out of its period since the last completed retention run that applied it,
+// so that chunks are not rewritten again by every run.
+func (e *expirationChecker) expiredLines(userID string, ref ChunkEntry, now model.Time) (bool, []IntervalFilter) {
+	var filters []filter.Func
+	for _, streamRetention := range e.tenantsRetention.limits.StreamRetention(userID) {
+		if streamRetention.LineFilter == nil || !labels.Selector(streamRetention.Matchers).Matches(ref.Labels) {
+			continue
+		}
+
+		period := time.Duration(streamRetention.Period)
+		start := e.applyLineRetentionRule(ref.UserID, streamRetention)
+		if now.Sub(ref.Through) <= period || start.Sub(ref.Through) > period {
+			continue
+		}
+
+		f, err := LineFilterFunc(streamRetention.LineFilter, ref.Labels)
+		if err != nil {
+			// The selector is checked when the limits are validated, so this error should not occur.
+			level.Error(util_log.Logger).Log("msg", "unexpected error getting line retention filter", "selector", streamRetention.Selector, "user", userID, "err", err)
+			continue
+		}
+		filters = append(filters, f)
+	}
+
+	if len(filters) == 0 {
+		return false, nil
+	}
+	return true, []IntervalFilter{
+		{
+			Interval: model.Interval{
+				Start: ref.From,
+				End:   ref.Through,
+			},
+			Filter: func(line string) bool {
+				for _, f := range filters {
+					if f(line) {
+						return true
+					}
+				}
+				return false
+			},
+		},
+	}
+}
+
+// applyLineRetentionRule records that the current run applies the line retention rule of the tenant,
+// and returns the start of the last completed run that applied it.
+func (e *expirationChecker) applyLineRetentionRule(userID []byte, streamRetention validation.StreamRetention) model.Time {
+	rule := lineRetentionRule{
+		UserID:   unsafeGetString(userID),
+		Selector: streamRetention.Selector,
+		Period:   streamRetention.Period,
+	}
+
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	if _, ok := e.appliedLineRetentionRules[rule]; !ok && e.appliedLineRetentionRules != nil {
+		// the user ID refers to the buffer of the chunk entry.
+		rule.UserID = string(userID)
+		e.appliedLineRetentionRules[rule] = struct{}{}
+	}
+	return e.lineRetentionStarts[rule]
 }
 
 // DropFromIndex tells if it is okay to drop the chunk entry from index table.
@@ -66,14 +226,48 @@ func (e *expirationChecker) DropFromIndex(ref ChunkEntry, tableEndTime model.Tim
 }
 
 func (e *expirationChecker) MarkPhaseStarted() {
-	e.latestRetentionStartTime = findLatestRetentionStartTime(model.Now(), e.tenantsRetention.limits)
+	now := model.Now()
+	e.mtx.Lock()
+	e.currentLineRetentionStart = now
+	e.appliedLineRetentionRules = map[lineRetentionRule]struct{}{}
+	e.timedOut = false
+	e.mtx.Unlock()
+	e.latestRetentionStartTime = findLatestRetentionStartTime(now, e.tenantsRetention.limits)
 	level.Info(util_log.Logger).Log("msg", fmt.Sprintf("overall smallest retention period %v, default smallest retention period %v",
 		e.latestRetentionStartTime.overall, e.latestRetentionStartTime.defaults))
 }
 
-func (e *expirationChecker) MarkPhaseFailed()   {}
-func (e *expirationChecker) MarkPhaseTimedOut() {}
-func (e *expirationChecker) MarkPhaseFinished() {}
+func (e *expirationChecker) MarkPhaseFailed() {}
+
+func (e *expirationChecker) MarkPhaseTimedOut() {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	e.timedOut = true
+}
+
+func (e *expirationChecker) MarkPhaseFinished() {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	// Some chunks were not processed when the run timed out, so the next run
+	// has to apply the line retention rules to them.
+	if e.timedOut || e.appliedLineRetentionRules == nil {
+		return
+	}
+	// The rules which were not applied by this run are forgotten, they are applied
+	// again to all the chunks if they are used later.
+	starts := make(map[lineRetentionRule]model.Time, len(e.appliedLineRetentionRules))
+	for rule := range e.appliedLineRetentionRules {
+		starts[rule] = e.currentLineRetentionStart
+	}
+	e.lineRetentionStarts = starts
+	e.appliedLineRetentionRules = nil
+	if e.workingDirectory == "" {
+		return
+	}
+	if err := storeLineRetentionStarts(e.workingDirectory, e.lineRetentionStarts); err != nil {
+		level.Error(util_log.Logger).Log("msg", "failed to store the start of the last line retention runs", "err", err)
+	}
+}
 
 func (e *expirationChecker) IntervalMayHaveExpiredChunks(interval model.Interval, userID string) bool {
 	// when userID is empty, it means we are checking for common index table. In this case we use e.overallLatestRetentionStartTime.
@@ -132,6 +326,10 @@ func (tr *TenantsRetention) RetentionPeriodFor(userID string, lbs labels.Labels)
 	)
 Outer:
 	for _, streamRetention := range streamRetentions {
+		// line retention rules only apply to some lines of the stream.
+		if streamRetention.LineFilter != nil {
+			continue
+		}
 		for _, m := range streamRetention.Matchers {
 			if !m.Matches(lbs.Get(m.Name)) {
 				continue Outer
@@ -157,6 +355,21 @@ Outer:
 	return globalRetention
 }
 
+// LineFilterFunc returns a filter function that returns true for the lines of the stream
+// matching the pipeline of the log selector.
+func LineFilterFunc(logSelectorExpr syntax.LogSelectorExpr, lbls labels.Labels) (filter.Func, error) {
+	p, err := logSelectorExpr.Pipeline()
+	if err != nil {
+		return nil, err
+	}
+
+	f := p.ForStream(lbls).ProcessString
+	return func(s string) bool {
+		result, _, skip := f(0, s)
+		return len(result) != 0 || skip
+	}, nil
+}
+
 type latestRetentionStartTime struct {
 	// defaults holds latest retention start time considering only default retention config.
 	// It is used to determine if user index table may have any expired chunks when the user does not have any custom retention config set.
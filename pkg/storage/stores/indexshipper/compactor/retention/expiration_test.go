@@ -8,6 +8,7 @@ import (
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/loki/pkg/logql/syntax"
 	"github.com/grafana/loki/pkg/validation"
 )
 
@@ -66,7 +67,7 @@ func Test_expirationChecker_Expired(t *testing.T) {
 				},
 			},
 		},
-	})
+	}, "")
 	tests := []struct {
 		name string
 		ref  ChunkEntry
@@ -88,6 +89,134 @@ func Test_expirationChecker_Expired(t *testing.T) {
 	}
 }
 
+func Test_expirationChecker_ExpiredLines(t *testing.T) {
+	lineRetention := func(selector string, period time.Duration) validation.StreamRetention {
+		expr, err := syntax.ParseLogSelector(selector, false)
+		require.NoError(t, err)
+		return validation.StreamRetention{Period: model.Duration(period), Selector: selector, Matchers: expr.Matchers(), LineFilter: expr}
+	}
+	limits := &fakeLimits{
+		perTenant: map[string]retentionLimit{
+			"1": {
+				retentionPeriod: 10 * time.Hour,
+				streamRetention: []validation.StreamRetention{
+					lineRetention(`{foo="bar"} |= "debug"`, time.Hour),
+					lineRetention(`{foo="bar"} |= "info"`, 2*time.Hour),
+				},
+			},
+		},
+	}
+	workingDir := t.TempDir()
+	e := NewExpirationChecker(limits, workingDir).(*expirationChecker)
+	now := model.Now()
+	e.MarkPhaseStarted()
+
+	for _, tc := range []struct {
+		name            string
+		ref             ChunkEntry
+		expired         bool
+		hasFilter       bool
+		deletedLines    []string
+		nonDeletedLines []string
+	}{
+		{
+			name:    "expired stream",
+			ref:     newChunkEntry("1", `{foo="bar"}`, now.Add(-12*time.Hour), now.Add(-11*time.Hour)),
+			expired: true,
+		},
+		{
+			name: "stream not matching the line retention rules",
+			ref:  newChunkEntry("1", `{foo="buzz"}`, now.Add(-5*time.Hour), now.Add(-4*time.Hour)),
+		},
+		{
+			name: "chunk partially out of the line retention period",
+			ref:  newChunkEntry("1", `{foo="bar"}`, now.Add(-2*time.Hour), now.Add(-30*time.Minute)),
+		},
+		{
+			name:            "chunk out of the period of a line retention rule",
+			ref:             newChunkEntry("1", `{foo="bar"}`, now.Add(-90*time.Minute), now.Add(-80*time.Minute)),
+			expired:         true,
+			hasFilter:       true,
+			deletedLines:    []string{"level=debug msg=hello"},
+			nonDeletedLines: []string{"level=info msg=hello", "level=error msg=hello"},
+		},
+		{
+			name:            "chunk out of the period of all line retention rules",
+			ref:             newChunkEntry("1", `{foo="bar"}`, now.Add(-4*time.Hour), now.Add(-3*time.Hour)),
+			expired:         true,
+			hasFilter:       true,
+			deletedLines:    []string{"level=debug msg=hello", "level=info msg=hello"},
+			nonDeletedLines: []string{"level=error msg=hello"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			expired, filters := e.Expired(tc.ref, now)
+			require.Equal(t, tc.expired, expired)
+			if !tc.hasFilter {
+				require.Nil(t, filters)
+				return
+			}
+
+			require.Len(t, filters, 1)
+			require.Equal(t, model.Interval{Start: tc.ref.From, End: tc.ref.Through}, filters[0].Interval)
+			for _, line := range tc.deletedLines {
+				require.True(t, filters[0].Filter(line), line)
+			}
+			for _, line := range tc.nonDeletedLines {
+				require.False(t, filters[0].Filter(line), line)
+			}
+		})
+	}
+
+	// Once a retention run completed, only the chunks that went out of the
+	// period of a rule since then have their lines deleted.
+	e.MarkPhaseFinished()
+	require.Len(t, e.lineRetentionStarts, 2)
+	later := model.Now().Add(time.Hour)
+	e.MarkPhaseStarted()
+
+	expired, filters := e.Expired(newChunkEntry("1", `{foo="bar"}`, now.Add(-4*time.Hour), now.Add(-3*time.Hour)), later)
+	require.False(t, expired)
+	require.Nil(t, filters)
+
+	expired, filters = e.Expired(newChunkEntry("1", `{foo="bar"}`, now.Add(-90*time.Minute), now.Add(-80*time.Minute)), later)
+	require.True(t, expired)
+	require.Len(t, filters, 1)
+	require.False(t, filters[0].Filter("level=debug msg=hello"))
+	require.True(t, filters[0].Filter("level=info msg=hello"))
+	e.MarkPhaseFinished()
+
+	// A timed out run doesn't move the start of the line retention.
+	starts := e.lineRetentionStarts
+	e.MarkPhaseStarted()
+	expired, _ = e.Expired(newChunkEntry("1", `{foo="bar"}`, now.Add(-90*time.Minute), now.Add(-80*time.Minute)), later)
+	require.True(t, expired)
+	e.MarkPhaseTimedOut()
+	e.MarkPhaseFinished()
+	require.Equal(t, starts, e.lineRetentionStarts)
+
+	// The start of the line retention is kept after a restart.
+	restarted := NewExpirationChecker(limits, workingDir).(*expirationChecker)
+	require.Equal(t, e.lineRetentionStarts, restarted.lineRetentionStarts)
+	restarted.MarkPhaseStarted()
+	expired, filters = restarted.Expired(newChunkEntry("1", `{foo="bar"}`, now.Add(-4*time.Hour), now.Add(-3*time.Hour)), later)
+	require.False(t, expired)
+	require.Nil(t, filters)
+
+	// A rule added after a completed run applies to the chunks that were already out of its period.
+	limits.perTenant["1"] = retentionLimit{
+		retentionPeriod: 10 * time.Hour,
+		streamRetention: append(limits.perTenant["1"].streamRetention, lineRetention(`{foo="bar"} |= "warn"`, time.Hour)),
+	}
+	expired, filters = restarted.Expired(newChunkEntry("1", `{foo="bar"}`, now.Add(-4*time.Hour), now.Add(-3*time.Hour)), later)
+	require.True(t, expired)
+	require.Len(t, filters, 1)
+	require.True(t, filters[0].Filter("level=warn msg=hello"))
+	require.False(t, filters[0].Filter("level=debug msg=hello"))
+	restarted.MarkPhaseFinished()
+	require.Len(t, restarted.lineRetentionStarts, 3)
+}
+
 func TestFindLatestRetentionStartTime(t *testing.T) {
 	const dayDuration = 24 * time.Hour
 	now := model.Now()
@@ -176,20 +176,23 @@ func markForDelete(
 		seriesMap.Add(c.SeriesID, c.UserID, c.Labels)
 
 		// see if the chunk is deleted completely or partially
-		if expired, nonDeletedIntervalFilters := expiration.Expired(c, now); expired {
-			if len(nonDeletedIntervalFilters) > 0 {
-				wroteChunks, err := chunkRewriter.rewriteChunk(ctx, c, tableInterval, nonDeletedIntervalFilters)
-				if err != nil {
-					return false, fmt.Errorf("failed to rewrite chunk %s for intervals %+v with error %s", c.ChunkID, nonDeletedIntervalFilters, err)
-				}
-
-				if wroteChunks {
-					// we have re-written chunk to the storage so the table won't be empty and the series are still being referred.
-					empty = false
-					seriesMap.MarkSeriesNotDeleted(c.SeriesID, c.UserID)
-				}
+		expired, nonDeletedIntervalFilters := expiration.Expired(c, now)
+		if expired && len(nonDeletedIntervalFilters) > 0 {
+			wroteChunks, unchanged, err := chunkRewriter.rewriteChunk(ctx, c, tableInterval, nonDeletedIntervalFilters)
+			if err != nil {
+				return false, fmt.Errorf("failed to rewrite chunk %s for intervals %+v with error %s", c.ChunkID, nonDeletedIntervalFilters, err)
 			}
 
+			if unchanged {
+				// the filters don't delete any line of the chunk so it is kept as is.
+				expired = false
+			} else if wroteChunks {
+				// we have re-written chunk to the storage so the table won't be empty and the series are still being referred.
+				empty = false
+				seriesMap.MarkSeriesNotDeleted(c.SeriesID, c.UserID)
+			}
+		}
+		if expired {
 			modified = true
 
 			// Mark the chunk for deletion only if it is completely deleted, or this is the last table that the chunk is index in.
@@ -332,22 +335,25 @@ func newChunkRewriter(chunkClient client.Client, tableName string, chunkIndexer
 	}
 }
 
-func (c *chunkRewriter) rewriteChunk(ctx context.Context, ce ChunkEntry, tableInterval model.Interval, intervalFilters []IntervalFilter) (bool, error) {
+// rewriteChunk writes the parts of the chunk within the given intervals, without the lines deleted by their filters.
+// It returns whether chunks were written, and whether the chunk is unchanged because a single interval covers the
+// whole chunk and its filter doesn't delete any line, in which case nothing is written and the chunk must be kept.
+func (c *chunkRewriter) rewriteChunk(ctx context.Context, ce ChunkEntry, tableInterval model.Interval, intervalFilters []IntervalFilter) (bool, bool, error) {
 	userID := unsafeGetString(ce.UserID)
 	chunkID := unsafeGetString(ce.ChunkID)
 
 	chk, err := chunk.ParseExternalKey(userID, chunkID)
 	if err != nil {
-		return false, err
+		return false, false, err
 	}
 
 	chks, err := c.chunkClient.GetChunks(ctx, []chunk.Chunk{chk})
 	if err != nil {
-		return false, err
+		return false, false, err
 	}
 
 	if len(chks) != 1 {
-		return false, fmt.Errorf("expected 1 entry for chunk %s but found %d in storage", chunkID, len(chks))
+		return false, false, fmt.Errorf("expected 1 entry for chunk %s but found %d in storage", chunkID, len(chks))
 	}
 
 	wroteChunks := false
@@ -363,7 +369,11 @@ func (c *chunkRewriter) rewriteChunk(ctx context.Context, ce ChunkEntry, tableIn
 				// skip empty chunks
 				continue
 			}
-			return false, err
+			return false, false, err
+		}
+
+		if len(intervalFilters) == 1 && start <= ce.From && end >= ce.Through && newChunkData.Entries() == chks[0].Data.Entries() {
+			return false, true, nil
 		}
 
 		if start > tableInterval.End || end < tableInterval.Start {
@@ -372,7 +382,7 @@ func (c *chunkRewriter) rewriteChunk(ctx context.Context, ce ChunkEntry, tableIn
 
 		facade, ok := newChunkData.(*chunkenc.Facade)
 		if !ok {
-			return false, errors.New("invalid chunk type")
+			return false, false, errors.New("invalid chunk type")
 		}
 
 		newChunk := chunk.NewChunk(
@@ -384,23 +394,23 @@ func (c *chunkRewriter) rewriteChunk(ctx context.Context, ce ChunkEntry, tableIn
 
 		err = newChunk.Encode()
 		if err != nil {
-			return false, err
+			return false, false, err
 		}
 
 		uploadChunk, err := c.chunkIndexer.IndexChunk(newChunk)
 		if err != nil {
-			return false, err
+			return false, false, err
 		}
 
 		// upload chunk only if an entry was written
 		if uploadChunk {
 			err = c.chunkClient.PutChunks(ctx, []chunk.Chunk{newChunk})
 			if err != nil {
-				return false, err
+				return false, false, err
 			}
 			wroteChunks = true
 		}
 	}
 
-	return wroteChunks, nil
+	return wroteChunks, false, nil
 }
@@ -137,7 +137,7 @@ func Test_Retention(t *testing.T) {
 			store.Stop()
 
 			// marks and sweep
-			expiration := NewExpirationChecker(tt.limits)
+			expiration := NewExpirationChecker(tt.limits, "")
 			workDir := filepath.Join(t.TempDir(), "retention")
 			chunkClient := &mockChunkClient{deletedChunks: map[string]struct{}{}}
 			sweep, err := NewSweeper(workDir, chunkClient, 10, 0, nil)
@@ -192,11 +192,11 @@ func Test_EmptyTable(t *testing.T) {
 
 	tables := store.indexTables()
 	require.Len(t, tables, 1)
-	empty, _, err := markForDelete(context.Background(), 0, tables[0].name, noopWriter{}, tables[0], NewExpirationChecker(&fakeLimits{perTenant: map[string]retentionLimit{"1": {retentionPeriod: 0}, "2": {retentionPeriod: 0}}}), nil, util_log.Logger)
+	empty, _, err := markForDelete(context.Background(), 0, tables[0].name, noopWriter{}, tables[0], NewExpirationChecker(&fakeLimits{perTenant: map[string]retentionLimit{"1": {retentionPeriod: 0}, "2": {retentionPeriod: 0}}}, ""), nil, util_log.Logger)
 	require.NoError(t, err)
 	require.True(t, empty)
 
-	_, _, err = markForDelete(context.Background(), 0, tables[0].name, noopWriter{}, newTable("test"), NewExpirationChecker(&fakeLimits{}), nil, util_log.Logger)
+	_, _, err = markForDelete(context.Background(), 0, tables[0].name, noopWriter{}, newTable("test"), NewExpirationChecker(&fakeLimits{}, ""), nil, util_log.Logger)
 	require.Equal(t, err, errNoChunksFound)
 }
 
@@ -275,6 +275,7 @@ func TestChunkRewriter(t *testing.T) {
 		name                   string
 		chunk                  chunk.Chunk
 		rewriteIntervalFilters []IntervalFilter
+		unchanged              bool
 	}{
 		{
 			name:  "no rewrites",
@@ -371,6 +372,22 @@ func TestChunkRewriter(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:  "keep the chunk when the filter function removes no lines",
+			chunk: createChunk(t, "1", labels.Labels{labels.Label{Name: "foo", Value: "bar"}}, now.Add(-48*time.Hour), now),
+			rewriteIntervalFilters: []IntervalFilter{
+				{
+					Interval: model.Interval{
+						Start: now.Add(-48 * time.Hour),
+						End:   now,
+					},
+					Filter: func(s string) bool {
+						return false
+					},
+				},
+			},
+			unchanged: true,
+		},
 	} {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
@@ -381,15 +398,23 @@ func TestChunkRewriter(t *testing.T) {
 			for _, indexTable := range store.indexTables() {
 				cr := newChunkRewriter(store.chunkClient, indexTable.name, indexTable)
 
-				wroteChunks, err := cr.rewriteChunk(context.Background(), entryFromChunk(tt.chunk), ExtractIntervalFromTableName(indexTable.name), tt.rewriteIntervalFilters)
+				wroteChunks, unchanged, err := cr.rewriteChunk(context.Background(), entryFromChunk(tt.chunk), ExtractIntervalFromTableName(indexTable.name), tt.rewriteIntervalFilters)
 				require.NoError(t, err)
-				if len(tt.rewriteIntervalFilters) == 0 {
+				require.Equal(t, tt.unchanged, unchanged)
+				if len(tt.rewriteIntervalFilters) == 0 || tt.unchanged {
 					require.False(t, wroteChunks)
 				}
 			}
 
 			chunks := store.GetChunks(tt.chunk.UserID, tt.chunk.From, tt.chunk.Through, tt.chunk.Metric)
 
+			if tt.unchanged {
+				// only the source chunk should be there in the store
+				require.Len(t, chunks, 1)
+				require.Equal(t, getChunkID(tt.chunk.ChunkRef), getChunkID(chunks[0].ChunkRef))
+				return
+			}
+
 			// number of chunks should be the new re-written chunks + the source chunk
 			require.Len(t, chunks, len(tt.rewriteIntervalFilters)+1)
 			for _, ivf := range tt.rewriteIntervalFilters {
@@ -747,7 +772,7 @@ func TestMarkForDelete_DropChunkFromIndex(t *testing.T) {
 
 	for i, table := range tables {
 		empty, _, err := markForDelete(context.Background(), 0, table.name, noopWriter{}, table,
-			NewExpirationChecker(fakeLimits{perTenant: map[string]retentionLimit{"1": {retentionPeriod: retentionPeriod}}}), nil, util_log.Logger)
+			NewExpirationChecker(fakeLimits{perTenant: map[string]retentionLimit{"1": {retentionPeriod: retentionPeriod}}}, ""), nil, util_log.Logger)
 		require.NoError(t, err)
 		if i == 7 {
 			require.False(t, empty)
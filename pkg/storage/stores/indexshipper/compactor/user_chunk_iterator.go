@@ -0,0 +1,129 @@
+package compactor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/loki/pkg/storage/stores/indexshipper/compactor/deletion"
+	"github.com/grafana/loki/pkg/storage/stores/indexshipper/compactor/retention"
+	"github.com/grafana/loki/pkg/storage/stores/indexshipper/storage"
+	util_log "github.com/grafana/loki/pkg/util/log"
+)
+
+// userChunkIterator iterates over the chunks of a user by reading a local copy of the index files
+// of the tables, so it can run while the tables are compacted.
+// TSDB index files built by the ingesters are multi-tenant, so their chunks are only seen once compacted.
+// At most maxTables tables are read per iteration, 0 means no limit.
+type userChunkIterator struct {
+	compactor *Compactor
+	maxTables int
+}
+
+func (i userChunkIterator) ForEachUserChunk(ctx context.Context, userID string, interval model.Interval, callback func(retention.ChunkEntry) error) error {
+	tables, err := i.compactor.indexStorageClient.ListTables(ctx)
+	if err != nil {
+		return err
+	}
+
+	tables, err = tablesInInterval(tables, interval, i.maxTables)
+	if err != nil {
+		return err
+	}
+
+	for _, tableName := range tables {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := i.forEachUserChunkInTable(ctx, tableName, userID, callback); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// tablesInInterval returns the index tables overlapping the interval,
+// or an error if there are more than maxTables of them and maxTables is positive.
+func tablesInInterval(tables []string, interval model.Interval, maxTables int) ([]string, error) {
+	var res []string
+	for _, tableName := range tables {
+		if tableName == deletion.DeleteRequestsTableName {
+			continue
+		}
+
+		tableInterval := retention.ExtractIntervalFromTableName(tableName)
+		if tableInterval.Start > interval.End || interval.Start > tableInterval.End {
+			continue
+		}
+		res = append(res, tableName)
+	}
+
+	if maxTables > 0 && len(res) > maxTables {
+		return nil, fmt.Errorf("%w: %d tables, the limit is %d", deletion.ErrPreviewTooManyTables, len(res), maxTables)
+	}
+	return res, nil
+}
+
+func (i userChunkIterator) forEachUserChunkInTable(ctx context.Context, tableName, userID string, callback func(retention.ChunkEntry) error) error {
+	schemaCfg, ok := schemaPeriodForTable(i.compactor.schemaConfig, tableName)
+	if !ok {
+		return nil
+	}
+
+	indexCompactor, ok := i.compactor.indexCompactors[schemaCfg.IndexType]
+	if !ok {
+		return fmt.Errorf("index processor not found for index type %s", schemaCfg.IndexType)
+	}
+
+	workingDir, err := os.MkdirTemp(i.compactor.cfg.WorkingDirectory, "user-chunks-"+tableName)
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(workingDir)
+
+	logger := log.With(util_log.Logger, "table-name", tableName)
+	commonIndexSet, err := newCommonIndexSet(ctx, tableName, storage.NewIndexSet(i.compactor.indexStorageClient, false), filepath.Join(workingDir, "common"), logger)
+	if err != nil {
+		return err
+	}
+	userIndexSet, err := newUserIndexSet(ctx, tableName, userID, storage.NewIndexSet(i.compactor.indexStorageClient, true), filepath.Join(workingDir, userID), logger)
+	if err != nil {
+		return err
+	}
+
+	for _, is := range []*indexSet{commonIndexSet, userIndexSet} {
+		for _, indexFile := range is.ListSourceFiles() {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			path, err := is.GetSourceFile(indexFile)
+			if err != nil {
+				return err
+			}
+
+			compactedIndex, err := indexCompactor.OpenCompactedIndexFile(ctx, path, tableName, is.userID, is.workingDir, schemaCfg, is.logger)
+			if err != nil {
+				return err
+			}
+
+			err = compactedIndex.ForEachChunk(ctx, func(entry retention.ChunkEntry) (bool, error) {
+				if string(entry.UserID) != userID {
+					return false, nil
+				}
+				return false, callback(entry)
+			})
+			compactedIndex.Cleanup()
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
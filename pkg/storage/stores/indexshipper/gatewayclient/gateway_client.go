@@ -253,6 +253,21 @@ func (s *GatewayClient) GetStats(ctx context.Context, in *logproto.IndexStatsReq
 	return s.grpcClient.GetStats(ctx, in, opts...)
 }
 
+func (s *GatewayClient) GetVolume(ctx context.Context, in *logproto.VolumeRequest, opts ...grpc.CallOption) (*logproto.VolumeResponse, error) {
+	if s.cfg.Mode == indexgateway.RingMode {
+		var (
+			resp *logproto.VolumeResponse
+			err  error
+		)
+		err = s.ringModeDo(ctx, func(client logproto.IndexGatewayClient) error {
+			resp, err = client.GetVolume(ctx, in, opts...)
+			return err
+		})
+		return resp, err
+	}
+	return s.grpcClient.GetVolume(ctx, in, opts...)
+}
+
 func (s *GatewayClient) doQueries(ctx context.Context, queries []index.Query, callback index.QueryPagesCallback) error {
 	queryKeyQueryMap := make(map[string]index.Query, len(queries))
 	gatewayQueries := make([]*logproto.IndexQuery, 0, len(queries))
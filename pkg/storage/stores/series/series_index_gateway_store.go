@@ -32,6 +32,7 @@ type IndexGatewayClient interface {
 	LabelNamesForMetricName(ctx context.Context, in *logproto.LabelNamesForMetricNameRequest, opts ...grpc.CallOption) (*logproto.LabelResponse, error)
 	LabelValuesForMetricName(ctx context.Context, in *logproto.LabelValuesForMetricNameRequest, opts ...grpc.CallOption) (*logproto.LabelResponse, error)
 	GetStats(ctx context.Context, req *logproto.IndexStatsRequest, opts ...grpc.CallOption) (*logproto.IndexStatsResponse, error)
+	GetVolume(ctx context.Context, req *logproto.VolumeRequest, opts ...grpc.CallOption) (*logproto.VolumeResponse, error)
 }
 
 func NewIndexGatewayClientStore(client IndexGatewayClient, fallbackStore index.Reader) index.ReaderWriter {
@@ -139,6 +140,24 @@ func (c *IndexGatewayClientStore) Stats(ctx context.Context, userID string, from
 	return resp, nil
 }
 
+func (c *IndexGatewayClientStore) Volume(ctx context.Context, userID string, from, through model.Time, targetLabels []string, matchers ...*labels.Matcher) (*stats.Volumes, error) {
+	resp, err := c.client.GetVolume(ctx, &logproto.VolumeRequest{
+		From:         from,
+		Through:      through,
+		Matchers:     (&syntax.MatchersExpr{Mts: matchers}).String(),
+		TargetLabels: targetLabels,
+	})
+	if err != nil {
+		if isUnimplementedCallError(err) && c.fallbackStore != nil {
+			// Handle communication with older index gateways gracefully, by falling back to the index store calls.
+			return c.fallbackStore.Volume(ctx, userID, from, through, targetLabels, matchers...)
+		}
+		return nil, err
+	}
+
+	return resp, nil
+}
+
 func (c *IndexGatewayClientStore) SetChunkFilterer(chunkFilter chunk.RequestChunkFilterer) {
 	// if there is no fallback store, we can't set the chunk filterer and index gateway would take care of filtering out data
 	if c.fallbackStore != nil {
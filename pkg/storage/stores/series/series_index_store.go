@@ -705,3 +705,8 @@ func (c *indexReaderWriter) convertChunkIDsToChunkRefs(_ context.Context, userID
 func (c *indexReaderWriter) Stats(ctx context.Context, userID string, from, through model.Time, matchers ...*labels.Matcher) (*stats.Stats, error) {
 	return nil, nil
 }
+
+// old index stores do not implement volume -- skip
+func (c *indexReaderWriter) Volume(ctx context.Context, userID string, from, through model.Time, targetLabels []string, matchers ...*labels.Matcher) (*stats.Volumes, error) {
+	return nil, nil
+}
@@ -45,6 +45,12 @@ type IndexStatsAccumulator interface {
 	Stats() stats.Stats
 }
 
+type VolumeAccumulator interface {
+	AddStream(ls labels.Labels, fp model.Fingerprint)
+	AddChunk(fp model.Fingerprint, chk index.ChunkMeta)
+	Volumes() stats.Volumes
+}
+
 func NewIndexClient(idx Index, opts IndexClientOptions) *IndexClient {
 	return &IndexClient{
 		idx:  idx,
@@ -174,6 +180,51 @@ func (c *IndexClient) Stats(ctx context.Context, userID string, from, through mo
 		return nil, err
 	}
 
+	var acc IndexStatsAccumulator
+	if c.opts.UseBloomFilters {
+		blooms := stats.BloomPool.Get()
+		defer stats.BloomPool.Put(blooms)
+		acc = blooms
+	} else {
+		acc = &stats.Stats{}
+	}
+
+	if err := forTableIntervals(from, through, func(start, end model.Time, shouldIncludeChunk shouldIncludeChunk) error {
+		return c.idx.Stats(ctx, userID, start, end, acc, shard, shouldIncludeChunk, matchers...)
+	}); err != nil {
+		return nil, err
+	}
+	res := acc.Stats()
+
+	return &res, nil
+}
+
+// Volume returns the volume of the streams matching the matchers, aggregated by the values of the target labels.
+func (c *IndexClient) Volume(ctx context.Context, userID string, from, through model.Time, targetLabels []string, matchers ...*labels.Matcher) (*stats.Volumes, error) {
+	matchers, shard, err := cleanMatchers(matchers...)
+	if err != nil {
+		return nil, err
+	}
+
+	var blooms *stats.Blooms
+	if c.opts.UseBloomFilters {
+		blooms = stats.BloomPool.Get()
+		defer stats.BloomPool.Put(blooms)
+	}
+
+	acc := stats.NewVolumeAccumulator(targetLabels, blooms)
+	if err := forTableIntervals(from, through, func(start, end model.Time, shouldIncludeChunk shouldIncludeChunk) error {
+		return c.idx.Volume(ctx, userID, start, end, acc, shard, shouldIncludeChunk, matchers...)
+	}); err != nil {
+		return nil, err
+	}
+	res := acc.Volumes()
+
+	return &res, nil
+}
+
+// forTableIntervals calls fn for each table interval of the query range, along with the chunks to consider in the interval.
+func forTableIntervals(from, through model.Time, fn func(start, end model.Time, shouldIncludeChunk shouldIncludeChunk) error) error {
 	// split the query range to align with table intervals i.e. ObjectStorageIndexRequiredPeriod
 	// This is to avoid explicitly deduping chunks by leveraging the table intervals.
 	// The idea is to make each split process chunks that have start time >= start time of the table interval.
@@ -195,19 +246,10 @@ func (c *IndexClient) Stats(ctx context.Context, userID string, from, through mo
 		})
 	})
 
-	var acc IndexStatsAccumulator
-	if c.opts.UseBloomFilters {
-		blooms := stats.BloomPool.Get()
-		defer stats.BloomPool.Put(blooms)
-		acc = blooms
-	} else {
-		acc = &stats.Stats{}
-	}
-
 	queryBounds := newBounds(from, through)
 
 	for idx, interval := range intervals {
-		if err := c.idx.Stats(ctx, userID, interval.Start, interval.End, acc, shard, func(chk index.ChunkMeta) bool {
+		if err := fn(interval.Start, interval.End, func(chk index.ChunkMeta) bool {
 			// for the first split, purely do overlap check to also include chunks having
 			// start time earlier than start time of the table interval we are querying.
 			// for all other splits, consider only chunks that have from >= interval.Start
@@ -216,17 +258,12 @@ func (c *IndexClient) Stats(ctx context.Context, userID string, from, through mo
 				return true
 			}
 			return false
-		}, matchers...); err != nil {
-			return nil, err
+		}); err != nil {
+			return err
 		}
 	}
 
-	if err != nil {
-		return nil, err
-	}
-	res := acc.Stats()
-
-	return &res, nil
+	return nil
 }
 
 // SetChunkFilterer sets a chunk filter to be used when retrieving chunks.
@@ -10,6 +10,7 @@ import (
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/loki/pkg/logproto"
 	"github.com/grafana/loki/pkg/storage/config"
 	index_shipper "github.com/grafana/loki/pkg/storage/stores/indexshipper/index"
 )
@@ -204,3 +205,94 @@ func TestIndexClient_Stats(t *testing.T) {
 		})
 	}
 }
+
+func TestIndexClient_Volume(t *testing.T) {
+	tempDir := t.TempDir()
+	tableRanges := config.TableRanges{
+		{
+			Start: 0,
+			End:   math.MaxInt64,
+			PeriodConfig: &config.PeriodConfig{
+				IndexTables: config.PeriodicTableConfig{
+					Period: config.ObjectStorageIndexRequiredPeriod,
+				},
+			},
+		},
+	}
+
+	indexStartToday := model.TimeFromUnixNano(time.Now().Truncate(config.ObjectStorageIndexRequiredPeriod).UnixNano())
+	indexStartYesterday := indexStartToday.Add(-config.ObjectStorageIndexRequiredPeriod)
+
+	tables := map[string][]*TSDBFile{
+		tableRanges[0].PeriodConfig.IndexTables.TableFor(indexStartToday): {
+			BuildIndex(t, tempDir, []LoadableSeries{
+				{
+					Labels: mustParseLabels(`{foo="bar"}`),
+					Chunks: buildChunkMetas(int64(indexStartToday), int64(indexStartToday+99)),
+				},
+				{
+					Labels: mustParseLabels(`{fizz="buzz"}`),
+					Chunks: buildChunkMetas(int64(indexStartToday), int64(indexStartToday+99)),
+				},
+			}),
+			// the same chunks indexed by another ingester, in a table which isn't compacted yet.
+			BuildIndex(t, t.TempDir(), []LoadableSeries{
+				{
+					Labels: mustParseLabels(`{foo="bar"}`),
+					Chunks: buildChunkMetas(int64(indexStartToday), int64(indexStartToday+99)),
+				},
+			}),
+		},
+
+		tableRanges[0].PeriodConfig.IndexTables.TableFor(indexStartYesterday): {
+			BuildIndex(t, tempDir, []LoadableSeries{
+				{
+					Labels: mustParseLabels(`{foo="bar"}`),
+					Chunks: buildChunkMetas(int64(indexStartYesterday), int64(indexStartYesterday+99)),
+				},
+				{
+					Labels: mustParseLabels(`{foo="bar", fizz="buzz"}`),
+					Chunks: buildChunkMetas(int64(indexStartYesterday), int64(indexStartYesterday+99)),
+				},
+			}),
+		},
+	}
+
+	idx := newIndexShipperQuerier(mockIndexShipperIndexIterator{tables: tables}, config.TableRanges{
+		{
+			Start:        0,
+			End:          math.MaxInt64,
+			PeriodConfig: &config.PeriodConfig{},
+		},
+	})
+
+	indexClient := NewIndexClient(idx, IndexClientOptions{UseBloomFilters: true})
+
+	for _, tc := range []struct {
+		name         string
+		targetLabels []string
+		expected     []logproto.Volume
+	}{
+		{
+			name:         "by label",
+			targetLabels: []string{"fizz"},
+			expected: []logproto.Volume{
+				// streams seen in both tables and chunks seen in both indexes are counted once.
+				{Name: `{fizz="buzz"}`, Streams: 1, Chunks: 99, Entries: 99},
+				{Name: `{}`, Streams: 1, Chunks: 199, Entries: 199},
+			},
+		},
+		{
+			name: "without labels",
+			expected: []logproto.Volume{
+				{Name: `{}`, Streams: 2, Chunks: 298, Entries: 298},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			volumes, err := indexClient.Volume(context.Background(), "", indexStartYesterday, indexStartToday+1000, tc.targetLabels, labels.MustNewMatcher(labels.MatchEqual, "foo", "bar"))
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, volumes.Volumes)
+		})
+	}
+}
@@ -253,3 +253,20 @@ func (i *TSDBIndex) Stats(ctx context.Context, userID string, from, through mode
 
 	return nil
 }
+
+func (i *TSDBIndex) Volume(ctx context.Context, userID string, from, through model.Time, acc VolumeAccumulator, shard *index.ShardAnnotation, shouldIncludeChunk shouldIncludeChunk, matchers ...*labels.Matcher) error {
+	return i.forSeries(ctx, shard,
+		func(ls labels.Labels, fp model.Fingerprint, chks []index.ChunkMeta) {
+			var addedStream bool
+			for _, chk := range chks {
+				if shouldIncludeChunk(chk) {
+					if !addedStream {
+						acc.AddStream(ls, fp)
+						addedStream = true
+					}
+					acc.AddChunk(fp, chk)
+				}
+			}
+		},
+		matchers...)
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/grafana/loki/pkg/logqlmodel/stats"
 	"github.com/grafana/loki/pkg/querier/astmapper"
 	"github.com/grafana/loki/pkg/storage/chunk"
+	"github.com/grafana/loki/pkg/storage/chunk/bloom"
 	"github.com/grafana/loki/pkg/storage/chunk/cache"
 	chunkclient "github.com/grafana/loki/pkg/storage/chunk/client"
 	"github.com/grafana/loki/pkg/storage/chunk/fetcher"
@@ -259,9 +260,14 @@ func (m *mockChunkStore) Stats(ctx context.Context, userID string, from, through
 	return nil, nil
 }
 
+func (m *mockChunkStore) Volume(ctx context.Context, userID string, from, through model.Time, targetLabels []string, matchers ...*labels.Matcher) (*index_stats.Volumes, error) {
+	return nil, nil
+}
+
 type mockChunkStoreClient struct {
 	chunks []chunk.Chunk
 	scfg   config.SchemaConfig
+	blooms map[string]*bloom.Filter
 }
 
 func (m mockChunkStoreClient) Stop() {
@@ -285,6 +291,14 @@ func (m mockChunkStoreClient) GetChunks(ctx context.Context, chunks []chunk.Chun
 	return res, nil
 }
 
+func (m mockChunkStoreClient) GetBlooms(ctx context.Context, chunks []chunk.Chunk) ([]*bloom.Filter, error) {
+	res := make([]*bloom.Filter, 0, len(chunks))
+	for _, c := range chunks {
+		res = append(res, m.blooms[m.scfg.ExternalKey(c.ChunkRef)])
+	}
+	return res, nil
+}
+
 func (m mockChunkStoreClient) DeleteChunk(ctx context.Context, userID, chunkID string) error {
 	return nil
 }
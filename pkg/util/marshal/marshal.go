@@ -98,3 +98,13 @@ func WriteIndexStatsResponseJSON(r *stats.Stats, w io.Writer) error {
 	s.WriteRaw("\n")
 	return s.Flush()
 }
+
+// WriteVolumeResponseJSON marshals a logproto.VolumeResponse to JSON and then
+// writes it to the provided io.Writer.
+func WriteVolumeResponseJSON(r *stats.Volumes, w io.Writer) error {
+	s := jsoniter.ConfigFastest.BorrowStream(w)
+	defer jsoniter.ConfigFastest.ReturnStream(s)
+	s.WriteVal(r)
+	s.WriteRaw("\n")
+	return s.Flush()
+}
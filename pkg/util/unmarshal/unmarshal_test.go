@@ -45,6 +45,40 @@ var pushTests = []struct {
 			]
 		}`,
 	},
+	{
+		[]logproto.Stream{
+			{
+				Entries: []logproto.Entry{
+					{
+						Timestamp: time.Unix(0, 123456789012345),
+						Line:      "super line",
+					},
+					{
+						Timestamp: time.Unix(0, 123456789012346),
+						Line:      "super line with structured metadata",
+						StructuredMetadata: []logproto.LabelAdapter{
+							{Name: "traceID", Value: "abc"},
+							{Name: "user", Value: "a"},
+						},
+					},
+				},
+				Labels: `{test="test"}`,
+			},
+		},
+		`{
+			"streams": [
+				{
+					"stream": {
+						"test": "test"
+					},
+					"values":[
+						[ "123456789012345", "super line" ],
+						[ "123456789012346", "super line with structured metadata", { "traceID": "abc", "user": "a" } ]
+					]
+				}
+			]
+		}`,
+	},
 }
 
 func Test_DecodePushRequest(t *testing.T) {
@@ -694,6 +694,23 @@ func SmallestPositiveNonZeroDurationPerTenant(tenantIDs []string, f func(string)
 	return *result
 }
 
+// SmallestPositiveNonZeroFloat64PerTenant is returning the minimal positive
+// and non-zero value of the supplied limit function for all given tenants. It
+// returns 0 only if all inputs are 0 or an empty tenant list is given.
+func SmallestPositiveNonZeroFloat64PerTenant(tenantIDs []string, f func(string) float64) float64 {
+	var result *float64
+	for _, tenantID := range tenantIDs {
+		v := f(tenantID)
+		if v > 0 && (result == nil || v < *result) {
+			result = &v
+		}
+	}
+	if result == nil {
+		return 0
+	}
+	return *result
+}
+
 // MaxDurationPerTenant is returning the maximum duration per tenant. Without
 // tenants given it will return a time.Duration(0).
 func MaxDurationPerTenant(tenantIDs []string, f func(string) time.Duration) time.Duration {
@@ -725,3 +742,17 @@ func MaxDurationOrZeroPerTenant(tenantIDs []string, f func(string) time.Duration
 	}
 	return *result
 }
+
+// AllTrueBooleansPerTenant returns true only if the supplied limit function
+// returns true for all given tenants. Without tenants given it will return false.
+func AllTrueBooleansPerTenant(tenantIDs []string, f func(string) bool) bool {
+	if len(tenantIDs) == 0 {
+		return false
+	}
+	for _, tenantID := range tenantIDs {
+		if !f(tenantID) {
+			return false
+		}
+	}
+	return true
+}
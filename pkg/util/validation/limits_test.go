@@ -5,6 +5,18 @@ import (
 	"time"
 )
 
+func TestSmallestPositiveNonZeroFloat64PerTenant(t *testing.T) {
+	limits := map[string]float64{"tenant1": 0.5, "tenantTwo": 0, "tenantThree": 2}
+	f := func(tenantID string) float64 { return limits[tenantID] }
+
+	if got := SmallestPositiveNonZeroFloat64PerTenant([]string{"tenant1", "tenantTwo", "tenantThree"}, f); got != 0.5 {
+		t.Errorf("SmallestPositiveNonZeroFloat64PerTenant() = %v, want %v", got, 0.5)
+	}
+	if got := SmallestPositiveNonZeroFloat64PerTenant([]string{"tenantTwo"}, f); got != 0 {
+		t.Errorf("SmallestPositiveNonZeroFloat64PerTenant() = %v, want %v", got, 0)
+	}
+}
+
 // nolint:goconst
 func TestSmallestPositiveIntPerTenant(t *testing.T) {
 	type args struct {
@@ -220,3 +232,25 @@ func TestMaxDurationOrZeroPerTenant(t *testing.T) {
 		})
 	}
 }
+
+func TestAllTrueBooleansPerTenant(t *testing.T) {
+	enabled := map[string]bool{"tenant1": true, "tenantTwo": true, "tenantThree": false}
+	f := func(tenantID string) bool { return enabled[tenantID] }
+
+	tests := []struct {
+		name      string
+		tenantIDs []string
+		want      bool
+	}{
+		{name: "all tenants enabled", tenantIDs: []string{"tenant1", "tenantTwo"}, want: true},
+		{name: "one tenant disabled", tenantIDs: []string{"tenant1", "tenantThree"}, want: false},
+		{name: "no tenants", tenantIDs: nil, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AllTrueBooleansPerTenant(tt.tenantIDs, f); got != tt.want {
+				t.Errorf("AllTrueBooleansPerTenant() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
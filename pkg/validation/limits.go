@@ -5,6 +5,7 @@ import (
 	"flag"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-kit/log/level"
@@ -52,6 +53,21 @@ const (
 	DefaultPerTenantQueryTimeout = "1m"
 )
 
+// DefaultOTLPResourceAttributesAsLabels are the OTLP resource attributes converted to stream labels by default.
+var DefaultOTLPResourceAttributesAsLabels = []string{
+	"service.name",
+	"service.namespace",
+	"service.instance.id",
+	"deployment.environment",
+	"cloud.region",
+	"cloud.availability_zone",
+	"k8s.cluster.name",
+	"k8s.namespace.name",
+	"k8s.pod.name",
+	"k8s.container.name",
+	"container.name",
+}
+
 // Limits describe all the limits for users; can be used to describe global default
 // limits via flags, or per-user limits via yaml config.
 // NOTE: we use custom `model.Duration` instead of standard `time.Duration` because,
@@ -71,6 +87,9 @@ type Limits struct {
 	MaxLineSize                 flagext.ByteSize `yaml:"max_line_size" json:"max_line_size"`
 	MaxLineSizeTruncate         bool             `yaml:"max_line_size_truncate" json:"max_line_size_truncate"`
 	IncrementDuplicateTimestamp bool             `yaml:"increment_duplicate_timestamp" json:"increment_duplicate_timestamp"`
+	AllowStructuredMetadata     bool             `yaml:"allow_structured_metadata" json:"allow_structured_metadata"`
+
+	OTLPResourceAttributesAsLabels dskit_flagext.StringSliceCSV `yaml:"otlp_resource_attributes_as_labels" json:"otlp_resource_attributes_as_labels"`
 
 	// Ingester enforced limits.
 	MaxLocalStreamsPerUser  int              `yaml:"max_streams_per_user" json:"max_streams_per_user"`
@@ -92,12 +111,17 @@ type Limits struct {
 	MaxEntriesLimitPerQuery    int            `yaml:"max_entries_limit_per_query" json:"max_entries_limit_per_query"`
 	MaxCacheFreshness          model.Duration `yaml:"max_cache_freshness_per_query" json:"max_cache_freshness_per_query"`
 	MaxQueriersPerTenant       int            `yaml:"max_queriers_per_tenant" json:"max_queriers_per_tenant"`
+	QueryPriority              int            `yaml:"query_priority" json:"query_priority"`
+	QueryWeight                float64        `yaml:"query_weight" json:"query_weight"`
 	QueryReadyIndexNumDays     int            `yaml:"query_ready_index_num_days" json:"query_ready_index_num_days"`
 	QueryTimeout               model.Duration `yaml:"query_timeout" json:"query_timeout"`
 
 	// Query frontend enforced limits. The default is actually parameterized by the queryrange config.
-	QuerySplitDuration  model.Duration `yaml:"split_queries_by_interval" json:"split_queries_by_interval"`
-	MinShardingLookback model.Duration `yaml:"min_sharding_lookback" json:"min_sharding_lookback"`
+	QuerySplitDuration   model.Duration   `yaml:"split_queries_by_interval" json:"split_queries_by_interval"`
+	MinShardingLookback  model.Duration   `yaml:"min_sharding_lookback" json:"min_sharding_lookback"`
+	ProbabilisticQueries bool             `yaml:"probabilistic_queries" json:"probabilistic_queries"`
+	MaxQueryBytesRead    flagext.ByteSize `yaml:"max_query_bytes_read" json:"max_query_bytes_read"`
+	MaxQuerierBytesRead  flagext.ByteSize `yaml:"max_querier_bytes_read" json:"max_querier_bytes_read"`
 
 	// Ruler defaults and limits.
 	RulerEvaluationDelay        model.Duration                   `yaml:"ruler_evaluation_delay_duration" json:"ruler_evaluation_delay_duration"`
@@ -105,6 +129,10 @@ type Limits struct {
 	RulerMaxRuleGroupsPerTenant int                              `yaml:"ruler_max_rule_groups_per_tenant" json:"ruler_max_rule_groups_per_tenant"`
 	RulerAlertManagerConfig     *ruler_config.AlertManagerConfig `yaml:"ruler_alertmanager_config" json:"ruler_alertmanager_config" doc:"hidden"`
 
+	// Ruler remote evaluation limits.
+	RulerRemoteEvaluationTimeout    model.Duration `yaml:"ruler_remote_evaluation_timeout" json:"ruler_remote_evaluation_timeout"`
+	RulerRemoteEvaluationMaxRetries int            `yaml:"ruler_remote_evaluation_max_retries" json:"ruler_remote_evaluation_max_retries"`
+
 	// TODO(dannyk): add HTTP client overrides (basic auth / tls config, etc)
 	// Ruler remote-write limits.
 
@@ -146,7 +174,7 @@ type Limits struct {
 
 	// Global and per tenant retention
 	RetentionPeriod model.Duration    `yaml:"retention_period" json:"retention_period"`
-	StreamRetention []StreamRetention `yaml:"retention_stream,omitempty" json:"retention_stream,omitempty" doc:"description=Per-stream retention to apply, if the retention is enable on the compactor side.\nExample:\n retention_stream:\n - selector: '{namespace=\"dev\"}'\n priority: 1\n period: 24h\n- selector: '{container=\"nginx\"}'\n priority: 1\n period: 744h\nSelector is a Prometheus labels matchers that will apply the 'period' retention only if the stream is matching. In case multiple stream are matching, the highest priority will be picked. If no rule is matched the 'retention_period' is used."`
+	StreamRetention []StreamRetention `yaml:"retention_stream,omitempty" json:"retention_stream,omitempty" doc:"description=Per-stream retention to apply, if the retention is enable on the compactor side.\nExample:\n retention_stream:\n - selector: '{namespace=\"dev\"}'\n priority: 1\n period: 24h\n- selector: '{container=\"nginx\"}'\n priority: 1\n period: 744h\nSelector is a Prometheus labels matchers that will apply the 'period' retention only if the stream is matching. In case multiple stream are matching, the highest priority will be picked. If no rule is matched the 'retention_period' is used.\nThe selector can also have line or label filters, like '{container=\"nginx\"} != \"error\"'. Only the lines matching it are then deleted after the 'period', by rewriting the chunks, and the other lines are kept for the retention of their stream."`
 
 	// Config for overrides, convenient if it goes here.
 	PerTenantOverrideConfig string         `yaml:"per_tenant_override_config" json:"per_tenant_override_config"`
@@ -165,6 +193,9 @@ type StreamRetention struct {
 	Priority int               `yaml:"priority" json:"priority"`
 	Selector string            `yaml:"selector" json:"selector"`
 	Matchers []*labels.Matcher `yaml:"-" json:"-"` // populated during validation.
+	// LineFilter is set when the selector has a pipeline, in which case the
+	// period only applies to the lines matching it. Populated during validation.
+	LineFilter syntax.LogSelectorExpr `yaml:"-" json:"-"`
 }
 
 // RegisterFlags adds the flags required to config this to the given FlagSet
@@ -178,6 +209,9 @@ func (l *Limits) RegisterFlags(f *flag.FlagSet) {
 	f.IntVar(&l.MaxLabelValueLength, "validation.max-length-label-value", 2048, "Maximum length accepted for label value. This setting also applies to the metric name.")
 	f.IntVar(&l.MaxLabelNamesPerSeries, "validation.max-label-names-per-series", 30, "Maximum number of label names per series.")
 	f.BoolVar(&l.RejectOldSamples, "validation.reject-old-samples", true, "Whether or not old samples will be rejected.")
+	_ = l.OTLPResourceAttributesAsLabels.Set(strings.Join(DefaultOTLPResourceAttributesAsLabels, ","))
+	f.Var(&l.OTLPResourceAttributesAsLabels, "distributor.otlp-resource-attributes-as-labels", "Comma separated list of OTLP resource attributes converted to stream labels by the /otlp/v1/logs endpoint. Dots are replaced with underscores in label names. The other attributes are added to the log line in logfmt.")
+	f.BoolVar(&l.AllowStructuredMetadata, "validation.allow-structured-metadata", false, "Allow the entries of the pushed streams to carry structured metadata, key/value pairs which are not part of the stream labels. Structured metadata is only stored when unordered writes are enabled.")
 	f.BoolVar(&l.IncrementDuplicateTimestamp, "validation.increment-duplicate-timestamps", false, "Alter the log line timestamp during ingestion when the timestamp is the same as the previous entry for the same stream. When enabled, if a log line in a push request has the same timestamp as the previous line for the same stream, one nanosecond is added to the log line. This will preserve the received order of log lines with the exact same timestamp when they are queried, by slightly altering their stored timestamp. NOTE: This is imperfect, because Loki accepts out of order writes, and another push request for the same stream could contain duplicate timestamps to existing entries and they will not be incremented.")
 
 	_ = l.RejectOldSamplesMaxAge.Set("7d")
@@ -214,11 +248,16 @@ func (l *Limits) RegisterFlags(f *flag.FlagSet) {
 
 	_ = l.MinShardingLookback.Set("0s")
 	f.Var(&l.MinShardingLookback, "frontend.min-sharding-lookback", "Limit queries that can be sharded. Queries within the time range of now and now minus this sharding lookback are not sharded. The default value of 0s disables the lookback, causing sharding of all queries at all times.")
+	f.BoolVar(&l.ProbabilisticQueries, "frontend.probabilistic-queries", false, "Allow the query frontend to shard queries that can only be merged approximately, such as quantile_over_time with grouping, topk over summed series and count over parsed series. The results of those queries are estimated from mergeable sketches.")
+	f.Var(&l.MaxQueryBytesRead, "frontend.max-query-bytes-read", "Max number of bytes a query can fetch, estimated from the index statistics before the query is scheduled. Queries exceeding this limit are rejected. Only applies to TSDB index periods. 0 to disable.")
+	f.Var(&l.MaxQuerierBytesRead, "frontend.max-querier-bytes-read", "Max number of bytes a single subquery can fetch, estimated from the index statistics after the query is split by time and sharded. Queries with a subquery exceeding this limit are rejected. Only applies to TSDB index periods. 0 to disable.")
 
 	_ = l.MaxCacheFreshness.Set("1m")
 	f.Var(&l.MaxCacheFreshness, "frontend.max-cache-freshness", "Most recent allowed cacheable result per-tenant, to prevent caching very recent results that might still be in flux.")
 
 	f.IntVar(&l.MaxQueriersPerTenant, "frontend.max-queriers-per-tenant", 0, "Maximum number of queriers that can handle requests for a single tenant. If set to 0 or value higher than number of available queriers, *all* queriers will handle requests for the tenant. Each frontend (or query-scheduler, if used) will select the same set of queriers for the same tenant (given that all queriers are connected to all frontends / query-schedulers). This option only works with queriers connecting to the query-frontend / query-scheduler, not when using downstream URL.")
+	f.IntVar(&l.QueryPriority, "frontend.query-priority", 0, "Priority class of the queries of the tenant in the query-frontend / query-scheduler queue. Queries of the tenants with the highest priority class are always dequeued first, the tenants of a lower priority class only get the queriers left idle.")
+	f.Float64Var(&l.QueryWeight, "frontend.query-weight", 1, "Share of the queriers given to the tenant, relative to the other tenants of the same priority class. Each tenant is charged the estimated cost of its dequeued queries, and the tenant with the lowest cost divided by its weight is dequeued first. A tenant with a weight of 2 gets twice the share of a tenant with a weight of 1. Weights lower than or equal to 0 are treated as 1. The query-scheduler estimates the cost of a query as the time range it queries divided by its number of shards, the query-frontend counts each query as 1.")
 	f.IntVar(&l.QueryReadyIndexNumDays, "store.query-ready-index-num-days", 0, "Number of days of index to be kept always downloaded for queries. Applies only to per user index in boltdb-shipper index store. 0 to disable.")
 
 	_ = l.RulerEvaluationDelay.Set("0s")
@@ -227,6 +266,10 @@ func (l *Limits) RegisterFlags(f *flag.FlagSet) {
 	f.IntVar(&l.RulerMaxRulesPerRuleGroup, "ruler.max-rules-per-rule-group", 0, "Maximum number of rules per rule group per-tenant. 0 to disable.")
 	f.IntVar(&l.RulerMaxRuleGroupsPerTenant, "ruler.max-rule-groups-per-tenant", 0, "Maximum number of rule groups per-tenant. 0 to disable.")
 
+	_ = l.RulerRemoteEvaluationTimeout.Set("0s")
+	f.Var(&l.RulerRemoteEvaluationTimeout, "ruler.remote-evaluation.timeout", "Timeout for the evaluation of a rule by the query-frontend, including retries, when the ruler evaluation mode is 'remote'. 0 to disable.")
+	f.IntVar(&l.RulerRemoteEvaluationMaxRetries, "ruler.remote-evaluation.max-retries", 3, "Maximum number of retries of a rule evaluation failing with a server error, when the ruler evaluation mode is 'remote'.")
+
 	f.StringVar(&l.PerTenantOverrideConfig, "limits.per-user-override-config", "", "Feature renamed to 'runtime configuration', flag deprecated in favor of -runtime-config.file (runtime_config.file in YAML).")
 	_ = l.RetentionPeriod.Set("744h")
 	f.Var(&l.RetentionPeriod, "store.retention", "Retention to apply for the store, if the retention is enabled on the compactor side.")
@@ -270,7 +313,7 @@ func (l *Limits) UnmarshalYAML(unmarshal func(interface{}) error) error {
 func (l *Limits) Validate() error {
 	if l.StreamRetention != nil {
 		for i, rule := range l.StreamRetention {
-			matchers, err := syntax.ParseMatchers(rule.Selector)
+			expr, err := syntax.ParseLogSelector(rule.Selector, false)
 			if err != nil {
 				return fmt.Errorf("invalid labels matchers: %w", err)
 			}
@@ -278,7 +321,13 @@ func (l *Limits) Validate() error {
 				return fmt.Errorf("retention period must be >= 24h was %s", rule.Period)
 			}
 			// populate matchers during validation
-			l.StreamRetention[i].Matchers = matchers
+			l.StreamRetention[i].Matchers = expr.Matchers()
+			if _, ok := expr.(*syntax.MatchersExpr); !ok {
+				if !expr.HasFilter() {
+					return fmt.Errorf("retention selector %s has a pipeline without filter", rule.Selector)
+				}
+				l.StreamRetention[i].LineFilter = expr
+			}
 		}
 	}
 
@@ -423,6 +472,16 @@ func (o *Overrides) MaxQueriersPerUser(userID string) int {
 	return o.getOverridesForUser(userID).MaxQueriersPerTenant
 }
 
+// QueryPriority returns the priority class of the queries of this user in the query queue.
+func (o *Overrides) QueryPriority(userID string) int {
+	return o.getOverridesForUser(userID).QueryPriority
+}
+
+// QueryWeight returns the share of the queriers given to this user relative to the other users of the same priority class.
+func (o *Overrides) QueryWeight(userID string) float64 {
+	return o.getOverridesForUser(userID).QueryWeight
+}
+
 // QueryReadyIndexNumDays returns the number of days for which we have to be query ready for a user.
 func (o *Overrides) QueryReadyIndexNumDays(userID string) int {
 	return o.getOverridesForUser(userID).QueryReadyIndexNumDays
@@ -460,11 +519,26 @@ func (o *Overrides) MinShardingLookback(userID string) time.Duration {
 	return time.Duration(o.getOverridesForUser(userID).MinShardingLookback)
 }
 
+// ProbabilisticQueries returns whether the query frontend can shard queries with approximate results for this tenant.
+func (o *Overrides) ProbabilisticQueries(userID string) bool {
+	return o.getOverridesForUser(userID).ProbabilisticQueries
+}
+
 // QuerySplitDuration returns the tenant specific splitby interval applied in the query frontend.
 func (o *Overrides) QuerySplitDuration(userID string) time.Duration {
 	return time.Duration(o.getOverridesForUser(userID).QuerySplitDuration)
 }
 
+// MaxQueryBytesRead returns the maximum number of bytes a query can read.
+func (o *Overrides) MaxQueryBytesRead(userID string) int {
+	return o.getOverridesForUser(userID).MaxQueryBytesRead.Val()
+}
+
+// MaxQuerierBytesRead returns the maximum number of bytes a subquery can read.
+func (o *Overrides) MaxQuerierBytesRead(userID string) int {
+	return o.getOverridesForUser(userID).MaxQuerierBytesRead.Val()
+}
+
 // MaxConcurrentTailRequests returns the limit to number of concurrent tail requests.
 func (o *Overrides) MaxConcurrentTailRequests(userID string) int {
 	return o.getOverridesForUser(userID).MaxConcurrentTailRequests
@@ -524,6 +598,16 @@ func (o *Overrides) RulerAlertManagerConfig(userID string) *ruler_config.AlertMa
 	return o.getOverridesForUser(userID).RulerAlertManagerConfig
 }
 
+// RulerRemoteEvaluationTimeout returns the timeout of the remote evaluation of a rule for a given user.
+func (o *Overrides) RulerRemoteEvaluationTimeout(userID string) time.Duration {
+	return time.Duration(o.getOverridesForUser(userID).RulerRemoteEvaluationTimeout)
+}
+
+// RulerRemoteEvaluationMaxRetries returns the maximum number of retries of the remote evaluation of a rule for a given user.
+func (o *Overrides) RulerRemoteEvaluationMaxRetries(userID string) int {
+	return o.getOverridesForUser(userID).RulerRemoteEvaluationMaxRetries
+}
+
 // RulerRemoteWriteDisabled returns whether remote-write is disabled for a given user or not.
 func (o *Overrides) RulerRemoteWriteDisabled(userID string) bool {
 	return o.getOverridesForUser(userID).RulerRemoteWriteDisabled
@@ -658,6 +742,15 @@ func (o *Overrides) IncrementDuplicateTimestamps(userID string) bool {
 	return o.getOverridesForUser(userID).IncrementDuplicateTimestamp
 }
 
+func (o *Overrides) AllowStructuredMetadata(userID string) bool {
+	return o.getOverridesForUser(userID).AllowStructuredMetadata
+}
+
+// OTLPResourceAttributesAsLabels returns the OTLP resource attributes converted to stream labels.
+func (o *Overrides) OTLPResourceAttributesAsLabels(userID string) []string {
+	return o.getOverridesForUser(userID).OTLPResourceAttributesAsLabels
+}
+
 func (o *Overrides) getOverridesForUser(userID string) *Limits {
 	if o.tenantLimits != nil {
 		l := o.tenantLimits.TenantLimits(userID)
@@ -11,6 +11,7 @@ import (
 	"github.com/grafana/loki/pkg/storage/stores/indexshipper/compactor/deletionmode"
 
 	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v2"
@@ -301,3 +302,32 @@ func TestLimitsValidation(t *testing.T) {
 		require.True(t, errors.Is(limits.Validate(), tc.expected))
 	}
 }
+
+func TestStreamRetentionValidation(t *testing.T) {
+	for _, tc := range []struct {
+		selector      string
+		expectErr     bool
+		hasLineFilter bool
+	}{
+		{selector: `{app="foo"}`},
+		{selector: `{app="foo"} |= "level=debug"`, hasLineFilter: true},
+		{selector: `{app="foo"} | logfmt | level="debug"`, hasLineFilter: true},
+		{selector: `{app="foo"} | logfmt`, expectErr: true},
+		{selector: `app="foo"`, expectErr: true},
+	} {
+		t.Run(tc.selector, func(t *testing.T) {
+			limits := Limits{
+				DeletionMode:    "disabled",
+				StreamRetention: []StreamRetention{{Period: model.Duration(24 * time.Hour), Selector: tc.selector}},
+			}
+			err := limits.Validate()
+			if tc.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, []*labels.Matcher{labels.MustNewMatcher(labels.MatchEqual, "app", "foo")}, limits.StreamRetention[0].Matchers)
+			require.Equal(t, tc.hasLineFilter, limits.StreamRetention[0].LineFilter != nil)
+		})
+	}
+}
@@ -51,6 +51,12 @@ const (
 	// DuplicateLabelNames is a reason for discarding a log line which has duplicate label names
 	DuplicateLabelNames         = "duplicate_label_names"
 	DuplicateLabelNamesErrorMsg = "stream '%s' has duplicate label name: '%s'"
+	// DisallowedStructuredMetadata is a reason for discarding a log line which has structured metadata while it's not allowed
+	DisallowedStructuredMetadata         = "disallowed_structured_metadata"
+	DisallowedStructuredMetadataErrorMsg = "stream '%s' includes structured metadata, but this feature is disallowed. Please see `limits_config.allow_structured_metadata` or contact your Loki administrator to enable it."
+	// InvalidStructuredMetadata is a reason for discarding a log line which has structured metadata with an invalid name
+	InvalidStructuredMetadata         = "invalid_structured_metadata"
+	InvalidStructuredMetadataErrorMsg = "stream '%s' has structured metadata with an invalid name: '%s'"
 )
 
 type ErrStreamRateLimit struct {